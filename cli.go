@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"Meiko/internal/apikeys"
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+)
+
+// runAPIKeyCommand handles `meiko apikey <create|list|revoke>` and returns the exit code
+func runAPIKeyCommand(args []string) int {
+	usage := func() {
+		fmt.Println("Usage:")
+		fmt.Println("  meiko apikey create -name <name> -scopes <scope,...>")
+		fmt.Println("  meiko apikey list")
+		fmt.Println("  meiko apikey revoke <id>")
+		fmt.Printf("\nScopes: %s\n", strings.Join(apikeys.Scopes, ", "))
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	flags, configPath := commandFlags("apikey " + args[0])
+	name := flags.String("name", "", "Name describing who uses the key")
+	scopes := flags.String("scopes", apikeys.ScopeReadCalls, "Comma-separated scopes")
+	flags.Parse(args[1:])
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to load configuration: %v\n", err)
+		return 1
+	}
+	db, err := database.New(cfg.Database, logger.New(config.LoggingConfig{Level: "error"}))
+	if err != nil {
+		fmt.Printf("❌ Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "create":
+		record, key, err := apikeys.Create(db, *name, []string{*scopes})
+		if err != nil {
+			fmt.Printf("❌ Failed to create API key: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Created API key %d (%s) with scopes %s\n", record.ID, record.Name, strings.Join(record.Scopes, ","))
+		fmt.Printf("\n    %s\n\n", key)
+		fmt.Println("Store this key now; it cannot be shown again.")
+
+	case "list":
+		keys, err := db.GetAPIKeys()
+		if err != nil {
+			fmt.Printf("❌ Failed to list API keys: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tLAST USED\tSTATUS")
+		for _, key := range keys {
+			lastUsed, status := "never", "active"
+			if key.LastUsedAt != nil {
+				lastUsed = key.LastUsedAt.Format("2006-01-02 15:04")
+			}
+			if key.RevokedAt != nil {
+				status = "revoked " + key.RevokedAt.Format("2006-01-02")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s…\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix,
+				strings.Join(key.Scopes, ","), key.CreatedAt.Format("2006-01-02"), lastUsed, status)
+		}
+		w.Flush()
+
+	case "revoke":
+		if flags.NArg() < 1 {
+			usage()
+			return 2
+		}
+		id, err := strconv.Atoi(flags.Arg(0))
+		if err != nil {
+			fmt.Printf("❌ Invalid API key ID: %s\n", flags.Arg(0))
+			return 2
+		}
+		if err := db.RevokeAPIKey(id); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Revoked API key %d\n", id)
+
+	default:
+		usage()
+		return 2
+	}
+
+	return 0
+}
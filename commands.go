@@ -0,0 +1,552 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"Meiko/internal/backup"
+	"Meiko/internal/config"
+	"Meiko/internal/corrections"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+	"Meiko/internal/processor"
+	"Meiko/internal/talkgroups"
+	"Meiko/internal/transcription"
+	"Meiko/internal/watcher"
+	"Meiko/internal/winsvc"
+)
+
+// printUsage lists the available subcommands
+func printUsage() {
+	fmt.Println("Usage: meiko [command] [flags]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  serve                     Run SDRTrunk, transcription, Discord and the dashboard (default)")
+	fmt.Println("  transcribe <file>         Transcribe one recording and print the result")
+	fmt.Println("  import <dir>              Process historical recordings into the database")
+	fmt.Println("  scan                      Find recordings in the capture directories that were never processed")
+	fmt.Println("  db <vacuum|migrate|stats|backup|verify|restore>")
+	fmt.Println("                            Database maintenance and backups")
+	fmt.Println("  apikey <create|list|revoke>")
+	fmt.Println("                            Manage API keys")
+	fmt.Println("  service <install|uninstall|start|stop>")
+	fmt.Println("                            Manage the Windows service")
+	fmt.Println("  version                   Show the version")
+	fmt.Println()
+	fmt.Println("Every command accepts -config <path> (default config.yaml).")
+}
+
+// commandFlags creates a flag set with the -config flag shared by every command
+func commandFlags(name string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet("meiko "+name, flag.ExitOnError)
+	configPath := flags.String("config", "config.yaml", "Path to the configuration file")
+	return flags, configPath
+}
+
+// interruptContext returns a context cancelled by Ctrl+C or SIGTERM, so long
+// running commands stop after the current recording
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+// runTranscribeCommand handles `meiko transcribe <file>` and returns the exit code
+func runTranscribeCommand(args []string) int {
+	flags, configPath := commandFlags("transcribe")
+	talkgroup := flags.String("talkgroup", "", "Talkgroup ID, to apply its glossary")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: meiko transcribe [-config path] [-talkgroup id] <file>")
+		return 2
+	}
+	file := flags.Arg(0)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to load configuration: %v\n", err)
+		return 1
+	}
+	transcriber, err := transcription.New(cfg.Transcription, logger.New(config.LoggingConfig{Level: "error"}))
+	if err != nil {
+		fmt.Printf("❌ Failed to initialize transcription: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	fmt.Printf("🎤 Transcribing %s (%s mode)...\n", filepath.Base(file), cfg.Transcription.Mode)
+	result, err := transcriber.TranscribeFile(ctx, file, &transcription.Options{
+		TalkgroupID: *talkgroup,
+		Vocabulary:  cfg.GetGlossary(*talkgroup),
+	})
+	if err != nil {
+		fmt.Printf("❌ Transcription failed: %v\n", err)
+		return 1
+	}
+
+	text := result.Text
+	if transcription.SpeakerCount(result.Segments) > 1 {
+		text = transcription.FormatDialog(result.Segments)
+	}
+	fmt.Println()
+	fmt.Println(text)
+	fmt.Println()
+	if result.Language != "" {
+		fmt.Printf("Language: %s\n", result.Language)
+	}
+	fmt.Printf("Took:     %s\n", result.EndTime.Sub(result.StartTime).Round(time.Millisecond))
+	return 0
+}
+
+// pipeline is the processing chain used by commands that store calls
+type pipeline struct {
+	db        *database.Database
+	processor *processor.CallProcessor
+	logger    *logger.Logger
+}
+
+// openPipeline sets up the database and call processor without Discord or
+// the web server, so backfilled calls don't send notifications
+func openPipeline(cfg *config.Config) (*pipeline, error) {
+	log := logger.New(cfg.Logging)
+
+	db, err := database.New(cfg.Database, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	transcriber, err := transcription.New(cfg.Transcription, log)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize transcription: %w", err)
+	}
+
+	talkgroupService := talkgroups.New(cfg, log)
+	if err := talkgroupService.SetDatabase(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	cp := processor.New(db, transcriber, nil, cfg, log, talkgroupService)
+	if cfg.Corrections.Enabled {
+		engine, err := corrections.New(cfg.Corrections, db, log)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialize correction engine: %w", err)
+		}
+		cp.SetCorrections(engine)
+	}
+
+	return &pipeline{db: db, processor: cp, logger: log}, nil
+}
+
+// findUnprocessed lists recordings in a directory, oldest first, that are not
+// yet in the database
+func (p *pipeline) findUnprocessed(cfg *config.Config, directory config.WatchDirectoryConfig) ([]watcher.FileEvent, error) {
+	fw, err := watcher.New(directory, cfg.FileMonitor, p.logger)
+	if err != nil {
+		return nil, err
+	}
+	events, err := fw.ScanExisting()
+	if err != nil {
+		return nil, err
+	}
+
+	var unprocessed []watcher.FileEvent
+	for _, event := range events {
+		exists, err := p.db.FileExists(event.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			unprocessed = append(unprocessed, event)
+		}
+	}
+	sort.Slice(unprocessed, func(i, j int) bool { return unprocessed[i].ModTime.Before(unprocessed[j].ModTime) })
+	return unprocessed, nil
+}
+
+// process runs recordings through the pipeline with a pool of workers,
+// starting at most rate recordings a minute (0 for no limit). It stops
+// handing out recordings when ctx is cancelled and returns how many were
+// processed.
+func (p *pipeline) process(ctx context.Context, events []watcher.FileEvent, workers, rate int) int {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan watcher.FileEvent)
+	var mu sync.Mutex
+	processed := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range jobs {
+				p.processor.ProcessFile(ctx, event)
+
+				mu.Lock()
+				processed++
+				fmt.Printf("[%d/%d] %s\n", processed, len(events), filepath.Base(event.Path))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var throttle <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Minute / time.Duration(rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+dispatch:
+	for i, event := range events {
+		if throttle != nil && i > 0 {
+			select {
+			case <-throttle:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		select {
+		case jobs <- event:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		fmt.Printf("⏹️  Interrupted after %d of %d recordings\n", processed, len(events))
+	}
+	return processed
+}
+
+// runImportCommand handles `meiko import <dir>` and returns the exit code
+func runImportCommand(args []string) int {
+	flags, configPath := commandFlags("import")
+	system := flags.String("system", "", "System ID to file the calls under")
+	site := flags.String("site", "", "Receive site to record on the calls")
+	dryRun := flags.Bool("dry-run", false, "List the recordings that would be imported")
+	workers := flags.Int("workers", 2, "Recordings to transcribe at once")
+	rate := flags.Int("rate", 0, "Most recordings to start per minute, 0 for no limit")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: meiko import [-config path] [-system id] [-site name] [-workers n] [-rate n] [-dry-run] <dir>")
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to load configuration: %v\n", err)
+		return 1
+	}
+	p, err := openPipeline(cfg)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	defer p.db.Close()
+
+	directory := config.WatchDirectoryConfig{Path: flags.Arg(0), System: *system, Recursive: true}
+	events, err := p.findUnprocessed(cfg, directory)
+	if err != nil {
+		fmt.Printf("❌ Failed to scan %s: %v\n", flags.Arg(0), err)
+		return 1
+	}
+	for i := range events {
+		events[i].Site = *site
+	}
+
+	if *dryRun || len(events) == 0 {
+		for _, event := range events {
+			fmt.Println(event.Path)
+		}
+		fmt.Printf("%d recording(s) to import\n", len(events))
+		return 0
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	fmt.Printf("📥 Importing %d recording(s) from %s with %d worker(s)\n", len(events), flags.Arg(0), *workers)
+	processed := p.process(ctx, events, *workers, *rate)
+
+	// Recordings that are neither imported nor skipped failed, and are
+	// retried by the service if they were stored
+	stats := p.processor.Pipeline()
+	failed := int64(processed) - stats.Processed - stats.Skipped
+	fmt.Printf("✅ Imported %d of %d recording(s): %d skipped, %d failed\n", stats.Processed, processed, stats.Skipped, failed)
+	return 0
+}
+
+// runScanCommand handles `meiko scan` and returns the exit code
+func runScanCommand(args []string) int {
+	flags, configPath := commandFlags("scan")
+	process := flags.Bool("process", false, "Process the recordings found")
+	flags.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to load configuration: %v\n", err)
+		return 1
+	}
+	if !cfg.Captures() {
+		fmt.Println("Server mode has no capture directories to scan")
+		return 0
+	}
+	p, err := openPipeline(cfg)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	defer p.db.Close()
+
+	var found []watcher.FileEvent
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SYSTEM\tDIRECTORY\tUNPROCESSED")
+	for _, directory := range cfg.WatchDirectories() {
+		events, err := p.findUnprocessed(cfg, directory)
+		if err != nil {
+			w.Flush()
+			fmt.Printf("❌ Failed to scan %s: %v\n", directory.Path, err)
+			return 1
+		}
+		name := directory.System
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\n", name, directory.Path, len(events))
+		found = append(found, events...)
+	}
+	w.Flush()
+
+	if !*process || len(found) == 0 {
+		return 0
+	}
+
+	ctx, stop := interruptContext()
+	defer stop()
+
+	processed := p.process(ctx, found, 1, 0)
+	fmt.Printf("✅ Processed %d recording(s)\n", processed)
+	return 0
+}
+
+// runDBCommand handles `meiko db <vacuum|migrate|stats|backup|verify|restore>`
+// and returns the exit code
+func runDBCommand(args []string) int {
+	usage := func() {
+		fmt.Println("Usage:")
+		fmt.Println("  meiko db vacuum [-config path]          Reclaim space left by deleted calls")
+		fmt.Println("  meiko db migrate [-config path]         Bring the schema up to date")
+		fmt.Println("  meiko db stats [-config path]           Show table sizes and call history")
+		fmt.Println("  meiko db backup [-config path]          Back up the database to backup.directory")
+		fmt.Println("  meiko db verify [-passphrase p] <file>  Check that a backup can be restored")
+		fmt.Println("  meiko db restore [-passphrase p] <file> Replace the database with a backup (stop Meiko first)")
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	flags, configPath := commandFlags("db " + args[0])
+	passphrase := flags.String("passphrase", "", "Passphrase of an encrypted backup (defaults to backup.passphrase)")
+	flags.Parse(args[1:])
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to load configuration: %v\n", err)
+		return 1
+	}
+	if *passphrase == "" {
+		*passphrase = cfg.Backup.Passphrase
+	}
+
+	// Verifying and restoring work on backup files, without opening the database
+	switch args[0] {
+	case "verify":
+		if flags.NArg() != 1 {
+			usage()
+			return 2
+		}
+		calls, err := backup.Verify(flags.Arg(0), *passphrase)
+		if err != nil {
+			fmt.Printf("❌ %s cannot be restored: %v\n", flags.Arg(0), err)
+			return 1
+		}
+		fmt.Printf("✅ %s passed the integrity check (%d calls)\n", flags.Arg(0), calls)
+		return 0
+
+	case "restore":
+		if flags.NArg() != 1 {
+			usage()
+			return 2
+		}
+		calls, previous, err := backup.Restore(flags.Arg(0), *passphrase, cfg.Database.Path)
+		if err != nil {
+			fmt.Printf("❌ Restore failed, the database was not changed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Restored %s from %s (%d calls)\n", cfg.Database.Path, flags.Arg(0), calls)
+		if previous != "" {
+			fmt.Printf("The previous database was kept as %s\n", previous)
+		}
+		return 0
+	}
+	// Opening the database applies any pending migrations
+	db, err := database.New(cfg.Database, logger.New(config.LoggingConfig{Level: "error"}))
+	if err != nil {
+		fmt.Printf("❌ Failed to open database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "vacuum":
+		before := databaseSize(cfg.Database.Path)
+		if err := db.Vacuum(); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		after := databaseSize(cfg.Database.Path)
+		fmt.Printf("✅ Vacuumed %s: %.1f MB → %.1f MB\n", cfg.Database.Path, megabytes(before), megabytes(after))
+
+	case "migrate":
+		fmt.Printf("✅ Database schema is up to date (%s)\n", cfg.Database.Path)
+
+	case "stats":
+		counts, err := db.TableCounts()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+		stats, err := db.GetLifetimeStats()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return 1
+		}
+
+		fmt.Printf("Database: %s (%.1f MB)\n\n", cfg.Database.Path, megabytes(databaseSize(cfg.Database.Path)))
+		tables := make([]string, 0, len(counts))
+		for table := range counts {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TABLE\tROWS")
+		for _, table := range tables {
+			fmt.Fprintf(w, "%s\t%d\n", table, counts[table])
+		}
+		w.Flush()
+
+		fmt.Println()
+		if first, ok := stats["first_call"].(*time.Time); ok && first != nil {
+			fmt.Printf("First call: %s\n", first.Format("2006-01-02 15:04"))
+		}
+		if last, ok := stats["last_call"].(*time.Time); ok && last != nil {
+			fmt.Printf("Last call:  %s\n", last.Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("Talkgroups: %d\n", stats["unique_talkgroups"])
+
+	case "backup":
+		ctx, stop := interruptContext()
+		defer stop()
+
+		result, err := backup.New(cfg.Backup, db, logger.New(config.LoggingConfig{Level: "error"})).Run(ctx)
+		if err != nil {
+			fmt.Printf("❌ Backup failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Backed up %d calls to %s (%.1f MB) in %s\n", result.Calls, result.Path, megabytes(result.Size), result.Duration.Round(time.Millisecond))
+		if result.Key != "" {
+			fmt.Printf("Uploaded to s3://%s/%s\n", cfg.Backup.S3.Bucket, result.Key)
+		}
+
+	default:
+		usage()
+		return 2
+	}
+
+	return 0
+}
+
+// databaseSize returns the size of the database file and its write-ahead log
+func databaseSize(path string) int64 {
+	var size int64
+	for _, file := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(file); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// megabytes converts bytes to megabytes
+func megabytes(bytes int64) float64 {
+	return float64(bytes) / 1024 / 1024
+}
+
+// runServiceCommand handles `meiko service <install|uninstall|start|stop>` and
+// returns the exit code
+func runServiceCommand(args []string) int {
+	usage := func() {
+		fmt.Println("Usage: meiko service <install|uninstall|start|stop> [-config path]")
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	flags, configPath := commandFlags("service " + args[0])
+	flags.Parse(args[1:])
+
+	var err error
+	switch args[0] {
+	case "install":
+		// The service starts in the system directory, so it needs the full path
+		var path string
+		path, err = filepath.Abs(*configPath)
+		if err == nil {
+			err = winsvc.Install("serve", "-config", path)
+		}
+		if err == nil {
+			fmt.Printf("✅ Installed the %s service using %s\n", winsvc.Name, path)
+			fmt.Println("Start it with `meiko service start` or from the Services console.")
+		}
+	case "uninstall":
+		if err = winsvc.Uninstall(); err == nil {
+			fmt.Printf("✅ Removed the %s service\n", winsvc.Name)
+		}
+	case "start":
+		if err = winsvc.Start(); err == nil {
+			fmt.Printf("✅ Started the %s service\n", winsvc.Name)
+		}
+	case "stop":
+		if err = winsvc.Stop(); err == nil {
+			fmt.Printf("✅ Asked the %s service to stop\n", winsvc.Name)
+		}
+	default:
+		usage()
+		return 2
+	}
+
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
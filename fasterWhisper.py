@@ -11,7 +11,7 @@ import os
 import logging
 import time
 from pathlib import Path
-from typing import Optional, Dict, Any
+from typing import Optional, Dict, Any, List
 
 try:
     from faster_whisper import WhisperModel
@@ -34,22 +34,41 @@ class FasterWhisperTranscriber:
     def __init__(self, 
                  model_size: str = "tiny",
                  device: str = "cpu",
+                 device_index: int = 0,
                  language: Optional[str] = "en",
-                 compute_type: str = "int8"):
+                 compute_type: str = "int8",
+                 beam_size: int = 1,
+                 vad_filter: bool = True,
+                 initial_prompt: Optional[str] = None,
+                 diarize: bool = False,
+                 max_speakers: Optional[int] = None):
         """
         Initialize the transcriber with optimized settings for Raspberry Pi 5
         
         Args:
             model_size: Model size (tiny, base, small) - tiny recommended for Pi
-            device: Device to use (cpu only on Pi 5)
+            device: Device to use (cpu, cuda or auto; cpu only on Pi 5)
+            device_index: GPU to use when there is more than one
             language: Language code for transcription (None for auto-detect)
             compute_type: Quantization type for efficiency (int8 for Pi)
+            beam_size: Beams to search (1 is fastest, 5 is most accurate)
+            vad_filter: Skip silence with voice activity detection
+            initial_prompt: Glossary prompt to bias recognition towards local terms
+            diarize: Tag segments with speakers using pyannote (needs HF_TOKEN)
+            max_speakers: Upper bound on speakers per call (None for automatic)
         """
         self.model_size = model_size
         self.device = device
+        self.device_index = device_index
         self.language = language if language and language != "auto" else None
         self.compute_type = compute_type
+        self.beam_size = beam_size
+        self.vad_filter = vad_filter
+        self.initial_prompt = initial_prompt or None
+        self.diarize = diarize
+        self.max_speakers = max_speakers or None
         self.model = None
+        self.diarization_pipeline = None
         
         # Raspberry Pi optimizations
         self.cpu_threads = min(4, os.cpu_count() or 4)  # Pi 5 has 4 cores
@@ -63,6 +82,7 @@ class FasterWhisperTranscriber:
             self.model = WhisperModel(
                 self.model_size,
                 device=self.device,
+                device_index=self.device_index,
                 compute_type=self.compute_type,
                 cpu_threads=self.cpu_threads,
                 # Download to a persistent cache directory
@@ -78,6 +98,55 @@ class FasterWhisperTranscriber:
             logger.error(f"Failed to load model: {e}")
             raise
     
+    def load_diarization_pipeline(self) -> bool:
+        """Load the pyannote speaker diarization pipeline, returning False if unavailable"""
+        try:
+            from pyannote.audio import Pipeline
+        except ImportError:
+            logger.warning("pyannote.audio not installed, skipping diarization. Run: pip install pyannote.audio")
+            return False
+        
+        try:
+            self.diarization_pipeline = Pipeline.from_pretrained(
+                "pyannote/speaker-diarization-3.1",
+                use_auth_token=os.environ.get("HF_TOKEN")
+            )
+            return True
+        except Exception as e:
+            logger.warning(f"Failed to load diarization pipeline: {e}")
+            return False
+    
+    def assign_speakers(self, audio_path: str, segments: List[Dict[str, Any]]) -> List[Dict[str, Any]]:
+        """
+        Tag each transcription segment with the speaker that overlaps it most
+        
+        Args:
+            audio_path: Path to the audio file
+            segments: Transcription segments with start, end and text
+            
+        Returns:
+            Segments with a speaker label added
+        """
+        if self.diarization_pipeline is None and not self.load_diarization_pipeline():
+            return []
+        
+        params = {}
+        if self.max_speakers:
+            params["max_speakers"] = self.max_speakers
+        diarization = self.diarization_pipeline(audio_path, **params)
+        turns = [(turn.start, turn.end, speaker)
+                 for turn, _, speaker in diarization.itertracks(yield_label=True)]
+        
+        for segment in segments:
+            overlaps = {}
+            for start, end, speaker in turns:
+                overlap = min(end, segment["end"]) - max(start, segment["start"])
+                if overlap > 0:
+                    overlaps[speaker] = overlaps.get(speaker, 0.0) + overlap
+            segment["speaker"] = max(overlaps, key=overlaps.get) if overlaps else ""
+        
+        return segments
+    
     def transcribe_file(self, audio_path: str) -> Dict[str, Any]:
         """
         Transcribe an audio file and return results
@@ -104,14 +173,16 @@ class FasterWhisperTranscriber:
                 audio_path,
                 language=self.language,
                 # Speed optimizations
-                beam_size=1,              # Faster than default 5
+                beam_size=self.beam_size, # 1 is faster than the default 5
                 best_of=1,                # Faster than default 5
                 patience=1.0,             # Less patience for faster results
                 # Quality vs speed tradeoffs
                 temperature=0.0,          # Deterministic output
                 condition_on_previous_text=False,  # Faster processing
+                # Glossary boosting for street names and unit designators
+                initial_prompt=self.initial_prompt,
                 # VAD settings for better silence detection
-                vad_filter=True,
+                vad_filter=self.vad_filter,
                 vad_parameters=dict(
                     min_silence_duration_ms=500,
                     threshold=0.5,
@@ -119,16 +190,22 @@ class FasterWhisperTranscriber:
                 ),
                 # Memory optimizations
                 word_timestamps=False,    # Disable to save memory/time
-                without_timestamps=True   # Text only, no timing info needed
+                without_timestamps=not self.diarize  # Timing is only needed for diarization
             )
             
             # Combine all segments into single text
             full_text = ""
             segment_count = 0
+            timed_segments = []
             
             for segment in segments:
                 full_text += segment.text + " "
                 segment_count += 1
+                timed_segments.append({
+                    "start": segment.start,
+                    "end": segment.end,
+                    "text": segment.text.strip()
+                })
             
             # Clean up the text
             full_text = full_text.strip()
@@ -145,6 +222,10 @@ class FasterWhisperTranscriber:
                 "file_size_mb": round(os.path.getsize(audio_path) / (1024 * 1024), 2)
             }
             
+            # Speaker-tagged segments for multi-transmission calls
+            if self.diarize and timed_segments:
+                result["speaker_segments"] = self.assign_speakers(audio_path, timed_segments)
+            
             logger.info(f"Transcription completed in {transcription_time:.2f}s")
             logger.info(f"Text length: {len(full_text)} chars, Segments: {segment_count}")
             
@@ -169,19 +250,105 @@ def validate_audio_file(file_path: str) -> bool:
     
     return file_ext in valid_extensions
 
+def run_daemon(transcriber: FasterWhisperTranscriber) -> None:
+    """
+    Serve transcription requests from Meiko as JSON lines until stdin closes,
+    keeping the model loaded between calls
+    
+    Requests are {"id": 1, "command": "transcribe", "audio_file": "...",
+    "initial_prompt": "..."} or {"id": 2, "command": "ping"}. Each response
+    echoes the request id and holds the result or an "error".
+    """
+    # Only protocol messages may be written to stdout
+    out = sys.stdout
+    sys.stdout = sys.stderr
+    
+    def respond(message: Dict[str, Any]) -> None:
+        out.write(json.dumps(message, ensure_ascii=False) + "\n")
+        out.flush()
+    
+    try:
+        transcriber.load_model()
+    except Exception as e:
+        respond({"error": f"Failed to load model: {str(e)}"})
+        sys.exit(1)
+    respond({"ready": True, "model_size": transcriber.model_size})
+    
+    for line in sys.stdin:
+        line = line.strip()
+        if not line:
+            continue
+        
+        request_id = None
+        try:
+            request = json.loads(line)
+            request_id = request.get("id")
+            command = request.get("command", "transcribe")
+            
+            if command == "ping":
+                respond({"id": request_id, "ok": True})
+                continue
+            if command != "transcribe":
+                respond({"id": request_id, "error": f"Unknown command: {command}"})
+                continue
+            
+            audio_file = request.get("audio_file", "")
+            if not validate_audio_file(audio_file):
+                respond({"id": request_id, "error": f"Invalid or missing audio file: {audio_file}"})
+                continue
+            
+            transcriber.initial_prompt = request.get("initial_prompt") or None
+            result = transcriber.transcribe_file(audio_file)
+            result["id"] = request_id
+            respond(result)
+            
+        except Exception as e:
+            respond({"id": request_id, "error": f"Transcription failed: {str(e)}"})
+
+def transcriber_from_args(args: argparse.Namespace) -> FasterWhisperTranscriber:
+    """Create a transcriber from the command line options"""
+    return FasterWhisperTranscriber(
+        model_size=args.model,
+        device=args.device,
+        device_index=args.device_index,
+        language=args.language if args.language != "auto" else None,
+        compute_type=args.compute_type,
+        beam_size=args.beam_size,
+        vad_filter=not args.no_vad_filter,
+        initial_prompt=args.initial_prompt,
+        diarize=args.diarize,
+        max_speakers=args.max_speakers
+    )
+
 def main():
     """Main entry point for the transcription script"""
     parser = argparse.ArgumentParser(
         description="FasterWhisper transcription for Meiko (Raspberry Pi optimized)"
     )
-    parser.add_argument("audio_file", help="Path to audio file to transcribe")
+    parser.add_argument("audio_file", nargs="?", help="Path to audio file to transcribe")
     parser.add_argument("--model", default="tiny", 
                        choices=["tiny", "base", "small"],
                        help="Model size (default: tiny for Pi 5)")
     parser.add_argument("--language", default="en",
                        help="Language code (default: en)")
     parser.add_argument("--device", default="cpu",
-                       help="Device to use (default: cpu)")
+                       help="Device to use: cpu, cuda or auto (default: cpu)")
+    parser.add_argument("--device-index", type=int, default=0,
+                       help="GPU index when using cuda (default: 0)")
+    parser.add_argument("--compute-type", default="int8",
+                       help="Quantization: int8, int8_float16, float16, float32, ... (default: int8)")
+    parser.add_argument("--beam-size", type=int, default=1,
+                       help="Beam search width; higher is slower but more accurate (default: 1)")
+    parser.add_argument("--no-vad-filter", action="store_true",
+                       help="Disable the voice activity detection filter")
+    parser.add_argument("--initial-prompt", default=None,
+                       help="Initial prompt with glossary terms to bias transcription")
+    parser.add_argument("--diarize", action="store_true",
+                       help="Tag segments with speakers using pyannote (reads HF_TOKEN)")
+    parser.add_argument("--max-speakers", type=int, default=None,
+                       help="Maximum number of speakers for diarization")
+    parser.add_argument("--daemon", action="store_true",
+                       help="Keep the model loaded and read requests from stdin as JSON lines")
     parser.add_argument("--verbose", action="store_true",
                        help="Enable verbose logging")
     
@@ -191,6 +358,13 @@ def main():
     if args.verbose:
         logging.getLogger().setLevel(logging.INFO)
     
+    if args.daemon:
+        run_daemon(transcriber_from_args(args))
+        return
+    
+    if not args.audio_file:
+        parser.error("audio_file is required unless --daemon is set")
+    
     try:
         # Validate input file
         if not validate_audio_file(args.audio_file):
@@ -201,11 +375,7 @@ def main():
             sys.exit(1)
         
         # Initialize transcriber
-        transcriber = FasterWhisperTranscriber(
-            model_size=args.model,
-            device=args.device,
-            language=args.language if args.language != "auto" else None
-        )
+        transcriber = transcriber_from_args(args)
         
         # Perform transcription
         result = transcriber.transcribe_file(args.audio_file)
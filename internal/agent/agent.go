@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+	"Meiko/internal/metadata"
+	"Meiko/internal/watcher"
+)
+
+const (
+	initialBackoff = 2 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+// Uploader forwards recordings from a receive site to a central Meiko server
+type Uploader struct {
+	config config.AgentConfig
+	logger *logger.Logger
+	client *http.Client
+}
+
+// permanentError marks an upload the server rejected and that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+// New creates a new uploader
+func New(cfg config.AgentConfig, logger *logger.Logger) *Uploader {
+	return &Uploader{
+		config: cfg,
+		logger: logger,
+		client: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+}
+
+// Start begins forwarding file events to the server
+func (u *Uploader) Start(ctx context.Context, events <-chan watcher.FileEvent) {
+	go u.run(ctx, events)
+}
+
+// run uploads recordings one at a time, in the order they were captured
+func (u *Uploader) run(ctx context.Context, events <-chan watcher.FileEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			u.logger.Info("Agent uploader stopping...")
+			return
+		case event, ok := <-events:
+			if !ok {
+				u.logger.Info("File events channel closed, stopping uploader")
+				return
+			}
+			u.forward(ctx, event)
+		}
+	}
+}
+
+// forward uploads a recording, retrying with exponential backoff
+func (u *Uploader) forward(ctx context.Context, event watcher.FileEvent) {
+	path := event.Path
+	backoff := initialBackoff
+
+	// Recordings from a configured system keep its ID; otherwise use the agent's
+	system := event.System
+	if system == "" {
+		system = u.config.System
+	}
+
+	for attempt := 1; attempt <= u.config.MaxRetries; attempt++ {
+		status, err := u.upload(ctx, path, system)
+		if err == nil {
+			u.logger.Success("Recording uploaded", "file", filepath.Base(path), "status", status)
+			if u.config.DeleteAfterUpload {
+				sidecar := metadata.SidecarPath(path)
+				if err := os.Remove(path); err != nil {
+					u.logger.Warn("Failed to delete uploaded recording", "file", filepath.Base(path), "error", err)
+				} else if sidecar != "" {
+					os.Remove(sidecar)
+				}
+			}
+			return
+		}
+
+		if _, ok := err.(*permanentError); ok {
+			u.logger.Error("Server rejected recording", "file", filepath.Base(path), "error", err)
+			return
+		}
+
+		u.logger.Warn("Upload failed, retrying",
+			"file", filepath.Base(path),
+			"attempt", attempt,
+			"retry_in", backoff,
+			"error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+
+	u.logger.Error("Giving up on recording after repeated failures", "file", filepath.Base(path), "attempts", u.config.MaxRetries)
+}
+
+// upload sends one recording to the server's ingest endpoint
+func (u *Uploader) upload(ctx context.Context, path, system string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", &permanentError{fmt.Errorf("failed to open recording: %w", err)}
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("site", u.config.Site); err != nil {
+		return "", err
+	}
+	if system != "" {
+		if err := form.WriteField("system", system); err != nil {
+			return "", err
+		}
+	}
+	part, err := form.CreateFormFile("audio", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return "", fmt.Errorf("failed to read recording: %w", err)
+	}
+	// The recorder's sidecar file goes along, so the server reads the same details
+	if sidecar := metadata.SidecarPath(path); sidecar != "" {
+		data, err := os.ReadFile(sidecar)
+		if err != nil {
+			return "", fmt.Errorf("failed to read metadata file: %w", err)
+		}
+		part, err := form.CreateFormFile("metadata", filepath.Base(sidecar))
+		if err != nil {
+			return "", err
+		}
+		if _, err := part.Write(data); err != nil {
+			return "", err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	url := strings.TrimRight(u.config.ServerURL, "/") + "/api/v1/ingest/calls"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return "", &permanentError{fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+u.config.APIKey)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return result.Status, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return "", fmt.Errorf("server returned %d: %s", resp.StatusCode, result.Error)
+	default:
+		return "", &permanentError{fmt.Errorf("server returned %d: %s", resp.StatusCode, result.Error)}
+	}
+}
@@ -0,0 +1,192 @@
+// Package anomaly flags talkgroups that are much busier than usual, such as
+// three times the normal fire traffic at 2am, without needing keywords
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+)
+
+const (
+	// minBaselineDays is how much history is needed before volume is judged
+	minBaselineDays = 7
+
+	// silentBaseline is the normal hourly volume below which a talkgroup is
+	// treated as silent at that hour
+	silentBaseline = 0.05
+)
+
+// Anomaly is a talkgroup with unusual call volume over the last hour
+type Anomaly struct {
+	TalkgroupID    string    `json:"talkgroup_id"`
+	TalkgroupAlias string    `json:"talkgroup_alias"`
+	Calls          int64     `json:"calls"`    // Calls in the last hour
+	Baseline       float64   `json:"baseline"` // Normal calls per hour at this time of day
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+// Message describes the anomaly for alerts and the timeline
+func (a Anomaly) Message() string {
+	if a.Baseline < silentBaseline {
+		return fmt.Sprintf("%d calls on %s in the last hour, which is normally silent at this time",
+			a.Calls, a.TalkgroupAlias)
+	}
+	return fmt.Sprintf("%d calls on %s in the last hour, %.1fx the usual %.1f at this time",
+		a.Calls, a.TalkgroupAlias, float64(a.Calls)/a.Baseline, a.Baseline)
+}
+
+// Detector learns each talkgroup's normal call volume for every hour of the
+// day from the statistics rollups and flags talkgroups far above it
+type Detector struct {
+	config    config.AnomalyConfig
+	db        *database.Database
+	logger    *logger.Logger
+	onAnomaly func(Anomaly)
+
+	// Only used by the check loop
+	baseline  map[string]*[24]float64 // Normal calls per local hour of the day, by talkgroup
+	learnedAt time.Time               // Hour the baseline was learned up to
+	lastAlert map[string]time.Time    // Last anomaly by talkgroup, for the cooldown
+}
+
+// New creates an anomaly detector
+func New(cfg config.AnomalyConfig, db *database.Database, logger *logger.Logger) *Detector {
+	return &Detector{
+		config:    cfg,
+		db:        db,
+		logger:    logger,
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// OnAnomaly sets a callback for each anomaly found. It must be set before Start.
+func (d *Detector) OnAnomaly(fn func(Anomaly)) {
+	d.onAnomaly = fn
+}
+
+// Start checks the last hour's calls every check interval
+func (d *Detector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(d.config.CheckInterval) * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := d.check(time.Now()); err != nil {
+					d.logger.Error("Anomaly check failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// check compares each talkgroup's calls over the hour before now with its
+// baseline and reports those at or above the threshold
+func (d *Detector) check(now time.Time) error {
+	learned, err := d.learn(now)
+	if err != nil {
+		return err
+	}
+	if !learned {
+		return nil
+	}
+
+	recent, err := d.db.GetRecentTalkgroupVolume(now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+
+	// The last hour mostly falls in the hour of the day half an hour ago
+	hour := now.Add(-30 * time.Minute).Hour()
+	cooldown := time.Duration(d.config.Cooldown) * time.Minute
+
+	var anomalies []Anomaly
+	for _, activity := range recent {
+		if activity.Calls < int64(d.config.MinCalls) {
+			continue
+		}
+		var baseline float64
+		if hours, ok := d.baseline[activity.TalkgroupID]; ok {
+			baseline = hours[hour]
+		}
+		if float64(activity.Calls) < d.config.Threshold*baseline {
+			continue
+		}
+		if last, ok := d.lastAlert[activity.TalkgroupID]; ok && now.Sub(last) < cooldown {
+			continue
+		}
+
+		d.lastAlert[activity.TalkgroupID] = now
+		anomalies = append(anomalies, Anomaly{
+			TalkgroupID:    activity.TalkgroupID,
+			TalkgroupAlias: activity.TalkgroupAlias,
+			Calls:          activity.Calls,
+			Baseline:       baseline,
+			DetectedAt:     now,
+		})
+	}
+
+	for _, anomaly := range anomalies {
+		d.logger.Warn("Unusual talkgroup activity", "talkgroup", anomaly.TalkgroupID,
+			"calls", anomaly.Calls, "baseline", fmt.Sprintf("%.2f", anomaly.Baseline))
+		if d.onAnomaly != nil {
+			d.onAnomaly(anomaly)
+		}
+	}
+	return nil
+}
+
+// learn rebuilds the baseline from the rollups once per hour, reporting
+// whether there is enough history to judge volume yet. Days of the baseline
+// window without calls count as silent.
+func (d *Detector) learn(now time.Time) (bool, error) {
+	end := now.Truncate(time.Hour)
+	if d.baseline != nil && d.learnedAt.Equal(end) {
+		return true, nil
+	}
+
+	first, err := d.db.GetFirstCallHour()
+	if err != nil {
+		return false, err
+	}
+	if first.IsZero() {
+		return false, nil
+	}
+
+	start := end.AddDate(0, 0, -d.config.BaselineDays)
+	if first.After(start) {
+		start = first
+	}
+	days := end.Sub(start).Hours() / 24
+	if days < minBaselineDays {
+		d.logger.Debug("Anomaly", "Still learning normal call volume", "history_days", fmt.Sprintf("%.1f", days))
+		return false, nil
+	}
+
+	volume, err := d.db.GetHourlyVolume(start, end)
+	if err != nil {
+		return false, err
+	}
+
+	baseline := make(map[string]*[24]float64)
+	for _, v := range volume {
+		hours, ok := baseline[v.TalkgroupID]
+		if !ok {
+			hours = &[24]float64{}
+			baseline[v.TalkgroupID] = hours
+		}
+		hours[v.Hour.Local().Hour()] += float64(v.Calls) / days
+	}
+
+	d.baseline = baseline
+	d.learnedAt = end
+	d.logger.Debug("Anomaly", "Learned normal call volume", "talkgroups", len(baseline), "days", fmt.Sprintf("%.1f", days))
+	return true, nil
+}
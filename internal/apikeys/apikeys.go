@@ -0,0 +1,125 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"Meiko/internal/database"
+)
+
+// Scopes granted to API keys
+const (
+	ScopeReadCalls = "read-calls" // Calls, audio, timeline and summaries
+	ScopeReadStats = "read-stats" // Statistics, reports and system status
+	ScopeIngest    = "ingest"     // Submitting data to Meiko
+	ScopeEditCalls = "edit-calls" // Correcting transcriptions, talkgroups and noise by hand
+	ScopeAdmin     = "admin"      // Everything, including key and rule management
+)
+
+// Scopes lists every valid scope
+var Scopes = []string{ScopeReadCalls, ScopeReadStats, ScopeIngest, ScopeEditCalls, ScopeAdmin}
+
+// keyPrefix marks Meiko API keys so they are recognisable in configs and logs
+const keyPrefix = "mk_"
+
+// prefixLength is the number of key characters kept in the clear for identification
+const prefixLength = len(keyPrefix) + 8
+
+// Generate creates a new random API key and returns it with its display prefix
+func Generate() (key, prefix string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return key, key[:prefixLength], nil
+}
+
+// Create generates and stores a new key, returning the record and the
+// plaintext key. The plaintext key cannot be recovered later.
+func Create(db *database.Database, name string, scopes []string) (*database.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("name is required")
+	}
+
+	parsed, err := ParseScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, prefix, err := Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	record := &database.APIKey{
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: Hash(key),
+		Scopes:  parsed,
+	}
+	if err := db.InsertAPIKey(record); err != nil {
+		return nil, "", err
+	}
+
+	return record, key, nil
+}
+
+// Hash returns the stored form of an API key
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseScopes validates and normalizes a list of scopes, which may also be
+// given as comma-separated values
+func ParseScopes(values []string) ([]string, error) {
+	var scopes []string
+	seen := make(map[string]bool)
+
+	for _, value := range values {
+		for _, scope := range strings.Split(value, ",") {
+			scope = strings.ToLower(strings.TrimSpace(scope))
+			if scope == "" || seen[scope] {
+				continue
+			}
+			if !valid(scope) {
+				return nil, fmt.Errorf("unknown scope %q (valid scopes: %s)", scope, strings.Join(Scopes, ", "))
+			}
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	return scopes, nil
+}
+
+// Allows reports whether a set of scopes grants the required scope. The
+// admin scope grants every scope.
+func Allows(scopes []string, required string) bool {
+	for _, scope := range scopes {
+		if scope == required || scope == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// valid reports whether a scope is known
+func valid(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+	"Meiko/internal/storage"
+)
+
+// Exporter writes nightly static reports of the previous day's activity
+type Exporter struct {
+	config  config.ArchiveConfig
+	builder *Builder
+	logger  *logger.Logger
+	s3      *storage.S3
+}
+
+// New creates a new archive exporter. The summarizer may be nil, in which
+// case reports are written without an AI summary.
+func New(cfg config.ArchiveConfig, db *database.Database, summarizer Summarizer, logger *logger.Logger) (*Exporter, error) {
+	if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	exporter := &Exporter{
+		config:  cfg,
+		builder: NewBuilder(db, summarizer, cfg.BaseURL, cfg.MinSeverity, logger),
+		logger:  logger,
+	}
+
+	if cfg.S3.Bucket != "" {
+		exporter.s3 = storage.NewS3(cfg.S3)
+	}
+
+	return exporter, nil
+}
+
+// Start begins the nightly export schedule
+func (e *Exporter) Start(ctx context.Context) {
+	go e.run(ctx)
+}
+
+// run exports any missing report for yesterday, then waits for each scheduled run
+func (e *Exporter) run(ctx context.Context) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	if !e.reportExists(yesterday) {
+		e.exportAndLog(ctx, yesterday)
+	}
+
+	for {
+		next := e.nextRun(time.Now())
+		e.logger.Debug("Archive", "Next daily report scheduled", "at", next.Format("2006-01-02 15:04"))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			e.logger.Info("Archive exporter stopping...")
+			return
+		case <-timer.C:
+			e.exportAndLog(ctx, next.AddDate(0, 0, -1))
+		}
+	}
+}
+
+// nextRun returns the next configured run time after now
+func (e *Exporter) nextRun(now time.Time) time.Time {
+	runAt, _ := time.Parse("15:04", e.config.RunAt)
+	next := time.Date(now.Year(), now.Month(), now.Day(), runAt.Hour(), runAt.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// exportAndLog exports a day and logs the outcome
+func (e *Exporter) exportAndLog(ctx context.Context, day time.Time) {
+	if err := e.Export(ctx, day); err != nil {
+		e.logger.Error("Failed to export daily report", "date", day.Format("2006-01-02"), "error", err)
+	}
+}
+
+// Export builds and writes the report for the given day
+func (e *Exporter) Export(ctx context.Context, day time.Time) error {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	report, err := e.builder.Build(start, end)
+	if err != nil {
+		return err
+	}
+
+	name := start.Format("2006-01-02")
+	for _, format := range e.config.Formats {
+		var content []byte
+		var ext, contentType string
+
+		switch format {
+		case "markdown":
+			content, err = RenderMarkdown(report)
+			ext, contentType = ".md", "text/markdown; charset=utf-8"
+		case "html":
+			content, err = RenderHTML(report)
+			ext, contentType = ".html", "text/html; charset=utf-8"
+		case "pdf":
+			content, err = RenderPDF(report)
+			ext, contentType = ".pdf", "application/pdf"
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to render %s report: %w", format, err)
+		}
+
+		if err := e.write(ctx, name+ext, content, contentType); err != nil {
+			return err
+		}
+	}
+
+	if err := e.writeIndex(ctx); err != nil {
+		return err
+	}
+
+	e.logger.Success("Daily report exported",
+		"date", name,
+		"calls", report.TotalCalls,
+		"notable", len(report.Notable),
+		"directory", e.config.Directory)
+	return nil
+}
+
+// write stores a file in the archive directory and uploads it to S3 if configured
+func (e *Exporter) write(ctx context.Context, name string, content []byte, contentType string) error {
+	if err := os.WriteFile(filepath.Join(e.config.Directory, name), content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	if e.s3 != nil {
+		key := path.Join(e.config.S3.Prefix, name)
+		if err := e.s3.Put(ctx, key, content, contentType); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", name, err)
+		}
+		e.logger.Debug("Archive", "Uploaded report to S3", "bucket", e.config.S3.Bucket, "key", key)
+	}
+
+	return nil
+}
+
+// writeIndex regenerates the index page linking every archived report
+func (e *Exporter) writeIndex(ctx context.Context) error {
+	entries, err := os.ReadDir(e.config.Directory)
+	if err != nil {
+		return fmt.Errorf("failed to read archive directory: %w", err)
+	}
+
+	var reports []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, "index.") {
+			continue
+		}
+		if ext := filepath.Ext(name); ext == ".html" || (ext == ".md" && !e.hasFormat("html")) {
+			reports = append(reports, name)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(reports)))
+
+	var index strings.Builder
+	if e.hasFormat("html") {
+		index.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head><meta charset=\"utf-8\"><title>Meiko Daily Reports</title></head>\n<body>\n<h1>Meiko Daily Reports</h1>\n<ul>\n")
+		for _, name := range reports {
+			date := strings.TrimSuffix(name, filepath.Ext(name))
+			fmt.Fprintf(&index, "<li><a href=\"%s\">%s</a></li>\n", name, date)
+		}
+		index.WriteString("</ul>\n</body>\n</html>\n")
+		return e.write(ctx, "index.html", []byte(index.String()), "text/html; charset=utf-8")
+	}
+
+	index.WriteString("# Meiko Daily Reports\n\n")
+	for _, name := range reports {
+		date := strings.TrimSuffix(name, filepath.Ext(name))
+		fmt.Fprintf(&index, "- [%s](%s)\n", date, name)
+	}
+	return e.write(ctx, "index.md", []byte(index.String()), "text/markdown; charset=utf-8")
+}
+
+// reportExists reports whether any report file exists for the day
+func (e *Exporter) reportExists(day time.Time) bool {
+	matches, _ := filepath.Glob(filepath.Join(e.config.Directory, day.Format("2006-01-02")+".*"))
+	return len(matches) > 0
+}
+
+// hasFormat reports whether a format is enabled
+func (e *Exporter) hasFormat(format string) bool {
+	for _, f := range e.config.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package archive
+
+import (
+	"sort"
+	"time"
+
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+)
+
+const (
+	// notableCallLimit caps the number of notable calls listed in a report
+	notableCallLimit = 50
+	// busiestHourCount is the number of peak hours highlighted in a report
+	busiestHourCount = 3
+)
+
+// Summarizer produces an AI summary for a time range
+type Summarizer interface {
+	SummarizeRange(start, end time.Time) (string, error)
+}
+
+// Builder gathers the statistics, incidents and summary for a report
+type Builder struct {
+	db          *database.Database
+	summarizer  Summarizer
+	logger      *logger.Logger
+	baseURL     string
+	minSeverity int
+}
+
+// NewBuilder creates a report builder. The summarizer may be nil, in which
+// case reports are built without an AI summary.
+func NewBuilder(db *database.Database, summarizer Summarizer, baseURL string, minSeverity int, logger *logger.Logger) *Builder {
+	return &Builder{
+		db:          db,
+		summarizer:  summarizer,
+		logger:      logger,
+		baseURL:     baseURL,
+		minSeverity: minSeverity,
+	}
+}
+
+// Build gathers the report for a range. A range of exactly one calendar day
+// is a daily report; anything else is titled as a shift report.
+func (b *Builder) Build(start, end time.Time) (*Report, error) {
+	report := &Report{
+		Date:        start,
+		End:         end,
+		Shift:       !(start.Hour() == 0 && start.Minute() == 0 && end.Equal(start.AddDate(0, 0, 1))),
+		GeneratedAt: time.Now(),
+		BaseURL:     b.baseURL,
+	}
+
+	stats, err := b.db.GetCallStats(&start, &end, "")
+	if err != nil {
+		return nil, err
+	}
+	report.TotalCalls, _ = stats["total_calls"].(int64)
+	report.TotalDuration, _ = stats["total_duration"].(float64)
+	report.AvgDuration, _ = stats["avg_duration"].(float64)
+	report.UniqueTalkgroups, _ = stats["unique_talkgroups"].(int64)
+
+	talkgroupStats, err := b.db.GetTalkgroupStatsForRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	report.Talkgroups = sortedTalkgroups(talkgroupStats)
+
+	agencyStats, err := b.db.GetAgencyStatsForRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	report.Agencies = sortedTalkgroups(agencyStats)
+
+	if report.Hourly, err = b.db.GetHourlyCallCounts(start, end); err != nil {
+		return nil, err
+	}
+	report.BusiestHours = busiestHours(report.Hourly, busiestHourCount)
+
+	if report.Incidents, err = b.incidents(start, end); err != nil {
+		return nil, err
+	}
+
+	if report.Notable, err = b.db.GetNotableCalls(start, end, b.minSeverity, notableCallLimit); err != nil {
+		return nil, err
+	}
+
+	if b.summarizer != nil && report.TotalCalls > 0 {
+		summary, err := b.summarizer.SummarizeRange(start, end)
+		if err != nil {
+			b.logger.Warn("Report will not include a summary", "start", start.Format("2006-01-02 15:04"), "error", err)
+		} else {
+			report.Summary = summary
+		}
+	}
+
+	return report, nil
+}
+
+// incidents returns the stored hourly AI summaries that fall inside the range
+func (b *Builder) incidents(start, end time.Time) ([]Incident, error) {
+	var incidents []Incident
+
+	firstDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for day := firstDay; day.Before(end); day = day.AddDate(0, 0, 1) {
+		summaries, err := b.db.GetHourSummariesForDate(day.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, summary := range summaries {
+			at := time.Date(day.Year(), day.Month(), day.Day(), summary.Hour, 0, 0, 0, day.Location())
+			if at.Before(start) || !at.Before(end) || summary.CallCount == 0 {
+				continue
+			}
+			incidents = append(incidents, Incident{
+				Time:      at,
+				Summary:   summary.Summary,
+				CallCount: summary.CallCount,
+			})
+		}
+	}
+
+	return incidents, nil
+}
+
+// busiestHours returns up to n hours with the most calls, busiest first
+func busiestHours(hourly [24]int64, n int) []HourCount {
+	var hours []HourCount
+	for hour, calls := range hourly {
+		if calls > 0 {
+			hours = append(hours, HourCount{Hour: hour, Calls: calls})
+		}
+	}
+	sort.SliceStable(hours, func(i, j int) bool {
+		return hours[i].Calls > hours[j].Calls
+	})
+	if len(hours) > n {
+		hours = hours[:n]
+	}
+	return hours
+}
@@ -0,0 +1,218 @@
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Page layout in points (US Letter)
+const (
+	pdfPageWidth  = 612.0
+	pdfPageHeight = 792.0
+	pdfMargin     = 50.0
+	pdfValueX     = 400.0 // Column for table values
+	pdfWrapWidth  = 100   // Approximate characters per line at body size
+)
+
+// pdfLine is a single line of text placed on a page
+type pdfLine struct {
+	bold  bool
+	size  float64
+	text  string
+	value string // Optional right-hand column
+	space float64
+}
+
+// pdfDocument lays out lines into pages of a minimal PDF using the
+// standard Helvetica fonts, so no external renderer is required
+type pdfDocument struct {
+	lines []pdfLine
+}
+
+func (d *pdfDocument) heading(text string) {
+	d.lines = append(d.lines, pdfLine{bold: true, size: 16, text: text, space: 8})
+}
+
+func (d *pdfDocument) section(text string) {
+	d.lines = append(d.lines, pdfLine{bold: true, size: 12, text: text, space: 10})
+}
+
+func (d *pdfDocument) row(label, value string) {
+	d.lines = append(d.lines, pdfLine{size: 10, text: label, value: value})
+}
+
+// paragraph adds body text wrapped to the page width
+func (d *pdfDocument) paragraph(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		for _, wrapped := range wrapText(line, pdfWrapWidth) {
+			d.lines = append(d.lines, pdfLine{size: 10, text: wrapped})
+		}
+	}
+}
+
+// RenderPDF renders the report as a simple paginated PDF document
+func RenderPDF(report *Report) ([]byte, error) {
+	doc := &pdfDocument{}
+
+	doc.heading(report.Title())
+	doc.paragraph("Generated " + report.GeneratedAt.Format("2006-01-02 15:04 MST"))
+
+	doc.section("Summary")
+	if report.Summary != "" {
+		doc.paragraph(report.Summary)
+	} else {
+		doc.paragraph("No summary available.")
+	}
+
+	doc.section("Statistics")
+	doc.row("Total calls", strconv.FormatInt(report.TotalCalls, 10))
+	doc.row("Total airtime", strconv.FormatFloat(report.TotalDuration/60, 'f', 1, 64)+" min")
+	doc.row("Average call length", strconv.FormatFloat(report.AvgDuration, 'f', 1, 64)+" s")
+	doc.row("Active talkgroups", strconv.FormatInt(report.UniqueTalkgroups, 10))
+
+	doc.section("Calls by Agency")
+	for _, agency := range report.Agencies {
+		doc.row(agency.Name, strconv.FormatInt(agency.Calls, 10))
+	}
+
+	doc.section("Busiest Hours")
+	if len(report.BusiestHours) == 0 {
+		doc.paragraph("No activity.")
+	}
+	for _, hour := range report.BusiestHours {
+		doc.row(fmt.Sprintf("%02d:00", hour.Hour), strconv.FormatInt(hour.Calls, 10)+" calls")
+	}
+
+	doc.section("Calls by Talkgroup")
+	for _, talkgroup := range report.Talkgroups {
+		doc.row(talkgroup.Name, strconv.FormatInt(talkgroup.Calls, 10))
+	}
+
+	doc.section("Incident Log")
+	if len(report.Incidents) == 0 {
+		doc.paragraph("No hourly summaries available.")
+	}
+	for _, incident := range report.Incidents {
+		doc.paragraph(fmt.Sprintf("%s (%d calls): %s",
+			incident.Time.Format("15:04"), incident.CallCount, strings.Join(strings.Fields(incident.Summary), " ")))
+	}
+
+	doc.section("Notable Calls")
+	if len(report.Notable) == 0 {
+		doc.paragraph("No notable calls.")
+	}
+	for _, call := range report.Notable {
+		doc.paragraph(fmt.Sprintf("%s %s (severity %d/5): %s",
+			call.Timestamp.Format("15:04:05"), call.TalkgroupAlias, call.Severity,
+			strings.Join(strings.Fields(call.Transcription), " ")))
+	}
+
+	return doc.render(), nil
+}
+
+// render paginates the lines and serializes the PDF
+func (d *pdfDocument) render() []byte {
+	var pages []string
+	var content strings.Builder
+	y := pdfPageHeight - pdfMargin
+
+	for _, line := range d.lines {
+		leading := line.size*1.4 + line.space
+		if y-leading < pdfMargin {
+			pages = append(pages, content.String())
+			content.Reset()
+			y = pdfPageHeight - pdfMargin
+		}
+		y -= leading
+
+		font := "F1"
+		if line.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&content, "BT /%s %.0f Tf %.1f %.1f Td (%s) Tj ET\n", font, line.size, pdfMargin, y, pdfEscape(line.text))
+		if line.value != "" {
+			fmt.Fprintf(&content, "BT /%s %.0f Tf %.1f %.1f Td (%s) Tj ET\n", font, line.size, pdfValueX, y, pdfEscape(line.value))
+		}
+	}
+	pages = append(pages, content.String())
+
+	// Objects: 1 catalog, 2 page tree, 3-4 fonts, then a page and content stream per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+i*2))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(page), page))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfEscape converts text to a WinAnsi PDF string body. Characters the
+// standard fonts can't draw, such as the emoji in department names, are dropped.
+func pdfEscape(text string) string {
+	replacer := strings.NewReplacer("‘", "'", "’", "'", "“", "\"", "”", "\"", "–", "-", "—", "-", "…", "...")
+	text = replacer.Replace(text)
+
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// wrapText splits text into lines of at most width characters at word boundaries
+func wrapText(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := words[0]
+	for _, word := range words[1:] {
+		if len(current)+1+len(word) > width {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current += " " + word
+	}
+	return append(lines, current)
+}
@@ -0,0 +1,238 @@
+package archive
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"Meiko/internal/database"
+)
+
+// Report is the content of a single daily or shift report
+type Report struct {
+	Date             time.Time // Start of the reporting period
+	End              time.Time
+	Shift            bool // The period is not a whole calendar day
+	GeneratedAt      time.Time
+	Summary          string
+	TotalCalls       int64
+	TotalDuration    float64
+	AvgDuration      float64
+	UniqueTalkgroups int64
+	Talkgroups       []TalkgroupCount
+	Agencies         []TalkgroupCount
+	Hourly           [24]int64
+	BusiestHours     []HourCount
+	Incidents        []Incident
+	Notable          []*database.CallRecord
+	BaseURL          string
+}
+
+// TalkgroupCount is the number of calls on a talkgroup or agency during the period
+type TalkgroupCount struct {
+	Name  string
+	Calls int64
+}
+
+// HourCount is the number of calls in an hour of the day
+type HourCount struct {
+	Hour  int
+	Calls int64
+}
+
+// Incident is an hourly AI summary of activity within the period
+type Incident struct {
+	Time      time.Time
+	Summary   string
+	CallCount int
+}
+
+// Title returns the report heading
+func (r *Report) Title() string {
+	if r.Shift {
+		return "Meiko Shift Report - " + r.Date.Format("Monday, January 2, 2006 15:04") + " to " + r.End.Format("15:04")
+	}
+	return "Meiko Daily Report - " + r.Date.Format("Monday, January 2, 2006")
+}
+
+// CallURL returns a link to a call's audio, or an empty string without a base URL
+func (r *Report) CallURL(call *database.CallRecord) string {
+	if r.BaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(r.BaseURL, "/") + "/api/v1/calls/" + strconv.Itoa(call.ID) + "/audio"
+}
+
+// sortedTalkgroups converts talkgroup stats into a list ordered by call count
+func sortedTalkgroups(stats map[string]int64) []TalkgroupCount {
+	counts := make([]TalkgroupCount, 0, len(stats))
+	for name, calls := range stats {
+		counts = append(counts, TalkgroupCount{Name: name, Calls: calls})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Calls != counts[j].Calls {
+			return counts[i].Calls > counts[j].Calls
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// templateFuncs are shared by the Markdown and HTML templates
+var templateFuncs = map[string]interface{}{
+	"clock": func(t time.Time) string { return t.Format("15:04:05") },
+	"hour": func(h int) string {
+		return time.Date(2000, 1, 1, h, 0, 0, 0, time.UTC).Format("15:00")
+	},
+	"oneline": func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	},
+	"minutes": func(seconds float64) string {
+		return strconv.FormatFloat(seconds/60, 'f', 1, 64)
+	},
+	"seconds": func(seconds float64) string {
+		return strconv.FormatFloat(seconds, 'f', 1, 64)
+	},
+}
+
+const markdownTemplate = `# {{.Title}}
+
+_Generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}_
+
+## Summary
+
+{{if .Summary}}{{.Summary}}{{else}}No summary available.{{end}}
+
+## Statistics
+
+| Metric | Value |
+| --- | --- |
+| Total calls | {{.TotalCalls}} |
+| Total airtime | {{minutes .TotalDuration}} min |
+| Average call length | {{seconds .AvgDuration}} s |
+| Active talkgroups | {{.UniqueTalkgroups}} |
+
+### Calls by Agency
+
+| Agency | Calls |
+| --- | --- |
+{{range .Agencies}}| {{.Name}} | {{.Calls}} |
+{{end}}
+### Calls by Talkgroup
+
+| Talkgroup | Calls |
+| --- | --- |
+{{range .Talkgroups}}| {{.Name}} | {{.Calls}} |
+{{end}}
+### Busiest Hours
+
+{{if .BusiestHours}}{{range .BusiestHours}}- {{hour .Hour}}: {{.Calls}} calls
+{{end}}{{else}}No activity.
+{{end}}
+### Calls by Hour
+
+| Hour | Calls |
+| --- | --- |
+{{range $h, $count := .Hourly}}| {{hour $h}} | {{$count}} |
+{{end}}
+## Incident Log
+
+{{if .Incidents}}{{range .Incidents}}- **{{.Time.Format "15:04"}}** ({{.CallCount}} calls): {{oneline .Summary}}
+{{end}}{{else}}No hourly summaries available.
+{{end}}
+## Notable Calls
+
+{{if .Notable}}{{range .Notable}}- **{{clock .Timestamp}}** {{.TalkgroupAlias}} (severity {{.Severity}}/5){{with $.CallURL .}} [audio]({{.}}){{end}}: {{oneline .Transcription}}
+{{end}}{{else}}No notable calls.
+{{end}}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+.summary { white-space: pre-line; background: #f6f8fa; padding: 1rem; border-left: 4px solid #0099ff; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="muted">Generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</p>
+
+<h2>Summary</h2>
+<div class="summary">{{if .Summary}}{{.Summary}}{{else}}No summary available.{{end}}</div>
+
+<h2>Statistics</h2>
+<table>
+<tr><th>Total calls</th><td>{{.TotalCalls}}</td></tr>
+<tr><th>Total airtime</th><td>{{minutes .TotalDuration}} min</td></tr>
+<tr><th>Average call length</th><td>{{seconds .AvgDuration}} s</td></tr>
+<tr><th>Active talkgroups</th><td>{{.UniqueTalkgroups}}</td></tr>
+</table>
+
+<h3>Calls by Agency</h3>
+<table>
+<tr><th>Agency</th><th>Calls</th></tr>
+{{range .Agencies}}<tr><td>{{.Name}}</td><td>{{.Calls}}</td></tr>
+{{end}}</table>
+
+<h3>Calls by Talkgroup</h3>
+<table>
+<tr><th>Talkgroup</th><th>Calls</th></tr>
+{{range .Talkgroups}}<tr><td>{{.Name}}</td><td>{{.Calls}}</td></tr>
+{{end}}</table>
+
+<h3>Busiest Hours</h3>
+{{if .BusiestHours}}<ol>
+{{range .BusiestHours}}<li>{{hour .Hour}}: {{.Calls}} calls</li>
+{{end}}</ol>{{else}}<p>No activity.</p>{{end}}
+
+<h3>Calls by Hour</h3>
+<table>
+<tr><th>Hour</th><th>Calls</th></tr>
+{{range $h, $count := .Hourly}}<tr><td>{{hour $h}}</td><td>{{$count}}</td></tr>
+{{end}}</table>
+
+<h2>Incident Log</h2>
+{{if .Incidents}}<ul>
+{{range .Incidents}}<li><strong>{{.Time.Format "15:04"}}</strong> ({{.CallCount}} calls): {{oneline .Summary}}</li>
+{{end}}</ul>{{else}}<p>No hourly summaries available.</p>{{end}}
+
+<h2>Notable Calls</h2>
+{{if .Notable}}<ul>
+{{range .Notable}}<li><strong>{{clock .Timestamp}}</strong> {{.TalkgroupAlias}} (severity {{.Severity}}/5){{with $.CallURL .}} <a href="{{.}}">audio</a>{{end}}: {{oneline .Transcription}}</li>
+{{end}}</ul>{{else}}<p>No notable calls.</p>{{end}}
+</body>
+</html>
+`
+
+var (
+	markdownReport = template.Must(template.New("markdown").Funcs(templateFuncs).Parse(markdownTemplate))
+	htmlReport     = htmltemplate.Must(htmltemplate.New("html").Funcs(templateFuncs).Parse(htmlTemplate))
+)
+
+// RenderMarkdown renders the report as Markdown
+func RenderMarkdown(report *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := markdownReport.Execute(&buf, report); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderHTML renders the report as a standalone HTML page
+func RenderHTML(report *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlReport.Execute(&buf, report); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"os/exec"
+)
+
+// Decode converts an audio file to mono float samples at sampleRate with the
+// ffmpeg executable
+func Decode(ctx context.Context, ffmpeg, filePath string, sampleRate int) ([]float64, error) {
+	cmd := exec.CommandContext(ctx, ffmpeg, "-v", "quiet", "-i", filePath,
+		"-ac", "1", "-ar", fmt.Sprintf("%d", sampleRate), "-f", "s16le", "-")
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg decode failed: %w", err)
+	}
+
+	raw := stdout.Bytes()
+	samples := make([]float64, len(raw)/2)
+	for i := range samples {
+		samples[i] = float64(int16(binary.LittleEndian.Uint16(raw[i*2:]))) / 32768.0
+	}
+	return samples, nil
+}
+
+// HannWindow returns a Hann window of length n
+func HannWindow(n int) []float64 {
+	window := make([]float64, n)
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+	}
+	return window
+}
+
+// PowerSpectrum returns the power in each frequency bin of a windowed frame;
+// len(frame) must be a power of two
+func PowerSpectrum(frame, window []float64) []float64 {
+	spectrum := make([]complex128, len(frame))
+	for i, sample := range frame {
+		spectrum[i] = complex(sample*window[i], 0)
+	}
+	FFT(spectrum)
+
+	power := make([]float64, len(frame)/2+1)
+	for bin := range power {
+		magnitude := cmplx.Abs(spectrum[bin])
+		power[bin] = magnitude * magnitude
+	}
+	return power
+}
+
+// FFT performs an in-place radix-2 FFT; len(x) must be a power of two
+func FFT(x []complex128) {
+	n := len(x)
+
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even := x[start+k]
+				odd := w * x[start+k+size/2]
+				x[start+k] = even + odd
+				x[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedFormat is returned by Duration for files that are neither MP3
+// nor WAV
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+// Duration reads the length of an MP3 or WAV recording from its headers,
+// without decoding the audio or running ffprobe
+func Duration(filePath string) (time.Duration, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".mp3":
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return 0, err
+		}
+		return mp3Duration(data)
+	case ".wav":
+		file, err := os.Open(filePath)
+		if err != nil {
+			return 0, err
+		}
+		defer file.Close()
+		return wavDuration(file)
+	default:
+		return 0, ErrUnsupportedFormat
+	}
+}
+
+// mp3Header is a decoded MPEG audio frame header
+type mp3Header struct {
+	sampleRate int // Hz
+	samples    int // Samples per frame
+	size       int // Frame length in bytes, including the header
+	mono       bool
+	mpeg1      bool
+}
+
+// Bitrates in kbps by bitrate index, for MPEG-1 layers I-III and MPEG-2/2.5
+// layer I and layers II-III
+var mp3Bitrates = [5][16]int{
+	{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+	{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+	{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+}
+
+// Sample rates in Hz by sample rate index, for MPEG-1, MPEG-2 and MPEG-2.5
+var mp3SampleRates = [3][3]int{
+	{44100, 48000, 32000},
+	{22050, 24000, 16000},
+	{11025, 12000, 8000},
+}
+
+// parseMP3Header decodes the four header bytes of an MPEG audio frame
+func parseMP3Header(b []byte) (mp3Header, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mp3Header{}, false
+	}
+	version := (b[1] >> 3) & 0x03 // 0 = MPEG-2.5, 2 = MPEG-2, 3 = MPEG-1
+	layer := 4 - int((b[1]>>1)&0x03)
+	bitrateIndex := b[2] >> 4
+	rateIndex := (b[2] >> 2) & 0x03
+	padding := int((b[2] >> 1) & 0x01)
+	if version == 1 || layer == 4 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return mp3Header{}, false
+	}
+
+	h := mp3Header{mpeg1: version == 3, mono: b[3]>>6 == 3}
+	var table int
+	switch {
+	case h.mpeg1:
+		table = layer - 1
+		h.sampleRate = mp3SampleRates[0][rateIndex]
+	case layer == 1:
+		table = 3
+	default:
+		table = 4
+	}
+	if !h.mpeg1 {
+		h.sampleRate = mp3SampleRates[1][rateIndex]
+		if version == 0 {
+			h.sampleRate = mp3SampleRates[2][rateIndex]
+		}
+	}
+	bitrate := mp3Bitrates[table][bitrateIndex] * 1000
+
+	switch {
+	case layer == 1:
+		h.samples = 384
+		h.size = (12*bitrate/h.sampleRate + padding) * 4
+	case layer == 3 && !h.mpeg1:
+		h.samples = 576
+		h.size = 72*bitrate/h.sampleRate + padding
+	default:
+		h.samples = 1152
+		h.size = 144*bitrate/h.sampleRate + padding
+	}
+	return h, true
+}
+
+// mp3Duration measures an MP3 file. A Xing, Info or VBRI header in the first
+// frame gives the frame count directly; otherwise every frame is counted,
+// which also handles variable bitrate files without one.
+func mp3Duration(data []byte) (time.Duration, error) {
+	offset := 0
+
+	// Skip an ID3v2 tag, whose size is stored as a syncsafe integer
+	if len(data) >= 10 && string(data[:3]) == "ID3" {
+		size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+		offset = 10 + size
+		if data[5]&0x10 != 0 {
+			offset += 10 // Footer
+		}
+	}
+
+	var first *mp3Header
+	frames := 0
+	for offset+4 <= len(data) {
+		h, ok := parseMP3Header(data[offset:])
+		if !ok {
+			// Resynchronise on the next frame, skipping junk between frames
+			offset++
+			continue
+		}
+		if first == nil {
+			first = &h
+			if count, ok := mp3FrameCount(data[offset:], h); ok {
+				return samplesDuration(count*h.samples, h.sampleRate), nil
+			}
+		}
+		if offset+h.size > len(data) {
+			break // Truncated final frame
+		}
+		frames++
+		offset += h.size
+	}
+
+	if first == nil {
+		return 0, fmt.Errorf("no MP3 frames found")
+	}
+	return samplesDuration(frames*first.samples, first.sampleRate), nil
+}
+
+// mp3FrameCount reads the frame count from a Xing, Info or VBRI header in the
+// first frame, if there is one
+func mp3FrameCount(frame []byte, h mp3Header) (int, bool) {
+	// Xing and Info headers follow the side information
+	side := 17
+	switch {
+	case h.mpeg1 && !h.mono:
+		side = 32
+	case !h.mpeg1 && h.mono:
+		side = 9
+	}
+	if at := 4 + side; at+12 <= len(frame) {
+		tag := string(frame[at : at+4])
+		if (tag == "Xing" || tag == "Info") && binary.BigEndian.Uint32(frame[at+4:])&0x01 != 0 {
+			if count := int(binary.BigEndian.Uint32(frame[at+8:])); count > 0 {
+				return count, true
+			}
+		}
+	}
+
+	// VBRI headers sit at a fixed offset
+	if len(frame) >= 36+18 && string(frame[36:40]) == "VBRI" {
+		if count := int(binary.BigEndian.Uint32(frame[36+14:])); count > 0 {
+			return count, true
+		}
+	}
+
+	return 0, false
+}
+
+// wavDuration measures a WAV file from its fmt and data chunks
+func wavDuration(r io.ReadSeeker) (time.Duration, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return 0, fmt.Errorf("failed to read WAV header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return 0, fmt.Errorf("not a WAV file")
+	}
+
+	byteRate := 0
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return 0, fmt.Errorf("no data chunk in WAV file")
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			var format [16]byte
+			if size < int64(len(format)) {
+				return 0, fmt.Errorf("invalid WAV fmt chunk")
+			}
+			if _, err := io.ReadFull(r, format[:]); err != nil {
+				return 0, fmt.Errorf("failed to read WAV fmt chunk: %w", err)
+			}
+			byteRate = int(binary.LittleEndian.Uint32(format[8:12]))
+			size -= int64(len(format))
+		case "data":
+			if byteRate == 0 {
+				return 0, fmt.Errorf("WAV data chunk before fmt chunk")
+			}
+			// A recorder that was stopped early may leave the size unset, so
+			// never count beyond the end of the file
+			position, err := r.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return 0, err
+			}
+			end, err := r.Seek(0, io.SeekEnd)
+			if err != nil {
+				return 0, err
+			}
+			if remaining := end - position; size == 0xFFFFFFFF || size > remaining {
+				size = remaining
+			}
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second)), nil
+		}
+
+		// Chunks are padded to an even length
+		if _, err := r.Seek(size+size%2, io.SeekCurrent); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// samplesDuration converts a sample count at a sample rate to a duration
+func samplesDuration(samples, sampleRate int) time.Duration {
+	return time.Duration(int64(samples) * int64(time.Second) / int64(sampleRate))
+}
@@ -0,0 +1,234 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// Frame headers used by the tests
+var (
+	mpeg1Stereo = []byte{0xFF, 0xFB, 0x90, 0x00} // MPEG-1 layer III, 128 kbps, 44.1 kHz: 417 byte frames
+	mpeg1Mono   = []byte{0xFF, 0xFB, 0x90, 0xC0} // As above, mono
+	mpeg2Mono   = []byte{0xFF, 0xF3, 0x80, 0xC0} // MPEG-2 layer III, 64 kbps, 22.05 kHz, mono: 208 byte frames
+)
+
+// frame returns an MPEG audio frame of size bytes with the given header
+func frame(header []byte, size int) []byte {
+	f := make([]byte, size)
+	copy(f, header)
+	return f
+}
+
+// frames returns count MPEG-1 layer III frames at 128 kbps and 44.1 kHz
+func frames(count int) []byte {
+	var b []byte
+	for i := 0; i < count; i++ {
+		b = append(b, frame(mpeg1Stereo, 417)...)
+	}
+	return b
+}
+
+// xingFrame returns a first frame carrying a Xing or Info header that
+// reports count frames, at offset, after the side information
+func xingFrame(header []byte, tag string, offset, count int) []byte {
+	f := frame(header, 417)
+	copy(f[offset:], tag)
+	binary.BigEndian.PutUint32(f[offset+4:], 0x01) // Frame count present
+	binary.BigEndian.PutUint32(f[offset+8:], uint32(count))
+	return f
+}
+
+// vbriFrame returns a first frame carrying a VBRI header that reports count
+// frames
+func vbriFrame(count int) []byte {
+	f := frame(mpeg1Stereo, 417)
+	copy(f[36:], "VBRI")
+	binary.BigEndian.PutUint32(f[36+14:], uint32(count))
+	return f
+}
+
+// id3Tag returns an ID3v2 tag with size bytes of contents, which contain
+// something that looks like a frame header
+func id3Tag(size int) []byte {
+	tag := []byte{'I', 'D', '3', 3, 0, 0,
+		byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	contents := make([]byte, size)
+	copy(contents[100:], mpeg1Stereo)
+	return append(tag, contents...)
+}
+
+// samples returns how long a number of samples at a sample rate lasts
+func samples(count, sampleRate int) time.Duration {
+	return time.Duration(int64(count) * int64(time.Second) / int64(sampleRate))
+}
+
+func TestMP3Duration(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want time.Duration
+	}{
+		{
+			name: "CBR",
+			data: frames(100),
+			want: samples(100*1152, 44100),
+		},
+		{
+			name: "MPEG-2",
+			data: bytes.Repeat(frame(mpeg2Mono, 208), 50),
+			want: samples(50*576, 22050),
+		},
+		{
+			name: "Xing",
+			data: append(xingFrame(mpeg1Stereo, "Xing", 4+32, 500), frames(3)...),
+			want: samples(500*1152, 44100),
+		},
+		{
+			name: "Info mono",
+			data: append(xingFrame(mpeg1Mono, "Info", 4+17, 250), frames(3)...),
+			want: samples(250*1152, 44100),
+		},
+		{
+			name: "VBRI",
+			data: append(vbriFrame(800), frames(3)...),
+			want: samples(800*1152, 44100),
+		},
+		{
+			name: "ID3 tag",
+			data: append(id3Tag(1000), frames(20)...),
+			want: samples(20*1152, 44100),
+		},
+		{
+			name: "truncated final frame",
+			data: append(frames(10), frame(mpeg1Stereo, 200)...),
+			want: samples(10*1152, 44100),
+		},
+		{
+			name: "junk between frames",
+			data: append(append(frames(5), 0x00, 0x12, 0x34), frames(5)...),
+			want: samples(10*1152, 44100),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mp3Duration(tt.data)
+			if err != nil {
+				t.Fatalf("mp3Duration: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("duration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMP3DurationNoFrames(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"empty":    nil,
+		"text":     []byte("not an mp3 file at all"),
+		"tag only": id3Tag(200)[:150],
+	} {
+		if _, err := mp3Duration(data); err == nil {
+			t.Errorf("%s: mp3Duration succeeded", name)
+		}
+	}
+}
+
+// chunk returns a RIFF chunk, padded to an even length
+func chunk(id string, size uint32, data []byte) []byte {
+	b := append([]byte(id), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b[4:], size)
+	b = append(b, data...)
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// wav returns a WAV file made of the given chunks
+func wav(chunks ...[]byte) []byte {
+	body := []byte("WAVE")
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+	b := append([]byte("RIFF"), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(body)))
+	return append(b, body...)
+}
+
+// fmtChunk returns the fmt chunk of 16-bit mono PCM at 8 kHz, 16000 bytes a second
+func fmtChunk() []byte {
+	format := make([]byte, 16)
+	binary.LittleEndian.PutUint16(format[0:], 1)     // PCM
+	binary.LittleEndian.PutUint16(format[2:], 1)     // Channels
+	binary.LittleEndian.PutUint32(format[4:], 8000)  // Sample rate
+	binary.LittleEndian.PutUint32(format[8:], 16000) // Byte rate
+	binary.LittleEndian.PutUint16(format[12:], 2)    // Block align
+	binary.LittleEndian.PutUint16(format[14:], 16)   // Bits per sample
+	return chunk("fmt ", 16, format)
+}
+
+func TestWAVDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want time.Duration
+	}{
+		{
+			name: "PCM",
+			data: wav(fmtChunk(), chunk("data", 32000, make([]byte, 32000))),
+			want: 2 * time.Second,
+		},
+		{
+			name: "extra chunks",
+			data: wav(
+				chunk("LIST", 5, []byte("INFOx")),
+				fmtChunk(),
+				chunk("fact", 4, []byte{0x40, 0x1F, 0, 0}),
+				chunk("data", 24000, make([]byte, 24000)),
+			),
+			want: 1500 * time.Millisecond,
+		},
+		{
+			name: "unset data size",
+			data: wav(fmtChunk(), chunk("data", 0xFFFFFFFF, make([]byte, 8000))),
+			want: 500 * time.Millisecond,
+		},
+		{
+			name: "data size beyond the end",
+			data: wav(fmtChunk(), chunk("data", 64000, make([]byte, 16000))),
+			want: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wavDuration(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("wavDuration: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("duration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWAVDurationInvalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"not RIFF":      []byte("ID3\x03\x00\x00\x00\x00\x00\x00 not a wav file"),
+		"no data chunk": wav(fmtChunk()),
+		"data before fmt": wav(
+			chunk("data", 16000, make([]byte, 16000)),
+			fmtChunk(),
+		),
+		"short fmt chunk": wav(chunk("fmt ", 8, make([]byte, 8)), chunk("data", 16000, make([]byte, 16000))),
+	} {
+		if _, err := wavDuration(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: wavDuration succeeded", name)
+		}
+	}
+}
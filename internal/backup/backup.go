@@ -0,0 +1,319 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+	"Meiko/internal/storage"
+)
+
+const (
+	// filePrefix and the extensions name backup files, so old ones can be
+	// found and removed
+	filePrefix   = "meiko-"
+	backupExt    = ".db.gz"
+	encryptedExt = ".enc"
+
+	// catchUpDelay is how long after startup a missed scheduled backup is taken
+	catchUpDelay = 5 * time.Minute
+)
+
+// Result describes a finished backup
+type Result struct {
+	Path     string
+	Size     int64
+	Calls    int64  // Calls in the backup
+	Key      string // Object key, when uploaded to S3
+	Duration time.Duration
+}
+
+// Service takes compressed, optionally encrypted snapshots of the database
+// and keeps the newest few
+type Service struct {
+	config config.BackupConfig
+	db     *database.Database
+	s3     *storage.S3 // nil unless a bucket is configured
+	logger *logger.Logger
+	mu     sync.Mutex // Only one backup runs at a time
+}
+
+// New creates the backup service
+func New(cfg config.BackupConfig, db *database.Database, logger *logger.Logger) *Service {
+	s := &Service{config: cfg, db: db, logger: logger}
+	if cfg.S3.Bucket != "" {
+		s.s3 = storage.NewS3(cfg.S3)
+	}
+	return s
+}
+
+// Start takes a backup every interval. A backup missed while Meiko was
+// stopped is taken shortly after it starts.
+func (s *Service) Start(ctx context.Context) {
+	go func() {
+		interval := time.Duration(s.config.Interval) * time.Hour
+		wait := catchUpDelay
+		if latest, ok := s.latest(); ok && time.Since(latest) < interval {
+			wait = interval - time.Since(latest)
+		}
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				result, err := s.Run(ctx)
+				if err != nil {
+					s.logger.Error("Scheduled backup failed", "error", err)
+				} else {
+					s.logger.Info("Database backed up", "file", result.Path, "size_mb", fmt.Sprintf("%.1f", float64(result.Size)/1024/1024), "calls", result.Calls)
+				}
+				timer.Reset(interval)
+			}
+		}
+	}()
+}
+
+// Run takes a backup now: the database is snapshotted with SQLite's backup
+// API, checked for integrity, compressed and encrypted when a passphrase is
+// set. It is then uploaded when S3 is configured, and old backups removed.
+func (s *Service) Run(ctx context.Context) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	started := time.Now()
+	if err := os.MkdirAll(s.config.Directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	name := filePrefix + started.Format("20060102-150405") + backupExt
+	if s.config.Passphrase != "" {
+		name += encryptedExt
+	}
+	file := filepath.Join(s.config.Directory, name)
+
+	snapshot := file + ".snapshot"
+	defer os.Remove(snapshot)
+	if err := s.db.Backup(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	calls, err := database.VerifyFile(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot failed verification: %w", err)
+	}
+
+	partial := file + ".partial"
+	if err := compress(snapshot, partial, s.config.Passphrase); err != nil {
+		os.Remove(partial)
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.Rename(partial, file); err != nil {
+		os.Remove(partial)
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Path: file, Size: info.Size(), Calls: calls}
+
+	if s.s3 != nil {
+		key := path.Join(s.config.S3.Prefix, name)
+		if err := s.s3.PutFile(ctx, key, file, "application/octet-stream"); err != nil {
+			return nil, fmt.Errorf("backup written to %s but upload failed: %w", file, err)
+		}
+		result.Key = key
+	}
+
+	s.prune()
+	result.Duration = time.Since(started)
+	return result, nil
+}
+
+// backups lists the backup files in the directory, oldest first
+func (s *Service) backups() []string {
+	entries, err := os.ReadDir(s.config.Directory)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, filePrefix) && (strings.HasSuffix(name, backupExt) || strings.HasSuffix(name, backupExt+encryptedExt)) {
+			names = append(names, name)
+		}
+	}
+	// Names start with the time, so they sort oldest first
+	sort.Strings(names)
+	return names
+}
+
+// latest returns when the newest backup was written
+func (s *Service) latest() (time.Time, bool) {
+	names := s.backups()
+	if len(names) == 0 {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(filepath.Join(s.config.Directory, names[len(names)-1]))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// prune removes all but the newest keep backups from the directory. Backups
+// uploaded to S3 are left for the bucket's lifecycle rules.
+func (s *Service) prune() {
+	names := s.backups()
+	for len(names) > s.config.Keep {
+		if err := os.Remove(filepath.Join(s.config.Directory, names[0])); err != nil {
+			s.logger.Warn("Failed to remove old backup", "file", names[0], "error", err)
+		}
+		names = names[1:]
+	}
+}
+
+// compress gzips a snapshot into a backup file, encrypting it when a
+// passphrase is given
+func compress(snapshot, file, passphrase string) error {
+	in, err := os.Open(snapshot)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var w io.Writer = out
+	var encrypted *encryptWriter
+	if passphrase != "" {
+		encrypted, err = newEncryptWriter(out, passphrase)
+		if err != nil {
+			return err
+		}
+		w = encrypted
+	}
+
+	gz := gzip.NewWriter(w)
+	if _, err := io.Copy(gz, in); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if encrypted != nil {
+		if err := encrypted.Close(); err != nil {
+			return err
+		}
+	}
+	return out.Sync()
+}
+
+// extract writes the database in a backup to dest. Encrypted and compressed
+// backups are detected from their contents, and a plain SQLite file is
+// copied as it is.
+func extract(backup, dest, passphrase string) error {
+	in, err := os.Open(backup)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	r := bufio.NewReader(in)
+	var reader io.Reader = r
+	if magic, _ := r.Peek(len(encryptedMagic)); string(magic) == encryptedMagic {
+		r.Discard(len(encryptedMagic))
+		decrypted, err := newDecryptReader(r, passphrase)
+		if err != nil {
+			return err
+		}
+		if reader, err = gzip.NewReader(decrypted); err != nil {
+			return fmt.Errorf("failed to decompress backup: %w", err)
+		}
+	} else if magic, _ := r.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		if reader, err = gzip.NewReader(r); err != nil {
+			return fmt.Errorf("failed to decompress backup: %w", err)
+		}
+	}
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, reader); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Verify checks that a backup decrypts, decompresses and passes SQLite's
+// integrity check, and returns the number of calls in it
+func Verify(backup, passphrase string) (int64, error) {
+	temp, err := os.CreateTemp("", "meiko-verify-*.db")
+	if err != nil {
+		return 0, err
+	}
+	temp.Close()
+	defer os.Remove(temp.Name())
+
+	if err := extract(backup, temp.Name(), passphrase); err != nil {
+		return 0, err
+	}
+	return database.VerifyFile(temp.Name())
+}
+
+// Restore replaces the database at dbPath with a verified backup. Meiko must
+// be stopped first. The replaced database is kept next to it with the
+// suffix .before-restore, and its path is returned.
+func Restore(backup, passphrase, dbPath string) (int64, string, error) {
+	restored := dbPath + ".restoring"
+	defer os.Remove(restored)
+	if err := extract(backup, restored, passphrase); err != nil {
+		return 0, "", err
+	}
+	calls, err := database.VerifyFile(restored)
+	if err != nil {
+		return 0, "", fmt.Errorf("backup failed verification: %w", err)
+	}
+
+	// Keep the current database, with its write-ahead log, until the
+	// restore is known to be good
+	previous := ""
+	if _, err := os.Stat(dbPath); err == nil {
+		previous = dbPath + ".before-restore"
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Rename(dbPath+suffix, previous+suffix); err != nil && !os.IsNotExist(err) {
+				return 0, "", fmt.Errorf("failed to move the current database aside: %w", err)
+			}
+		}
+	}
+
+	if err := os.Rename(restored, dbPath); err != nil {
+		return 0, "", fmt.Errorf("failed to move the restored database into place: %w", err)
+	}
+	return calls, previous, nil
+}
@@ -0,0 +1,193 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted backups start with encryptedMagic, the scrypt salt and a nonce
+// prefix. The compressed data follows in chunks sealed with AES-256-GCM, each
+// preceded by a final flag and its sealed length. The flag is authenticated,
+// so a truncated backup fails to decrypt instead of restoring partially.
+const (
+	encryptedMagic = "MEIKOBK1"
+	saltSize       = 16
+	noncePrefix    = 8
+	chunkSize      = 64 * 1024
+)
+
+// deriveKey derives the AES key from the passphrase
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+}
+
+// newGCM creates the cipher for a passphrase and salt
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce is the nonce prefix followed by the chunk number
+func chunkNonce(prefix []byte, counter uint32) []byte {
+	nonce := make([]byte, noncePrefix+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefix:], counter)
+	return nonce
+}
+
+// encryptWriter encrypts everything written to it; Close seals the last chunk
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+}
+
+// newEncryptWriter writes the header and returns a writer encrypting to w
+func newEncryptWriter(w io.Writer, passphrase string) (*encryptWriter, error) {
+	header := make([]byte, len(encryptedMagic)+saltSize+noncePrefix)
+	copy(header, encryptedMagic)
+	if _, err := rand.Read(header[len(encryptedMagic):]); err != nil {
+		return nil, err
+	}
+	salt := header[len(encryptedMagic) : len(encryptedMagic)+saltSize]
+	aead, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		prefix: header[len(encryptedMagic)+saltSize:],
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(chunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close seals the buffered data as the final chunk
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+// seal encrypts and writes the buffered data as one chunk
+func (e *encryptWriter) seal(final bool) error {
+	flag := byte(0)
+	if final {
+		flag = 1
+	}
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.counter), e.buf, []byte{flag})
+	e.counter++
+	e.buf = e.buf[:0]
+
+	header := make([]byte, 5)
+	header[0] = flag
+	binary.BigEndian.PutUint32(header[1:], uint32(len(sealed)))
+	if _, err := e.w.Write(header); err != nil {
+		return err
+	}
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// decryptReader decrypts a backup written by encryptWriter
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	final   bool
+}
+
+// newDecryptReader reads the header, after the magic, and returns a reader
+// of the decrypted data
+func newDecryptReader(r *bufio.Reader, passphrase string) (*decryptReader, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("backup is encrypted; a passphrase is required")
+	}
+	header := make([]byte, saltSize+noncePrefix)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read backup header: %w", err)
+	}
+	aead, err := newGCM(passphrase, header[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: r, aead: aead, prefix: header[saltSize:]}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.final {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next chunk
+func (d *decryptReader) open() error {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("backup is truncated")
+		}
+		return err
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > chunkSize+uint32(d.aead.Overhead()) {
+		return fmt.Errorf("backup is corrupt")
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return fmt.Errorf("backup is truncated")
+	}
+
+	plain, err := d.aead.Open(nil, chunkNonce(d.prefix, d.counter), sealed, header[:1])
+	if err != nil {
+		return fmt.Errorf("failed to decrypt backup: wrong passphrase or corrupt file")
+	}
+	d.counter++
+	d.buf = plain
+	d.final = header[0] == 1
+	return nil
+}
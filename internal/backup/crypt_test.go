@@ -0,0 +1,141 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// sealedChunk is the size of a full chunk in an encrypted backup: the final
+// flag, the sealed length, the data and the GCM tag
+const sealedChunk = 5 + chunkSize + 16
+
+// encrypt returns data encrypted with a passphrase
+func encrypt(t *testing.T, data []byte, passphrase string) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	w, err := newEncryptWriter(&out, passphrase)
+	if err != nil {
+		t.Fatalf("newEncryptWriter: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return out.Bytes()
+}
+
+// decrypt reads an encrypted backup with a passphrase, skipping the magic as
+// the restore does before it picks the reader
+func decrypt(encrypted []byte, passphrase string) ([]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(encrypted[len(encryptedMagic):]))
+	d, err := newDecryptReader(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(d)
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	for name, size := range map[string]int{
+		"empty":            0,
+		"small":            100,
+		"one chunk":        chunkSize,
+		"several chunks":   2*chunkSize + chunkSize/2,
+		"chunk and a byte": chunkSize + 1,
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := bytes.Repeat([]byte("meiko"), size/5+1)[:size]
+			got, err := decrypt(encrypt(t, data, "secret"), "secret")
+			if err != nil {
+				t.Fatalf("decrypt: %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("decrypted %d bytes, want the %d written", len(got), len(data))
+			}
+		})
+	}
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), (2*chunkSize+chunkSize/2)/10)
+	header := len(encryptedMagic) + saltSize + noncePrefix
+
+	tests := []struct {
+		name       string
+		passphrase string
+		modify     func([]byte) []byte
+		want       string
+	}{
+		{
+			name:       "wrong passphrase",
+			passphrase: "wrong",
+			modify:     func(b []byte) []byte { return b },
+			want:       "wrong passphrase or corrupt file",
+		},
+		{
+			name:       "no passphrase",
+			passphrase: "",
+			modify:     func(b []byte) []byte { return b },
+			want:       "a passphrase is required",
+		},
+		{
+			name:       "final chunk missing",
+			passphrase: "secret",
+			modify:     func(b []byte) []byte { return b[:header+2*sealedChunk] },
+			want:       "truncated",
+		},
+		{
+			name:       "cut inside a chunk",
+			passphrase: "secret",
+			modify:     func(b []byte) []byte { return b[:header+sealedChunk+100] },
+			want:       "truncated",
+		},
+		{
+			name:       "tampered chunk",
+			passphrase: "secret",
+			modify: func(b []byte) []byte {
+				b[header+sealedChunk+5+10] ^= 0x01
+				return b
+			},
+			want: "wrong passphrase or corrupt file",
+		},
+		{
+			name:       "chunk marked final",
+			passphrase: "secret",
+			modify: func(b []byte) []byte {
+				b[header] = 1
+				return b
+			},
+			want: "wrong passphrase or corrupt file",
+		},
+		{
+			name:       "chunks swapped",
+			passphrase: "secret",
+			modify: func(b []byte) []byte {
+				first := bytes.Clone(b[header : header+sealedChunk])
+				copy(b[header:], b[header+sealedChunk:header+2*sealedChunk])
+				copy(b[header+sealedChunk:], first)
+				return b
+			},
+			want: "wrong passphrase or corrupt file",
+		},
+	}
+
+	encrypted := encrypt(t, data, "secret")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decrypt(tt.modify(bytes.Clone(encrypted)), tt.passphrase)
+			if err == nil {
+				t.Fatal("decrypt succeeded")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
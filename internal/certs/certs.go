@@ -0,0 +1,95 @@
+// Package certs obtains and renews the dashboard's TLS certificate from Let's
+// Encrypt or another ACME certificate authority
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+)
+
+// Manager provides certificates for the dashboard's TLS listener. With the
+// http-01 challenge it answers the CA on the HTTP port; with dns-01 it runs a
+// command to publish the challenge as a TXT record, which also allows
+// wildcard names and hosts unreachable from the internet.
+type Manager struct {
+	config config.WebACMEConfig
+	logger *logger.Logger
+
+	autocert *autocert.Manager // http-01
+	dns      *dnsManager       // dns-01
+}
+
+// New creates a certificate manager. Certificates are obtained on first use
+// with http-01, or by Start with dns-01.
+func New(cfg config.WebACMEConfig, logger *logger.Logger) (*Manager, error) {
+	m := &Manager{config: cfg, logger: logger}
+
+	switch cfg.Challenge {
+	case "http-01":
+		m.autocert = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cfg.CacheDir),
+			HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+			Email:      cfg.Email,
+			Client:     &acme.Client{DirectoryURL: cfg.DirectoryURL},
+		}
+	case "dns-01":
+		dns, err := newDNSManager(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		m.dns = dns
+	default:
+		return nil, fmt.Errorf("unsupported ACME challenge %q", cfg.Challenge)
+	}
+
+	return m, nil
+}
+
+// TLSConfig returns the TLS configuration serving the managed certificate
+func (m *Manager) TLSConfig() *tls.Config {
+	if m.autocert != nil {
+		return m.autocert.TLSConfig()
+	}
+	return &tls.Config{
+		GetCertificate: m.dns.getCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// Start answers http-01 challenges on the HTTP port, redirecting other
+// requests to HTTPS, or obtains the dns-01 certificate and renews it before
+// it expires. It returns once a dns-01 certificate is ready to serve.
+func (m *Manager) Start(ctx context.Context) error {
+	if m.dns != nil {
+		return m.dns.start(ctx)
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", m.config.HTTPPort),
+		Handler:           m.autocert.HTTPHandler(nil),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		m.logger.Info("Answering ACME challenges", "port", m.config.HTTPPort, "domains", m.config.Domains)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.logger.Error("ACME challenge listener failed", "port", m.config.HTTPPort, "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	return nil
+}
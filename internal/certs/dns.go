@@ -0,0 +1,353 @@
+package certs
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+)
+
+const (
+	// renewBefore is how long before expiry a certificate is renewed, as
+	// Let's Encrypt recommends
+	renewBefore = 30 * 24 * time.Hour
+
+	// renewCheck is how often the certificate's expiry is checked
+	renewCheck = 12 * time.Hour
+
+	// obtainTimeout bounds one attempt to get a certificate, including
+	// waiting for DNS and the CA
+	obtainTimeout = 15 * time.Minute
+
+	// dnsCommandTimeout bounds each run of the DNS command
+	dnsCommandTimeout = 2 * time.Minute
+)
+
+// dnsManager obtains certificates with the dns-01 challenge. It publishes
+// each challenge by running the configured command as
+//
+//	<dns_command> present <record name> <value>
+//	<dns_command> cleanup <record name> <value>
+//
+// so any DNS provider with a CLI or API can be scripted.
+type dnsManager struct {
+	config config.WebACMEConfig
+	logger *logger.Logger
+
+	mu   sync.RWMutex
+	cert *tls.Certificate // nil until one is loaded or obtained
+}
+
+// newDNSManager creates a dns-01 manager, loading a cached certificate
+func newDNSManager(cfg config.WebACMEConfig, logger *logger.Logger) (*dnsManager, error) {
+	if err := os.MkdirAll(cfg.CacheDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create certificate cache: %w", err)
+	}
+
+	d := &dnsManager{config: cfg, logger: logger}
+	if cert, err := loadCertificate(d.path("cert.pem"), d.path("key.pem")); err == nil {
+		d.cert = cert
+	} else if !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Ignoring unreadable cached certificate", "error", err)
+	}
+	return d, nil
+}
+
+// getCertificate serves the current certificate to TLS handshakes
+func (d *dnsManager) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.cert == nil {
+		return nil, fmt.Errorf("no certificate has been obtained yet")
+	}
+	return d.cert, nil
+}
+
+// start obtains a certificate unless a valid one is cached, then checks for
+// renewal in the background. A failed renewal is retried at the next check
+// while the current certificate is still served.
+func (d *dnsManager) start(ctx context.Context) error {
+	if d.needsRenewal() {
+		if err := d.obtain(ctx); err != nil {
+			if !d.hasCertificate() {
+				return err
+			}
+			d.logger.Error("Failed to renew TLS certificate, serving the current one", "error", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(renewCheck)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !d.needsRenewal() {
+					continue
+				}
+				if err := d.obtain(ctx); err != nil {
+					d.logger.Error("Failed to renew TLS certificate", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// hasCertificate reports whether a certificate is available to serve
+func (d *dnsManager) hasCertificate() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.cert != nil
+}
+
+// needsRenewal reports whether there is no certificate, it expires soon, or
+// it doesn't cover the configured domains
+func (d *dnsManager) needsRenewal() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.cert == nil {
+		return true
+	}
+	leaf := d.cert.Leaf
+	if time.Until(leaf.NotAfter) < renewBefore {
+		return true
+	}
+	for _, domain := range d.config.Domains {
+		if !slices.Contains(leaf.DNSNames, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// obtain orders a certificate for the configured domains, completing a
+// dns-01 challenge for each, and saves it to the cache
+func (d *dnsManager) obtain(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, obtainTimeout)
+	defer cancel()
+
+	d.logger.Info("Requesting TLS certificate", "domains", d.config.Domains, "challenge", "dns-01")
+
+	key, err := d.accountKey()
+	if err != nil {
+		return err
+	}
+	client := &acme.Client{Key: key, DirectoryURL: d.config.DirectoryURL}
+
+	account := &acme.Account{}
+	if d.config.Email != "" {
+		account.Contact = []string{"mailto:" + d.config.Email}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("failed to register ACME account: %w", err)
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(d.config.Domains...))
+	if err != nil {
+		return fmt.Errorf("failed to order certificate: %w", err)
+	}
+	for _, url := range order.AuthzURLs {
+		if err := d.authorize(ctx, client, url); err != nil {
+			return err
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("certificate order failed: %w", err)
+	}
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate certificate key: %w", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: d.config.Domains[0]},
+		DNSNames: d.config.Domains,
+	}, certKey)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("failed to finalize certificate: %w", err)
+	}
+
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(certKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode certificate key: %w", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if err := os.WriteFile(d.path("key.pem"), keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to save certificate key: %w", err)
+	}
+	if err := os.WriteFile(d.path("cert.pem"), certPEM, 0600); err != nil {
+		return fmt.Errorf("failed to save certificate: %w", err)
+	}
+
+	cert, err := parseCertificate(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.cert = cert
+	d.mu.Unlock()
+
+	d.logger.Info("Obtained TLS certificate", "domains", d.config.Domains, "expires", cert.Leaf.NotAfter.Format(time.RFC3339))
+	return nil
+}
+
+// authorize completes the dns-01 challenge of one authorization, unless the
+// CA already considers it valid
+func (d *dnsManager) authorize(ctx context.Context, client *acme.Client, url string) error {
+	authz, err := client.GetAuthorization(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed to get authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("the CA offered no dns-01 challenge for %s", authz.Identifier.Value)
+	}
+
+	value, err := client.DNS01ChallengeRecord(challenge.Token)
+	if err != nil {
+		return fmt.Errorf("failed to compute challenge record: %w", err)
+	}
+	// Wildcard names are validated on their base domain
+	name := "_acme-challenge." + strings.TrimPrefix(authz.Identifier.Value, "*.")
+
+	if err := d.runDNSCommand(ctx, "present", name, value); err != nil {
+		return err
+	}
+	defer func() {
+		if err := d.runDNSCommand(context.Background(), "cleanup", name, value); err != nil {
+			d.logger.Warn("Failed to remove ACME challenge record", "record", name, "error", err)
+		}
+	}()
+
+	d.logger.Debug("Certs", "Waiting for challenge record to propagate", "record", name, "seconds", d.config.DNSPropagation)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Duration(d.config.DNSPropagation) * time.Second):
+	}
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("failed to accept challenge for %s: %w", authz.Identifier.Value, err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("challenge for %s failed: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+// runDNSCommand runs the configured command to present or clean up a
+// challenge record
+func (d *dnsManager) runDNSCommand(ctx context.Context, action, name, value string) error {
+	ctx, cancel := context.WithTimeout(ctx, dnsCommandTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, d.config.DNSCommand, action, name, value).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("dns_command %s failed: %w: %s", action, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// accountKey loads the ACME account key, creating it on first use
+func (d *dnsManager) accountKey() (crypto.Signer, error) {
+	path := d.path("account.key")
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("invalid ACME account key in %s", path)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read ACME account key: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ACME account key: %w", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ACME account key: %w", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save ACME account key: %w", err)
+	}
+	return key, nil
+}
+
+// path returns a file in the certificate cache
+func (d *dnsManager) path(name string) string {
+	return filepath.Join(d.config.CacheDir, "dns-01-"+name)
+}
+
+// loadCertificate reads a certificate and key saved by obtain
+func loadCertificate(certFile, keyFile string) (*tls.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertificate(certPEM, keyPEM)
+}
+
+// parseCertificate parses a PEM certificate chain and key, keeping the
+// parsed leaf for expiry checks
+func parseCertificate(certPEM, keyPEM []byte) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+	}
+	return &cert, nil
+}
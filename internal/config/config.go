@@ -2,24 +2,82 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"Meiko/internal/filename"
 )
 
 // Config represents the main configuration structure
 type Config struct {
-	SDRTrunk      SDRTrunkConfig      `yaml:"sdrtrunk"`
-	Transcription TranscriptionConfig `yaml:"transcription"`
-	Discord       DiscordConfig       `yaml:"discord"`
-	Database      DatabaseConfig      `yaml:"database"`
-	Logging       LoggingConfig       `yaml:"logging"`
-	Monitoring    MonitoringConfig    `yaml:"monitoring"`
-	FileMonitor   FileMonitorConfig   `yaml:"file_monitor"`
-	Talkgroups    TalkgroupConfig     `yaml:"talkgroups"`
-	Preflight     PreflightConfig     `yaml:"preflight"`
-	Web           WebConfig           `yaml:"web"`
+	Mode           string               `yaml:"mode"` // standalone, agent or server
+	SDRTrunk       SDRTrunkConfig       `yaml:"sdrtrunk"`
+	Systems        []SystemConfig       `yaml:"systems"`
+	Transcription  TranscriptionConfig  `yaml:"transcription"`
+	Discord        DiscordConfig        `yaml:"discord"`
+	Telegram       TelegramConfig       `yaml:"telegram"`
+	Matrix         MatrixConfig         `yaml:"matrix"`
+	Database       DatabaseConfig       `yaml:"database"`
+	Logging        LoggingConfig        `yaml:"logging"`
+	Monitoring     MonitoringConfig     `yaml:"monitoring"`
+	FileMonitor    FileMonitorConfig    `yaml:"file_monitor"`
+	Talkgroups     TalkgroupConfig      `yaml:"talkgroups"`
+	Preflight      PreflightConfig      `yaml:"preflight"`
+	USBWatchdog    USBWatchdogConfig    `yaml:"usb_watchdog"`
+	Storage        StorageConfig        `yaml:"storage"`
+	Anomalies      AnomalyConfig        `yaml:"anomalies"`
+	AudioArchive   AudioArchiveConfig   `yaml:"audio_archive"`
+	Backup         BackupConfig         `yaml:"backup"`
+	Web            WebConfig            `yaml:"web"`
+	LLM            LLMConfig            `yaml:"llm"`
+	Corrections    CorrectionsConfig    `yaml:"corrections"`
+	Redaction      RedactionConfig      `yaml:"redaction"`
+	Severity       SeverityConfig       `yaml:"severity"`
+	Priority       PriorityConfig       `yaml:"priority"`
+	Dedup          DedupConfig          `yaml:"dedup"`
+	Enrichment     EnrichmentConfig     `yaml:"enrichment"`
+	Embeddings     EmbeddingsConfig     `yaml:"embeddings"`
+	Archive        ArchiveConfig        `yaml:"archive"`
+	Tones          TonesConfig          `yaml:"tones"`
+	VoiceDetection VoiceDetectionConfig `yaml:"voice_detection"`
+	Transcode      TranscodeConfig      `yaml:"transcode"`
+	Organize       OrganizeConfig       `yaml:"organize"`
+	Reconcile      ReconcileConfig      `yaml:"reconcile"`
+	FFmpeg         FFmpegConfig         `yaml:"ffmpeg"`
+	Email          EmailConfig          `yaml:"email"`
+	Push           PushConfig           `yaml:"push"`
+	Agent          AgentConfig          `yaml:"agent"`
+	Ingest         IngestConfig         `yaml:"ingest"`
+
+	ShutdownTimeout int `yaml:"shutdown_timeout"` // Seconds to finish queued calls when shutting down
+
+	// IgnoredEnv lists MEIKO_ environment variables that name no setting,
+	// such as the service links Kubernetes and Docker add, to be logged
+	IgnoredEnv []string `yaml:"-"`
+}
+
+// AgentConfig contains settings for forwarding recordings to a central server
+type AgentConfig struct {
+	ServerURL         string `yaml:"server_url"`          // Base URL of the Meiko server
+	APIKey            string `yaml:"api_key"`             // Server API key with the ingest scope
+	Site              string `yaml:"site"`                // Name of this receive site
+	MaxRetries        int    `yaml:"max_retries"`         // Upload attempts before giving up on a file
+	Timeout           int    `yaml:"timeout"`             // Upload timeout in seconds
+	DeleteAfterUpload bool   `yaml:"delete_after_upload"` // Remove recordings once the server has them
+	System            string `yaml:"system"`              // Server system ID to file this site's calls under
+}
+
+// IngestConfig contains settings for accepting recordings from agents
+type IngestConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Directory   string `yaml:"directory"`     // Where uploaded recordings are stored
+	MaxUploadMB int    `yaml:"max_upload_mb"` // Largest accepted recording
 }
 
 // SDRTrunkConfig contains SDRTrunk process management settings
@@ -31,6 +89,35 @@ type SDRTrunkConfig struct {
 	WorkingDir     string   `yaml:"working_dir"`
 	AudioOutputDir string   `yaml:"audio_output_dir"`
 	LogLevel       string   `yaml:"log_level"` // Level for SDRTrunk output: DEBUG, INFO, WARN, ERROR
+	External       bool     `yaml:"external"`  // SDRTrunk runs outside Meiko, such as in another container; only its recordings are watched
+
+	// Restart policy when the process exits unexpectedly
+	RestartPolicy string `yaml:"restart_policy"` // on-failure, always or never
+	MaxRestarts   int    `yaml:"max_restarts"`   // Consecutive restarts before giving up (-1 = unlimited)
+	RestartDelay  int    `yaml:"restart_delay"`  // Seconds before the first restart, doubled on each attempt
+
+	HealthCheck SDRTrunkHealthConfig `yaml:"health_check"`
+
+	// FilenameFormat is how recordings in audio_output_dir are named: auto,
+	// a built-in format or one of file_monitor.filename_formats
+	FilenameFormat string `yaml:"filename_format"`
+}
+
+// SDRTrunkHealthConfig detects an SDRTrunk process that is running but hung
+type SDRTrunkHealthConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	AudioTimeout int    `yaml:"audio_timeout"` // Minutes without a new recording before SDRTrunk is considered hung (0 = don't check)
+	LogTimeout   int    `yaml:"log_timeout"`   // Minutes without log output before SDRTrunk is considered hung (0 = don't check)
+	Action       string `yaml:"action"`        // restart or alert
+}
+
+// SystemConfig defines one monitored radio system with its own SDRTrunk
+// instance. Unset SDRTrunk fields are inherited from the top-level sdrtrunk section.
+type SystemConfig struct {
+	ID               string         `yaml:"id"`   // Short identifier stored with each call
+	Name             string         `yaml:"name"` // Display name
+	SDRTrunk         SDRTrunkConfig `yaml:"sdrtrunk"`
+	DiscordChannelID string         `yaml:"discord_channel_id"` // Post this system's calls here instead of discord.channel_id
 }
 
 // TranscriptionConfig contains transcription service settings
@@ -38,51 +125,187 @@ type TranscriptionConfig struct {
 	Mode            string                    `yaml:"mode"`
 	Local           LocalTranscriptionConfig  `yaml:"local"`
 	Remote          RemoteTranscriptionConfig `yaml:"remote"`
+	Diarization     DiarizationConfig         `yaml:"diarization"`
+	Translation     TranslationConfig         `yaml:"translation"`
 	MinDurationSecs int                       `yaml:"min_duration_seconds"`
 	MaxRetries      int                       `yaml:"max_retries"`
 	BatchSize       int                       `yaml:"batch_size"`
 }
 
+// DiarizationConfig contains speaker diarization settings
+type DiarizationConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	HFToken     string `yaml:"hf_token"`     // Hugging Face token for the pyannote pipeline (local mode)
+	MaxSpeakers int    `yaml:"max_speakers"` // Upper bound on speakers per call (0 = automatic)
+}
+
+// TranslationConfig contains settings for translating non-English
+// transcriptions to English with the LLM provider in llm
+type TranslationConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Model   string `yaml:"model"` // Overrides llm.model for translations
+}
+
 // LocalTranscriptionConfig contains local transcription settings
 type LocalTranscriptionConfig struct {
 	WhisperScript string `yaml:"whisper_script"`
 	PythonPath    string `yaml:"python_path"`
 	ModelSize     string `yaml:"model_size"`
-	Device        string `yaml:"device"`
+	Device        string `yaml:"device"`       // cpu, cuda or auto
+	DeviceIndex   int    `yaml:"device_index"` // GPU to use when there is more than one
 	Language      string `yaml:"language"`
+	ComputeType   string `yaml:"compute_type"`   // int8, int8_float16, float16, float32, ...
+	BeamSize      int    `yaml:"beam_size"`      // 1 is fastest; 5 is Whisper's default and most accurate
+	VADFilter     *bool  `yaml:"vad_filter"`     // Skip silence with voice activity detection (default true)
+	InitialPrompt string `yaml:"initial_prompt"` // Context given to Whisper ahead of the glossary terms
+
+	// Keep the model loaded in a long-lived worker instead of starting the script per call
+	Daemon              bool `yaml:"daemon"`
+	HealthCheckInterval int  `yaml:"health_check_interval"` // Seconds between worker health checks
+}
+
+// modelSizes are the models fasterWhisper.py accepts
+var modelSizes = []string{"tiny", "base", "small"}
+
+// computeTypes are the quantization types faster-whisper accepts
+var computeTypes = []string{"default", "auto", "int8", "int8_float32", "int8_float16", "int8_bfloat16",
+	"int16", "float16", "bfloat16", "float32"}
+
+// VADEnabled reports whether the voice activity detection filter is on
+func (l LocalTranscriptionConfig) VADEnabled() bool {
+	return l.VADFilter == nil || *l.VADFilter
+}
+
+// validate checks the device and decoding options
+func (l LocalTranscriptionConfig) validate() error {
+	if !slices.Contains(modelSizes, l.ModelSize) {
+		return fmt.Errorf("transcription.local.model_size must be one of: %s", strings.Join(modelSizes, ", "))
+	}
+	if l.Device != "cpu" && l.Device != "cuda" && l.Device != "auto" {
+		return fmt.Errorf("transcription.local.device must be 'cpu', 'cuda' or 'auto'")
+	}
+	if l.DeviceIndex < 0 {
+		return fmt.Errorf("transcription.local.device_index cannot be negative")
+	}
+	if !slices.Contains(computeTypes, l.ComputeType) {
+		return fmt.Errorf("transcription.local.compute_type must be one of: %s", strings.Join(computeTypes, ", "))
+	}
+	if l.BeamSize < 1 || l.BeamSize > 10 {
+		return fmt.Errorf("transcription.local.beam_size must be between 1 and 10")
+	}
+	return nil
 }
 
 // RemoteTranscriptionConfig contains remote transcription settings
 type RemoteTranscriptionConfig struct {
+	Provider   string `yaml:"provider"` // generic or deepgram
 	Endpoint   string `yaml:"endpoint"`
 	APIKey     string `yaml:"api_key"`
 	Timeout    int    `yaml:"timeout"`
 	MaxRetries int    `yaml:"max_retries"`
+	Language   string `yaml:"language"` // Language code sent to the service; empty or auto lets it detect the language
 }
 
 // DiscordConfig contains Discord integration settings
 type DiscordConfig struct {
 	Token         string                    `yaml:"token"`
 	ChannelID     string                    `yaml:"channel_id"`
-	WebhookURL    string                    `yaml:"webhook_url"`
+	WebhookURL    string                    `yaml:"webhook_url"` // Posts through a webhook when there is no token or channel_id
 	Notifications DiscordNotificationConfig `yaml:"notifications"`
 	Monitoring    DiscordMonitoringConfig   `yaml:"monitoring"`
+	Digest        DiscordDigestConfig       `yaml:"digest"`
+	Threads       DiscordThreadsConfig      `yaml:"threads"`
+}
+
+// Enabled reports whether Discord notifications are configured
+func (d DiscordConfig) Enabled() bool {
+	return d.Token != "" || d.WebhookURL != ""
+}
+
+// UseWebhook reports whether messages are posted through the webhook rather
+// than a bot, which needs both a token and a channel
+func (d DiscordConfig) UseWebhook() bool {
+	return d.WebhookURL != "" && (d.Token == "" || d.ChannelID == "")
 }
 
 // DiscordNotificationConfig defines which events to send to Discord
 type DiscordNotificationConfig struct {
-	Startup        bool `yaml:"startup"`
-	Shutdown       bool `yaml:"shutdown"`
-	Errors         bool `yaml:"errors"`
-	Transcriptions bool `yaml:"transcriptions"`
-	SystemHealth   bool `yaml:"system_health"`
+	Startup          bool `yaml:"startup"`
+	Shutdown         bool `yaml:"shutdown"`
+	Errors           bool `yaml:"errors"`
+	Transcriptions   bool `yaml:"transcriptions"`
+	SystemHealth     bool `yaml:"system_health"`
+	MinSeverity      int  `yaml:"min_severity"`      // Only post calls at or above this severity (0 = all)
+	EscalatePriority int  `yaml:"escalate_priority"` // Mention @here for calls at or above this priority (0 = never)
+
+	Talkgroups          []string                `yaml:"talkgroups"`            // Only post calls from these talkgroup IDs (empty = all)
+	ExcludeTalkgroups   []string                `yaml:"exclude_talkgroups"`    // Never post calls from these talkgroup IDs
+	ServiceTypes        []string                `yaml:"service_types"`         // Only post calls of these service types (empty = all)
+	ExcludeServiceTypes []string                `yaml:"exclude_service_types"` // Never post calls of these service types
+	QuietHours          DiscordQuietHoursConfig `yaml:"quiet_hours"`
+}
+
+// DiscordQuietHoursConfig holds back routine calls overnight. Escalated calls
+// and calls mentioning an emergency keyword are posted anyway.
+type DiscordQuietHoursConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	Start        string   `yaml:"start"`         // Local time (HH:MM) quiet hours begin
+	End          string   `yaml:"end"`           // Local time (HH:MM) quiet hours end, may be after midnight
+	Talkgroups   []string `yaml:"talkgroups"`    // Talkgroup IDs held back (empty with no service_types = all)
+	ServiceTypes []string `yaml:"service_types"` // Service types held back, such as PUBLIC_WORKS
+	MinSeverity  int      `yaml:"min_severity"`  // Calls at or above this severity are posted anyway (0 = off)
+	Keywords     []string `yaml:"keywords"`      // Case-insensitive text that gets a call posted anyway
 }
 
 // DiscordMonitoringConfig contains Discord monitoring settings
 type DiscordMonitoringConfig struct {
-	Enabled            bool `yaml:"enabled"`
-	UpdateChannelTopic bool `yaml:"update_channel_topic"`
-	UpdateInterval     int  `yaml:"update_interval"`
+	Enabled            bool `yaml:"enabled"`              // Post periodic health embeds
+	UpdateChannelTopic bool `yaml:"update_channel_topic"` // Keep a status summary in the channel topic
+	UpdateInterval     int  `yaml:"update_interval"`      // Seconds between updates
+}
+
+// DiscordDigestConfig contains settings for the daily recap post
+type DiscordDigestConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	RunAt        string `yaml:"run_at"`        // Local time (HH:MM) to post the previous day's recap
+	ChannelID    string `yaml:"channel_id"`    // Defaults to channel_id
+	Pin          bool   `yaml:"pin"`           // Pin the post, unpinning the previous recap
+	Thread       bool   `yaml:"thread"`        // List the top incidents in a thread on the post
+	TopAgencies  int    `yaml:"top_agencies"`  // Agencies listed with their call counts
+	TopIncidents int    `yaml:"top_incidents"` // Highest severity calls listed
+	MinSeverity  int    `yaml:"min_severity"`  // Calls at or above this severity can be listed
+}
+
+// DiscordThreadsConfig contains settings for incident threads. A significant
+// call opens a thread on its notification, and related calls that follow are
+// posted in the thread instead of the channel.
+type DiscordThreadsConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	MinPriority int  `yaml:"min_priority"` // Priority that opens a thread (defaults to priority.major_incident)
+	MinSeverity int  `yaml:"min_severity"` // Severity that opens a thread, such as a critical keyword hit (defaults to 5)
+	Window      int  `yaml:"window"`       // Minutes a thread takes related calls after its last one
+}
+
+// notificationTypes are the notifications Telegram and Matrix can be sent
+var notificationTypes = []string{"calls", "tones", "health", "startup", "shutdown"}
+
+// TelegramConfig contains settings for posting notifications with a Telegram bot
+type TelegramConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	BotToken      string   `yaml:"bot_token"`     // From @BotFather
+	ChatID        string   `yaml:"chat_id"`       // Chat ID, or @username of a public channel
+	Notifications []string `yaml:"notifications"` // calls, tones, health, startup and/or shutdown
+	MinSeverity   int      `yaml:"min_severity"`  // Only post calls at or above this severity (0 = all)
+}
+
+// MatrixConfig contains settings for posting notifications to a Matrix room
+type MatrixConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	Homeserver    string   `yaml:"homeserver"`    // e.g. https://matrix.org
+	AccessToken   string   `yaml:"access_token"`  // Token of the account that posts; it must have joined the room
+	RoomID        string   `yaml:"room_id"`       // e.g. !abcdef:matrix.org
+	Notifications []string `yaml:"notifications"` // calls, tones, health, startup and/or shutdown
+	MinSeverity   int      `yaml:"min_severity"`  // Only post calls at or above this severity (0 = all)
 }
 
 // DatabaseConfig contains database settings
@@ -90,6 +313,8 @@ type DatabaseConfig struct {
 	Path         string `yaml:"path"`
 	MaxOpenConns int    `yaml:"max_open_conns"`
 	MaxIdleConns int    `yaml:"max_idle_conns"`
+	JournalMode  string `yaml:"journal_mode"` // SQLite journal mode (WAL, DELETE, ...)
+	BusyTimeout  int    `yaml:"busy_timeout"` // Milliseconds to wait on a locked database
 }
 
 // LoggingConfig contains logging settings
@@ -104,8 +329,8 @@ type LoggingConfig struct {
 type FileLoggingConfig struct {
 	Enabled    bool   `yaml:"enabled"`
 	Path       string `yaml:"path"`
-	MaxSizeMB  int    `yaml:"max_size_mb"`
-	MaxBackups int    `yaml:"max_backups"`
+	MaxSizeMB  int    `yaml:"max_size_mb"` // Rotate once the file reaches this size
+	MaxBackups int    `yaml:"max_backups"` // Compressed rotated files to keep
 }
 
 // MonitoringConfig contains system monitoring settings
@@ -113,6 +338,14 @@ type MonitoringConfig struct {
 	Enabled       bool                      `yaml:"enabled"`
 	CheckInterval int                       `yaml:"check_interval"`
 	Thresholds    MonitoringThresholdConfig `yaml:"thresholds"`
+	Alerts        MonitoringAlertConfig     `yaml:"alerts"`
+}
+
+// MonitoringAlertConfig controls notifications when thresholds are crossed
+type MonitoringAlertConfig struct {
+	Hysteresis float64 `yaml:"hysteresis"`  // How far below its threshold a metric must fall to clear
+	Cooldown   int     `yaml:"cooldown"`    // Minutes before the same metric can alert again
+	WebhookURL string  `yaml:"webhook_url"` // Also POST alerts as JSON here
 }
 
 // MonitoringThresholdConfig contains monitoring thresholds
@@ -126,15 +359,89 @@ type MonitoringThresholdConfig struct {
 // FileMonitorConfig contains file monitoring settings
 type FileMonitorConfig struct {
 	PollInterval    int      `yaml:"poll_interval"`
+	Polling         bool     `yaml:"polling"` // List the directory every poll_interval instead of relying on filesystem events, for network and container volumes
 	Patterns        []string `yaml:"patterns"`
 	MinFileAge      int      `yaml:"min_file_age"`
 	MinCallDuration int      `yaml:"min_call_duration"`
+	Recursive       bool     `yaml:"recursive"` // Also watch subdirectories of each system's audio_output_dir
+
+	// QueueTimeout is how many seconds a watcher waits for room in the
+	// processing queue before putting a recording back to try again later.
+	// 0 tries again on the next check without waiting; unset waits 10.
+	QueueTimeout *int `yaml:"queue_timeout"`
+
+	// Directories are watched for recordings alongside each system's
+	// audio_output_dir, such as the output of another recorder
+	Directories []WatchDirectoryConfig `yaml:"directories"`
+
+	// TalkgroupOverrides customizes filtering per talkgroup ID
+	TalkgroupOverrides map[string]TalkgroupFilterConfig `yaml:"talkgroup_overrides"`
+
+	// FilenameFormats are custom recording filename formats, tried before the
+	// built-in ones and selectable by name with sdrtrunk.filename_format or a
+	// directory's filename_format
+	FilenameFormats []FilenameFormatConfig `yaml:"filename_formats"`
+}
+
+// QueueWait returns how long a watcher waits for room in the processing queue
+func (f FileMonitorConfig) QueueWait() time.Duration {
+	if f.QueueTimeout == nil {
+		return 10 * time.Second
+	}
+	return time.Duration(*f.QueueTimeout) * time.Second
+}
+
+// WatchDirectoryConfig is a directory watched for recordings. Unset settings
+// come from file_monitor, or from the system the directory is tagged with.
+type WatchDirectoryConfig struct {
+	Path            string   `yaml:"path"`
+	System          string   `yaml:"system"` // System ID calls from this directory are tagged with
+	Patterns        []string `yaml:"patterns"`
+	MinCallDuration int      `yaml:"min_call_duration"` // Seconds (0 = file_monitor.min_call_duration)
+	FilenameFormat  string   `yaml:"filename_format"`
+	Recursive       bool     `yaml:"recursive"` // Also watch subdirectories
+}
+
+// FilenameFormatConfig is a recording filename format: a regular expression
+// whose named groups capture the call details
+type FilenameFormatConfig struct {
+	Name            string `yaml:"name"`
+	Pattern         string `yaml:"pattern"`          // Named groups: date, time, timestamp, unix, system, site, channel, to, from, frequency
+	TimestampLayout string `yaml:"timestamp_layout"` // Go time layout of the timestamp group
+}
+
+// Template returns the format as a filename template
+func (f FilenameFormatConfig) Template() filename.Template {
+	return filename.Template{Name: f.Name, Pattern: f.Pattern, TimestampLayout: f.TimestampLayout}
+}
+
+// TalkgroupFilterConfig overrides call filtering for a single talkgroup
+type TalkgroupFilterConfig struct {
+	Mute            bool `yaml:"mute"`              // Ignore calls on this talkgroup entirely
+	MinCallDuration int  `yaml:"min_call_duration"` // Minimum call duration in seconds (0 = global default)
+	Priority        bool `yaml:"priority"`          // Always process, bypassing global filters
 }
 
 // TalkgroupConfig contains talkgroup-related settings
 type TalkgroupConfig struct {
-	PlaylistPath string                  `yaml:"playlist_path"`
-	Glossaries   TalkgroupGlossaryConfig `yaml:"glossaries"`
+	PlaylistPath   string                        `yaml:"playlist_path"`
+	Glossaries     TalkgroupGlossaryConfig       `yaml:"glossaries"`
+	Classification TalkgroupClassificationConfig `yaml:"classification"`
+}
+
+// TalkgroupClassificationConfig contains the rules that assign talkgroups to
+// a service type from their playlist group and name
+type TalkgroupClassificationConfig struct {
+	ReplaceDefaults bool                       `yaml:"replace_defaults"` // Drop the built-in keyword lists
+	Rules           []ClassificationRuleConfig `yaml:"rules"`
+}
+
+// ClassificationRuleConfig assigns matching talkgroups to a service type
+type ClassificationRuleConfig struct {
+	ServiceType string   `yaml:"service_type"` // POLICE, FIRE, EMS, EMERGENCY, PUBLIC_WORKS, EDUCATION, EVENTS, AIRPORT or OTHER
+	Keywords    []string `yaml:"keywords"`     // Case-insensitive text to find in the group or name
+	Regex       string   `yaml:"regex"`        // Matched against "group name"
+	Priority    int      `yaml:"priority"`     // Higher priorities are tried first; built-in keywords are 0
 }
 
 // TalkgroupGlossaryConfig contains glossary settings
@@ -143,14 +450,306 @@ type TalkgroupGlossaryConfig struct {
 	Specific map[string][]string `yaml:"specific"`
 }
 
+// CorrectionsConfig contains post-transcription correction settings
+type CorrectionsConfig struct {
+	Enabled bool                   `yaml:"enabled"`
+	Rules   []CorrectionRuleConfig `yaml:"rules"`
+}
+
+// CorrectionRuleConfig defines a single find/replace correction rule
+type CorrectionRuleConfig struct {
+	Find       string   `yaml:"find"`
+	Replace    string   `yaml:"replace"`
+	Regex      bool     `yaml:"regex"`      // Treat find as a regular expression
+	Talkgroups []string `yaml:"talkgroups"` // Limit to these talkgroup IDs (empty = all)
+}
+
+// RedactionConfig contains patterns for sensitive details hidden from
+// transcripts in API responses and notifications. The database keeps the
+// original text, which only admin API keys can read.
+type RedactionConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	Names           []string `yaml:"names"`            // Regular expressions replaced with [name]
+	Plates          []string `yaml:"plates"`           // Regular expressions replaced with [plate]
+	Medical         []string `yaml:"medical"`          // Regular expressions replaced with [medical]
+	ReplaceDefaults bool     `yaml:"replace_defaults"` // Use only these patterns, not the built-in ones
+}
+
+// SeverityConfig contains call severity scoring settings
+type SeverityConfig struct {
+	// Keywords maps a severity level name (minor, moderate, serious, critical)
+	// to extra keywords that raise a call to that level
+	Keywords map[string][]string `yaml:"keywords"`
+}
+
+// PriorityConfig contains call priority scoring settings. A call's priority
+// (0-100) adds up points for its talkgroup, service type, keyword hits and
+// duration.
+type PriorityConfig struct {
+	TalkgroupWeights   map[string]int `yaml:"talkgroup_weights"`    // Points by talkgroup ID (priority talkgroups default to 20)
+	ServiceTypeWeights map[string]int `yaml:"service_type_weights"` // Points by service type (POLICE, FIRE, EMS, ...)
+	KeywordWeight      int            `yaml:"keyword_weight"`       // Points per severity level of each keyword hit, up to 50
+	DurationWeight     int            `yaml:"duration_weight"`      // Points per 30 seconds of airtime, up to 20
+	MajorIncident      int            `yaml:"major_incident"`       // Priority at or above which a call is a major incident
+}
+
+// DedupConfig contains settings for linking simulcast duplicates, the same
+// transmission recorded on more than one frequency or site
+type DedupConfig struct {
+	Enabled           bool    `yaml:"enabled"`
+	Window            int     `yaml:"window"`             // Seconds between call start times to still be the same transmission
+	DurationTolerance int     `yaml:"duration_tolerance"` // Seconds the durations may differ by
+	MinSimilarity     float64 `yaml:"min_similarity"`     // Transcript word overlap (0-1) required when both calls have one
+}
+
+// EnrichmentConfig contains settings for extracting structured incident
+// details from each transcription with the LLM provider in llm
+type EnrichmentConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	Model         string   `yaml:"model"`          // Overrides llm.model for enrichment
+	IncidentTypes []string `yaml:"incident_types"` // Types the LLM chooses from; anything else is "other"
+	MinDuration   int      `yaml:"min_duration"`   // Skip calls shorter than this many seconds
+}
+
+// EmbeddingsConfig contains settings for semantic search, which stores an
+// embedding vector of each transcription
+type EmbeddingsConfig struct {
+	Enabled           bool    `yaml:"enabled"`
+	Provider          string  `yaml:"provider"` // gemini, openai or ollama; defaults to llm.provider
+	APIKey            string  `yaml:"api_key"`  // Defaults to llm.api_key for the same provider
+	Model             string  `yaml:"model"`
+	Endpoint          string  `yaml:"endpoint"`           // Defaults to llm.endpoint for the same provider
+	MinSimilarity     float64 `yaml:"min_similarity"`     // Cosine similarity (0-1) a search result needs
+	ClusterSimilarity float64 `yaml:"cluster_similarity"` // Cosine similarity (0-1) for calls to be clustered together
+}
+
+// embeddingProviders are the LLM providers with an embeddings API
+var embeddingProviders = []string{"gemini", "openai", "ollama"}
+
+// LLM returns the provider settings for the embedding model
+func (e EmbeddingsConfig) LLM(timeout int) LLMConfig {
+	return LLMConfig{Provider: e.Provider, APIKey: e.APIKey, Model: e.Model, Endpoint: e.Endpoint, Timeout: timeout}
+}
+
+// ArchiveConfig contains nightly report export settings
+type ArchiveConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Directory   string   `yaml:"directory"`    // Local output directory for reports
+	Formats     []string `yaml:"formats"`      // markdown and/or html
+	RunAt       string   `yaml:"run_at"`       // Local time (HH:MM) to export the previous day
+	BaseURL     string   `yaml:"base_url"`     // Public dashboard URL used for call links
+	MinSeverity int      `yaml:"min_severity"` // Calls at or above this severity are listed as notable
+	S3          S3Config `yaml:"s3"`
+}
+
+// S3Config contains S3 or S3-compatible bucket settings
+type S3Config struct {
+	Bucket    string `yaml:"bucket"`
+	Region    string `yaml:"region"`
+	Endpoint  string `yaml:"endpoint"` // Custom endpoint for S3-compatible storage
+	Prefix    string `yaml:"prefix"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// EmailConfig contains SMTP and scheduled digest settings
+type EmailConfig struct {
+	Enabled bool           `yaml:"enabled"`
+	BaseURL string         `yaml:"base_url"` // Public dashboard URL used for call links
+	SMTP    SMTPConfig     `yaml:"smtp"`
+	Digests []DigestConfig `yaml:"digests"`
+}
+
+// SMTPConfig contains outgoing mail server settings
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+	TLS      string `yaml:"tls"` // starttls, tls (implicit, usually port 465) or none
+}
+
+// DigestConfig defines a scheduled email digest
+type DigestConfig struct {
+	Name          string                  `yaml:"name"`
+	Frequency     string                  `yaml:"frequency"`      // daily or weekly
+	RunAt         string                  `yaml:"run_at"`         // Local time (HH:MM) to send
+	Weekday       string                  `yaml:"weekday"`        // Day to send weekly digests
+	Subject       string                  `yaml:"subject"`        // Go template for the subject line
+	Template      string                  `yaml:"template"`       // Optional HTML template file for the body
+	TopTalkgroups int                     `yaml:"top_talkgroups"` // Number of talkgroups to list
+	Keywords      []string                `yaml:"keywords"`       // Flagged keywords to report hits for
+	Recipients    []DigestRecipientConfig `yaml:"recipients"`
+}
+
+// DigestRecipientConfig is a digest recipient with optional filters
+type DigestRecipientConfig struct {
+	Address     string   `yaml:"address"`
+	Talkgroups  []string `yaml:"talkgroups"`   // Only include these talkgroups (empty = all)
+	Keywords    []string `yaml:"keywords"`     // Replace the digest keywords for this recipient
+	MinSeverity int      `yaml:"min_severity"` // Only list keyword hits at or above this severity
+}
+
+// PushConfig contains mobile push alert settings. Each call is checked against
+// the rules in order, and the first that matches sends an alert.
+type PushConfig struct {
+	Enabled  bool             `yaml:"enabled"`
+	BaseURL  string           `yaml:"base_url"` // Public dashboard URL used to link the call audio
+	Cooldown int              `yaml:"cooldown"` // Minutes before a rule alerts again for the same talkgroup
+	Pushover PushoverConfig   `yaml:"pushover"`
+	Ntfy     NtfyConfig       `yaml:"ntfy"`
+	Rules    []PushRuleConfig `yaml:"rules"`
+}
+
+// PushoverConfig contains Pushover credentials
+type PushoverConfig struct {
+	AppToken string `yaml:"app_token"` // Application API token
+	UserKey  string `yaml:"user_key"`  // User or group key to notify
+}
+
+// NtfyConfig contains ntfy settings
+type NtfyConfig struct {
+	Server string `yaml:"server"` // Defaults to https://ntfy.sh
+	Topic  string `yaml:"topic"`
+	Token  string `yaml:"token"` // Access token for protected topics
+}
+
+// PushRuleConfig defines which calls send a push alert. All the conditions
+// given must match; a call matches keywords if it contains any of them.
+type PushRuleConfig struct {
+	Name         string   `yaml:"name"`
+	Keywords     []string `yaml:"keywords"`      // Case-insensitive text in the transcription or translation
+	Talkgroups   []string `yaml:"talkgroups"`    // Talkgroup IDs (empty = all)
+	ServiceTypes []string `yaml:"service_types"` // Service types (empty = all)
+	MinSeverity  int      `yaml:"min_severity"`
+	MinPriority  int      `yaml:"min_priority"`
+	Priority     int      `yaml:"priority"` // Pushover priority from -2 (silent) to 2 (emergency, repeats until acknowledged)
+	Sound        string   `yaml:"sound"`    // Pushover sound, such as siren or persistent
+	Tags         []string `yaml:"tags"`     // ntfy tags; emoji shortcodes such as fire are shown as icons
+}
+
+// TranscodeConfig converts recordings to Opus once they have been transcribed
+type TranscodeConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Bitrate int  `yaml:"bitrate"` // Opus bitrate in kbps
+}
+
+// OrganizeConfig files processed recordings into a dated archive directory,
+// keeping the directories SDRTrunk writes to small and quick to list
+type OrganizeConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Directory string `yaml:"directory"` // Recordings are filed under YYYY/MM/DD/<talkgroup>/
+	Mode      string `yaml:"mode"`      // move, or hardlink to leave the original in place
+}
+
+// ReconcileConfig periodically compares the database with the recordings on
+// disk, finding calls whose audio was deleted and recordings no call refers to
+type ReconcileConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	Interval int  `yaml:"interval"` // Hours between checks
+}
+
+// FFmpegConfig locates the ffmpeg and ffprobe executables used to measure,
+// decode and transcode recordings
+type FFmpegConfig struct {
+	Path        string `yaml:"path"`         // ffmpeg executable
+	FFprobePath string `yaml:"ffprobe_path"` // ffprobe executable
+}
+
+// TonesConfig contains paging tone detection settings
+type TonesConfig struct {
+	Enabled    bool                `yaml:"enabled"`
+	Talkgroups []string            `yaml:"talkgroups"` // Only analyze these talkgroups (empty = all)
+	MinToneA   float64             `yaml:"min_tone_a"` // Minimum A tone length in seconds
+	MinToneB   float64             `yaml:"min_tone_b"` // Minimum B tone length in seconds
+	Tolerance  float64             `yaml:"tolerance"`  // Station match tolerance in percent
+	Stations   []ToneStationConfig `yaml:"stations"`
+}
+
+// VoiceDetectionConfig contains settings for recognising encrypted audio and
+// data bursts so they are not transcribed
+type VoiceDetectionConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	MaxFlatness   float64 `yaml:"max_flatness"`   // Spectral flatness (0-1) at or above which audio is noise-like
+	MinModulation float64 `yaml:"min_modulation"` // Level variation in dB below which a short recording is a steady data burst
+}
+
+// ToneStationConfig maps a two-tone pair to a station
+type ToneStationConfig struct {
+	Name  string  `yaml:"name"`
+	ToneA float64 `yaml:"tone_a"`
+	ToneB float64 `yaml:"tone_b"`
+	Alert bool    `yaml:"alert"` // Post a Discord alert when these tones drop
+}
+
 // PreflightConfig contains pre-flight check settings
 type PreflightConfig struct {
 	Enabled         bool    `yaml:"enabled"`
 	CheckUSBDevices bool    `yaml:"check_usb_devices"`
 	MinDiskSpaceGB  float64 `yaml:"min_disk_space_gb"`
-	CheckNetwork    bool    `yaml:"check_network"`
+	CheckNetwork    bool    `yaml:"check_network"`   // Reach the transcription, Discord and LLM APIs and check the clock
+	NetworkTimeout  int     `yaml:"network_timeout"` // Seconds to wait for each service
+	NTPServer       string  `yaml:"ntp_server"`      // Server the clock is compared against
+	MaxClockSkew    int     `yaml:"max_clock_skew"`  // Seconds the clock may be off before startup fails
+}
+
+// USBWatchdogConfig watches for SDR receivers dropping off the USB bus
+type USBWatchdogConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	Interval        int      `yaml:"interval"`         // Seconds between USB scans
+	Devices         []string `yaml:"devices"`          // vendor:product IDs to watch; empty watches known SDR receivers
+	RestartSDRTrunk bool     `yaml:"restart_sdrtrunk"` // Restart SDRTrunk once missing devices return
+	SettleTime      int      `yaml:"settle_time"`      // Seconds to wait after devices return before restarting
+}
+
+// StorageConfig controls recording storage forecasting and emergency cleanup
+type StorageConfig struct {
+	Enabled           bool    `yaml:"enabled"`
+	CheckInterval     int     `yaml:"check_interval"`      // Minutes between storage samples
+	ForecastWindow    int     `yaml:"forecast_window"`     // Hours of samples used to measure growth
+	AlertDays         float64 `yaml:"alert_days"`          // Alert when the disk is projected to fill within this many days
+	MinFreeGB         float64 `yaml:"min_free_gb"`         // Free space floor that triggers emergency cleanup
+	EmergencyCleanup  bool    `yaml:"emergency_cleanup"`   // Delete the oldest recordings when below the floor
+	CleanupHeadroomGB float64 `yaml:"cleanup_headroom_gb"` // Space to free beyond the floor
+}
+
+// AnomalyConfig controls detection of unusual call volume on a talkgroup,
+// measured against what is normal for that talkgroup at that hour of the day
+type AnomalyConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	CheckInterval int     `yaml:"check_interval"` // Minutes between checks of the last hour's calls
+	BaselineDays  int     `yaml:"baseline_days"`  // Days of history the normal volume is learned from
+	Threshold     float64 `yaml:"threshold"`      // Flag a talkgroup at this many times its normal volume
+	MinCalls      int     `yaml:"min_calls"`      // Calls in the last hour needed before a talkgroup is flagged
+	Cooldown      int     `yaml:"cooldown"`       // Minutes before the same talkgroup is flagged again
+	Discord       bool    `yaml:"discord"`        // Also post anomalies to Discord
+}
+
+// AudioArchiveConfig moves call audio to object storage once calls are processed
+type AudioArchiveConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	S3            S3Config `yaml:"s3"`              // S3, Backblaze B2 or another S3-compatible bucket
+	Interval      int      `yaml:"interval"`        // Minutes between upload passes
+	DeleteLocal   string   `yaml:"delete_local"`    // never, after_upload or after_days
+	KeepLocalDays int      `yaml:"keep_local_days"` // Days to keep local copies when delete_local is after_days
+	URLExpiry     int      `yaml:"url_expiry"`      // Minutes presigned audio links stay valid
+}
+
+// BackupConfig contains database backup settings, used by scheduled backups
+// and by `meiko db backup`
+type BackupConfig struct {
+	Enabled    bool     `yaml:"enabled"`    // Take backups on a schedule
+	Directory  string   `yaml:"directory"`  // Where backups are written
+	Interval   int      `yaml:"interval"`   // Hours between scheduled backups
+	Keep       int      `yaml:"keep"`       // Backups kept in the directory; older ones are removed
+	Passphrase string   `yaml:"passphrase"` // Encrypts backups when set
+	S3         S3Config `yaml:"s3"`         // Also upload backups to this bucket when bucket is set
 }
 
+// usbDeviceID matches a USB vendor:product ID, or a vendor ID and colon
+var usbDeviceID = regexp.MustCompile(`^[0-9a-fA-F]{4}:([0-9a-fA-F]{4})?$`)
+
 // WebConfig contains web dashboard settings
 type WebConfig struct {
 	Enabled  bool              `yaml:"enabled"`
@@ -158,15 +757,83 @@ type WebConfig struct {
 	Host     string            `yaml:"host"`
 	TLS      WebTLSConfig      `yaml:"tls"`
 	Auth     WebAuthConfig     `yaml:"auth"`
+	APIKeys  WebAPIKeysConfig  `yaml:"api_keys"`
 	Gemini   WebGeminiConfig   `yaml:"gemini"`
 	Realtime WebRealtimeConfig `yaml:"realtime"`
+	History  WebHistoryConfig  `yaml:"history"`
+	Feeds    WebFeedsConfig    `yaml:"feeds"`
+	Public   WebPublicConfig   `yaml:"public_mode"`
+
+	RateLimit      WebRateLimitConfig   `yaml:"rate_limit"`
+	Compression    WebCompressionConfig `yaml:"compression"`
+	LegacyAPI      WebLegacyAPIConfig   `yaml:"legacy_api"`
+	CORS           WebCORSConfig        `yaml:"cors"`
+	RequestLogging bool                 `yaml:"request_logging"` // Log every API request
+	ProxyHeader    string               `yaml:"proxy_header"`    // Client IP header set by a reverse proxy, e.g. X-Forwarded-For
+	TrustedProxies []string             `yaml:"trusted_proxies"` // Proxy addresses or CIDR ranges whose proxy_header is believed
+	BasePath       string               `yaml:"base_path"`       // URL path the dashboard is served under behind a proxy, e.g. /meiko
+	StaticDir      string               `yaml:"static_dir"`      // Serve the dashboard from this directory instead of the embedded copy, for development
+}
+
+// WebCORSConfig controls which other sites' pages may call the API from a
+// browser
+type WebCORSConfig struct {
+	AllowOrigins     []string             `yaml:"allow_origins"`     // e.g. https://dash.example.com, or * for any; default * without API keys, none with them
+	AllowCredentials bool                 `yaml:"allow_credentials"` // Let browsers send cookies and HTTP auth cross-origin
+	MaxAge           int                  `yaml:"max_age"`           // Seconds browsers may cache a preflight response
+	Routes           []WebCORSRouteConfig `yaml:"routes"`            // Policies for paths under a prefix, overriding the above
+}
+
+// WebCORSRouteConfig is the CORS policy of the paths under a prefix
+type WebCORSRouteConfig struct {
+	Path             string   `yaml:"path"` // e.g. /api/v1/feeds
+	AllowOrigins     []string `yaml:"allow_origins"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+}
+
+// WebLegacyAPIConfig controls the unversioned /api paths kept for clients
+// written before /api/v1
+type WebLegacyAPIConfig struct {
+	Enabled *bool  `yaml:"enabled"` // Default true
+	Sunset  string `yaml:"sunset"`  // YYYY-MM-DD the unversioned paths will be removed, announced in the Sunset header
+}
+
+// IsEnabled reports whether the unversioned /api paths are served
+func (w WebLegacyAPIConfig) IsEnabled() bool {
+	return w.Enabled == nil || *w.Enabled
+}
+
+// WebCompressionConfig controls gzip, deflate and Brotli compression of responses
+type WebCompressionConfig struct {
+	Enabled *bool  `yaml:"enabled"` // Default true
+	Level   string `yaml:"level"`   // speed, default or best
+}
+
+// IsEnabled reports whether responses are compressed
+func (w WebCompressionConfig) IsEnabled() bool {
+	return w.Enabled == nil || *w.Enabled
 }
 
 // WebTLSConfig contains TLS settings
 type WebTLSConfig struct {
-	Enabled  bool   `yaml:"enabled"`
-	CertFile string `yaml:"cert_file"`
-	KeyFile  string `yaml:"key_file"`
+	Enabled  bool          `yaml:"enabled"`
+	CertFile string        `yaml:"cert_file"`
+	KeyFile  string        `yaml:"key_file"`
+	ACME     WebACMEConfig `yaml:"acme"`
+}
+
+// WebACMEConfig obtains and renews certificates automatically from Let's
+// Encrypt or another ACME certificate authority, in place of cert_file and key_file
+type WebACMEConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	Domains        []string `yaml:"domains"`         // Names the certificate covers
+	Email          string   `yaml:"email"`           // Contact for expiry notices from the CA
+	Challenge      string   `yaml:"challenge"`       // http-01 or dns-01
+	HTTPPort       int      `yaml:"http_port"`       // Port answering http-01 challenges and redirecting to HTTPS
+	DNSCommand     string   `yaml:"dns_command"`     // Sets and removes the TXT record for dns-01
+	DNSPropagation int      `yaml:"dns_propagation"` // Seconds to wait for a new TXT record to be visible
+	CacheDir       string   `yaml:"cache_dir"`       // Where the account key and certificates are kept
+	DirectoryURL   string   `yaml:"directory_url"`   // ACME directory; Let's Encrypt by default
 }
 
 // WebAuthConfig contains authentication settings
@@ -176,7 +843,57 @@ type WebAuthConfig struct {
 	Password string `yaml:"password"`
 }
 
-// WebGeminiConfig contains Google Gemini integration settings
+// WebAPIKeysConfig contains API key enforcement settings
+type WebAPIKeysConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	PublicScopes []string `yaml:"public_scopes"` // Scopes granted to requests without a key
+}
+
+// WebRateLimitConfig contains API rate limiting settings. Limits are requests
+// per minute for each client, identified by API key or else by IP address.
+type WebRateLimitConfig struct {
+	Enabled              bool `yaml:"enabled"`
+	RequestsPerMinute    int  `yaml:"requests_per_minute"`     // Per IP without an API key
+	KeyRequestsPerMinute int  `yaml:"key_requests_per_minute"` // Per API key
+	AIRequestsPerMinute  int  `yaml:"ai_requests_per_minute"`  // Endpoints that call the LLM
+}
+
+// LLMConfig selects the language model used for AI summaries and translation
+type LLMConfig struct {
+	Provider string `yaml:"provider"` // gemini, openai, anthropic or ollama; empty disables AI features
+	APIKey   string `yaml:"api_key"`
+	Model    string `yaml:"model"`
+	Endpoint string `yaml:"endpoint"` // Base URL, for OpenAI-compatible servers or a remote Ollama
+	Timeout  int    `yaml:"timeout"`  // Seconds per request
+}
+
+// llmProviders are the supported LLM providers
+var llmProviders = []string{"gemini", "openai", "anthropic", "ollama"}
+
+// Enabled reports whether an LLM provider is configured
+func (l LLMConfig) Enabled() bool {
+	return l.Provider != ""
+}
+
+// validate checks the provider and its credentials
+func (l LLMConfig) validate() error {
+	if !l.Enabled() {
+		return nil
+	}
+	if !slices.Contains(llmProviders, l.Provider) {
+		return fmt.Errorf("llm.provider must be one of: %s", strings.Join(llmProviders, ", "))
+	}
+	if l.APIKey == "" && (l.Provider == "gemini" || l.Provider == "anthropic") {
+		return fmt.Errorf("llm.api_key is required for the %s provider", l.Provider)
+	}
+	if l.Timeout < 1 {
+		return fmt.Errorf("llm.timeout must be at least 1 second")
+	}
+	return nil
+}
+
+// WebGeminiConfig contains Google Gemini integration settings. It is kept
+// for older configs and is used as the gemini provider when llm is not set.
 type WebGeminiConfig struct {
 	Enabled bool   `yaml:"enabled"`
 	APIKey  string `yaml:"api_key"`
@@ -189,16 +906,49 @@ type WebRealtimeConfig struct {
 	UpdateInterval int  `yaml:"update_interval"`
 }
 
-// Load reads and parses the configuration file
+// WebHistoryConfig contains settings for pre-rendering past days to static files
+type WebHistoryConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Directory string `yaml:"directory"` // Where rendered days are written
+}
+
+// WebFeedsConfig contains settings for the public Atom and iCal feeds of
+// major incidents and daily summaries, which need no API key
+type WebFeedsConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Title       string `yaml:"title"`        // Feed and calendar name
+	BaseURL     string `yaml:"base_url"`     // Public dashboard URL the feeds link to
+	Days        int    `yaml:"days"`         // How many days back the feeds go
+	MinPriority int    `yaml:"min_priority"` // Priority of calls listed as incidents (defaults to priority.major_incident)
+}
+
+// WebPublicConfig contains settings for public mode, a read-only dashboard
+// that can be shared: calls appear after a delay, unit identifiers and names
+// are redacted from transcripts, and admin and debug endpoints are disabled
+type WebPublicConfig struct {
+	Enabled         bool                   `yaml:"enabled"`
+	Delay           int                    `yaml:"delay"`            // Minutes before a call is shown
+	Redactions      []CorrectionRuleConfig `yaml:"redactions"`       // Extra rules applied to transcripts
+	ReplaceDefaults bool                   `yaml:"replace_defaults"` // Use only redactions, not the built-in unit and name rules
+}
+
+// Load reads and parses the configuration file. Settings in MEIKO_ environment
+// variables override the file, and without a file at path Meiko can be
+// configured with environment variables alone.
 func Load(path string) (*Config, error) {
+	var config Config
 	data, err := os.ReadFile(path)
-	if err != nil {
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	case !os.IsNotExist(err) || !hasEnvConfig():
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	if err := config.applyEnv(); err != nil {
+		return nil, err
 	}
 
 	// Set defaults
@@ -214,6 +964,33 @@ func Load(path string) (*Config, error) {
 
 // setDefaults sets default values for configuration fields
 func (c *Config) setDefaults() {
+	// Mode defaults
+	if c.Mode == "" {
+		c.Mode = "standalone"
+	}
+	if c.Mode == "server" {
+		c.Ingest.Enabled = true
+	}
+	if c.ShutdownTimeout == 0 {
+		c.ShutdownTimeout = 30
+	}
+
+	// Agent defaults
+	if c.Agent.MaxRetries == 0 {
+		c.Agent.MaxRetries = 10
+	}
+	if c.Agent.Timeout == 0 {
+		c.Agent.Timeout = 120
+	}
+
+	// Ingest defaults
+	if c.Ingest.Directory == "" {
+		c.Ingest.Directory = "./ingest"
+	}
+	if c.Ingest.MaxUploadMB == 0 {
+		c.Ingest.MaxUploadMB = 50
+	}
+
 	// SDRTrunk defaults
 	if c.SDRTrunk.JavaPath == "" {
 		c.SDRTrunk.JavaPath = "java"
@@ -221,6 +998,67 @@ func (c *Config) setDefaults() {
 	if c.SDRTrunk.LogLevel == "" {
 		c.SDRTrunk.LogLevel = "INFO" // Default to INFO level for SDRTrunk output
 	}
+	if c.SDRTrunk.RestartPolicy == "" {
+		c.SDRTrunk.RestartPolicy = "on-failure"
+	}
+	if c.SDRTrunk.MaxRestarts == 0 {
+		c.SDRTrunk.MaxRestarts = 5
+	}
+	if c.SDRTrunk.RestartDelay == 0 {
+		c.SDRTrunk.RestartDelay = 10
+	}
+	if c.SDRTrunk.HealthCheck.Enabled && c.SDRTrunk.HealthCheck.AudioTimeout == 0 && c.SDRTrunk.HealthCheck.LogTimeout == 0 {
+		c.SDRTrunk.HealthCheck.AudioTimeout = 30
+	}
+	if c.SDRTrunk.HealthCheck.Action == "" {
+		c.SDRTrunk.HealthCheck.Action = "restart"
+	}
+	if c.SDRTrunk.FilenameFormat == "" {
+		c.SDRTrunk.FilenameFormat = filename.Auto
+	}
+
+	// System defaults: inherit shared SDRTrunk settings
+	for i := range c.Systems {
+		system := &c.Systems[i]
+		if system.Name == "" {
+			system.Name = system.ID
+		}
+		if system.SDRTrunk.Path == "" {
+			system.SDRTrunk.Path = c.SDRTrunk.Path
+		}
+		if system.SDRTrunk.JavaPath == "" {
+			system.SDRTrunk.JavaPath = c.SDRTrunk.JavaPath
+		}
+		if system.SDRTrunk.JVMArgs == nil {
+			system.SDRTrunk.JVMArgs = c.SDRTrunk.JVMArgs
+		}
+		if system.SDRTrunk.Args == nil {
+			system.SDRTrunk.Args = c.SDRTrunk.Args
+		}
+		if system.SDRTrunk.LogLevel == "" {
+			system.SDRTrunk.LogLevel = c.SDRTrunk.LogLevel
+		}
+		if system.SDRTrunk.FilenameFormat == "" {
+			system.SDRTrunk.FilenameFormat = c.SDRTrunk.FilenameFormat
+		}
+		if system.SDRTrunk.RestartPolicy == "" {
+			system.SDRTrunk.RestartPolicy = c.SDRTrunk.RestartPolicy
+		}
+		if system.SDRTrunk.MaxRestarts == 0 {
+			system.SDRTrunk.MaxRestarts = c.SDRTrunk.MaxRestarts
+		}
+		if system.SDRTrunk.RestartDelay == 0 {
+			system.SDRTrunk.RestartDelay = c.SDRTrunk.RestartDelay
+		}
+		if system.SDRTrunk.HealthCheck == (SDRTrunkHealthConfig{}) {
+			system.SDRTrunk.HealthCheck = c.SDRTrunk.HealthCheck
+		} else if system.SDRTrunk.HealthCheck.Action == "" {
+			system.SDRTrunk.HealthCheck.Action = c.SDRTrunk.HealthCheck.Action
+		}
+		if health := &system.SDRTrunk.HealthCheck; health.Enabled && health.AudioTimeout == 0 && health.LogTimeout == 0 {
+			health.AudioTimeout = 30
+		}
+	}
 
 	// Transcription defaults
 	if c.Transcription.Mode == "" {
@@ -249,8 +1087,23 @@ func (c *Config) setDefaults() {
 	if c.Transcription.Local.Language == "" {
 		c.Transcription.Local.Language = "en"
 	}
+	if c.Transcription.Local.ComputeType == "" {
+		c.Transcription.Local.ComputeType = "int8"
+	}
+	if c.Transcription.Local.BeamSize == 0 {
+		c.Transcription.Local.BeamSize = 1
+	}
+	if c.Transcription.Local.HealthCheckInterval == 0 {
+		c.Transcription.Local.HealthCheckInterval = 30
+	}
 
 	// Remote transcription defaults
+	if c.Transcription.Remote.Provider == "" {
+		c.Transcription.Remote.Provider = "generic"
+	}
+	if c.Transcription.Remote.Provider == "deepgram" && c.Transcription.Remote.Endpoint == "" {
+		c.Transcription.Remote.Endpoint = "https://api.deepgram.com/v1/listen"
+	}
 	if c.Transcription.Remote.Timeout == 0 {
 		c.Transcription.Remote.Timeout = 30
 	}
@@ -268,11 +1121,70 @@ func (c *Config) setDefaults() {
 	if c.Database.MaxIdleConns == 0 {
 		c.Database.MaxIdleConns = 5
 	}
+	if c.Database.JournalMode == "" {
+		c.Database.JournalMode = "WAL"
+	}
+	if c.Database.BusyTimeout == 0 {
+		c.Database.BusyTimeout = 5000
+	}
 
 	// Logging defaults
 	if c.Logging.Level == "" {
 		c.Logging.Level = "INFO"
 	}
+	if c.Logging.FileLogging.Path == "" {
+		c.Logging.FileLogging.Path = "logs/meiko.log"
+	}
+	if c.Logging.FileLogging.MaxSizeMB == 0 {
+		c.Logging.FileLogging.MaxSizeMB = 100
+	}
+	if c.Logging.FileLogging.MaxBackups == 0 {
+		c.Logging.FileLogging.MaxBackups = 5
+	}
+
+	// Discord health report defaults
+	if c.Discord.Monitoring.UpdateInterval == 0 {
+		c.Discord.Monitoring.UpdateInterval = 900 // 15 minutes
+	}
+	if c.Discord.Notifications.QuietHours.Start == "" {
+		c.Discord.Notifications.QuietHours.Start = "22:00"
+	}
+	if c.Discord.Notifications.QuietHours.End == "" {
+		c.Discord.Notifications.QuietHours.End = "06:00"
+	}
+	if c.Discord.Notifications.QuietHours.Keywords == nil {
+		c.Discord.Notifications.QuietHours.Keywords = []string{
+			"mayday", "shots fired", "officer down", "structure fire", "working fire",
+			"cardiac arrest", "not breathing", "entrapment",
+		}
+	}
+	if c.Discord.Threads.MinSeverity == 0 {
+		c.Discord.Threads.MinSeverity = 5
+	}
+	if c.Discord.Threads.Window == 0 {
+		c.Discord.Threads.Window = 30
+	}
+	if c.Telegram.Notifications == nil {
+		c.Telegram.Notifications = []string{"calls", "tones", "health"}
+	}
+	if c.Matrix.Notifications == nil {
+		c.Matrix.Notifications = []string{"calls", "tones", "health"}
+	}
+	if c.Discord.Digest.RunAt == "" {
+		c.Discord.Digest.RunAt = "07:00"
+	}
+	if c.Discord.Digest.ChannelID == "" {
+		c.Discord.Digest.ChannelID = c.Discord.ChannelID
+	}
+	if c.Discord.Digest.TopAgencies == 0 {
+		c.Discord.Digest.TopAgencies = 10
+	}
+	if c.Discord.Digest.TopIncidents == 0 {
+		c.Discord.Digest.TopIncidents = 5
+	}
+	if c.Discord.Digest.MinSeverity == 0 {
+		c.Discord.Digest.MinSeverity = 3
+	}
 
 	// Monitoring defaults
 	if c.Monitoring.CheckInterval == 0 {
@@ -290,6 +1202,12 @@ func (c *Config) setDefaults() {
 	if c.Monitoring.Thresholds.Temperature == 0 {
 		c.Monitoring.Thresholds.Temperature = 70.0
 	}
+	if c.Monitoring.Alerts.Hysteresis == 0 {
+		c.Monitoring.Alerts.Hysteresis = 10.0
+	}
+	if c.Monitoring.Alerts.Cooldown == 0 {
+		c.Monitoring.Alerts.Cooldown = 30
+	}
 
 	// File monitor defaults
 	if c.FileMonitor.PollInterval == 0 {
@@ -304,52 +1222,543 @@ func (c *Config) setDefaults() {
 	if c.FileMonitor.MinCallDuration == 0 {
 		c.FileMonitor.MinCallDuration = 3
 	}
+	for i := range c.FileMonitor.Directories {
+		directory := &c.FileMonitor.Directories[i]
+		if len(directory.Patterns) == 0 {
+			directory.Patterns = c.FileMonitor.Patterns
+		}
+		if directory.FilenameFormat == "" {
+			directory.FilenameFormat = filename.Auto
+			if system, ok := c.GetSystem(directory.System); ok {
+				directory.FilenameFormat = system.SDRTrunk.FilenameFormat
+			}
+		}
+	}
 
 	// Preflight defaults
 	if c.Preflight.MinDiskSpaceGB == 0 {
 		c.Preflight.MinDiskSpaceGB = 1.0
 	}
-
-	// Web defaults
-	if c.Web.Port == 0 {
-		c.Web.Port = 8080
-	}
-	if c.Web.Host == "" {
-		c.Web.Host = "0.0.0.0"
+	if c.Preflight.NetworkTimeout == 0 {
+		c.Preflight.NetworkTimeout = 10
 	}
-	if c.Web.Gemini.Model == "" {
-		c.Web.Gemini.Model = "gemini-1.5-flash"
+	if c.Preflight.NTPServer == "" {
+		c.Preflight.NTPServer = "pool.ntp.org"
 	}
-	if c.Web.Realtime.UpdateInterval == 0 {
-		c.Web.Realtime.UpdateInterval = 1000
+	if c.Preflight.MaxClockSkew == 0 {
+		c.Preflight.MaxClockSkew = 30
 	}
-}
 
-// validate checks the configuration for required fields and logical consistency
-func (c *Config) validate() error {
-	// Validate SDRTrunk configuration
-	if c.SDRTrunk.Path == "" {
-		return fmt.Errorf("sdrtrunk.path is required")
+	// FFmpeg defaults, found on the PATH
+	if c.FFmpeg.Path == "" {
+		c.FFmpeg.Path = "ffmpeg"
 	}
-	if c.SDRTrunk.AudioOutputDir == "" {
-		return fmt.Errorf("sdrtrunk.audio_output_dir is required")
+	if c.FFmpeg.FFprobePath == "" {
+		c.FFmpeg.FFprobePath = "ffprobe"
 	}
 
-	// Validate transcription mode
-	if c.Transcription.Mode != "local" && c.Transcription.Mode != "remote" {
-		return fmt.Errorf("transcription.mode must be 'local' or 'remote'")
+	// Storage defaults
+	if c.Storage.CheckInterval == 0 {
+		c.Storage.CheckInterval = 15
 	}
-
+	if c.Storage.ForecastWindow == 0 {
+		c.Storage.ForecastWindow = 24
+	}
+	if c.Storage.AlertDays == 0 {
+		c.Storage.AlertDays = 7
+	}
+	if c.Storage.MinFreeGB == 0 {
+		c.Storage.MinFreeGB = c.Preflight.MinDiskSpaceGB
+	}
+	if c.Storage.CleanupHeadroomGB == 0 {
+		c.Storage.CleanupHeadroomGB = 1
+	}
+
+	// Anomaly detection defaults
+	if c.Anomalies.CheckInterval == 0 {
+		c.Anomalies.CheckInterval = 15
+	}
+	if c.Anomalies.BaselineDays == 0 {
+		c.Anomalies.BaselineDays = 28
+	}
+	if c.Anomalies.Threshold == 0 {
+		c.Anomalies.Threshold = 3
+	}
+	if c.Anomalies.MinCalls == 0 {
+		c.Anomalies.MinCalls = 5
+	}
+	if c.Anomalies.Cooldown == 0 {
+		c.Anomalies.Cooldown = 60
+	}
+
+	// USB watchdog defaults
+	if c.USBWatchdog.Interval == 0 {
+		c.USBWatchdog.Interval = 10
+	}
+	if c.USBWatchdog.SettleTime == 0 {
+		c.USBWatchdog.SettleTime = 5
+	}
+
+	// Web defaults
+	if c.Web.Port == 0 {
+		c.Web.Port = 8080
+	}
+	if c.Web.Host == "" {
+		c.Web.Host = "0.0.0.0"
+	}
+	if c.Web.Gemini.Model == "" {
+		c.Web.Gemini.Model = "gemini-1.5-flash"
+	}
+
+	// LLM defaults; an older web.gemini section selects Gemini
+	if c.LLM.Provider == "" && c.Web.Gemini.Enabled && c.Web.Gemini.APIKey != "" {
+		c.LLM.Provider = "gemini"
+		c.LLM.APIKey = c.Web.Gemini.APIKey
+		if c.LLM.Model == "" {
+			c.LLM.Model = c.Web.Gemini.Model
+		}
+	}
+	if c.LLM.Model == "" {
+		switch c.LLM.Provider {
+		case "gemini":
+			c.LLM.Model = "gemini-1.5-flash"
+		case "openai":
+			c.LLM.Model = "gpt-4o-mini"
+		case "anthropic":
+			c.LLM.Model = "claude-3-5-haiku-latest"
+		case "ollama":
+			c.LLM.Model = "llama3.1"
+		}
+	}
+	if c.LLM.Endpoint == "" {
+		switch c.LLM.Provider {
+		case "openai":
+			c.LLM.Endpoint = "https://api.openai.com/v1"
+		case "anthropic":
+			c.LLM.Endpoint = "https://api.anthropic.com"
+		case "ollama":
+			c.LLM.Endpoint = "http://localhost:11434"
+		}
+	}
+	if c.LLM.Timeout == 0 {
+		c.LLM.Timeout = 60
+	}
+
+	// Embeddings defaults to the LLM provider and its credentials
+	if c.Embeddings.Provider == "" && slices.Contains(embeddingProviders, c.LLM.Provider) {
+		c.Embeddings.Provider = c.LLM.Provider
+	}
+	if c.Embeddings.Provider == c.LLM.Provider {
+		if c.Embeddings.APIKey == "" {
+			c.Embeddings.APIKey = c.LLM.APIKey
+		}
+		if c.Embeddings.Endpoint == "" {
+			c.Embeddings.Endpoint = c.LLM.Endpoint
+		}
+	}
+	if c.Embeddings.Model == "" {
+		switch c.Embeddings.Provider {
+		case "gemini":
+			c.Embeddings.Model = "text-embedding-004"
+		case "openai":
+			c.Embeddings.Model = "text-embedding-3-small"
+		case "ollama":
+			c.Embeddings.Model = "nomic-embed-text"
+		}
+	}
+	if c.Embeddings.Endpoint == "" {
+		switch c.Embeddings.Provider {
+		case "openai":
+			c.Embeddings.Endpoint = "https://api.openai.com/v1"
+		case "ollama":
+			c.Embeddings.Endpoint = "http://localhost:11434"
+		}
+	}
+	if c.Embeddings.MinSimilarity == 0 {
+		c.Embeddings.MinSimilarity = 0.5
+	}
+	if c.Embeddings.ClusterSimilarity == 0 {
+		c.Embeddings.ClusterSimilarity = 0.8
+	}
+	if c.Web.Realtime.UpdateInterval == 0 {
+		c.Web.Realtime.UpdateInterval = 1000
+	}
+	if c.Web.History.Directory == "" {
+		c.Web.History.Directory = "./data/history"
+	}
+	if c.Web.Feeds.Title == "" {
+		c.Web.Feeds.Title = "Meiko Scanner"
+	}
+	if c.Web.Feeds.Days == 0 {
+		c.Web.Feeds.Days = 14
+	}
+	if c.Web.Public.Delay == 0 {
+		c.Web.Public.Delay = 15
+	}
+	if c.Web.RateLimit.RequestsPerMinute == 0 {
+		c.Web.RateLimit.RequestsPerMinute = 120
+	}
+	if c.Web.RateLimit.KeyRequestsPerMinute == 0 {
+		c.Web.RateLimit.KeyRequestsPerMinute = 600
+	}
+	if c.Web.RateLimit.AIRequestsPerMinute == 0 {
+		c.Web.RateLimit.AIRequestsPerMinute = 5
+	}
+	if c.Web.Compression.Level == "" {
+		c.Web.Compression.Level = "default"
+	}
+	if c.Web.TLS.ACME.Challenge == "" {
+		c.Web.TLS.ACME.Challenge = "http-01"
+	}
+	if c.Web.TLS.ACME.HTTPPort == 0 {
+		c.Web.TLS.ACME.HTTPPort = 80
+	}
+	if c.Web.TLS.ACME.DNSPropagation == 0 {
+		c.Web.TLS.ACME.DNSPropagation = 60
+	}
+	if c.Web.TLS.ACME.CacheDir == "" {
+		c.Web.TLS.ACME.CacheDir = "./data/certs"
+	}
+	if c.Web.TLS.ACME.DirectoryURL == "" {
+		c.Web.TLS.ACME.DirectoryURL = "https://acme-v02.api.letsencrypt.org/directory"
+	}
+	if c.Web.BasePath != "" {
+		c.Web.BasePath = strings.TrimRight("/"+strings.Trim(c.Web.BasePath, "/"), "/")
+	}
+	if len(c.Web.TrustedProxies) > 0 && c.Web.ProxyHeader == "" {
+		c.Web.ProxyHeader = "X-Forwarded-For"
+	}
+	if c.Web.CORS.AllowOrigins == nil {
+		// Authenticated APIs are only open to other sites when listed
+		if c.Web.APIKeys.Enabled {
+			c.Web.CORS.AllowOrigins = []string{}
+		} else {
+			c.Web.CORS.AllowOrigins = []string{"*"}
+		}
+	}
+	if c.Web.APIKeys.PublicScopes == nil {
+		// Keep the dashboard readable without a key unless explicitly locked down
+		c.Web.APIKeys.PublicScopes = []string{"read-calls", "read-stats"}
+	}
+
+	// Tone detection defaults
+	if c.Tones.MinToneA == 0 {
+		c.Tones.MinToneA = 0.6
+	}
+	if c.Tones.MinToneB == 0 {
+		c.Tones.MinToneB = 1.5
+	}
+	if c.Tones.Tolerance == 0 {
+		c.Tones.Tolerance = 2.0
+	}
+
+	// Voice detection defaults
+	if c.VoiceDetection.MaxFlatness == 0 {
+		c.VoiceDetection.MaxFlatness = 0.45
+	}
+	if c.VoiceDetection.MinModulation == 0 {
+		c.VoiceDetection.MinModulation = 3
+	}
+
+	// Audio archive defaults
+	if c.AudioArchive.S3.Region == "" {
+		c.AudioArchive.S3.Region = "us-east-1"
+	}
+	if c.AudioArchive.Interval == 0 {
+		c.AudioArchive.Interval = 5
+	}
+	if c.AudioArchive.DeleteLocal == "" {
+		c.AudioArchive.DeleteLocal = "never"
+	}
+	if c.AudioArchive.KeepLocalDays == 0 {
+		c.AudioArchive.KeepLocalDays = 7
+	}
+	if c.AudioArchive.URLExpiry == 0 {
+		c.AudioArchive.URLExpiry = 60
+	}
+	if c.Backup.Directory == "" {
+		c.Backup.Directory = "./data/backups"
+	}
+	if c.Backup.Interval == 0 {
+		c.Backup.Interval = 24
+	}
+	if c.Backup.Keep == 0 {
+		c.Backup.Keep = 7
+	}
+
+	// Transcode defaults
+	if c.Transcode.Bitrate == 0 {
+		c.Transcode.Bitrate = 16
+	}
+
+	// Organize defaults
+	if c.Organize.Mode == "" {
+		c.Organize.Mode = "move"
+	}
+
+	// Reconcile defaults
+	if c.Reconcile.Interval == 0 {
+		c.Reconcile.Interval = 24
+	}
+
+	// Priority defaults
+	if c.Priority.ServiceTypeWeights == nil {
+		c.Priority.ServiceTypeWeights = map[string]int{
+			"EMERGENCY": 20,
+			"FIRE":      15,
+			"EMS":       15,
+			"POLICE":    10,
+		}
+	}
+	if c.Priority.KeywordWeight == 0 {
+		c.Priority.KeywordWeight = 4
+	}
+	if c.Priority.DurationWeight == 0 {
+		c.Priority.DurationWeight = 2
+	}
+	if c.Priority.MajorIncident == 0 {
+		c.Priority.MajorIncident = 60
+	}
+	if c.Discord.Threads.MinPriority == 0 {
+		c.Discord.Threads.MinPriority = c.Priority.MajorIncident
+	}
+	if c.Web.Feeds.MinPriority == 0 {
+		c.Web.Feeds.MinPriority = c.Priority.MajorIncident
+	}
+
+	// Dedup defaults
+	if c.Dedup.Window == 0 {
+		c.Dedup.Window = 3
+	}
+	if c.Dedup.DurationTolerance == 0 {
+		c.Dedup.DurationTolerance = 2
+	}
+	if c.Dedup.MinSimilarity == 0 {
+		c.Dedup.MinSimilarity = 0.6
+	}
+
+	// Enrichment defaults
+	if len(c.Enrichment.IncidentTypes) == 0 {
+		c.Enrichment.IncidentTypes = []string{"fire", "medical", "traffic", "crime", "hazmat", "rescue",
+			"weather", "utility", "administrative", "other"}
+	}
+
+	// Archive defaults
+	if c.Archive.Directory == "" {
+		c.Archive.Directory = "./archive"
+	}
+	if len(c.Archive.Formats) == 0 {
+		c.Archive.Formats = []string{"markdown", "html"}
+	}
+	if c.Archive.RunAt == "" {
+		c.Archive.RunAt = "00:15"
+	}
+	if c.Archive.MinSeverity == 0 {
+		c.Archive.MinSeverity = 3
+	}
+	if c.Archive.S3.Region == "" {
+		c.Archive.S3.Region = "us-east-1"
+	}
+
+	// Email defaults
+	if c.Email.SMTP.Port == 0 {
+		c.Email.SMTP.Port = 587
+	}
+	if c.Email.SMTP.TLS == "" {
+		c.Email.SMTP.TLS = "starttls"
+	}
+	for i := range c.Email.Digests {
+		digest := &c.Email.Digests[i]
+		if digest.Frequency == "" {
+			digest.Frequency = "daily"
+		}
+		if digest.RunAt == "" {
+			digest.RunAt = "07:00"
+		}
+		if digest.Weekday == "" {
+			digest.Weekday = "monday"
+		}
+		if digest.Subject == "" {
+			digest.Subject = "Meiko {{.Frequency}} digest - {{.Start.Format \"Jan 2, 2006\"}}"
+		}
+		if digest.TopTalkgroups == 0 {
+			digest.TopTalkgroups = 10
+		}
+	}
+
+	// Push defaults
+	if c.Push.Cooldown == 0 {
+		c.Push.Cooldown = 5
+	}
+	if c.Push.Ntfy.Server == "" {
+		c.Push.Ntfy.Server = "https://ntfy.sh"
+	}
+}
+
+// validate checks the configuration for required fields and logical consistency
+func (c *Config) validate() error {
+	// Validate mode
+	if c.Mode != "standalone" && c.Mode != "agent" && c.Mode != "server" {
+		return fmt.Errorf("mode must be 'standalone', 'agent' or 'server'")
+	}
+	if c.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdown_timeout must not be negative")
+	}
+
+	// Validate SDRTrunk configuration
+	if c.Captures() && len(c.Systems) == 0 {
+		if c.SDRTrunk.Path == "" && !c.SDRTrunk.External {
+			return fmt.Errorf("sdrtrunk.path is required")
+		}
+		if c.SDRTrunk.AudioOutputDir == "" {
+			return fmt.Errorf("sdrtrunk.audio_output_dir is required")
+		}
+	}
+
+	if c.FileMonitor.QueueTimeout != nil && *c.FileMonitor.QueueTimeout < 0 {
+		return fmt.Errorf("file_monitor.queue_timeout cannot be negative")
+	}
+
+	// Validate custom filename formats
+	formatNames := make(map[string]bool)
+	for _, name := range filename.BuiltinNames() {
+		formatNames[name] = true
+	}
+	formatNames[filename.Auto] = true
+	for i, format := range c.FileMonitor.FilenameFormats {
+		if format.Name == "" {
+			return fmt.Errorf("file_monitor.filename_formats[%d].name is required", i)
+		}
+		if formatNames[format.Name] {
+			return fmt.Errorf("file_monitor.filename_formats[%d].name '%s' is already used", i, format.Name)
+		}
+		formatNames[format.Name] = true
+		if _, err := filename.Compile(format.Template()); err != nil {
+			return fmt.Errorf("file_monitor.filename_formats[%d]: %w", i, err)
+		}
+	}
+
+	// Validate SDRTrunk restart policies
+	for i, system := range c.CaptureSystems() {
+		switch system.SDRTrunk.RestartPolicy {
+		case "on-failure", "always", "never":
+		default:
+			if len(c.Systems) == 0 {
+				return fmt.Errorf("sdrtrunk.restart_policy must be 'on-failure', 'always' or 'never'")
+			}
+			return fmt.Errorf("systems[%d].sdrtrunk.restart_policy must be 'on-failure', 'always' or 'never'", i)
+		}
+		if system.SDRTrunk.MaxRestarts < -1 || system.SDRTrunk.RestartDelay < 0 {
+			return fmt.Errorf("sdrtrunk.max_restarts must be -1 or more and sdrtrunk.restart_delay cannot be negative")
+		}
+		health := system.SDRTrunk.HealthCheck
+		if health.AudioTimeout < 0 || health.LogTimeout < 0 {
+			return fmt.Errorf("sdrtrunk.health_check timeouts cannot be negative")
+		}
+		if health.Action != "restart" && health.Action != "alert" {
+			return fmt.Errorf("sdrtrunk.health_check.action must be 'restart' or 'alert'")
+		}
+		if _, err := filename.NewParser(system.SDRTrunk.FilenameFormat, c.FilenameTemplates()); err != nil {
+			if len(c.Systems) == 0 {
+				return fmt.Errorf("sdrtrunk.filename_format: %w", err)
+			}
+			return fmt.Errorf("systems[%d].sdrtrunk.filename_format: %w", i, err)
+		}
+	}
+
+	// Validate systems
+	systemIDs := make(map[string]bool)
+	outputDirs := make(map[string]bool)
+	for i, system := range c.Systems {
+		if system.ID == "" {
+			return fmt.Errorf("systems[%d].id is required", i)
+		}
+		if systemIDs[system.ID] {
+			return fmt.Errorf("systems[%d].id '%s' is used more than once", i, system.ID)
+		}
+		systemIDs[system.ID] = true
+
+		if !c.Captures() {
+			continue
+		}
+		if system.SDRTrunk.Path == "" && !c.SDRTrunk.External {
+			return fmt.Errorf("systems[%d].sdrtrunk.path is required (or set sdrtrunk.path)", i)
+		}
+		if system.SDRTrunk.AudioOutputDir == "" {
+			return fmt.Errorf("systems[%d].sdrtrunk.audio_output_dir is required", i)
+		}
+		if outputDirs[system.SDRTrunk.AudioOutputDir] {
+			return fmt.Errorf("systems[%d].sdrtrunk.audio_output_dir is shared with another system", i)
+		}
+		outputDirs[system.SDRTrunk.AudioOutputDir] = true
+	}
+
+	// Validate additional watched directories
+	if c.Captures() && len(c.Systems) == 0 {
+		outputDirs[c.SDRTrunk.AudioOutputDir] = true
+	}
+	for i, directory := range c.FileMonitor.Directories {
+		if directory.Path == "" {
+			return fmt.Errorf("file_monitor.directories[%d].path is required", i)
+		}
+		if outputDirs[directory.Path] {
+			return fmt.Errorf("file_monitor.directories[%d].path is already watched", i)
+		}
+		outputDirs[directory.Path] = true
+		if directory.MinCallDuration < 0 {
+			return fmt.Errorf("file_monitor.directories[%d].min_call_duration cannot be negative", i)
+		}
+		if _, err := filename.NewParser(directory.FilenameFormat, c.FilenameTemplates()); err != nil {
+			return fmt.Errorf("file_monitor.directories[%d].filename_format: %w", i, err)
+		}
+	}
+
+	// Validate agent configuration
+	if c.Mode == "agent" {
+		if c.Agent.ServerURL == "" || c.Agent.APIKey == "" || c.Agent.Site == "" {
+			return fmt.Errorf("agent.server_url, agent.api_key and agent.site are required in agent mode")
+		}
+	}
+
+	// Validate ingest configuration
+	if c.Ingest.Enabled {
+		if !c.Web.Enabled || !c.Web.APIKeys.Enabled {
+			return fmt.Errorf("ingest requires web.enabled and web.api_keys.enabled")
+		}
+		for _, scope := range c.Web.APIKeys.PublicScopes {
+			if scope == "ingest" || scope == "admin" {
+				return fmt.Errorf("web.api_keys.public_scopes cannot include '%s' when ingest is enabled", scope)
+			}
+		}
+	}
+
+	// Validate transcription mode
+	if !c.Processes() {
+		return c.validateCapturePaths()
+	}
+	if c.Transcription.Mode != "local" && c.Transcription.Mode != "remote" {
+		return fmt.Errorf("transcription.mode must be 'local' or 'remote'")
+	}
+
 	// Validate transcription configuration based on mode
 	if c.Transcription.Mode == "local" {
 		if c.Transcription.Local.WhisperScript == "" {
 			return fmt.Errorf("transcription.local.whisper_script is required for local mode")
 		}
+		if err := c.Transcription.Local.validate(); err != nil {
+			return err
+		}
 	} else if c.Transcription.Mode == "remote" {
+		if c.Transcription.Remote.Provider != "generic" && c.Transcription.Remote.Provider != "deepgram" {
+			return fmt.Errorf("transcription.remote.provider must be 'generic' or 'deepgram'")
+		}
 		if c.Transcription.Remote.Endpoint == "" {
 			return fmt.Errorf("transcription.remote.endpoint is required for remote mode")
 		}
 	}
+	if c.Transcription.Translation.Enabled && !c.LLM.Enabled() {
+		return fmt.Errorf("llm.provider is required when transcription.translation is enabled")
+	}
 
 	// Validate Discord configuration (if enabled)
 	if c.Discord.Token != "" {
@@ -357,20 +1766,594 @@ func (c *Config) validate() error {
 			return fmt.Errorf("discord.channel_id or discord.webhook_url is required when Discord is enabled")
 		}
 	}
+	if c.Discord.WebhookURL != "" && !strings.Contains(c.Discord.WebhookURL, "/api/webhooks/") {
+		return fmt.Errorf("discord.webhook_url must be a Discord webhook URL (https://discord.com/api/webhooks/...)")
+	}
+	if c.Discord.Monitoring.UpdateInterval < 60 {
+		return fmt.Errorf("discord.monitoring.update_interval must be at least 60 seconds")
+	}
+	if c.Discord.Notifications.MinSeverity < 0 || c.Discord.Notifications.MinSeverity > 5 {
+		return fmt.Errorf("discord.notifications.min_severity must be between 0 and 5")
+	}
+	if quiet := c.Discord.Notifications.QuietHours; quiet.Enabled {
+		if _, err := time.Parse("15:04", quiet.Start); err != nil {
+			return fmt.Errorf("discord.notifications.quiet_hours.start must be in HH:MM format")
+		}
+		if _, err := time.Parse("15:04", quiet.End); err != nil {
+			return fmt.Errorf("discord.notifications.quiet_hours.end must be in HH:MM format")
+		}
+		if quiet.Start == quiet.End {
+			return fmt.Errorf("discord.notifications.quiet_hours.start and end must differ")
+		}
+		if quiet.MinSeverity < 0 || quiet.MinSeverity > 5 {
+			return fmt.Errorf("discord.notifications.quiet_hours.min_severity must be between 0 and 5")
+		}
+	}
+	if c.Discord.Threads.Enabled {
+		if c.Discord.Threads.MinPriority < 1 || c.Discord.Threads.MinPriority > 100 {
+			return fmt.Errorf("discord.threads.min_priority must be between 1 and 100")
+		}
+		if c.Discord.Threads.MinSeverity < 0 || c.Discord.Threads.MinSeverity > 5 {
+			return fmt.Errorf("discord.threads.min_severity must be between 0 and 5")
+		}
+		if c.Discord.Threads.Window < 1 {
+			return fmt.Errorf("discord.threads.window must be at least 1 minute")
+		}
+	}
+	if c.Telegram.Enabled {
+		if c.Telegram.BotToken == "" || c.Telegram.ChatID == "" {
+			return fmt.Errorf("telegram.bot_token and telegram.chat_id are required when Telegram is enabled")
+		}
+		if err := validateNotificationTypes("telegram", c.Telegram.Notifications, c.Telegram.MinSeverity); err != nil {
+			return err
+		}
+	}
+	if c.Matrix.Enabled {
+		if !strings.HasPrefix(c.Matrix.Homeserver, "http://") && !strings.HasPrefix(c.Matrix.Homeserver, "https://") {
+			return fmt.Errorf("matrix.homeserver must be an http:// or https:// URL")
+		}
+		if c.Matrix.AccessToken == "" || !strings.HasPrefix(c.Matrix.RoomID, "!") {
+			return fmt.Errorf("matrix.access_token and matrix.room_id (!id:server) are required when Matrix is enabled")
+		}
+		if err := validateNotificationTypes("matrix", c.Matrix.Notifications, c.Matrix.MinSeverity); err != nil {
+			return err
+		}
+	}
+	if c.Discord.Digest.Enabled {
+		if _, err := time.Parse("15:04", c.Discord.Digest.RunAt); err != nil {
+			return fmt.Errorf("discord.digest.run_at must be in HH:MM format")
+		}
+		if c.Discord.Digest.ChannelID == "" && !c.Discord.UseWebhook() {
+			return fmt.Errorf("discord.digest.channel_id or discord.channel_id is required for the digest")
+		}
+		if c.Discord.Digest.TopAgencies < 1 || c.Discord.Digest.TopAgencies > 25 {
+			return fmt.Errorf("discord.digest.top_agencies must be between 1 and 25")
+		}
+		if c.Discord.Digest.TopIncidents < 1 || c.Discord.Digest.TopIncidents > 25 {
+			return fmt.Errorf("discord.digest.top_incidents must be between 1 and 25")
+		}
+		if c.Discord.Digest.MinSeverity < 0 || c.Discord.Digest.MinSeverity > 5 {
+			return fmt.Errorf("discord.digest.min_severity must be between 0 and 5")
+		}
+	}
+
+	// Validate log rotation
+	if c.Logging.FileLogging.MaxSizeMB < 0 || c.Logging.FileLogging.MaxBackups < 0 {
+		return fmt.Errorf("logging.file_logging.max_size_mb and max_backups cannot be negative")
+	}
+
+	// Validate monitoring alerts
+	if c.Monitoring.Alerts.Hysteresis < 0 || c.Monitoring.Alerts.Cooldown < 0 {
+		return fmt.Errorf("monitoring.alerts.hysteresis and monitoring.alerts.cooldown cannot be negative")
+	}
+
+	// Validate storage
+	if c.Storage.Enabled {
+		if c.Storage.CheckInterval < 1 || c.Storage.ForecastWindow < 1 {
+			return fmt.Errorf("storage.check_interval and storage.forecast_window must be at least 1")
+		}
+		if c.Storage.AlertDays < 0 || c.Storage.MinFreeGB < 0 || c.Storage.CleanupHeadroomGB < 0 {
+			return fmt.Errorf("storage.alert_days, storage.min_free_gb and storage.cleanup_headroom_gb cannot be negative")
+		}
+	}
+
+	// Validate audio archive
+	if c.AudioArchive.Enabled {
+		if c.AudioArchive.S3.Bucket == "" || c.AudioArchive.S3.AccessKey == "" || c.AudioArchive.S3.SecretKey == "" {
+			return fmt.Errorf("audio_archive.s3 requires bucket, access_key and secret_key")
+		}
+		switch c.AudioArchive.DeleteLocal {
+		case "never", "after_upload", "after_days":
+		default:
+			return fmt.Errorf("audio_archive.delete_local must be 'never', 'after_upload' or 'after_days'")
+		}
+		if c.AudioArchive.Interval < 1 || c.AudioArchive.KeepLocalDays < 1 {
+			return fmt.Errorf("audio_archive.interval and audio_archive.keep_local_days must be at least 1")
+		}
+		// Signature V4 presigned URLs are valid for at most seven days
+		if c.AudioArchive.URLExpiry < 1 || c.AudioArchive.URLExpiry > 7*24*60 {
+			return fmt.Errorf("audio_archive.url_expiry must be between 1 and 10080 minutes")
+		}
+	}
+
+	// Validate backups
+	if c.Backup.Interval < 1 || c.Backup.Keep < 1 {
+		return fmt.Errorf("backup.interval and backup.keep must be at least 1")
+	}
+	if c.Backup.S3.Bucket != "" && (c.Backup.S3.AccessKey == "" || c.Backup.S3.SecretKey == "") {
+		return fmt.Errorf("backup.s3 requires access_key and secret_key")
+	}
+
+	// Validate pre-flight checks
+	if c.Preflight.NetworkTimeout < 0 || c.Preflight.MaxClockSkew < 0 {
+		return fmt.Errorf("preflight.network_timeout and preflight.max_clock_skew cannot be negative")
+	}
+
+	// Validate USB watchdog
+	if c.USBWatchdog.Enabled {
+		if c.USBWatchdog.Interval < 0 || c.USBWatchdog.SettleTime < 0 {
+			return fmt.Errorf("usb_watchdog.interval and usb_watchdog.settle_time cannot be negative")
+		}
+		for _, id := range c.USBWatchdog.Devices {
+			if !usbDeviceID.MatchString(id) {
+				return fmt.Errorf("usb_watchdog.devices entry %q must be vendor:product in hex (e.g. 0bda:2838) or vendor: for any product", id)
+			}
+		}
+	}
+
+	// Validate rate limits
+	if c.Web.RateLimit.RequestsPerMinute < 0 || c.Web.RateLimit.KeyRequestsPerMinute < 0 || c.Web.RateLimit.AIRequestsPerMinute < 0 {
+		return fmt.Errorf("web.rate_limit values cannot be negative")
+	}
+
+	// Validate response compression
+	switch c.Web.Compression.Level {
+	case "speed", "default", "best":
+	default:
+		return fmt.Errorf("web.compression.level must be 'speed', 'default' or 'best'")
+	}
+
+	// Validate automatic certificates
+	if acme := c.Web.TLS.ACME; acme.Enabled {
+		if len(acme.Domains) == 0 {
+			return fmt.Errorf("web.tls.acme.domains is required when ACME is enabled")
+		}
+		switch acme.Challenge {
+		case "http-01":
+			if acme.HTTPPort < 1 || acme.HTTPPort > 65535 || acme.HTTPPort == c.Web.Port {
+				return fmt.Errorf("web.tls.acme.http_port must be a port other than web.port")
+			}
+			for _, domain := range acme.Domains {
+				if strings.HasPrefix(domain, "*.") {
+					return fmt.Errorf("web.tls.acme wildcard domain %q needs the dns-01 challenge", domain)
+				}
+			}
+		case "dns-01":
+			if acme.DNSCommand == "" {
+				return fmt.Errorf("web.tls.acme.dns_command is required for the dns-01 challenge")
+			}
+			if acme.DNSPropagation < 0 {
+				return fmt.Errorf("web.tls.acme.dns_propagation cannot be negative")
+			}
+		default:
+			return fmt.Errorf("web.tls.acme.challenge must be 'http-01' or 'dns-01'")
+		}
+	}
+
+	// Validate the dashboard override directory
+	if c.Web.StaticDir != "" {
+		if info, err := os.Stat(c.Web.StaticDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("web.static_dir %s is not a directory", c.Web.StaticDir)
+		}
+	}
+
+	// Validate reverse proxy settings
+	if strings.ContainsAny(c.Web.BasePath, "?#* ") {
+		return fmt.Errorf("web.base_path must be a plain URL path, e.g. /meiko")
+	}
+	for _, reserved := range []string{"/api", "/ws", "/static"} {
+		if c.Web.BasePath == reserved || strings.HasPrefix(c.Web.BasePath, reserved+"/") {
+			return fmt.Errorf("web.base_path cannot be under %s", reserved)
+		}
+	}
+	for _, proxy := range c.Web.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("web.trusted_proxies entry %q must be an IP address or CIDR range", proxy)
+			}
+		}
+	}
+
+	// Validate CORS policies
+	if err := validateCORSOrigins("web.cors", c.Web.CORS.AllowOrigins, c.Web.CORS.AllowCredentials); err != nil {
+		return err
+	}
+	if c.Web.CORS.MaxAge < 0 {
+		return fmt.Errorf("web.cors.max_age cannot be negative")
+	}
+	for _, route := range c.Web.CORS.Routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("web.cors.routes path %q must start with /", route.Path)
+		}
+		if err := validateCORSOrigins("web.cors.routes "+route.Path, route.AllowOrigins, route.AllowCredentials); err != nil {
+			return err
+		}
+	}
+
+	// Validate the legacy API sunset date
+	if c.Web.LegacyAPI.Sunset != "" {
+		if _, err := time.Parse("2006-01-02", c.Web.LegacyAPI.Sunset); err != nil {
+			return fmt.Errorf("web.legacy_api.sunset must be a date as YYYY-MM-DD")
+		}
+	}
+
+	// Validate public feeds
+	if c.Web.Feeds.Enabled {
+		if c.Web.Feeds.Days < 1 || c.Web.Feeds.Days > 90 {
+			return fmt.Errorf("web.feeds.days must be between 1 and 90")
+		}
+		if c.Web.Feeds.MinPriority < 1 || c.Web.Feeds.MinPriority > 100 {
+			return fmt.Errorf("web.feeds.min_priority must be between 1 and 100")
+		}
+	}
+
+	// Validate public mode
+	if c.Web.Public.Enabled {
+		if c.Web.Public.Delay < 1 || c.Web.Public.Delay > 1440 {
+			return fmt.Errorf("web.public_mode.delay must be between 1 and 1440 minutes")
+		}
+		for i, rule := range c.Web.Public.Redactions {
+			if rule.Find == "" {
+				return fmt.Errorf("web.public_mode.redactions[%d].find is required", i)
+			}
+		}
+	}
+
+	// Validate the LLM provider
+	if err := c.LLM.validate(); err != nil {
+		return err
+	}
+
+	// Validate talkgroup filter overrides
+	for talkgroupID, filter := range c.FileMonitor.TalkgroupOverrides {
+		if filter.Mute && filter.Priority {
+			return fmt.Errorf("file_monitor.talkgroup_overrides.%s cannot be both mute and priority", talkgroupID)
+		}
+		if filter.MinCallDuration < 0 {
+			return fmt.Errorf("file_monitor.talkgroup_overrides.%s.min_call_duration cannot be negative", talkgroupID)
+		}
+	}
+
+	// Validate classification rules
+	for i, rule := range c.Talkgroups.Classification.Rules {
+		if rule.ServiceType == "" {
+			return fmt.Errorf("talkgroups.classification.rules[%d] requires a service_type", i)
+		}
+		if len(rule.Keywords) == 0 && rule.Regex == "" {
+			return fmt.Errorf("talkgroups.classification.rules[%d] requires keywords or a regex", i)
+		}
+		if rule.Regex != "" {
+			if _, err := regexp.Compile(rule.Regex); err != nil {
+				return fmt.Errorf("talkgroups.classification.rules[%d].regex is invalid: %w", i, err)
+			}
+		}
+	}
+
+	// Validate tone stations
+	for i, station := range c.Tones.Stations {
+		if station.Name == "" || station.ToneA <= 0 || station.ToneB <= 0 {
+			return fmt.Errorf("tones.stations[%d] requires name, tone_a and tone_b", i)
+		}
+	}
+
+	// Validate voice detection
+	if c.VoiceDetection.MaxFlatness < 0 || c.VoiceDetection.MaxFlatness > 1 {
+		return fmt.Errorf("voice_detection.max_flatness must be between 0 and 1")
+	}
+	if c.VoiceDetection.MinModulation < 0 {
+		return fmt.Errorf("voice_detection.min_modulation cannot be negative")
+	}
+
+	// Validate priority scoring
+	if c.Priority.KeywordWeight < 0 || c.Priority.DurationWeight < 0 {
+		return fmt.Errorf("priority.keyword_weight and priority.duration_weight cannot be negative")
+	}
+	if c.Priority.MajorIncident < 1 || c.Priority.MajorIncident > 100 {
+		return fmt.Errorf("priority.major_incident must be between 1 and 100")
+	}
+	if c.Discord.Notifications.EscalatePriority < 0 || c.Discord.Notifications.EscalatePriority > 100 {
+		return fmt.Errorf("discord.notifications.escalate_priority must be between 0 and 100")
+	}
+
+	// Validate simulcast deduplication
+	if c.Dedup.Window < 0 || c.Dedup.DurationTolerance < 0 {
+		return fmt.Errorf("dedup.window and dedup.duration_tolerance cannot be negative")
+	}
+	if c.Dedup.MinSimilarity < 0 || c.Dedup.MinSimilarity > 1 {
+		return fmt.Errorf("dedup.min_similarity must be between 0 and 1")
+	}
+
+	// Validate call enrichment
+	if c.Enrichment.Enabled && !c.LLM.Enabled() {
+		return fmt.Errorf("llm.provider is required when enrichment is enabled")
+	}
+	if c.Enrichment.MinDuration < 0 {
+		return fmt.Errorf("enrichment.min_duration cannot be negative")
+	}
+
+	// Validate semantic search
+	if c.Embeddings.Enabled {
+		if !slices.Contains(embeddingProviders, c.Embeddings.Provider) {
+			return fmt.Errorf("embeddings.provider must be one of: %s", strings.Join(embeddingProviders, ", "))
+		}
+		if c.Embeddings.APIKey == "" && c.Embeddings.Provider == "gemini" {
+			return fmt.Errorf("embeddings.api_key is required for the gemini provider")
+		}
+		if c.Embeddings.MinSimilarity <= 0 || c.Embeddings.MinSimilarity > 1 {
+			return fmt.Errorf("embeddings.min_similarity must be between 0 and 1")
+		}
+		if c.Embeddings.ClusterSimilarity <= 0 || c.Embeddings.ClusterSimilarity > 1 {
+			return fmt.Errorf("embeddings.cluster_similarity must be between 0 and 1")
+		}
+	}
+
+	// Validate transcoding (libopus accepts 6-510 kbps)
+	if c.Transcode.Enabled && (c.Transcode.Bitrate < 6 || c.Transcode.Bitrate > 510) {
+		return fmt.Errorf("transcode.bitrate must be between 6 and 510 kbps")
+	}
+
+	// Validate the recording archive
+	if c.Organize.Enabled {
+		if c.Organize.Directory == "" {
+			return fmt.Errorf("organize.directory is required when organizing is enabled")
+		}
+		if c.Organize.Mode != "move" && c.Organize.Mode != "hardlink" {
+			return fmt.Errorf("organize.mode must be 'move' or 'hardlink'")
+		}
+	}
+	if c.Reconcile.Enabled && c.Reconcile.Interval < 1 {
+		return fmt.Errorf("reconcile.interval must be at least 1 hour")
+	}
+
+	// Validate archive configuration (if enabled)
+	if c.Archive.Enabled {
+		if _, err := time.Parse("15:04", c.Archive.RunAt); err != nil {
+			return fmt.Errorf("archive.run_at must be in HH:MM format")
+		}
+		for _, format := range c.Archive.Formats {
+			if format != "markdown" && format != "html" && format != "pdf" {
+				return fmt.Errorf("archive.formats must contain only 'markdown', 'html' or 'pdf'")
+			}
+		}
+	}
+
+	// Validate email configuration (if enabled)
+	if c.Email.Enabled {
+		if c.Email.SMTP.Host == "" || c.Email.SMTP.From == "" {
+			return fmt.Errorf("email.smtp.host and email.smtp.from are required when email is enabled")
+		}
+		if c.Email.SMTP.TLS != "starttls" && c.Email.SMTP.TLS != "tls" && c.Email.SMTP.TLS != "none" {
+			return fmt.Errorf("email.smtp.tls must be 'starttls', 'tls' or 'none'")
+		}
+		for i, digest := range c.Email.Digests {
+			if digest.Frequency != "daily" && digest.Frequency != "weekly" {
+				return fmt.Errorf("email.digests[%d].frequency must be 'daily' or 'weekly'", i)
+			}
+			if _, err := time.Parse("15:04", digest.RunAt); err != nil {
+				return fmt.Errorf("email.digests[%d].run_at must be in HH:MM format", i)
+			}
+			if _, ok := ParseWeekday(digest.Weekday); !ok {
+				return fmt.Errorf("email.digests[%d].weekday is not a valid day of the week", i)
+			}
+			if len(digest.Recipients) == 0 {
+				return fmt.Errorf("email.digests[%d] requires at least one recipient", i)
+			}
+			for j, recipient := range digest.Recipients {
+				if recipient.Address == "" {
+					return fmt.Errorf("email.digests[%d].recipients[%d].address is required", i, j)
+				}
+			}
+		}
+	}
+
+	// Validate push alerts (if enabled)
+	if c.Push.Enabled {
+		if (c.Push.Pushover.AppToken == "") != (c.Push.Pushover.UserKey == "") {
+			return fmt.Errorf("push.pushover requires both app_token and user_key")
+		}
+		if c.Push.Pushover.AppToken == "" && c.Push.Ntfy.Topic == "" {
+			return fmt.Errorf("push requires pushover credentials or an ntfy topic")
+		}
+		if c.Push.Cooldown < 0 {
+			return fmt.Errorf("push.cooldown cannot be negative")
+		}
+		if len(c.Push.Rules) == 0 {
+			return fmt.Errorf("push requires at least one rule")
+		}
+		for i, rule := range c.Push.Rules {
+			if rule.Name == "" {
+				return fmt.Errorf("push.rules[%d] requires a name", i)
+			}
+			if len(rule.Keywords) == 0 && len(rule.Talkgroups) == 0 && len(rule.ServiceTypes) == 0 && rule.MinSeverity == 0 && rule.MinPriority == 0 {
+				return fmt.Errorf("push.rules[%d] requires keywords, talkgroups, service_types, min_severity or min_priority", i)
+			}
+			if rule.MinSeverity < 0 || rule.MinSeverity > 5 {
+				return fmt.Errorf("push.rules[%d].min_severity must be between 0 and 5", i)
+			}
+			if rule.MinPriority < 0 || rule.MinPriority > 100 {
+				return fmt.Errorf("push.rules[%d].min_priority must be between 0 and 100", i)
+			}
+			if rule.Priority < -2 || rule.Priority > 2 {
+				return fmt.Errorf("push.rules[%d].priority must be between -2 and 2", i)
+			}
+		}
+	}
 
-	// Validate file paths exist
-	if _, err := os.Stat(c.SDRTrunk.Path); os.IsNotExist(err) {
-		return fmt.Errorf("sdrtrunk.path does not exist: %s", c.SDRTrunk.Path)
+	// Validate anomaly detection (if enabled)
+	if c.Anomalies.Enabled {
+		if c.Anomalies.CheckInterval < 0 || c.Anomalies.BaselineDays < 0 || c.Anomalies.MinCalls < 0 || c.Anomalies.Cooldown < 0 {
+			return fmt.Errorf("anomalies.check_interval, baseline_days, min_calls and cooldown cannot be negative")
+		}
+		if c.Anomalies.Threshold <= 1 {
+			return fmt.Errorf("anomalies.threshold must be above 1")
+		}
+	}
+
+	// Validate redaction patterns (if enabled)
+	if c.Redaction.Enabled {
+		lists := []struct {
+			name     string
+			patterns []string
+		}{{"names", c.Redaction.Names}, {"plates", c.Redaction.Plates}, {"medical", c.Redaction.Medical}}
+		for _, list := range lists {
+			for i, pattern := range list.patterns {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("redaction.%s[%d] is not a valid regular expression: %w", list.name, i, err)
+				}
+			}
+		}
 	}
 
-	// Validate audio output directory exists
-	if _, err := os.Stat(c.SDRTrunk.AudioOutputDir); os.IsNotExist(err) {
-		return fmt.Errorf("sdrtrunk.audio_output_dir does not exist: %s", c.SDRTrunk.AudioOutputDir)
+	return c.validateCapturePaths()
+}
+
+// validateNotificationTypes checks the notifications selected for a chat
+// integration and its severity threshold
+func validateNotificationTypes(section string, types []string, minSeverity int) error {
+	for _, kind := range types {
+		if !slices.Contains(notificationTypes, kind) {
+			return fmt.Errorf("%s.notifications must only contain %s", section, strings.Join(notificationTypes, ", "))
+		}
+	}
+	if minSeverity < 0 || minSeverity > 5 {
+		return fmt.Errorf("%s.min_severity must be between 0 and 5", section)
 	}
+	return nil
+}
 
+// validateCORSOrigins checks a CORS policy's origins, which must be * or a
+// scheme and host without a path. Browsers refuse credentials for any origin.
+func validateCORSOrigins(section string, origins []string, credentials bool) error {
+	for _, origin := range origins {
+		if origin == "*" {
+			if credentials {
+				return fmt.Errorf("%s cannot allow credentials for every origin; list the origins instead of *", section)
+			}
+			continue
+		}
+		scheme, host, ok := strings.Cut(origin, "://")
+		if !ok || (scheme != "http" && scheme != "https") || host == "" || strings.ContainsAny(host, "/?#*") {
+			return fmt.Errorf("%s origin %q must look like https://example.com", section, origin)
+		}
+	}
 	return nil
 }
 
+// validateCapturePaths checks that the SDRTrunk paths exist on capturing instances
+func (c *Config) validateCapturePaths() error {
+	if !c.Captures() {
+		return nil
+	}
+
+	for i, system := range c.CaptureSystems() {
+		prefix := "sdrtrunk"
+		if len(c.Systems) > 0 {
+			prefix = fmt.Sprintf("systems[%d].sdrtrunk", i)
+		}
+
+		// Validate file paths exist
+		if _, err := os.Stat(system.SDRTrunk.Path); os.IsNotExist(err) && !c.SDRTrunk.External {
+			return fmt.Errorf("%s.path does not exist: %s", prefix, system.SDRTrunk.Path)
+		}
+
+		// Validate audio output directory exists
+		if _, err := os.Stat(system.SDRTrunk.AudioOutputDir); os.IsNotExist(err) {
+			return fmt.Errorf("%s.audio_output_dir does not exist: %s", prefix, system.SDRTrunk.AudioOutputDir)
+		}
+	}
+
+	for i, directory := range c.FileMonitor.Directories {
+		if _, err := os.Stat(directory.Path); os.IsNotExist(err) {
+			return fmt.Errorf("file_monitor.directories[%d].path does not exist: %s", i, directory.Path)
+		}
+	}
+
+	return nil
+}
+
+// FilenameTemplates returns the custom recording filename formats
+func (c *Config) FilenameTemplates() []filename.Template {
+	templates := make([]filename.Template, len(c.FileMonitor.FilenameFormats))
+	for i, format := range c.FileMonitor.FilenameFormats {
+		templates[i] = format.Template()
+	}
+	return templates
+}
+
+// CaptureSystems returns the systems to capture. Without a systems section this
+// is a single system with an empty ID using the top-level sdrtrunk settings.
+func (c *Config) CaptureSystems() []SystemConfig {
+	if len(c.Systems) > 0 {
+		return c.Systems
+	}
+	return []SystemConfig{{SDRTrunk: c.SDRTrunk}}
+}
+
+// WatchDirectories returns the directories to watch for recordings: each
+// system's audio_output_dir, in the order of CaptureSystems, followed by
+// file_monitor.directories
+func (c *Config) WatchDirectories() []WatchDirectoryConfig {
+	var directories []WatchDirectoryConfig
+	for _, system := range c.CaptureSystems() {
+		directories = append(directories, WatchDirectoryConfig{
+			Path:           system.SDRTrunk.AudioOutputDir,
+			System:         system.ID,
+			Patterns:       c.FileMonitor.Patterns,
+			FilenameFormat: system.SDRTrunk.FilenameFormat,
+			Recursive:      c.FileMonitor.Recursive,
+		})
+	}
+	return append(directories, c.FileMonitor.Directories...)
+}
+
+// GetSystem returns the configured system with an ID, if any
+func (c *Config) GetSystem(id string) (SystemConfig, bool) {
+	for _, system := range c.Systems {
+		if system.ID == id {
+			return system, true
+		}
+	}
+	return SystemConfig{}, false
+}
+
+// Captures reports whether this instance runs SDRTrunk and watches for recordings
+func (c *Config) Captures() bool {
+	return c.Mode != "server"
+}
+
+// ManagesSDRTrunk reports whether this instance starts and supervises SDRTrunk,
+// rather than only watching the recordings of an SDRTrunk run elsewhere
+func (c *Config) ManagesSDRTrunk() bool {
+	return c.Captures() && !c.SDRTrunk.External
+}
+
+// Processes reports whether this instance stores, transcribes and serves calls
+func (c *Config) Processes() bool {
+	return c.Mode != "agent"
+}
+
+// ParseWeekday parses a day name such as "monday" or "Mon"
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
+
 // GetPollInterval returns the file monitor poll interval as a time.Duration
 func (c *Config) GetPollInterval() time.Duration {
 	return time.Duration(c.FileMonitor.PollInterval) * time.Millisecond
@@ -388,10 +2371,48 @@ func (c *Config) GetCheckInterval() time.Duration {
 
 // GetDiscordUpdateInterval returns the Discord update interval as a time.Duration
 func (c *Config) GetDiscordUpdateInterval() time.Duration {
-	return time.Duration(c.Discord.Monitoring.UpdateInterval) * time.Millisecond
+	return time.Duration(c.Discord.Monitoring.UpdateInterval) * time.Second
 }
 
 // GetMinCallDuration returns the minimum call duration as a time.Duration
 func (c *Config) GetMinCallDuration() time.Duration {
 	return time.Duration(c.FileMonitor.MinCallDuration) * time.Second
 }
+
+// GetTalkgroupFilter returns the filter overrides for a talkgroup
+func (c *Config) GetTalkgroupFilter(talkgroupID string) TalkgroupFilterConfig {
+	return c.FileMonitor.TalkgroupOverrides[talkgroupID]
+}
+
+// GetMinCallDurationFor returns the minimum call duration for a talkgroup,
+// falling back to the global minimum
+func (c *Config) GetMinCallDurationFor(talkgroupID string) time.Duration {
+	if filter := c.GetTalkgroupFilter(talkgroupID); filter.MinCallDuration > 0 {
+		return time.Duration(filter.MinCallDuration) * time.Second
+	}
+	return c.GetMinCallDuration()
+}
+
+// GetGlossary returns the combined global and talkgroup-specific glossary terms
+func (c *Config) GetGlossary(talkgroupID string) []string {
+	terms := make([]string, 0, len(c.Talkgroups.Glossaries.Global))
+	seen := make(map[string]bool)
+
+	add := func(list []string) {
+		for _, term := range list {
+			term = strings.TrimSpace(term)
+			if term == "" || seen[strings.ToLower(term)] {
+				continue
+			}
+			seen[strings.ToLower(term)] = true
+			terms = append(terms, term)
+		}
+	}
+
+	add(c.Talkgroups.Glossaries.Global)
+	if talkgroupID != "" {
+		add(c.Talkgroups.Glossaries.Specific[talkgroupID])
+	}
+
+	return terms
+}
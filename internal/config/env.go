@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables that override the config file.
+// The rest of the name is the setting's path in upper case, joined with
+// underscores: MEIKO_WEB_PORT sets web.port and MEIKO_SDRTRUNK_AUDIO_OUTPUT_DIR
+// sets sdrtrunk.audio_output_dir.
+const EnvPrefix = "MEIKO_"
+
+// hasEnvConfig reports whether any setting is given in the environment
+func hasEnvConfig() bool {
+	var config Config
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		if _, ok := envField(reflect.ValueOf(&config).Elem(), strings.TrimPrefix(name, EnvPrefix)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEnv sets each setting given in a MEIKO_ environment variable. Strings
+// are taken as they are, lists of strings may be comma separated, and any
+// other value is parsed as YAML, so MEIKO_SYSTEMS can hold a list of systems.
+// Variables that name no setting are skipped and listed in IgnoredEnv, as
+// Kubernetes adds MEIKO_PORT, MEIKO_SERVICE_HOST and others to every pod in a
+// namespace with a service named meiko.
+func (c *Config) applyEnv() error {
+	var names []string
+	values := make(map[string]string)
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(name, EnvPrefix) {
+			names = append(names, name)
+			values[name] = value
+		}
+	}
+	// Apply in a fixed order, so a list and a field inside it resolve the same way every time
+	sort.Strings(names)
+
+	for _, name := range names {
+		field, ok := envField(reflect.ValueOf(c).Elem(), strings.TrimPrefix(name, EnvPrefix))
+		if !ok {
+			c.IgnoredEnv = append(c.IgnoredEnv, name)
+			continue
+		}
+		if err := setEnvValue(field, values[name]); err != nil {
+			return fmt.Errorf("invalid value in environment variable %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// envField finds the field a variable name refers to, matching the yaml
+// names of nested settings one level at a time
+func envField(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := strings.ToUpper(tag)
+		field := v.Field(i)
+		if key == name {
+			return field, true
+		}
+		if field.Kind() == reflect.Struct && strings.HasPrefix(key, name+"_") {
+			if found, ok := envField(field, key[len(name)+1:]); ok {
+				return found, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setEnvValue parses a variable's value into a field
+func setEnvValue(field reflect.Value, value string) error {
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+		return nil
+	case field.Type() == reflect.TypeOf([]string(nil)) && !strings.HasPrefix(strings.TrimSpace(value), "["):
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+		return nil
+	}
+
+	parsed := reflect.New(field.Type())
+	if err := yaml.Unmarshal([]byte(value), parsed.Interface()); err != nil {
+		return err
+	}
+	field.Set(parsed.Elem())
+	return nil
+}
@@ -0,0 +1,178 @@
+package corrections
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+)
+
+// compiledRule is a correction rule ready to be applied
+type compiledRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+	literal     bool            // Plain rules insert the replacement as written, without $ expansion
+	talkgroups  map[string]bool // Empty applies to all talkgroups
+}
+
+// Engine applies find/replace corrections to transcriptions
+type Engine struct {
+	config config.CorrectionsConfig
+	db     *database.Database
+	logger *logger.Logger
+	rules  []*compiledRule
+	mu     sync.RWMutex
+}
+
+// New creates a new correction engine and loads its rules
+func New(cfg config.CorrectionsConfig, db *database.Database, logger *logger.Logger) (*Engine, error) {
+	engine := &Engine{
+		config: cfg,
+		db:     db,
+		logger: logger,
+	}
+
+	if err := engine.Reload(); err != nil {
+		return nil, err
+	}
+
+	return engine, nil
+}
+
+// Reload recompiles rules from the configuration and the database
+func (e *Engine) Reload() error {
+	var rules []*compiledRule
+
+	for i, ruleCfg := range e.config.Rules {
+		pattern, err := Compile(ruleCfg.Find, ruleCfg.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid correction rule %d (%q): %w", i, ruleCfg.Find, err)
+		}
+		rules = append(rules, &compiledRule{
+			pattern:     pattern,
+			replacement: ruleCfg.Replace,
+			literal:     !ruleCfg.Regex,
+			talkgroups:  toSet(ruleCfg.Talkgroups),
+		})
+	}
+
+	if e.db != nil {
+		dbRules, err := e.db.GetCorrectionRules()
+		if err != nil {
+			return fmt.Errorf("failed to load correction rules: %w", err)
+		}
+
+		for _, rule := range dbRules {
+			if !rule.Enabled {
+				continue
+			}
+			pattern, err := Compile(rule.Pattern, rule.IsRegex)
+			if err != nil {
+				e.logger.Warn("Skipping invalid correction rule", "id", rule.ID, "pattern", rule.Pattern, "error", err)
+				continue
+			}
+			var talkgroups []string
+			if rule.TalkgroupID != "" {
+				talkgroups = []string{rule.TalkgroupID}
+			}
+			rules = append(rules, &compiledRule{
+				pattern:     pattern,
+				replacement: rule.Replacement,
+				literal:     !rule.IsRegex,
+				talkgroups:  toSet(talkgroups),
+			})
+		}
+	}
+
+	e.mu.Lock()
+	e.rules = rules
+	e.mu.Unlock()
+
+	e.logger.Info("Correction rules loaded", "count", len(rules))
+	return nil
+}
+
+// Apply runs every matching rule over the text in order
+func (e *Engine) Apply(talkgroupID, text string) string {
+	if text == "" {
+		return text
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	corrected := text
+	for _, rule := range e.rules {
+		if len(rule.talkgroups) > 0 && !rule.talkgroups[talkgroupID] {
+			continue
+		}
+		if rule.literal {
+			corrected = rule.pattern.ReplaceAllLiteralString(corrected, rule.replacement)
+		} else {
+			corrected = rule.pattern.ReplaceAllString(corrected, rule.replacement)
+		}
+	}
+
+	if corrected != text {
+		e.logger.Debug("Corrections", "Applied transcription corrections", "talkgroup", talkgroupID)
+	}
+
+	return corrected
+}
+
+// RuleCount returns the number of active rules
+func (e *Engine) RuleCount() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.rules)
+}
+
+// Compile builds the matcher for a rule. Plain (phonetic) patterns match
+// case-insensitively on word boundaries and tolerate spacing or hyphen
+// differences, so "mail box" also matches "mailbox" and "Mail-Box". A word
+// boundary is only required next to a word character, so patterns such as
+// "10-4." or "#12" still match.
+func Compile(find string, isRegex bool) (*regexp.Regexp, error) {
+	find = strings.TrimSpace(find)
+	if find == "" {
+		return nil, fmt.Errorf("pattern is empty")
+	}
+
+	if isRegex {
+		return regexp.Compile(find)
+	}
+
+	words := strings.Fields(find)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+
+	pattern := strings.Join(words, `[\s-]*`)
+	if isWordChar(find[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordChar(find[len(find)-1]) {
+		pattern += `\b`
+	}
+	return regexp.Compile(`(?i)` + pattern)
+}
+
+// isWordChar reports whether a byte is a word character as \b sees it
+func isWordChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// toSet converts a list of talkgroup IDs into a lookup set
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			set[value] = true
+		}
+	}
+	return set
+}
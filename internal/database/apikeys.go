@@ -0,0 +1,127 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKey is a stored API key. The key itself is never stored, only its hash.
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // First characters of the key, for identification
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at`
+
+// scanAPIKey scans a row selected with apiKeyColumns
+func scanAPIKey(row rowScanner, key *APIKey) error {
+	var scopes string
+	var lastUsedAt, revokedAt sql.NullTime
+	if err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &scopes,
+		&key.CreatedAt, &lastUsedAt, &revokedAt); err != nil {
+		return err
+	}
+
+	key.Scopes = strings.Split(scopes, ",")
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return nil
+}
+
+// InsertAPIKey stores a new API key
+func (d *Database) InsertAPIKey(key *APIKey) error {
+	key.CreatedAt = time.Now()
+	result, err := d.db.Exec(
+		"INSERT INTO api_keys (name, prefix, key_hash, scopes, created_at) VALUES (?, ?, ?, ?, ?)",
+		key.Name, key.Prefix, key.KeyHash, strings.Join(key.Scopes, ","), key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert API key: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	key.ID = int(id)
+	d.logger.Debug("Database", "Inserted API key", "id", id, "name", key.Name)
+	return nil
+}
+
+// GetAPIKeys returns all API keys, including revoked ones
+func (d *Database) GetAPIKeys() ([]*APIKey, error) {
+	rows, err := d.db.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*APIKey
+	for rows.Next() {
+		key := &APIKey{}
+		if err := scanAPIKey(rows, key); err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetActiveAPIKeyByHash returns the unrevoked API key with the given hash, or nil
+func (d *Database) GetActiveAPIKeyByHash(hash string) (*APIKey, error) {
+	row := d.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL", hash)
+
+	key := &APIKey{}
+	if err := scanAPIKey(row, key); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// TouchAPIKey records that an API key was used
+func (d *Database) TouchAPIKey(id int, at time.Time) error {
+	if _, err := d.db.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", at, id); err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+	return nil
+}
+
+// RevokeAPIKey revokes an API key so it can no longer be used
+func (d *Database) RevokeAPIKey(id int) error {
+	result, err := d.db.Exec("UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("no active API key found with ID %d", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetCallsToArchive returns processed calls whose audio hasn't been uploaded to
+// object storage yet, oldest first
+func (d *Database) GetCallsToArchive(limit int) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE processed = TRUE AND (storage_key IS NULL OR storage_key = '') AND local_deleted = FALSE
+		ORDER BY timestamp ASC
+		LIMIT ?
+	`
+	return d.queryCalls(query, limit)
+}
+
+// GetArchivedLocalCalls returns archived calls from before a cutoff whose local
+// recording still exists, oldest first
+func (d *Database) GetArchivedLocalCalls(before time.Time, limit int) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE storage_key != '' AND local_deleted = FALSE AND timestamp < ?
+		ORDER BY timestamp ASC
+		LIMIT ?
+	`
+	return d.queryCalls(query, before, limit)
+}
+
+// SetStorageKey records where a call's audio was uploaded
+func (d *Database) SetStorageKey(id int, key string) error {
+	if _, err := d.db.Exec("UPDATE calls SET storage_key = ? WHERE id = ?", key, id); err != nil {
+		return fmt.Errorf("failed to set storage key: %w", err)
+	}
+	d.logger.Debug("Database", "Set call storage key", "id", id, "key", key)
+	return nil
+}
+
+// MarkLocalDeleted records that a call's local recording was removed
+func (d *Database) MarkLocalDeleted(id int) error {
+	if _, err := d.db.Exec("UPDATE calls SET local_deleted = TRUE WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to mark local recording deleted: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// backupRetryDelay is how long Backup waits when the database is locked
+const backupRetryDelay = 100 * time.Millisecond
+
+// Backup copies the database to a new SQLite file at path with SQLite's
+// online backup API, which gives a consistent snapshot while Meiko keeps
+// running. In WAL mode writers carry on while the copy is made.
+func (d *Database) Backup(ctx context.Context, path string) error {
+	dest, err := sql.Open("sqlite3", fileDSN(path, ""))
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer dest.Close()
+
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer destConn.Close()
+
+	srcConn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer srcConn.Close()
+
+	err = destConn.Raw(func(destDriver any) error {
+		return srcConn.Raw(func(srcDriver any) error {
+			destSQLite, ok := destDriver.(*sqlite3.SQLiteConn)
+			srcSQLite, ok2 := srcDriver.(*sqlite3.SQLiteConn)
+			if !ok || !ok2 {
+				return fmt.Errorf("backup requires SQLite connections")
+			}
+
+			backup, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return fmt.Errorf("failed to start backup: %w", err)
+			}
+			for {
+				// Copy every page in one step; a locked database returns
+				// without copying, and is retried
+				done, err := backup.Step(-1)
+				if err != nil {
+					backup.Finish()
+					return fmt.Errorf("failed to copy database: %w", err)
+				}
+				if done {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					backup.Finish()
+					return ctx.Err()
+				case <-time.After(backupRetryDelay):
+				}
+			}
+			if err := backup.Finish(); err != nil {
+				return fmt.Errorf("failed to finish backup: %w", err)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	// The copy keeps the source's WAL mode; a rollback journal makes the
+	// snapshot a single self-contained file
+	if _, err := destConn.ExecContext(ctx, "PRAGMA journal_mode=DELETE"); err != nil {
+		return fmt.Errorf("failed to finish backup: %w", err)
+	}
+	return nil
+}
+
+// VerifyFile opens a database file read-only and runs SQLite's integrity
+// check on it. It returns the number of calls in the file.
+func VerifyFile(path string) (int64, error) {
+	db, err := sql.Open("sqlite3", fileDSN(path, "mode=ro"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return 0, fmt.Errorf("failed to check integrity: %w", err)
+	}
+	if result != "ok" {
+		return 0, fmt.Errorf("integrity check failed: %s", result)
+	}
+
+	var calls int64
+	if err := db.QueryRow("SELECT COUNT(*) FROM calls").Scan(&calls); err != nil {
+		return 0, fmt.Errorf("not a Meiko database: %w", err)
+	}
+	return calls, nil
+}
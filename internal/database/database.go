@@ -2,14 +2,19 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
 	"Meiko/internal/config"
+	"Meiko/internal/frequency"
 	"Meiko/internal/logger"
 )
 
@@ -21,20 +26,64 @@ type Database struct {
 
 // CallRecord represents a call record in the database
 type CallRecord struct {
-	ID              int       `json:"id"`
-	Filename        string    `json:"filename"`
-	Filepath        string    `json:"filepath"`
-	Timestamp       time.Time `json:"timestamp"`
-	Duration        int       `json:"duration"`
-	Frequency       string    `json:"frequency"`
-	TalkgroupID     string    `json:"talkgroup_id"`
-	TalkgroupAlias  string    `json:"talkgroup_alias"`
-	TalkgroupGroup  string    `json:"talkgroup_group"`
-	TranscriptionID *int      `json:"transcription_id,omitempty"`
-	Transcription   string    `json:"transcription"`
-	Processed       bool      `json:"processed"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              int              `json:"id"`
+	Filename        string           `json:"filename"`
+	Filepath        string           `json:"filepath"`
+	Timestamp       time.Time        `json:"timestamp"`
+	Duration        int              `json:"duration"`
+	Frequency       string           `json:"frequency"`              // Hz, or the text it was recorded as when it couldn't be parsed
+	FrequencyHz     int64            `json:"frequency_hz,omitempty"` // 0 when unknown
+	TalkgroupID     string           `json:"talkgroup_id"`
+	TalkgroupAlias  string           `json:"talkgroup_alias"`
+	TalkgroupGroup  string           `json:"talkgroup_group"`
+	TranscriptionID *int             `json:"transcription_id,omitempty"`
+	Transcription   string           `json:"transcription"`
+	Language        string           `json:"language,omitempty"`    // Language the call was transcribed in
+	Translation     string           `json:"translation,omitempty"` // English translation of a non-English transcription
+	Processed       bool             `json:"processed"`
+	Severity        int              `json:"severity"`
+	Priority        int              `json:"priority"`                // 0-100 importance for ordering and escalation
+	DuplicateOf     int              `json:"duplicate_of,omitempty"`  // Original call this is a simulcast duplicate of
+	AudioClass      string           `json:"audio_class,omitempty"`   // "encrypted", "data" or "noise" for recordings that aren't speech
+	Attempts        int              `json:"attempts,omitempty"`      // Failed transcription attempts
+	LastError       string           `json:"last_error,omitempty"`    // Why the last transcription attempt failed
+	RetryAt         *time.Time       `json:"retry_at,omitempty"`      // When transcription is next retried
+	Failed          bool             `json:"failed,omitempty"`        // Gave up transcribing after the last retry
+	Segments        []SpeakerSegment `json:"segments,omitempty"`      // Speaker-tagged transcript (diarization)
+	Tones           []ToneSequence   `json:"tones,omitempty"`         // Detected paging tone sequences
+	Enrichment      *Enrichment      `json:"enrichment,omitempty"`    // Details extracted from the transcription by the LLM
+	Site            string           `json:"site,omitempty"`          // Receive site for calls uploaded by agents
+	SystemID        string           `json:"system_id,omitempty"`     // Radio system the call was captured on
+	StorageKey      string           `json:"storage_key,omitempty"`   // Object storage key once the audio is archived
+	LocalDeleted    bool             `json:"local_deleted,omitempty"` // Local recording removed after archiving
+	AudioMissing    bool             `json:"audio_missing,omitempty"` // Recording deleted outside Meiko, found by reconciliation
+	ContentHash     string           `json:"content_hash,omitempty"`  // SHA-256 of the recording as it arrived
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// SpeakerSegment is one speaker's transmission within a call
+type SpeakerSegment struct {
+	Speaker string  `json:"speaker"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+}
+
+// ToneSequence is a two-tone paging sequence detected in a call
+type ToneSequence struct {
+	ToneA   float64 `json:"tone_a"`
+	ToneB   float64 `json:"tone_b"`
+	Start   float64 `json:"start"`
+	Station string  `json:"station,omitempty"`
+}
+
+// Enrichment holds structured details an LLM extracted from a call's transcription
+type Enrichment struct {
+	IncidentType string   `json:"incident_type,omitempty"` // e.g. fire, medical, traffic_accident
+	Severity     int      `json:"severity,omitempty"`      // 1-5 as judged by the LLM
+	Units        []string `json:"units,omitempty"`         // Units dispatched or mentioned
+	Location     string   `json:"location,omitempty"`      // Address or place of the incident
 }
 
 // HourSummary represents an AI-generated summary for a specific hour
@@ -49,6 +98,106 @@ type HourSummary struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// Summary represents a cached AI summary for an arbitrary time range
+type Summary struct {
+	ID          int        `json:"id"`
+	CacheKey    string     `json:"cache_key"`
+	Scope       string     `json:"scope"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	Prompt      string     `json:"prompt"`
+	Summary     string     `json:"summary"`
+	CallCount   int        `json:"call_count"`
+	Categories  string     `json:"categories"` // JSON array of categories
+	GeneratedAt time.Time  `json:"generated_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// CorrectionRule represents a user-managed transcription correction rule
+type CorrectionRule struct {
+	ID          int       `json:"id"`
+	Pattern     string    `json:"pattern"`
+	Replacement string    `json:"replacement"`
+	IsRegex     bool      `json:"is_regex"`
+	TalkgroupID string    `json:"talkgroup_id"` // Empty applies to all talkgroups
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// callColumns is the column list matching scanCall
+const callColumns = `id, filename, filepath, timestamp, duration, frequency, frequency_hz, talkgroup_id,
+		       talkgroup_alias, talkgroup_group, transcription_id, transcription,
+		       processed, severity, priority, segments, tones, site, system_id, storage_key,
+		       local_deleted, duplicate_of, audio_class, attempts, last_error, retry_at, failed,
+		       language, translation, enrichment, audio_missing, content_hash, created_at, updated_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCall scans a row selected with callColumns into a call record
+func scanCall(row rowScanner, call *CallRecord) error {
+	var segments, tones, site, systemID, storageKey, audioClass, lastError, language, translation, enrichment, contentHash sql.NullString
+	var priority, duplicateOf, attempts, frequencyHz sql.NullInt64
+	var localDeleted, failed, audioMissing sql.NullBool
+	err := row.Scan(
+		&call.ID, &call.Filename, &call.Filepath, &call.Timestamp,
+		&call.Duration, &call.Frequency, &frequencyHz, &call.TalkgroupID,
+		&call.TalkgroupAlias, &call.TalkgroupGroup, &call.TranscriptionID,
+		&call.Transcription, &call.Processed, &call.Severity, &priority, &segments, &tones,
+		&site, &systemID, &storageKey, &localDeleted, &duplicateOf, &audioClass,
+		&attempts, &lastError, &call.RetryAt, &failed, &language, &translation, &enrichment, &audioMissing, &contentHash, &call.CreatedAt, &call.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	call.Site = site.String
+	call.SystemID = systemID.String
+	call.StorageKey = storageKey.String
+	call.LocalDeleted = localDeleted.Bool
+	call.AudioMissing = audioMissing.Bool
+	call.ContentHash = contentHash.String
+	call.FrequencyHz = frequencyHz.Int64
+	call.Priority = int(priority.Int64)
+	call.DuplicateOf = int(duplicateOf.Int64)
+	call.AudioClass = audioClass.String
+	call.Attempts = int(attempts.Int64)
+	call.LastError = lastError.String
+	call.Failed = failed.Bool
+	call.Language = language.String
+	call.Translation = translation.String
+
+	if segments.Valid && segments.String != "" {
+		if err := json.Unmarshal([]byte(segments.String), &call.Segments); err != nil {
+			return fmt.Errorf("failed to decode segments for call %d: %w", call.ID, err)
+		}
+	}
+	if tones.Valid && tones.String != "" {
+		if err := json.Unmarshal([]byte(tones.String), &call.Tones); err != nil {
+			return fmt.Errorf("failed to decode tones for call %d: %w", call.ID, err)
+		}
+	}
+	if enrichment.Valid && enrichment.String != "" {
+		call.Enrichment = &Enrichment{}
+		if err := json.Unmarshal([]byte(enrichment.String), call.Enrichment); err != nil {
+			return fmt.Errorf("failed to decode enrichment for call %d: %w", call.ID, err)
+		}
+	}
+	return nil
+}
+
+// fileDSN returns the SQLite URI of a database file with the given query
+// parameters. The path is escaped, so a ? or # in it isn't read as the start
+// of the parameters.
+func fileDSN(path, params string) string {
+	dsn := "file:" + url.PathEscape(path)
+	if params != "" {
+		dsn += "?" + params
+	}
+	return dsn
+}
+
 // New creates a new database connection
 func New(config config.DatabaseConfig, logger *logger.Logger) (*Database, error) {
 	// Ensure database directory exists
@@ -59,7 +208,11 @@ func New(config config.DatabaseConfig, logger *logger.Logger) (*Database, error)
 		}
 	}
 
-	db, err := sql.Open("sqlite3", config.Path)
+	// Journal mode and busy timeout are set in the DSN so every pooled connection gets them.
+	// WAL lets dashboard reads proceed while the processor writes.
+	dsn := fileDSN(config.Path, fmt.Sprintf("_journal_mode=%s&_busy_timeout=%d&_synchronous=NORMAL",
+		config.JournalMode, config.BusyTimeout))
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -78,7 +231,7 @@ func New(config config.DatabaseConfig, logger *logger.Logger) (*Database, error)
 		return nil, fmt.Errorf("failed to initialize database schema: %w", err)
 	}
 
-	logger.Info("Database initialized successfully", "path", config.Path)
+	logger.Info("Database initialized successfully", "path", config.Path, "journal_mode", config.JournalMode)
 	return database, nil
 }
 
@@ -114,6 +267,22 @@ func (d *Database) initSchema() error {
 			UPDATE calls SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
 		END;
 
+	-- Transcription embeddings for semantic search, removed with their call
+	CREATE TABLE IF NOT EXISTS call_embeddings (
+		call_id INTEGER PRIMARY KEY,
+		model TEXT NOT NULL,
+		vector BLOB NOT NULL, -- Little-endian float32s
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_call_embeddings_model ON call_embeddings(model);
+
+	CREATE TRIGGER IF NOT EXISTS delete_call_embeddings
+		AFTER DELETE ON calls
+		BEGIN
+			DELETE FROM call_embeddings WHERE call_id = OLD.id;
+		END;
+
 	-- Hour summaries table for permanent AI summary storage
 	CREATE TABLE IF NOT EXISTS hour_summaries (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -130,69 +299,468 @@ func (d *Database) initSchema() error {
 	CREATE INDEX IF NOT EXISTS idx_hour_summaries_date ON hour_summaries(date);
 	CREATE INDEX IF NOT EXISTS idx_hour_summaries_date_hour ON hour_summaries(date, hour);
 	CREATE INDEX IF NOT EXISTS idx_hour_summaries_generated_at ON hour_summaries(generated_at);
+
+	-- Cached AI summaries keyed by a deterministic hash of scope, range and prompt
+	CREATE TABLE IF NOT EXISTS summaries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		cache_key TEXT NOT NULL UNIQUE,
+		scope TEXT NOT NULL,
+		start_time DATETIME NOT NULL,
+		end_time DATETIME NOT NULL,
+		prompt TEXT,
+		summary TEXT NOT NULL,
+		call_count INTEGER NOT NULL,
+		categories TEXT, -- JSON array of categories
+		generated_at DATETIME NOT NULL,
+		expires_at DATETIME
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_summaries_scope_range ON summaries(scope, start_time, end_time);
+	CREATE INDEX IF NOT EXISTS idx_summaries_expires_at ON summaries(expires_at);
+
+	-- Transcription correction rules managed through the API
+	CREATE TABLE IF NOT EXISTS correction_rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		pattern TEXT NOT NULL,
+		replacement TEXT NOT NULL,
+		is_regex BOOLEAN DEFAULT FALSE,
+		talkgroup_id TEXT DEFAULT '',
+		enabled BOOLEAN DEFAULT TRUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_correction_rules_talkgroup ON correction_rules(talkgroup_id);
+
+	-- Call statistics rolled up per UTC hour, system, talkgroup and frequency
+	CREATE TABLE IF NOT EXISTS call_rollups_hourly (
+		bucket TEXT NOT NULL, -- UTC hour start, YYYY-MM-DD HH:00:00
+		system_id TEXT NOT NULL DEFAULT '',
+		talkgroup_id TEXT NOT NULL DEFAULT '',
+		talkgroup_alias TEXT NOT NULL DEFAULT '',
+		frequency TEXT NOT NULL DEFAULT '',
+		call_count INTEGER NOT NULL DEFAULT 0,
+		total_duration INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (bucket, system_id, talkgroup_id, frequency)
+	);
+
+	-- Call statistics rolled up per local day, system, talkgroup and frequency
+	CREATE TABLE IF NOT EXISTS call_rollups_daily (
+		day TEXT NOT NULL, -- Local date, YYYY-MM-DD
+		system_id TEXT NOT NULL DEFAULT '',
+		talkgroup_id TEXT NOT NULL DEFAULT '',
+		talkgroup_alias TEXT NOT NULL DEFAULT '',
+		frequency TEXT NOT NULL DEFAULT '',
+		call_count INTEGER NOT NULL DEFAULT 0,
+		total_duration INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (day, system_id, talkgroup_id, frequency)
+	);
+
+	-- API keys for external consumers; only a SHA-256 hash of each key is stored
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		prefix TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		scopes TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_used_at DATETIME,
+		revoked_at DATETIME
+	);
+
+	-- Startups, shutdowns, process restarts and alerts, shown on the timeline
+	CREATE TABLE IF NOT EXISTS system_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp DATETIME NOT NULL,
+		type TEXT NOT NULL,
+		level TEXT NOT NULL,
+		source TEXT DEFAULT '',
+		message TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_system_events_timestamp ON system_events(timestamp);
+
+	-- Known frequencies, labelled for display
+	CREATE TABLE IF NOT EXISTS frequencies (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		frequency INTEGER NOT NULL, -- Hz
+		label TEXT NOT NULL,
+		system_id TEXT NOT NULL DEFAULT '', -- Empty applies to every system
+		mode TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (frequency, system_id)
+	);
+
+	-- Talkgroup metadata edited through the API, superseding the playlist
+	CREATE TABLE IF NOT EXISTS talkgroup_overrides (
+		talkgroup_id TEXT PRIMARY KEY,
+		name TEXT NOT NULL DEFAULT '',
+		group_name TEXT NOT NULL DEFAULT '',
+		service_type TEXT NOT NULL DEFAULT '',
+		color TEXT NOT NULL DEFAULT '',
+		priority BOOLEAN, -- NULL falls back to the config
+		mute BOOLEAN,
+		updated_at DATETIME NOT NULL
+	);
+
+	-- Audit log of calls corrected by hand, kept after the call is deleted
+	CREATE TABLE IF NOT EXISTS call_edits (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		call_id INTEGER NOT NULL,
+		field TEXT NOT NULL,
+		old_value TEXT NOT NULL DEFAULT '',
+		new_value TEXT NOT NULL DEFAULT '',
+		editor TEXT NOT NULL DEFAULT '', -- API key name, or client address without keys
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_call_edits_call_id ON call_edits(call_id);
+
+	-- Dashboard preferences by API key or browser token, as a JSON object
+	CREATE TABLE IF NOT EXISTS user_preferences (
+		owner TEXT PRIMARY KEY,
+		preferences TEXT NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
 	`
 
+	if err := d.dropOutdatedRollups(); err != nil {
+		return err
+	}
+
 	if _, err := d.db.Exec(schema); err != nil {
 		return fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	if err := d.migrateSchema(); err != nil {
+		return err
+	}
+
+	if err := d.ensureCallSearch(); err != nil {
+		return err
+	}
+
+	return d.backfillRollups()
+}
+
+// migrateSchema adds columns introduced after the initial schema to existing databases
+func (d *Database) migrateSchema() error {
+	migrations := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"calls", "severity", "INTEGER DEFAULT 0"},
+		{"calls", "segments", "TEXT DEFAULT ''"},
+		{"calls", "tones", "TEXT DEFAULT ''"},
+		{"calls", "site", "TEXT DEFAULT ''"},
+		{"calls", "system_id", "TEXT DEFAULT ''"},
+		{"calls", "storage_key", "TEXT DEFAULT ''"},
+		{"calls", "local_deleted", "BOOLEAN DEFAULT FALSE"},
+		{"calls", "priority", "INTEGER DEFAULT 0"},
+		{"calls", "duplicate_of", "INTEGER DEFAULT 0"},
+		{"calls", "audio_class", "TEXT DEFAULT ''"},
+		{"calls", "attempts", "INTEGER DEFAULT 0"},
+		{"calls", "last_error", "TEXT DEFAULT ''"},
+		{"calls", "retry_at", "DATETIME"},
+		{"calls", "failed", "BOOLEAN DEFAULT FALSE"},
+		{"calls", "language", "TEXT DEFAULT ''"},
+		{"calls", "translation", "TEXT DEFAULT ''"},
+		{"calls", "enrichment", "TEXT DEFAULT ''"},
+		{"calls", "frequency_hz", "INTEGER DEFAULT 0"},
+		{"calls", "audio_missing", "BOOLEAN DEFAULT FALSE"},
+		{"calls", "content_hash", "TEXT DEFAULT ''"},
+		{"system_events", "call_id", "INTEGER DEFAULT 0"},
+	}
+
+	_, hadFrequencyHz, err := d.hasColumn("calls", "frequency_hz")
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if err := d.ensureColumn(m.table, m.column, m.definition); err != nil {
+			return err
+		}
+	}
+
+	if !hadFrequencyHz {
+		if err := d.normalizeFrequencies(); err != nil {
+			return err
+		}
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_calls_severity ON calls(severity)",
+		"CREATE INDEX IF NOT EXISTS idx_calls_priority_timestamp ON calls(priority, timestamp)",
+		"CREATE INDEX IF NOT EXISTS idx_calls_system_timestamp ON calls(system_id, timestamp)",
+		"CREATE INDEX IF NOT EXISTS idx_calls_duplicate_of ON calls(duplicate_of)",
+		"CREATE INDEX IF NOT EXISTS idx_calls_retry_at ON calls(retry_at) WHERE retry_at IS NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idx_calls_incident_type ON calls(json_extract(NULLIF(enrichment, ''), '$.incident_type'))",
+		"CREATE INDEX IF NOT EXISTS idx_calls_frequency_hz ON calls(frequency_hz)",
+		"CREATE INDEX IF NOT EXISTS idx_calls_content_hash ON calls(content_hash) WHERE content_hash != ''",
+	}
+	for _, index := range indexes {
+		if _, err := d.db.Exec(index); err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeFrequencies fills in frequency_hz for calls recorded before it
+// existed, rewriting frequencies written as MHz or kHz in Hz. The rollups are
+// dropped so backfillRollups rebuilds them with each frequency counted once.
+func (d *Database) normalizeFrequencies() error {
+	rows, err := d.db.Query("SELECT id, frequency FROM calls WHERE COALESCE(frequency, '') != ''")
+	if err != nil {
+		return fmt.Errorf("failed to read call frequencies: %w", err)
+	}
+	hz := make(map[int]int64)
+	for rows.Next() {
+		var id int
+		var raw string
+		if err := rows.Scan(&id, &raw); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan call frequency: %w", err)
+		}
+		if value, ok := frequency.Normalize(raw); ok {
+			hz[id] = value
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read call frequencies: %w", err)
+	}
+	if len(hz) == 0 {
+		return nil
+	}
+
+	err = d.withTx(func(tx *sql.Tx) error {
+		for id, value := range hz {
+			if _, err := tx.Exec("UPDATE calls SET frequency = ?, frequency_hz = ? WHERE id = ?",
+				strconv.FormatInt(value, 10), value, id); err != nil {
+				return fmt.Errorf("failed to normalize frequency of call %d: %w", id, err)
+			}
+		}
+		for _, table := range []string{"call_rollups_hourly", "call_rollups_daily"} {
+			if _, err := tx.Exec("DELETE FROM " + table); err != nil {
+				return fmt.Errorf("failed to clear %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.logger.Info("Normalized call frequencies to Hz", "calls", len(hz))
+	return nil
+}
+
+// dropOutdatedRollups drops rollup tables created before they were keyed by
+// system. Rollups are derived from the calls table, so they are rebuilt by
+// backfillRollups once the schema has recreated them.
+func (d *Database) dropOutdatedRollups() error {
+	for _, table := range []string{"call_rollups_hourly", "call_rollups_daily"} {
+		exists, hasSystem, err := d.hasColumn(table, "system_id")
+		if err != nil {
+			return err
+		}
+		if !exists || hasSystem {
+			continue
+		}
+		if _, err := d.db.Exec("DROP TABLE " + table); err != nil {
+			return fmt.Errorf("failed to drop outdated %s: %w", table, err)
+		}
+		d.logger.Info("Dropped outdated call rollups for rebuild", "table", table)
+	}
+	return nil
+}
+
+// hasColumn reports whether a table exists and whether it has a column
+func (d *Database) hasColumn(table, column string) (bool, bool, error) {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	exists := false
+	for rows.Next() {
+		exists = true
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, false, fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return true, true, nil
+		}
+	}
+
+	return exists, false, rows.Err()
+}
+
+// ensureColumn adds a column to a table if it does not already exist
+func (d *Database) ensureColumn(table, column, definition string) error {
+	_, found, err := d.hasColumn(table, column)
+	if err != nil || found {
+		return err
+	}
+
+	if _, err := d.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+
+	d.logger.Info("Database schema migrated", "table", table, "column", column)
 	return nil
 }
 
 // InsertCall inserts a new call record
 func (d *Database) InsertCall(call *CallRecord) error {
 	query := `
-		INSERT INTO calls (filename, filepath, timestamp, duration, frequency, talkgroup_id, talkgroup_alias, talkgroup_group, transcription)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO calls (filename, filepath, timestamp, duration, frequency, frequency_hz, talkgroup_id, talkgroup_alias, talkgroup_group, transcription, site, system_id, content_hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := d.db.Exec(query,
-		call.Filename, call.Filepath, call.Timestamp, call.Duration, call.Frequency,
-		call.TalkgroupID, call.TalkgroupAlias, call.TalkgroupGroup, call.Transcription)
+	// Frequencies are stored in Hz when they can be parsed, so the same
+	// frequency groups together however it was written
+	if hz, ok := frequency.Normalize(call.Frequency); ok {
+		call.Frequency = strconv.FormatInt(hz, 10)
+		call.FrequencyHz = hz
+	}
+
+	// The call and its statistics rollups are written together
+	err := d.withTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec(query,
+			call.Filename, call.Filepath, call.Timestamp, call.Duration, call.Frequency, call.FrequencyHz,
+			call.TalkgroupID, call.TalkgroupAlias, call.TalkgroupGroup, call.Transcription, call.Site, call.SystemID, call.ContentHash)
+		if err != nil {
+			return fmt.Errorf("failed to insert call: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert ID: %w", err)
+		}
+		call.ID = int(id)
 
+		return addToRollups(tx, call.Timestamp, call.SystemID, call.TalkgroupID, call.TalkgroupAlias, call.Frequency, 1, int64(call.Duration))
+	})
 	if err != nil {
-		return fmt.Errorf("failed to insert call: %w", err)
+		return err
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert ID: %w", err)
+	d.logger.Debug("Database", "Inserted call record", "id", call.ID, "file", call.Filename)
+	return nil
+}
+
+// CompleteCall stores the transcription results for a call and marks it as
+// processed in a single transaction. A call linked as a simulcast duplicate is
+// taken back out of the statistics rollups.
+func (d *Database) CompleteCall(call *CallRecord) error {
+	return d.withTx(func(tx *sql.Tx) error {
+		if err := storeCallResults(tx, call); err != nil {
+			return err
+		}
+
+		if call.DuplicateOf > 0 {
+			if err := removeFromRollups(tx, call); err != nil {
+				return err
+			}
+		}
+
+		d.logger.Debug("Database", "Completed call record", "id", call.ID)
+		return nil
+	})
+}
+
+// UpdateCallResults replaces the stored results of a call processed again.
+// Its rollups and simulcast link were settled when it was first completed.
+func (d *Database) UpdateCallResults(call *CallRecord) error {
+	if err := storeCallResults(d.db, call); err != nil {
+		return err
 	}
 
-	call.ID = int(id)
-	d.logger.Debug("Database", "Inserted call record", "id", id, "file", call.Filename)
+	d.logger.Debug("Database", "Updated call results", "id", call.ID)
 	return nil
 }
 
-// UpdateTranscription updates the transcription for a call
-func (d *Database) UpdateTranscription(id int, transcription string) error {
-	query := `UPDATE calls SET transcription = ? WHERE id = ?`
+// storeCallResults writes a call's transcription results and processing state
+func storeCallResults(db execer, call *CallRecord) error {
+	segments, err := marshalOptional(call.Segments)
+	if err != nil {
+		return fmt.Errorf("failed to encode segments: %w", err)
+	}
+	tones, err := marshalOptional(call.Tones)
+	if err != nil {
+		return fmt.Errorf("failed to encode tones: %w", err)
+	}
+	var enrichment string
+	if call.Enrichment != nil {
+		data, err := json.Marshal(call.Enrichment)
+		if err != nil {
+			return fmt.Errorf("failed to encode enrichment: %w", err)
+		}
+		enrichment = string(data)
+	}
 
-	result, err := d.db.Exec(query, transcription, id)
+	query := `
+		UPDATE calls
+		SET transcription = ?, language = ?, translation = ?, severity = ?, priority = ?, segments = ?, tones = ?, enrichment = ?,
+		    processed = ?, duplicate_of = ?, audio_class = ?, last_error = '', retry_at = NULL, failed = FALSE, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	result, err := db.Exec(query, call.Transcription, call.Language, call.Translation, call.Severity, call.Priority,
+		segments, tones, enrichment, call.Processed, call.DuplicateOf, call.AudioClass, call.ID)
 	if err != nil {
-		return fmt.Errorf("failed to update transcription: %w", err)
+		return fmt.Errorf("failed to complete call: %w", err)
 	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get affected rows: %w", err)
 	}
-
 	if rows == 0 {
-		return fmt.Errorf("no call found with ID %d", id)
+		return fmt.Errorf("no call found with ID %d", call.ID)
 	}
-
-	d.logger.Debug("Database", "Updated transcription", "id", id)
 	return nil
 }
 
-// MarkAsProcessed marks a call as processed
-func (d *Database) MarkAsProcessed(id int) error {
-	query := `UPDATE calls SET processed = TRUE WHERE id = ?`
+// marshalOptional encodes a slice as JSON, or an empty string when it is empty
+func marshalOptional[T any](values []T) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling back on error
+func (d *Database) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
 
-	result, err := d.db.Exec(query, id)
+// UpdateCallFile points a call at a new recording, e.g. after transcoding
+func (d *Database) UpdateCallFile(id int, filename, filepath string) error {
+	result, err := d.db.Exec("UPDATE calls SET filename = ?, filepath = ? WHERE id = ?", filename, filepath, id)
 	if err != nil {
-		return fmt.Errorf("failed to mark call as processed: %w", err)
+		return fmt.Errorf("failed to update call file: %w", err)
 	}
 
 	rows, err := result.RowsAffected()
@@ -204,67 +772,41 @@ func (d *Database) MarkAsProcessed(id int) error {
 		return fmt.Errorf("no call found with ID %d", id)
 	}
 
-	d.logger.Debug("Database", "Marked call as processed", "id", id)
+	d.logger.Debug("Database", "Updated call file", "id", id, "file", filename)
 	return nil
 }
 
-// GetUnprocessedCalls returns calls that haven't been processed yet
-func (d *Database) GetUnprocessedCalls(limit int) ([]*CallRecord, error) {
-	query := `
-		SELECT id, filename, filepath, timestamp, duration, frequency, talkgroup_id, 
-		       talkgroup_alias, talkgroup_group, transcription_id, transcription, 
-		       processed, created_at, updated_at
-		FROM calls 
-		WHERE processed = FALSE 
-		ORDER BY created_at ASC 
-		LIMIT ?
-	`
-
-	rows, err := d.db.Query(query, limit)
+// UpdateCallTalkgroup stores a call's talkgroup display name and group, e.g.
+// after the playlist mapping it was classified with is corrected
+func (d *Database) UpdateCallTalkgroup(id int, alias, group string) error {
+	result, err := d.db.Exec("UPDATE calls SET talkgroup_alias = ?, talkgroup_group = ? WHERE id = ?", alias, group, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query unprocessed calls: %w", err)
+		return fmt.Errorf("failed to update call talkgroup: %w", err)
 	}
-	defer rows.Close()
 
-	var calls []*CallRecord
-	for rows.Next() {
-		call := &CallRecord{}
-		err := rows.Scan(
-			&call.ID, &call.Filename, &call.Filepath, &call.Timestamp,
-			&call.Duration, &call.Frequency, &call.TalkgroupID,
-			&call.TalkgroupAlias, &call.TalkgroupGroup, &call.TranscriptionID,
-			&call.Transcription, &call.Processed, &call.CreatedAt, &call.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan call record: %w", err)
-		}
-		calls = append(calls, call)
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration error: %w", err)
+	if rows == 0 {
+		return fmt.Errorf("no call found with ID %d", id)
 	}
 
-	return calls, nil
+	d.logger.Debug("Database", "Updated call talkgroup", "id", id, "talkgroup", alias)
+	return nil
 }
 
 // GetCallByFilepath returns a call record by its filepath
 func (d *Database) GetCallByFilepath(filepath string) (*CallRecord, error) {
 	query := `
-		SELECT id, filename, filepath, timestamp, duration, frequency, talkgroup_id, 
-		       talkgroup_alias, talkgroup_group, transcription_id, transcription, 
-		       processed, created_at, updated_at
+		SELECT ` + callColumns + `
 		FROM calls 
 		WHERE filepath = ?
 	`
 
 	call := &CallRecord{}
-	err := d.db.QueryRow(query, filepath).Scan(
-		&call.ID, &call.Filename, &call.Filepath, &call.Timestamp,
-		&call.Duration, &call.Frequency, &call.TalkgroupID,
-		&call.TalkgroupAlias, &call.TalkgroupGroup, &call.TranscriptionID,
-		&call.Transcription, &call.Processed, &call.CreatedAt, &call.UpdatedAt,
-	)
+	err := scanCall(d.db.QueryRow(query, filepath), call)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -279,9 +821,7 @@ func (d *Database) GetCallByFilepath(filepath string) (*CallRecord, error) {
 // GetRecentCalls returns the most recent calls
 func (d *Database) GetRecentCalls(limit int) ([]*CallRecord, error) {
 	query := `
-		SELECT id, filename, filepath, timestamp, duration, frequency, talkgroup_id, 
-		       talkgroup_alias, talkgroup_group, transcription_id, transcription, 
-		       processed, created_at, updated_at
+		SELECT ` + callColumns + `
 		FROM calls 
 		ORDER BY timestamp DESC 
 		LIMIT ?
@@ -296,12 +836,7 @@ func (d *Database) GetRecentCalls(limit int) ([]*CallRecord, error) {
 	var calls []*CallRecord
 	for rows.Next() {
 		call := &CallRecord{}
-		err := rows.Scan(
-			&call.ID, &call.Filename, &call.Filepath, &call.Timestamp,
-			&call.Duration, &call.Frequency, &call.TalkgroupID,
-			&call.TalkgroupAlias, &call.TalkgroupGroup, &call.TranscriptionID,
-			&call.Transcription, &call.Processed, &call.CreatedAt, &call.UpdatedAt,
-		)
+		err := scanCall(rows, call)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan call record: %w", err)
 		}
@@ -315,33 +850,119 @@ func (d *Database) GetRecentCalls(limit int) ([]*CallRecord, error) {
 	return calls, nil
 }
 
-// GetCallRecords returns call records with optional filtering
-func (d *Database) GetCallRecords(start, end *time.Time, talkgroupID string, limit, offset int) ([]*CallRecord, error) {
-	query := `
-		SELECT id, filename, filepath, timestamp, duration, frequency, talkgroup_id, 
-		       talkgroup_alias, talkgroup_group, transcription_id, transcription, 
-		       processed, created_at, updated_at
-		FROM calls 
-		WHERE 1=1
-	`
+// CallCursor identifies a position in calls, or system events, ordered newest
+// first, for keyset pagination
+type CallCursor struct {
+	Timestamp time.Time
+	ID        int
+}
+
+// Call processing states for filtering call listings
+const (
+	CallStatusProcessed = "processed" // Transcribed or classified
+	CallStatusPending   = "pending"   // Waiting to be transcribed or retried
+	CallStatusFailed    = "failed"    // Gave up transcribing after the last retry
+)
+
+// CallFilter selects calls for listing and counting. Zero values don't filter.
+type CallFilter struct {
+	Start        *time.Time
+	End          *time.Time
+	TalkgroupID  string
+	TalkgroupIDs []string // Any of these talkgroups; an empty non-nil list matches nothing
+	SystemID     string
+	MinPriority  int
+	Status       string // processed, pending or failed
+	IncidentType string // Incident type extracted by enrichment
+
+	HasTranscription *bool  // Calls with (true) or without (false) transcribed text
+	MinDuration      int    // Seconds
+	MaxDuration      int    // Seconds
+	Keyword          string // Words that must all appear in the transcription or translation
+	MinFrequency     int64  // Hz
+	MaxFrequency     int64  // Hz
+}
+
+// where builds the WHERE clause shared by call listing and counting queries
+func (f CallFilter) where() (string, []interface{}) {
+	where := " WHERE duplicate_of = 0"
 	args := []interface{}{}
 
-	if start != nil {
-		query += " AND timestamp >= ?"
-		args = append(args, start)
+	if f.Start != nil {
+		where += " AND timestamp >= ?"
+		args = append(args, f.Start)
 	}
-	if end != nil {
-		query += " AND timestamp <= ?"
-		args = append(args, end)
+	if f.End != nil {
+		where += " AND timestamp <= ?"
+		args = append(args, f.End)
 	}
-	if talkgroupID != "" {
-		query += " AND talkgroup_id = ?"
-		args = append(args, talkgroupID)
+	if f.TalkgroupID != "" {
+		where += " AND talkgroup_id = ?"
+		args = append(args, f.TalkgroupID)
 	}
+	if f.TalkgroupIDs != nil {
+		where += " AND talkgroup_id IN (" + placeholders(len(f.TalkgroupIDs)) + ")"
+		for _, id := range f.TalkgroupIDs {
+			args = append(args, id)
+		}
+	}
+	if f.MinPriority > 0 {
+		where += " AND priority >= ?"
+		args = append(args, f.MinPriority)
+	}
+	if f.IncidentType != "" {
+		where += " AND json_extract(NULLIF(enrichment, ''), '$.incident_type') = ?"
+		args = append(args, f.IncidentType)
+	}
+	switch f.Status {
+	case CallStatusProcessed:
+		where += " AND processed = TRUE"
+	case CallStatusPending:
+		where += " AND processed = FALSE AND failed = FALSE"
+	case CallStatusFailed:
+		where += " AND processed = FALSE AND failed = TRUE"
+	}
+	if f.HasTranscription != nil {
+		if *f.HasTranscription {
+			where += " AND COALESCE(transcription, '') != ''"
+		} else {
+			where += " AND COALESCE(transcription, '') = ''"
+		}
+	}
+	if f.MinDuration > 0 {
+		where += " AND duration >= ?"
+		args = append(args, f.MinDuration)
+	}
+	if f.MaxDuration > 0 {
+		where += " AND duration <= ?"
+		args = append(args, f.MaxDuration)
+	}
+	if match := matchAll(f.Keyword); match != "" {
+		where += " AND id IN (SELECT docid FROM calls_fts WHERE calls_fts MATCH ?)"
+		args = append(args, match)
+	}
+	if f.MinFrequency > 0 {
+		where += " AND frequency_hz >= ?"
+		args = append(args, f.MinFrequency)
+	}
+	if f.MaxFrequency > 0 {
+		where += " AND frequency_hz BETWEEN 1 AND ?"
+		args = append(args, f.MaxFrequency)
+	}
+	system, systemArgs := systemFilter(f.SystemID)
+	where += system
+	args = append(args, systemArgs...)
 
-	query += " ORDER BY timestamp DESC LIMIT ? OFFSET ?"
-	args = append(args, limit, offset)
+	return where, args
+}
+
+// placeholders returns n comma-separated SQL placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
 
+// queryCalls runs a call listing query and scans the results
+func (d *Database) queryCalls(query string, args ...interface{}) ([]*CallRecord, error) {
 	rows, err := d.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query call records: %w", err)
@@ -351,12 +972,7 @@ func (d *Database) GetCallRecords(start, end *time.Time, talkgroupID string, lim
 	var calls []*CallRecord
 	for rows.Next() {
 		call := &CallRecord{}
-		err := rows.Scan(
-			&call.ID, &call.Filename, &call.Filepath, &call.Timestamp,
-			&call.Duration, &call.Frequency, &call.TalkgroupID,
-			&call.TalkgroupAlias, &call.TalkgroupGroup, &call.TranscriptionID,
-			&call.Transcription, &call.Processed, &call.CreatedAt, &call.UpdatedAt,
-		)
+		err := scanCall(rows, call)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan call record: %w", err)
 		}
@@ -366,25 +982,62 @@ func (d *Database) GetCallRecords(start, end *time.Time, talkgroupID string, lim
 	return calls, nil
 }
 
-// GetCallRecord returns a single call record by ID
-func (d *Database) GetCallRecord(id int) (*CallRecord, error) {
-	query := `
-		SELECT id, filename, filepath, timestamp, duration, frequency, talkgroup_id, 
-		       talkgroup_alias, talkgroup_group, transcription_id, transcription, 
-		       processed, created_at, updated_at
-		FROM calls 
-		WHERE id = ?
-	`
+// GetCallRecords returns call records matching the filter, newest first
+func (d *Database) GetCallRecords(filter CallFilter, limit, offset int) ([]*CallRecord, error) {
+	where, args := filter.where()
+	query := `SELECT ` + callColumns + ` FROM calls` + where + " ORDER BY timestamp DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
 
-	row := d.db.QueryRow(query, id)
-	call := &CallRecord{}
+	return d.queryCalls(query, args...)
+}
 
-	err := row.Scan(
-		&call.ID, &call.Filename, &call.Filepath, &call.Timestamp,
-		&call.Duration, &call.Frequency, &call.TalkgroupID,
-		&call.TalkgroupAlias, &call.TalkgroupGroup, &call.TranscriptionID,
-		&call.Transcription, &call.Processed, &call.CreatedAt, &call.UpdatedAt,
-	)
+// GetCallRecordsByPriority returns call records matching the filter, highest
+// priority first and newest first within a priority
+func (d *Database) GetCallRecordsByPriority(filter CallFilter, limit, offset int) ([]*CallRecord, error) {
+	where, args := filter.where()
+	query := `SELECT ` + callColumns + ` FROM calls` + where + " ORDER BY priority DESC, timestamp DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	return d.queryCalls(query, args...)
+}
+
+// GetCallRecordsAfter returns call records older than the cursor using keyset
+// pagination, which stays fast and stable on deep pages unlike OFFSET
+func (d *Database) GetCallRecordsAfter(filter CallFilter, cursor *CallCursor, limit int) ([]*CallRecord, error) {
+	where, args := filter.where()
+	if cursor != nil {
+		where += " AND (timestamp < ? OR (timestamp = ? AND id < ?))"
+		args = append(args, cursor.Timestamp, cursor.Timestamp, cursor.ID)
+	}
+	query := `SELECT ` + callColumns + ` FROM calls` + where + " ORDER BY timestamp DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	return d.queryCalls(query, args...)
+}
+
+// CountCallRecords returns the number of calls matching the filter
+func (d *Database) CountCallRecords(filter CallFilter) (int64, error) {
+	where, args := filter.where()
+
+	var count int64
+	if err := d.db.QueryRow("SELECT COUNT(*) FROM calls"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count call records: %w", err)
+	}
+	return count, nil
+}
+
+// GetCallRecord returns a single call record by ID
+func (d *Database) GetCallRecord(id int) (*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls 
+		WHERE id = ?
+	`
+
+	row := d.db.QueryRow(query, id)
+	call := &CallRecord{}
+
+	err := scanCall(row, call)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -399,9 +1052,7 @@ func (d *Database) GetCallRecord(id int) (*CallRecord, error) {
 // GetMostRecentCall returns the most recent call record
 func (d *Database) GetMostRecentCall() (*CallRecord, error) {
 	query := `
-		SELECT id, filename, filepath, timestamp, duration, frequency, talkgroup_id, 
-		       talkgroup_alias, talkgroup_group, transcription_id, transcription, 
-		       processed, created_at, updated_at
+		SELECT ` + callColumns + `
 		FROM calls 
 		ORDER BY timestamp DESC 
 		LIMIT 1
@@ -410,12 +1061,7 @@ func (d *Database) GetMostRecentCall() (*CallRecord, error) {
 	row := d.db.QueryRow(query)
 	call := &CallRecord{}
 
-	err := row.Scan(
-		&call.ID, &call.Filename, &call.Filepath, &call.Timestamp,
-		&call.Duration, &call.Frequency, &call.TalkgroupID,
-		&call.TalkgroupAlias, &call.TalkgroupGroup, &call.TranscriptionID,
-		&call.Transcription, &call.Processed, &call.CreatedAt, &call.UpdatedAt,
-	)
+	err := scanCall(row, call)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -427,80 +1073,39 @@ func (d *Database) GetMostRecentCall() (*CallRecord, error) {
 	return call, nil
 }
 
-// GetCallStats returns aggregated call statistics for a time range
-func (d *Database) GetCallStats(start, end *time.Time) (map[string]interface{}, error) {
-	query := `
-		SELECT 
-			COUNT(*) as total_calls,
-			AVG(duration) as avg_duration,
-			SUM(duration) as total_duration,
-			COUNT(DISTINCT talkgroup_id) as unique_talkgroups,
-			COUNT(DISTINCT frequency) as unique_frequencies
-		FROM calls 
-		WHERE 1=1
-	`
-	args := []interface{}{}
-
-	if start != nil {
-		query += " AND timestamp >= ?"
-		args = append(args, start)
-	}
-	if end != nil {
-		query += " AND timestamp <= ?"
-		args = append(args, end)
-	}
-
-	var totalCalls int64
-	var avgDuration, totalDuration sql.NullFloat64
-	var uniqueTalkgroups, uniqueFrequencies int64
-
-	err := d.db.QueryRow(query, args...).Scan(
-		&totalCalls, &avgDuration, &totalDuration, &uniqueTalkgroups, &uniqueFrequencies,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get call stats: %w", err)
-	}
-
-	stats := map[string]interface{}{
-		"total_calls":        totalCalls,
-		"avg_duration":       avgDuration.Float64,
-		"total_duration":     totalDuration.Float64,
-		"unique_talkgroups":  uniqueTalkgroups,
-		"unique_frequencies": uniqueFrequencies,
-	}
-
-	return stats, nil
-}
-
-// GetTotalCallCount returns the total number of calls
-func (d *Database) GetTotalCallCount() (int64, error) {
+// GetTotalCallCount returns the total number of calls, optionally for one system
+func (d *Database) GetTotalCallCount(systemID string) (int64, error) {
+	system, args := systemFilter(systemID)
 	var count int64
-	err := d.db.QueryRow("SELECT COUNT(*) FROM calls").Scan(&count)
+	err := d.db.QueryRow("SELECT COALESCE(SUM(call_count), 0) FROM call_rollups_daily WHERE 1=1"+system, args...).Scan(&count)
 	return count, err
 }
 
-// GetLastCallTime returns the timestamp of the most recent call
-func (d *Database) GetLastCallTime() (*time.Time, error) {
+// GetLastCallTime returns the timestamp of the most recent call, optionally for one system
+func (d *Database) GetLastCallTime(systemID string) (*time.Time, error) {
+	system, args := systemFilter(systemID)
 	var timestamp *time.Time
-	err := d.db.QueryRow("SELECT MAX(timestamp) FROM calls").Scan(&timestamp)
+	err := d.db.QueryRow("SELECT MAX(timestamp) FROM calls WHERE 1=1"+system, args...).Scan(&timestamp)
 	if err != nil {
 		return nil, err
 	}
 	return timestamp, nil
 }
 
-// GetCallsToday returns the number of calls today
-func (d *Database) GetCallsToday() (int64, error) {
-	today := time.Now().Format("2006-01-02")
+// GetCallsToday returns the number of calls today, optionally for one system
+func (d *Database) GetCallsToday(systemID string) (int64, error) {
+	system, systemArgs := systemFilter(systemID)
+	args := append([]interface{}{time.Now().Format("2006-01-02")}, systemArgs...)
 	var count int64
-	err := d.db.QueryRow("SELECT COUNT(*) FROM calls WHERE DATE(timestamp) = ?", today).Scan(&count)
+	err := d.db.QueryRow("SELECT COALESCE(SUM(call_count), 0) FROM call_rollups_daily WHERE day = ?"+system, args...).Scan(&count)
 	return count, err
 }
 
-// GetFrequencyStats returns frequency usage statistics
-func (d *Database) GetFrequencyStats() (map[string]int64, error) {
-	query := "SELECT frequency, COUNT(*) FROM calls WHERE frequency IS NOT NULL GROUP BY frequency"
-	rows, err := d.db.Query(query)
+// GetFrequencyStats returns frequency usage statistics, optionally for one system
+func (d *Database) GetFrequencyStats(systemID string) (map[string]int64, error) {
+	system, args := systemFilter(systemID)
+	query := "SELECT frequency, SUM(call_count) FROM call_rollups_daily WHERE frequency != ''" + system + " GROUP BY frequency"
+	rows, err := d.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -519,10 +1124,11 @@ func (d *Database) GetFrequencyStats() (map[string]int64, error) {
 	return stats, nil
 }
 
-// GetTalkgroupStats returns talkgroup usage statistics
-func (d *Database) GetTalkgroupStats() (map[string]int64, error) {
-	query := "SELECT talkgroup_alias, COUNT(*) FROM calls WHERE talkgroup_alias IS NOT NULL GROUP BY talkgroup_alias"
-	rows, err := d.db.Query(query)
+// GetTalkgroupStats returns talkgroup usage statistics, optionally for one system
+func (d *Database) GetTalkgroupStats(systemID string) (map[string]int64, error) {
+	system, args := systemFilter(systemID)
+	query := "SELECT talkgroup_alias, SUM(call_count) FROM call_rollups_daily WHERE 1=1" + system + " GROUP BY talkgroup_alias"
+	rows, err := d.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -541,23 +1147,152 @@ func (d *Database) GetTalkgroupStats() (map[string]int64, error) {
 	return stats, nil
 }
 
+// GetTalkgroupStatsForRange returns talkgroup usage statistics within a time range
+func (d *Database) GetTalkgroupStatsForRange(start, end time.Time) (map[string]int64, error) {
+	query := `
+		SELECT COALESCE(NULLIF(talkgroup_alias, ''), talkgroup_id), COUNT(*)
+		FROM calls
+		WHERE timestamp >= ? AND timestamp < ? AND duplicate_of = 0
+		GROUP BY 1
+	`
+	rows, err := d.db.Query(query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query talkgroup stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]int64)
+	for rows.Next() {
+		var talkgroup string
+		var count int64
+		if err := rows.Scan(&talkgroup, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan talkgroup stats: %w", err)
+		}
+		stats[talkgroup] = count
+	}
+
+	return stats, nil
+}
+
+// GetAgencyStatsForRange returns call counts per talkgroup group (agency) within a time range
+func (d *Database) GetAgencyStatsForRange(start, end time.Time) (map[string]int64, error) {
+	query := `
+		SELECT COALESCE(NULLIF(talkgroup_group, ''), 'Unassigned'), COUNT(*)
+		FROM calls
+		WHERE timestamp >= ? AND timestamp < ? AND duplicate_of = 0
+		GROUP BY 1
+	`
+	rows, err := d.db.Query(query, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query agency stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]int64)
+	for rows.Next() {
+		var agency string
+		var count int64
+		if err := rows.Scan(&agency, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan agency stats: %w", err)
+		}
+		stats[agency] = count
+	}
+
+	return stats, nil
+}
+
+// GetHourlyCallCounts returns the number of calls in each local hour of a time range
+func (d *Database) GetHourlyCallCounts(start, end time.Time) ([24]int64, error) {
+	var counts [24]int64
+
+	rows, err := d.db.Query(`SELECT timestamp FROM calls WHERE timestamp >= ? AND timestamp < ? AND duplicate_of = 0`, start, end)
+	if err != nil {
+		return counts, fmt.Errorf("failed to query call timestamps: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var timestamp time.Time
+		if err := rows.Scan(&timestamp); err != nil {
+			return counts, fmt.Errorf("failed to scan call timestamp: %w", err)
+		}
+		counts[timestamp.In(start.Location()).Hour()]++
+	}
+
+	return counts, nil
+}
+
+// GetNotableCalls returns calls at or above a severity level, most severe first
+func (d *Database) GetNotableCalls(start, end time.Time, minSeverity, limit int) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE timestamp >= ? AND timestamp < ? AND severity >= ? AND duplicate_of = 0
+		ORDER BY severity DESC, timestamp ASC
+		LIMIT ?
+	`
+
+	rows, err := d.db.Query(query, start, end, minSeverity, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notable calls: %w", err)
+	}
+	defer rows.Close()
+
+	var calls []*CallRecord
+	for rows.Next() {
+		call := &CallRecord{}
+		if err := scanCall(rows, call); err != nil {
+			return nil, fmt.Errorf("failed to scan call record: %w", err)
+		}
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+// GetKeywordHits returns calls whose transcription or translation contains any
+// of the keywords (case-insensitive), oldest first
+func (d *Database) GetKeywordHits(start, end time.Time, keywords []string, limit int) ([]*CallRecord, error) {
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+
+	conditions := make([]string, len(keywords))
+	args := []interface{}{start, end}
+	for i, keyword := range keywords {
+		conditions[i] = "(LOWER(transcription) LIKE ? OR LOWER(translation) LIKE ?)"
+		pattern := "%" + strings.ToLower(keyword) + "%"
+		args = append(args, pattern, pattern)
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE timestamp >= ? AND timestamp < ? AND duplicate_of = 0 AND (` + strings.Join(conditions, " OR ") + `)
+		ORDER BY timestamp ASC
+		LIMIT ?
+	`
+
+	return d.queryCalls(query, args...)
+}
+
 // GetLifetimeStats returns comprehensive lifetime statistics
 func (d *Database) GetLifetimeStats() (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Total calls
-	totalCalls, _ := d.GetTotalCallCount()
+	totalCalls, _ := d.GetTotalCallCount("")
 	stats["total_calls"] = totalCalls
 
-	// Total duration
+	// Total and average duration
 	var totalDuration sql.NullFloat64
-	d.db.QueryRow("SELECT SUM(duration) FROM calls").Scan(&totalDuration)
+	d.db.QueryRow("SELECT SUM(total_duration) FROM call_rollups_daily").Scan(&totalDuration)
 	stats["total_duration"] = totalDuration.Float64
-
-	// Average duration
-	var avgDuration sql.NullFloat64
-	d.db.QueryRow("SELECT AVG(duration) FROM calls").Scan(&avgDuration)
-	stats["avg_duration"] = avgDuration.Float64
+	stats["avg_duration"] = 0.0
+	if totalCalls > 0 {
+		stats["avg_duration"] = totalDuration.Float64 / float64(totalCalls)
+	}
 
 	// First and last call
 	var firstCall, lastCall *time.Time
@@ -568,8 +1303,8 @@ func (d *Database) GetLifetimeStats() (map[string]interface{}, error) {
 
 	// Unique talkgroups and frequencies
 	var uniqueTalkgroups, uniqueFrequencies int64
-	d.db.QueryRow("SELECT COUNT(DISTINCT talkgroup_id) FROM calls").Scan(&uniqueTalkgroups)
-	d.db.QueryRow("SELECT COUNT(DISTINCT frequency) FROM calls").Scan(&uniqueFrequencies)
+	d.db.QueryRow("SELECT COUNT(DISTINCT talkgroup_id) FROM call_rollups_daily").Scan(&uniqueTalkgroups)
+	d.db.QueryRow("SELECT COUNT(DISTINCT frequency) FROM call_rollups_daily WHERE frequency != ''").Scan(&uniqueFrequencies)
 	stats["unique_talkgroups"] = uniqueTalkgroups
 	stats["unique_frequencies"] = uniqueFrequencies
 
@@ -599,6 +1334,26 @@ func (d *Database) DeleteOldCalls(daysOld int) (int, error) {
 	return int(rows), nil
 }
 
+// DeleteCalls deletes calls in a single transaction, taking them out of the
+// statistics rollups. Duplicates were taken out when they were linked.
+func (d *Database) DeleteCalls(calls []*CallRecord) error {
+	return d.withTx(func(tx *sql.Tx) error {
+		for _, call := range calls {
+			if _, err := tx.Exec("DELETE FROM calls WHERE id = ?", call.ID); err != nil {
+				return fmt.Errorf("failed to delete call %d: %w", call.ID, err)
+			}
+			if call.DuplicateOf == 0 {
+				if err := removeFromRollups(tx, call); err != nil {
+					return err
+				}
+			}
+		}
+
+		d.logger.Info("Database", "Deleted calls", "count", len(calls))
+		return nil
+	})
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	if d.db != nil {
@@ -618,6 +1373,49 @@ func (d *Database) BeginTransaction() (*sql.Tx, error) {
 	return d.db.Begin()
 }
 
+// Vacuum rebuilds the database file to reclaim space left by deleted rows
+func (d *Database) Vacuum() error {
+	if _, err := d.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	// Fold the write-ahead log back into the main file
+	if _, err := d.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+	return nil
+}
+
+// TableCounts returns the number of rows in each table
+func (d *Database) TableCounts() (map[string]int64, error) {
+	rows, err := d.db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var count int64
+		if err := d.db.QueryRow(`SELECT COUNT(*) FROM "` + table + `"`).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
 // FileExists checks if a file has already been processed
 func (d *Database) FileExists(filepath string) (bool, error) {
 	var count int
@@ -628,6 +1426,45 @@ func (d *Database) FileExists(filepath string) (bool, error) {
 	return count > 0, nil
 }
 
+// GetCallByContentHash returns the call whose recording had the given
+// SHA-256, or nil if no call has it
+func (d *Database) GetCallByContentHash(hash string) (*CallRecord, error) {
+	query := `SELECT ` + callColumns + ` FROM calls WHERE content_hash = ? ORDER BY id LIMIT 1`
+	call := &CallRecord{}
+	err := scanCall(d.db.QueryRow(query, hash), call)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call by content hash: %w", err)
+	}
+	return call, nil
+}
+
+// GetCallsWithoutContentHash returns up to limit calls after afterID, in ID
+// order, whose recording was stored before content hashes were. Calls whose
+// recording was deleted locally or found missing are left out.
+func (d *Database) GetCallsWithoutContentHash(afterID, limit int) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE id > ? AND COALESCE(content_hash, '') = ''
+		  AND local_deleted = FALSE AND audio_missing = FALSE
+		ORDER BY id
+		LIMIT ?
+	`
+
+	return d.queryCalls(query, afterID, limit)
+}
+
+// SetContentHash stores the SHA-256 of a call's recording
+func (d *Database) SetContentHash(id int, hash string) error {
+	if _, err := d.db.Exec("UPDATE calls SET content_hash = ? WHERE id = ?", hash, id); err != nil {
+		return fmt.Errorf("failed to set content hash: %w", err)
+	}
+	return nil
+}
+
 // Hour Summary Management Functions
 
 // GetHourSummary returns an existing hour summary if it exists
@@ -731,3 +1568,249 @@ func (d *Database) DeleteOldHourSummaries(daysOld int) (int, error) {
 	d.logger.Debug("Database", "Deleted old hour summaries", "count", rows, "cutoff", cutoff)
 	return int(rows), nil
 }
+
+// Summary Cache Management Functions
+
+// summaryColumns is the column list matching scanSummary
+const summaryColumns = `id, cache_key, scope, start_time, end_time, prompt, summary, call_count,
+		       categories, generated_at, expires_at`
+
+// scanSummary scans a row selected with summaryColumns into a summary
+func scanSummary(row rowScanner) (*Summary, error) {
+	summary := &Summary{}
+	var prompt, categories sql.NullString
+	err := row.Scan(
+		&summary.ID, &summary.CacheKey, &summary.Scope, &summary.StartTime, &summary.EndTime,
+		&prompt, &summary.Summary, &summary.CallCount, &categories,
+		&summary.GeneratedAt, &summary.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Prompt = prompt.String
+	summary.Categories = categories.String
+	return summary, nil
+}
+
+// GetSummaryByKey returns a cached summary if it exists and has not expired
+func (d *Database) GetSummaryByKey(cacheKey string) (*Summary, error) {
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM summaries
+		WHERE cache_key = ? AND (expires_at IS NULL OR expires_at > ?)
+	`
+
+	summary, err := scanSummary(d.db.QueryRow(query, cacheKey, time.Now()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No cached summary
+		}
+		return nil, fmt.Errorf("failed to get summary: %w", err)
+	}
+	return summary, nil
+}
+
+// FindSummary returns the newest unexpired summary of a scope for exactly
+// this range and prompt, whichever model generated it
+func (d *Database) FindSummary(scope string, start, end time.Time, prompt string) (*Summary, error) {
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM summaries
+		WHERE scope = ? AND start_time = ? AND end_time = ? AND COALESCE(prompt, '') = ?
+		  AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY generated_at DESC
+		LIMIT 1
+	`
+
+	summary, err := scanSummary(d.db.QueryRow(query, scope, start, end, prompt, time.Now()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find summary: %w", err)
+	}
+	return summary, nil
+}
+
+// GetLatestSummary returns the most recently generated summary of a scope
+func (d *Database) GetLatestSummary(scope string) (*Summary, error) {
+	query := `SELECT ` + summaryColumns + ` FROM summaries WHERE scope = ? ORDER BY generated_at DESC LIMIT 1`
+
+	summary, err := scanSummary(d.db.QueryRow(query, scope))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest summary: %w", err)
+	}
+	return summary, nil
+}
+
+// GetSummaries returns unexpired summaries overlapping a time range, newest
+// range first. An empty scope matches every scope.
+func (d *Database) GetSummaries(scope string, start, end time.Time, limit int) ([]*Summary, error) {
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM summaries
+		WHERE start_time < ? AND end_time > ? AND (? = '' OR scope = ?)
+		  AND (expires_at IS NULL OR expires_at > ?)
+		ORDER BY start_time DESC, generated_at DESC
+		LIMIT ?
+	`
+
+	rows, err := d.db.Query(query, end, start, scope, scope, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []*Summary{}
+	for rows.Next() {
+		summary, err := scanSummary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan summary: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// UpsertSummary stores a summary, replacing any existing entry with the same cache key
+func (d *Database) UpsertSummary(summary *Summary) error {
+	query := `
+		INSERT INTO summaries (cache_key, scope, start_time, end_time, prompt, summary, call_count, categories, generated_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(cache_key) DO UPDATE SET
+			summary = excluded.summary,
+			call_count = excluded.call_count,
+			categories = excluded.categories,
+			generated_at = excluded.generated_at,
+			expires_at = excluded.expires_at
+	`
+
+	_, err := d.db.Exec(query,
+		summary.CacheKey, summary.Scope, summary.StartTime, summary.EndTime, summary.Prompt,
+		summary.Summary, summary.CallCount, summary.Categories, summary.GeneratedAt, summary.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert summary: %w", err)
+	}
+
+	d.logger.Debug("Database", "Stored summary", "key", summary.CacheKey, "scope", summary.Scope)
+	return nil
+}
+
+// DeleteExpiredSummaries removes cached summaries past their expiry
+func (d *Database) DeleteExpiredSummaries() (int, error) {
+	result, err := d.db.Exec("DELETE FROM summaries WHERE expires_at IS NOT NULL AND expires_at <= ?", time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired summaries: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+// Correction Rule Management Functions
+
+// GetCorrectionRules returns all correction rules in insertion order
+func (d *Database) GetCorrectionRules() ([]*CorrectionRule, error) {
+	query := `
+		SELECT id, pattern, replacement, is_regex, talkgroup_id, enabled, created_at
+		FROM correction_rules
+		ORDER BY id ASC
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query correction rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*CorrectionRule
+	for rows.Next() {
+		rule := &CorrectionRule{}
+		if err := rows.Scan(&rule.ID, &rule.Pattern, &rule.Replacement, &rule.IsRegex,
+			&rule.TalkgroupID, &rule.Enabled, &rule.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan correction rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return rules, nil
+}
+
+// InsertCorrectionRule creates a new correction rule
+func (d *Database) InsertCorrectionRule(rule *CorrectionRule) error {
+	query := `
+		INSERT INTO correction_rules (pattern, replacement, is_regex, talkgroup_id, enabled)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := d.db.Exec(query, rule.Pattern, rule.Replacement, rule.IsRegex, rule.TalkgroupID, rule.Enabled)
+	if err != nil {
+		return fmt.Errorf("failed to insert correction rule: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	rule.ID = int(id)
+	rule.CreatedAt = time.Now()
+	d.logger.Debug("Database", "Inserted correction rule", "id", id, "pattern", rule.Pattern)
+	return nil
+}
+
+// UpdateCorrectionRule updates an existing correction rule
+func (d *Database) UpdateCorrectionRule(rule *CorrectionRule) error {
+	query := `
+		UPDATE correction_rules
+		SET pattern = ?, replacement = ?, is_regex = ?, talkgroup_id = ?, enabled = ?
+		WHERE id = ?
+	`
+
+	result, err := d.db.Exec(query, rule.Pattern, rule.Replacement, rule.IsRegex, rule.TalkgroupID, rule.Enabled, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update correction rule: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("no correction rule found with ID %d", rule.ID)
+	}
+
+	return nil
+}
+
+// DeleteCorrectionRule removes a correction rule
+func (d *Database) DeleteCorrectionRule(id int) error {
+	result, err := d.db.Exec("DELETE FROM correction_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete correction rule: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("no correction rule found with ID %d", id)
+	}
+
+	return nil
+}
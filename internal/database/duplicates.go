@@ -0,0 +1,38 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetSimulcastCandidates returns processed calls on the same system and
+// talkgroup that started within window of a call and are not duplicates
+// themselves
+func (d *Database) GetSimulcastCandidates(call *CallRecord, window time.Duration) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE id != ? AND talkgroup_id = ? AND COALESCE(system_id, '') = ?
+		  AND timestamp >= ? AND timestamp <= ?
+		  AND processed = TRUE AND duplicate_of = 0
+		ORDER BY timestamp ASC, id ASC
+	`
+
+	calls, err := d.queryCalls(query, call.ID, call.TalkgroupID, call.SystemID,
+		call.Timestamp.Add(-window), call.Timestamp.Add(window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find simulcast candidates: %w", err)
+	}
+	return calls, nil
+}
+
+// GetDuplicateCalls returns the calls linked as simulcast duplicates of a call
+func (d *Database) GetDuplicateCalls(id int) ([]*CallRecord, error) {
+	query := `SELECT ` + callColumns + ` FROM calls WHERE duplicate_of = ? ORDER BY timestamp ASC, id ASC`
+
+	calls, err := d.queryCalls(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duplicate calls: %w", err)
+	}
+	return calls, nil
+}
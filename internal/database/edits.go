@@ -0,0 +1,121 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AudioClassNoise marks a call flagged by hand as noise rather than speech
+const AudioClassNoise = "noise"
+
+// CallEdit records one field of a call corrected by hand
+type CallEdit struct {
+	ID        int       `json:"id"`
+	CallID    int       `json:"call_id"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	Editor    string    `json:"editor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EditCall stores the corrected fields of a call and records each change in
+// the audit log, in a single transaction. edited holds the corrected copy of
+// call; fields that are the same in both are left alone. A talkgroup change
+// moves the call's statistics to the new talkgroup.
+func (d *Database) EditCall(call, edited *CallRecord, editor string) ([]*CallEdit, error) {
+	fields := []struct {
+		column   string
+		old, new string
+	}{
+		{"transcription", call.Transcription, edited.Transcription},
+		{"talkgroup_id", call.TalkgroupID, edited.TalkgroupID},
+		{"talkgroup_alias", call.TalkgroupAlias, edited.TalkgroupAlias},
+		{"talkgroup_group", call.TalkgroupGroup, edited.TalkgroupGroup},
+		{"audio_class", call.AudioClass, edited.AudioClass},
+	}
+
+	var edits []*CallEdit
+	now := time.Now()
+	err := d.withTx(func(tx *sql.Tx) error {
+		for _, field := range fields {
+			if field.old == field.new {
+				continue
+			}
+			if _, err := tx.Exec("UPDATE calls SET "+field.column+" = ? WHERE id = ?", field.new, call.ID); err != nil {
+				return fmt.Errorf("failed to update %s: %w", field.column, err)
+			}
+
+			edit := &CallEdit{
+				CallID:    call.ID,
+				Field:     field.column,
+				OldValue:  field.old,
+				NewValue:  field.new,
+				Editor:    editor,
+				CreatedAt: now,
+			}
+			result, err := tx.Exec(`
+				INSERT INTO call_edits (call_id, field, old_value, new_value, editor, created_at)
+				VALUES (?, ?, ?, ?, ?, ?)
+			`, edit.CallID, edit.Field, edit.OldValue, edit.NewValue, edit.Editor, edit.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to record call edit: %w", err)
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get call edit ID: %w", err)
+			}
+			edit.ID = int(id)
+			edits = append(edits, edit)
+		}
+
+		// Duplicates were taken out of the rollups when they were linked
+		if call.TalkgroupID != edited.TalkgroupID && call.DuplicateOf == 0 {
+			if err := removeFromRollups(tx, call); err != nil {
+				return err
+			}
+			if err := addToRollups(tx, edited.Timestamp, edited.SystemID, edited.TalkgroupID, edited.TalkgroupAlias,
+				edited.Frequency, 1, int64(edited.Duration)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.Debug("Database", "Edited call record", "id", call.ID, "fields", len(edits), "editor", editor)
+	return edits, nil
+}
+
+// GetCallEdits returns the audit log of a call's manual corrections, oldest first
+func (d *Database) GetCallEdits(callID int) ([]*CallEdit, error) {
+	rows, err := d.db.Query(`
+		SELECT id, call_id, field, old_value, new_value, editor, created_at
+		FROM call_edits
+		WHERE call_id = ?
+		ORDER BY id ASC
+	`, callID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query call edits: %w", err)
+	}
+	defer rows.Close()
+
+	var edits []*CallEdit
+	for rows.Next() {
+		edit := &CallEdit{}
+		if err := rows.Scan(&edit.ID, &edit.CallID, &edit.Field, &edit.OldValue, &edit.NewValue,
+			&edit.Editor, &edit.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan call edit: %w", err)
+		}
+		edits = append(edits, edit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return edits, nil
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// CallEmbedding is the embedding vector of a call's transcription
+type CallEmbedding struct {
+	CallID    int
+	Timestamp time.Time
+	Vector    []float32
+}
+
+// encodeVector packs a vector as little-endian float32s
+func encodeVector(vector []float32) []byte {
+	data := make([]byte, len(vector)*4)
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(value))
+	}
+	return data
+}
+
+// decodeVector unpacks a vector stored by encodeVector
+func decodeVector(data []byte) []float32 {
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return vector
+}
+
+// StoreEmbedding saves a call's embedding, replacing one from another model
+func (d *Database) StoreEmbedding(callID int, model string, vector []float32) error {
+	_, err := d.db.Exec(`
+		INSERT INTO call_embeddings (call_id, model, vector, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(call_id) DO UPDATE SET
+			model = excluded.model,
+			vector = excluded.vector,
+			created_at = excluded.created_at
+	`, callID, model, encodeVector(vector), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to store embedding for call %d: %w", callID, err)
+	}
+	return nil
+}
+
+// GetEmbedding returns a call's embedding from a model, or nil if it has none
+func (d *Database) GetEmbedding(callID int, model string) ([]float32, error) {
+	var data []byte
+	err := d.db.QueryRow("SELECT vector FROM call_embeddings WHERE call_id = ? AND model = ?", callID, model).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embedding for call %d: %w", callID, err)
+	}
+	return decodeVector(data), nil
+}
+
+// GetEmbeddings returns the embeddings from a model of the calls in a time
+// range, oldest first. Simulcast duplicates are left out.
+func (d *Database) GetEmbeddings(model string, start, end time.Time) ([]CallEmbedding, error) {
+	rows, err := d.db.Query(`
+		SELECT e.call_id, c.timestamp, e.vector
+		FROM call_embeddings e
+		JOIN calls c ON c.id = e.call_id
+		WHERE e.model = ? AND c.timestamp >= ? AND c.timestamp < ? AND c.duplicate_of = 0
+		ORDER BY c.timestamp ASC
+	`, model, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var embeddings []CallEmbedding
+	for rows.Next() {
+		var embedding CallEmbedding
+		var data []byte
+		if err := rows.Scan(&embedding.CallID, &embedding.Timestamp, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+		embedding.Vector = decodeVector(data)
+		embeddings = append(embeddings, embedding)
+	}
+
+	return embeddings, rows.Err()
+}
+
+// GetCallsWithoutEmbedding returns transcribed calls with no embedding from
+// a model, newest first
+func (d *Database) GetCallsWithoutEmbedding(model string, limit int) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE processed = TRUE AND duplicate_of = 0 AND COALESCE(transcription, '') != ''
+		  AND NOT EXISTS (SELECT 1 FROM call_embeddings e WHERE e.call_id = calls.id AND e.model = ?)
+		ORDER BY timestamp DESC
+		LIMIT ?
+	`
+
+	return d.queryCalls(query, model, limit)
+}
+
+// GetCallRecordsByIDs returns the calls with the given IDs in the same order.
+// IDs with no call are skipped.
+func (d *Database) GetCallRecordsByIDs(ids []int) ([]*CallRecord, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	calls, err := d.queryCalls(`
+		SELECT `+callColumns+`
+		FROM calls
+		WHERE id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*CallRecord, len(calls))
+	for _, call := range calls {
+		byID[call.ID] = call
+	}
+	ordered := make([]*CallRecord, 0, len(calls))
+	for _, id := range ids {
+		if call, ok := byID[id]; ok {
+			ordered = append(ordered, call)
+		}
+	}
+	return ordered, nil
+}
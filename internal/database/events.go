@@ -0,0 +1,111 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// System event types
+const (
+	EventStartup  = "startup"
+	EventShutdown = "shutdown"
+	EventSDRTrunk = "sdrtrunk" // SDRTrunk process exits, restarts and hangs
+	EventAlert    = "alert"    // Monitoring threshold breaches and recoveries
+	EventDevice   = "device"   // USB receivers disappearing and returning
+	EventStorage  = "storage"  // Low disk space warnings and cleanups
+	EventKeyword  = "keyword"  // Calls that matched a push alert rule
+	EventAnomaly  = "anomaly"  // Talkgroups far busier than normal for the time of day
+)
+
+// System event levels
+const (
+	EventInfo    = "info"
+	EventWarning = "warning"
+	EventError   = "error"
+)
+
+// SystemEvent is something that happened to Meiko itself rather than on the radio
+type SystemEvent struct {
+	ID        int       `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Level     string    `json:"level"`
+	Source    string    `json:"source,omitempty"` // System ID, device, metric or alert rule the event concerns
+	Message   string    `json:"message"`
+	CallID    int       `json:"call_id,omitempty"` // Call that raised the event, for keyword alerts
+}
+
+// InsertSystemEvent records a system event, timestamping it now if unset
+func (d *Database) InsertSystemEvent(event *SystemEvent) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	result, err := d.db.Exec(
+		"INSERT INTO system_events (timestamp, type, level, source, message, call_id) VALUES (?, ?, ?, ?, ?, ?)",
+		event.Timestamp, event.Type, event.Level, event.Source, event.Message, event.CallID)
+	if err != nil {
+		return fmt.Errorf("failed to insert system event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	event.ID = int(id)
+	d.logger.Debug("Database", "Inserted system event", "id", id, "type", event.Type)
+	return nil
+}
+
+// GetSystemEvents returns system events in a time range, newest first. A
+// cursor holding the timestamp and ID of the last event already seen pages
+// on from there.
+func (d *Database) GetSystemEvents(start, end *time.Time, cursor *CallCursor, limit int) ([]*SystemEvent, error) {
+	query := "SELECT id, timestamp, type, level, source, message, call_id FROM system_events WHERE 1=1"
+	var args []interface{}
+
+	if cursor != nil {
+		query += " AND (timestamp < ? OR (timestamp = ? AND id < ?))"
+		args = append(args, cursor.Timestamp, cursor.Timestamp, cursor.ID)
+	}
+
+	if start != nil {
+		query += " AND timestamp >= ?"
+		args = append(args, *start)
+	}
+	if end != nil {
+		query += " AND timestamp <= ?"
+		args = append(args, *end)
+	}
+
+	query += " ORDER BY timestamp DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query system events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*SystemEvent
+	for rows.Next() {
+		event := &SystemEvent{}
+		var source sql.NullString
+		if err := rows.Scan(&event.ID, &event.Timestamp, &event.Type, &event.Level, &source, &event.Message, &event.CallID); err != nil {
+			return nil, fmt.Errorf("failed to scan system event: %w", err)
+		}
+		event.Source = source.String
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Frequency is a known frequency with a label for display
+type Frequency struct {
+	ID        int       `json:"id"`
+	Frequency int64     `json:"frequency"` // Hz
+	Label     string    `json:"label"`
+	SystemID  string    `json:"system_id"` // Empty applies to every system
+	Mode      string    `json:"mode"`      // P25, DMR, FM, ...
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const frequencyColumns = `id, frequency, label, system_id, mode, created_at`
+
+// scanFrequency scans a row selected with frequencyColumns
+func scanFrequency(row rowScanner, frequency *Frequency) error {
+	return row.Scan(&frequency.ID, &frequency.Frequency, &frequency.Label, &frequency.SystemID,
+		&frequency.Mode, &frequency.CreatedAt)
+}
+
+// GetFrequencies returns all known frequencies in ascending order
+func (d *Database) GetFrequencies() ([]*Frequency, error) {
+	rows, err := d.db.Query("SELECT " + frequencyColumns + " FROM frequencies ORDER BY frequency ASC, system_id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query frequencies: %w", err)
+	}
+	defer rows.Close()
+
+	var frequencies []*Frequency
+	for rows.Next() {
+		frequency := &Frequency{}
+		if err := scanFrequency(rows, frequency); err != nil {
+			return nil, fmt.Errorf("failed to scan frequency: %w", err)
+		}
+		frequencies = append(frequencies, frequency)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return frequencies, nil
+}
+
+// LookupFrequency returns the entry for a frequency on a system, falling back
+// to an entry for every system. It returns nil if the frequency is unknown.
+func (d *Database) LookupFrequency(hz int64, systemID string) (*Frequency, error) {
+	frequency := &Frequency{}
+	err := scanFrequency(d.db.QueryRow(`
+		SELECT `+frequencyColumns+` FROM frequencies
+		WHERE frequency = ? AND system_id IN (?, '')
+		ORDER BY system_id DESC
+		LIMIT 1
+	`, hz, systemID), frequency)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up frequency: %w", err)
+	}
+	return frequency, nil
+}
+
+// InsertFrequency adds a known frequency
+func (d *Database) InsertFrequency(frequency *Frequency) error {
+	frequency.CreatedAt = time.Now()
+	result, err := d.db.Exec(
+		"INSERT INTO frequencies (frequency, label, system_id, mode, created_at) VALUES (?, ?, ?, ?, ?)",
+		frequency.Frequency, frequency.Label, frequency.SystemID, frequency.Mode, frequency.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert frequency: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	frequency.ID = int(id)
+	d.logger.Debug("Database", "Inserted frequency", "id", id, "frequency", frequency.Frequency)
+	return nil
+}
+
+// UpdateFrequency updates a known frequency
+func (d *Database) UpdateFrequency(frequency *Frequency) error {
+	result, err := d.db.Exec(
+		"UPDATE frequencies SET frequency = ?, label = ?, system_id = ?, mode = ? WHERE id = ?",
+		frequency.Frequency, frequency.Label, frequency.SystemID, frequency.Mode, frequency.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update frequency: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("no frequency found with ID %d", frequency.ID)
+	}
+
+	return nil
+}
+
+// DeleteFrequency removes a known frequency
+func (d *Database) DeleteFrequency(id int) error {
+	result, err := d.db.Exec("DELETE FROM frequencies WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete frequency: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("no frequency found with ID %d", id)
+	}
+
+	return nil
+}
+
+// FrequencyActivity is how many calls were heard on a frequency
+type FrequencyActivity struct {
+	Frequency int64 `json:"frequency_hz"`
+	Calls     int64 `json:"calls"`
+}
+
+// GetActiveFrequencies returns the frequencies calls were heard on since a
+// time, optionally on one system, the most recently heard first
+func (d *Database) GetActiveFrequencies(since time.Time, systemID string) ([]FrequencyActivity, error) {
+	system, systemArgs := systemFilter(systemID)
+	args := append([]interface{}{since}, systemArgs...)
+	rows, err := d.db.Query(`
+		SELECT frequency_hz, COUNT(*) FROM calls
+		WHERE frequency_hz > 0 AND duplicate_of = 0 AND timestamp >= ?`+system+`
+		GROUP BY frequency_hz
+		ORDER BY MAX(timestamp) DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active frequencies: %w", err)
+	}
+	defer rows.Close()
+
+	active := []FrequencyActivity{}
+	for rows.Next() {
+		var activity FrequencyActivity
+		if err := rows.Scan(&activity.Frequency, &activity.Calls); err != nil {
+			return nil, fmt.Errorf("failed to scan active frequency: %w", err)
+		}
+		active = append(active, activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return active, nil
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// UserPreferences are the dashboard settings saved for an API key or browser
+type UserPreferences struct {
+	Owner       string    `json:"-"`
+	Preferences string    `json:"-"` // JSON object, opaque to the server
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// GetPreferences returns the preferences saved for an owner, or nil if none are
+func (d *Database) GetPreferences(owner string) (*UserPreferences, error) {
+	prefs := &UserPreferences{Owner: owner}
+	err := d.db.QueryRow("SELECT preferences, updated_at FROM user_preferences WHERE owner = ?", owner).
+		Scan(&prefs.Preferences, &prefs.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// SavePreferences creates or replaces an owner's preferences
+func (d *Database) SavePreferences(prefs *UserPreferences) error {
+	prefs.UpdatedAt = time.Now()
+	_, err := d.db.Exec(`
+		INSERT INTO user_preferences (owner, preferences, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(owner) DO UPDATE SET
+			preferences = excluded.preferences,
+			updated_at = excluded.updated_at
+	`, prefs.Owner, prefs.Preferences, prefs.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save preferences: %w", err)
+	}
+
+	d.logger.Debug("Database", "Saved preferences")
+	return nil
+}
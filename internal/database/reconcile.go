@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CallFile is where a call's recording is kept
+type CallFile struct {
+	ID           int
+	Filepath     string
+	StorageKey   string // Set once the audio is in object storage
+	LocalDeleted bool   // Removed on purpose after archiving
+	AudioMissing bool
+}
+
+// GetCallFiles returns the recordings of calls after an ID, in ID order, for
+// walking every call in batches
+func (d *Database) GetCallFiles(afterID, limit int) ([]CallFile, error) {
+	rows, err := d.db.Query(`
+		SELECT id, filepath, COALESCE(storage_key, ''), COALESCE(local_deleted, FALSE), COALESCE(audio_missing, FALSE)
+		FROM calls
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ?
+	`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query call files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []CallFile
+	for rows.Next() {
+		var file CallFile
+		if err := rows.Scan(&file.ID, &file.Filepath, &file.StorageKey, &file.LocalDeleted, &file.AudioMissing); err != nil {
+			return nil, fmt.Errorf("failed to scan call file: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// SetAudioMissing marks calls whose recordings were deleted outside Meiko, or
+// clears the mark from ones that have reappeared
+func (d *Database) SetAudioMissing(ids []int, missing bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return d.withTx(func(tx *sql.Tx) error {
+		for _, id := range ids {
+			if _, err := tx.Exec("UPDATE calls SET audio_missing = ? WHERE id = ?", missing, id); err != nil {
+				return fmt.Errorf("failed to mark call %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// CountMissingAudio returns how many calls are marked as missing their recording
+func (d *Database) CountMissingAudio() (int, error) {
+	var count int
+	if err := d.db.QueryRow("SELECT COUNT(*) FROM calls WHERE audio_missing = TRUE").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count calls missing audio: %w", err)
+	}
+	return count, nil
+}
+
+// GetMissingAudioCalls returns calls marked as missing their recording, oldest first
+func (d *Database) GetMissingAudioCalls(limit int) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE audio_missing = TRUE
+		ORDER BY timestamp ASC
+		LIMIT ?
+	`
+	return d.queryCalls(query, limit)
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordTranscriptionFailure stores a failed transcription attempt. The call
+// is retried at retryAt, or marked failed when retryAt is nil.
+func (d *Database) RecordTranscriptionFailure(id, attempts int, lastError string, retryAt *time.Time) error {
+	result, err := d.db.Exec(`
+		UPDATE calls
+		SET attempts = ?, last_error = ?, retry_at = ?, failed = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`, attempts, lastError, retryAt, retryAt == nil, id)
+	if err != nil {
+		return fmt.Errorf("failed to record transcription failure: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no call found with ID %d", id)
+	}
+
+	return nil
+}
+
+// GetCallsDueForRetry returns unprocessed calls whose next transcription
+// attempt is due, oldest retry first
+func (d *Database) GetCallsDueForRetry(now time.Time, limit int) ([]*CallRecord, error) {
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		WHERE processed = FALSE AND failed = FALSE AND retry_at IS NOT NULL AND retry_at <= ?
+		ORDER BY retry_at ASC
+		LIMIT ?
+	`
+
+	calls, err := d.queryCalls(query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calls due for retry: %w", err)
+	}
+	return calls, nil
+}
+
+// RetryFailedCalls queues every failed call to be transcribed again with a
+// fresh set of attempts, returning how many were queued
+func (d *Database) RetryFailedCalls() (int64, error) {
+	result, err := d.db.Exec(`
+		UPDATE calls
+		SET failed = FALSE, attempts = 0, retry_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE processed = FALSE AND failed = TRUE
+	`, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue failed calls: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,490 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Rollup bucket formats. Hourly buckets are UTC so they are unambiguous across
+// DST changes; daily buckets use the local date the dashboard reports on.
+const (
+	hourBucketFormat = "2006-01-02 15:00:00"
+	dayBucketFormat  = "2006-01-02"
+)
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// addToRollups adds calls to the hourly and daily statistics rollups
+func addToRollups(db execer, timestamp time.Time, systemID, talkgroupID, talkgroupAlias, frequency string, calls, duration int64) error {
+	queries := []struct {
+		query  string
+		bucket string
+	}{
+		{
+			query: `
+				INSERT INTO call_rollups_hourly (bucket, system_id, talkgroup_id, talkgroup_alias, frequency, call_count, total_duration)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(bucket, system_id, talkgroup_id, frequency) DO UPDATE SET
+					talkgroup_alias = excluded.talkgroup_alias,
+					call_count = call_count + excluded.call_count,
+					total_duration = total_duration + excluded.total_duration
+			`,
+			bucket: timestamp.UTC().Format(hourBucketFormat),
+		},
+		{
+			query: `
+				INSERT INTO call_rollups_daily (day, system_id, talkgroup_id, talkgroup_alias, frequency, call_count, total_duration)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(day, system_id, talkgroup_id, frequency) DO UPDATE SET
+					talkgroup_alias = excluded.talkgroup_alias,
+					call_count = call_count + excluded.call_count,
+					total_duration = total_duration + excluded.total_duration
+			`,
+			bucket: timestamp.Local().Format(dayBucketFormat),
+		},
+	}
+
+	for _, q := range queries {
+		if _, err := db.Exec(q.query, q.bucket, systemID, talkgroupID, talkgroupAlias, frequency, calls, duration); err != nil {
+			return fmt.Errorf("failed to update call rollups: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeFromRollups takes a call back out of the statistics rollups, dropping
+// buckets it leaves empty so they no longer count as active talkgroups or
+// frequencies
+func removeFromRollups(db execer, call *CallRecord) error {
+	if err := addToRollups(db, call.Timestamp, call.SystemID, call.TalkgroupID, call.TalkgroupAlias, call.Frequency, -1, -int64(call.Duration)); err != nil {
+		return err
+	}
+
+	buckets := []struct {
+		query  string
+		bucket string
+	}{
+		{"DELETE FROM call_rollups_hourly WHERE bucket = ? AND system_id = ? AND talkgroup_id = ? AND frequency = ? AND call_count <= 0",
+			call.Timestamp.UTC().Format(hourBucketFormat)},
+		{"DELETE FROM call_rollups_daily WHERE day = ? AND system_id = ? AND talkgroup_id = ? AND frequency = ? AND call_count <= 0",
+			call.Timestamp.Local().Format(dayBucketFormat)},
+	}
+	for _, b := range buckets {
+		if _, err := db.Exec(b.query, b.bucket, call.SystemID, call.TalkgroupID, call.Frequency); err != nil {
+			return fmt.Errorf("failed to prune call rollups: %w", err)
+		}
+	}
+	return nil
+}
+
+// backfillRollups builds the rollup tables from existing calls the first time
+// they are created on a database that already has call history
+func (d *Database) backfillRollups() error {
+	var rollupRows, callRows int64
+	if err := d.db.QueryRow("SELECT COUNT(*) FROM call_rollups_daily").Scan(&rollupRows); err != nil {
+		return fmt.Errorf("failed to check call rollups: %w", err)
+	}
+	if rollupRows > 0 {
+		return nil
+	}
+	if err := d.db.QueryRow("SELECT COUNT(*) FROM calls").Scan(&callRows); err != nil {
+		return fmt.Errorf("failed to count calls: %w", err)
+	}
+	if callRows == 0 {
+		return nil
+	}
+
+	d.logger.Info("Building call statistics rollups", "calls", callRows)
+	start := time.Now()
+
+	rows, err := d.db.Query(`
+		SELECT timestamp, COALESCE(system_id, ''), COALESCE(talkgroup_id, ''), COALESCE(talkgroup_alias, ''),
+		       COALESCE(frequency, ''), COALESCE(duration, 0)
+		FROM calls
+		WHERE timestamp IS NOT NULL AND COALESCE(duplicate_of, 0) = 0
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to read calls for rollups: %w", err)
+	}
+
+	type key struct {
+		hour, systemID, talkgroupID, frequency string
+	}
+	type totals struct {
+		timestamp      time.Time
+		talkgroupAlias string
+		calls          int64
+		duration       int64
+	}
+
+	// Aggregate per hour in memory first so the backfill is one write per bucket
+	buckets := make(map[key]*totals)
+	for rows.Next() {
+		var timestamp time.Time
+		var systemID, talkgroupID, talkgroupAlias, frequency string
+		var duration int64
+		if err := rows.Scan(&timestamp, &systemID, &talkgroupID, &talkgroupAlias, &frequency, &duration); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan call for rollups: %w", err)
+		}
+
+		k := key{timestamp.UTC().Format(hourBucketFormat), systemID, talkgroupID, frequency}
+		if buckets[k] == nil {
+			buckets[k] = &totals{timestamp: timestamp}
+		}
+		buckets[k].talkgroupAlias = talkgroupAlias
+		buckets[k].calls++
+		buckets[k].duration += duration
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read calls for rollups: %w", err)
+	}
+
+	err = d.withTx(func(tx *sql.Tx) error {
+		for k, t := range buckets {
+			if err := addToRollups(tx, t.timestamp, k.systemID, k.talkgroupID, t.talkgroupAlias, k.frequency, t.calls, t.duration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.logger.Info("Call statistics rollups built", "buckets", len(buckets), "took", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// systemFilter returns a condition limiting a query to one system, or an empty
+// condition for all systems
+func systemFilter(systemID string) (string, []interface{}) {
+	if systemID == "" {
+		return "", nil
+	}
+	return " AND system_id = ?", []interface{}{systemID}
+}
+
+// GetCallStats returns aggregated call statistics for a time range, optionally
+// for one system. Whole hours are read from the hourly rollups and only the
+// partial hours at either edge are aggregated from the calls table.
+func (d *Database) GetCallStats(start, end *time.Time, systemID string) (map[string]interface{}, error) {
+	rangeStart := time.Time{}
+	if start != nil {
+		rangeStart = *start
+	}
+	rangeEnd := time.Now()
+	if end != nil {
+		rangeEnd = *end
+	}
+
+	// Hour boundaries covered entirely by the range
+	firstHour := rangeStart.Truncate(time.Hour)
+	if firstHour.Before(rangeStart) {
+		firstHour = firstHour.Add(time.Hour)
+	}
+	lastHour := rangeEnd.Truncate(time.Hour)
+	if !firstHour.Before(lastHour) {
+		firstHour, lastHour = rangeEnd, rangeEnd
+	}
+
+	system, systemArgs := systemFilter(systemID)
+	query := `
+		SELECT
+			COALESCE(SUM(calls), 0),
+			COALESCE(SUM(duration), 0),
+			COUNT(DISTINCT talkgroup_id),
+			COUNT(DISTINCT frequency)
+		FROM (
+			SELECT call_count AS calls, total_duration AS duration, talkgroup_id, frequency
+			FROM call_rollups_hourly
+			WHERE bucket >= ? AND bucket < ?` + system + `
+			UNION ALL
+			SELECT 1, duration, talkgroup_id, frequency
+			FROM calls
+			WHERE ((timestamp >= ? AND timestamp < ?) OR (timestamp >= ? AND timestamp <= ?)) AND duplicate_of = 0` + system + `
+		)
+	`
+	args := []interface{}{firstHour.UTC().Format(hourBucketFormat), lastHour.UTC().Format(hourBucketFormat)}
+	args = append(args, systemArgs...)
+	args = append(args, rangeStart, firstHour, lastHour, rangeEnd)
+	args = append(args, systemArgs...)
+
+	var totalCalls int64
+	var totalDuration float64
+	var uniqueTalkgroups, uniqueFrequencies int64
+
+	err := d.db.QueryRow(query, args...).Scan(&totalCalls, &totalDuration, &uniqueTalkgroups, &uniqueFrequencies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call stats: %w", err)
+	}
+
+	avgDuration := 0.0
+	if totalCalls > 0 {
+		avgDuration = totalDuration / float64(totalCalls)
+	}
+
+	stats := map[string]interface{}{
+		"total_calls":        totalCalls,
+		"avg_duration":       avgDuration,
+		"total_duration":     totalDuration,
+		"unique_talkgroups":  uniqueTalkgroups,
+		"unique_frequencies": uniqueFrequencies,
+	}
+
+	return stats, nil
+}
+
+// ActivityHeatmap counts calls by local weekday (Sunday first) and hour of day
+type ActivityHeatmap [7][24]int64
+
+// GetActivityHeatmap counts calls by local weekday and hour over the whole
+// hours in [start, end), read from the hourly rollups. systemID limits it to
+// one system, and talkgroupIDs to those talkgroups when not nil; an empty
+// non-nil list matches nothing.
+func (d *Database) GetActivityHeatmap(start, end time.Time, systemID string, talkgroupIDs []string) (ActivityHeatmap, error) {
+	var heatmap ActivityHeatmap
+	if talkgroupIDs != nil && len(talkgroupIDs) == 0 {
+		return heatmap, nil
+	}
+
+	system, args := systemFilter(systemID)
+	query := `
+		SELECT bucket, SUM(call_count)
+		FROM call_rollups_hourly
+		WHERE bucket >= ? AND bucket < ?` + system
+	args = append([]interface{}{start.UTC().Format(hourBucketFormat), end.UTC().Format(hourBucketFormat)}, args...)
+	if talkgroupIDs != nil {
+		query += " AND talkgroup_id IN (" + placeholders(len(talkgroupIDs)) + ")"
+		for _, id := range talkgroupIDs {
+			args = append(args, id)
+		}
+	}
+	query += " GROUP BY bucket"
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return heatmap, fmt.Errorf("failed to query activity heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket string
+		var calls int64
+		if err := rows.Scan(&bucket, &calls); err != nil {
+			return heatmap, fmt.Errorf("failed to scan activity heatmap: %w", err)
+		}
+		hour, err := time.ParseInLocation(hourBucketFormat, bucket, time.UTC)
+		if err != nil {
+			return heatmap, fmt.Errorf("invalid rollup bucket %q: %w", bucket, err)
+		}
+		hour = hour.Local()
+		heatmap[hour.Weekday()][hour.Hour()] += calls
+	}
+
+	return heatmap, rows.Err()
+}
+
+// HourlyVolume is the number of calls on a talkgroup in one hour
+type HourlyVolume struct {
+	Hour           time.Time
+	TalkgroupID    string
+	TalkgroupAlias string
+	Calls          int64
+}
+
+// GetHourlyVolume returns each talkgroup's calls per hour over the whole hours
+// in [start, end), read from the hourly rollups. Hours without calls are left
+// out.
+func (d *Database) GetHourlyVolume(start, end time.Time) ([]HourlyVolume, error) {
+	query := `
+		SELECT bucket, talkgroup_id, MAX(talkgroup_alias), SUM(call_count)
+		FROM call_rollups_hourly
+		WHERE bucket >= ? AND bucket < ?
+		GROUP BY bucket, talkgroup_id
+	`
+
+	rows, err := d.db.Query(query, start.UTC().Format(hourBucketFormat), end.UTC().Format(hourBucketFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hourly volume: %w", err)
+	}
+	defer rows.Close()
+
+	var volume []HourlyVolume
+	for rows.Next() {
+		var v HourlyVolume
+		var bucket string
+		if err := rows.Scan(&bucket, &v.TalkgroupID, &v.TalkgroupAlias, &v.Calls); err != nil {
+			return nil, fmt.Errorf("failed to scan hourly volume: %w", err)
+		}
+		if v.Hour, err = time.ParseInLocation(hourBucketFormat, bucket, time.UTC); err != nil {
+			return nil, fmt.Errorf("invalid rollup bucket %q: %w", bucket, err)
+		}
+		volume = append(volume, v)
+	}
+
+	return volume, rows.Err()
+}
+
+// GetFirstCallHour returns the earliest hour in the hourly rollups, or the zero
+// time when there are no calls yet
+func (d *Database) GetFirstCallHour() (time.Time, error) {
+	var bucket sql.NullString
+	if err := d.db.QueryRow("SELECT MIN(bucket) FROM call_rollups_hourly").Scan(&bucket); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get first call hour: %w", err)
+	}
+	if !bucket.Valid {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(hourBucketFormat, bucket.String, time.UTC)
+}
+
+// GetRecentTalkgroupVolume returns per-talkgroup call volume since a time,
+// busiest first, read from the calls table so the current hour is included.
+// Simulcast duplicates are not counted.
+func (d *Database) GetRecentTalkgroupVolume(since time.Time) ([]TalkgroupActivity, error) {
+	query := `
+		SELECT talkgroup_id, MAX(talkgroup_alias), COUNT(*), CAST(COALESCE(SUM(duration), 0) AS INTEGER)
+		FROM calls
+		WHERE timestamp >= ? AND duplicate_of = 0
+		GROUP BY talkgroup_id
+		ORDER BY COUNT(*) DESC
+	`
+
+	rows, err := d.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent talkgroup volume: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []TalkgroupActivity
+	for rows.Next() {
+		var a TalkgroupActivity
+		if err := rows.Scan(&a.TalkgroupID, &a.TalkgroupAlias, &a.Calls, &a.Duration); err != nil {
+			return nil, fmt.Errorf("failed to scan recent talkgroup volume: %w", err)
+		}
+		activity = append(activity, a)
+	}
+
+	return activity, rows.Err()
+}
+
+// TalkgroupActivity is the call volume of a talkgroup over a period
+type TalkgroupActivity struct {
+	TalkgroupID    string `json:"talkgroup_id"`
+	TalkgroupAlias string `json:"talkgroup_alias"`
+	Calls          int64  `json:"calls"`
+	Duration       int64  `json:"duration"`
+}
+
+// GetTalkgroupActivity returns per-talkgroup call volume for the local days in
+// [startDay, endDay), busiest first, read from the daily rollups
+func (d *Database) GetTalkgroupActivity(startDay, endDay time.Time) ([]TalkgroupActivity, error) {
+	query := `
+		SELECT talkgroup_id, MAX(talkgroup_alias), SUM(call_count), SUM(total_duration)
+		FROM call_rollups_daily
+		WHERE day >= ? AND day < ?
+		GROUP BY talkgroup_id
+		ORDER BY SUM(call_count) DESC
+	`
+
+	rows, err := d.db.Query(query, startDay.Format(dayBucketFormat), endDay.Format(dayBucketFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query talkgroup activity: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []TalkgroupActivity
+	for rows.Next() {
+		var a TalkgroupActivity
+		if err := rows.Scan(&a.TalkgroupID, &a.TalkgroupAlias, &a.Calls, &a.Duration); err != nil {
+			return nil, fmt.Errorf("failed to scan talkgroup activity: %w", err)
+		}
+		activity = append(activity, a)
+	}
+
+	return activity, rows.Err()
+}
+
+// GetCallDays returns the local days before the given day that have calls,
+// oldest first, read from the daily rollups
+func (d *Database) GetCallDays(before time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT day
+		FROM call_rollups_daily
+		WHERE day < ? AND call_count > 0
+		ORDER BY day ASC
+	`
+
+	rows, err := d.db.Query(query, before.Format(dayBucketFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query call days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []string
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan call day: %w", err)
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// SystemActivity is the call volume of a radio system
+type SystemActivity struct {
+	SystemID   string     `json:"system_id"`
+	TotalCalls int64      `json:"total_calls"`
+	CallsToday int64      `json:"calls_today"`
+	LastCall   *time.Time `json:"last_call,omitempty"`
+}
+
+// GetSystemActivity returns call volume for every system that has calls
+func (d *Database) GetSystemActivity() (map[string]*SystemActivity, error) {
+	query := `
+		SELECT system_id, SUM(call_count), SUM(CASE WHEN day = ? THEN call_count ELSE 0 END)
+		FROM call_rollups_daily
+		GROUP BY system_id
+	`
+	rows, err := d.db.Query(query, time.Now().Format(dayBucketFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query system activity: %w", err)
+	}
+	defer rows.Close()
+
+	activity := make(map[string]*SystemActivity)
+	for rows.Next() {
+		a := &SystemActivity{}
+		if err := rows.Scan(&a.SystemID, &a.TotalCalls, &a.CallsToday); err != nil {
+			return nil, fmt.Errorf("failed to scan system activity: %w", err)
+		}
+		activity[a.SystemID] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read system activity: %w", err)
+	}
+
+	// Selecting the column itself rather than MAX() keeps the DATETIME type for scanning
+	for systemID, a := range activity {
+		var timestamp time.Time
+		err := d.db.QueryRow("SELECT timestamp FROM calls WHERE COALESCE(system_id, '') = ? ORDER BY timestamp DESC LIMIT 1", systemID).Scan(&timestamp)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last call for system %q: %w", systemID, err)
+		}
+		a.LastCall = &timestamp
+	}
+
+	return activity, nil
+}
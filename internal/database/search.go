@@ -0,0 +1,121 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// callSearchSchema keeps a full-text index of transcriptions and translations
+// in step with the calls table. The triggers only fire on changes to the
+// indexed columns, so bumping updated_at doesn't reindex a call.
+const callSearchSchema = `
+	CREATE VIRTUAL TABLE IF NOT EXISTS calls_fts USING fts4(content='calls', transcription, translation);
+
+	CREATE TRIGGER IF NOT EXISTS calls_fts_before_update
+		BEFORE UPDATE OF transcription, translation ON calls
+		BEGIN
+			DELETE FROM calls_fts WHERE docid = OLD.id;
+		END;
+
+	CREATE TRIGGER IF NOT EXISTS calls_fts_before_delete
+		BEFORE DELETE ON calls
+		BEGIN
+			DELETE FROM calls_fts WHERE docid = OLD.id;
+		END;
+
+	CREATE TRIGGER IF NOT EXISTS calls_fts_after_update
+		AFTER UPDATE OF transcription, translation ON calls
+		BEGIN
+			INSERT INTO calls_fts(docid, transcription, translation) VALUES (NEW.id, NEW.transcription, NEW.translation);
+		END;
+
+	CREATE TRIGGER IF NOT EXISTS calls_fts_after_insert
+		AFTER INSERT ON calls
+		BEGIN
+			INSERT INTO calls_fts(docid, transcription, translation) VALUES (NEW.id, NEW.transcription, NEW.translation);
+		END;
+`
+
+// ensureCallSearch creates the full-text index, indexing existing calls the
+// first time it is created
+func (d *Database) ensureCallSearch() error {
+	var existing int
+	if err := d.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'calls_fts'").Scan(&existing); err != nil {
+		return fmt.Errorf("failed to check call search index: %w", err)
+	}
+
+	if _, err := d.db.Exec(callSearchSchema); err != nil {
+		return fmt.Errorf("failed to create call search index: %w", err)
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	start := time.Now()
+	if _, err := d.db.Exec("INSERT INTO calls_fts(calls_fts) VALUES ('rebuild')"); err != nil {
+		return fmt.Errorf("failed to build call search index: %w", err)
+	}
+	d.logger.Info("Built call search index", "took", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// SearchCalls returns calls in a time range whose transcription or translation
+// matches any of the terms, most matches first. Terms match word prefixes, so
+// "fire" also finds "fires" and "firefighters".
+func (d *Database) SearchCalls(terms []string, start, end time.Time, limit int) ([]*CallRecord, error) {
+	var match []string
+	for _, term := range terms {
+		if term = ftsTerm(term); term != "" {
+			match = append(match, term)
+		}
+	}
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	// offsets() lists one entry per matched term occurrence, so its length
+	// ranks calls that mention more of the terms higher
+	query := `
+		SELECT ` + callColumns + `
+		FROM calls
+		JOIN (
+			SELECT docid, length(offsets(calls_fts)) AS rank
+			FROM calls_fts
+			WHERE calls_fts MATCH ?
+		) AS hits ON hits.docid = calls.id
+		WHERE timestamp >= ? AND timestamp < ? AND duplicate_of = 0
+		ORDER BY hits.rank DESC, timestamp DESC
+		LIMIT ?
+	`
+
+	return d.queryCalls(query, strings.Join(match, " OR "), start, end, limit)
+}
+
+// ftsTerm quotes a search term as a word prefix for a full-text query, or
+// returns an empty string when nothing searchable is left
+func ftsTerm(term string) string {
+	// Quotes and operators in a term would change the query's meaning
+	term = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`"*^():-`, r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(term))
+	if term == "" {
+		return ""
+	}
+	return `"` + term + `*"`
+}
+
+// matchAll builds a full-text query matching calls that contain every word of
+// text, or an empty string when there is nothing to search for
+func matchAll(text string) string {
+	var match []string
+	for _, word := range strings.Fields(text) {
+		if word = ftsTerm(word); word != "" {
+			match = append(match, word)
+		}
+	}
+	return strings.Join(match, " ")
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TalkgroupOverride replaces playlist metadata for a talkgroup. Empty strings
+// and nil flags leave the playlist or config value in place.
+type TalkgroupOverride struct {
+	TalkgroupID string    `json:"talkgroup_id"`
+	Name        string    `json:"name,omitempty"`
+	Group       string    `json:"group,omitempty"`
+	ServiceType string    `json:"service_type,omitempty"`
+	Color       string    `json:"color,omitempty"` // Display color as #rrggbb
+	Priority    *bool     `json:"priority,omitempty"`
+	Mute        *bool     `json:"mute,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// GetTalkgroupOverrides returns all talkgroup overrides
+func (d *Database) GetTalkgroupOverrides() ([]*TalkgroupOverride, error) {
+	rows, err := d.db.Query(`
+		SELECT talkgroup_id, name, group_name, service_type, color, priority, mute, updated_at
+		FROM talkgroup_overrides
+		ORDER BY talkgroup_id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query talkgroup overrides: %w", err)
+	}
+	defer rows.Close()
+
+	var overrides []*TalkgroupOverride
+	for rows.Next() {
+		override := &TalkgroupOverride{}
+		var priority, mute sql.NullBool
+		if err := rows.Scan(&override.TalkgroupID, &override.Name, &override.Group, &override.ServiceType,
+			&override.Color, &priority, &mute, &override.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan talkgroup override: %w", err)
+		}
+		if priority.Valid {
+			override.Priority = &priority.Bool
+		}
+		if mute.Valid {
+			override.Mute = &mute.Bool
+		}
+		overrides = append(overrides, override)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// SaveTalkgroupOverride creates or replaces the override for a talkgroup
+func (d *Database) SaveTalkgroupOverride(override *TalkgroupOverride) error {
+	override.UpdatedAt = time.Now()
+	_, err := d.db.Exec(`
+		INSERT INTO talkgroup_overrides (talkgroup_id, name, group_name, service_type, color, priority, mute, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(talkgroup_id) DO UPDATE SET
+			name = excluded.name,
+			group_name = excluded.group_name,
+			service_type = excluded.service_type,
+			color = excluded.color,
+			priority = excluded.priority,
+			mute = excluded.mute,
+			updated_at = excluded.updated_at
+	`, override.TalkgroupID, override.Name, override.Group, override.ServiceType, override.Color,
+		override.Priority, override.Mute, override.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save talkgroup override: %w", err)
+	}
+
+	d.logger.Debug("Database", "Saved talkgroup override", "talkgroup_id", override.TalkgroupID)
+	return nil
+}
+
+// DeleteTalkgroupOverride removes the override for a talkgroup
+func (d *Database) DeleteTalkgroupOverride(talkgroupID string) error {
+	result, err := d.db.Exec("DELETE FROM talkgroup_overrides WHERE talkgroup_id = ?", talkgroupID)
+	if err != nil {
+		return fmt.Errorf("failed to delete talkgroup override: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("no override found for talkgroup %s", talkgroupID)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package dedup
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+)
+
+// Detector recognises simulcast duplicates: the same transmission recorded on
+// more than one frequency or site
+type Detector struct {
+	config config.DedupConfig
+}
+
+// NewDetector creates a duplicate detector
+func NewDetector(cfg config.DedupConfig) *Detector {
+	return &Detector{config: cfg}
+}
+
+// Window returns how far apart two call start times may be and still be the
+// same transmission
+func (d *Detector) Window() time.Duration {
+	return time.Duration(d.config.Window) * time.Second
+}
+
+// Match returns the candidate a call duplicates, or nil if there is none.
+// Candidates must be within the duration tolerance and, when both calls have
+// a transcript, similar enough to it. The most similar candidate wins, then
+// the closest in time.
+func (d *Detector) Match(call *database.CallRecord, candidates []*database.CallRecord) *database.CallRecord {
+	var best *database.CallRecord
+	bestSimilarity := -1.0
+	var bestGap time.Duration
+
+	for _, candidate := range candidates {
+		if abs(candidate.Duration-call.Duration) > d.config.DurationTolerance {
+			continue
+		}
+
+		similarity := 1.0
+		if strings.TrimSpace(call.Transcription) != "" && strings.TrimSpace(candidate.Transcription) != "" {
+			similarity = Similarity(call.Transcription, candidate.Transcription)
+			if similarity < d.config.MinSimilarity {
+				continue
+			}
+		}
+
+		gap := call.Timestamp.Sub(candidate.Timestamp).Abs()
+		if similarity > bestSimilarity || (similarity == bestSimilarity && gap < bestGap) {
+			best, bestSimilarity, bestGap = candidate, similarity, gap
+		}
+	}
+
+	return best
+}
+
+// Similarity returns the overlap (0-1) between the words of two transcripts
+func Similarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+// words returns the set of lowercase words in a transcript
+func words(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[word] = true
+	}
+	return set
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
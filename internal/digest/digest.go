@@ -0,0 +1,263 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"Meiko/internal/archive"
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+)
+
+// keywordHitLimit caps the number of keyword hits fetched per digest
+const keywordHitLimit = 200
+
+// schedule is a configured digest with its parsed templates
+type schedule struct {
+	config  config.DigestConfig
+	subject *template.Template
+	body    *htmltemplate.Template
+}
+
+// Scheduler sends configured email digests on their schedules
+type Scheduler struct {
+	config     config.EmailConfig
+	db         *database.Database
+	summarizer archive.Summarizer
+	logger     *logger.Logger
+	mailer     *mailer
+	schedules  []*schedule
+}
+
+// New creates a digest scheduler and parses all digest templates. The
+// summarizer may be nil, in which case digests are sent without an AI summary.
+func New(cfg config.EmailConfig, db *database.Database, summarizer archive.Summarizer, logger *logger.Logger) (*Scheduler, error) {
+	scheduler := &Scheduler{
+		config:     cfg,
+		db:         db,
+		summarizer: summarizer,
+		logger:     logger,
+		mailer:     &mailer{config: cfg.SMTP},
+	}
+
+	for _, digestConfig := range cfg.Digests {
+		s := &schedule{config: digestConfig, body: defaultBody}
+
+		var err error
+		s.subject, err = template.New("subject").Funcs(templateFuncs).Parse(digestConfig.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse subject for digest %q: %w", digestConfig.Name, err)
+		}
+
+		if digestConfig.Template != "" {
+			s.body, err = htmltemplate.New(filepath.Base(digestConfig.Template)).Funcs(templateFuncs).ParseFiles(digestConfig.Template)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse template for digest %q: %w", digestConfig.Name, err)
+			}
+		}
+
+		scheduler.schedules = append(scheduler.schedules, s)
+	}
+
+	return scheduler, nil
+}
+
+// Start begins sending each digest on its schedule
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, sched := range s.schedules {
+		go s.run(ctx, sched)
+	}
+}
+
+// run waits for each scheduled send of a digest
+func (s *Scheduler) run(ctx context.Context, sched *schedule) {
+	for {
+		next := nextRun(sched.config, time.Now())
+		s.logger.Debug("Digest", "Next digest scheduled", "name", sched.config.Name, "at", next.Format("2006-01-02 15:04"))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := s.send(sched, next); err != nil {
+				s.logger.Error("Failed to send digest", "name", sched.config.Name, "error", err)
+			}
+		}
+	}
+}
+
+// nextRun returns the next send time for a digest after now
+func nextRun(cfg config.DigestConfig, now time.Time) time.Time {
+	runAt, _ := time.Parse("15:04", cfg.RunAt)
+	next := time.Date(now.Year(), now.Month(), now.Day(), runAt.Hour(), runAt.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	if cfg.Frequency == "weekly" {
+		weekday, _ := config.ParseWeekday(cfg.Weekday)
+		for next.Weekday() != weekday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+
+	return next
+}
+
+// period returns the whole days covered by a digest sent at the given time
+func period(cfg config.DigestConfig, sentAt time.Time) (time.Time, time.Time) {
+	end := time.Date(sentAt.Year(), sentAt.Month(), sentAt.Day(), 0, 0, 0, 0, sentAt.Location())
+	if cfg.Frequency == "weekly" {
+		return end.AddDate(0, 0, -7), end
+	}
+	return end.AddDate(0, 0, -1), end
+}
+
+// send builds and delivers a digest to all of its recipients
+func (s *Scheduler) send(sched *schedule, sentAt time.Time) error {
+	cfg := sched.config
+	start, end := period(cfg, sentAt)
+
+	activity, err := s.db.GetTalkgroupActivity(start, end)
+	if err != nil {
+		return err
+	}
+
+	// Fetch hits for every keyword any recipient is interested in, then filter per recipient
+	var allKeywords []string
+	seen := make(map[string]bool)
+	for _, keywords := range append([][]string{cfg.Keywords}, recipientKeywords(cfg)...) {
+		for _, keyword := range keywords {
+			keyword = strings.ToLower(strings.TrimSpace(keyword))
+			if keyword != "" && !seen[keyword] {
+				seen[keyword] = true
+				allKeywords = append(allKeywords, keyword)
+			}
+		}
+	}
+	hits, err := s.db.GetKeywordHits(start, end, allKeywords, keywordHitLimit)
+	if err != nil {
+		return err
+	}
+
+	var summary string
+	if s.summarizer != nil && len(activity) > 0 {
+		if summary, err = s.summarizer.SummarizeRange(start, end); err != nil {
+			s.logger.Warn("Digest will not include a summary", "name", cfg.Name, "error", err)
+		}
+	}
+
+	sent := 0
+	for _, recipient := range cfg.Recipients {
+		digest := &Digest{
+			Name:      cfg.Name,
+			Frequency: cfg.Frequency,
+			Start:     start,
+			End:       end,
+			Recipient: recipient.Address,
+			Summary:   summary,
+			BaseURL:   s.config.BaseURL,
+		}
+		digest.TopTalkgroups, digest.TotalCalls = filterActivity(activity, recipient.Talkgroups, cfg.TopTalkgroups)
+
+		keywords := cfg.Keywords
+		if len(recipient.Keywords) > 0 {
+			keywords = recipient.Keywords
+		}
+		digest.KeywordHits = filterHits(hits, keywords, recipient)
+
+		if err := s.deliver(sched, digest); err != nil {
+			s.logger.Error("Failed to email digest", "name", cfg.Name, "recipient", recipient.Address, "error", err)
+			continue
+		}
+		sent++
+	}
+
+	s.logger.Success("Digest sent", "name", cfg.Name, "recipients", sent, "failed", len(cfg.Recipients)-sent)
+	return nil
+}
+
+// deliver renders a digest and emails it to its recipient
+func (s *Scheduler) deliver(sched *schedule, digest *Digest) error {
+	var subject, body bytes.Buffer
+	if err := sched.subject.Execute(&subject, digest); err != nil {
+		return fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := sched.body.Execute(&body, digest); err != nil {
+		return fmt.Errorf("failed to render body: %w", err)
+	}
+
+	message, err := s.mailer.buildMessage(digest.Recipient, strings.TrimSpace(subject.String()), body.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	return s.mailer.send(digest.Recipient, message)
+}
+
+// recipientKeywords returns the keyword overrides of every recipient
+func recipientKeywords(cfg config.DigestConfig) [][]string {
+	keywords := make([][]string, 0, len(cfg.Recipients))
+	for _, recipient := range cfg.Recipients {
+		keywords = append(keywords, recipient.Keywords)
+	}
+	return keywords
+}
+
+// filterActivity limits talkgroup activity to a recipient's talkgroups and
+// returns the top entries along with the total call count
+func filterActivity(activity []database.TalkgroupActivity, talkgroups []string, top int) ([]database.TalkgroupActivity, int64) {
+	var filtered []database.TalkgroupActivity
+	var total int64
+	for _, a := range activity {
+		if len(talkgroups) > 0 && !contains(talkgroups, a.TalkgroupID) {
+			continue
+		}
+		total += a.Calls
+		if len(filtered) < top {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered, total
+}
+
+// filterHits returns the calls matching a recipient's keywords and filters
+func filterHits(calls []*database.CallRecord, keywords []string, recipient config.DigestRecipientConfig) []KeywordHit {
+	var hits []KeywordHit
+	for _, call := range calls {
+		if len(recipient.Talkgroups) > 0 && !contains(recipient.Talkgroups, call.TalkgroupID) {
+			continue
+		}
+		if call.Severity < recipient.MinSeverity {
+			continue
+		}
+
+		transcription := strings.ToLower(call.Transcription)
+		for _, keyword := range keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" && strings.Contains(transcription, strings.ToLower(keyword)) {
+				hits = append(hits, KeywordHit{Keyword: keyword, Call: call})
+				break
+			}
+		}
+	}
+	return hits
+}
+
+// contains reports whether a list holds a value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package digest
+
+import (
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"Meiko/internal/database"
+)
+
+// Digest is the data passed to subject and body templates for one recipient
+type Digest struct {
+	Name          string
+	Frequency     string // daily or weekly
+	Start         time.Time
+	End           time.Time
+	Recipient     string
+	Summary       string
+	TotalCalls    int64
+	TopTalkgroups []database.TalkgroupActivity
+	KeywordHits   []KeywordHit
+	BaseURL       string
+}
+
+// KeywordHit is a call whose transcription contains a flagged keyword
+type KeywordHit struct {
+	Keyword string
+	Call    *database.CallRecord
+}
+
+// LastDay returns the final day covered by the digest
+func (d *Digest) LastDay() time.Time {
+	return d.End.AddDate(0, 0, -1)
+}
+
+// CallURL returns a link to a call's audio, or an empty string without a base URL
+func (d *Digest) CallURL(call *database.CallRecord) string {
+	if d.BaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(d.BaseURL, "/") + "/api/v1/calls/" + strconv.Itoa(call.ID) + "/audio"
+}
+
+// templateFuncs are available to subject and body templates
+var templateFuncs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Format("Mon Jan 2") },
+	"clock": func(t time.Time) string {
+		return t.Format("Jan 2 15:04")
+	},
+	"oneline": func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	},
+	"minutes": func(seconds int64) string {
+		return strconv.FormatFloat(float64(seconds)/60, 'f', 1, 64)
+	},
+}
+
+const defaultBodyTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"></head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif; color: #222; max-width: 720px;">
+<h2>Meiko {{.Frequency}} digest</h2>
+<p style="color: #777;">{{date .Start}}{{if ne .Frequency "daily"}} &ndash; {{date .LastDay}}{{end}} &middot; {{.TotalCalls}} calls</p>
+
+<h3>Summary</h3>
+<p style="white-space: pre-line; background: #f6f8fa; padding: 12px; border-left: 4px solid #0099ff;">{{if .Summary}}{{.Summary}}{{else}}No summary available.{{end}}</p>
+
+<h3>Top Talkgroups</h3>
+{{if .TopTalkgroups}}<table style="border-collapse: collapse;">
+<tr><th align="left" style="padding: 4px 12px 4px 0;">Talkgroup</th><th align="right" style="padding: 4px 12px;">Calls</th><th align="right" style="padding: 4px 0;">Airtime</th></tr>
+{{range .TopTalkgroups}}<tr><td style="padding: 4px 12px 4px 0;">{{if .TalkgroupAlias}}{{.TalkgroupAlias}}{{else}}{{.TalkgroupID}}{{end}}</td><td align="right" style="padding: 4px 12px;">{{.Calls}}</td><td align="right" style="padding: 4px 0;">{{minutes .Duration}} min</td></tr>
+{{end}}</table>{{else}}<p>No activity.</p>{{end}}
+
+<h3>Flagged Keywords</h3>
+{{if .KeywordHits}}<ul>
+{{range .KeywordHits}}<li><strong>{{.Keyword}}</strong> &middot; {{clock .Call.Timestamp}} &middot; {{.Call.TalkgroupAlias}}{{with $.CallURL .Call}} &middot; <a href="{{.}}">audio</a>{{end}}<br>{{oneline .Call.Transcription}}</li>
+{{end}}</ul>{{else}}<p>No flagged keywords.</p>{{end}}
+</body>
+</html>
+`
+
+// defaultBody is used for digests without a custom template
+var defaultBody = htmltemplate.Must(htmltemplate.New("digest").Funcs(templateFuncs).Parse(defaultBodyTemplate))
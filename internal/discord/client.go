@@ -3,14 +3,19 @@ package discord
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 
 	"Meiko/internal/config"
 	"Meiko/internal/database"
+	"Meiko/internal/frequency"
 	"Meiko/internal/logger"
+	"Meiko/internal/redaction"
 	"Meiko/internal/talkgroups"
+	"Meiko/internal/textutil"
 )
 
 // Client handles Discord integration
@@ -19,11 +24,43 @@ type Client struct {
 	logger     *logger.Logger
 	session    *discordgo.Session
 	talkgroups *talkgroups.Service
+	systems    map[string]config.SystemConfig
+	redactor   *redaction.Redactor // Hides sensitive details in posted calls, nil when disabled
+	webhook    *webhook            // Set in webhook-only mode, which has no gateway connection
 	connected  bool
+	sending    atomic.Int32 // Messages queued or being sent, waited on by Flush
+
+	queueMu sync.Mutex
+	queue   []*outbound   // Messages waiting to be sent, oldest first
+	wake    chan struct{} // Signals the sender that a message was queued
+	done    chan struct{} // Closed by Stop to end the sender
+
+	threadsMu sync.Mutex
+	threads   []*incidentThread // Open incident threads
 }
 
 // New creates a new Discord client
 func New(config config.DiscordConfig, logger *logger.Logger, talkgroupService *talkgroups.Service) (*Client, error) {
+	if config.UseWebhook() {
+		hook, err := parseWebhookURL(config.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		// Webhooks need no authorization, so the session only sends REST requests
+		session, err := discordgo.New("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Discord session: %w", err)
+		}
+		return &Client{
+			config:     config,
+			logger:     logger,
+			session:    session,
+			talkgroups: talkgroupService,
+			webhook:    hook,
+			wake:       make(chan struct{}, 1),
+		}, nil
+	}
+
 	if config.Token == "" {
 		return nil, fmt.Errorf("Discord token is required")
 	}
@@ -38,23 +75,59 @@ func New(config config.DiscordConfig, logger *logger.Logger, talkgroupService *t
 		logger:     logger,
 		session:    session,
 		talkgroups: talkgroupService,
+		wake:       make(chan struct{}, 1),
 	}, nil
 }
 
-// Start connects to Discord
+// SetRedactor sets the rules that hide sensitive details in posted calls
+func (c *Client) SetRedactor(redactor *redaction.Redactor) {
+	c.redactor = redactor
+}
+
+// SetSystems sets the radio systems used to label calls and route them to
+// per-system channels
+func (c *Client) SetSystems(systems []config.SystemConfig) {
+	c.systems = make(map[string]config.SystemConfig, len(systems))
+	for _, system := range systems {
+		c.systems[system.ID] = system
+	}
+}
+
+// Start connects to Discord and starts sending queued messages
 func (c *Client) Start() error {
+	if c.webhook != nil {
+		if c.config.Threads.Enabled {
+			c.logger.Warn("Discord incident threads need a bot token, not a webhook")
+		}
+		c.startQueue()
+		c.logger.Success("Discord webhook ready")
+		return nil
+	}
+
 	if err := c.session.Open(); err != nil {
 		return fmt.Errorf("failed to open Discord session: %w", err)
 	}
 
-	c.connected = true
+	c.startQueue()
 	c.logger.Success("Connected to Discord")
 	return nil
 }
 
-// Stop disconnects from Discord
+// startQueue marks the client connected and starts the sender
+func (c *Client) startQueue() {
+	c.done = make(chan struct{})
+	c.connected = true
+	go c.runQueue(c.done)
+}
+
+// Stop disconnects from Discord. Messages still queued are dropped; call
+// Flush first to send them.
 func (c *Client) Stop() error {
-	if c.session != nil {
+	if c.done != nil {
+		close(c.done)
+		c.done = nil
+	}
+	if c.session != nil && c.webhook == nil {
 		if err := c.session.Close(); err != nil {
 			c.logger.Error("Error closing Discord session", "error", err)
 		}
@@ -63,6 +136,19 @@ func (c *Client) Stop() error {
 	return nil
 }
 
+// Flush waits up to timeout for messages being sent, including those sent
+// from other goroutines, and reports whether they all finished
+func (c *Client) Flush(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for c.sending.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
+
 // IsConnected returns whether the client is connected
 func (c *Client) IsConnected() bool {
 	return c.connected
@@ -106,6 +192,22 @@ func (c *Client) SendCallNotification(call *database.CallRecord) error {
 		return nil
 	}
 
+	// Calls below the severity threshold are still stored and shown on the
+	// dashboard; escalated calls are always posted
+	escalate := c.config.Notifications.EscalatePriority > 0 && call.Priority >= c.config.Notifications.EscalatePriority
+
+	// Calls related to an open incident go to its thread whatever their
+	// severity, and significant calls open a new one
+	thread := c.threadFor(call)
+	opensThread := thread == nil && c.significant(call)
+	incident := thread != nil || opensThread
+
+	if call.Severity < c.config.Notifications.MinSeverity && !escalate && !incident {
+		c.logger.Debug("Discord", "Skipping notification below severity threshold",
+			"call_id", call.ID, "severity", call.Severity, "min_severity", c.config.Notifications.MinSeverity)
+		return nil
+	}
+
 	// Use the enhanced talkgroup information that was already processed with context awareness
 	// The processor has already done intelligent classification, so we should use those results
 	var deptInfo *talkgroups.DepartmentType
@@ -122,7 +224,10 @@ func (c *Client) SendCallNotification(call *database.CallRecord) error {
 			// Extract service type from the enhanced group information
 			for _, dept := range c.talkgroups.GetServiceTypes() {
 				if strings.Contains(call.TalkgroupGroup, dept.Emoji) {
-					deptInfo = dept
+					// Keep the talkgroup's own color when the type agrees
+					if dept.Type != deptInfo.Type {
+						deptInfo = dept
+					}
 					break
 				}
 			}
@@ -162,6 +267,13 @@ func (c *Client) SendCallNotification(call *database.CallRecord) error {
 		}
 	}
 
+	if reason := c.filterCall(call, deptInfo.Type, escalate || incident); reason != "" {
+		c.logger.Debug("Discord", "Skipping filtered notification",
+			"call_id", call.ID, "talkgroup", call.TalkgroupID, "reason", reason)
+		return nil
+	}
+	call = c.redactor.Call(call)
+
 	// Create transcription preview
 	transcriptionPreview := "No transcription available"
 	if call.Transcription != "" {
@@ -202,24 +314,91 @@ func (c *Client) SendCallNotification(call *database.CallRecord) error {
 		},
 		Timestamp: call.Timestamp.Format(time.RFC3339),
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("TalkGroup: %s • Meiko Scanner", call.TalkgroupID),
+			Text: c.footer(call),
 		},
 	}
 
+	// Add the English translation of a non-English call
+	if call.Translation != "" {
+		translation := call.Translation
+		if len(translation) > 300 {
+			translation = translation[:300] + "..."
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("Translation (%s)", strings.ToUpper(call.Language)),
+			Value:  translation,
+			Inline: false,
+		})
+	}
+
 	// Add frequency if available
 	if call.Frequency != "" {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:   "Frequency",
-			Value:  call.Frequency,
+			Value:  frequency.Display(call.Frequency),
 			Inline: true,
 		})
 	}
 
-	c.sendEmbed(embed)
+	// Add severity if the call was scored
+	if call.Severity > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Severity",
+			Value:  fmt.Sprintf("%d/5", call.Severity),
+			Inline: true,
+		})
+	}
+
+	// Add priority if the call was scored
+	if call.Priority > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Priority",
+			Value:  fmt.Sprintf("%d/100", call.Priority),
+			Inline: true,
+		})
+	}
+
+	item := &outbound{
+		channelID: c.channelFor(call),
+		message:   &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}},
+		color:     colorHex,
+	}
+
+	switch {
+	case thread != nil:
+		item.thread = thread
+	case opensThread:
+		item.thread = c.openThread(call)
+		item.opensThread = true
+	}
+
+	if escalate {
+		// Escalate high-priority calls by mentioning everyone online
+		item.message.Content = "@here"
+		item.message.AllowedMentions = &discordgo.MessageAllowedMentions{
+			Parse: []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeEveryone},
+		}
+	} else if item.thread == nil {
+		// Plain notifications carry a one-line summary, so they can be
+		// combined into one embed when Discord falls behind
+		text := call.Transcription
+		if call.Translation != "" {
+			text = call.Translation
+		}
+		if text == "" {
+			text = "No transcription available"
+		}
+		item.batch = &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s %s • <t:%d:T>", deptInfo.Emoji, talkgroupInfo.Name, call.Timestamp.Unix()),
+			Value: textutil.Truncate(text, 200),
+		}
+	}
+	c.enqueue(item)
 
 	// Log notification details
 	c.logger.Info("Discord notification sent",
 		"talkgroup", call.TalkgroupID,
+		"system", call.SystemID,
 		"department", talkgroupInfo.Group,
 		"service_type", string(deptInfo.Type),
 		"duration", fmt.Sprintf("%.1fs", duration),
@@ -228,6 +407,158 @@ func (c *Client) SendCallNotification(call *database.CallRecord) error {
 	return nil
 }
 
+// SendToneAlert sends an alert for a matched paging tone sequence
+func (c *Client) SendToneAlert(call *database.CallRecord, sequence database.ToneSequence) {
+	call = c.redactor.Call(call)
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🚨 Tone out: %s", sequence.Station),
+		Description: fmt.Sprintf("📻 %s", call.TalkgroupAlias),
+		Color:       0xff3300,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Tones",
+				Value:  fmt.Sprintf("`%.1f Hz` → `%.1f Hz`", sequence.ToneA, sequence.ToneB),
+				Inline: true,
+			},
+			{
+				Name:   "Time",
+				Value:  fmt.Sprintf("<t:%d:T>", call.Timestamp.Unix()),
+				Inline: true,
+			},
+		},
+		Timestamp: call.Timestamp.Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: c.footer(call),
+		},
+	}
+
+	if call.Transcription != "" {
+		transcription := call.Transcription
+		if len(transcription) > 300 {
+			transcription = transcription[:300] + "..."
+		}
+		embed.Description += "\n\n" + transcription
+	}
+
+	c.sendEmbedTo(c.channelFor(call), embed)
+	c.logger.Info("Discord tone alert sent", "station", sequence.Station, "call_id", call.ID)
+}
+
+// SendProcessAlert reports an SDRTrunk process exiting or restarting, with how
+// many of the supervised processes are still running
+func (c *Client) SendProcessAlert(systemID, message string, running, total int) {
+	if !c.config.Notifications.SystemHealth {
+		return
+	}
+
+	title := "📡 SDRTrunk"
+	channelID := c.config.ChannelID
+	if system, ok := c.systems[systemID]; ok {
+		title += ": " + system.Name
+		if system.DiscordChannelID != "" {
+			channelID = system.DiscordChannelID
+		}
+	}
+
+	color := 0x00ff00 // Green
+	switch {
+	case running == 0:
+		color = 0xff0000 // Red
+	case running < total:
+		color = 0xff9900 // Orange
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: message,
+		Color:       color,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Running",
+				Value:  fmt.Sprintf("%d/%d processes", running, total),
+				Inline: true,
+			},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Meiko Scanner",
+		},
+	}
+
+	c.sendEmbedTo(channelID, embed)
+}
+
+// SendHealthAlert reports a system metric crossing its threshold, or recovering
+func (c *Client) SendHealthAlert(title, description string, recovered bool) {
+	if !c.config.Notifications.SystemHealth {
+		return
+	}
+
+	color := 0xff9900 // Orange
+	if recovered {
+		color = 0x00ff00 // Green
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: description,
+		Color:       color,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Meiko Scanner",
+		},
+	}
+
+	c.sendEmbed(embed)
+}
+
+// SendAnomalyAlert reports a talkgroup far busier than normal for the time of day
+func (c *Client) SendAnomalyAlert(talkgroup, message string) {
+	embed := &discordgo.MessageEmbed{
+		Title:       "📈 Unusual activity: " + talkgroup,
+		Description: message,
+		Color:       0xff9900, // Orange
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Meiko Scanner",
+		},
+	}
+
+	c.sendEmbed(embed)
+}
+
+// SendDeviceAlert reports an SDR receiver dropping off or returning to the USB bus
+func (c *Client) SendDeviceAlert(device string, present bool, missing int) {
+	if !c.config.Notifications.SystemHealth {
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔌 SDR receiver disconnected",
+		Description: device,
+		Color:       0xff0000, // Red
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Meiko Scanner",
+		},
+	}
+	if present {
+		embed.Title = "🔌 SDR receiver reconnected"
+		embed.Color = 0x00ff00 // Green
+	}
+	if missing > 0 {
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{
+				Name:   "Still missing",
+				Value:  fmt.Sprintf("%d device(s)", missing),
+				Inline: true,
+			},
+		}
+	}
+
+	c.sendEmbed(embed)
+}
+
 // parseHexColor converts a hex color string to Discord color integer
 func parseHexColor(hexColor string) (int, error) {
 	// Remove # if present
@@ -258,14 +589,35 @@ func parseHexColor(hexColor string) (int, error) {
 	return color, nil
 }
 
-// sendEmbed sends an embed to the configured channel
-func (c *Client) sendEmbed(embed *discordgo.MessageEmbed) {
-	if !c.connected || c.config.ChannelID == "" {
-		return
+// footer returns the embed footer for a call, naming its system if it has one
+func (c *Client) footer(call *database.CallRecord) string {
+	if system, ok := c.systems[call.SystemID]; ok {
+		return fmt.Sprintf("TalkGroup: %s • %s • Meiko Scanner", call.TalkgroupID, system.Name)
 	}
+	return fmt.Sprintf("TalkGroup: %s • Meiko Scanner", call.TalkgroupID)
+}
 
-	_, err := c.session.ChannelMessageSendEmbed(c.config.ChannelID, embed)
-	if err != nil {
-		c.logger.Error("Failed to send Discord message", "error", err)
+// channelFor returns the channel for a call: its system's channel if one is
+// configured, otherwise the default channel
+func (c *Client) channelFor(call *database.CallRecord) string {
+	if system, ok := c.systems[call.SystemID]; ok && system.DiscordChannelID != "" {
+		return system.DiscordChannelID
 	}
+	return c.config.ChannelID
+}
+
+// sendEmbed sends an embed to the configured channel
+func (c *Client) sendEmbed(embed *discordgo.MessageEmbed) {
+	c.sendEmbedTo(c.config.ChannelID, embed)
+}
+
+// sendEmbedTo queues an embed for a channel, or for the webhook's channel in
+// webhook-only mode
+func (c *Client) sendEmbedTo(channelID string, embed *discordgo.MessageEmbed) {
+	c.sendMessageTo(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+// sendMessageTo queues a message with content, such as mentions, for a channel
+func (c *Client) sendMessageTo(channelID string, message *discordgo.MessageSend) {
+	c.enqueue(&outbound{channelID: channelID, message: message})
 }
@@ -0,0 +1,211 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"Meiko/internal/archive"
+	"Meiko/internal/database"
+	"Meiko/internal/textutil"
+)
+
+const (
+	// digestTitle starts the title of every recap post, so earlier recaps can
+	// be found among the pinned messages
+	digestTitle = "📋 Daily Recap"
+	// digestThreadArchive is how long, in minutes, the incidents thread stays
+	// open without activity
+	digestThreadArchive = 1440
+	// maxEmbedDescription is Discord's limit on an embed description
+	maxEmbedDescription = 4096
+)
+
+// StartDailyDigest posts a recap of the previous day at discord.digest.run_at
+// each day when the digest is enabled. build is called for the report each time.
+func (c *Client) StartDailyDigest(ctx context.Context, build func(start, end time.Time) (*archive.Report, error)) {
+	if !c.config.Digest.Enabled {
+		return
+	}
+
+	go func() {
+		for {
+			next := nextDigestRun(c.config.Digest.RunAt, time.Now())
+			c.logger.Debug("Discord", "Next daily recap scheduled", "at", next.Format("2006-01-02 15:04"))
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				end := time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, next.Location())
+				report, err := build(end.AddDate(0, 0, -1), end)
+				if err != nil {
+					c.logger.Error("Failed to build daily recap", "error", err)
+					continue
+				}
+				c.SendDailyDigest(report)
+			}
+		}
+	}()
+}
+
+// nextDigestRun returns the next time after now that the digest is posted
+func nextDigestRun(runAt string, now time.Time) time.Time {
+	at, _ := time.Parse("15:04", runAt)
+	next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// SendDailyDigest posts a recap of a report: the AI summary, call counts per
+// agency and the top incidents. The post is pinned and the incidents moved to
+// a thread on it when configured; a webhook can do neither, so in webhook-only
+// mode the incidents are always in the post.
+func (c *Client) SendDailyDigest(report *archive.Report) {
+	channelID := c.config.Digest.ChannelID
+	if !c.connected || (channelID == "" && c.webhook == nil) {
+		return
+	}
+
+	c.sending.Add(1)
+	defer c.sending.Add(-1)
+
+	date := report.Date.Format("Monday, January 2")
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s • %s", digestTitle, date),
+		Description: textutil.Truncate(report.Summary, maxEmbedDescription),
+		Color:       0x0099ff,
+		Timestamp:   report.GeneratedAt.Format(time.RFC3339),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Meiko Scanner"},
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Calls", Value: fmt.Sprintf("%d", report.TotalCalls), Inline: true},
+			{Name: "Airtime", Value: (time.Duration(report.TotalDuration) * time.Second).String(), Inline: true},
+			{Name: "Talkgroups", Value: fmt.Sprintf("%d", report.UniqueTalkgroups), Inline: true},
+		},
+	}
+	if embed.Description == "" {
+		embed.Description = "No AI summary available for this day."
+	}
+	if report.TotalCalls == 0 {
+		embed.Description = "No radio activity recorded."
+	}
+
+	if agencies := c.digestAgencies(report); agencies != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Calls by Agency", Value: agencies})
+	}
+	incidents := c.digestIncidents(report)
+	if incidents != "" && (!c.config.Digest.Thread || c.webhook != nil) {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Top Incidents", Value: textutil.Truncate(incidents, 1024)})
+	}
+
+	if c.webhook != nil {
+		if _, err := c.webhook.execute(c.session, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}); err != nil {
+			c.logger.Error("Failed to send daily recap", "error", err)
+			return
+		}
+		c.logger.Info("Posted daily recap to Discord", "date", report.Date.Format("2006-01-02"), "calls", report.TotalCalls)
+		return
+	}
+
+	message, err := c.session.ChannelMessageSendEmbed(channelID, embed)
+	if err != nil {
+		c.logger.Error("Failed to send daily recap", "error", err)
+		return
+	}
+
+	if c.config.Digest.Pin {
+		c.pinDigest(channelID, message.ID)
+	}
+
+	if c.config.Digest.Thread && incidents != "" {
+		thread, err := c.session.MessageThreadStart(channelID, message.ID, "Top incidents • "+date, digestThreadArchive)
+		if err != nil {
+			c.logger.Error("Failed to start daily recap thread", "error", err)
+			return
+		}
+		if _, err := c.session.ChannelMessageSend(thread.ID, textutil.Truncate(incidents, 2000)); err != nil {
+			c.logger.Error("Failed to post daily recap incidents", "error", err)
+		}
+	}
+
+	c.logger.Info("Posted daily recap to Discord", "date", report.Date.Format("2006-01-02"), "calls", report.TotalCalls)
+}
+
+// pinDigest pins a recap and unpins the recaps pinned before it, so only the
+// latest one stays pinned
+func (c *Client) pinDigest(channelID, messageID string) {
+	pinned, err := c.session.ChannelMessagesPinned(channelID)
+	if err != nil {
+		c.logger.Warn("Failed to list pinned messages", "error", err)
+	}
+	for _, message := range pinned {
+		if message.ID != messageID && c.isDigest(message) {
+			if err := c.session.ChannelMessageUnpin(channelID, message.ID); err != nil {
+				c.logger.Warn("Failed to unpin previous daily recap", "error", err)
+			}
+		}
+	}
+
+	if err := c.session.ChannelMessagePin(channelID, messageID); err != nil {
+		c.logger.Error("Failed to pin daily recap", "error", err)
+	}
+}
+
+// isDigest reports whether a message is a recap posted by this bot
+func (c *Client) isDigest(message *discordgo.Message) bool {
+	if c.session.State == nil || c.session.State.User == nil || message.Author == nil || message.Author.ID != c.session.State.User.ID {
+		return false
+	}
+	return len(message.Embeds) > 0 && strings.HasPrefix(message.Embeds[0].Title, digestTitle)
+}
+
+// digestAgencies lists the busiest agencies with their call counts
+func (c *Client) digestAgencies(report *archive.Report) string {
+	var lines []string
+	for i, agency := range report.Agencies {
+		if i == c.config.Digest.TopAgencies {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(report.Agencies)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("**%s**: %d", agency.Name, agency.Calls))
+	}
+	return textutil.Truncate(strings.Join(lines, "\n"), 1024)
+}
+
+// digestIncidents lists the highest severity calls, one per line
+func (c *Client) digestIncidents(report *archive.Report) string {
+	var lines []string
+	for i, call := range report.Notable {
+		if i == c.config.Digest.TopIncidents {
+			break
+		}
+		lines = append(lines, digestIncidentLine(c.redactor.Call(call)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// digestIncidentLine describes a call in one line, with the incident details
+// extracted by enrichment when it has them
+func digestIncidentLine(call *database.CallRecord) string {
+	label := call.TalkgroupAlias
+	if call.Enrichment != nil && call.Enrichment.IncidentType != "" {
+		label = strings.ToUpper(call.Enrichment.IncidentType[:1]) + call.Enrichment.IncidentType[1:]
+		if call.Enrichment.Location != "" {
+			label += " at " + call.Enrichment.Location
+		}
+		label += " • " + call.TalkgroupAlias
+	}
+
+	text := call.Transcription
+	if call.Translation != "" {
+		text = call.Translation
+	}
+	return fmt.Sprintf("`%s` **%s** (%d/5) — %s", call.Timestamp.Format("15:04"), label, call.Severity, textutil.Truncate(text, 150))
+}
@@ -0,0 +1,92 @@
+package discord
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"Meiko/internal/database"
+	"Meiko/internal/talkgroups"
+)
+
+// filterCall returns why a call should not be posted, or "" to post it.
+// Escalated calls skip the quiet hours but not the talkgroup and service type
+// filters, which say what the channel is for.
+func (c *Client) filterCall(call *database.CallRecord, serviceType talkgroups.ServiceType, escalate bool) string {
+	notifications := c.config.Notifications
+
+	if len(notifications.Talkgroups) > 0 && !slices.Contains(notifications.Talkgroups, call.TalkgroupID) {
+		return "talkgroup not selected"
+	}
+	if slices.Contains(notifications.ExcludeTalkgroups, call.TalkgroupID) {
+		return "talkgroup excluded"
+	}
+	if len(notifications.ServiceTypes) > 0 && !containsFold(notifications.ServiceTypes, string(serviceType)) {
+		return "service type not selected"
+	}
+	if containsFold(notifications.ExcludeServiceTypes, string(serviceType)) {
+		return "service type excluded"
+	}
+
+	if escalate || !c.quiet(call, serviceType) {
+		return ""
+	}
+	return "quiet hours"
+}
+
+// quiet reports whether a call is held back by quiet hours: it falls within
+// them, is from a talkgroup or service type they cover, and is neither severe
+// enough nor mentions an emergency keyword
+func (c *Client) quiet(call *database.CallRecord, serviceType talkgroups.ServiceType) bool {
+	quiet := c.config.Notifications.QuietHours
+	if !quiet.Enabled || !inQuietHours(quiet.Start, quiet.End, call.Timestamp) {
+		return false
+	}
+
+	covered := len(quiet.Talkgroups) == 0 && len(quiet.ServiceTypes) == 0
+	if slices.Contains(quiet.Talkgroups, call.TalkgroupID) || containsFold(quiet.ServiceTypes, string(serviceType)) {
+		covered = true
+	}
+	if !covered {
+		return false
+	}
+
+	if quiet.MinSeverity > 0 && call.Severity >= quiet.MinSeverity {
+		return false
+	}
+
+	text := strings.ToLower(call.Transcription + " " + call.Translation)
+	for _, keyword := range quiet.Keywords {
+		if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+			return false
+		}
+	}
+	return true
+}
+
+// inQuietHours reports whether a time of day is between start and end (HH:MM,
+// local time). Quiet hours ending earlier than they start run past midnight.
+func inQuietHours(start, end string, at time.Time) bool {
+	from, _ := time.Parse("15:04", start)
+	to, _ := time.Parse("15:04", end)
+
+	local := at.Local()
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := from.Hour()*60 + from.Minute()
+	endMinute := to.Hour()*60 + to.Minute()
+
+	if startMinute < endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	return minute >= startMinute || minute < endMinute
+}
+
+// containsFold reports whether a list contains a value, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
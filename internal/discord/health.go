@@ -0,0 +1,137 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// minTopicInterval respects Discord's limit of two topic changes per channel
+// every ten minutes
+const minTopicInterval = 5 * time.Minute
+
+// HealthReport holds the figures shown in health embeds and the channel topic
+type HealthReport struct {
+	HasStats    bool // CPU, memory, disk and temperature were collected
+	CPU         float64
+	Memory      float64
+	Disk        float64
+	Temperature float64 // 0 when no sensor is available
+	CallsToday  int64
+	QueueDepth  int // Recordings waiting to be processed
+	SDRRunning  int
+	SDRTotal    int // 0 when this instance does not run SDRTrunk
+}
+
+// StartHealthReports posts a health embed every monitoring.update_interval
+// when monitoring is enabled, and keeps the channel topic current when
+// update_channel_topic is set. collect is called for the figures each time.
+func (c *Client) StartHealthReports(ctx context.Context, collect func() HealthReport) {
+	updateTopic := c.config.Monitoring.UpdateChannelTopic
+	if updateTopic && c.webhook != nil {
+		c.logger.Warn("Discord channel topic updates need a bot token, not a webhook")
+		updateTopic = false
+	}
+	if !c.config.Monitoring.Enabled && !updateTopic {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(c.config.Monitoring.UpdateInterval) * time.Second)
+		defer ticker.Stop()
+
+		var lastTopic time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report := collect()
+				if c.config.Monitoring.Enabled {
+					c.sendHealthReport(report)
+				}
+				if updateTopic && time.Since(lastTopic) >= minTopicInterval {
+					c.updateTopic(report)
+					lastTopic = time.Now()
+				}
+			}
+		}
+	}()
+}
+
+// sendHealthReport posts a health embed to the default channel
+func (c *Client) sendHealthReport(report HealthReport) {
+	var fields []*discordgo.MessageEmbedField
+	if report.HasStats {
+		fields = append(fields,
+			&discordgo.MessageEmbedField{Name: "CPU", Value: fmt.Sprintf("%.1f%%", report.CPU), Inline: true},
+			&discordgo.MessageEmbedField{Name: "Memory", Value: fmt.Sprintf("%.1f%%", report.Memory), Inline: true},
+			&discordgo.MessageEmbedField{Name: "Disk", Value: fmt.Sprintf("%.1f%%", report.Disk), Inline: true},
+		)
+		if report.Temperature > 0 {
+			fields = append(fields, &discordgo.MessageEmbedField{Name: "Temperature", Value: fmt.Sprintf("%.1f°C", report.Temperature), Inline: true})
+		}
+	}
+	fields = append(fields,
+		&discordgo.MessageEmbedField{Name: "Calls Today", Value: fmt.Sprintf("%d", report.CallsToday), Inline: true},
+		&discordgo.MessageEmbedField{Name: "Queue", Value: fmt.Sprintf("%d waiting", report.QueueDepth), Inline: true},
+	)
+	if report.SDRTotal > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "SDRTrunk", Value: fmt.Sprintf("%d/%d running", report.SDRRunning, report.SDRTotal), Inline: true})
+	}
+
+	colors := map[string]int{"🟢": 0x00ff00, "🟡": 0xff9900, "🔴": 0xff0000}
+	embed := &discordgo.MessageEmbed{
+		Title:     report.status() + " System Health",
+		Color:     colors[report.status()],
+		Fields:    fields,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Meiko Scanner",
+		},
+	}
+
+	c.sendEmbed(embed)
+}
+
+// updateTopic sets the default channel's topic to a one-line summary, e.g.
+// "🟢 142 calls today | CPU 34%"
+func (c *Client) updateTopic(report HealthReport) {
+	if !c.connected || c.config.ChannelID == "" {
+		return
+	}
+
+	topic := fmt.Sprintf("%s %d calls today", report.status(), report.CallsToday)
+	if report.HasStats {
+		topic += fmt.Sprintf(" | CPU %.0f%%", report.CPU)
+		if report.Temperature > 0 {
+			topic += fmt.Sprintf(" | %.0f°C", report.Temperature)
+		}
+	}
+	if report.SDRTotal > 1 {
+		topic += fmt.Sprintf(" | SDR %d/%d", report.SDRRunning, report.SDRTotal)
+	}
+	if report.QueueDepth > 0 {
+		topic += fmt.Sprintf(" | %d queued", report.QueueDepth)
+	}
+
+	if _, err := c.session.ChannelEdit(c.config.ChannelID, &discordgo.ChannelEdit{Topic: topic}); err != nil {
+		c.logger.Error("Failed to update Discord channel topic", "error", err)
+	}
+}
+
+// status summarises SDRTrunk health as a traffic light: green when every
+// process is running (or none are expected), yellow when some are, red when
+// none are
+func (r HealthReport) status() string {
+	switch {
+	case r.SDRTotal == 0 || r.SDRRunning == r.SDRTotal:
+		return "🟢"
+	case r.SDRRunning > 0:
+		return "🟡"
+	default:
+		return "🔴"
+	}
+}
@@ -0,0 +1,195 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxQueuedMessages caps the outbound queue; the oldest message is
+	// dropped when it is full
+	maxQueuedMessages = 500
+	// batchThreshold is how many call notifications must be waiting for a
+	// channel before they are combined into one embed
+	batchThreshold = 3
+	// maxBatchSize is the most calls combined into one embed
+	maxBatchSize = 10
+	// maxSendAttempts is how many times a message is tried before it is dropped
+	maxSendAttempts = 5
+	// maxRetryDelay caps the doubling delay between attempts
+	maxRetryDelay = 30 * time.Second
+)
+
+// outbound is a message waiting to be sent
+type outbound struct {
+	channelID string
+	message   *discordgo.MessageSend
+	batch     *discordgo.MessageEmbedField // Summary line for call notifications that can be combined
+	color     int                          // Embed color used when combined
+	attempts  int
+
+	thread      *incidentThread // Incident the call belongs to, whose thread it is posted in
+	opensThread bool            // The call opened the incident; its thread is started on this message
+}
+
+// enqueue queues a message for the sender. Messages are dropped while
+// disconnected, as before.
+func (c *Client) enqueue(item *outbound) {
+	if !c.connected || (item.channelID == "" && c.webhook == nil) {
+		return
+	}
+
+	c.queueMu.Lock()
+	if len(c.queue) >= maxQueuedMessages {
+		c.queue = c.queue[1:]
+		c.sending.Add(-1)
+		c.logger.Warn("Discord queue full, dropped oldest message", "queued", maxQueuedMessages)
+	}
+	c.queue = append(c.queue, item)
+	c.sending.Add(1)
+	c.queueMu.Unlock()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runQueue sends queued messages one at a time until done is closed
+func (c *Client) runQueue(done <-chan struct{}) {
+	for {
+		items, message := c.next()
+		if items == nil {
+			select {
+			case <-done:
+				return
+			case <-c.wake:
+			}
+			continue
+		}
+
+		channelID := items[0].channelID
+		if thread := items[0].thread; thread != nil && !items[0].opensThread {
+			channelID = c.threadChannel(thread, channelID)
+		}
+
+		sent, err := c.post(channelID, message)
+		if err == nil {
+			if items[0].opensThread {
+				c.startThread(items[0].thread, sent)
+			}
+			c.sending.Add(-int32(len(items)))
+			continue
+		}
+
+		delay, retry := retryDelay(err, items[0].attempts)
+		if !retry || items[0].attempts+1 >= maxSendAttempts {
+			c.logger.Error("Failed to send Discord message", "error", err, "attempts", items[0].attempts+1, "calls", len(items))
+			c.sending.Add(-int32(len(items)))
+			continue
+		}
+
+		// Put the messages back at the front; more calls may queue up behind
+		// them while waiting and be combined on the next attempt
+		for _, item := range items {
+			item.attempts++
+		}
+		c.queueMu.Lock()
+		c.queue = append(items, c.queue...)
+		c.queueMu.Unlock()
+
+		c.logger.Warn("Discord send failed, retrying", "error", err, "retry_in", delay, "attempt", items[0].attempts)
+		select {
+		case <-done:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// next takes the next message off the queue. When enough call notifications
+// for the same channel are waiting, they are taken together and combined.
+// It returns nil when the queue is empty.
+func (c *Client) next() ([]*outbound, *discordgo.MessageSend) {
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+
+	if len(c.queue) == 0 {
+		return nil, nil
+	}
+	first := c.queue[0]
+	if first.batch == nil {
+		c.queue = c.queue[1:]
+		return []*outbound{first}, first.message
+	}
+
+	waiting := 0
+	for _, item := range c.queue {
+		if item.batch != nil && item.channelID == first.channelID {
+			waiting++
+		}
+	}
+	if waiting < batchThreshold {
+		c.queue = c.queue[1:]
+		return []*outbound{first}, first.message
+	}
+
+	var batch, rest []*outbound
+	for _, item := range c.queue {
+		if item.batch != nil && item.channelID == first.channelID && len(batch) < maxBatchSize {
+			batch = append(batch, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	c.queue = rest
+	return batch, combine(batch)
+}
+
+// combine builds one embed listing several calls
+func combine(items []*outbound) *discordgo.MessageSend {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("📻 %d calls", len(items)),
+		Color:     items[0].color,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Footer:    &discordgo.MessageEmbedFooter{Text: "Combined while Discord was busy • Meiko Scanner"},
+	}
+	for _, item := range items {
+		embed.Fields = append(embed.Fields, item.batch)
+	}
+	return &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+}
+
+// post sends a message to a channel, or through the webhook in webhook-only
+// mode. Rate limits are returned rather than waited out, so the queue can
+// combine calls while it waits.
+func (c *Client) post(channelID string, message *discordgo.MessageSend) (*discordgo.Message, error) {
+	if c.webhook != nil {
+		return c.webhook.execute(c.session, message, discordgo.WithRetryOnRatelimit(false))
+	}
+	return c.session.ChannelMessageSendComplex(channelID, message, discordgo.WithRetryOnRatelimit(false))
+}
+
+// retryDelay reports whether a failed send is worth retrying and how long to
+// wait first: as long as Discord asks for rate limits, otherwise doubling
+// from a second. Requests Discord rejected outright are not retried.
+func retryDelay(err error, attempts int) (time.Duration, bool) {
+	var rateLimit *discordgo.RateLimitError
+	if errors.As(err, &rateLimit) && rateLimit.RateLimit != nil && rateLimit.TooManyRequests != nil {
+		return max(rateLimit.RetryAfter, 100*time.Millisecond), true
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		status := restErr.Response.StatusCode
+		if status != http.StatusTooManyRequests && status < 500 {
+			return 0, false
+		}
+	}
+
+	return min(time.Duration(1<<min(attempts, 5))*time.Second, maxRetryDelay), true
+}
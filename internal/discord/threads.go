@@ -0,0 +1,140 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"Meiko/internal/database"
+	"Meiko/internal/textutil"
+)
+
+// threadArchiveMinutes is how long an incident thread stays open in Discord
+// without activity
+const threadArchiveMinutes = 60
+
+// incidentThread is a Discord thread for a significant call, which related
+// calls that follow are posted in
+type incidentThread struct {
+	id          string // Set once the thread is started; empty if starting it failed
+	name        string
+	systemID    string
+	talkgroupID string
+	location    string // Incident location from enrichment, if known
+	lastCall    time.Time
+}
+
+// threadsEnabled reports whether incident threads are used. Webhooks can't
+// start threads.
+func (c *Client) threadsEnabled() bool {
+	return c.config.Threads.Enabled && c.webhook == nil
+}
+
+// threadFor returns the open incident thread a call belongs to: one on the
+// same system for the same talkgroup or incident location, with a call within
+// the window. Threads past their window are closed.
+func (c *Client) threadFor(call *database.CallRecord) *incidentThread {
+	if !c.threadsEnabled() {
+		return nil
+	}
+
+	c.threadsMu.Lock()
+	defer c.threadsMu.Unlock()
+
+	window := time.Duration(c.config.Threads.Window) * time.Minute
+	open := c.threads[:0]
+	for _, thread := range c.threads {
+		if call.Timestamp.Sub(thread.lastCall) <= window {
+			open = append(open, thread)
+		}
+	}
+	c.threads = open
+
+	location := callLocation(call)
+	for _, thread := range c.threads {
+		if thread.systemID != call.SystemID {
+			continue
+		}
+		if thread.talkgroupID == call.TalkgroupID || (location != "" && strings.EqualFold(thread.location, location)) {
+			if call.Timestamp.After(thread.lastCall) {
+				thread.lastCall = call.Timestamp
+			}
+			return thread
+		}
+	}
+	return nil
+}
+
+// significant reports whether a call opens an incident thread: its priority
+// or severity, such as from a critical keyword, is high enough
+func (c *Client) significant(call *database.CallRecord) bool {
+	return c.threadsEnabled() &&
+		(call.Priority >= c.config.Threads.MinPriority || call.Severity >= c.config.Threads.MinSeverity)
+}
+
+// openThread starts tracking an incident for a significant call. The thread
+// itself is started on the call's notification once it is sent.
+func (c *Client) openThread(call *database.CallRecord) *incidentThread {
+	thread := &incidentThread{
+		name:        threadName(call),
+		systemID:    call.SystemID,
+		talkgroupID: call.TalkgroupID,
+		location:    callLocation(call),
+		lastCall:    call.Timestamp,
+	}
+
+	c.threadsMu.Lock()
+	c.threads = append(c.threads, thread)
+	c.threadsMu.Unlock()
+
+	return thread
+}
+
+// startThread starts an incident's thread on its first call's notification
+func (c *Client) startThread(thread *incidentThread, message *discordgo.Message) {
+	started, err := c.session.MessageThreadStart(message.ChannelID, message.ID, thread.name, threadArchiveMinutes)
+	if err != nil {
+		c.logger.Error("Failed to start incident thread", "error", err, "thread", thread.name)
+		return
+	}
+
+	c.threadsMu.Lock()
+	thread.id = started.ID
+	c.threadsMu.Unlock()
+
+	c.logger.Info("Started Discord incident thread", "thread", thread.name)
+}
+
+// threadChannel returns the thread to post a related call in, or the
+// channel if the thread couldn't be started
+func (c *Client) threadChannel(thread *incidentThread, channelID string) string {
+	c.threadsMu.Lock()
+	defer c.threadsMu.Unlock()
+
+	if thread.id == "" {
+		return channelID
+	}
+	return thread.id
+}
+
+// threadName names an incident thread after the call that opened it
+func threadName(call *database.CallRecord) string {
+	label := call.TalkgroupAlias
+	if call.Enrichment != nil && call.Enrichment.IncidentType != "" {
+		label = strings.ToUpper(call.Enrichment.IncidentType[:1]) + strings.ReplaceAll(call.Enrichment.IncidentType[1:], "_", " ")
+		if call.Enrichment.Location != "" {
+			label += " at " + call.Enrichment.Location
+		}
+	}
+	return textutil.Truncate(fmt.Sprintf("🚨 %s • %s", label, call.Timestamp.Format("15:04")), 100)
+}
+
+// callLocation returns a call's incident location from enrichment, if known
+func callLocation(call *database.CallRecord) string {
+	if call.Enrichment == nil {
+		return ""
+	}
+	return strings.TrimSpace(call.Enrichment.Location)
+}
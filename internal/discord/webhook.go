@@ -0,0 +1,37 @@
+package discord
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// webhookURLPattern matches a Discord webhook URL and captures its ID and token
+var webhookURLPattern = regexp.MustCompile(`^https://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/api(?:/v\d+)?/webhooks/(\d+)/([\w-]+)/?$`)
+
+// webhook posts messages to the channel a Discord webhook belongs to
+type webhook struct {
+	id    string
+	token string
+}
+
+// parseWebhookURL reads the ID and token from a webhook URL
+func parseWebhookURL(url string) (*webhook, error) {
+	match := webhookURLPattern.FindStringSubmatch(url)
+	if match == nil {
+		return nil, fmt.Errorf("invalid Discord webhook URL")
+	}
+	return &webhook{id: match[1], token: match[2]}, nil
+}
+
+// execute posts a message through the webhook and returns it once Discord has
+// accepted it
+func (w *webhook) execute(session *discordgo.Session, message *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return session.WebhookExecute(w.id, w.token, true, &discordgo.WebhookParams{
+		Content:         message.Content,
+		Embeds:          message.Embeds,
+		Files:           message.Files,
+		AllowedMentions: message.AllowedMentions,
+	}, options...)
+}
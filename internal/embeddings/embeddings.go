@@ -0,0 +1,268 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/llm"
+	"Meiko/internal/logger"
+)
+
+const (
+	// backfillBatch is how many calls are embedded per request while backfilling
+	backfillBatch = 32
+	// backfillInterval is how often calls missing an embedding are looked for
+	backfillInterval = 5 * time.Minute
+)
+
+// Match is a call similar to a search
+type Match struct {
+	CallID     int     `json:"call_id"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Cluster is a group of related calls, oldest first
+type Cluster struct {
+	CallIDs []int `json:"call_ids"`
+}
+
+// Service embeds transcriptions and searches them by meaning
+type Service struct {
+	embedder llm.Embedder
+	db       *database.Database
+	config   config.EmbeddingsConfig
+	timeout  time.Duration
+	logger   *logger.Logger
+}
+
+// New creates the semantic search service
+func New(embedder llm.Embedder, db *database.Database, cfg config.EmbeddingsConfig, timeout time.Duration, logger *logger.Logger) *Service {
+	return &Service{embedder: embedder, db: db, config: cfg, timeout: timeout, logger: logger}
+}
+
+// callText is the text embedded for a call; translations are preferred so
+// calls in any language are searchable in English
+func callText(call *database.CallRecord) string {
+	if call.Translation != "" {
+		return call.Translation
+	}
+	return call.Transcription
+}
+
+// EmbedCall stores the embedding of a call's transcription
+func (s *Service) EmbedCall(ctx context.Context, call *database.CallRecord) error {
+	if callText(call) == "" {
+		return nil
+	}
+	return s.embedCalls(ctx, []*database.CallRecord{call})
+}
+
+// embedCalls embeds calls in one request and stores the vectors
+func (s *Service) embedCalls(ctx context.Context, calls []*database.CallRecord) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	texts := make([]string, len(calls))
+	for i, call := range calls {
+		texts[i] = callText(call)
+	}
+
+	vectors, err := s.embedder.Embed(ctx, texts)
+	if err != nil {
+		return err
+	}
+	for i, call := range calls {
+		if err := s.db.StoreEmbedding(call.ID, s.config.Model, normalize(vectors[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start embeds calls that have no embedding from the current model, such as
+// calls from before semantic search was enabled, now and every few minutes
+func (s *Service) Start(ctx context.Context) {
+	go func() {
+		s.backfill(ctx)
+
+		ticker := time.NewTicker(backfillInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.backfill(ctx)
+			}
+		}
+	}()
+}
+
+// backfill embeds calls missing an embedding in batches, stopping at the
+// first failure until the next run
+func (s *Service) backfill(ctx context.Context) {
+	embedded := 0
+	for ctx.Err() == nil {
+		calls, err := s.db.GetCallsWithoutEmbedding(s.config.Model, backfillBatch)
+		if err != nil {
+			s.logger.Error("Failed to find calls to embed", "error", err)
+			return
+		}
+		if len(calls) == 0 {
+			break
+		}
+		if err := s.embedCalls(ctx, calls); err != nil {
+			if ctx.Err() == nil {
+				s.logger.Warn("Failed to embed calls", "error", err, "embedder", s.embedder.Name())
+			}
+			return
+		}
+		embedded += len(calls)
+	}
+
+	if embedded > 0 {
+		s.logger.Info("Embedded calls for semantic search", "calls", embedded, "embedder", s.embedder.Name())
+	}
+}
+
+// Search returns the calls in a time range closest in meaning to a query
+func (s *Service) Search(ctx context.Context, query string, start, end time.Time, limit int) ([]Match, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	vectors, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	return s.nearest(normalize(vectors[0]), 0, start, end, limit)
+}
+
+// Similar returns the calls in a time range closest in meaning to a call
+func (s *Service) Similar(callID int, start, end time.Time, limit int) ([]Match, error) {
+	vector, err := s.db.GetEmbedding(callID, s.config.Model)
+	if err != nil {
+		return nil, err
+	}
+	if vector == nil {
+		return nil, fmt.Errorf("call %d has no embedding yet", callID)
+	}
+	return s.nearest(vector, callID, start, end, limit)
+}
+
+// nearest ranks the calls in a time range by similarity to a vector,
+// leaving out the excluded call
+func (s *Service) nearest(vector []float32, exclude int, start, end time.Time, limit int) ([]Match, error) {
+	embeddings, err := s.db.GetEmbeddings(s.config.Model, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, embedding := range embeddings {
+		if embedding.CallID == exclude {
+			continue
+		}
+		if similarity := dot(vector, embedding.Vector); similarity >= s.config.MinSimilarity {
+			matches = append(matches, Match{CallID: embedding.CallID, Similarity: similarity})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// Clusters groups the calls in a time range whose transcriptions are about
+// the same thing. Each call joins the most similar cluster it is close enough
+// to, compared with the cluster's average vector. Clusters smaller than
+// minSize are left out, and the largest come first.
+func (s *Service) Clusters(start, end time.Time, minSize int) ([]Cluster, error) {
+	embeddings, err := s.db.GetEmbeddings(s.config.Model, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type group struct {
+		callIDs  []int
+		sum      []float32 // Sum of the members' vectors
+		centroid []float32 // Normalized sum
+	}
+	var groups []*group
+
+	for _, embedding := range embeddings {
+		var best *group
+		bestSimilarity := s.config.ClusterSimilarity
+		for _, g := range groups {
+			if similarity := dot(embedding.Vector, g.centroid); similarity >= bestSimilarity {
+				best, bestSimilarity = g, similarity
+			}
+		}
+
+		if best == nil {
+			best = &group{sum: make([]float32, len(embedding.Vector))}
+			groups = append(groups, best)
+		}
+		best.callIDs = append(best.callIDs, embedding.CallID)
+		for i, value := range embedding.Vector {
+			best.sum[i] += value
+		}
+		best.centroid = normalize(best.sum)
+	}
+
+	clusters := []Cluster{}
+	for _, g := range groups {
+		if len(g.callIDs) >= minSize {
+			clusters = append(clusters, Cluster{CallIDs: g.callIDs})
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].CallIDs) > len(clusters[j].CallIDs)
+	})
+	return clusters, nil
+}
+
+// Close releases the embedder
+func (s *Service) Close() error {
+	return s.embedder.Close()
+}
+
+// normalize scales a vector to unit length, so the dot product of two
+// normalized vectors is their cosine similarity
+func normalize(vector []float32) []float32 {
+	var sum float64
+	for _, value := range vector {
+		sum += float64(value) * float64(value)
+	}
+	if sum == 0 {
+		return vector
+	}
+
+	norm := float32(math.Sqrt(sum))
+	normalized := make([]float32, len(vector))
+	for i, value := range vector {
+		normalized[i] = value / norm
+	}
+	return normalized
+}
+
+// dot returns the dot product of two vectors. Vectors of different lengths,
+// from different models, are unrelated.
+func dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
@@ -0,0 +1,85 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/llm"
+)
+
+// Enricher extracts structured incident details from transcriptions
+type Enricher struct {
+	provider llm.Provider
+	config   config.EnrichmentConfig
+}
+
+// New creates an enricher using an LLM provider
+func New(provider llm.Provider, cfg config.EnrichmentConfig) *Enricher {
+	return &Enricher{provider: provider, config: cfg}
+}
+
+// Applies reports whether a call is long enough to be enriched
+func (e *Enricher) Applies(call *database.CallRecord) bool {
+	return call.Duration >= e.config.MinDuration
+}
+
+// Enrich asks the LLM for the incident type, severity, units and location of
+// a transcription
+func (e *Enricher) Enrich(ctx context.Context, transcription, talkgroup string) (*database.Enrichment, error) {
+	prompt := fmt.Sprintf(`Extract incident details from this public safety radio transcript from the %q talkgroup.
+Reply with only a JSON object with these fields:
+- "incident_type": one of %s
+- "severity": 1 (routine) to 5 (life-threatening or major incident)
+- "units": the unit IDs dispatched or mentioned, e.g. ["Engine 5", "Medic 12"]
+- "location": the address or place of the incident, or "" if none is given
+
+Transcript:
+%s`, talkgroup, strings.Join(e.config.IncidentTypes, ", "), transcription)
+
+	reply, err := e.provider.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("enrichment request failed: %w", err)
+	}
+	return e.parse(reply)
+}
+
+// parse decodes the LLM's reply, tolerating code fences and text around the
+// JSON object, and normalizes its fields
+func (e *Enricher) parse(reply string) (*database.Enrichment, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in enrichment reply: %q", reply)
+	}
+
+	var result database.Enrichment
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse enrichment reply: %w", err)
+	}
+
+	result.IncidentType = strings.ToLower(strings.TrimSpace(result.IncidentType))
+	if !slices.Contains(e.config.IncidentTypes, result.IncidentType) {
+		result.IncidentType = "other"
+	}
+	result.Severity = min(max(result.Severity, 0), 5)
+	result.Location = strings.TrimSpace(result.Location)
+
+	var units []string
+	for _, unit := range result.Units {
+		if unit = strings.TrimSpace(unit); unit != "" && !slices.Contains(units, unit) {
+			units = append(units, unit)
+		}
+	}
+	result.Units = units
+
+	return &result, nil
+}
+
+// Close releases the provider
+func (e *Enricher) Close() error {
+	return e.provider.Close()
+}
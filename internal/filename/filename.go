@@ -0,0 +1,222 @@
+// Package filename reads call details from recording filenames. Each recorder
+// names its recordings differently, so filenames are matched against format
+// templates: regular expressions whose named groups capture the details.
+package filename
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Auto tries every known format in turn
+const Auto = "auto"
+
+// Groups are the named groups a format's pattern can capture
+var Groups = []string{
+	"date", "time", // YYYYMMDD and HHMMSS
+	"timestamp", // Date and time together in the format's timestamp_layout
+	"unix",      // Unix time in seconds
+	"system", "site", "channel",
+	"to", "from", // Talkgroup and calling radio
+	"frequency", // In Hz, kHz or MHz
+}
+
+// Fields are the call details read from a filename. Details the format
+// doesn't capture are empty.
+type Fields struct {
+	Timestamp time.Time
+	System    string
+	Site      string
+	Channel   string // Channel name, such as T-Control for a control channel recording
+	To        string
+	From      string
+	Frequency string
+}
+
+// Template describes a filename format: a regular expression whose named
+// groups, from Groups, capture the call details
+type Template struct {
+	Name            string
+	Pattern         string
+	TimestampLayout string // Go time layout of the timestamp group
+}
+
+// Format is a compiled filename format template
+type Format struct {
+	Name    string
+	pattern *regexp.Regexp
+	layout  string // Layout of the timestamp group
+}
+
+// builtinFormats are the formats of well-known recorders, in the order Auto
+// tries them. A recorder with several formats has a template for each. SDRTrunk's system, site and channel names may contain
+// underscores, so they can't be told apart and are kept together as the
+// system; a T- channel name at the end of them is captured on its own.
+var builtinFormats = []Template{
+	{
+		// 20250607_203346Heart_of_Texas_Regional_Radio_System_(HOTRRS)_McLennan_T-Control__TO_198_FROM_3071.mp3
+		Name:    "sdrtrunk",
+		Pattern: `^(?P<date>\d{8})_(?P<time>\d{6})(?P<system>.*?)(?:_(?P<channel>T-[^_]*))?_+TO_(?P<to>[^_]+)(?:_+FROM_(?P<from>[^_]+))?`,
+	},
+	{
+		// 52198-1714567890_851012500.0-call_1234.wav
+		Name:    "trunk-recorder",
+		Pattern: `^(?P<to>\d+)-(?P<unix>\d{9,11})_(?P<frequency>\d+(?:\.\d+)?)(?:-call_\d+)?$`,
+	},
+	{
+		// 20240501_131415_851.0125_P25_BEE00_TG52198_RID1234567.wav
+		Name:    "dsdplus",
+		Pattern: `^(?P<date>\d{8})[ _](?P<time>\d{6})(?:[ _](?P<frequency>\d+\.\d+)(?:MHz)?)?.*?[ _]TG[ _]?(?P<to>\d+)(?:.*?[ _]RID[ _]?(?P<from>\d+))?`,
+	},
+	{
+		// SDRTrunk recordings without a talkgroup, such as control channel audio
+		Name:    "sdrtrunk",
+		Pattern: `^(?P<date>\d{8})_(?P<time>\d{6})(?P<system>.*?)(?:_(?P<channel>T-[^_]*).*)?$`,
+	},
+}
+
+// BuiltinNames returns the names of the built-in formats
+func BuiltinNames() []string {
+	var names []string
+	for _, format := range builtinFormats {
+		if !slices.Contains(names, format.Name) {
+			names = append(names, format.Name)
+		}
+	}
+	return names
+}
+
+// Compile checks a format template and prepares it for matching
+func Compile(template Template) (*Format, error) {
+	pattern, err := regexp.Compile(template.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	captured := 0
+	for _, name := range pattern.SubexpNames()[1:] {
+		if name == "" {
+			continue
+		}
+		if !isGroup(name) {
+			return nil, fmt.Errorf("unknown group %q, use %s", name, strings.Join(Groups, ", "))
+		}
+		captured++
+	}
+	if captured == 0 {
+		return nil, fmt.Errorf("pattern has no named groups")
+	}
+	return &Format{Name: template.Name, pattern: pattern, layout: template.TimestampLayout}, nil
+}
+
+// Parse reads the call details from a filename, with or without its
+// directory and extension. It reports false when the filename doesn't match.
+func (f *Format) Parse(name string) (Fields, bool) {
+	name = filepath.Base(name)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	match := f.pattern.FindStringSubmatch(name)
+	if match == nil {
+		return Fields{}, false
+	}
+	groups := make(map[string]string)
+	for i, group := range f.pattern.SubexpNames() {
+		if group != "" && match[i] != "" {
+			groups[group] = match[i]
+		}
+	}
+
+	fields := Fields{
+		System:    readable(groups["system"]),
+		Site:      readable(groups["site"]),
+		Channel:   groups["channel"],
+		To:        groups["to"],
+		From:      groups["from"],
+		Frequency: groups["frequency"],
+	}
+
+	switch {
+	case groups["unix"] != "":
+		if seconds, err := strconv.ParseInt(groups["unix"], 10, 64); err == nil {
+			fields.Timestamp = time.Unix(seconds, 0)
+		}
+	case groups["timestamp"] != "" && f.layout != "":
+		if timestamp, err := time.ParseInLocation(f.layout, groups["timestamp"], time.Local); err == nil {
+			fields.Timestamp = timestamp
+		}
+	case groups["date"] != "" && groups["time"] != "":
+		if timestamp, err := time.ParseInLocation("20060102150405", groups["date"]+groups["time"], time.Local); err == nil {
+			fields.Timestamp = timestamp
+		}
+	}
+
+	return fields, true
+}
+
+// Parser reads filenames in one format, or tries each format in turn
+type Parser struct {
+	formats []*Format
+}
+
+// NewParser creates a parser for a named format: one of the built-in formats,
+// one of the custom formats, or Auto to try the custom formats and then the
+// built-in ones
+func NewParser(name string, custom []Template) (*Parser, error) {
+	if name == "" {
+		name = Auto
+	}
+
+	var candidates []Template
+	if name == Auto {
+		candidates = append(append(candidates, custom...), builtinFormats...)
+	} else {
+		for _, format := range append(append([]Template{}, custom...), builtinFormats...) {
+			if format.Name == name {
+				candidates = append(candidates, format)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("unknown filename format %q", name)
+		}
+	}
+
+	parser := &Parser{}
+	for _, candidate := range candidates {
+		format, err := Compile(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("filename format %s: %w", candidate.Name, err)
+		}
+		parser.formats = append(parser.formats, format)
+	}
+	return parser, nil
+}
+
+// Parse reads the call details from a filename with the first format that
+// matches it, returning that format's name, or "" when none match
+func (p *Parser) Parse(name string) (Fields, string) {
+	for _, format := range p.formats {
+		if fields, ok := format.Parse(name); ok {
+			return fields, format.Name
+		}
+	}
+	return Fields{}, ""
+}
+
+// isGroup reports whether a group name is one of Groups
+func isGroup(name string) bool {
+	for _, group := range Groups {
+		if group == name {
+			return true
+		}
+	}
+	return false
+}
+
+// readable turns the underscores recorders write for spaces back into spaces
+func readable(name string) string {
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '_' }), " ")
+}
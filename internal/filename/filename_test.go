@@ -0,0 +1,173 @@
+package filename
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	custom := []Template{{
+		Name:            "scanner",
+		Pattern:         `^(?P<timestamp>\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2})_(?P<frequency>\d+\.\d+)MHz_TG(?P<to>\d+)(?:_ID(?P<from>\d+))?$`,
+		TimestampLayout: "2006-01-02T15-04-05",
+	}}
+
+	tests := []struct {
+		name      string
+		filename  string
+		format    string // Format expected to match, "" for none
+		timestamp time.Time
+		system    string
+		channel   string
+		to        string
+		from      string
+		frequency string
+	}{
+		{
+			name:      "sdrtrunk",
+			filename:  "/recordings/20250607_203346Heart_of_Texas_Regional_Radio_System_(HOTRRS)_McLennan_T-Control__TO_198_FROM_3071.mp3",
+			format:    "sdrtrunk",
+			timestamp: time.Date(2025, 6, 7, 20, 33, 46, 0, time.Local),
+			system:    "Heart of Texas Regional Radio System (HOTRRS) McLennan",
+			channel:   "T-Control",
+			to:        "198",
+			from:      "3071",
+		},
+		{
+			name:      "sdrtrunk without caller",
+			filename:  "20250607_203346County_P25_TO_52198.mp3",
+			format:    "sdrtrunk",
+			timestamp: time.Date(2025, 6, 7, 20, 33, 46, 0, time.Local),
+			system:    "County P25",
+			to:        "52198",
+		},
+		{
+			name:      "sdrtrunk without talkgroup",
+			filename:  "20250607_203346Heart_of_Texas_T-Control.mp3",
+			format:    "sdrtrunk",
+			timestamp: time.Date(2025, 6, 7, 20, 33, 46, 0, time.Local),
+			system:    "Heart of Texas",
+			channel:   "T-Control",
+		},
+		{
+			name:      "trunk-recorder",
+			filename:  "52198-1714567890_851012500.0-call_1234.wav",
+			format:    "trunk-recorder",
+			timestamp: time.Unix(1714567890, 0),
+			to:        "52198",
+			frequency: "851012500.0",
+		},
+		{
+			name:      "trunk-recorder without call number",
+			filename:  "52198-1714567890_851012500.wav",
+			format:    "trunk-recorder",
+			timestamp: time.Unix(1714567890, 0),
+			to:        "52198",
+			frequency: "851012500",
+		},
+		{
+			name:      "dsdplus",
+			filename:  "20240501_131415_851.0125_P25_BEE00_TG52198_RID1234567.wav",
+			format:    "dsdplus",
+			timestamp: time.Date(2024, 5, 1, 13, 14, 15, 0, time.Local),
+			to:        "52198",
+			from:      "1234567",
+			frequency: "851.0125",
+		},
+		{
+			name:      "dsdplus without caller",
+			filename:  "20240501 131415 851.0125MHz TG 52198.wav",
+			format:    "dsdplus",
+			timestamp: time.Date(2024, 5, 1, 13, 14, 15, 0, time.Local),
+			to:        "52198",
+			frequency: "851.0125",
+		},
+		{
+			name:      "custom",
+			filename:  "2024-05-01T13-14-15_851.0125MHz_TG52198_ID1234567.wav",
+			format:    "scanner",
+			timestamp: time.Date(2024, 5, 1, 13, 14, 15, 0, time.Local),
+			to:        "52198",
+			from:      "1234567",
+			frequency: "851.0125",
+		},
+		{
+			name:     "no timestamp",
+			filename: "dispatch_recording.mp3",
+		},
+		{
+			name:     "trunk-recorder with short timestamp",
+			filename: "52198-17145_851012500.wav",
+		},
+		{
+			name:     "empty",
+			filename: "",
+		},
+	}
+
+	parser, err := NewParser(Auto, custom)
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, format := parser.Parse(tt.filename)
+			if format != tt.format {
+				t.Fatalf("format = %q, want %q", format, tt.format)
+			}
+			if !fields.Timestamp.Equal(tt.timestamp) {
+				t.Errorf("timestamp = %v, want %v", fields.Timestamp, tt.timestamp)
+			}
+			if fields.System != tt.system {
+				t.Errorf("system = %q, want %q", fields.System, tt.system)
+			}
+			if fields.Channel != tt.channel {
+				t.Errorf("channel = %q, want %q", fields.Channel, tt.channel)
+			}
+			if fields.To != tt.to {
+				t.Errorf("to = %q, want %q", fields.To, tt.to)
+			}
+			if fields.From != tt.from {
+				t.Errorf("from = %q, want %q", fields.From, tt.from)
+			}
+			if fields.Frequency != tt.frequency {
+				t.Errorf("frequency = %q, want %q", fields.Frequency, tt.frequency)
+			}
+		})
+	}
+}
+
+func TestNewParserNamedFormat(t *testing.T) {
+	parser, err := NewParser("trunk-recorder", nil)
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+	if _, format := parser.Parse("20240501_131415_851.0125_P25_BEE00_TG52198_RID1234567.wav"); format != "" {
+		t.Errorf("trunk-recorder parser matched a DSDPlus filename as %q", format)
+	}
+	if _, err := NewParser("unknown", nil); err == nil {
+		t.Error("NewParser accepted an unknown format")
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		valid   bool
+	}{
+		{"named groups", `^(?P<to>\d+)-(?P<unix>\d+)$`, true},
+		{"invalid pattern", `^(?P<to>\d+`, false},
+		{"unknown group", `^(?P<talkgroup>\d+)$`, false},
+		{"no named groups", `^(\d+)$`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(Template{Name: tt.name, Pattern: tt.pattern})
+			if (err == nil) != tt.valid {
+				t.Errorf("Compile error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package frequency
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Frequencies outside this range are rejected as something else in a filename
+const (
+	minHz = 100_000        // 100 kHz
+	maxHz = 10_000_000_000 // 10 GHz
+)
+
+// units maps suffixes to their multiplier in Hz, longest first so "mhz"
+// isn't read as "hz"
+var units = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"ghz", 1e9},
+	{"mhz", 1e6},
+	{"khz", 1e3},
+	{"hz", 1},
+}
+
+// Normalize parses a frequency such as "851.0125", "851.0125MHz",
+// "460500 kHz" or "851012500" and returns it in Hz. Without a unit, values
+// below 10,000 are read as MHz, below 10,000,000 as kHz and above that as Hz.
+func Normalize(raw string) (int64, bool) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if value == "" {
+		return 0, false
+	}
+
+	multiplier := 0.0
+	for _, unit := range units {
+		if trimmed, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value = strings.TrimSpace(trimmed)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 || math.IsInf(number, 0) {
+		return 0, false
+	}
+
+	if multiplier == 0 {
+		switch {
+		case number < 1e4:
+			multiplier = 1e6
+		case number < 1e7:
+			multiplier = 1e3
+		default:
+			multiplier = 1
+		}
+	}
+
+	hz := int64(math.Round(number * multiplier))
+	if hz < minHz || hz > maxHz {
+		return 0, false
+	}
+	return hz, true
+}
+
+// Format returns a frequency in Hz as MHz for display, such as "851.0125 MHz"
+func Format(hz int64) string {
+	mhz := strconv.FormatFloat(float64(hz)/1e6, 'f', 6, 64)
+	mhz = strings.TrimSuffix(strings.TrimRight(mhz, "0"), ".")
+	return mhz + " MHz"
+}
+
+// Display formats a stored frequency for display, leaving values recorded
+// before frequencies were normalized as they are
+func Display(stored string) string {
+	if hz, err := strconv.ParseInt(stored, 10, 64); err == nil {
+		return Format(hz)
+	}
+	return stored
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"Meiko/internal/config"
+)
+
+// anthropicVersion is the Messages API version requests are made against
+const anthropicVersion = "2023-06-01"
+
+// anthropic uses the Anthropic Messages API
+type anthropic struct {
+	config config.LLMConfig
+	client *http.Client
+}
+
+func (a *anthropic) Generate(ctx context.Context, prompt string) (string, error) {
+	request := map[string]interface{}{
+		"model":      a.config.Model,
+		"max_tokens": 2048,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	headers := map[string]string{
+		"x-api-key":         a.config.APIKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var response struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := postJSON(ctx, a.client, endpoint(a.config.Endpoint, "/v1/messages"), headers, request, &response); err != nil {
+		return "", fmt.Errorf("Anthropic: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("empty Anthropic response")
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+func (a *anthropic) Name() string {
+	return "anthropic/" + a.config.Model
+}
+
+func (a *anthropic) Close() error {
+	return nil
+}
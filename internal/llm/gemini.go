@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+
+	"Meiko/internal/config"
+)
+
+// gemini uses the Google Gemini API
+type gemini struct {
+	client *genai.Client
+	model  string
+}
+
+func newGemini(ctx context.Context, cfg config.LLMConfig) (*gemini, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(cfg.APIKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	return &gemini{client: client, model: cfg.Model}, nil
+}
+
+func (g *gemini) Generate(ctx context.Context, prompt string) (string, error) {
+	resp, err := g.client.GenerativeModel(g.model).GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", fmt.Errorf("Gemini request failed: %w", err)
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("empty Gemini response")
+	}
+
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		fmt.Fprintf(&text, "%v", part)
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+func (g *gemini) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := g.client.EmbeddingModel(g.model)
+	batch := model.NewBatch()
+	for _, text := range texts {
+		batch.AddContent(genai.Text(text))
+	}
+
+	resp, err := model.BatchEmbedContents(ctx, batch)
+	if err != nil {
+		return nil, fmt.Errorf("Gemini embedding request failed: %w", err)
+	}
+
+	vectors := make([][]float32, len(resp.Embeddings))
+	for i, embedding := range resp.Embeddings {
+		vectors[i] = embedding.Values
+	}
+	return vectors, checkEmbeddings(vectors, texts)
+}
+
+func (g *gemini) Name() string {
+	return "gemini/" + g.model
+}
+
+func (g *gemini) Close() error {
+	return g.client.Close()
+}
@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"Meiko/internal/config"
+)
+
+// Provider generates text from a prompt with a language model
+type Provider interface {
+	// Generate returns the model's reply to a prompt
+	Generate(ctx context.Context, prompt string) (string, error)
+	// Name returns the provider and model, for logging
+	Name() string
+	// Close releases the provider's resources
+	Close() error
+}
+
+// Embedder converts text to embedding vectors for semantic search
+type Embedder interface {
+	// Embed returns one vector per text
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Name returns the provider and model, for logging
+	Name() string
+	// Close releases the provider's resources
+	Close() error
+}
+
+// New creates the provider selected in the configuration
+func New(ctx context.Context, cfg config.LLMConfig) (Provider, error) {
+	client := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+
+	switch cfg.Provider {
+	case "gemini":
+		return newGemini(ctx, cfg)
+	case "openai":
+		return &openAI{config: cfg, client: client}, nil
+	case "anthropic":
+		return &anthropic{config: cfg, client: client}, nil
+	case "ollama":
+		return &ollama{config: cfg, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider: %s", cfg.Provider)
+	}
+}
+
+// NewEmbedder creates an embedder for the provider selected in the
+// configuration. Anthropic has no embeddings API.
+func NewEmbedder(ctx context.Context, cfg config.LLMConfig) (Embedder, error) {
+	client := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+
+	switch cfg.Provider {
+	case "gemini":
+		return newGemini(ctx, cfg)
+	case "openai":
+		return &openAI{config: cfg, client: client}, nil
+	case "ollama":
+		return &ollama{config: cfg, client: client}, nil
+	default:
+		return nil, fmt.Errorf("no embeddings support for provider: %s", cfg.Provider)
+	}
+}
+
+// checkEmbeddings verifies a provider returned one vector per text
+func checkEmbeddings(vectors [][]float32, texts []string) error {
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("got %d embeddings for %d texts", len(vectors), len(texts))
+	}
+	return nil
+}
+
+// postJSON sends a JSON request and decodes the JSON response
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// endpoint joins a base URL and a path
+func endpoint(base, path string) string {
+	return strings.TrimRight(base, "/") + path
+}
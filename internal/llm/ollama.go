@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"Meiko/internal/config"
+)
+
+// ollama uses a local Ollama server, so summaries work offline
+type ollama struct {
+	config config.LLMConfig
+	client *http.Client
+}
+
+func (o *ollama) Generate(ctx context.Context, prompt string) (string, error) {
+	request := map[string]interface{}{
+		"model":  o.config.Model,
+		"prompt": prompt,
+		"stream": false,
+	}
+
+	var response struct {
+		Response string `json:"response"`
+	}
+	if err := postJSON(ctx, o.client, endpoint(o.config.Endpoint, "/api/generate"), nil, request, &response); err != nil {
+		return "", fmt.Errorf("Ollama: %w", err)
+	}
+	if strings.TrimSpace(response.Response) == "" {
+		return "", fmt.Errorf("empty Ollama response")
+	}
+	return strings.TrimSpace(response.Response), nil
+}
+
+func (o *ollama) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	request := map[string]interface{}{
+		"model": o.config.Model,
+		"input": texts,
+	}
+
+	var response struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := postJSON(ctx, o.client, endpoint(o.config.Endpoint, "/api/embed"), nil, request, &response); err != nil {
+		return nil, fmt.Errorf("Ollama: %w", err)
+	}
+	return response.Embeddings, checkEmbeddings(response.Embeddings, texts)
+}
+
+func (o *ollama) Name() string {
+	return "ollama/" + o.config.Model
+}
+
+func (o *ollama) Close() error {
+	return nil
+}
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"Meiko/internal/config"
+)
+
+// openAI uses the OpenAI chat completions API, which many local servers
+// such as llama.cpp and vLLM also provide
+type openAI struct {
+	config config.LLMConfig
+	client *http.Client
+}
+
+func (o *openAI) Generate(ctx context.Context, prompt string) (string, error) {
+	request := map[string]interface{}{
+		"model": o.config.Model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	headers := map[string]string{}
+	if o.config.APIKey != "" {
+		headers["Authorization"] = "Bearer " + o.config.APIKey
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, o.client, endpoint(o.config.Endpoint, "/chat/completions"), headers, request, &response); err != nil {
+		return "", fmt.Errorf("OpenAI: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("empty OpenAI response")
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+func (o *openAI) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	request := map[string]interface{}{
+		"model": o.config.Model,
+		"input": texts,
+	}
+
+	headers := map[string]string{}
+	if o.config.APIKey != "" {
+		headers["Authorization"] = "Bearer " + o.config.APIKey
+	}
+
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, o.client, endpoint(o.config.Endpoint, "/embeddings"), headers, request, &response); err != nil {
+		return nil, fmt.Errorf("OpenAI: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range response.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("OpenAI returned an embedding for unknown input %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, fmt.Errorf("OpenAI returned no embedding for input %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+func (o *openAI) Name() string {
+	return "openai/" + o.config.Model
+}
+
+func (o *openAI) Close() error {
+	return nil
+}
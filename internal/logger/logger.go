@@ -3,7 +3,6 @@ package logger
 import (
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"sync"
 	"time"
@@ -38,6 +37,9 @@ type Logger struct {
 	buffer     []LogEntry
 	bufferMu   sync.RWMutex
 	maxBuffer  int
+
+	subscribers map[chan LogEntry]LogLevel
+	subMu       sync.Mutex
 }
 
 // Color constants for terminal output
@@ -69,7 +71,7 @@ func New(config config.LoggingConfig) *Logger {
 
 	// Setup file logging if enabled
 	if config.FileLogging.Enabled {
-		file, err := os.OpenFile(config.FileLogging.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		file, err := openRotatingFile(config.FileLogging.Path, config.FileLogging.MaxSizeMB, config.FileLogging.MaxBackups)
 		if err != nil {
 			log.Printf("Failed to open log file: %v", err)
 		} else {
@@ -133,9 +135,6 @@ func (l *Logger) Success(message string, args ...interface{}) {
 
 // addToBuffer adds a log entry to the internal buffer
 func (l *Logger) addToBuffer(level LogLevel, component, message string) {
-	l.bufferMu.Lock()
-	defer l.bufferMu.Unlock()
-
 	entry := LogEntry{
 		Timestamp: time.Now(),
 		Level:     levelToString(level),
@@ -143,12 +142,16 @@ func (l *Logger) addToBuffer(level LogLevel, component, message string) {
 		Message:   message,
 	}
 
+	l.bufferMu.Lock()
 	l.buffer = append(l.buffer, entry)
 
 	// Keep only the last maxBuffer entries
 	if len(l.buffer) > l.maxBuffer {
 		l.buffer = l.buffer[len(l.buffer)-l.maxBuffer:]
 	}
+	l.bufferMu.Unlock()
+
+	l.publish(entry)
 }
 
 // GetRecentLogs returns recent log entries
@@ -252,6 +255,8 @@ func (l *Logger) logSuccess(component, message string, args ...interface{}) {
 	}
 	l.bufferMu.Unlock()
 
+	l.publish(entry)
+
 	var logEntry string
 	var coloredEntry string
 
@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files, e.g. meiko-2024-05-01T14-03-00.000.log.gz
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotatingFile is an append-only log file that is rotated once it reaches
+// maxSize. Rotated files are gzip-compressed in the background and only the
+// newest maxBackups are kept.
+type rotatingFile struct {
+	path       string
+	maxSize    int64 // 0 disables rotation
+	maxBackups int   // 0 keeps every backup
+	file       *os.File
+	size       int64
+	mutex      sync.Mutex
+	compressMu sync.Mutex // Serialises compression and pruning
+}
+
+// openRotatingFile opens or creates the log file, appending to it
+func openRotatingFile(path string, maxSizeMB, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	// Finish any compression interrupted by a restart
+	go r.compressBackups()
+	return r, nil
+}
+
+// Write appends to the log file, rotating first if the write would exceed the maximum size
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			// Keep logging to the current file rather than losing entries
+			fmt.Fprintf(os.Stderr, "Failed to rotate log file: %v\n", err)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// open opens the log file and records its current size
+func (r *rotatingFile) open() error {
+	if dir := filepath.Dir(r.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file to a timestamped backup and starts a new
+// one. The caller must hold the mutex.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	backup := r.backupPrefix() + time.Now().Format(backupTimeFormat) + filepath.Ext(r.path)
+	if err := os.Rename(r.path, backup); err != nil {
+		// Reopen so logging can continue in the oversized file
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rename log file: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	go r.compressBackups()
+	return nil
+}
+
+// backupPrefix returns the path prefix shared by backups, e.g. logs/meiko-
+func (r *rotatingFile) backupPrefix() string {
+	return strings.TrimSuffix(r.path, filepath.Ext(r.path)) + "-"
+}
+
+// backups lists rotated files, compressed or not, newest first
+func (r *rotatingFile) backups() []string {
+	matches, _ := filepath.Glob(r.backupPrefix() + "*")
+
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimPrefix(match, r.backupPrefix())
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), filepath.Ext(r.path))
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+
+	// Timestamps sort chronologically as strings
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups
+}
+
+// compressBackups gzips uncompressed backups and deletes all but the newest maxBackups
+func (r *rotatingFile) compressBackups() {
+	r.compressMu.Lock()
+	defer r.compressMu.Unlock()
+
+	backups := r.backups()
+	for i, backup := range backups {
+		if r.maxBackups > 0 && i >= r.maxBackups {
+			os.Remove(backup)
+			continue
+		}
+		if !strings.HasSuffix(backup, ".gz") {
+			if err := compressFile(backup); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to compress log file %s: %v\n", backup, err)
+			}
+		}
+	}
+}
+
+// compressFile gzips a file to path.gz and removes the original
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+	if _, err := io.Copy(writer, source); err != nil {
+		target.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		target.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := target.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	source.Close()
+	return os.Remove(path)
+}
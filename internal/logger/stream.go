@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// subscriberBuffer is how many entries a slow subscriber can fall behind
+// before new entries are dropped for it
+const subscriberBuffer = 256
+
+// Subscribe returns a channel that receives every new log entry at or above
+// minLevel, and a function that ends the subscription. Entries are dropped
+// rather than blocking logging when the subscriber falls behind.
+func (l *Logger) Subscribe(minLevel LogLevel) (<-chan LogEntry, func()) {
+	ch := make(chan LogEntry, subscriberBuffer)
+
+	l.subMu.Lock()
+	if l.subscribers == nil {
+		l.subscribers = make(map[chan LogEntry]LogLevel)
+	}
+	l.subscribers[ch] = minLevel
+	l.subMu.Unlock()
+
+	unsubscribe := func() {
+		l.subMu.Lock()
+		defer l.subMu.Unlock()
+		if _, ok := l.subscribers[ch]; ok {
+			delete(l.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// publish passes an entry to the subscribers whose level it meets
+func (l *Logger) publish(entry LogEntry) {
+	l.subMu.Lock()
+	defer l.subMu.Unlock()
+
+	level := entry.LogLevel()
+	for ch, minLevel := range l.subscribers {
+		if level < minLevel {
+			continue
+		}
+		select {
+		case ch <- entry:
+		default:
+			// Subscriber is behind, drop the entry
+		}
+	}
+}
+
+// LogLevel returns the entry's level. SUCCESS entries rank as INFO.
+func (e LogEntry) LogLevel() LogLevel {
+	if e.Level == "SUCCESS" {
+		return INFO
+	}
+	return parseLogLevel(e.Level)
+}
+
+// ParseLevel converts a level name to a LogLevel, rejecting unknown names
+func ParseLevel(level string) (LogLevel, error) {
+	switch strings.ToUpper(level) {
+	case "DEBUG", "INFO", "WARN", "WARNING", "ERROR", "SUCCESS":
+		return LogEntry{Level: strings.ToUpper(level)}.LogLevel(), nil
+	}
+	return INFO, fmt.Errorf("unknown log level %q", level)
+}
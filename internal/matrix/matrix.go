@@ -0,0 +1,80 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/notify"
+)
+
+// Client posts notifications to a Matrix room
+type Client struct {
+	config config.MatrixConfig
+	client *http.Client
+}
+
+// New creates a Matrix client
+func New(cfg config.MatrixConfig) *Client {
+	return &Client{config: cfg, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name identifies the transport in logs
+func (c *Client) Name() string {
+	return "matrix"
+}
+
+// Send posts a message to the room as a notice, which bots use so other bots
+// don't reply to it
+func (c *Client) Send(ctx context.Context, message notify.Message) error {
+	body, err := json.Marshal(map[string]string{
+		"msgtype":        "m.notice",
+		"body":           message.Text,
+		"format":         "org.matrix.custom.html",
+		"formatted_body": strings.ReplaceAll(message.HTML, "\n", "<br>"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Retries reuse the transaction ID, so the homeserver ignores a retry of
+	// a message it already posted
+	txnID := "meiko-" + message.ID
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(c.config.Homeserver, "/"), url.PathEscape(c.config.RoomID), txnID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Matrix request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var response struct {
+		ErrCode      string `json:"errcode"`
+		Error        string `json:"error"`
+		RetryAfterMs int    `json:"retry_after_ms"`
+	}
+	json.NewDecoder(resp.Body).Decode(&response)
+	err = fmt.Errorf("Matrix API error (status %d): %s", resp.StatusCode, strings.TrimSpace(response.ErrCode+" "+response.Error))
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &notify.RetryError{After: time.Duration(response.RetryAfterMs) * time.Millisecond, Err: err}
+	}
+	return err
+}
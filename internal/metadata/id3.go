@@ -0,0 +1,200 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxTagSize bounds how much of a file is read as its ID3 tag
+const maxTagSize = 16 << 20
+
+// readID3 reads the ID3v2 tag SDRTrunk writes to its MP3 recordings: the
+// talkgroup as the title, the calling radio as the artist, the system as the
+// album, and the remaining details as "Key:Value" pairs in the comment. It
+// returns nil when the file has no tag or the tag has no call details.
+func readID3(path string) (*Metadata, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(file, header); err != nil || string(header[:3]) != "ID3" {
+		return nil, nil
+	}
+	version, flags := header[3], header[5]
+	if version != 3 && version != 4 {
+		return nil, nil // ID3v2.2 uses three letter frame IDs and isn't written by recorders
+	}
+	size := syncsafe(header[6:10])
+	if size > maxTagSize {
+		return nil, fmt.Errorf("ID3 tag too large: %d bytes", size)
+	}
+	tag := make([]byte, size)
+	if _, err := io.ReadFull(file, tag); err != nil {
+		return nil, fmt.Errorf("failed to read ID3 tag: %w", err)
+	}
+
+	if flags&0x80 != 0 {
+		// Unsynchronisation inserts a zero after every 0xFF
+		tag = bytes.ReplaceAll(tag, []byte{0xFF, 0x00}, []byte{0xFF})
+	}
+	if flags&0x40 != 0 && len(tag) >= 4 {
+		// Skip the extended header, whose size includes itself only in v2.4
+		// Sizes are compared as int64, so a corrupt one can't overflow int on
+		// 32-bit systems
+		extended := int64(binary.BigEndian.Uint32(tag[:4])) + 4
+		if version == 4 {
+			extended = int64(syncsafe(tag[:4]))
+		}
+		if extended > int64(len(tag)) {
+			return nil, errors.New("invalid ID3 extended header")
+		}
+		tag = tag[extended:]
+	}
+
+	meta := &Metadata{Source: "id3"}
+	for len(tag) >= 10 && tag[0] != 0 {
+		id := string(tag[:4])
+		frameSize := int64(binary.BigEndian.Uint32(tag[4:8]))
+		if version == 4 {
+			frameSize = int64(syncsafe(tag[4:8]))
+		}
+		if frameSize > int64(len(tag)-10) {
+			break
+		}
+		applyFrame(meta, id, tag[10:10+frameSize])
+		tag = tag[10+frameSize:]
+	}
+
+	if meta.empty() {
+		return nil, nil
+	}
+	return meta, nil
+}
+
+// applyFrame copies the call details in one ID3 frame into the metadata
+func applyFrame(meta *Metadata, id string, frame []byte) {
+	if len(frame) == 0 {
+		return
+	}
+	encoding, body := frame[0], frame[1:]
+
+	switch id {
+	case "TIT2":
+		if meta.To == "" {
+			meta.To, meta.ToAlias = splitIdentifier(decodeText(encoding, body))
+		}
+	case "TPE1":
+		if meta.From == "" {
+			meta.From, meta.FromAlias = splitIdentifier(decodeText(encoding, body))
+		}
+	case "TALB":
+		if meta.System == "" {
+			meta.System = decodeText(encoding, body)
+		}
+	case "TDRC":
+		if timestamp, ok := parseTime(decodeText(encoding, body)); ok && meta.Timestamp.IsZero() {
+			meta.Timestamp = timestamp
+		}
+	case "COMM":
+		// Language and a short description come before the comment
+		if len(body) < 3 {
+			return
+		}
+		_, text := splitTerminated(encoding, body[3:])
+		applyPairs(meta, decodeText(encoding, text))
+	case "TXXX":
+		description, value := splitTerminated(encoding, body)
+		applyPairs(meta, decodeText(encoding, description)+":"+decodeText(encoding, value))
+	}
+}
+
+// applyPairs reads "Key:Value" pairs separated by semicolons or lines, as
+// SDRTrunk writes in the comment. Details found here are more specific than
+// the title and artist, so they take precedence.
+func applyPairs(meta *Metadata, text string) {
+	for _, pair := range strings.FieldsFunc(text, func(r rune) bool { return r == ';' || r == '\n' || r == '\r' }) {
+		key, value, ok := strings.Cut(pair, ":")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "date", "time", "timestamp":
+			if timestamp, ok := parseTime(value); ok {
+				meta.Timestamp = timestamp
+			}
+		case "system":
+			meta.System = value
+		case "site":
+			meta.Site = value
+		case "frequency":
+			meta.Frequency = value
+		case "to", "talkgroup":
+			meta.To, meta.ToAlias = splitIdentifier(value)
+		case "from", "radio":
+			meta.From, meta.FromAlias = splitIdentifier(value)
+		}
+	}
+}
+
+// syncsafe decodes a 28-bit ID3 integer stored in the low 7 bits of 4 bytes
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// splitTerminated splits a frame body at the terminator of its first string,
+// which is two zero bytes in the UTF-16 encodings and one otherwise
+func splitTerminated(encoding byte, body []byte) ([]byte, []byte) {
+	if encoding == 1 || encoding == 2 {
+		for i := 0; i+1 < len(body); i += 2 {
+			if body[i] == 0 && body[i+1] == 0 {
+				return body[:i], body[i+2:]
+			}
+		}
+		return body, nil
+	}
+	if i := bytes.IndexByte(body, 0); i >= 0 {
+		return body[:i], body[i+1:]
+	}
+	return body, nil
+}
+
+// decodeText decodes an ID3 text field in its declared encoding
+func decodeText(encoding byte, body []byte) string {
+	var text string
+	switch encoding {
+	case 0: // ISO-8859-1
+		runes := make([]rune, len(body))
+		for i, b := range body {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	case 1, 2: // UTF-16 with a byte order mark, or big-endian without
+		order := binary.ByteOrder(binary.BigEndian)
+		if encoding == 1 && len(body) >= 2 {
+			if body[0] == 0xFF && body[1] == 0xFE {
+				order = binary.LittleEndian
+			}
+			if (body[0] == 0xFF && body[1] == 0xFE) || (body[0] == 0xFE && body[1] == 0xFF) {
+				body = body[2:]
+			}
+		}
+		units := make([]uint16, len(body)/2)
+		for i := range units {
+			units[i] = order.Uint16(body[i*2:])
+		}
+		text = string(utf16.Decode(units))
+	default: // UTF-8
+		text = string(body)
+	}
+	return strings.TrimSpace(strings.TrimRight(text, "\x00"))
+}
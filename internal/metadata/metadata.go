@@ -0,0 +1,94 @@
+// Package metadata reads the call details recorders save alongside the audio:
+// ID3 tags in SDRTrunk's MP3 recordings and JSON sidecar files. They are more
+// reliable than details taken apart from the filename, which is only
+// needed when a recording has neither.
+package metadata
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata is what a recorder saved about a call. Fields it didn't record
+// are empty.
+type Metadata struct {
+	Timestamp time.Time // When the call started
+	System    string    // Radio system name
+	Site      string
+	To        string // Talkgroup ID
+	ToAlias   string // Talkgroup name given by the recorder's alias list
+	From      string // Radio ID of the calling unit
+	FromAlias string
+	Frequency string // As recorded, usually in Hz
+	Source    string // "sidecar" or "id3"
+}
+
+// Read returns the metadata of a recording, preferring a JSON sidecar file to
+// the recording's own tags. It returns nil when there is neither.
+func Read(path string) (*Metadata, error) {
+	meta, err := readSidecar(path)
+	if meta != nil || err != nil {
+		return meta, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".mp3") {
+		meta, err := readID3(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return meta, err
+	}
+	return nil, nil
+}
+
+// empty reports whether no call details were found
+func (m *Metadata) empty() bool {
+	return m.Timestamp.IsZero() && m.System == "" && m.Site == "" && m.To == "" &&
+		m.From == "" && m.Frequency == ""
+}
+
+// dateLayouts are the timestamp formats recorders are known to write
+var dateLayouts = []string{
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"20060102150405",
+	"20060102_150405",
+}
+
+// parseTime reads a timestamp written as Unix seconds or milliseconds, RFC
+// 3339, or one of dateLayouts in local time
+func parseTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "_-") && len(value) < 14 {
+		if seconds > 1e11 {
+			seconds /= 1000 // Milliseconds
+		}
+		return time.Unix(0, int64(seconds*float64(time.Second))), seconds > 0
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// splitIdentifier separates a radio or talkgroup ID from the alias recorders
+// often write after it, as in `1234 "Fire Dispatch"` or `1234 (Fire Dispatch)`.
+// A value without a numeric ID is all alias.
+func splitIdentifier(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	id, alias, _ := strings.Cut(value, " ")
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return "", strings.Trim(value, `"'`)
+	}
+	return id, strings.Trim(strings.TrimSpace(alias), `"'()[]`)
+}
@@ -0,0 +1,110 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sidecarKeys are the JSON keys each field is read from, in order of
+// preference. They cover SDRTrunk's streaming metadata and trunk-recorder's
+// call JSON, so recordings from either can be imported with their details.
+var sidecarKeys = struct {
+	timestamp, system, site, to, toAlias, from, fromAlias, frequency []string
+}{
+	timestamp: []string{"timestamp", "start_time", "date", "time"},
+	system:    []string{"system", "system_name", "short_name"},
+	site:      []string{"site", "site_name"},
+	to:        []string{"to", "talkgroup", "talkgroup_id"},
+	toAlias:   []string{"to_alias", "talkgroup_alias", "talkgroup_tag", "talkgroup_description"},
+	from:      []string{"from", "source", "radio_id", "unit"},
+	fromAlias: []string{"from_alias", "source_alias", "unit_alias"},
+	frequency: []string{"frequency", "freq"},
+}
+
+// SidecarPath returns the JSON file saved next to a recording, named either
+// like the recording with a .json extension or with .json appended, or ""
+// when there is none
+func SidecarPath(path string) string {
+	for _, candidate := range []string{strings.TrimSuffix(path, filepath.Ext(path)) + ".json", path + ".json"} {
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// readSidecar reads a recording's sidecar file, returning nil when there is none
+func readSidecar(path string) (*Metadata, error) {
+	sidecar := SidecarPath(path)
+	if sidecar == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(sidecar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+	meta, err := parseSidecar(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata file %s: %w", filepath.Base(sidecar), err)
+	}
+	return meta, nil
+}
+
+// parseSidecar reads call details from a JSON object
+func parseSidecar(data []byte) (*Metadata, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep IDs and frequencies exact
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{
+		System:    sidecarString(fields, sidecarKeys.system),
+		Site:      sidecarString(fields, sidecarKeys.site),
+		To:        sidecarString(fields, sidecarKeys.to),
+		ToAlias:   sidecarString(fields, sidecarKeys.toAlias),
+		From:      sidecarString(fields, sidecarKeys.from),
+		FromAlias: sidecarString(fields, sidecarKeys.fromAlias),
+		Frequency: sidecarString(fields, sidecarKeys.frequency),
+		Source:    "sidecar",
+	}
+	if timestamp, ok := parseTime(sidecarString(fields, sidecarKeys.timestamp)); ok {
+		meta.Timestamp = timestamp
+	}
+
+	// trunk-recorder lists the transmitting units, the first one starting the call
+	if meta.From == "" {
+		if sources, ok := fields["srcList"].([]interface{}); ok && len(sources) > 0 {
+			if source, ok := sources[0].(map[string]interface{}); ok {
+				meta.From = sidecarString(source, []string{"src"})
+				meta.FromAlias = sidecarString(source, []string{"tag"})
+			}
+		}
+	}
+
+	if meta.empty() {
+		return nil, errors.New("no call details found")
+	}
+	return meta, nil
+}
+
+// sidecarString returns the first of keys present as a string or number
+func sidecarString(fields map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		switch value := fields[key].(type) {
+		case string:
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		case json.Number:
+			return value.String()
+		}
+	}
+	return ""
+}
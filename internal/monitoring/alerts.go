@@ -0,0 +1,144 @@
+package monitoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// alertState tracks one metric between checks
+type alertState struct {
+	breached  bool      // Above the threshold and not yet cleared
+	notified  bool      // An alert was sent for the current breach
+	lastAlert time.Time // When the last breach alert was sent
+}
+
+// Alert is the JSON body posted to monitoring.alerts.webhook_url
+type Alert struct {
+	Metric    string    `json:"metric"` // cpu_usage, memory_usage, disk_usage or temperature
+	State     string    `json:"state"`  // breach or recovered
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	ClearAt   float64   `json:"clear_at"`
+	Unit      string    `json:"unit"`
+	Message   string    `json:"message"`
+	Host      string    `json:"host"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// checkThresholds checks if any thresholds are exceeded
+func (m *Monitor) checkThresholds(stats *SystemStats) {
+	thresholds := m.config.Thresholds
+	m.checkThreshold("cpu_usage", "CPU usage", stats.CPU, thresholds.CPUUsage, "%", "")
+	m.checkThreshold("memory_usage", "Memory usage", stats.Memory, thresholds.MemoryUsage, "%", "")
+	m.checkThreshold("disk_usage", "Disk usage", stats.Disk, thresholds.DiskUsage, "%", "")
+	if len(stats.Sensors) > 0 {
+		m.checkThreshold("temperature", "Temperature", stats.Temperature, thresholds.Temperature, "°C", formatSensors(stats.Sensors))
+	}
+}
+
+// checkThreshold alerts when a metric rises above its threshold and again once
+// it falls below the threshold minus the hysteresis, so a value hovering at
+// the threshold alerts once. After an alert the metric stays quiet for the
+// cooldown even if it clears and breaches again; a recovery is only sent for
+// a breach that was alerted.
+func (m *Monitor) checkThreshold(metric, name string, value, threshold float64, unit, details string) {
+	state, ok := m.alerts[metric]
+	if !ok {
+		state = &alertState{}
+		m.alerts[metric] = state
+	}
+
+	clearAt := threshold - m.config.Alerts.Hysteresis
+	alert := Alert{
+		Metric:    metric,
+		Value:     value,
+		Threshold: threshold,
+		ClearAt:   clearAt,
+		Unit:      unit,
+		Timestamp: time.Now(),
+	}
+
+	switch {
+	case value > threshold && !state.breached:
+		state.breached = true
+		m.logger.Warn("High "+strings.ToLower(name)+" detected", "value", value, "threshold", threshold)
+
+		cooldown := time.Duration(m.config.Alerts.Cooldown) * time.Minute
+		if !state.lastAlert.IsZero() && time.Since(state.lastAlert) < cooldown {
+			m.logger.Debug("Monitor", "Alert suppressed during cooldown", "metric", metric)
+			return
+		}
+		state.notified = true
+		state.lastAlert = alert.Timestamp
+
+		alert.State = "breach"
+		alert.Message = fmt.Sprintf("%s is %.1f%s (threshold %.1f%s)", name, value, unit, threshold, unit)
+		m.sendAlert("⚠️ High "+strings.ToLower(name), alert, details, false)
+
+	case value <= clearAt && state.breached:
+		state.breached = false
+		m.logger.Info(name+" back to normal", "value", value, "threshold", threshold)
+		if !state.notified {
+			return
+		}
+		state.notified = false
+
+		alert.State = "recovered"
+		alert.Message = fmt.Sprintf("%s is back to %.1f%s (clears at %.1f%s)", name, value, unit, clearAt, unit)
+		m.sendAlert("✅ "+name+" back to normal", alert, details, true)
+	}
+}
+
+// sendAlert passes an alert to the callback and posts it to Discord and the
+// webhook, if configured
+func (m *Monitor) sendAlert(title string, alert Alert, details string, recovered bool) {
+	if m.onAlert != nil {
+		m.onAlert(alert)
+	}
+
+	if m.discord != nil {
+		description := alert.Message
+		if details != "" {
+			description += "\n\n" + details
+		}
+		m.discord.SendHealthAlert(title, description, recovered)
+	}
+
+	if m.config.Alerts.WebhookURL != "" {
+		alert.Host, _ = os.Hostname()
+		m.postWebhook(alert)
+	}
+}
+
+// postWebhook sends an alert as JSON to the configured webhook
+func (m *Monitor) postWebhook(alert Alert) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		m.logger.Error("Failed to encode alert", "error", err)
+		return
+	}
+
+	resp, err := m.client.Post(m.config.Alerts.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		m.logger.Error("Failed to send alert webhook", "metric", alert.Metric, "error", err)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		m.logger.Error("Alert webhook rejected", "metric", alert.Metric, "status", resp.StatusCode)
+	}
+}
+
+// formatSensors lists sensor readings, one per line
+func formatSensors(sensors []Sensor) string {
+	lines := make([]string, len(sensors))
+	for i, sensor := range sensors {
+		lines[i] = fmt.Sprintf("`%s`: %.1f°C", sensor.Name, sensor.Temperature)
+	}
+	return strings.Join(lines, "\n")
+}
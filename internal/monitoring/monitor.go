@@ -2,6 +2,8 @@ package monitoring
 
 import (
 	"context"
+	"net/http"
+	"os"
 	"runtime"
 	"time"
 
@@ -21,6 +23,9 @@ type Monitor struct {
 	discord   *discord.Client
 	logger    *logger.Logger
 	startTime time.Time
+	alerts    map[string]*alertState // Threshold state per metric
+	client    *http.Client           // For alert webhooks
+	onAlert   func(Alert)
 }
 
 // SystemMonitor is an alias for backward compatibility
@@ -31,7 +36,8 @@ type SystemStats struct {
 	CPU         float64   `json:"cpu"`
 	Memory      float64   `json:"memory"`
 	Disk        float64   `json:"disk"`
-	Temperature float64   `json:"temperature"`
+	Temperature float64   `json:"temperature"` // Hottest sensor
+	Sensors     []Sensor  `json:"sensors,omitempty"`
 	Timestamp   time.Time `json:"timestamp"`
 }
 
@@ -42,9 +48,16 @@ func New(config config.MonitoringConfig, discord *discord.Client, logger *logger
 		discord:   discord,
 		logger:    logger,
 		startTime: time.Now(),
+		alerts:    make(map[string]*alertState),
+		client:    &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
+// OnAlert sets a callback for threshold breaches and recoveries. It must be set before Start.
+func (m *Monitor) OnAlert(fn func(Alert)) {
+	m.onAlert = fn
+}
+
 // Start begins system monitoring
 func (m *Monitor) Start(ctx context.Context) {
 	if !m.config.Enabled {
@@ -111,32 +124,13 @@ func (m *Monitor) getSystemStats() (*SystemStats, error) {
 	}
 	stats.Disk = diskInfo.UsedPercent
 
-	// Temperature (try to get thermal data, fallback to 0.0)
-	if temps, err := host.SensorsTemperatures(); err == nil && len(temps) > 0 {
-		// Use the first available temperature sensor
-		stats.Temperature = temps[0].Temperature
-	} else {
-		stats.Temperature = 0.0
-	}
+	// Temperature, reported as the hottest sensor (0 when none are available)
+	stats.Sensors = readTemperatures()
+	stats.Temperature = hottest(stats.Sensors)
 
 	return stats, nil
 }
 
-// checkThresholds checks if any thresholds are exceeded
-func (m *Monitor) checkThresholds(stats *SystemStats) {
-	if stats.CPU > m.config.Thresholds.CPUUsage {
-		m.logger.Warn("High CPU usage detected", "usage", stats.CPU, "threshold", m.config.Thresholds.CPUUsage)
-	}
-
-	if stats.Memory > m.config.Thresholds.MemoryUsage {
-		m.logger.Warn("High memory usage detected", "usage", stats.Memory, "threshold", m.config.Thresholds.MemoryUsage)
-	}
-
-	if stats.Disk > m.config.Thresholds.DiskUsage {
-		m.logger.Warn("High disk usage detected", "usage", stats.Disk, "threshold", m.config.Thresholds.DiskUsage)
-	}
-}
-
 // GetCurrentStats returns the current system statistics
 func (m *Monitor) GetCurrentStats() *SystemStats {
 	stats, err := m.getSystemStats()
@@ -153,24 +147,53 @@ func (m *Monitor) GetCurrentStats() *SystemStats {
 	return stats
 }
 
-// GetSystemInfo returns system information
+// GetSystemInfo returns host, Go runtime and Meiko process information. The
+// uptime key is Meiko's own uptime in seconds; system_uptime is the host's.
 func (m *Monitor) GetSystemInfo() map[string]interface{} {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	goInfo := map[string]interface{}{
+		"version":     runtime.Version(),
+		"goroutines":  runtime.NumGoroutine(),
+		"cpus":        runtime.NumCPU(),
+		"heap_alloc":  memStats.HeapAlloc,
+		"sys":         memStats.Sys,
+		"gc_cycles":   memStats.NumGC,
+		"gc_pause_ns": memStats.PauseTotalNs,
+	}
+	if memStats.NumGC > 0 {
+		goInfo["last_gc_at"] = time.Unix(0, int64(memStats.LastGC))
+	}
+
+	info := map[string]interface{}{
+		"os":           runtime.GOOS,
+		"architecture": runtime.GOARCH,
+		"hostname":     "Unknown",
+		"uptime":       time.Since(m.startTime).Seconds(),
+		"started_at":   m.startTime,
+		"pid":          os.Getpid(),
+		"go":           goInfo,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		info["hostname"] = hostname
+	}
+
 	hostInfo, err := host.Info()
 	if err != nil {
 		m.logger.Error("Failed to get host info", "error", err)
-		return map[string]interface{}{
-			"os":           runtime.GOOS,
-			"architecture": runtime.GOARCH,
-			"hostname":     "Unknown",
-			"uptime":       time.Since(m.startTime).Seconds(),
-		}
+		return info
 	}
 
-	return map[string]interface{}{
-		"os":            hostInfo.OS,
-		"architecture":  hostInfo.KernelArch,
-		"hostname":      hostInfo.Hostname,
-		"uptime":        time.Since(m.startTime).Seconds(),
-		"system_uptime": hostInfo.Uptime,
+	info["os"] = hostInfo.OS
+	info["platform"] = hostInfo.Platform
+	info["platform_version"] = hostInfo.PlatformVersion
+	info["kernel"] = hostInfo.KernelVersion
+	info["architecture"] = hostInfo.KernelArch
+	info["hostname"] = hostInfo.Hostname
+	info["system_uptime"] = hostInfo.Uptime
+	info["boot_time"] = time.Unix(int64(hostInfo.BootTime), 0)
+	if hostInfo.VirtualizationRole == "guest" {
+		info["virtualization"] = hostInfo.VirtualizationSystem
 	}
+	return info
 }
@@ -0,0 +1,125 @@
+package monitoring
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// maxPlausibleTemperature filters out sensors reporting garbage
+const maxPlausibleTemperature = 150.0
+
+// Sensor is one temperature reading in Celsius
+type Sensor struct {
+	Name        string  `json:"name"`
+	Temperature float64 `json:"temperature"`
+	Source      string  `json:"source"` // thermal_zone, hwmon or vcgencmd
+}
+
+// readTemperatures collects every available temperature sensor. Linux thermal
+// zones come first, then hwmon sensors through gopsutil; vcgencmd is asked on
+// a Raspberry Pi where neither is readable, such as in some containers.
+func readTemperatures() []Sensor {
+	sensors := readThermalZones()
+
+	seen := make(map[string]bool, len(sensors))
+	for _, sensor := range sensors {
+		seen[sensor.Name] = true
+	}
+	// gopsutil returns partial results alongside warnings, so errors are ignored
+	temps, _ := host.SensorsTemperatures()
+	for _, temp := range temps {
+		name := strings.TrimSuffix(temp.SensorKey, "_input")
+		if seen[name] || !plausibleTemperature(temp.Temperature) {
+			continue
+		}
+		seen[name] = true
+		sensors = append(sensors, Sensor{Name: name, Temperature: temp.Temperature, Source: "hwmon"})
+	}
+
+	if len(sensors) == 0 {
+		if sensor, ok := readVcgencmd(); ok {
+			sensors = append(sensors, sensor)
+		}
+	}
+
+	sort.Slice(sensors, func(i, j int) bool { return sensors[i].Name < sensors[j].Name })
+	return sensors
+}
+
+// hottest returns the highest reading, or zero without sensors
+func hottest(sensors []Sensor) float64 {
+	max := 0.0
+	for _, sensor := range sensors {
+		if sensor.Temperature > max {
+			max = sensor.Temperature
+		}
+	}
+	return max
+}
+
+// readThermalZones reads /sys/class/thermal/thermal_zone*/temp, which holds
+// millidegrees Celsius. Zones are named by their type, e.g. cpu-thermal.
+func readThermalZones() []Sensor {
+	zones, _ := filepath.Glob("/sys/class/thermal/thermal_zone*")
+
+	var sensors []Sensor
+	for _, zone := range zones {
+		data, err := os.ReadFile(filepath.Join(zone, "temp"))
+		if err != nil {
+			continue
+		}
+		millidegrees, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+		if err != nil {
+			continue
+		}
+		temperature := millidegrees / 1000
+		if !plausibleTemperature(temperature) {
+			continue
+		}
+
+		name := filepath.Base(zone)
+		if data, err := os.ReadFile(filepath.Join(zone, "type")); err == nil && strings.TrimSpace(string(data)) != "" {
+			name = strings.TrimSpace(string(data))
+		}
+		sensors = append(sensors, Sensor{Name: name, Temperature: temperature, Source: "thermal_zone"})
+	}
+	return sensors
+}
+
+// readVcgencmd asks the Raspberry Pi firmware for the SoC temperature. The
+// output looks like temp=48.3'C.
+func readVcgencmd() (Sensor, bool) {
+	path, err := exec.LookPath("vcgencmd")
+	if err != nil {
+		return Sensor{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "measure_temp").Output()
+	if err != nil {
+		return Sensor{}, false
+	}
+
+	value := strings.TrimSpace(string(output))
+	value = strings.TrimPrefix(value, "temp=")
+	value = strings.TrimSuffix(value, "'C")
+	temperature, err := strconv.ParseFloat(value, 64)
+	if err != nil || !plausibleTemperature(temperature) {
+		return Sensor{}, false
+	}
+	return Sensor{Name: "soc", Temperature: temperature, Source: "vcgencmd"}, true
+}
+
+// plausibleTemperature rejects unset and nonsensical readings
+func plausibleTemperature(temperature float64) bool {
+	return temperature > 0 && temperature < maxPlausibleTemperature
+}
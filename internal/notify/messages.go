@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"Meiko/internal/database"
+	"Meiko/internal/frequency"
+	"Meiko/internal/textutil"
+)
+
+// maxTranscription is the most of a transcription included in a message
+const maxTranscription = 1000
+
+// tagPattern matches the HTML tags in a message, removed for its plain text
+var tagPattern = regexp.MustCompile(`</?[a-z][^>]*>`)
+
+// newMessage builds a message from HTML lines
+func newMessage(kind Kind, lines ...string) Message {
+	body := strings.Join(lines, "\n")
+	return Message{
+		Kind: kind,
+		HTML: body,
+		Text: html.UnescapeString(tagPattern.ReplaceAllString(body, "")),
+	}
+}
+
+// SendCall posts a transcribed call
+func (n *Notifier) SendCall(call *database.CallRecord) {
+	call = n.redactor.Call(call)
+	heading := fmt.Sprintf("📞 <b>%s</b>", html.EscapeString(call.TalkgroupAlias))
+	if call.TalkgroupGroup != "" {
+		heading += " • " + html.EscapeString(call.TalkgroupGroup)
+	}
+
+	lines := []string{heading}
+	switch {
+	case call.Translation != "":
+		lines = append(lines, html.EscapeString(textutil.Truncate(call.Translation, maxTranscription)),
+			fmt.Sprintf("<i>Translated from %s</i>", html.EscapeString(strings.ToUpper(call.Language))))
+	case call.Transcription != "":
+		lines = append(lines, html.EscapeString(textutil.Truncate(call.Transcription, maxTranscription)))
+	default:
+		lines = append(lines, "<i>No transcription available</i>")
+	}
+
+	details := []string{call.Timestamp.Format("15:04:05"), fmt.Sprintf("%ds", call.Duration)}
+	if call.Frequency != "" {
+		details = append(details, frequency.Display(call.Frequency))
+	}
+	if call.Severity > 0 {
+		details = append(details, fmt.Sprintf("severity %d/5", call.Severity))
+	}
+	if system, ok := n.systems[call.SystemID]; ok {
+		details = append(details, system.Name)
+	}
+	lines = append(lines, "<i>"+html.EscapeString(strings.Join(details, " • "))+"</i>")
+
+	message := newMessage(KindCalls, lines...)
+	message.severity = call.Severity
+	n.send(message)
+}
+
+// SendToneAlert posts a matched paging tone sequence
+func (n *Notifier) SendToneAlert(call *database.CallRecord, sequence database.ToneSequence) {
+	call = n.redactor.Call(call)
+	lines := []string{
+		fmt.Sprintf("🚨 <b>Tone out: %s</b>", html.EscapeString(sequence.Station)),
+		fmt.Sprintf("📻 %s • %s", html.EscapeString(call.TalkgroupAlias), call.Timestamp.Format("15:04:05")),
+		fmt.Sprintf("<code>%.1f Hz → %.1f Hz</code>", sequence.ToneA, sequence.ToneB),
+	}
+	if call.Transcription != "" {
+		lines = append(lines, html.EscapeString(textutil.Truncate(call.Transcription, maxTranscription)))
+	}
+	n.send(newMessage(KindTones, lines...))
+}
+
+// SendHealthAlert posts a system health alert, or its recovery
+func (n *Notifier) SendHealthAlert(title, description string, recovered bool) {
+	n.send(newMessage(KindHealth, "<b>"+html.EscapeString(title)+"</b>", html.EscapeString(description)))
+}
+
+// SendProcessAlert reports an SDRTrunk process exiting or restarting
+func (n *Notifier) SendProcessAlert(systemID, message string, running, total int) {
+	title := "📡 SDRTrunk"
+	if system, ok := n.systems[systemID]; ok {
+		title += ": " + system.Name
+	}
+	n.send(newMessage(KindHealth,
+		"<b>"+html.EscapeString(title)+"</b>",
+		html.EscapeString(message),
+		fmt.Sprintf("<i>%d/%d processes running</i>", running, total)))
+}
+
+// SendDeviceAlert reports an SDR receiver dropping off or returning to the USB bus
+func (n *Notifier) SendDeviceAlert(device string, present bool, missing int) {
+	title := "🔌 SDR receiver disconnected"
+	if present {
+		title = "🔌 SDR receiver reconnected"
+	}
+	lines := []string{"<b>" + title + "</b>", html.EscapeString(device)}
+	if missing > 0 {
+		lines = append(lines, fmt.Sprintf("<i>%d device(s) still missing</i>", missing))
+	}
+	n.send(newMessage(KindHealth, lines...))
+}
+
+// SendStartup posts that Meiko started
+func (n *Notifier) SendStartup(appName, version string) {
+	n.send(newMessage(KindStartup, fmt.Sprintf("🚀 <b>%s started</b>", html.EscapeString(appName)), "Version "+html.EscapeString(version)+" is now running"))
+}
+
+// SendShutdown posts that Meiko is shutting down
+func (n *Notifier) SendShutdown(appName string) {
+	n.send(newMessage(KindShutdown, fmt.Sprintf("🛑 <b>%s shutting down</b>", html.EscapeString(appName))))
+}
@@ -0,0 +1,214 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+	"Meiko/internal/redaction"
+)
+
+// Kind is a type of notification, selected per transport in config
+type Kind string
+
+const (
+	KindCalls    Kind = "calls"
+	KindTones    Kind = "tones"
+	KindHealth   Kind = "health"
+	KindStartup  Kind = "startup"
+	KindShutdown Kind = "shutdown"
+)
+
+const (
+	// queueSize is how many messages can wait for each transport before new
+	// ones are dropped
+	queueSize = 100
+	// maxAttempts is how many times a message is sent before it is dropped
+	maxAttempts = 3
+	// sendTimeout limits each attempt to send a message
+	sendTimeout = 15 * time.Second
+)
+
+// Message is a notification ready to post. HTML only uses the tags Telegram
+// and Matrix both render: b, i, code and a; lines are separated by newlines.
+type Message struct {
+	ID       string // Unique to the message, the same on each attempt to send it
+	Kind     Kind
+	Text     string // Plain text version
+	HTML     string
+	severity int // Call severity, checked against each transport's threshold
+}
+
+// Transport posts messages to a chat service
+type Transport interface {
+	Name() string
+	Send(ctx context.Context, message Message) error
+}
+
+// RetryError is returned by a transport when the service asks it to wait
+// before sending again
+type RetryError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// route is a transport with the notifications it takes and its queue
+type route struct {
+	transport   Transport
+	kinds       map[Kind]bool
+	minSeverity int
+	queue       chan Message
+}
+
+// Notifier posts notifications to the chat services other than Discord.
+// Each transport has its own queue, so a slow service doesn't hold up another.
+type Notifier struct {
+	routes   []*route
+	systems  map[string]config.SystemConfig
+	redactor *redaction.Redactor // Hides sensitive details in calls, nil when disabled
+	logger   *logger.Logger
+	sending  atomic.Int32 // Messages queued or being sent, waited on by Flush
+	sent     atomic.Int64 // Numbers messages for their IDs
+	started  int64
+	done     chan struct{}
+}
+
+// New creates a notifier with no transports
+func New(logger *logger.Logger) *Notifier {
+	return &Notifier{logger: logger, started: time.Now().UnixNano(), done: make(chan struct{})}
+}
+
+// Add sends the selected kinds of notification to a transport. Calls below
+// minSeverity are left out.
+func (n *Notifier) Add(transport Transport, kinds []string, minSeverity int) {
+	r := &route{
+		transport:   transport,
+		kinds:       make(map[Kind]bool, len(kinds)),
+		minSeverity: minSeverity,
+		queue:       make(chan Message, queueSize),
+	}
+	for _, kind := range kinds {
+		r.kinds[Kind(kind)] = true
+	}
+	n.routes = append(n.routes, r)
+}
+
+// Enabled reports whether any transport was added
+func (n *Notifier) Enabled() bool {
+	return len(n.routes) > 0
+}
+
+// SetSystems sets the radio systems used to label calls
+func (n *Notifier) SetSystems(systems []config.SystemConfig) {
+	n.systems = make(map[string]config.SystemConfig, len(systems))
+	for _, system := range systems {
+		n.systems[system.ID] = system
+	}
+}
+
+// SetRedactor sets the rules that hide sensitive details in posted calls
+func (n *Notifier) SetRedactor(redactor *redaction.Redactor) {
+	n.redactor = redactor
+}
+
+// Start starts sending queued messages
+func (n *Notifier) Start() {
+	for _, r := range n.routes {
+		go n.run(r)
+	}
+}
+
+// Stop stops sending. Messages still queued are dropped; call Flush first to
+// send them.
+func (n *Notifier) Stop() {
+	close(n.done)
+}
+
+// Flush waits up to timeout for queued messages to be sent and reports
+// whether they all were
+func (n *Notifier) Flush(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for n.sending.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
+
+// send queues a message for every transport that takes its kind
+func (n *Notifier) send(message Message) {
+	message.ID = fmt.Sprintf("%d.%d", n.started, n.sent.Add(1))
+	for _, r := range n.routes {
+		if !r.kinds[message.Kind] {
+			continue
+		}
+		if message.Kind == KindCalls && message.severity < r.minSeverity {
+			continue
+		}
+
+		n.sending.Add(1)
+		select {
+		case r.queue <- message:
+		default:
+			n.sending.Add(-1)
+			n.logger.Warn("Notification queue full, dropped message", "transport", r.transport.Name(), "kind", string(message.Kind))
+		}
+	}
+}
+
+// run sends a transport's messages in order until the notifier is stopped
+func (n *Notifier) run(r *route) {
+	for {
+		select {
+		case <-n.done:
+			return
+		case message := <-r.queue:
+			n.deliver(r.transport, message)
+			n.sending.Add(-1)
+		}
+	}
+}
+
+// deliver sends a message, retrying failures after the delay the service
+// asks for, or a couple of seconds
+func (n *Notifier) deliver(transport Transport, message Message) {
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err := transport.Send(ctx, message)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt == maxAttempts {
+			n.logger.Error("Failed to send notification", "transport", transport.Name(), "kind", string(message.Kind), "error", err)
+			return
+		}
+
+		delay := time.Duration(attempt) * 2 * time.Second
+		var retry *RetryError
+		if errors.As(err, &retry) && retry.After > 0 {
+			delay = retry.After
+		}
+		n.logger.Debug("Notify", "Notification failed, retrying", "transport", transport.Name(), "error", err, "retry_in", delay)
+
+		select {
+		case <-n.done:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"Meiko/internal/config"
 	"Meiko/internal/logger"
+	"Meiko/internal/usb"
 )
 
 // Checker performs system validation checks
@@ -26,15 +28,67 @@ func New(config *config.Config, logger *logger.Logger) *Checker {
 
 // RunAll runs all preflight checks
 func (c *Checker) RunAll() error {
-	checks := []struct {
+	type check struct {
 		name string
 		fn   func() error
-	}{
-		{"SDRTrunk Path", c.checkSDRTrunkPath},
-		{"Java Runtime", c.checkJavaRuntime},
-		{"Audio Output Directory", c.checkAudioOutputDir},
-		{"Transcription Config", c.checkTranscriptionConfig},
-		{"Database Path", c.checkDatabasePath},
+	}
+
+	// Agents only capture and servers only process, so each skips the other's checks
+	var checks []check
+	if c.config.Captures() {
+		for _, system := range c.config.CaptureSystems() {
+			suffix := ""
+			if system.ID != "" {
+				suffix = fmt.Sprintf(" (%s)", system.ID)
+			}
+			sdr := system.SDRTrunk
+			if c.config.ManagesSDRTrunk() {
+				checks = append(checks,
+					check{"SDRTrunk Path" + suffix, func() error { return checkSDRTrunkPath(sdr) }},
+					check{"Java Runtime" + suffix, func() error { return checkJavaRuntime(sdr) }},
+				)
+			}
+			checks = append(checks, check{"Audio Output Directory" + suffix, func() error { return checkAudioOutputDir(sdr) }})
+		}
+		for _, directory := range c.config.FileMonitor.Directories {
+			path := directory.Path
+			checks = append(checks, check{"Watched Directory (" + path + ")", func() error { return checkWatchDirectory(path) }})
+		}
+		if c.config.Preflight.CheckUSBDevices {
+			checks = append(checks, check{"USB Devices", c.checkUSBDevices})
+		}
+	}
+	if c.config.Processes() {
+		checks = append(checks,
+			check{"Transcription Config", c.checkTranscriptionConfig},
+			check{"Database Path", c.checkDatabasePath},
+		)
+		if c.needsFFprobe() {
+			checks = append(checks, check{"FFprobe", c.checkFFprobe})
+		}
+		if c.config.Transcode.Enabled || c.config.Tones.Enabled || c.config.VoiceDetection.Enabled {
+			checks = append(checks, check{"FFmpeg", c.checkFFmpeg})
+		}
+		if c.config.Transcode.Enabled {
+			checks = append(checks, check{"FFmpeg Opus Encoder", c.checkOpusEncoder})
+		}
+	}
+	if c.config.Preflight.CheckNetwork {
+		if c.config.Processes() {
+			if c.config.Transcription.Mode == "remote" {
+				checks = append(checks, check{"Transcription Endpoint", c.checkRemoteEndpoint})
+			}
+			if c.config.Discord.Enabled() {
+				checks = append(checks, check{"Discord Connectivity", c.checkDiscord})
+			}
+			if c.config.LLM.Enabled() {
+				checks = append(checks, check{"LLM API", c.checkLLM})
+			}
+		}
+		if c.config.Mode == "agent" {
+			checks = append(checks, check{"Server Connectivity", c.checkAgentServer})
+		}
+		checks = append(checks, check{"System Clock", c.checkClock})
 	}
 
 	for _, check := range checks {
@@ -49,8 +103,8 @@ func (c *Checker) RunAll() error {
 }
 
 // checkSDRTrunkPath validates the SDRTrunk executable path
-func (c *Checker) checkSDRTrunkPath() error {
-	path := c.config.SDRTrunk.Path
+func checkSDRTrunkPath(sdr config.SDRTrunkConfig) error {
+	path := sdr.Path
 	if path == "" {
 		return fmt.Errorf("SDRTrunk path is not configured")
 	}
@@ -63,8 +117,8 @@ func (c *Checker) checkSDRTrunkPath() error {
 }
 
 // checkJavaRuntime validates Java is available
-func (c *Checker) checkJavaRuntime() error {
-	javaPath := c.config.SDRTrunk.JavaPath
+func checkJavaRuntime(sdr config.SDRTrunkConfig) error {
+	javaPath := sdr.JavaPath
 	if javaPath == "" {
 		javaPath = "java"
 	}
@@ -83,8 +137,8 @@ func (c *Checker) checkJavaRuntime() error {
 }
 
 // checkAudioOutputDir validates the audio output directory
-func (c *Checker) checkAudioOutputDir() error {
-	dir := c.config.SDRTrunk.AudioOutputDir
+func checkAudioOutputDir(sdr config.SDRTrunkConfig) error {
+	dir := sdr.AudioOutputDir
 	if dir == "" {
 		return fmt.Errorf("audio output directory is not configured")
 	}
@@ -94,7 +148,7 @@ func (c *Checker) checkAudioOutputDir() error {
 	}
 
 	// Check if directory is writable
-	testFile := dir + "/.meiko_test"
+	testFile := filepath.Join(dir, ".meiko_test")
 	file, err := os.Create(testFile)
 	if err != nil {
 		return fmt.Errorf("audio output directory is not writable: %w", err)
@@ -105,6 +159,41 @@ func (c *Checker) checkAudioOutputDir() error {
 	return nil
 }
 
+// checkWatchDirectory validates a directory watched for another recorder's
+// recordings, which only needs to be readable
+func checkWatchDirectory(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("watched directory is not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("watched path is not a directory: %s", dir)
+	}
+	if _, err := os.ReadDir(dir); err != nil {
+		return fmt.Errorf("watched directory is not readable: %w", err)
+	}
+	return nil
+}
+
+// checkUSBDevices verifies that at least one SDR receiver is connected. The
+// IDs watched by the USB watchdog are used when configured.
+func (c *Checker) checkUSBDevices() error {
+	devices, err := usb.Scan()
+	if err != nil {
+		return fmt.Errorf("failed to list USB devices: %w", err)
+	}
+
+	receivers := usb.Match(devices, c.config.USBWatchdog.Devices)
+	if len(receivers) == 0 {
+		return fmt.Errorf("no SDR receivers found on the USB bus")
+	}
+	for _, device := range receivers {
+		c.logger.Info("Found SDR receiver", "device", device.String())
+	}
+
+	return nil
+}
+
 // checkTranscriptionConfig validates transcription configuration
 func (c *Checker) checkTranscriptionConfig() error {
 	switch c.config.Transcription.Mode {
@@ -148,7 +237,70 @@ func (c *Checker) checkRemoteTranscription() error {
 		return fmt.Errorf("remote transcription endpoint not configured")
 	}
 
-	// TODO: Add network connectivity check
+	// Reachability is checked with the network checks
+	return nil
+}
+
+// needsFFprobe reports whether a watched pattern matches recordings other
+// than MP3 and WAV, whose durations Meiko reads without ffprobe
+func (c *Checker) needsFFprobe() bool {
+	patterns := append([]string{}, c.config.FileMonitor.Patterns...)
+	for _, directory := range c.config.FileMonitor.Directories {
+		patterns = append(patterns, directory.Patterns...)
+	}
+	for _, pattern := range patterns {
+		if ext := strings.ToLower(filepath.Ext(pattern)); ext != ".mp3" && ext != ".wav" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFFprobe verifies ffprobe runs, as it measures the durations of
+// recordings other than MP3 and WAV
+func (c *Checker) checkFFprobe() error {
+	return c.checkTool(c.config.FFmpeg.FFprobePath, "ffmpeg.ffprobe_path")
+}
+
+// checkFFmpeg verifies ffmpeg runs, for transcoding and for decoding audio
+// for tone and voice detection
+func (c *Checker) checkFFmpeg() error {
+	return c.checkTool(c.config.FFmpeg.Path, "ffmpeg.path")
+}
+
+// checkTool verifies an FFmpeg executable runs and logs its version. setting
+// names the configuration that locates it, for the error message.
+func (c *Checker) checkTool(path, setting string) error {
+	name := filepath.Base(path)
+	if _, err := exec.LookPath(path); err != nil {
+		return fmt.Errorf("%s not found at %q; install FFmpeg (e.g. apt install ffmpeg) or set %s", name, path, setting)
+	}
+
+	output, err := exec.Command(path, "-version").Output()
+	if err != nil {
+		return fmt.Errorf("%s -version failed: %w; check the FFmpeg install or set %s", name, err, setting)
+	}
+
+	// The first line reads like "ffprobe version 6.1.1-3ubuntu5 Copyright ..."
+	version := "unknown"
+	line, _, _ := strings.Cut(string(output), "\n")
+	if fields := strings.Fields(line); len(fields) >= 3 && fields[1] == "version" {
+		version = fields[2]
+	}
+	c.logger.Info("Found "+name, "path", path, "version", version)
+	return nil
+}
+
+// checkOpusEncoder verifies ffmpeg was built with libopus for transcoding
+func (c *Checker) checkOpusEncoder() error {
+	output, err := exec.Command(c.config.FFmpeg.Path, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return fmt.Errorf("ffmpeg encoder listing failed: %w", err)
+	}
+	if !strings.Contains(string(output), "libopus") {
+		return fmt.Errorf("ffmpeg was built without the libopus encoder")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,198 @@
+package preflight
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Services reached by the network checks
+const (
+	discordGatewayURL = "https://discord.com/api/v10/gateway"
+	geminiModelsURL   = "https://generativelanguage.googleapis.com/v1beta/models"
+)
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and
+// the Unix epoch (1970)
+const ntpEpochOffset = 2208988800
+
+// networkClient returns the HTTP client used by the network checks
+func (c *Checker) networkClient() *http.Client {
+	return &http.Client{Timeout: time.Duration(c.config.Preflight.NetworkTimeout) * time.Second}
+}
+
+// get requests a URL and returns its status code. Any response means the
+// service is reachable; callers decide which codes are acceptable.
+func (c *Checker) get(target string, header http.Header) (int, error) {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := c.networkClient().Do(req)
+	if err != nil {
+		// The error includes the URL, which may carry an API key
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("%s is unreachable: %w", hostOf(target), err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// hostOf returns the host of a URL for messages, without any credentials
+func hostOf(target string) string {
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "server"
+}
+
+// checkRemoteEndpoint verifies the remote transcription service answers.
+// Any HTTP response will do, as the endpoint may only accept uploads.
+func (c *Checker) checkRemoteEndpoint() error {
+	endpoint := c.config.Transcription.Remote.Endpoint
+	if _, err := c.get(endpoint, nil); err != nil {
+		return fmt.Errorf("remote transcription %w", err)
+	}
+	return nil
+}
+
+// checkDiscord verifies Discord is reachable: the gateway for a bot, or the
+// webhook itself, which also shows whether it still exists
+func (c *Checker) checkDiscord() error {
+	discord := c.config.Discord
+	if !discord.UseWebhook() {
+		status, err := c.get(discordGatewayURL, nil)
+		if err != nil {
+			return fmt.Errorf("Discord gateway %w", err)
+		}
+		if status != http.StatusOK {
+			return fmt.Errorf("Discord gateway returned HTTP %d", status)
+		}
+		return nil
+	}
+
+	status, err := c.get(discord.WebhookURL, nil)
+	if err != nil {
+		return fmt.Errorf("Discord webhook %w", err)
+	}
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusNotFound:
+		return fmt.Errorf("Discord webhook was deleted or its URL is wrong")
+	default:
+		return fmt.Errorf("Discord webhook returned HTTP %d", status)
+	}
+}
+
+// checkLLM verifies the LLM provider is reachable. Gemini's key is checked
+// too, since listing models needs nothing but the key.
+func (c *Checker) checkLLM() error {
+	llm := c.config.LLM
+	if llm.Provider != "gemini" {
+		if _, err := c.get(llm.Endpoint, nil); err != nil {
+			return fmt.Errorf("%s API %w", llm.Provider, err)
+		}
+		return nil
+	}
+
+	status, err := c.get(geminiModelsURL, http.Header{"X-Goog-Api-Key": {llm.APIKey}})
+	if err != nil {
+		return fmt.Errorf("Gemini API %w", err)
+	}
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("Gemini API rejected the API key (HTTP %d)", status)
+	default:
+		return fmt.Errorf("Gemini API returned HTTP %d", status)
+	}
+}
+
+// checkAgentServer verifies an agent can reach the server it uploads to
+func (c *Checker) checkAgentServer() error {
+	if _, err := c.get(c.config.Agent.ServerURL, nil); err != nil {
+		return fmt.Errorf("Meiko server %w", err)
+	}
+	return nil
+}
+
+// checkClock compares the system clock with an NTP server. Call timestamps,
+// API tokens and TLS certificates all depend on it being right.
+func (c *Checker) checkClock() error {
+	server := c.config.Preflight.NTPServer
+	offset, err := ntpOffset(server, time.Duration(c.config.Preflight.NetworkTimeout)*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to query NTP server %s: %w", server, err)
+	}
+
+	c.logger.Info("System clock offset", "server", server, "offset", offset.Round(time.Millisecond))
+	if limit := time.Duration(c.config.Preflight.MaxClockSkew) * time.Second; offset > limit || offset < -limit {
+		return fmt.Errorf("system clock is off by %s; enable time synchronization (e.g. timedatectl set-ntp true)", offset.Round(time.Second))
+	}
+	return nil
+}
+
+// ntpOffset asks an SNTP server for the time and returns how far the local
+// clock is behind it
+func ntpOffset(server string, timeout time.Duration) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(server, "123"), timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// Version 3 client request; every other field may be zero
+	request := make([]byte, 48)
+	request[0] = 0x1B
+
+	sent := time.Now()
+	if _, err := conn.Write(request); err != nil {
+		return 0, err
+	}
+	response := make([]byte, 48)
+	n, err := conn.Read(response)
+	if err != nil {
+		return 0, err
+	}
+	received := time.Now()
+	if n < len(response) {
+		return 0, fmt.Errorf("short response")
+	}
+
+	if response[0]&0x07 != 4 {
+		return 0, fmt.Errorf("not a server response")
+	}
+	serverReceived := ntpTime(response[32:40])
+	serverSent := ntpTime(response[40:48])
+	if serverSent.IsZero() {
+		return 0, fmt.Errorf("server did not send its time")
+	}
+
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+// ntpTime decodes a 64-bit NTP timestamp
+func ntpTime(b []byte) time.Time {
+	seconds := binary.BigEndian.Uint32(b[0:4])
+	fraction := binary.BigEndian.Uint32(b[4:8])
+	if seconds == 0 && fraction == 0 {
+		return time.Time{}
+	}
+	nanos := (int64(fraction) * 1e9) >> 32
+	return time.Unix(int64(seconds)-ntpEpochOffset, nanos)
+}
@@ -0,0 +1,70 @@
+package priority
+
+import (
+	"Meiko/internal/config"
+	"Meiko/internal/severity"
+	"Meiko/internal/talkgroups"
+)
+
+const (
+	// Max is the highest priority a call can have
+	Max = 100
+
+	// priorityTalkgroupWeight is given to talkgroups flagged as priority
+	// that have no weight of their own
+	priorityTalkgroupWeight = 20
+
+	maxKeywordPoints  = 50
+	maxDurationPoints = 20
+)
+
+// Call is what a call is scored on
+type Call struct {
+	TalkgroupID       string
+	PriorityTalkgroup bool // Flagged priority in the config or an override
+	ServiceType       talkgroups.ServiceType
+	Transcription     string
+	Duration          int // Seconds
+}
+
+// Scorer assigns each call a priority from 0 to 100
+type Scorer struct {
+	config   config.PriorityConfig
+	severity *severity.Scorer
+}
+
+// NewScorer creates a scorer that finds keyword hits with the severity scorer
+func NewScorer(cfg config.PriorityConfig, severity *severity.Scorer) *Scorer {
+	return &Scorer{config: cfg, severity: severity}
+}
+
+// Score returns the call's priority: its talkgroup and service type weights
+// plus points for keyword hits and airtime
+func (s *Scorer) Score(call Call) int {
+	score := s.talkgroupWeight(call) + s.config.ServiceTypeWeights[string(call.ServiceType)]
+
+	keywordPoints := 0
+	for _, level := range s.severity.Hits(call.Transcription) {
+		keywordPoints += int(level) * s.config.KeywordWeight
+	}
+	score += min(keywordPoints, maxKeywordPoints)
+	score += min(call.Duration/30*s.config.DurationWeight, maxDurationPoints)
+
+	return max(0, min(score, Max))
+}
+
+// IsMajor reports whether a priority marks a major incident
+func (s *Scorer) IsMajor(priority int) bool {
+	return priority >= s.config.MajorIncident
+}
+
+// talkgroupWeight returns the configured weight for the call's talkgroup
+func (s *Scorer) talkgroupWeight(call Call) int {
+	if weight, ok := s.config.TalkgroupWeights[call.TalkgroupID]; ok {
+		return weight
+	}
+	if call.PriorityTalkgroup {
+		return priorityTalkgroupWeight
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"Meiko/internal/database"
+)
+
+// hashBackfillBatch is how many calls are read at a time while hashing
+// recordings stored before content hashes were
+const hashBackfillBatch = 100
+
+// contentHash returns the hex SHA-256 of a file's contents
+func contentHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// processedCopy returns the call already made from a recording with the same
+// contents, such as one copied again or renamed, or nil if there is none.
+// When that call's own recording is gone, it is pointed at this one, so a
+// recording moved by hand keeps playing.
+func (cp *CallProcessor) processedCopy(path, hash string) *database.CallRecord {
+	original, err := cp.db.GetCallByContentHash(hash)
+	if err != nil {
+		cp.logger.Error("Error checking for an identical recording", "error", err, "file", path)
+		return nil
+	}
+	if original == nil {
+		return nil
+	}
+
+	if _, err := os.Stat(original.Filepath); errors.Is(err, fs.ErrNotExist) && !original.LocalDeleted {
+		if err := cp.db.UpdateCallFile(original.ID, filepath.Base(path), path); err != nil {
+			cp.logger.Warn("Failed to move call to its renamed recording", "error", err, "id", original.ID)
+			return original
+		}
+		if original.AudioMissing {
+			if err := cp.db.SetAudioMissing([]int{original.ID}, false); err != nil {
+				cp.logger.Warn("Failed to clear missing audio", "error", err, "id", original.ID)
+			}
+		}
+		cp.logger.Info("Recording was moved, updated its call",
+			"call_id", original.ID,
+			"from", original.Filepath,
+			"to", path)
+	}
+	return original
+}
+
+// backfillHashes hashes the recordings of calls stored before content hashes
+// were, so copies of them are recognised too. Calls whose recording is gone
+// are passed over, and are looked at again on the next start.
+func (cp *CallProcessor) backfillHashes(ctx context.Context) {
+	hashed, lastID := 0, 0
+	for ctx.Err() == nil {
+		calls, err := cp.db.GetCallsWithoutContentHash(lastID, hashBackfillBatch)
+		if err != nil {
+			cp.logger.Error("Failed to find calls to hash", "error", err)
+			return
+		}
+		if len(calls) == 0 {
+			break
+		}
+		for _, call := range calls {
+			if ctx.Err() != nil {
+				break
+			}
+			lastID = call.ID
+			hash, err := contentHash(call.Filepath)
+			if err != nil {
+				if !errors.Is(err, fs.ErrNotExist) {
+					cp.logger.Warn("Failed to hash recording", "error", err, "id", call.ID, "file", call.Filepath)
+				}
+				continue
+			}
+			if err := cp.db.SetContentHash(call.ID, hash); err != nil {
+				cp.logger.Error("Failed to store content hash", "error", err, "id", call.ID)
+				return
+			}
+			hashed++
+		}
+	}
+
+	if hashed > 0 {
+		cp.logger.Info("Hashed recordings of earlier calls", "calls", hashed)
+	}
+}
@@ -0,0 +1,136 @@
+package processor
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pipeline stages timed by the processor, in the order they run
+const (
+	StageParse      = "parse"      // Checking for an earlier copy and reading the metadata
+	StageDuration   = "duration"   // Measuring the recording's length
+	StageTranscribe = "transcribe" // Speech to text and corrections
+	StageNotify     = "notify"     // Discord, Telegram, Matrix and push notifications
+	StageStore      = "store"      // Database writes, counted only as failures
+)
+
+// Stages lists the timed pipeline stages in the order they run
+var Stages = []string{StageParse, StageDuration, StageTranscribe, StageNotify}
+
+// StageStats is how often a pipeline stage ran and how long it took
+type StageStats struct {
+	Count          int64   `json:"count"`
+	TotalSeconds   float64 `json:"total_seconds"`
+	AverageSeconds float64 `json:"average_seconds"`
+}
+
+// PipelineStats is a snapshot of the processor's queue and throughput since startup
+type PipelineStats struct {
+	QueueDepth      int                   `json:"queue_depth"`  // Recordings waiting to be processed
+	Transcribing    int                   `json:"transcribing"` // Transcriptions in progress
+	Processed       int64                 `json:"processed"`    // Calls transcribed and stored, including simulcast duplicates
+	Skipped         int64                 `json:"skipped"`      // Recordings already processed, muted or too short
+	Deferred        int64                 `json:"deferred"`     // Times a watcher found the queue full and retried a recording later
+	Dropped         int64                 `json:"dropped"`      // Recordings never queued because their watcher stopped
+	Stages          map[string]StageStats `json:"stages"`
+	Failures        map[string]int64      `json:"failures"` // By stage
+	LastFile        string                `json:"last_file,omitempty"`
+	LastProcessedAt *time.Time            `json:"last_processed_at,omitempty"`
+	Since           time.Time             `json:"since"`
+}
+
+// pipelineMetrics counts what the processor has done since startup
+type pipelineMetrics struct {
+	transcribing atomic.Int32 // Also counts retranscriptions from bulk operations
+
+	mutex         sync.Mutex
+	since         time.Time
+	processed     int64
+	skipped       int64
+	stages        map[string]StageStats
+	failures      map[string]int64
+	lastFile      string
+	lastProcessed time.Time
+}
+
+// newPipelineMetrics creates empty pipeline metrics
+func newPipelineMetrics() *pipelineMetrics {
+	return &pipelineMetrics{
+		since:    time.Now(),
+		stages:   make(map[string]StageStats),
+		failures: make(map[string]int64),
+	}
+}
+
+// observe records that a stage which started at start has finished
+func (m *pipelineMetrics) observe(stage string, start time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stats := m.stages[stage]
+	stats.Count++
+	stats.TotalSeconds += time.Since(start).Seconds()
+	m.stages[stage] = stats
+}
+
+// fail records a failure in a stage
+func (m *pipelineMetrics) fail(stage string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.failures[stage]++
+}
+
+// skip records a recording that was not made into a call
+func (m *pipelineMetrics) skip() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.skipped++
+}
+
+// complete records a call that made it through the pipeline
+func (m *pipelineMetrics) complete(path string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.processed++
+	m.lastFile = filepath.Base(path)
+	m.lastProcessed = time.Now()
+}
+
+// Pipeline returns the processor's queue depth and what it has done since startup
+func (cp *CallProcessor) Pipeline() PipelineStats {
+	m := cp.metrics
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	stats := PipelineStats{
+		QueueDepth:   cp.QueueDepth(),
+		Transcribing: int(m.transcribing.Load()),
+		Processed:    m.processed,
+		Skipped:      m.skipped,
+		Stages:       make(map[string]StageStats, len(Stages)),
+		Failures:     make(map[string]int64, len(m.failures)),
+		LastFile:     m.lastFile,
+		Since:        m.since,
+	}
+	for _, stage := range Stages {
+		timing := m.stages[stage]
+		if timing.Count > 0 {
+			timing.AverageSeconds = timing.TotalSeconds / float64(timing.Count)
+		}
+		stats.Stages[stage] = timing
+	}
+	for stage, count := range m.failures {
+		stats.Failures[stage] = count
+	}
+	for _, fw := range cp.watchers {
+		deferred, dropped := fw.Overflow()
+		stats.Deferred += deferred
+		stats.Dropped += dropped
+	}
+	if !m.lastProcessed.IsZero() {
+		lastProcessed := m.lastProcessed
+		stats.LastProcessedAt = &lastProcessed
+	}
+	return stats
+}
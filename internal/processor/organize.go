@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"Meiko/internal/database"
+	"Meiko/internal/metadata"
+)
+
+// organize files a processed call's recording under the archive directory as
+// YYYY/MM/DD/<talkgroup>/, along with its metadata file. The recording stays
+// where it is if anything fails, so a call always has playable audio.
+func (cp *CallProcessor) organize(callRecord *database.CallRecord) {
+	original := callRecord.Filepath
+	directory := filepath.Join(cp.config.Organize.Directory,
+		callRecord.Timestamp.Format("2006"), callRecord.Timestamp.Format("01"), callRecord.Timestamp.Format("02"),
+		pathSegment(callRecord.TalkgroupID))
+	target := filepath.Join(directory, filepath.Base(original))
+	if target == original {
+		return
+	}
+
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		cp.logger.Warn("Failed to create archive directory", "error", err, "directory", directory)
+		return
+	}
+	if _, err := os.Stat(target); err == nil {
+		cp.logger.Warn("Recording is already archived, leaving it in place", "file", filepath.Base(original), "archived", target)
+		return
+	}
+	sidecar := metadata.SidecarPath(original)
+
+	// Point the call at the archived file before it appears, so a watcher of
+	// the archive directory sees it as already processed
+	if err := cp.db.UpdateCallFile(callRecord.ID, callRecord.Filename, target); err != nil {
+		cp.logger.Error("Failed to update archived call", "error", err, "id", callRecord.ID)
+		return
+	}
+	if err := placeFile(original, target, cp.config.Organize.Mode); err != nil {
+		cp.logger.Error("Failed to archive recording", "error", err, "file", filepath.Base(original))
+		if err := cp.db.UpdateCallFile(callRecord.ID, callRecord.Filename, original); err != nil {
+			cp.logger.Error("Failed to restore call file", "error", err, "id", callRecord.ID)
+		}
+		return
+	}
+	callRecord.Filepath = target
+
+	if sidecar != "" {
+		if err := placeFile(sidecar, filepath.Join(directory, filepath.Base(sidecar)), cp.config.Organize.Mode); err != nil {
+			cp.logger.Warn("Failed to archive recording metadata", "error", err, "file", filepath.Base(sidecar))
+		}
+	}
+
+	cp.logger.Debug("Processor", "Archived recording",
+		"call_id", callRecord.ID,
+		"file", callRecord.Filename,
+		"path", target,
+		"mode", cp.config.Organize.Mode)
+}
+
+// placeFile moves or hard links a file to a new path. Across filesystems,
+// where neither works, the file is copied instead, and removed when moving.
+func placeFile(source, target, mode string) error {
+	var err error
+	if mode == "hardlink" {
+		err = os.Link(source, target)
+	} else {
+		err = os.Rename(source, target)
+	}
+	if err == nil {
+		return nil
+	}
+
+	if copyErr := copyFile(source, target); copyErr != nil {
+		return fmt.Errorf("%w (copying failed too: %v)", err, copyErr)
+	}
+	if mode != "hardlink" {
+		if err := os.Remove(source); err != nil {
+			os.Remove(target)
+			return fmt.Errorf("failed to remove the original: %w", err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies a file, leaving nothing at the target if it fails
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	partial := target + ".part"
+	out, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(partial)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(partial)
+		return err
+	}
+	if err := os.Rename(partial, target); err != nil {
+		os.Remove(partial)
+		return err
+	}
+	return nil
+}
+
+// pathSegment makes a talkgroup ID safe to use as a directory name
+func pathSegment(name string) string {
+	segment := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+	if strings.Trim(segment, ".") == "" {
+		return "unknown"
+	}
+	return segment
+}
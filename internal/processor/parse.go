@@ -0,0 +1,272 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/filename"
+	"Meiko/internal/frequency"
+	"Meiko/internal/logger"
+	"Meiko/internal/metadata"
+	"Meiko/internal/talkgroups"
+)
+
+// callDetails are what is known about a call before its audio is processed,
+// from the recorder's metadata or the filename
+type callDetails struct {
+	timestamp time.Time
+	system    string // System name, the group of talkgroups nothing else is known about
+	site      string
+	to        string // Talkgroup ID
+	toAlias   string
+	from      string // Radio ID of the calling unit
+	fromAlias string
+	control   string // Channel such as T-Control, for recordings without a talkgroup
+	frequency string
+}
+
+// parseRecording describes a new recording from the metadata its recorder
+// saved, taking anything the metadata leaves out from the filename, which is
+// read in a filename format
+func (cp *CallProcessor) parseRecording(filePath, format string) *database.CallRecord {
+	details := cp.parseFilenameDetails(filepath.Base(filePath), format)
+
+	meta, err := metadata.Read(filePath)
+	if err != nil {
+		cp.logger.Warn("Ignoring unreadable recording metadata", "file", filepath.Base(filePath), "error", err)
+	}
+	if meta != nil {
+		cp.logger.Debug("Processor", "Read recording metadata", "file", filepath.Base(filePath), "source", meta.Source)
+		details.merge(meta)
+	}
+
+	return cp.newCallRecord(filePath, details)
+}
+
+// merge replaces details with the ones the recorder's metadata has
+func (d *callDetails) merge(meta *metadata.Metadata) {
+	if !meta.Timestamp.IsZero() {
+		d.timestamp = meta.Timestamp
+	}
+	if meta.System != "" {
+		d.system = meta.System
+	}
+	if meta.Site != "" {
+		d.site = meta.Site
+	}
+	// The talkgroup and radio are taken together, so a recording's TO is
+	// never paired with a FROM from a different source
+	if meta.To != "" || meta.From != "" {
+		d.to, d.toAlias = meta.To, meta.ToAlias
+		d.from, d.fromAlias = meta.From, meta.FromAlias
+	}
+	if meta.Frequency != "" {
+		d.frequency = meta.Frequency
+	}
+}
+
+// newFilenameParsers creates a parser for each filename format the watched
+// directories use, plus the auto format, by format name. The configuration
+// has been validated, so a format that fails to load is left to auto.
+func newFilenameParsers(cfg *config.Config, logger *logger.Logger) map[string]*filename.Parser {
+	parsers := make(map[string]*filename.Parser)
+	formats := []string{filename.Auto}
+	for _, directory := range cfg.WatchDirectories() {
+		formats = append(formats, directory.FilenameFormat)
+	}
+	for _, format := range formats {
+		if _, ok := parsers[format]; ok || format == "" {
+			continue
+		}
+		parser, err := filename.NewParser(format, cfg.FilenameTemplates())
+		if err != nil {
+			logger.Warn("Using the auto filename format", "format", format, "error", err)
+			continue
+		}
+		parsers[format] = parser
+	}
+	return parsers
+}
+
+// filenameFormat returns the filename format of a recording's directory, or
+// of its system for recordings from elsewhere such as agents' uploads
+func (cp *CallProcessor) filenameFormat(format, system string) string {
+	if format != "" {
+		return format
+	}
+	if configured, ok := cp.config.GetSystem(system); ok {
+		return configured.SDRTrunk.FilenameFormat
+	}
+	if system == "" && len(cp.config.Systems) == 0 {
+		return cp.config.SDRTrunk.FilenameFormat
+	}
+	return filename.Auto
+}
+
+// parseFilenameDetails extracts call details from a recording's filename
+// with a filename format, trying every format when it is unknown
+func (cp *CallProcessor) parseFilenameDetails(name, format string) callDetails {
+	parser := cp.filenames[format]
+	if parser == nil {
+		parser = cp.filenames[filename.Auto]
+	}
+
+	fields, format := parser.Parse(name)
+	if format == "" {
+		cp.logger.Debug("Processor", "Filename matches no known format", "file", name)
+		return callDetails{}
+	}
+
+	details := callDetails{
+		timestamp: fields.Timestamp,
+		system:    fields.System,
+		site:      fields.Site,
+		to:        fields.To,
+		from:      fields.From,
+		control:   fields.Channel,
+	}
+	if _, ok := frequency.Normalize(fields.Frequency); ok {
+		details.frequency = fields.Frequency
+	}
+	return details
+}
+
+// newCallRecord creates a call record from a recording's details, naming
+// and grouping its talkgroup with the talkgroup service
+func (cp *CallProcessor) newCallRecord(filePath string, details callDetails) *database.CallRecord {
+	record := &database.CallRecord{
+		Filename:  filepath.Base(filePath),
+		Filepath:  filePath,
+		Timestamp: details.timestamp,
+		Duration:  0, // Will be determined from audio file later
+		Frequency: details.frequency,
+		Site:      details.site,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	toValue, fromValue := details.to, details.from
+
+	// Determine primary talkgroup (usually the FROM value is the calling unit)
+	talkgroupID := ""
+	talkgroupAlias := ""
+	if fromValue != "" {
+		talkgroupID = fromValue
+		// Use talkgroup service for enhanced formatting with context awareness
+		if cp.talkgroups != nil {
+			// Use context-aware classification - if FROM is unknown but TO is known,
+			// infer FROM's department based on TO's department
+			var talkgroupInfo *talkgroups.TalkgroupInfo
+			var deptInfo *talkgroups.DepartmentType
+
+			if toValue != "" && toValue != fromValue {
+				// We have both FROM and TO - use context-aware classification
+				talkgroupInfo = cp.talkgroups.GetTalkgroupInfoWithContext(fromValue, toValue)
+				deptInfo = cp.talkgroups.GetDepartmentInfoWithContext(fromValue, toValue)
+
+				// Check if this is a cross-department call (e.g., police → fire)
+				fromInfoDirect := cp.talkgroups.GetTalkgroupInfo(fromValue)
+				toInfoDirect := cp.talkgroups.GetTalkgroupInfo(toValue)
+
+				// If FROM has a known department type and it differs from TO's department type,
+				// keep the original classification to preserve cross-department calls like police → fire
+				if fromInfoDirect.ServiceType != talkgroups.ServiceOther &&
+					toInfoDirect.ServiceType != talkgroups.ServiceOther &&
+					fromInfoDirect.ServiceType != toInfoDirect.ServiceType {
+					talkgroupInfo = fromInfoDirect
+					deptInfo = cp.talkgroups.GetDepartmentInfo(fromValue)
+
+					cp.logger.Debug("Cross-department call detected, preserving original classification",
+						"from_tg", fromValue,
+						"from_dept", string(fromInfoDirect.ServiceType),
+						"to_tg", toValue,
+						"to_dept", string(toInfoDirect.ServiceType))
+				}
+			} else {
+				// No context available, use standard classification
+				talkgroupInfo = cp.talkgroups.GetTalkgroupInfo(fromValue)
+				deptInfo = cp.talkgroups.GetDepartmentInfo(fromValue)
+			}
+
+			talkgroupAlias = recorderAlias(cp.talkgroups.FormatTalkgroupDisplay(fromValue), fromValue, details.fromAlias)
+
+			// Use classified department name instead of raw group
+			if deptInfo.Type != talkgroups.ServiceOther {
+				record.TalkgroupGroup = fmt.Sprintf("%s %s", deptInfo.Emoji, talkgroupInfo.Group)
+			} else {
+				record.TalkgroupGroup = talkgroupInfo.Group
+			}
+		} else {
+			talkgroupAlias = recorderAlias("TG "+fromValue, fromValue, details.fromAlias)
+		}
+
+		// Add TO information if different
+		if toValue != "" && toValue != fromValue {
+			if cp.talkgroups != nil {
+				toDisplay := cp.talkgroups.FormatTalkgroupDisplay(toValue)
+				talkgroupAlias += " → " + recorderAlias(toDisplay, toValue, details.toAlias)
+			} else {
+				talkgroupAlias += " → " + recorderAlias("TG "+toValue, toValue, details.toAlias)
+			}
+		}
+	} else if toValue != "" {
+		talkgroupID = toValue
+		// Use talkgroup service for enhanced formatting
+		if cp.talkgroups != nil {
+			talkgroupInfo := cp.talkgroups.GetTalkgroupInfo(toValue)
+			deptInfo := cp.talkgroups.GetDepartmentInfo(toValue)
+			talkgroupAlias = recorderAlias(cp.talkgroups.FormatTalkgroupDisplay(toValue), toValue, details.toAlias)
+
+			// Use classified department name instead of raw group
+			if deptInfo.Type != talkgroups.ServiceOther {
+				record.TalkgroupGroup = fmt.Sprintf("%s %s", deptInfo.Emoji, talkgroupInfo.Group)
+			} else {
+				record.TalkgroupGroup = talkgroupInfo.Group
+			}
+		} else {
+			talkgroupAlias = recorderAlias("TG "+toValue, toValue, details.toAlias)
+		}
+	}
+
+	// If no TO/FROM found, use T-Control or other channel names
+	if talkgroupID == "" && details.control != "" {
+		talkgroupID = details.control
+		if cp.talkgroups != nil {
+			// T-Control is typically emergency management
+			talkgroupAlias = "🚨 " + details.control
+			record.TalkgroupGroup = "Emergency Management"
+		} else {
+			talkgroupAlias = details.control
+		}
+	}
+
+	// Set default if still empty
+	if talkgroupID == "" {
+		talkgroupID = "Unknown"
+		talkgroupAlias = "🔔 Unknown Talkgroup"
+		if record.TalkgroupGroup == "" {
+			record.TalkgroupGroup = "Unknown Department"
+		}
+	}
+
+	record.TalkgroupID = talkgroupID
+	record.TalkgroupAlias = talkgroupAlias
+
+	// Use system name if talkgroup service didn't set it
+	if record.TalkgroupGroup == "" || record.TalkgroupGroup == "Unknown Department" {
+		record.TalkgroupGroup = details.system
+	}
+
+	return record
+}
+
+// recorderAlias prefers the recorder's alias for a talkgroup or radio the
+// talkgroup service only knows by number
+func recorderAlias(display, id, alias string) string {
+	if alias != "" && display == "TG "+id {
+		return alias
+	}
+	return display
+}
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,15 +11,41 @@ import (
 	"strings"
 	"time"
 
+	"Meiko/internal/audio"
 	"Meiko/internal/config"
+	"Meiko/internal/corrections"
 	"Meiko/internal/database"
+	"Meiko/internal/dedup"
 	"Meiko/internal/discord"
+	"Meiko/internal/embeddings"
+	"Meiko/internal/enrichment"
+	"Meiko/internal/filename"
+	"Meiko/internal/frequency"
 	"Meiko/internal/logger"
+	"Meiko/internal/notify"
+	"Meiko/internal/priority"
+	"Meiko/internal/push"
+	"Meiko/internal/severity"
 	"Meiko/internal/talkgroups"
+	"Meiko/internal/tones"
+	"Meiko/internal/transcode"
 	"Meiko/internal/transcription"
+	"Meiko/internal/translation"
+	"Meiko/internal/voice"
 	"Meiko/internal/watcher"
 )
 
+const (
+	// retryInterval is how often calls are checked for a due transcription retry
+	retryInterval = 15 * time.Second
+	// retryBatchSize is how many due calls are retried per check
+	retryBatchSize = 10
+	// retryBaseDelay is the wait before the first retry, doubled on each attempt
+	retryBaseDelay = 30 * time.Second
+	// maxRetryDelay caps the doubling delay between retries
+	maxRetryDelay = time.Hour
+)
+
 // CallProcessor handles the processing pipeline for audio files
 type CallProcessor struct {
 	db          *database.Database
@@ -28,6 +55,24 @@ type CallProcessor struct {
 	logger      *logger.Logger
 	talkgroups  *talkgroups.Service
 	webServer   WebServer
+	corrections *corrections.Engine
+	translator  *translation.Translator // nil when translation is disabled
+	enricher    *enrichment.Enricher    // nil when enrichment is disabled
+	embeddings  *embeddings.Service     // nil when semantic search is disabled
+	notifier    *notify.Notifier        // nil when Telegram and Matrix are disabled
+	push        *push.Service           // nil when push alerts are disabled
+	severity    *severity.Scorer
+	priority    *priority.Scorer
+	dedup       *dedup.Detector             // nil when simulcast deduplication is disabled
+	voice       *voice.Classifier           // nil when voice detection is disabled
+	filenames   map[string]*filename.Parser // Filename parsers by format name
+	metrics     *pipelineMetrics
+	watchers    []*watcher.FileWatcher // Feeding events, for their overflow counts
+	events      <-chan watcher.FileEvent
+	ingest      chan watcher.FileEvent
+	work        context.Context // Cancelled by Drain to abort in-flight calls
+	cancelWork  context.CancelFunc
+	done        chan struct{} // Closed once processing has stopped
 }
 
 // WebServer interface for broadcasting new calls
@@ -37,6 +82,15 @@ type WebServer interface {
 
 // New creates a new call processor
 func New(db *database.Database, transcriber *transcription.Service, discord *discord.Client, config *config.Config, logger *logger.Logger, talkgroups *talkgroups.Service) *CallProcessor {
+	severityScorer := severity.NewScorer(config.Severity)
+	var detector *dedup.Detector
+	if config.Dedup.Enabled {
+		detector = dedup.NewDetector(config.Dedup)
+	}
+	var classifier *voice.Classifier
+	if config.VoiceDetection.Enabled {
+		classifier = voice.NewClassifier(config.VoiceDetection, config.FFmpeg.Path)
+	}
 	return &CallProcessor{
 		db:          db,
 		transcriber: transcriber,
@@ -44,6 +98,13 @@ func New(db *database.Database, transcriber *transcription.Service, discord *dis
 		config:      config,
 		logger:      logger,
 		talkgroups:  talkgroups,
+		severity:    severityScorer,
+		priority:    priority.NewScorer(config.Priority, severityScorer),
+		dedup:       detector,
+		voice:       classifier,
+		filenames:   newFilenameParsers(config, logger),
+		metrics:     newPipelineMetrics(),
+		ingest:      make(chan watcher.FileEvent, 100),
 	}
 }
 
@@ -52,318 +113,707 @@ func (cp *CallProcessor) SetWebServer(webServer WebServer) {
 	cp.webServer = webServer
 }
 
-// Start begins processing file events
+// SetCorrections sets the correction engine applied to transcriptions
+func (cp *CallProcessor) SetCorrections(engine *corrections.Engine) {
+	cp.corrections = engine
+}
+
+// SetTranslator sets the translator used for non-English transcriptions
+func (cp *CallProcessor) SetTranslator(translator *translation.Translator) {
+	cp.translator = translator
+}
+
+// SetEnricher sets the enricher that extracts incident details from transcriptions
+func (cp *CallProcessor) SetEnricher(enricher *enrichment.Enricher) {
+	cp.enricher = enricher
+}
+
+// SetEmbeddings sets the service that embeds transcriptions for semantic search
+func (cp *CallProcessor) SetEmbeddings(service *embeddings.Service) {
+	cp.embeddings = service
+}
+
+// SetNotifier sets the notifier that posts calls to Telegram and Matrix
+func (cp *CallProcessor) SetNotifier(notifier *notify.Notifier) {
+	cp.notifier = notifier
+}
+
+// SetPush sets the service that sends push alerts for calls matching its rules
+func (cp *CallProcessor) SetPush(service *push.Service) {
+	cp.push = service
+}
+
+// SetWatchers sets the file watchers feeding the processor, whose overflow
+// is reported with the pipeline statistics
+func (cp *CallProcessor) SetWatchers(watchers []*watcher.FileWatcher) {
+	cp.watchers = watchers
+}
+
+// Enqueue queues a recording received from an agent. It returns false when
+// the queue is full.
+func (cp *CallProcessor) Enqueue(event watcher.FileEvent) bool {
+	select {
+	case cp.ingest <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start begins processing file events. Once ctx is cancelled the processor
+// finishes the recordings already queued, so call Drain to wait for it.
 func (cp *CallProcessor) Start(ctx context.Context, events <-chan watcher.FileEvent) {
+	cp.events = events
+	cp.work, cp.cancelWork = context.WithCancel(context.Background())
+	cp.done = make(chan struct{})
 	go cp.processEvents(ctx, events)
+	go cp.backfillHashes(ctx)
+}
+
+// QueueDepth returns how many recordings are waiting to be processed
+func (cp *CallProcessor) QueueDepth() int {
+	return len(cp.events) + len(cp.ingest)
+}
+
+// Drain waits up to timeout for the processor to finish queued recordings
+// after the context passed to Start is cancelled. Calls still in progress at
+// the timeout are aborted. It reports whether everything finished in time.
+func (cp *CallProcessor) Drain(timeout time.Duration) bool {
+	if cp.done == nil {
+		return true
+	}
+
+	select {
+	case <-cp.done:
+		return true
+	case <-time.After(timeout):
+	}
+
+	cp.cancelWork()
+	<-cp.done
+	return false
+}
+
+// ProcessFile runs one recording through the pipeline, for backfilling
+// recordings outside the watched directories
+func (cp *CallProcessor) ProcessFile(ctx context.Context, event watcher.FileEvent) {
+	cp.processFileEvent(ctx, event)
 }
 
 // processEvents processes incoming file events
 func (cp *CallProcessor) processEvents(ctx context.Context, events <-chan watcher.FileEvent) {
+	defer close(cp.done)
+	defer cp.cancelWork()
+
+	// Retries run between new calls so calls are still processed one at a time
+	retries := time.NewTicker(retryInterval)
+	defer retries.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
-			cp.logger.Info("Call processor stopping...")
+			cp.drain(events)
 			return
 		case event, ok := <-events:
 			if !ok {
-				cp.logger.Info("File events channel closed, stopping processor")
+				// Watchers close their channels when they stop
+				cp.drain(nil)
 				return
 			}
-			cp.processFileEvent(ctx, event)
+			cp.processFileEvent(cp.work, event)
+		case event := <-cp.ingest:
+			cp.processFileEvent(cp.work, event)
+		case <-retries.C:
+			cp.retryDue(cp.work)
 		}
 	}
 }
 
+// drain processes the recordings still queued when the processor is stopping
+func (cp *CallProcessor) drain(events <-chan watcher.FileEvent) {
+	if queued := cp.QueueDepth(); queued > 0 {
+		cp.logger.Info("Call processor finishing queued recordings...", "queued", queued)
+	}
+
+	for cp.work.Err() == nil {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			cp.processFileEvent(cp.work, event)
+		case event := <-cp.ingest:
+			cp.processFileEvent(cp.work, event)
+		default:
+			cp.logger.Info("Call processor stopped")
+			return
+		}
+	}
+	cp.logger.Warn("Call processor stopped with recordings unprocessed", "queued", cp.QueueDepth())
+}
+
 // processFileEvent processes a single file event
 func (cp *CallProcessor) processFileEvent(ctx context.Context, event watcher.FileEvent) {
 	cp.logger.Info("Processing new audio file", "file", filepath.Base(event.Path))
+	parseStart := time.Now()
 
 	// Check if file already exists in database
 	exists, err := cp.db.FileExists(event.Path)
 	if err != nil {
 		cp.logger.Error("Error checking if file exists", "error", err, "file", event.Path)
+		cp.metrics.fail(StageParse)
 		return
 	}
 
 	if exists {
 		cp.logger.Debug("Processor", "File already processed, skipping", "file", filepath.Base(event.Path))
+		cp.metrics.skip()
+		return
+	}
+
+	// A recording copied again or renamed has a new path but the same contents
+	hash, err := contentHash(event.Path)
+	if err != nil {
+		cp.logger.Warn("Failed to hash recording", "error", err, "file", filepath.Base(event.Path))
+	} else if original := cp.processedCopy(event.Path, hash); original != nil {
+		cp.logger.Info("Recording was already processed, skipping",
+			"file", filepath.Base(event.Path),
+			"call_id", original.ID)
+		cp.metrics.skip()
 		return
 	}
 
-	// Parse filename to extract metadata
-	callRecord := cp.parseFilename(event.Path)
-	callRecord.Filepath = event.Path
+	// Read the recorder's metadata, or parse the filename without it
+	callRecord := cp.parseRecording(event.Path, cp.filenameFormat(event.FilenameFormat, event.System))
+	callRecord.ContentHash = hash
+
+	// Without a timestamp in the filename, the file's modification time is
+	// closest to when the call ended; imported archives keep their dates
+	if callRecord.Timestamp.IsZero() {
+		callRecord.Timestamp = event.ModTime
+	}
+	if callRecord.Timestamp.IsZero() {
+		callRecord.Timestamp = time.Now()
+	}
+	if event.Site != "" {
+		callRecord.Site = event.Site
+	}
+	callRecord.SystemID = event.System
+	cp.metrics.observe(StageParse, parseStart)
+
+	// Apply per-talkgroup filter overrides
+	filter := cp.talkgroupFilter(callRecord.TalkgroupID)
+	if filter.Mute {
+		cp.logger.Debug("Processor", "Skipping muted talkgroup",
+			"file", filepath.Base(event.Path),
+			"talkgroup", callRecord.TalkgroupID)
+		cp.metrics.skip()
+		return
+	}
 
 	// Calculate audio duration
-	if duration, err := cp.getAudioDuration(event.Path); err == nil {
+	durationStart := time.Now()
+	duration, err := cp.getAudioDuration(event.Path)
+	cp.metrics.observe(StageDuration, durationStart)
+	if err == nil {
 		callRecord.Duration = int(duration.Seconds())
 
-		// Check minimum call duration filter
-		minDuration := cp.config.GetMinCallDuration()
-		if duration < minDuration {
+		// Check minimum call duration filter (priority talkgroups bypass it)
+		minDuration := cp.config.GetMinCallDurationFor(callRecord.TalkgroupID)
+		if filter.MinCallDuration == 0 && event.MinCallDuration > 0 {
+			minDuration = event.MinCallDuration
+		}
+		if !filter.Priority && duration < minDuration {
 			cp.logger.Info("Skipping short call - below minimum duration threshold",
 				"file", filepath.Base(event.Path),
 				"duration", fmt.Sprintf("%.1fs", duration.Seconds()),
 				"minimum", fmt.Sprintf("%.1fs", minDuration.Seconds()))
+			cp.metrics.skip()
 			return
 		}
 	} else {
 		cp.logger.Warn("Failed to calculate audio duration", "error", err, "file", filepath.Base(event.Path))
+		cp.metrics.fail(StageDuration)
 		callRecord.Duration = 0
 	}
 
 	// Insert into database
 	if err := cp.db.InsertCall(callRecord); err != nil {
 		cp.logger.Error("Failed to insert call record", "error", err, "file", event.Path)
+		cp.metrics.fail(StageStore)
 		return
 	}
 
-	// Transcribe the audio file
-	result, err := cp.transcriber.TranscribeFile(ctx, event.Path)
-	if err != nil {
-		cp.logger.Error("Transcription failed", "error", err, "file", filepath.Base(event.Path))
+	cp.finishCall(ctx, callRecord, filter.Priority)
+
+	cp.logger.Debug("Parsed filename",
+		"file", filepath.Base(event.Path),
+		"talkgroup_id", callRecord.TalkgroupID,
+		"talkgroup_display", callRecord.TalkgroupAlias,
+		"department", callRecord.TalkgroupGroup,
+		"system", callRecord.TalkgroupGroup,
+		"timestamp", callRecord.Timestamp.Format("2006-01-02 15:04:05"))
+}
+
+// finishCall transcribes and scores a stored call, saves the results and
+// sends its notifications. Calls that fail to transcribe are retried later.
+func (cp *CallProcessor) finishCall(ctx context.Context, callRecord *database.CallRecord, priorityTalkgroup bool) {
+	if err := cp.analyze(ctx, callRecord, priorityTalkgroup); err != nil {
+		cp.logger.Error("Transcription failed", "error", err, "file", filepath.Base(callRecord.Filepath))
+		cp.scheduleRetry(ctx, callRecord, err)
 		return
 	}
 
-	// Update database with transcription
-	if err := cp.db.UpdateTranscription(callRecord.ID, result.Text); err != nil {
-		cp.logger.Error("Failed to update transcription", "error", err, "id", callRecord.ID)
+	// Link simulcast copies of a call already processed so they are only
+	// notified and counted once
+	if cp.dedup != nil {
+		cp.linkDuplicate(callRecord)
+	}
+
+	// Store the results and mark as processed in a single transaction
+	callRecord.Processed = true
+	if err := cp.db.CompleteCall(callRecord); err != nil {
+		cp.logger.Error("Failed to store call results", "error", err, "id", callRecord.ID)
+		cp.metrics.fail(StageStore)
 		return
 	}
 
-	// Update the call record with transcription
-	callRecord.Transcription = result.Text
+	// Shrink the recording now that transcription and tone detection are done
+	if cp.config.Transcode.Enabled {
+		cp.transcode(ctx, callRecord)
+	}
 
-	// Mark as processed
-	if err := cp.db.MarkAsProcessed(callRecord.ID); err != nil {
-		cp.logger.Error("Failed to mark as processed", "error", err, "id", callRecord.ID)
-		return
+	// File the recording away from the directory it was captured in
+	if cp.config.Organize.Enabled {
+		cp.organize(callRecord)
 	}
 
-	// Send Discord notification for new call
-	if cp.discord != nil && cp.discord.IsConnected() {
-		if err := cp.discord.SendCallNotification(callRecord); err != nil {
-			cp.logger.Error("Failed to send Discord notification", "error", err, "call_id", callRecord.ID)
-		}
+	if callRecord.DuplicateOf > 0 {
+		cp.logger.Info("Linked simulcast duplicate",
+			"call_id", callRecord.ID,
+			"duplicate_of", callRecord.DuplicateOf,
+			"talkgroup", callRecord.TalkgroupAlias,
+			"frequency", frequency.Display(callRecord.Frequency))
+		cp.metrics.complete(callRecord.Filepath)
+		return
 	}
 
+	cp.notify(callRecord)
+
 	// Broadcast to web clients
 	if cp.webServer != nil {
-		cp.logger.Info("Broadcasting new call to web clients", "call_id", callRecord.ID, "filename", filepath.Base(event.Path))
+		cp.logger.Info("Broadcasting new call to web clients", "call_id", callRecord.ID, "filename", filepath.Base(callRecord.Filepath))
 		cp.webServer.BroadcastNewCall(callRecord)
 	} else {
-		cp.logger.Warn("WebServer not set, cannot broadcast new call", "call_id", callRecord.ID)
+		cp.logger.Debug("Processor", "WebServer not set, cannot broadcast new call", "call_id", callRecord.ID)
 	}
 
+	// Index the call for semantic search; calls missed here are embedded later
+	if cp.embeddings != nil && callRecord.Transcription != "" {
+		if err := cp.embeddings.EmbedCall(ctx, callRecord); err != nil {
+			cp.logger.Warn("Failed to embed call", "error", err, "call_id", callRecord.ID)
+		}
+	}
+
+	cp.metrics.complete(callRecord.Filepath)
 	cp.logger.Success("Successfully processed audio file",
-		"file", filepath.Base(event.Path),
+		"file", filepath.Base(callRecord.Filepath),
 		"talkgroup", callRecord.TalkgroupAlias,
 		"department", callRecord.TalkgroupGroup,
 		"duration", fmt.Sprintf("%ds", callRecord.Duration),
-		"transcription_length", len(result.Text))
-
-	cp.logger.Debug("Parsed filename",
-		"file", filepath.Base(event.Path),
-		"talkgroup_id", callRecord.TalkgroupID,
-		"talkgroup_display", callRecord.TalkgroupAlias,
-		"department", callRecord.TalkgroupGroup,
-		"system", callRecord.TalkgroupGroup,
-		"timestamp", callRecord.Timestamp.Format("2006-01-02 15:04:05"))
+		"transcription_length", len(callRecord.Transcription))
 }
 
-// parseFilename extracts metadata from SDRTrunk filename format
-func (cp *CallProcessor) parseFilename(filePath string) *database.CallRecord {
-	filename := filepath.Base(filePath)
+// analyze classifies and transcribes a call's recording, then extracts
+// incident details, scores it and detects tone-outs
+func (cp *CallProcessor) analyze(ctx context.Context, callRecord *database.CallRecord, priorityTalkgroup bool) error {
+	// Recordings that aren't speech are stored without a transcript
+	if cp.voice != nil {
+		cp.classifyAudio(ctx, callRecord)
+	}
+	if callRecord.AudioClass == "" {
+		if err := cp.transcribe(ctx, callRecord); err != nil {
+			return err
+		}
+	}
 
-	// Remove extension
-	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	// Extract incident details for filtering
+	if cp.enricher != nil && callRecord.Transcription != "" && cp.enricher.Applies(callRecord) {
+		cp.enrich(ctx, callRecord)
+	}
 
-	record := &database.CallRecord{
-		Filename:  filename,
-		Filepath:  filePath,
-		Timestamp: time.Now(), // Default to current time
-		Duration:  0,          // Will be determined from audio file later
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	// Score call severity from the final transcription
+	serviceType := talkgroups.ServiceOther
+	if cp.talkgroups != nil {
+		serviceType = cp.talkgroups.GetDepartmentInfo(callRecord.TalkgroupID).Type
+	}
+	// Keywords are English, so translated calls are scored on their translation
+	text := callRecord.Transcription
+	if callRecord.Translation != "" {
+		text = callRecord.Translation
+	}
+	callRecord.Severity = int(cp.severity.Score(text, serviceType))
+	callRecord.Priority = cp.priority.Score(priority.Call{
+		TalkgroupID:       callRecord.TalkgroupID,
+		PriorityTalkgroup: priorityTalkgroup,
+		ServiceType:       serviceType,
+		Transcription:     text,
+		Duration:          callRecord.Duration,
+	})
+
+	// Detect paging tone-outs
+	if cp.config.Tones.Enabled && cp.shouldDetectTones(callRecord.TalkgroupID) {
+		cp.detectTones(ctx, callRecord)
 	}
 
-	// SDRTrunk filename format analysis:
-	// 20250607_203346Heart_of_Texas_Regional_Radio_System_(HOTRRS)_McLennan_T-Control__TO_198_FROM_3071.mp3
-	// Parts: [timestamp][system_name][site][talkgroup][TO_xxx_FROM_yyy]
+	return nil
+}
 
-	parts := strings.Split(name, "_")
+// notify sends a call's Discord, Telegram, Matrix and push notifications
+func (cp *CallProcessor) notify(callRecord *database.CallRecord) {
+	defer cp.metrics.observe(StageNotify, time.Now())
 
-	// Extract timestamp from first part if present (YYYYMMDD_HHMMSS format)
-	if len(parts) >= 2 && len(parts[0]) == 8 && len(parts[1]) >= 6 {
-		dateStr := parts[0] + parts[1][:6] // YYYYMMDDHHMMSS
-		if timestamp, err := time.ParseInLocation("20060102150405", dateStr, time.Local); err == nil {
-			record.Timestamp = timestamp
+	// Send Discord notification for new call; there is nothing to read in one that isn't speech
+	if cp.discord != nil && cp.discord.IsConnected() {
+		if callRecord.AudioClass == "" {
+			if err := cp.discord.SendCallNotification(callRecord); err != nil {
+				cp.logger.Error("Failed to send Discord notification", "error", err, "call_id", callRecord.ID)
+				cp.metrics.fail(StageNotify)
+			}
 		}
-	}
 
-	// Find system name (usually after timestamp, before site info)
-	systemName := ""
-	for i := 2; i < len(parts) && i < 8; i++ {
-		part := parts[i]
-		// Skip short parts, T-Control, TO/FROM parts
-		if len(part) > 3 && !strings.HasPrefix(part, "T-") &&
-			!strings.HasPrefix(part, "TO") && !strings.HasPrefix(part, "FROM") &&
-			!strings.Contains(part, "(") {
-			if systemName == "" {
-				systemName = part
-			} else {
-				systemName += " " + part
+		// Tone alerts are sent regardless of the severity threshold
+		for _, sequence := range callRecord.Tones {
+			if cp.toneAlertEnabled(sequence.Station) {
+				cp.discord.SendToneAlert(callRecord, sequence)
 			}
 		}
-		// Stop if we hit a parenthetical or T-Control
-		if strings.Contains(part, "(") || strings.HasPrefix(part, "T-") {
-			break
-		}
 	}
 
-	// Extract TO and FROM values for actual talkgroup identification
-	var toValue, fromValue string
-	for i, part := range parts {
-		if strings.HasPrefix(part, "TO") && i+1 < len(parts) {
-			toValue = parts[i+1]
+	if cp.notifier != nil {
+		if callRecord.AudioClass == "" {
+			cp.notifier.SendCall(callRecord)
 		}
-		if strings.HasPrefix(part, "FROM") && i+1 < len(parts) {
-			fromValue = parts[i+1]
+		for _, sequence := range callRecord.Tones {
+			if cp.toneAlertEnabled(sequence.Station) {
+				cp.notifier.SendToneAlert(callRecord, sequence)
+			}
 		}
 	}
 
-	// Determine primary talkgroup (usually the FROM value is the calling unit)
-	talkgroupID := ""
-	talkgroupAlias := ""
-	if fromValue != "" {
-		talkgroupID = fromValue
-		// Use talkgroup service for enhanced formatting with context awareness
-		if cp.talkgroups != nil {
-			// Use context-aware classification - if FROM is unknown but TO is known,
-			// infer FROM's department based on TO's department
-			var talkgroupInfo *talkgroups.TalkgroupInfo
-			var deptInfo *talkgroups.DepartmentType
-
-			if toValue != "" && toValue != fromValue {
-				// We have both FROM and TO - use context-aware classification
-				talkgroupInfo = cp.talkgroups.GetTalkgroupInfoWithContext(fromValue, toValue)
-				deptInfo = cp.talkgroups.GetDepartmentInfoWithContext(fromValue, toValue)
-
-				// Check if this is a cross-department call (e.g., police → fire)
-				fromInfoDirect := cp.talkgroups.GetTalkgroupInfo(fromValue)
-				toInfoDirect := cp.talkgroups.GetTalkgroupInfo(toValue)
-
-				// If FROM has a known department type and it differs from TO's department type,
-				// keep the original classification to preserve cross-department calls like police → fire
-				if fromInfoDirect.ServiceType != talkgroups.ServiceOther &&
-					toInfoDirect.ServiceType != talkgroups.ServiceOther &&
-					fromInfoDirect.ServiceType != toInfoDirect.ServiceType {
-					talkgroupInfo = fromInfoDirect
-					deptInfo = cp.talkgroups.GetDepartmentInfo(fromValue)
-
-					cp.logger.Debug("Cross-department call detected, preserving original classification",
-						"from_tg", fromValue,
-						"from_dept", string(fromInfoDirect.ServiceType),
-						"to_tg", toValue,
-						"to_dept", string(toInfoDirect.ServiceType))
-				}
-			} else {
-				// No context available, use standard classification
-				talkgroupInfo = cp.talkgroups.GetTalkgroupInfo(fromValue)
-				deptInfo = cp.talkgroups.GetDepartmentInfo(fromValue)
-			}
+	if cp.push != nil && callRecord.AudioClass == "" {
+		cp.push.Check(callRecord)
+	}
+}
 
-			talkgroupAlias = cp.talkgroups.FormatTalkgroupDisplay(fromValue)
+// scheduleRetry queues a call whose transcription failed to be tried again
+// with exponential backoff, or marks it failed once its retries are used up.
+// Calls cut off by shutdown are retried right away on the next start without
+// using up an attempt.
+func (cp *CallProcessor) scheduleRetry(ctx context.Context, callRecord *database.CallRecord, cause error) {
+	attempts := callRecord.Attempts
+	retryAt := time.Now()
+	if ctx.Err() == nil {
+		attempts++
+		retryAt = retryAt.Add(min(retryBaseDelay<<min(attempts-1, 16), maxRetryDelay))
+	}
 
-			// Use classified department name instead of raw group
-			if deptInfo.Type != talkgroups.ServiceOther {
-				record.TalkgroupGroup = fmt.Sprintf("%s %s", deptInfo.Emoji, talkgroupInfo.Group)
-			} else {
-				record.TalkgroupGroup = talkgroupInfo.Group
-			}
-		} else {
-			talkgroupAlias = "TG " + fromValue
+	var next *time.Time
+	if attempts <= cp.config.Transcription.MaxRetries {
+		next = &retryAt
+	}
+
+	if err := cp.db.RecordTranscriptionFailure(callRecord.ID, attempts, cause.Error(), next); err != nil {
+		cp.logger.Error("Failed to record transcription failure", "error", err, "call_id", callRecord.ID)
+		return
+	}
+
+	if next == nil {
+		cp.logger.Error("Giving up transcribing call after repeated failures",
+			"call_id", callRecord.ID, "attempts", attempts, "error", cause)
+		return
+	}
+	cp.logger.Info("Transcription will be retried",
+		"call_id", callRecord.ID, "attempt", attempts, "retry_in", time.Until(retryAt).Round(time.Second))
+}
+
+// retryDue retries transcribing the calls whose next attempt is due
+func (cp *CallProcessor) retryDue(ctx context.Context) {
+	calls, err := cp.db.GetCallsDueForRetry(time.Now(), retryBatchSize)
+	if err != nil {
+		cp.logger.Error("Failed to get calls to retry", "error", err)
+		return
+	}
+
+	for _, callRecord := range calls {
+		if ctx.Err() != nil {
+			return
 		}
 
-		// Add TO information if different
-		if toValue != "" && toValue != fromValue {
-			if cp.talkgroups != nil {
-				toDisplay := cp.talkgroups.FormatTalkgroupDisplay(toValue)
-				talkgroupAlias += " → " + toDisplay
-			} else {
-				talkgroupAlias += " → TG " + toValue
+		if _, err := os.Stat(callRecord.Filepath); err != nil {
+			cp.logger.Warn("Recording for retry is missing", "call_id", callRecord.ID, "file", callRecord.Filepath)
+			if err := cp.db.RecordTranscriptionFailure(callRecord.ID, callRecord.Attempts, "recording not found", nil); err != nil {
+				cp.logger.Error("Failed to record transcription failure", "error", err, "call_id", callRecord.ID)
 			}
+			continue
 		}
-	} else if toValue != "" {
-		talkgroupID = toValue
-		// Use talkgroup service for enhanced formatting
-		if cp.talkgroups != nil {
-			talkgroupInfo := cp.talkgroups.GetTalkgroupInfo(toValue)
-			deptInfo := cp.talkgroups.GetDepartmentInfo(toValue)
-			talkgroupAlias = cp.talkgroups.FormatTalkgroupDisplay(toValue)
-
-			// Use classified department name instead of raw group
-			if deptInfo.Type != talkgroups.ServiceOther {
-				record.TalkgroupGroup = fmt.Sprintf("%s %s", deptInfo.Emoji, talkgroupInfo.Group)
-			} else {
-				record.TalkgroupGroup = talkgroupInfo.Group
-			}
-		} else {
-			talkgroupAlias = "TG " + toValue
+
+		cp.logger.Info("Retrying transcription", "call_id", callRecord.ID, "attempt", callRecord.Attempts+1,
+			"file", filepath.Base(callRecord.Filepath))
+		cp.finishCall(ctx, callRecord, cp.talkgroupFilter(callRecord.TalkgroupID).Priority)
+	}
+}
+
+// talkgroupFilter returns the filter settings for a talkgroup, including
+// overrides made through the API
+func (cp *CallProcessor) talkgroupFilter(talkgroupID string) config.TalkgroupFilterConfig {
+	if cp.talkgroups != nil {
+		return cp.talkgroups.GetTalkgroupFilter(talkgroupID)
+	}
+	return cp.config.GetTalkgroupFilter(talkgroupID)
+}
+
+// transcribe transcribes a call's recording and applies corrections
+func (cp *CallProcessor) transcribe(ctx context.Context, callRecord *database.CallRecord) error {
+	cp.metrics.transcribing.Add(1)
+	start := time.Now()
+	result, err := cp.transcriber.TranscribeFile(ctx, callRecord.Filepath, &transcription.Options{
+		TalkgroupID: callRecord.TalkgroupID,
+		Vocabulary:  cp.config.GetGlossary(callRecord.TalkgroupID),
+	})
+	cp.metrics.transcribing.Add(-1)
+	if err != nil {
+		cp.metrics.fail(StageTranscribe)
+		return err
+	}
+	cp.metrics.observe(StageTranscribe, start)
+
+	// Apply post-transcription corrections before storage and notification
+	if cp.corrections != nil {
+		result.Text = cp.corrections.Apply(callRecord.TalkgroupID, result.Text)
+		for i := range result.Segments {
+			result.Segments[i].Text = cp.corrections.Apply(callRecord.TalkgroupID, result.Segments[i].Text)
 		}
 	}
 
-	// If no TO/FROM found, look for T-Control or other patterns
-	if talkgroupID == "" {
-		for _, part := range parts {
-			if strings.HasPrefix(part, "T-") {
-				talkgroupID = part
-				if cp.talkgroups != nil {
-					// T-Control is typically emergency management
-					talkgroupAlias = "🚨 " + part
-					record.TalkgroupGroup = "Emergency Management"
-				} else {
-					talkgroupAlias = part
-				}
-				break
-			}
+	// Multi-speaker calls read as a dialog instead of one run-on block
+	if transcription.SpeakerCount(result.Segments) > 1 {
+		result.Text = transcription.FormatDialog(result.Segments)
+	}
+
+	// Update the call record with transcription
+	callRecord.Transcription = result.Text
+	callRecord.Language = result.Language
+	if cp.translator != nil && translation.Needed(result.Language) && result.Text != "" {
+		cp.translate(ctx, callRecord)
+	}
+
+	// Keep speaker-tagged segments from diarization
+	if len(result.Segments) > 0 {
+		callRecord.Segments = make([]database.SpeakerSegment, len(result.Segments))
+		for i, segment := range result.Segments {
+			callRecord.Segments[i] = database.SpeakerSegment(segment)
 		}
 	}
 
-	// Set default if still empty
-	if talkgroupID == "" {
-		talkgroupID = "Unknown"
-		talkgroupAlias = "🔔 Unknown Talkgroup"
-		if record.TalkgroupGroup == "" {
-			record.TalkgroupGroup = "Unknown Department"
+	return nil
+}
+
+// translate stores an English translation of a call's transcription. The
+// original transcription is kept if translation fails.
+func (cp *CallProcessor) translate(ctx context.Context, callRecord *database.CallRecord) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	translated, err := cp.translator.Translate(ctx, callRecord.Transcription, callRecord.Language)
+	if err != nil {
+		cp.logger.Warn("Failed to translate transcription", "error", err, "call_id", callRecord.ID, "language", callRecord.Language)
+		return
+	}
+	callRecord.Translation = translated
+	cp.logger.Debug("Processor", "Translated transcription", "call_id", callRecord.ID, "language", callRecord.Language)
+}
+
+// enrich stores the incident details the LLM extracts from a call's
+// transcription. The call is stored without them if enrichment fails.
+func (cp *CallProcessor) enrich(ctx context.Context, callRecord *database.CallRecord) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cp.config.LLM.Timeout)*time.Second)
+	defer cancel()
+
+	text := callRecord.Transcription
+	if callRecord.Translation != "" {
+		text = callRecord.Translation
+	}
+
+	result, err := cp.enricher.Enrich(ctx, text, callRecord.TalkgroupAlias)
+	if err != nil {
+		cp.logger.Warn("Failed to enrich call", "error", err, "call_id", callRecord.ID)
+		return
+	}
+	callRecord.Enrichment = result
+	cp.logger.Debug("Processor", "Enriched call", "call_id", callRecord.ID,
+		"incident_type", result.IncidentType, "severity", result.Severity, "location", result.Location)
+}
+
+// classifyAudio flags recordings that are encrypted or data bursts rather
+// than speech. Recordings that can't be analyzed are transcribed as usual.
+func (cp *CallProcessor) classifyAudio(ctx context.Context, callRecord *database.CallRecord) {
+	result, err := cp.voice.ClassifyFile(ctx, callRecord.Filepath)
+	if err != nil {
+		cp.logger.Warn("Voice detection failed", "error", err, "file", filepath.Base(callRecord.Filepath))
+		return
+	}
+
+	if result.Class != voice.Voice {
+		callRecord.AudioClass = string(result.Class)
+		cp.logger.Info("Skipping transcription of non-voice recording",
+			"file", filepath.Base(callRecord.Filepath),
+			"class", result.Class,
+			"flatness", fmt.Sprintf("%.2f", result.Flatness),
+			"modulation", fmt.Sprintf("%.1fdB", result.Modulation))
+	}
+}
+
+// linkDuplicate marks a call as a duplicate of an earlier recording of the
+// same transmission, if there is one
+func (cp *CallProcessor) linkDuplicate(callRecord *database.CallRecord) {
+	if callRecord.TalkgroupID == "" || callRecord.TalkgroupID == "Unknown" {
+		return
+	}
+
+	candidates, err := cp.db.GetSimulcastCandidates(callRecord, cp.dedup.Window())
+	if err != nil {
+		cp.logger.Warn("Failed to check for simulcast duplicates", "error", err, "call_id", callRecord.ID)
+		return
+	}
+
+	if original := cp.dedup.Match(callRecord, candidates); original != nil {
+		callRecord.DuplicateOf = original.ID
+	}
+}
+
+// shouldDetectTones reports whether a talkgroup is configured for tone detection
+func (cp *CallProcessor) shouldDetectTones(talkgroupID string) bool {
+	if len(cp.config.Tones.Talkgroups) == 0 {
+		return true
+	}
+	for _, id := range cp.config.Tones.Talkgroups {
+		if id == talkgroupID {
+			return true
+		}
+	}
+	return false
+}
+
+// detectTones finds two-tone paging sequences in the call audio
+func (cp *CallProcessor) detectTones(ctx context.Context, callRecord *database.CallRecord) {
+	sequences, err := tones.DetectFile(ctx, callRecord.Filepath, tones.Options{
+		MinToneA: cp.config.Tones.MinToneA,
+		MinToneB: cp.config.Tones.MinToneB,
+		FFmpeg:   cp.config.FFmpeg.Path,
+	})
+	if err != nil {
+		cp.logger.Warn("Tone detection failed", "error", err, "file", filepath.Base(callRecord.Filepath))
+		return
+	}
+	if len(sequences) == 0 {
+		return
+	}
+
+	callRecord.Tones = make([]database.ToneSequence, len(sequences))
+	for i, sequence := range sequences {
+		if station, ok := tones.Match(sequence, cp.config.Tones.Stations, cp.config.Tones.Tolerance); ok {
+			sequence.Station = station.Name
 		}
+		callRecord.Tones[i] = database.ToneSequence(sequence)
+
+		cp.logger.Info("Paging tones detected",
+			"call_id", callRecord.ID,
+			"tone_a", sequence.ToneA,
+			"tone_b", sequence.ToneB,
+			"station", sequence.Station)
 	}
+}
 
-	record.TalkgroupID = talkgroupID
-	record.TalkgroupAlias = talkgroupAlias
+// transcode replaces a call's recording with an Opus copy. The original is
+// kept if anything fails, so a call always has playable audio.
+func (cp *CallProcessor) transcode(ctx context.Context, callRecord *database.CallRecord) {
+	original := callRecord.Filepath
+	if strings.EqualFold(filepath.Ext(original), transcode.Extension) {
+		return
+	}
 
-	// Use system name from filename if talkgroup service didn't set it
-	if record.TalkgroupGroup == "" || record.TalkgroupGroup == "Unknown Department" {
-		record.TalkgroupGroup = systemName
+	target := strings.TrimSuffix(original, filepath.Ext(original)) + transcode.Extension
+	partial := target + ".part"
+	if err := transcode.ToOpus(ctx, cp.config.FFmpeg.Path, original, partial, cp.config.Transcode.Bitrate); err != nil {
+		cp.logger.Warn("Failed to transcode recording", "error", err, "file", filepath.Base(original))
+		os.Remove(partial)
+		return
 	}
 
-	// Try to extract frequency if present in filename
-	for _, part := range parts {
-		// Look for frequency patterns (numbers with MHz or decimal points)
-		if strings.Contains(strings.ToLower(part), "mhz") ||
-			(strings.Contains(part, ".") && len(part) > 3 && len(part) < 10) {
-			record.Frequency = part
-			break
+	// Point the call at the new file before it appears under its final name,
+	// so the file watcher sees it as already processed
+	if err := cp.db.UpdateCallFile(callRecord.ID, filepath.Base(target), target); err != nil {
+		cp.logger.Error("Failed to update transcoded call", "error", err, "id", callRecord.ID)
+		os.Remove(partial)
+		return
+	}
+	if err := os.Rename(partial, target); err != nil {
+		cp.logger.Error("Failed to move transcoded recording", "error", err, "file", filepath.Base(target))
+		os.Remove(partial)
+		if err := cp.db.UpdateCallFile(callRecord.ID, callRecord.Filename, original); err != nil {
+			cp.logger.Error("Failed to restore call file", "error", err, "id", callRecord.ID)
 		}
+		return
 	}
+	callRecord.Filename = filepath.Base(target)
+	callRecord.Filepath = target
 
-	return record
+	var before, after int64
+	if info, err := os.Stat(original); err == nil {
+		before = info.Size()
+	}
+	if info, err := os.Stat(target); err == nil {
+		after = info.Size()
+	}
+	if err := os.Remove(original); err != nil {
+		cp.logger.Warn("Failed to remove original recording", "error", err, "file", filepath.Base(original))
+	}
+
+	cp.logger.Debug("Processor", "Transcoded recording to Opus",
+		"call_id", callRecord.ID,
+		"file", callRecord.Filename,
+		"before", before,
+		"after", after)
+}
+
+// toneAlertEnabled reports whether a matched station has alerts enabled
+func (cp *CallProcessor) toneAlertEnabled(station string) bool {
+	if station == "" {
+		return false
+	}
+	for _, configured := range cp.config.Tones.Stations {
+		if configured.Name == station {
+			return configured.Alert
+		}
+	}
+	return false
 }
 
-// getAudioDuration calculates the duration of an audio file using ffprobe
+// getAudioDuration calculates the duration of an audio file. MP3 and WAV
+// recordings are measured from their headers; other formats need ffprobe.
 func (cp *CallProcessor) getAudioDuration(filePath string) (time.Duration, error) {
-	// Try ffprobe first (most reliable)
-	cmd := exec.Command("ffprobe", "-v", "quiet", "-show_entries", "format=duration", "-of", "csv=p=0", filePath)
+	duration, err := audio.Duration(filePath)
+	if err == nil {
+		return duration, nil
+	}
+	if !errors.Is(err, audio.ErrUnsupportedFormat) {
+		cp.logger.Debug("Processor", "Failed to read audio duration from headers", "file", filepath.Base(filePath), "error", err)
+	}
+
+	// Try ffprobe next
+	cmd := exec.Command(cp.config.FFmpeg.FFprobePath, "-v", "quiet", "-show_entries", "format=duration", "-of", "csv=p=0", filePath)
 	output, err := cmd.Output()
 	if err == nil {
 		durationStr := strings.TrimSpace(string(output))
@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"Meiko/internal/database"
+)
+
+// Retranscribe transcribes a stored call again and rescores it, e.g. after a
+// model or vocabulary change. The call is not notified or broadcast again.
+// Calls marked as noise by hand are left as they are.
+func (cp *CallProcessor) Retranscribe(ctx context.Context, callRecord *database.CallRecord) error {
+	if callRecord.AudioClass == database.AudioClassNoise {
+		return nil
+	}
+	if _, err := os.Stat(callRecord.Filepath); err != nil {
+		return fmt.Errorf("recording not found: %w", err)
+	}
+
+	// The previous results are replaced, not merged
+	callRecord.AudioClass = ""
+	callRecord.Transcription = ""
+	callRecord.Language = ""
+	callRecord.Translation = ""
+	callRecord.Segments = nil
+	callRecord.Tones = nil
+	callRecord.Enrichment = nil
+
+	if err := cp.analyze(ctx, callRecord, cp.talkgroupFilter(callRecord.TalkgroupID).Priority); err != nil {
+		return err
+	}
+
+	callRecord.Processed = true
+	if err := cp.db.UpdateCallResults(callRecord); err != nil {
+		return err
+	}
+
+	if cp.embeddings != nil && callRecord.Transcription != "" {
+		if err := cp.embeddings.EmbedCall(ctx, callRecord); err != nil {
+			cp.logger.Warn("Failed to embed call", "error", err, "call_id", callRecord.ID)
+		}
+	}
+	return nil
+}
+
+// Reclassify looks a stored call's talkgroup up again in the current playlist
+// and overrides, reporting whether its display name or group changed. The
+// stored talkgroup is used, so calls corrected by hand keep their talkgroup.
+func (cp *CallProcessor) Reclassify(callRecord *database.CallRecord) (bool, error) {
+	if cp.talkgroups == nil {
+		return false, nil
+	}
+
+	alias := cp.talkgroups.FormatTalkgroupDisplay(callRecord.TalkgroupID)
+	group := cp.talkgroups.FormatTalkgroupGroup(callRecord.TalkgroupID)
+	if alias == callRecord.TalkgroupAlias && group == callRecord.TalkgroupGroup {
+		return false, nil
+	}
+
+	if err := cp.db.UpdateCallTalkgroup(callRecord.ID, alias, group); err != nil {
+		return false, err
+	}
+	callRecord.TalkgroupAlias = alias
+	callRecord.TalkgroupGroup = group
+	return true, nil
+}
+
+// Renotify sends a stored call's notifications again. Simulcast duplicates
+// and calls still waiting on a transcription are skipped.
+func (cp *CallProcessor) Renotify(callRecord *database.CallRecord) bool {
+	if !callRecord.Processed || callRecord.DuplicateOf > 0 {
+		return false
+	}
+
+	cp.notify(callRecord)
+	return true
+}
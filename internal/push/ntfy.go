@@ -0,0 +1,59 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"Meiko/internal/config"
+)
+
+// ntfy sends alerts to an ntfy topic, on ntfy.sh or a self-hosted server
+type ntfy struct {
+	config config.NtfyConfig
+	client *http.Client
+}
+
+func (n *ntfy) name() string {
+	return "ntfy"
+}
+
+func (n *ntfy) send(ctx context.Context, alert Alert) error {
+	// Published as JSON so titles aren't limited to what fits in a header
+	body, err := json.Marshal(map[string]interface{}{
+		"topic":    n.config.Topic,
+		"title":    alert.Title,
+		"message":  alert.Message,
+		"priority": alert.Priority + 3, // ntfy priorities run from 1 (min) to 5 (max)
+		"tags":     alert.Tags,
+		"click":    alert.URL,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(n.config.Server, "/"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.Token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ntfy request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("ntfy error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+	"Meiko/internal/redaction"
+	"Meiko/internal/talkgroups"
+	"Meiko/internal/textutil"
+)
+
+// sendTimeout limits sending an alert to each service
+const sendTimeout = 15 * time.Second
+
+// Alert is a push notification for a call that matched a rule
+type Alert struct {
+	Title    string
+	Message  string
+	URL      string // Link to the call audio, if base_url is set
+	Priority int    // Pushover priority, -2 to 2
+	Sound    string
+	Tags     []string
+}
+
+// sender delivers alerts to a push service
+type sender interface {
+	name() string
+	send(ctx context.Context, alert Alert) error
+}
+
+// Service checks calls against the push rules and sends alerts for matches
+type Service struct {
+	config     config.PushConfig
+	talkgroups *talkgroups.Service
+	senders    []sender
+	redactor   *redaction.Redactor // Hides sensitive details in alerts, nil when disabled
+	logger     *logger.Logger
+
+	onAlert func(rule string, call *database.CallRecord)
+
+	mu        sync.Mutex
+	lastAlert map[string]time.Time // Last alert by rule and talkgroup, for the cooldown
+}
+
+// New creates the push alert service with the configured services
+func New(cfg config.PushConfig, talkgroupService *talkgroups.Service, logger *logger.Logger) *Service {
+	client := &http.Client{Timeout: 30 * time.Second}
+	s := &Service{
+		config:     cfg,
+		talkgroups: talkgroupService,
+		logger:     logger,
+		lastAlert:  make(map[string]time.Time),
+	}
+	if cfg.Pushover.AppToken != "" {
+		s.senders = append(s.senders, &pushover{config: cfg.Pushover, client: client})
+	}
+	if cfg.Ntfy.Topic != "" {
+		s.senders = append(s.senders, &ntfy{config: cfg.Ntfy, client: client})
+	}
+	return s
+}
+
+// SetRedactor sets the rules that hide sensitive details in alerts
+func (s *Service) SetRedactor(redactor *redaction.Redactor) {
+	s.redactor = redactor
+}
+
+// OnAlert sets a callback for each call that raises an alert, with the name
+// of the rule it matched. It must be set before calls are checked.
+func (s *Service) OnAlert(fn func(rule string, call *database.CallRecord)) {
+	s.onAlert = fn
+}
+
+// Check sends an alert for a call if it matches a rule. Only the first
+// matching rule alerts, and not again for the same talkgroup until the
+// cooldown has passed.
+func (s *Service) Check(call *database.CallRecord) {
+	rule, ok := s.match(call)
+	if !ok {
+		return
+	}
+
+	key := rule.Name + "|" + call.SystemID + "|" + call.TalkgroupID
+	cooldown := time.Duration(s.config.Cooldown) * time.Minute
+	s.mu.Lock()
+	if last, ok := s.lastAlert[key]; ok && call.Timestamp.Sub(last) < cooldown {
+		s.mu.Unlock()
+		s.logger.Debug("Push", "Alert suppressed during cooldown", "rule", rule.Name, "call_id", call.ID)
+		return
+	}
+	s.lastAlert[key] = call.Timestamp
+	s.mu.Unlock()
+
+	if s.onAlert != nil {
+		s.onAlert(rule.Name, call)
+	}
+
+	alert := s.alert(rule, s.redactor.Call(call))
+	for _, service := range s.senders {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+			if err := service.send(ctx, alert); err != nil {
+				s.logger.Error("Failed to send push alert", "service", service.name(), "rule", rule.Name, "error", err)
+				return
+			}
+			s.logger.Info("Sent push alert", "service", service.name(), "rule", rule.Name, "call_id", call.ID)
+		}()
+	}
+}
+
+// match returns the first rule a call matches
+func (s *Service) match(call *database.CallRecord) (config.PushRuleConfig, bool) {
+	serviceType := talkgroups.ServiceOther
+	if s.talkgroups != nil {
+		serviceType = s.talkgroups.GetDepartmentInfo(call.TalkgroupID).Type
+	}
+	text := strings.ToLower(call.Transcription + " " + call.Translation)
+
+	for _, rule := range s.config.Rules {
+		if len(rule.Talkgroups) > 0 && !slices.Contains(rule.Talkgroups, call.TalkgroupID) {
+			continue
+		}
+		if len(rule.ServiceTypes) > 0 && !slices.ContainsFunc(rule.ServiceTypes, func(t string) bool {
+			return strings.EqualFold(t, string(serviceType))
+		}) {
+			continue
+		}
+		if call.Severity < rule.MinSeverity || call.Priority < rule.MinPriority {
+			continue
+		}
+		if len(rule.Keywords) > 0 && !slices.ContainsFunc(rule.Keywords, func(keyword string) bool {
+			return keyword != "" && strings.Contains(text, strings.ToLower(keyword))
+		}) {
+			continue
+		}
+		return rule, true
+	}
+	return config.PushRuleConfig{}, false
+}
+
+// alert builds the push notification for a call matching a rule
+func (s *Service) alert(rule config.PushRuleConfig, call *database.CallRecord) Alert {
+	text := call.Transcription
+	if call.Translation != "" {
+		text = call.Translation
+	}
+	if text == "" {
+		text = "No transcription available"
+	}
+
+	alert := Alert{
+		Title:    fmt.Sprintf("%s: %s", rule.Name, call.TalkgroupAlias),
+		Message:  fmt.Sprintf("%s\n\n%s", textutil.Truncate(text, 800), call.Timestamp.Format("15:04:05")),
+		Priority: rule.Priority,
+		Sound:    rule.Sound,
+		Tags:     rule.Tags,
+	}
+	if s.config.BaseURL != "" {
+		alert.URL = strings.TrimRight(s.config.BaseURL, "/") + "/api/v1/calls/" + strconv.Itoa(call.ID) + "/audio"
+	}
+	return alert
+}
@@ -0,0 +1,70 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"Meiko/internal/config"
+)
+
+// pushoverURL is the Pushover messages API
+const pushoverURL = "https://api.pushover.net/1/messages.json"
+
+// pushover sends alerts through Pushover
+type pushover struct {
+	config config.PushoverConfig
+	client *http.Client
+}
+
+func (p *pushover) name() string {
+	return "pushover"
+}
+
+func (p *pushover) send(ctx context.Context, alert Alert) error {
+	form := url.Values{
+		"token":    {p.config.AppToken},
+		"user":     {p.config.UserKey},
+		"title":    {alert.Title},
+		"message":  {alert.Message},
+		"priority": {strconv.Itoa(alert.Priority)},
+	}
+	if alert.Sound != "" {
+		form.Set("sound", alert.Sound)
+	}
+	if alert.URL != "" {
+		form.Set("url", alert.URL)
+		form.Set("url_title", "Listen to the call")
+	}
+	if alert.Priority == 2 {
+		// Emergency alerts repeat every minute for an hour until acknowledged
+		form.Set("retry", "60")
+		form.Set("expire", "3600")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pushoverURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Pushover request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Status int      `json:"status"`
+		Errors []string `json:"errors"`
+	}
+	json.NewDecoder(resp.Body).Decode(&response)
+	if resp.StatusCode != http.StatusOK || response.Status != 1 {
+		return fmt.Errorf("Pushover API error (status %d): %s", resp.StatusCode, strings.Join(response.Errors, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package redaction
+
+import (
+	"Meiko/internal/config"
+	"Meiko/internal/corrections"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+)
+
+// defaultNames hide people identified in radio traffic
+var defaultNames = []config.CorrectionRuleConfig{
+	{ // "Subject's name is John Smith", "last name Garcia"
+		Find:    `\b((?i:name is|named|last name|first name|last name of|first name of)\s+)[A-Z][a-zA-Z'-]+(?:\s+[A-Z][a-zA-Z'-]+)?`,
+		Replace: "${1}[name]",
+		Regex:   true,
+	},
+	{ // Dates of birth read out for a records check
+		Find:    `\b((?i:dob|date of birth|born)\s*(?i:is|of)?\s*)\d{1,2}[\s/.-]+\d{1,2}[\s/.-]+\d{2,4}\b`,
+		Replace: "${1}[dob]",
+		Regex:   true,
+	},
+}
+
+// defaultPlates hide licence plates read out for a vehicle check. A plate
+// needs at least one digit, so ordinary words after "tag" are left alone.
+var defaultPlates = []config.CorrectionRuleConfig{
+	{
+		Find:    `\b((?i:plate|tag|registration|licen[cs]e)(?:\s+(?i:number|no|is|reads|of))*[\s:#.]+)(?:[A-Z0-9]{1,4}[\s-]?)?[A-Z0-9]*[0-9][A-Z0-9]*\b`,
+		Replace: "${1}[plate]",
+		Regex:   true,
+	},
+}
+
+// defaultMedical hide conditions that identify a patient's health
+var defaultMedical = []config.CorrectionRuleConfig{
+	{
+		Find:    `(?i)\b(?:overdos(?:e|ed|ing)|suicid(?:e|al)|psychiatric|psych eval(?:uation)?|mental health|HIV|hepatitis|pregnan(?:t|cy)|miscarriage|dementia|detox|sexual assault|rape)\b`,
+		Replace: "[medical]",
+		Regex:   true,
+	},
+}
+
+// Redactor hides sensitive details in transcripts before they are shown or
+// posted. A nil Redactor leaves text unchanged, so callers needn't check
+// whether redaction is enabled.
+type Redactor struct {
+	engine *corrections.Engine
+}
+
+// New compiles the configured patterns, after the built-in ones unless they
+// are replaced
+func New(cfg config.RedactionConfig, logger *logger.Logger) (*Redactor, error) {
+	var rules []config.CorrectionRuleConfig
+	if !cfg.ReplaceDefaults {
+		rules = append(rules, defaultNames...)
+		rules = append(rules, defaultPlates...)
+		rules = append(rules, defaultMedical...)
+	}
+	rules = append(rules, patternRules(cfg.Names, "[name]")...)
+	rules = append(rules, patternRules(cfg.Plates, "[plate]")...)
+	rules = append(rules, patternRules(cfg.Medical, "[medical]")...)
+
+	engine, err := corrections.New(config.CorrectionsConfig{Rules: rules}, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &Redactor{engine: engine}, nil
+}
+
+// patternRules turns regular expressions into rules replacing each match
+func patternRules(patterns []string, replacement string) []config.CorrectionRuleConfig {
+	rules := make([]config.CorrectionRuleConfig, len(patterns))
+	for i, pattern := range patterns {
+		rules[i] = config.CorrectionRuleConfig{Find: pattern, Replace: replacement, Regex: true}
+	}
+	return rules
+}
+
+// Text redacts text from a talkgroup
+func (r *Redactor) Text(talkgroupID, text string) string {
+	if r == nil {
+		return text
+	}
+	return r.engine.Apply(talkgroupID, text)
+}
+
+// Call returns a copy of a call with its transcription, translation and
+// speaker segments redacted. The call itself is left unchanged.
+func (r *Redactor) Call(call *database.CallRecord) *database.CallRecord {
+	if r == nil {
+		return call
+	}
+
+	redacted := *call
+	redacted.Transcription = r.Text(call.TalkgroupID, call.Transcription)
+	redacted.Translation = r.Text(call.TalkgroupID, call.Translation)
+	if len(call.Segments) > 0 {
+		redacted.Segments = make([]database.SpeakerSegment, len(call.Segments))
+		for i, segment := range call.Segments {
+			segment.Text = r.Text(call.TalkgroupID, segment.Text)
+			redacted.Segments[i] = segment
+		}
+	}
+	return &redacted
+}
+
+// RuleCount returns the number of redaction rules
+func (r *Redactor) RuleCount() int {
+	if r == nil {
+		return 0
+	}
+	return r.engine.RuleCount()
+}
@@ -0,0 +1,108 @@
+package sdrtrunk
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// healthCheckInterval is how often a running process is checked for signs of life
+const healthCheckInterval = time.Minute
+
+// SetActivitySource sets a function reporting when the process last produced
+// a recording, used by the health check's audio timeout
+func (m *Manager) SetActivitySource(lastRecording func() time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastRecording = lastRecording
+}
+
+// touchOutput records that SDRTrunk printed something. It runs on the output
+// copying goroutines, which must not block on the mutex while Stop holds it.
+func (m *Manager) touchOutput() {
+	m.lastOutput.Store(time.Now().UnixNano())
+}
+
+// healthChecker periodically checks one run of the process for signs that
+// it is hung: a JVM that stops working usually keeps running
+func (m *Manager) healthChecker(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Debug(m.component(), "Health checker stopping")
+			return
+		case <-ticker.C:
+			m.checkHealth(time.Now())
+		}
+	}
+}
+
+// checkHealth marks the process as hung when it has gone quiet for too long
+// and either kills it, leaving the restart to the restart policy, or alerts.
+// A process that recovers after an alert is reported as healthy again.
+func (m *Manager) checkHealth(now time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.running || m.cmd == nil {
+		return
+	}
+
+	reason := m.stalled(now)
+	if reason == "" {
+		if m.hung != "" {
+			m.logger.Info(m.label("SDRTrunk activity resumed"))
+			m.report("SDRTrunk is active again")
+			m.hung = ""
+		}
+		return
+	}
+	if m.hung != "" {
+		return // Already handled
+	}
+
+	m.hung = reason
+	if m.config.HealthCheck.Action == "alert" {
+		m.logger.Warn(m.label("SDRTrunk appears hung"), "reason", reason)
+		m.report("SDRTrunk appears hung: " + reason)
+		return
+	}
+
+	// Kill rather than interrupt: a wedged JVM may not shut down cleanly. The
+	// monitor reports the exit and restarts the process.
+	m.logger.Error(m.label("SDRTrunk appears hung, killing it"), "reason", reason, "pid", m.cmd.Process.Pid)
+	if err := kill(m.cmd); err != nil {
+		m.logger.Error(m.label("Failed to kill hung SDRTrunk process"), "error", err)
+	}
+}
+
+// stalled returns why the process looks hung, or an empty string. Timeouts
+// count from the start of the run, so a fresh process gets the full window.
+// The caller must hold the mutex.
+func (m *Manager) stalled(now time.Time) string {
+	health := m.config.HealthCheck
+
+	if health.AudioTimeout > 0 {
+		last := m.startTime
+		if m.lastRecording != nil {
+			if recorded := m.lastRecording(); recorded.After(last) {
+				last = recorded
+			}
+		}
+		if quiet := now.Sub(last); quiet > time.Duration(health.AudioTimeout)*time.Minute {
+			return fmt.Sprintf("no recordings for %s", quiet.Round(time.Minute))
+		}
+	}
+
+	if health.LogTimeout > 0 {
+		last := time.Unix(0, m.lastOutput.Load())
+		if quiet := now.Sub(last); quiet > time.Duration(health.LogTimeout)*time.Minute {
+			return fmt.Sprintf("no log output for %s", quiet.Round(time.Minute))
+		}
+	}
+
+	return ""
+}
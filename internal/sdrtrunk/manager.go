@@ -9,40 +9,102 @@ import (
 	"regexp"
 	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"Meiko/internal/config"
 	"Meiko/internal/logger"
 )
 
+const (
+	// stableRunTime is how long a process must run before its restart count resets
+	stableRunTime = 10 * time.Minute
+
+	// maxRestartDelay caps the doubling delay between restarts
+	maxRestartDelay = 5 * time.Minute
+
+	// stopTimeout is how long a process gets to exit after being asked to stop
+	stopTimeout = 10 * time.Second
+)
+
+// Process states reported in ProcessStatus
+const (
+	StateStopped    = "stopped"
+	StateRunning    = "running"
+	StateRestarting = "restarting"
+	StateFailed     = "failed"
+)
+
 // Manager handles the SDRTrunk process lifecycle
 type Manager struct {
-	config  config.SDRTrunkConfig
-	logger  *logger.Logger
-	cmd     *exec.Cmd
-	mutex   sync.RWMutex
-	running bool
-	ctx     context.Context
-	cancel  context.CancelFunc
+	name      string
+	config    config.SDRTrunkConfig
+	logger    *logger.Logger
+	cmd       *exec.Cmd
+	mutex     sync.RWMutex
+	running   bool
+	state     string
+	startTime time.Time
+	restarts  int
+	lastExit  string
+	exited    chan struct{}
+	parent    context.Context
+	ctx       context.Context
+	cancel    context.CancelFunc
+	notify    func(m *Manager, message string)
+
+	// Health checking
+	lastOutput    atomic.Int64     // Unix nanoseconds of the last line SDRTrunk printed
+	lastRecording func() time.Time // When a recording last appeared, if known
+	hung          string           // Why the running process is considered hung
 }
 
 // ProcessStatus represents the status of the SDRTrunk process
 type ProcessStatus struct {
-	Running   bool
-	PID       int
-	StartTime time.Time
-	Error     error
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	Running   bool      `json:"running"`
+	PID       int       `json:"pid,omitempty"`
+	StartTime time.Time `json:"start_time,omitempty"`
+	Restarts  int       `json:"restarts"`            // Consecutive restarts since the process was last stable
+	LastExit  string    `json:"last_exit,omitempty"` // How the process last exited unexpectedly
+	Hung      string    `json:"hung,omitempty"`      // Why the running process is considered hung
+	OutputDir string    `json:"output_dir"`
+	Error     error     `json:"-"`
 }
 
-// New creates a new SDRTrunk manager
-func New(config config.SDRTrunkConfig, logger *logger.Logger) *Manager {
+// New creates a new SDRTrunk manager. The name identifies the process in logs
+// when several are supervised and may be empty for a single process.
+func New(name string, config config.SDRTrunkConfig, logger *logger.Logger) *Manager {
 	return &Manager{
+		name:   name,
 		config: config,
 		logger: logger,
+		state:  StateStopped,
 	}
 }
 
+// Name returns the process name, which is empty for a single unnamed process
+func (m *Manager) Name() string {
+	return m.name
+}
+
+// label prefixes a log message with the process name
+func (m *Manager) label(message string) string {
+	if m.name == "" {
+		return message
+	}
+	return "[" + m.name + "] " + message
+}
+
+// component returns the debug log component for this process
+func (m *Manager) component() string {
+	if m.name == "" {
+		return "SDRTrunk"
+	}
+	return "SDRTrunk/" + m.name
+}
+
 // Start launches the SDRTrunk process
 func (m *Manager) Start(ctx context.Context) error {
 	m.mutex.Lock()
@@ -52,17 +114,20 @@ func (m *Manager) Start(ctx context.Context) error {
 		return fmt.Errorf("SDRTrunk is already running")
 	}
 
-	// Create a context for this process
+	// Create a context for this process; restarts derive a new one from the parent
+	m.parent = ctx
 	m.ctx, m.cancel = context.WithCancel(ctx)
 
 	// Validate the SDRTrunk path
 	if err := m.validateSDRTrunkPath(); err != nil {
+		m.cancel()
 		return fmt.Errorf("SDRTrunk validation failed: %w", err)
 	}
 
 	// Build the command
 	cmd, err := m.buildCommand()
 	if err != nil {
+		m.cancel()
 		return fmt.Errorf("failed to build command: %w", err)
 	}
 
@@ -73,13 +138,13 @@ func (m *Manager) Start(ctx context.Context) error {
 	isJarFile := strings.HasSuffix(fileName, ".jar")
 
 	if isJarFile {
-		m.logger.Info("Starting SDRTrunk JAR",
+		m.logger.Info(m.label("Starting SDRTrunk JAR"),
 			"jar_path", m.config.Path,
 			"java_path", m.config.JavaPath,
 			"working_dir", cmd.Dir,
 			"jvm_args", m.config.JVMArgs)
 	} else {
-		m.logger.Info("Starting SDRTrunk binary",
+		m.logger.Info(m.label("Starting SDRTrunk binary"),
 			"binary_path", m.config.Path,
 			"working_dir", cmd.Dir,
 			"args", m.config.Args)
@@ -87,21 +152,32 @@ func (m *Manager) Start(ctx context.Context) error {
 
 	// Start the process
 	if err := m.cmd.Start(); err != nil {
+		m.cancel()
 		return fmt.Errorf("failed to start SDRTrunk: %w", err)
 	}
 
 	m.running = true
-	m.logger.Success("SDRTrunk process started successfully",
+	m.state = StateRunning
+	m.startTime = time.Now()
+	m.hung = ""
+	m.lastOutput.Store(m.startTime.UnixNano())
+	m.exited = make(chan struct{})
+	m.logger.Success(m.label("SDRTrunk process started successfully"),
 		"pid", m.cmd.Process.Pid,
 		"type", map[bool]string{true: "JAR", false: "binary"}[isJarFile])
 
-	m.logger.Info("SDRTrunk output directory", "path", m.config.AudioOutputDir)
+	m.logger.Info(m.label("SDRTrunk output directory"), "path", m.config.AudioOutputDir)
 
 	// Start monitoring in a separate goroutine
-	go m.monitor()
+	go m.monitor(m.ctx, m.cmd, m.exited)
 
 	// Start periodic status reporting
-	go m.statusReporter()
+	go m.statusReporter(m.ctx)
+
+	// Start checking for a hung process
+	if m.config.HealthCheck.Enabled {
+		go m.healthChecker(m.ctx)
+	}
 
 	return nil
 }
@@ -112,50 +188,38 @@ func (m *Manager) Stop() error {
 	defer m.mutex.Unlock()
 
 	if !m.running || m.cmd == nil {
-		m.logger.Debug("SDRTrunk", "Stop requested but process not running")
+		m.logger.Debug(m.component(), "Stop requested but process not running")
+		m.state = StateStopped // Abandons any pending restart
 		return nil
 	}
 
 	pid := m.cmd.Process.Pid
-	m.logger.Info("Stopping SDRTrunk process", "pid", pid)
+	m.logger.Info(m.label("Stopping SDRTrunk process"), "pid", pid)
 
-	// Cancel the context
+	// Cancelling the context interrupts the process (see buildCommand) and
+	// marks the exit as expected so the monitor does not restart the process
 	if m.cancel != nil {
 		m.cancel()
 	}
 
-	// Try graceful shutdown first
-	m.logger.Debug("SDRTrunk", "Sending SIGTERM for graceful shutdown")
-	if err := m.cmd.Process.Signal(syscall.SIGTERM); err != nil {
-		m.logger.Warn("Failed to send SIGTERM to SDRTrunk", "pid", pid, "error", err)
-	}
-
 	// Wait for graceful shutdown
-	done := make(chan error, 1)
-	go func() {
-		done <- m.cmd.Wait()
-	}()
-
 	select {
-	case <-time.After(10 * time.Second):
+	case <-time.After(stopTimeout):
 		// Force kill if it doesn't shutdown gracefully
-		m.logger.Warn("SDRTrunk did not shutdown gracefully, forcing termination", "pid", pid)
-		if err := m.cmd.Process.Kill(); err != nil {
-			m.logger.Error("Failed to kill SDRTrunk process", "pid", pid, "error", err)
-		}
-		<-done // Wait for the process to actually exit
-		m.logger.Info("SDRTrunk process terminated forcefully", "pid", pid)
-	case err := <-done:
-		if err != nil {
-			m.logger.Debug("SDRTrunk", "Process exited during shutdown", "pid", pid, "error", err)
-		} else {
-			m.logger.Info("SDRTrunk process shutdown cleanly", "pid", pid)
+		m.logger.Warn(m.label("SDRTrunk did not shutdown gracefully, forcing termination"), "pid", pid)
+		if err := kill(m.cmd); err != nil {
+			m.logger.Error(m.label("Failed to kill SDRTrunk process"), "pid", pid, "error", err)
 		}
+		<-m.exited // Wait for the process to actually exit
+		m.logger.Info(m.label("SDRTrunk process terminated forcefully"), "pid", pid)
+	case <-m.exited:
+		m.logger.Info(m.label("SDRTrunk process shutdown cleanly"), "pid", pid)
 	}
 
 	m.running = false
+	m.state = StateStopped
 	m.cmd = nil
-	m.logger.Success("SDRTrunk process stopped successfully", "pid", pid)
+	m.logger.Success(m.label("SDRTrunk process stopped successfully"), "pid", pid)
 	return nil
 }
 
@@ -172,12 +236,18 @@ func (m *Manager) GetStatus() ProcessStatus {
 	defer m.mutex.RUnlock()
 
 	status := ProcessStatus{
-		Running: m.running,
+		Name:      m.name,
+		State:     m.state,
+		Running:   m.running,
+		Restarts:  m.restarts,
+		LastExit:  m.lastExit,
+		Hung:      m.hung,
+		OutputDir: m.config.AudioOutputDir,
 	}
 
-	if m.cmd != nil && m.cmd.Process != nil {
+	if m.running && m.cmd != nil && m.cmd.Process != nil {
 		status.PID = m.cmd.Process.Pid
-		// Note: StartTime would need to be tracked separately
+		status.StartTime = m.startTime
 	}
 
 	return status
@@ -185,7 +255,7 @@ func (m *Manager) GetStatus() ProcessStatus {
 
 // Restart stops and starts the SDRTrunk process
 func (m *Manager) Restart() error {
-	m.logger.Info("Restarting SDRTrunk process...")
+	m.logger.Info(m.label("Restarting SDRTrunk process..."))
 
 	if err := m.Stop(); err != nil {
 		return fmt.Errorf("failed to stop SDRTrunk: %w", err)
@@ -194,13 +264,21 @@ func (m *Manager) Restart() error {
 	// Wait a moment before restarting
 	time.Sleep(2 * time.Second)
 
-	if err := m.Start(m.ctx); err != nil {
+	if err := m.Start(m.parent); err != nil {
 		return fmt.Errorf("failed to start SDRTrunk: %w", err)
 	}
 
 	return nil
 }
 
+// resetRestarts clears the restart count after a deliberate restart
+func (m *Manager) resetRestarts() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.restarts = 0
+	m.lastExit = ""
+}
+
 // validateSDRTrunkPath validates that the SDRTrunk executable exists and is accessible
 func (m *Manager) validateSDRTrunkPath() error {
 	// Check if the path exists
@@ -217,10 +295,7 @@ func (m *Manager) validateSDRTrunkPath() error {
 
 	// Check if it's an executable file (common names for SDRTrunk binary)
 	if strings.Contains(fileName, "sdr-trunk") || strings.Contains(fileName, "sdrtrunk") {
-		// Check if file has execute permissions (Unix-like systems)
-		if fileInfo.Mode()&0111 != 0 {
-			isExecutable = true
-		}
+		isExecutable = isExecutableFile(fileInfo)
 	}
 
 	if !isJarFile && !isExecutable {
@@ -269,6 +344,13 @@ func (m *Manager) buildCommand() (*exec.Cmd, error) {
 		cmd = exec.CommandContext(m.ctx, m.config.Path, args...)
 	}
 
+	// Ask SDRTrunk to shut down cleanly when the context is cancelled; Stop
+	// kills it if it has not exited in time
+	cmd.Cancel = func() error {
+		return interrupt(cmd)
+	}
+	configureProcess(cmd)
+
 	// Set working directory if specified
 	if m.config.WorkingDir != "" {
 		cmd.Dir = m.config.WorkingDir
@@ -283,65 +365,148 @@ func (m *Manager) buildCommand() (*exec.Cmd, error) {
 	// Redirect stdout and stderr to our logger
 	// Use configured log level for stdout, ERROR for stderr
 	stdoutLevel := strings.ToUpper(m.config.LogLevel)
-	cmd.Stdout = &logWriter{logger: m.logger, level: stdoutLevel}
-	cmd.Stderr = &logWriter{logger: m.logger, level: "ERROR"}
+	cmd.Stdout = &logWriter{logger: m.logger, level: stdoutLevel, label: m.label, component: m.component(), onOutput: m.touchOutput}
+	cmd.Stderr = &logWriter{logger: m.logger, level: "ERROR", label: m.label, component: m.component(), onOutput: m.touchOutput}
 
 	return cmd, nil
 }
 
-// monitor runs in a separate goroutine to monitor the SDRTrunk process
-func (m *Manager) monitor() {
-	defer func() {
-		m.mutex.Lock()
-		m.running = false
-		m.mutex.Unlock()
-		m.logger.Debug("SDRTrunk", "Monitor goroutine exiting")
-	}()
-
-	m.logger.Debug("SDRTrunk", "Starting process monitor")
+// monitor waits for one run of the SDRTrunk process to exit and applies the
+// restart policy if the exit was unexpected
+func (m *Manager) monitor(ctx context.Context, cmd *exec.Cmd, exited chan struct{}) {
+	m.logger.Debug(m.component(), "Starting process monitor")
 
 	// Wait for the process to exit
-	err := m.cmd.Wait()
+	err := cmd.Wait()
+	close(exited)
 
 	// Get exit information
 	exitCode := -1
-	if m.cmd.ProcessState != nil {
-		exitCode = m.cmd.ProcessState.ExitCode()
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
 	}
 
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	defer m.logger.Debug(m.component(), "Monitor goroutine exiting")
+
 	// Check if this was an expected shutdown
 	select {
-	case <-m.ctx.Done():
-		// Expected shutdown
-		m.logger.Info("SDRTrunk process stopped gracefully",
+	case <-ctx.Done():
+		m.running = false
+		m.state = StateStopped
+		m.hung = ""
+		m.logger.Info(m.label("SDRTrunk process stopped gracefully"),
 			"exit_code", exitCode,
 			"reason", "context_cancelled")
 		return
 	default:
-		// Unexpected exit
-		if err != nil {
-			m.logger.Error("SDRTrunk process exited unexpectedly",
-				"error", err,
-				"exit_code", exitCode)
-		} else {
-			m.logger.Warn("SDRTrunk process exited without error",
-				"exit_code", exitCode)
+	}
+
+	// Unexpected exit
+	if m.hung != "" && m.config.HealthCheck.Action == "restart" {
+		m.logger.Error(m.label("Hung SDRTrunk process was killed"), "reason", m.hung)
+		m.lastExit = "hung (" + m.hung + ")"
+	} else if err != nil {
+		m.logger.Error(m.label("SDRTrunk process exited unexpectedly"),
+			"error", err,
+			"exit_code", exitCode)
+		m.lastExit = fmt.Sprintf("exit code %d", exitCode)
+	} else {
+		m.logger.Warn(m.label("SDRTrunk process exited without error"),
+			"exit_code", exitCode)
+		m.lastExit = "exited cleanly"
+	}
+
+	m.running = false
+	m.hung = ""
+	m.cmd = nil
+	m.cancel() // Stop this run's status reporter and health checker
+	m.scheduleRestart(err != nil)
+}
+
+// scheduleRestart restarts the process after a delay if the restart policy
+// allows it. The caller must hold the mutex.
+func (m *Manager) scheduleRestart(failed bool) {
+	switch m.config.RestartPolicy {
+	case "never":
+		m.state = StateStopped
+		m.report(fmt.Sprintf("SDRTrunk stopped (%s); restart policy is never", m.lastExit))
+		return
+	case "on-failure":
+		if !failed {
+			m.state = StateStopped
+			m.report("SDRTrunk exited cleanly and will not be restarted")
+			return
 		}
 	}
 
-	// TODO: Implement restart logic or notification to main application
-	// For now, just log the unexpected exit
+	// A process that ran for a while before exiting starts counting again
+	if time.Since(m.startTime) > stableRunTime {
+		m.restarts = 0
+	}
+	if m.config.MaxRestarts >= 0 && m.restarts >= m.config.MaxRestarts {
+		m.state = StateFailed
+		m.logger.Error(m.label("SDRTrunk keeps exiting, giving up"), "restarts", m.restarts)
+		m.report(fmt.Sprintf("SDRTrunk %s and was restarted %d times; giving up", m.lastExit, m.restarts))
+		return
+	}
+	m.restarts++
+
+	delay := time.Duration(m.config.RestartDelay) * time.Second << (m.restarts - 1)
+	if delay > maxRestartDelay || delay <= 0 {
+		delay = maxRestartDelay
+	}
+	m.state = StateRestarting
+	m.logger.Warn(m.label("Restarting SDRTrunk"), "attempt", m.restarts, "in", delay)
+	m.report(fmt.Sprintf("SDRTrunk %s; restarting in %s (attempt %d)", m.lastExit, delay, m.restarts))
+
+	parent := m.parent
+	go func() {
+		select {
+		case <-parent.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		// Stop abandons a pending restart
+		m.mutex.RLock()
+		abandoned := m.state != StateRestarting
+		m.mutex.RUnlock()
+		if abandoned {
+			return
+		}
+
+		if err := m.Start(parent); err != nil {
+			m.logger.Error(m.label("Failed to restart SDRTrunk"), "error", err)
+			m.mutex.Lock()
+			m.lastExit = "failed to start"
+			m.scheduleRestart(true)
+			m.mutex.Unlock()
+			return
+		}
+		m.report("SDRTrunk restarted")
+	}()
+}
+
+// report passes a state change to the notify callback. It runs asynchronously
+// so the callback can read process statuses while the caller holds the mutex.
+func (m *Manager) report(message string) {
+	if m.notify == nil {
+		return
+	}
+	go m.notify(m, message)
 }
 
 // statusReporter periodically reports SDRTrunk status
-func (m *Manager) statusReporter() {
+func (m *Manager) statusReporter(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Minute) // Report status every 5 minutes
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-m.ctx.Done():
-			m.logger.Debug("SDRTrunk", "Status reporter stopping")
+		case <-ctx.Done():
+			m.logger.Debug(m.component(), "Status reporter stopping")
 			return
 		case <-ticker.C:
 			m.logStatus()
@@ -360,7 +525,7 @@ func (m *Manager) logStatus() {
 
 	if m.cmd != nil && m.cmd.Process != nil {
 		pid := m.cmd.Process.Pid
-		m.logger.Info("SDRTrunk status check",
+		m.logger.Info(m.label("SDRTrunk status check"),
 			"pid", pid,
 			"running", m.running,
 			"output_dir", m.config.AudioOutputDir)
@@ -371,6 +536,9 @@ func (m *Manager) logStatus() {
 type logWriter struct {
 	logger         *logger.Logger
 	level          string
+	label          func(string) string // Prefixes messages with the process name
+	component      string
+	onOutput       func() // Called for every write, as a sign of life
 	startupSummary *startupSummary
 }
 
@@ -386,6 +554,10 @@ type startupSummary struct {
 }
 
 func (lw *logWriter) Write(p []byte) (n int, err error) {
+	if lw.onOutput != nil {
+		lw.onOutput()
+	}
+
 	message := strings.TrimSpace(string(p))
 	if message == "" {
 		return len(p), nil
@@ -645,15 +817,15 @@ func (lw *logWriter) formatMessage(message string) string {
 func (lw *logWriter) logMessage(message string) {
 	switch lw.level {
 	case "ERROR":
-		lw.logger.Error(message)
+		lw.logger.Error(lw.label(message))
 	case "WARN":
-		lw.logger.Warn(message)
+		lw.logger.Warn(lw.label(message))
 	case "INFO":
-		lw.logger.Info(message)
+		lw.logger.Info(lw.label(message))
 	case "DEBUG":
-		lw.logger.Debug("SDRTrunk", message)
+		lw.logger.Debug(lw.component, message)
 	default:
-		lw.logger.Info(message)
+		lw.logger.Info(lw.label(message))
 	}
 }
 
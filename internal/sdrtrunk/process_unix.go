@@ -0,0 +1,28 @@
+//go:build !windows
+
+package sdrtrunk
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// configureProcess prepares the command before it starts. Nothing is needed
+// outside Windows.
+func configureProcess(cmd *exec.Cmd) {}
+
+// interrupt asks the process to shut down cleanly
+func interrupt(cmd *exec.Cmd) error {
+	return cmd.Process.Signal(syscall.SIGTERM)
+}
+
+// kill forcefully terminates the process
+func kill(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// isExecutableFile reports whether a file can be run directly
+func isExecutableFile(info os.FileInfo) bool {
+	return info.Mode()&0111 != 0
+}
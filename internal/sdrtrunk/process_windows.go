@@ -0,0 +1,49 @@
+//go:build windows
+
+package sdrtrunk
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// configureProcess starts SDRTrunk in its own process group, so a console
+// Ctrl+Break can be sent to it without reaching Meiko
+func configureProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.CREATE_NEW_PROCESS_GROUP}
+}
+
+// interrupt asks the process to shut down cleanly. Windows has no SIGTERM:
+// Java treats Ctrl+Break like one when the process shares Meiko's console,
+// and otherwise, as under a service, taskkill asks its windows to close.
+func interrupt(cmd *exec.Cmd) error {
+	if err := windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(cmd.Process.Pid)); err == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+// kill forcefully terminates the process and its children, which includes
+// the Java process started by the SDRTrunk launcher script
+func kill(cmd *exec.Cmd) error {
+	if err := exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+// isExecutableFile reports whether a file can be run directly, which Windows
+// decides by extension
+func isExecutableFile(info os.FileInfo) bool {
+	switch strings.ToLower(filepath.Ext(info.Name())) {
+	case ".exe", ".bat", ".cmd":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package sdrtrunk
+
+import (
+	"context"
+	"fmt"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+)
+
+// Event reports a change in one supervised process along with the state of all of them
+type Event struct {
+	Process  ProcessStatus
+	Message  string
+	Running  int // Processes running and not hung after the change
+	Total    int
+	Statuses []ProcessStatus
+}
+
+// Supervisor runs and restarts one SDRTrunk process per configured system
+type Supervisor struct {
+	managers []*Manager
+	logger   *logger.Logger
+	onEvent  func(Event)
+}
+
+// NewSupervisor creates a manager for each system. Processes are named after
+// their system ID, so a single unnamed system logs without a prefix.
+func NewSupervisor(systems []config.SystemConfig, logger *logger.Logger) *Supervisor {
+	s := &Supervisor{logger: logger}
+	for _, system := range systems {
+		manager := New(system.ID, system.SDRTrunk, logger)
+		manager.notify = s.report
+		s.managers = append(s.managers, manager)
+	}
+	return s
+}
+
+// OnEvent sets a callback for process exits and restarts. It must be set before Start.
+func (s *Supervisor) OnEvent(fn func(Event)) {
+	s.onEvent = fn
+}
+
+// Start launches every process, stopping those already started if one fails
+func (s *Supervisor) Start(ctx context.Context) error {
+	for i, manager := range s.managers {
+		if err := manager.Start(ctx); err != nil {
+			for _, started := range s.managers[:i] {
+				started.Stop()
+			}
+			if manager.Name() != "" {
+				return fmt.Errorf("system %s: %w", manager.Name(), err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops every process
+func (s *Supervisor) Stop() {
+	for _, manager := range s.managers {
+		if err := manager.Stop(); err != nil {
+			s.logger.Error("Failed to stop SDRTrunk", "system", manager.Name(), "error", err)
+		}
+	}
+}
+
+// Restart restarts every process, including any that gave up restarting, and
+// clears their restart counts
+func (s *Supervisor) Restart() {
+	for _, manager := range s.managers {
+		if err := manager.Restart(); err != nil {
+			s.logger.Error("Failed to restart SDRTrunk", "system", manager.Name(), "error", err)
+			continue
+		}
+		manager.resetRestarts()
+	}
+}
+
+// Managers returns the managed processes in configuration order
+func (s *Supervisor) Managers() []*Manager {
+	return s.managers
+}
+
+// Statuses returns the status of every process
+func (s *Supervisor) Statuses() []ProcessStatus {
+	statuses := make([]ProcessStatus, len(s.managers))
+	for i, manager := range s.managers {
+		statuses[i] = manager.GetStatus()
+	}
+	return statuses
+}
+
+// RunningCount returns how many processes are running and how many are supervised
+func (s *Supervisor) RunningCount() (int, int) {
+	running := 0
+	for _, manager := range s.managers {
+		if manager.IsRunning() {
+			running++
+		}
+	}
+	return running, len(s.managers)
+}
+
+// report passes a process state change to the event callback
+func (s *Supervisor) report(manager *Manager, message string) {
+	if s.onEvent == nil {
+		return
+	}
+
+	event := Event{
+		Process:  manager.GetStatus(),
+		Message:  message,
+		Statuses: s.Statuses(),
+		Total:    len(s.managers),
+	}
+	for _, status := range event.Statuses {
+		if status.Running && status.Hung == "" {
+			event.Running++
+		}
+	}
+	s.onEvent(event)
+}
@@ -0,0 +1,142 @@
+package severity
+
+import (
+	"regexp"
+	"strings"
+
+	"Meiko/internal/config"
+	"Meiko/internal/talkgroups"
+)
+
+// Level is a call severity score from 1 (routine) to 5 (critical)
+type Level int
+
+const (
+	None     Level = 0 // Not scored (no transcription)
+	Routine  Level = 1
+	Minor    Level = 2
+	Moderate Level = 3
+	Serious  Level = 4
+	Critical Level = 5
+)
+
+// String returns the lowercase name of the level
+func (l Level) String() string {
+	switch l {
+	case Routine:
+		return "routine"
+	case Minor:
+		return "minor"
+	case Moderate:
+		return "moderate"
+	case Serious:
+		return "serious"
+	case Critical:
+		return "critical"
+	default:
+		return "none"
+	}
+}
+
+// ParseLevel converts a level name into a Level
+func ParseLevel(name string) (Level, bool) {
+	for level := Routine; level <= Critical; level++ {
+		if strings.EqualFold(strings.TrimSpace(name), level.String()) {
+			return level, true
+		}
+	}
+	return None, false
+}
+
+// defaultKeywords are the built-in phrases that raise a call's severity
+var defaultKeywords = map[Level][]string{
+	Critical: {
+		"shots fired", "officer down", "mayday", "structure fire", "working fire",
+		"cardiac arrest", "cpr in progress", "not breathing", "active shooter",
+		"shooting", "stabbing", "fatality", "code 3", "firefighter down",
+	},
+	Serious: {
+		"pursuit", "weapon", "gun", "knife", "overdose", "unconscious",
+		"unresponsive", "entrapment", "rollover", "fully involved", "armed",
+		"assault", "robbery", "hazmat", "gas leak", "smoke showing",
+	},
+	Moderate: {
+		"accident", "collision", "mva", "injury", "injuries", "fire alarm",
+		"fight", "domestic", "burglary", "breathing difficulty", "chest pain",
+		"fall", "smoke", "suspicious",
+	},
+	Minor: {
+		"traffic stop", "disturbance", "alarm", "welfare check", "theft",
+		"lift assist", "noise complaint", "hazard", "disabled vehicle",
+	},
+}
+
+// Scorer assigns severity levels to transcribed calls
+type Scorer struct {
+	patterns map[Level][]*regexp.Regexp
+}
+
+// NewScorer creates a scorer from the built-in keywords plus any configured extras
+func NewScorer(cfg config.SeverityConfig) *Scorer {
+	scorer := &Scorer{patterns: make(map[Level][]*regexp.Regexp)}
+
+	for level, keywords := range defaultKeywords {
+		scorer.add(level, keywords)
+	}
+	for name, keywords := range cfg.Keywords {
+		if level, ok := ParseLevel(name); ok {
+			scorer.add(level, keywords)
+		}
+	}
+
+	return scorer
+}
+
+// add compiles keywords as case-insensitive whole-phrase matchers
+func (s *Scorer) add(level Level, keywords []string) {
+	for _, keyword := range keywords {
+		words := strings.Fields(keyword)
+		if len(words) == 0 {
+			continue
+		}
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		pattern := regexp.MustCompile(`(?i)\b` + strings.Join(words, `\s+`) + `\b`)
+		s.patterns[level] = append(s.patterns[level], pattern)
+	}
+}
+
+// Score returns the highest severity level matched by the transcription.
+// Emergency-service calls with no keyword match are scored Minor rather than Routine.
+func (s *Scorer) Score(transcription string, serviceType talkgroups.ServiceType) Level {
+	if strings.TrimSpace(transcription) == "" {
+		return None
+	}
+
+	for level := Critical; level > Routine; level-- {
+		for _, pattern := range s.patterns[level] {
+			if pattern.MatchString(transcription) {
+				return level
+			}
+		}
+	}
+
+	if serviceType == talkgroups.ServiceEmergency {
+		return Minor
+	}
+	return Routine
+}
+
+// Hits returns the level of every keyword found in the transcription
+func (s *Scorer) Hits(transcription string) []Level {
+	var hits []Level
+	for level := Critical; level > Routine; level-- {
+		for _, pattern := range s.patterns[level] {
+			if pattern.MatchString(transcription) {
+				hits = append(hits, level)
+			}
+		}
+	}
+	return hits
+}
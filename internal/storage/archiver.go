@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+)
+
+// archiveBatchSize is how many calls are uploaded or cleaned up per query
+const archiveBatchSize = 100
+
+// Backend stores call audio away from the local disk
+type Backend interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string, expiry time.Duration) (string, error) // Temporary link for playback
+}
+
+// AudioArchiver uploads the audio of processed calls to object storage and
+// removes local copies according to the delete_local policy
+type AudioArchiver struct {
+	config  config.AudioArchiveConfig
+	backend Backend
+	db      *database.Database
+	logger  *logger.Logger
+}
+
+// NewAudioArchiver creates an archiver for the configured bucket
+func NewAudioArchiver(cfg config.AudioArchiveConfig, db *database.Database, logger *logger.Logger) *AudioArchiver {
+	return &AudioArchiver{
+		config:  cfg,
+		backend: NewS3(cfg.S3),
+		db:      db,
+		logger:  logger,
+	}
+}
+
+// Start archives now and then every interval
+func (a *AudioArchiver) Start(ctx context.Context) {
+	go func() {
+		a.run(ctx)
+
+		ticker := time.NewTicker(time.Duration(a.config.Interval) * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.run(ctx)
+			}
+		}
+	}()
+}
+
+// URL returns a presigned link to an archived recording
+func (a *AudioArchiver) URL(key string) (string, error) {
+	return a.backend.URL(key, time.Duration(a.config.URLExpiry)*time.Minute)
+}
+
+// run uploads every call waiting to be archived, then deletes local copies
+// that have been kept long enough
+func (a *AudioArchiver) run(ctx context.Context) {
+	uploaded := 0
+	for ctx.Err() == nil {
+		calls, err := a.db.GetCallsToArchive(archiveBatchSize)
+		if err != nil {
+			a.logger.Error("Failed to list calls to archive", "error", err)
+			return
+		}
+
+		for _, call := range calls {
+			archived, err := a.upload(ctx, call)
+			if err != nil {
+				// Leave the rest for the next pass rather than failing every call
+				a.logger.Warn("Failed to archive call audio", "call_id", call.ID, "error", err)
+				return
+			}
+			if archived {
+				uploaded++
+			}
+		}
+		if len(calls) < archiveBatchSize {
+			break
+		}
+	}
+	if uploaded > 0 {
+		a.logger.Info("Archived call audio", "calls", uploaded, "bucket", a.config.S3.Bucket)
+	}
+
+	if a.config.DeleteLocal == "after_days" {
+		a.expireLocal(ctx)
+	}
+}
+
+// upload copies a call's recording to the bucket and records its key,
+// reporting whether there was a recording to upload
+func (a *AudioArchiver) upload(ctx context.Context, call *database.CallRecord) (bool, error) {
+	data, err := os.ReadFile(call.Filepath)
+	if os.IsNotExist(err) {
+		// Cleaned up before it could be archived; there is nothing to upload
+		a.logger.Warn("Recording missing, skipping archive", "call_id", call.ID, "file", call.Filepath)
+		return false, a.db.MarkLocalDeleted(call.ID)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	// Keys are grouped by day and prefixed with the call ID, as agents at
+	// different sites can produce the same filename
+	key := path.Join(a.config.S3.Prefix, call.Timestamp.Format("2006/01/02"), fmt.Sprintf("%d_%s", call.ID, call.Filename))
+	contentType := mime.TypeByExtension(filepath.Ext(call.Filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if err := a.backend.Put(ctx, key, data, contentType); err != nil {
+		return false, err
+	}
+	if err := a.db.SetStorageKey(call.ID, key); err != nil {
+		// Without the key the call is uploaded again next pass, so drop this copy
+		a.backend.Delete(ctx, key)
+		return false, err
+	}
+	a.logger.Debug("Storage", "Archived call audio", "call_id", call.ID, "key", key)
+
+	if a.config.DeleteLocal == "after_upload" {
+		a.removeLocal(call)
+	}
+	return true, nil
+}
+
+// expireLocal deletes local copies of archived calls older than keep_local_days
+func (a *AudioArchiver) expireLocal(ctx context.Context) {
+	cutoff := time.Now().AddDate(0, 0, -a.config.KeepLocalDays)
+	removed := 0
+	for ctx.Err() == nil {
+		calls, err := a.db.GetArchivedLocalCalls(cutoff, archiveBatchSize)
+		if err != nil {
+			a.logger.Error("Failed to list archived recordings", "error", err)
+			return
+		}
+
+		for _, call := range calls {
+			if !a.removeLocal(call) {
+				return
+			}
+			removed++
+		}
+		if len(calls) < archiveBatchSize {
+			break
+		}
+	}
+	if removed > 0 {
+		a.logger.Info("Deleted local copies of archived recordings", "calls", removed, "older_than_days", a.config.KeepLocalDays)
+	}
+}
+
+// removeLocal deletes a call's local recording, reporting whether it succeeded
+func (a *AudioArchiver) removeLocal(call *database.CallRecord) bool {
+	if err := os.Remove(call.Filepath); err != nil && !os.IsNotExist(err) {
+		a.logger.Error("Failed to delete archived recording", "file", call.Filepath, "error", err)
+		return false
+	}
+	if err := a.db.MarkLocalDeleted(call.ID); err != nil {
+		a.logger.Error("Failed to record deleted recording", "call_id", call.ID, "error", err)
+		return false
+	}
+	return true
+}
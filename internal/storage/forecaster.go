@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+)
+
+const (
+	// minForecastSpan is how much history is needed before projecting growth
+	minForecastSpan = time.Hour
+
+	// alertRepeat is how often a "disk full soon" alert repeats while it holds
+	alertRepeat = 24 * time.Hour
+
+	// minCleanupAge protects recordings that may not have been processed yet
+	minCleanupAge = time.Hour
+
+	gigabyte = 1 << 30
+)
+
+// sample is the recording storage measured at one time
+type sample struct {
+	at    time.Time
+	bytes int64
+}
+
+// Forecast describes recording storage and when the disk is projected to fill
+type Forecast struct {
+	RecordingBytes int64     `json:"recording_bytes"`
+	FreeBytes      uint64    `json:"free_bytes"`
+	GrowthPerDay   int64     `json:"growth_per_day"`  // Bytes per day over the forecast window
+	DaysUntilFull  *float64  `json:"days_until_full"` // Nil while recordings are not growing
+	SampledAt      time.Time `json:"sampled_at,omitempty"`
+}
+
+// Forecaster tracks how fast the recording directories grow, warns before the
+// disk fills and, if enabled, deletes the oldest recordings when free space
+// drops below the floor
+type Forecaster struct {
+	config      config.StorageConfig
+	directories []string
+	patterns    []string
+	logger      *logger.Logger
+	onAlert     func(message string)
+
+	mutex     sync.RWMutex
+	samples   []sample
+	forecast  Forecast
+	lastAlert time.Time
+}
+
+// New creates a forecaster for the given recording directories. Only files
+// matching the patterns are counted or deleted.
+func New(cfg config.StorageConfig, directories, patterns []string, logger *logger.Logger) *Forecaster {
+	return &Forecaster{
+		config:      cfg,
+		directories: directories,
+		patterns:    patterns,
+		logger:      logger,
+	}
+}
+
+// OnAlert sets a callback for low-space warnings and cleanups. It must be set before Start.
+func (f *Forecaster) OnAlert(fn func(message string)) {
+	f.onAlert = fn
+}
+
+// Start samples storage now and then every check interval
+func (f *Forecaster) Start(ctx context.Context) {
+	go func() {
+		f.check()
+
+		ticker := time.NewTicker(time.Duration(f.config.CheckInterval) * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.check()
+			}
+		}
+	}()
+}
+
+// Forecast returns the latest forecast
+func (f *Forecaster) Forecast() Forecast {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.forecast
+}
+
+// check takes a sample, updates the forecast, alerts if the disk will fill
+// soon and cleans up if free space is below the floor
+func (f *Forecaster) check() {
+	files, total := f.recordings()
+	free, err := f.freeSpace()
+	if err != nil {
+		f.logger.Error("Failed to read free disk space", "error", err)
+		return
+	}
+
+	now := time.Now()
+	f.mutex.Lock()
+	f.samples = append(f.samples, sample{at: now, bytes: total})
+	window := time.Duration(f.config.ForecastWindow) * time.Hour
+	for len(f.samples) > 2 && now.Sub(f.samples[0].at) > window {
+		f.samples = f.samples[1:]
+	}
+	f.forecast = project(f.samples, free)
+	forecast := f.forecast
+	alert := forecast.DaysUntilFull != nil && *forecast.DaysUntilFull < f.config.AlertDays &&
+		now.Sub(f.lastAlert) >= alertRepeat
+	if alert {
+		f.lastAlert = now
+	}
+	f.mutex.Unlock()
+
+	f.logger.Debug("Storage", "Recording storage sampled", "bytes", total, "free", free, "growth_per_day", forecast.GrowthPerDay)
+
+	if alert {
+		message := fmt.Sprintf("Disk full in ~%.1f days: %s free, recordings growing %s per day",
+			*forecast.DaysUntilFull, formatBytes(int64(free)), formatBytes(forecast.GrowthPerDay))
+		f.logger.Warn(message)
+		f.alert(message)
+	}
+
+	floor := uint64(f.config.MinFreeGB * gigabyte)
+	if f.config.EmergencyCleanup && floor > 0 && free < floor {
+		f.cleanup(files, free, floor)
+	}
+}
+
+// project estimates daily growth from the oldest and newest samples in the
+// window and how long the free space lasts at that rate
+func project(samples []sample, free uint64) Forecast {
+	last := samples[len(samples)-1]
+	forecast := Forecast{RecordingBytes: last.bytes, FreeBytes: free, SampledAt: last.at}
+
+	first := samples[0]
+	span := last.at.Sub(first.at)
+	if span < minForecastSpan {
+		return forecast
+	}
+
+	forecast.GrowthPerDay = int64(float64(last.bytes-first.bytes) / span.Hours() * 24)
+	if forecast.GrowthPerDay > 0 {
+		days := float64(free) / float64(forecast.GrowthPerDay)
+		forecast.DaysUntilFull = &days
+	}
+	return forecast
+}
+
+// cleanup deletes the oldest recordings until free space is back above the
+// floor plus the headroom. Recordings newer than minCleanupAge are kept, as
+// they may not have been processed yet.
+func (f *Forecaster) cleanup(files []recording, free, floor uint64) {
+	target := floor + uint64(f.config.CleanupHeadroomGB*gigabyte)
+	f.logger.Warn("Free disk space below floor, deleting oldest recordings",
+		"free", formatBytes(int64(free)), "floor", formatBytes(int64(floor)))
+
+	cutoff := time.Now().Add(-minCleanupAge)
+	deleted := 0
+	var freed int64
+	for _, file := range files {
+		if free+uint64(freed) >= target || file.modTime.After(cutoff) {
+			break
+		}
+		if err := os.Remove(file.path); err != nil {
+			f.logger.Error("Failed to delete recording", "file", file.path, "error", err)
+			continue
+		}
+		deleted++
+		freed += file.size
+	}
+
+	message := fmt.Sprintf("Free disk space fell below %s: deleted the %d oldest recordings, freeing %s",
+		formatBytes(int64(floor)), deleted, formatBytes(freed))
+	if free+uint64(freed) < target {
+		message += "; free space is still below the target"
+	}
+	f.logger.Warn(message)
+	f.alert(message)
+
+	// The deletions would read as negative growth, so measure afresh
+	f.mutex.Lock()
+	f.samples = nil
+	f.mutex.Unlock()
+}
+
+// recording is one recording file on disk
+type recording struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// recordings lists the recordings in every directory, oldest first, with
+// their total size
+func (f *Forecaster) recordings() ([]recording, int64) {
+	var files []recording
+	var total int64
+	for _, dir := range f.directories {
+		filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !f.matches(entry.Name()) {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			files = append(files, recording{path: path, size: info.Size(), modTime: info.ModTime()})
+			total += info.Size()
+			return nil
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	return files, total
+}
+
+// matches reports whether a filename matches the recording patterns
+func (f *Forecaster) matches(name string) bool {
+	return matchesAny(f.patterns, name)
+}
+
+// freeSpace returns the least free space among the filesystems holding the
+// recording directories
+func (f *Forecaster) freeSpace() (uint64, error) {
+	var free uint64
+	for i, dir := range f.directories {
+		usage, err := disk.Usage(dir)
+		if err != nil {
+			return 0, err
+		}
+		if i == 0 || usage.Free < free {
+			free = usage.Free
+		}
+	}
+	return free, nil
+}
+
+// alert passes a message to the alert callback
+func (f *Forecaster) alert(message string) {
+	if f.onAlert != nil {
+		f.onAlert(message)
+	}
+}
+
+// formatBytes renders a byte count in the largest fitting unit
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
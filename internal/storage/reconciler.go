@@ -0,0 +1,348 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+	"Meiko/internal/logger"
+	"Meiko/internal/metadata"
+)
+
+// reconcileBatchSize is how many calls are checked or cleaned up per query
+const reconcileBatchSize = 500
+
+// ErrReconcileRunning is returned while a check or cleanup is in progress
+var ErrReconcileRunning = errors.New("a reconciliation is already running")
+
+// Reconciliation is the outcome of comparing the database with the
+// recordings on disk
+type Reconciliation struct {
+	CheckedAt     time.Time `json:"checked_at,omitempty"`
+	Calls         int       `json:"calls"`          // Calls checked
+	MissingAudio  int       `json:"missing_audio"`  // Calls whose recording was deleted outside Meiko
+	Orphaned      int       `json:"orphaned"`       // Recordings no call refers to
+	OrphanedBytes int64     `json:"orphaned_bytes"` // Size of the orphaned recordings
+}
+
+// CleanupResult is what a cleanup removed
+type CleanupResult struct {
+	DeletedCalls      int   `json:"deleted_calls"`
+	DeletedRecordings int   `json:"deleted_recordings"`
+	FreedBytes        int64 `json:"freed_bytes"`
+}
+
+// Reconciler finds calls whose recordings were deleted outside Meiko, such
+// as by hand or by another tool's retention, and recordings in the watched
+// directories that no call refers to, such as calls skipped as too short.
+// Missing calls are marked in the database; both can be cleaned up on request.
+type Reconciler struct {
+	config      config.ReconcileConfig
+	db          *database.Database
+	directories []string
+	patterns    []string
+	logger      *logger.Logger
+
+	running sync.Mutex // Held by a check or cleanup
+
+	mutex   sync.RWMutex
+	report  Reconciliation
+	orphans []string // Orphaned recordings found by the last check
+}
+
+// NewReconciler creates a reconciler for the given recording directories.
+// Only files matching the patterns are considered recordings.
+func NewReconciler(cfg config.ReconcileConfig, db *database.Database, directories, patterns []string, logger *logger.Logger) *Reconciler {
+	return &Reconciler{
+		config:      cfg,
+		db:          db,
+		directories: directories,
+		patterns:    patterns,
+		logger:      logger,
+	}
+}
+
+// Start checks now and then every interval
+func (r *Reconciler) Start(ctx context.Context) {
+	go func() {
+		r.check(ctx)
+
+		ticker := time.NewTicker(time.Duration(r.config.Interval) * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.check(ctx)
+			}
+		}
+	}()
+}
+
+// Report returns the outcome of the last check
+func (r *Reconciler) Report() Reconciliation {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.report
+}
+
+// check runs a scheduled reconciliation, logging its outcome
+func (r *Reconciler) check(ctx context.Context) {
+	report, err := r.Run(ctx)
+	if errors.Is(err, ErrReconcileRunning) {
+		return
+	}
+	if err != nil {
+		r.logger.Error("Failed to reconcile recordings", "error", err)
+		return
+	}
+	if report.MissingAudio > 0 || report.Orphaned > 0 {
+		r.logger.Warn("Recordings and database disagree",
+			"missing_audio", report.MissingAudio,
+			"orphaned", report.Orphaned,
+			"orphaned_size", formatBytes(report.OrphanedBytes))
+	}
+}
+
+// Run compares every call with its recording, marking the calls whose
+// recording is gone and unmarking any that reappeared, then lists the
+// recordings no call refers to
+func (r *Reconciler) Run(ctx context.Context) (Reconciliation, error) {
+	if !r.running.TryLock() {
+		return Reconciliation{}, ErrReconcileRunning
+	}
+	defer r.running.Unlock()
+
+	report := Reconciliation{}
+	known := make(map[string]bool)
+	afterID := 0
+	for ctx.Err() == nil {
+		files, err := r.db.GetCallFiles(afterID, reconcileBatchSize)
+		if err != nil {
+			return report, err
+		}
+
+		var missing, found []int
+		for _, file := range files {
+			afterID = file.ID
+			known[filepath.Clean(file.Filepath)] = true
+			if file.LocalDeleted {
+				continue
+			}
+			report.Calls++
+
+			_, err := os.Stat(file.Filepath)
+			switch {
+			case err == nil:
+				if file.AudioMissing {
+					found = append(found, file.ID)
+				}
+			case !errors.Is(err, fs.ErrNotExist):
+				// Unreadable rather than gone, such as an unmounted share
+				r.logger.Debug("Storage", "Failed to check recording", "file", file.Filepath, "error", err)
+				if file.AudioMissing {
+					report.MissingAudio++
+				}
+			case file.StorageKey != "":
+				// The audio is still in object storage, which plays it from now on
+				if err := r.db.MarkLocalDeleted(file.ID); err != nil {
+					return report, err
+				}
+			default:
+				report.MissingAudio++
+				if !file.AudioMissing {
+					missing = append(missing, file.ID)
+				}
+			}
+		}
+
+		if err := r.db.SetAudioMissing(missing, true); err != nil {
+			return report, err
+		}
+		if err := r.db.SetAudioMissing(found, false); err != nil {
+			return report, err
+		}
+		if len(files) < reconcileBatchSize {
+			break
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	orphans := r.findOrphans(known)
+	report.Orphaned = len(orphans)
+	for _, orphan := range orphans {
+		report.OrphanedBytes += orphan.size
+	}
+	report.CheckedAt = time.Now()
+
+	paths := make([]string, len(orphans))
+	for i, orphan := range orphans {
+		paths[i] = orphan.path
+	}
+	r.mutex.Lock()
+	r.report = report
+	r.orphans = paths
+	r.mutex.Unlock()
+
+	r.logger.Debug("Storage", "Reconciled recordings",
+		"calls", report.Calls,
+		"missing_audio", report.MissingAudio,
+		"orphaned", report.Orphaned)
+	return report, nil
+}
+
+// findOrphans lists the recordings in the directories that no call refers
+// to, oldest first. Recordings newer than minCleanupAge are left out, as they
+// may not have been processed yet.
+func (r *Reconciler) findOrphans(known map[string]bool) []recording {
+	cutoff := time.Now().Add(-minCleanupAge)
+	var orphans []recording
+	for _, dir := range r.directories {
+		filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !matchesAny(r.patterns, entry.Name()) || known[filepath.Clean(path)] {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil || info.ModTime().After(cutoff) {
+				return nil
+			}
+			orphans = append(orphans, recording{path: path, size: info.Size(), modTime: info.ModTime()})
+			return nil
+		})
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].modTime.Before(orphans[j].modTime) })
+	return orphans
+}
+
+// Cleanup deletes the calls marked as missing their recording, and the
+// orphaned recordings found by the last check along with their metadata
+// files. Each is checked again first, so nothing that changed since is lost.
+func (r *Reconciler) Cleanup(ctx context.Context, calls, recordings bool) (CleanupResult, error) {
+	if !r.running.TryLock() {
+		return CleanupResult{}, ErrReconcileRunning
+	}
+	defer r.running.Unlock()
+
+	var result CleanupResult
+	if calls {
+		if err := r.deleteMissing(ctx, &result); err != nil {
+			return result, err
+		}
+	}
+	if recordings {
+		if err := r.deleteOrphans(ctx, &result); err != nil {
+			return result, err
+		}
+	}
+
+	r.mutex.Lock()
+	r.report.MissingAudio = max(r.report.MissingAudio-result.DeletedCalls, 0)
+	if recordings {
+		r.report.Orphaned = len(r.orphans)
+		r.report.OrphanedBytes = max(r.report.OrphanedBytes-result.FreedBytes, 0)
+	}
+	r.mutex.Unlock()
+
+	r.logger.Info("Cleaned up recordings and database",
+		"deleted_calls", result.DeletedCalls,
+		"deleted_recordings", result.DeletedRecordings,
+		"freed", formatBytes(result.FreedBytes))
+	return result, nil
+}
+
+// deleteMissing deletes the calls still missing their recording. Calls
+// whose recording reappeared are unmarked instead.
+func (r *Reconciler) deleteMissing(ctx context.Context, result *CleanupResult) error {
+	for ctx.Err() == nil {
+		calls, err := r.db.GetMissingAudioCalls(reconcileBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(calls) == 0 {
+			return nil
+		}
+
+		var missing []*database.CallRecord
+		var found []int
+		for _, call := range calls {
+			if _, err := os.Stat(call.Filepath); err == nil {
+				found = append(found, call.ID)
+			} else if errors.Is(err, fs.ErrNotExist) {
+				missing = append(missing, call)
+			} else {
+				return fmt.Errorf("failed to check recording of call %d: %w", call.ID, err)
+			}
+		}
+		if err := r.db.SetAudioMissing(found, false); err != nil {
+			return err
+		}
+		if err := r.db.DeleteCalls(missing); err != nil {
+			return err
+		}
+		result.DeletedCalls += len(missing)
+	}
+	return ctx.Err()
+}
+
+// deleteOrphans deletes the orphaned recordings found by the last check that
+// no call has come to refer to since
+func (r *Reconciler) deleteOrphans(ctx context.Context, result *CleanupResult) error {
+	r.mutex.RLock()
+	orphans := r.orphans
+	r.mutex.RUnlock()
+
+	var remaining []string
+	for i, path := range orphans {
+		if ctx.Err() != nil {
+			remaining = append(remaining, orphans[i:]...)
+			break
+		}
+		exists, err := r.db.FileExists(path)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			r.logger.Warn("Failed to delete orphaned recording", "file", path, "error", err)
+			remaining = append(remaining, path)
+			continue
+		}
+		if sidecar := metadata.SidecarPath(path); sidecar != "" {
+			os.Remove(sidecar)
+		}
+		result.DeletedRecordings++
+		result.FreedBytes += info.Size()
+	}
+
+	r.mutex.Lock()
+	r.orphans = remaining
+	r.mutex.Unlock()
+	return ctx.Err()
+}
+
+// matchesAny reports whether a filename matches any of the patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
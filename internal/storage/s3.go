@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"Meiko/internal/config"
+)
+
+// unsignedPayload stands in for the payload hash of presigned requests
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3 stores objects in S3 or S3-compatible storage such as Backblaze B2 using Signature V4
+type S3 struct {
+	config  config.S3Config
+	client  *http.Client
+	uploads *http.Client // For PutFile, whose uploads can take longer than client's timeout
+}
+
+// NewS3 creates a client for the configured bucket
+func NewS3(cfg config.S3Config) *S3 {
+	// A large file can take many minutes to send, so file uploads are only
+	// limited by their context and by how long the server takes to answer
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = 60 * time.Second
+
+	return &S3{
+		config:  cfg,
+		client:  &http.Client{Timeout: 60 * time.Second},
+		uploads: &http.Client{Transport: transport},
+	}
+}
+
+// objectURL returns the scheme, host and path for an object key
+func (s *S3) objectURL(key string) (string, string, string) {
+	path := "/" + awsURIEscape(strings.TrimLeft(key, "/"), true)
+
+	// S3-compatible endpoints use path-style addressing
+	if s.config.Endpoint != "" {
+		endpoint := strings.TrimRight(s.config.Endpoint, "/")
+		scheme := "https"
+		if strings.HasPrefix(endpoint, "http://") {
+			scheme = "http"
+		}
+		host := strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+		return scheme, host, "/" + s.config.Bucket + path
+	}
+
+	return "https", fmt.Sprintf("%s.s3.%s.amazonaws.com", s.config.Bucket, s.config.Region), path
+}
+
+// Put uploads a single object
+func (s *S3) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	headers := map[string]string{"Content-Type": contentType}
+	return s.do(ctx, "PUT", key, body, headers, http.StatusOK)
+}
+
+// PutFile uploads a file as a single object, streaming it from disk rather
+// than reading it into memory
+func (s *S3) PutFile(ctx context.Context, key, file, contentType string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// The payload hash is signed, so the file is read once to hash it and
+	// again as it is sent
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", file, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Content-Type": contentType}
+	return s.send(ctx, s.uploads, "PUT", key, f, size, hex.EncodeToString(hash.Sum(nil)), headers, http.StatusOK)
+}
+
+// Delete removes an object. Deleting an object that doesn't exist succeeds.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, "DELETE", key, nil, nil, http.StatusNoContent, http.StatusOK)
+}
+
+// URL returns a presigned GET URL for an object that is valid for expiry
+func (s *S3) URL(key string, expiry time.Duration) (string, error) {
+	return s.presign(key, expiry, time.Now().UTC()), nil
+}
+
+// presign builds a presigned GET URL signed at the given time
+func (s *S3) presign(key string, expiry time.Duration, now time.Time) string {
+	scheme, host, path := s.objectURL(key)
+
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + s.config.Region + "/s3/aws4_request"
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    s.config.AccessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expiry.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]string, len(names))
+	for i, name := range names {
+		params[i] = name + "=" + awsURIEscape(query[name], false)
+	}
+	canonicalQuery := strings.Join(params, "&")
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		path,
+		canonicalQuery,
+		"host:" + host,
+		"",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	signature := s.sign(now, scope, canonicalRequest)
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s", scheme, host, path, canonicalQuery, signature)
+}
+
+// do sends a signed request for an object and checks the response status
+func (s *S3) do(ctx context.Context, method, key string, body []byte, headers map[string]string, expected ...int) error {
+	return s.send(ctx, s.client, method, key, bytes.NewReader(body), int64(len(body)), sha256Hex(body), headers, expected...)
+}
+
+// send signs a request whose body has the given size and SHA-256, sends it
+// with client and checks the response status
+func (s *S3) send(ctx context.Context, client *http.Client, method, key string, body io.Reader, size int64, payloadHash string, headers map[string]string, expected ...int) error {
+	scheme, host, path := s.objectURL(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, scheme+"://"+host+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = size
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		"",
+		"host:" + host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := now.Format("20060102") + "/" + s.config.Region + "/s3/aws4_request"
+	signature := s.sign(now, scope, canonicalRequest)
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("S3 %s failed with status %d: %s", method, resp.StatusCode, string(respBody))
+}
+
+// sign returns the Signature V4 signature of a canonical request
+func (s *S3) sign(now time.Time, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretKey), now.Format("20060102"))
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 computes an HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsURIEscape escapes a value as required by Signature V4. Slashes are kept
+// in object keys and escaped in query values.
+func awsURIEscape(value string, keepSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package talkgroups
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultClassificationOrder is the order the built-in keyword lists are
+// tried in, so a talkgroup matching several always gets the same type
+var defaultClassificationOrder = []ServiceType{
+	ServicePolice,
+	ServiceFire,
+	ServiceEMS,
+	ServiceEmergency,
+	ServicePublicWorks,
+	ServiceEducation,
+	ServiceEvents,
+	ServiceAirport,
+}
+
+// classificationRule assigns talkgroups whose group and name match to a
+// service type
+type classificationRule struct {
+	serviceType ServiceType
+	keywords    []string // Upper case
+	pattern     *regexp.Regexp
+	priority    int
+	source      string // "config" or "default"
+}
+
+// initClassificationRules builds the rules from the configuration, followed
+// by the built-in keyword lists unless they are replaced
+func (s *Service) initClassificationRules() {
+	var rules []*classificationRule
+
+	for i, ruleCfg := range s.config.Talkgroups.Classification.Rules {
+		serviceType := ServiceType(strings.ToUpper(strings.TrimSpace(ruleCfg.ServiceType)))
+		if _, exists := s.departmentTypes[serviceType]; !exists {
+			s.logger.Warn("Skipping classification rule with unknown service type", "rule", i, "service_type", ruleCfg.ServiceType)
+			continue
+		}
+
+		rule := &classificationRule{
+			serviceType: serviceType,
+			keywords:    upperKeywords(ruleCfg.Keywords),
+			priority:    ruleCfg.Priority,
+			source:      "config",
+		}
+		if ruleCfg.Regex != "" {
+			pattern, err := regexp.Compile(ruleCfg.Regex)
+			if err != nil {
+				s.logger.Warn("Skipping classification rule with invalid regex", "rule", i, "regex", ruleCfg.Regex, "error", err)
+				continue
+			}
+			rule.pattern = pattern
+		}
+		rules = append(rules, rule)
+	}
+
+	if !s.config.Talkgroups.Classification.ReplaceDefaults {
+		for _, serviceType := range defaultClassificationOrder {
+			rules = append(rules, &classificationRule{
+				serviceType: serviceType,
+				keywords:    upperKeywords(s.departmentTypes[serviceType].Keywords),
+				source:      "default",
+			})
+		}
+	}
+
+	// Configured rules come before built-in ones of the same priority
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].priority > rules[j].priority
+	})
+	s.rules = rules
+}
+
+// upperKeywords returns the non-empty keywords in upper case
+func upperKeywords(keywords []string) []string {
+	upper := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if strings.TrimSpace(keyword) != "" {
+			upper = append(upper, strings.ToUpper(keyword))
+		}
+	}
+	return upper
+}
+
+// match returns what in the group and name matched the rule, if anything
+func (r *classificationRule) match(combined string) (string, bool) {
+	if r.pattern != nil && r.pattern.MatchString(combined) {
+		return r.pattern.String(), true
+	}
+
+	upper := strings.ToUpper(combined)
+	for _, keyword := range r.keywords {
+		if strings.Contains(upper, keyword) {
+			return keyword, true
+		}
+	}
+	return "", false
+}
+
+// classifyDepartment determines the service type based on group and name
+func (s *Service) classifyDepartment(group, name string) ServiceType {
+	combined := fmt.Sprintf("%s %s", group, name)
+
+	for _, rule := range s.rules {
+		if matched, ok := rule.match(combined); ok {
+			s.logger.Debug("Talkgroups", "Talkgroup classified",
+				"group", group,
+				"name", name,
+				"matched", matched,
+				"rule", rule.source,
+				"service_type", string(rule.serviceType))
+			return rule.serviceType
+		}
+	}
+
+	// Log unclassified talkgroups to help with troubleshooting
+	s.logger.Debug("Talkgroups", "Talkgroup unclassified",
+		"group", group,
+		"name", name,
+		"defaulting_to", "OTHER")
+
+	return ServiceOther
+}
@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
-	"strings"
+	"sync"
 	"time"
 
 	"Meiko/internal/config"
+	"Meiko/internal/database"
 	"Meiko/internal/logger"
 )
 
@@ -76,10 +77,16 @@ type ID struct {
 // Service handles talkgroup information and categorization
 type Service struct {
 	talkgroups      map[string]*TalkgroupInfo
+	overrides       map[string]*database.TalkgroupOverride // Supersede the playlist
+	overridden      map[string]*TalkgroupInfo              // Playlist information with overrides applied
 	departmentTypes map[ServiceType]*DepartmentType
+	rules           []*classificationRule // Tried in order
 	config          *config.Config
+	db              *database.Database
 	logger          *logger.Logger
 	lastLoaded      time.Time
+	reloadHooks     []func() // Called after the playlist changes on disk
+	mu              sync.RWMutex
 }
 
 // New creates a new talkgroup service
@@ -93,11 +100,14 @@ func New(config *config.Config, logger *logger.Logger) *Service {
 
 	service := &Service{
 		talkgroups: make(map[string]*TalkgroupInfo),
+		overrides:  make(map[string]*database.TalkgroupOverride),
+		overridden: make(map[string]*TalkgroupInfo),
 		config:     config,
 		logger:     logger,
 	}
 
 	service.initDepartmentTypes()
+	service.initClassificationRules()
 
 	// Load talkgroups if playlist path is configured
 	if config.Talkgroups.PlaylistPath != "" {
@@ -200,7 +210,7 @@ func (s *Service) LoadPlaylist(filePath string) error {
 		return fmt.Errorf("failed to parse playlist XML: %w", err)
 	}
 
-	count := 0
+	loaded := make(map[string]*TalkgroupInfo)
 	for _, alias := range playlist.Aliases {
 		// Find talkgroup ID
 		var talkgroupID string
@@ -230,17 +240,20 @@ func (s *Service) LoadPlaylist(filePath string) error {
 				ColorHex:    deptInfo.Color,
 			}
 
-			s.talkgroups[talkgroupID] = talkgroupInfo
-			count++
+			loaded[talkgroupID] = talkgroupInfo
 		}
 	}
 
+	s.mu.Lock()
+	s.talkgroups = loaded
 	s.lastLoaded = time.Now()
-	s.logger.Success("Loaded talkgroup playlist", "count", count, "file", filepath.Base(filePath))
+	s.applyOverrides()
+	s.mu.Unlock()
+	s.logger.Success("Loaded talkgroup playlist", "count", len(loaded), "file", filepath.Base(filePath))
 
 	// Log department breakdown
 	serviceCounts := make(map[ServiceType]int)
-	for _, tg := range s.talkgroups {
+	for _, tg := range loaded {
 		serviceCounts[tg.ServiceType]++
 	}
 
@@ -258,42 +271,98 @@ func (s *Service) LoadPlaylist(filePath string) error {
 	return nil
 }
 
-// classifyDepartment determines the service type based on group and name
-func (s *Service) classifyDepartment(group, name string) ServiceType {
-	combined := strings.ToUpper(fmt.Sprintf("%s %s", group, name))
-
-	// Check each department type for keyword matches
-	for serviceType, dept := range s.departmentTypes {
-		for _, keyword := range dept.Keywords {
-			if strings.Contains(combined, strings.ToUpper(keyword)) {
-				s.logger.Debug("Talkgroup classified",
-					"group", group,
-					"name", name,
-					"combined", combined,
-					"matched_keyword", keyword,
-					"service_type", string(serviceType))
-				return serviceType
-			}
+// SetDatabase loads talkgroup overrides from the database, which take
+// precedence over the playlist from then on
+func (s *Service) SetDatabase(db *database.Database) error {
+	s.db = db
+	return s.ReloadOverrides()
+}
+
+// ReloadOverrides reloads talkgroup overrides from the database
+func (s *Service) ReloadOverrides() error {
+	if s.db == nil {
+		return nil
+	}
+
+	list, err := s.db.GetTalkgroupOverrides()
+	if err != nil {
+		return fmt.Errorf("failed to load talkgroup overrides: %w", err)
+	}
+
+	overrides := make(map[string]*database.TalkgroupOverride, len(list))
+	for _, override := range list {
+		overrides[override.TalkgroupID] = override
+	}
+
+	s.mu.Lock()
+	s.overrides = overrides
+	s.applyOverrides()
+	s.mu.Unlock()
+
+	s.logger.Debug("Talkgroups", "Loaded talkgroup overrides", "count", len(overrides))
+	return nil
+}
+
+// applyOverrides merges every override with the playlist. The caller must
+// hold the write lock.
+func (s *Service) applyOverrides() {
+	s.overridden = make(map[string]*TalkgroupInfo, len(s.overrides))
+	for id, override := range s.overrides {
+		info, exists := s.talkgroups[id]
+		if !exists {
+			info = defaultTalkgroupInfo(id)
 		}
+		s.overridden[id] = s.applyOverride(info, override)
 	}
+}
 
-	// Log unclassified talkgroups to help with troubleshooting
-	s.logger.Debug("Talkgroup unclassified",
-		"group", group,
-		"name", name,
-		"combined", combined,
-		"defaulting_to", "OTHER")
+// lookup returns a talkgroup's information with any override applied,
+// reporting whether the talkgroup is known from the playlist or an override
+func (s *Service) lookup(talkgroupID string) (*TalkgroupInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	return ServiceOther
+	if info, exists := s.overridden[talkgroupID]; exists {
+		return info, true
+	}
+	info, exists := s.talkgroups[talkgroupID]
+	return info, exists
 }
 
-// GetTalkgroupInfo returns enhanced talkgroup information
-func (s *Service) GetTalkgroupInfo(talkgroupID string) *TalkgroupInfo {
-	if info, exists := s.talkgroups[talkgroupID]; exists {
-		return info
+// applyOverride returns a copy of info with the override's fields in place.
+// A talkgroup renamed without an explicit service type is classified again.
+func (s *Service) applyOverride(info *TalkgroupInfo, override *database.TalkgroupOverride) *TalkgroupInfo {
+	merged := *info
+	if override.Name != "" {
+		merged.Name = override.Name
+	}
+	if override.Group != "" {
+		merged.Group = override.Group
 	}
 
-	// Return default info for unknown talkgroups
+	switch {
+	case override.ServiceType != "":
+		merged.ServiceType = ServiceType(override.ServiceType)
+	case override.Name != "" || override.Group != "":
+		merged.ServiceType = s.classifyDepartment(merged.Group, merged.Name)
+	}
+
+	dept, exists := s.departmentTypes[merged.ServiceType]
+	if !exists {
+		merged.ServiceType = ServiceOther
+		dept = s.departmentTypes[ServiceOther]
+	}
+	merged.Emoji = dept.Emoji
+	merged.ColorHex = dept.Color
+	if override.Color != "" {
+		merged.ColorHex = override.Color
+	}
+
+	return &merged
+}
+
+// defaultTalkgroupInfo returns the information shown for unknown talkgroups
+func defaultTalkgroupInfo(talkgroupID string) *TalkgroupInfo {
 	return &TalkgroupInfo{
 		ID:          talkgroupID,
 		Name:        fmt.Sprintf("TG %s", talkgroupID),
@@ -305,12 +374,42 @@ func (s *Service) GetTalkgroupInfo(talkgroupID string) *TalkgroupInfo {
 	}
 }
 
+// GetTalkgroupInfo returns enhanced talkgroup information
+func (s *Service) GetTalkgroupInfo(talkgroupID string) *TalkgroupInfo {
+	if info, exists := s.lookup(talkgroupID); exists {
+		return info
+	}
+
+	// Return default info for unknown talkgroups
+	return defaultTalkgroupInfo(talkgroupID)
+}
+
+// GetTalkgroupFilter returns the filter settings for a talkgroup, with the
+// mute and priority flags of any override taking precedence over the config
+func (s *Service) GetTalkgroupFilter(talkgroupID string) config.TalkgroupFilterConfig {
+	filter := s.config.GetTalkgroupFilter(talkgroupID)
+
+	s.mu.RLock()
+	override := s.overrides[talkgroupID]
+	s.mu.RUnlock()
+
+	if override != nil {
+		if override.Mute != nil {
+			filter.Mute = *override.Mute
+		}
+		if override.Priority != nil {
+			filter.Priority = *override.Priority
+		}
+	}
+	return filter
+}
+
 // GetTalkgroupInfoWithContext returns enhanced talkgroup information with intelligent classification
 // based on call context (who is calling whom). If the caller is unknown but the called party
 // is a known department, it will assume the caller is from the same department type.
 func (s *Service) GetTalkgroupInfoWithContext(talkgroupID, contextTalkgroupID string) *TalkgroupInfo {
 	// If we have direct information about this talkgroup, use it
-	if info, exists := s.talkgroups[talkgroupID]; exists {
+	if info, exists := s.lookup(talkgroupID); exists {
 		return info
 	}
 
@@ -357,7 +456,7 @@ func (s *Service) GetTalkgroupInfoWithContext(talkgroupID, contextTalkgroupID st
 func (s *Service) GetDepartmentInfo(talkgroupID string) *DepartmentType {
 	info := s.GetTalkgroupInfo(talkgroupID)
 	if dept, exists := s.departmentTypes[info.ServiceType]; exists {
-		return dept
+		return withColor(dept, info.ColorHex)
 	}
 
 	// Return default for unknown departments
@@ -373,7 +472,7 @@ func (s *Service) GetDepartmentInfo(talkgroupID string) *DepartmentType {
 func (s *Service) GetDepartmentInfoWithContext(talkgroupID, contextTalkgroupID string) *DepartmentType {
 	info := s.GetTalkgroupInfoWithContext(talkgroupID, contextTalkgroupID)
 	if dept, exists := s.departmentTypes[info.ServiceType]; exists {
-		return dept
+		return withColor(dept, info.ColorHex)
 	}
 
 	// Return default for unknown departments
@@ -385,6 +484,17 @@ func (s *Service) GetDepartmentInfoWithContext(talkgroupID, contextTalkgroupID s
 	}
 }
 
+// withColor returns the department with a talkgroup's overridden color, or
+// the department itself when the color is the department's own
+func withColor(dept *DepartmentType, colorHex string) *DepartmentType {
+	if colorHex == "" || colorHex == dept.Color {
+		return dept
+	}
+	recolored := *dept
+	recolored.Color = colorHex
+	return &recolored
+}
+
 // FormatTalkgroupDisplay creates a formatted display string for talkgroups
 func (s *Service) FormatTalkgroupDisplay(talkgroupID string) string {
 	info := s.GetTalkgroupInfo(talkgroupID)
@@ -409,9 +519,30 @@ func (s *Service) FormatTalkgroupDisplayWithContext(talkgroupID, contextTalkgrou
 	return fmt.Sprintf("TG %s", talkgroupID)
 }
 
-// GetAllTalkgroups returns all loaded talkgroups
+// FormatTalkgroupGroup returns the department group stored with calls on a
+// talkgroup, prefixed with the department emoji when its service is known
+func (s *Service) FormatTalkgroupGroup(talkgroupID string) string {
+	info := s.GetTalkgroupInfo(talkgroupID)
+	if deptInfo := s.GetDepartmentInfo(talkgroupID); deptInfo.Type != ServiceOther {
+		return fmt.Sprintf("%s %s", deptInfo.Emoji, info.Group)
+	}
+	return info.Group
+}
+
+// GetAllTalkgroups returns all loaded talkgroups with overrides applied,
+// including talkgroups that are only known from an override
 func (s *Service) GetAllTalkgroups() map[string]*TalkgroupInfo {
-	return s.talkgroups
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make(map[string]*TalkgroupInfo, len(s.talkgroups)+len(s.overridden))
+	for id, info := range s.talkgroups {
+		all[id] = info
+	}
+	for id, info := range s.overridden {
+		all[id] = info
+	}
+	return all
 }
 
 // GetServiceTypes returns all available service types
@@ -421,13 +552,18 @@ func (s *Service) GetServiceTypes() map[ServiceType]*DepartmentType {
 
 // GetStats returns talkgroup service statistics
 func (s *Service) GetStats() map[string]interface{} {
+	all := s.GetAllTalkgroups()
+
+	s.mu.RLock()
 	stats := make(map[string]interface{})
-	stats["total_talkgroups"] = len(s.talkgroups)
+	stats["total_talkgroups"] = len(all)
+	stats["overrides"] = len(s.overrides)
 	stats["last_loaded"] = s.lastLoaded
+	s.mu.RUnlock()
 
 	// Count by service type
 	serviceTypeCounts := make(map[string]int)
-	for _, tg := range s.talkgroups {
+	for _, tg := range all {
 		serviceTypeCounts[string(tg.ServiceType)]++
 	}
 	stats["by_service_type"] = serviceTypeCounts
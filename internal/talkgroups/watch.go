@@ -0,0 +1,93 @@
+package talkgroups
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// playlistSettle is how long the playlist must go unchanged before it is
+// reloaded, so a save made of several writes is read once and in full
+const playlistSettle = time.Second
+
+// OnReload registers a function to call after the playlist is reloaded
+func (s *Service) OnReload(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reloadHooks = append(s.reloadHooks, fn)
+}
+
+// WatchPlaylist reloads the playlist whenever the file changes until the
+// context is cancelled. The directory is watched rather than the file, as
+// editors and SDRTrunk may replace the file instead of writing to it.
+func (s *Service) WatchPlaylist(ctx context.Context) error {
+	path := s.config.Talkgroups.PlaylistPath
+	if path == "" {
+		return fmt.Errorf("no playlist path configured")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create playlist watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch playlist directory: %w", err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		settle := time.NewTimer(playlistSettle)
+		settle.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(path) {
+					continue
+				}
+				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+					settle.Reset(playlistSettle)
+				}
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				s.logger.Error("Playlist watcher error", "error", err)
+
+			case <-settle.C:
+				s.reloadChangedPlaylist()
+			}
+		}
+	}()
+
+	s.logger.Info("Watching talkgroup playlist for changes", "path", path)
+	return nil
+}
+
+// reloadChangedPlaylist reloads the playlist after a change, keeping the
+// previous talkgroups if the new file can't be read
+func (s *Service) reloadChangedPlaylist() {
+	if err := s.ReloadPlaylist(); err != nil {
+		s.logger.Warn("Failed to reload changed talkgroup playlist", "error", err)
+		return
+	}
+
+	s.mu.RLock()
+	hooks := s.reloadHooks
+	s.mu.RUnlock()
+	for _, hook := range hooks {
+		hook()
+	}
+}
@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/notify"
+)
+
+// apiURL is the Telegram Bot API
+const apiURL = "https://api.telegram.org"
+
+// Client posts notifications to a Telegram chat with a bot
+type Client struct {
+	config config.TelegramConfig
+	client *http.Client
+}
+
+// New creates a Telegram client
+func New(cfg config.TelegramConfig) *Client {
+	return &Client{config: cfg, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name identifies the transport in logs
+func (c *Client) Name() string {
+	return "telegram"
+}
+
+// Send posts a message to the chat
+func (c *Client) Send(ctx context.Context, message notify.Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  c.config.ChatID,
+		"text":                     message.HTML,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", apiURL, c.config.BotToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The URL holds the bot token, so it is left out of the error
+		return fmt.Errorf("Telegram request failed")
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("Telegram API error (status %d)", resp.StatusCode)
+	}
+	if !response.OK {
+		err := fmt.Errorf("Telegram API error (status %d): %s", resp.StatusCode, response.Description)
+		if response.Parameters.RetryAfter > 0 {
+			return &notify.RetryError{After: time.Duration(response.Parameters.RetryAfter) * time.Second, Err: err}
+		}
+		return err
+	}
+	return nil
+}
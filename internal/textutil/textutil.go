@@ -0,0 +1,17 @@
+// Package textutil holds small text helpers shared by the notification senders
+package textutil
+
+import "unicode/utf8"
+
+// Truncate shortens text to at most limit bytes, marking the cut with "...".
+// The cut is moved back to the start of a character, so none is split.
+func Truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := max(limit-3, 0)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "..."
+}
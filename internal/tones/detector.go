@@ -0,0 +1,170 @@
+package tones
+
+import (
+	"context"
+	"math"
+
+	"Meiko/internal/audio"
+	"Meiko/internal/config"
+)
+
+const (
+	sampleRate = 8000 // Decode rate; paging tones are all below 3 kHz
+	frameSize  = 1024 // ~128ms analysis window
+	hopSize    = 512  // 50% overlap
+
+	minToneHz = 250.0
+	maxToneHz = 3000.0
+
+	// purity is the share of in-band energy the peak must hold for a frame to count as a tone
+	purity = 0.45
+	// frequencyDrift is the relative change allowed within a single tone
+	frequencyDrift = 0.015
+	// maxGap is the longest silence allowed between the A and B tones
+	maxGap = 0.35
+)
+
+// Sequence is a detected two-tone (Quick Call II) paging sequence
+type Sequence struct {
+	ToneA   float64 `json:"tone_a"`            // First tone in Hz
+	ToneB   float64 `json:"tone_b"`            // Second tone in Hz
+	Start   float64 `json:"start"`             // Offset into the call in seconds
+	Station string  `json:"station,omitempty"` // Matched station name, if configured
+}
+
+// Options control sequence detection
+type Options struct {
+	MinToneA float64 // Minimum A tone length in seconds
+	MinToneB float64 // Minimum B tone length in seconds
+	FFmpeg   string  // ffmpeg executable that decodes files
+}
+
+// tone is a run of frames holding a steady frequency
+type tone struct {
+	frequency float64 // Running mean of the frame frequencies
+	frames    int
+	start     float64
+	end       float64
+}
+
+// DetectFile decodes an audio file with ffmpeg and returns any paging sequences
+func DetectFile(ctx context.Context, filePath string, opts Options) ([]Sequence, error) {
+	samples, err := audio.Decode(ctx, opts.FFmpeg, filePath, sampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return Detect(samples, opts), nil
+}
+
+// Detect finds two-tone paging sequences in mono samples at sampleRate
+func Detect(samples []float64, opts Options) []Sequence {
+	tones := findTones(samples)
+
+	var sequences []Sequence
+	for i := 0; i+1 < len(tones); i++ {
+		a, b := tones[i], tones[i+1]
+		if a.end-a.start < opts.MinToneA || b.end-b.start < opts.MinToneB {
+			continue
+		}
+		if b.start-a.end > maxGap || sameFrequency(a.frequency, b.frequency) {
+			continue
+		}
+
+		sequences = append(sequences, Sequence{
+			ToneA: math.Round(a.frequency*10) / 10,
+			ToneB: math.Round(b.frequency*10) / 10,
+			Start: math.Round(a.start*100) / 100,
+		})
+		i++ // The B tone can't also start the next sequence
+	}
+
+	return sequences
+}
+
+// findTones groups consecutive pure-tone frames into steady tones
+func findTones(samples []float64) []tone {
+	window := audio.HannWindow(frameSize)
+	frameDuration := float64(hopSize) / sampleRate
+
+	var tones []tone
+	var current *tone
+
+	for offset := 0; offset+frameSize <= len(samples); offset += hopSize {
+		frequency, ok := dominantFrequency(samples[offset:offset+frameSize], window)
+		at := float64(offset) / sampleRate
+
+		if ok && current != nil && sameFrequency(current.frequency, frequency) {
+			current.frames++
+			current.frequency += (frequency - current.frequency) / float64(current.frames)
+			current.end = at + frameDuration
+			continue
+		}
+
+		if current != nil {
+			tones = append(tones, *current)
+			current = nil
+		}
+		if ok {
+			current = &tone{frequency: frequency, frames: 1, start: at, end: at + frameDuration}
+		}
+	}
+	if current != nil {
+		tones = append(tones, *current)
+	}
+
+	return tones
+}
+
+// dominantFrequency returns the peak frequency of a frame if it is a pure tone
+func dominantFrequency(frame, window []float64) (float64, bool) {
+	power := audio.PowerSpectrum(frame, window)
+
+	binWidth := float64(sampleRate) / float64(len(frame))
+	lowBin := int(minToneHz / binWidth)
+	highBin := int(maxToneHz / binWidth)
+
+	var total float64
+	peak := lowBin
+	for bin := lowBin; bin <= highBin; bin++ {
+		total += power[bin]
+		if power[bin] > power[peak] {
+			peak = bin
+		}
+	}
+
+	// Ignore silence and speech: a tone concentrates its energy in the peak and its neighbours
+	if total < 1e-3 || peak == lowBin || peak == highBin {
+		return 0, false
+	}
+	if (power[peak-1]+power[peak]+power[peak+1])/total < purity {
+		return 0, false
+	}
+
+	// Parabolic interpolation between neighbouring bins for sub-bin accuracy
+	alpha, beta, gamma := math.Log(power[peak-1]), math.Log(power[peak]), math.Log(power[peak+1])
+	shift := 0.0
+	if denominator := alpha - 2*beta + gamma; denominator != 0 {
+		shift = 0.5 * (alpha - gamma) / denominator
+	}
+
+	return (float64(peak) + shift) * binWidth, true
+}
+
+// Match returns the first station whose tones are within tolerance (in percent)
+// of the detected sequence
+func Match(sequence Sequence, stations []config.ToneStationConfig, tolerance float64) (config.ToneStationConfig, bool) {
+	within := func(detected, expected float64) bool {
+		return math.Abs(detected-expected) <= expected*tolerance/100
+	}
+	for _, station := range stations {
+		if within(sequence.ToneA, station.ToneA) && within(sequence.ToneB, station.ToneB) {
+			return station, true
+		}
+	}
+	return config.ToneStationConfig{}, false
+}
+
+// sameFrequency reports whether two frequencies are within the allowed drift
+func sameFrequency(a, b float64) bool {
+	return math.Abs(a-b) <= frequencyDrift*math.Max(a, b)
+}
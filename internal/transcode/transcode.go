@@ -0,0 +1,31 @@
+package transcode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Extension is the file extension given to transcoded recordings
+const Extension = ".opus"
+
+// ToOpus converts an audio file to mono Ogg Opus at the given bitrate in kbps
+// with the ffmpeg executable. The output format is set explicitly, so dst may
+// use any extension.
+func ToOpus(ctx context.Context, ffmpeg, src, dst string, bitrate int) error {
+	cmd := exec.CommandContext(ctx, ffmpeg, "-v", "error", "-y", "-i", src,
+		"-vn", "-ac", "1", "-c:a", "libopus", "-b:a", fmt.Sprintf("%dk", bitrate),
+		"-application", "voip", "-f", "opus", dst)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("ffmpeg transcode failed: %w: %s", err, message)
+		}
+		return fmt.Errorf("ffmpeg transcode failed: %w", err)
+	}
+	return nil
+}
@@ -8,9 +8,11 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,14 +28,37 @@ type TranscriptionResult struct {
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
 	FilePath  string    `json:"file_path"`
+	Segments  []Segment `json:"segments,omitempty"`
 	Error     error     `json:"error,omitempty"`
 }
 
+// Segment is a speaker-tagged span of a transcription
+type Segment struct {
+	Speaker string  `json:"speaker"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+}
+
+// transcript is what a transcription backend returns for one call
+type transcript struct {
+	text     string
+	language string // Detected or configured language, if the backend reports it
+	segments []Segment
+}
+
+// Options carries per-call hints for the transcription backend
+type Options struct {
+	TalkgroupID string   // Talkgroup the call was recorded on
+	Vocabulary  []string // Glossary terms to bias recognition towards
+}
+
 // Service handles audio transcription using local or remote methods
 type Service struct {
 	config config.TranscriptionConfig
 	logger *logger.Logger
 	client *http.Client
+	worker *whisperWorker // Long-lived whisper process in local daemon mode
 }
 
 // New creates a new transcription service
@@ -51,10 +76,31 @@ func New(config config.TranscriptionConfig, logger *logger.Logger) (*Service, er
 		return nil, fmt.Errorf("transcription service validation failed: %w", err)
 	}
 
+	if config.Mode == "local" && config.Local.Daemon {
+		service.worker = newWhisperWorker(config.Local, config.Diarization, logger)
+	}
+
 	logger.Info("Transcription service initialized", "mode", config.Mode)
 	return service, nil
 }
 
+// Start loads the model in the whisper worker ahead of the first call and
+// restarts the worker if it stops responding, until ctx is cancelled. It does
+// nothing unless local daemon mode is enabled.
+func (s *Service) Start(ctx context.Context) {
+	if s.worker == nil {
+		return
+	}
+	go s.worker.run(ctx, time.Duration(s.config.Local.HealthCheckInterval)*time.Second)
+}
+
+// Stop ends the whisper worker once any call in progress has finished
+func (s *Service) Stop() {
+	if s.worker != nil {
+		s.worker.stop()
+	}
+}
+
 // validate checks the transcription configuration
 func (s *Service) validate() error {
 	switch s.config.Mode {
@@ -91,8 +137,38 @@ func (s *Service) validateRemote() error {
 	return nil
 }
 
+// Check reports whether the transcription backend can be used: the whisper
+// script and worker in local mode, or that the endpoint answers in remote
+// mode. Any HTTP response counts, as endpoints reject requests without audio.
+func (s *Service) Check(ctx context.Context) error {
+	if s.config.Mode == "local" {
+		if err := s.validateLocal(); err != nil {
+			return err
+		}
+		if s.worker != nil {
+			return s.worker.healthy()
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.config.Remote.Endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
 // TranscribeFile transcribes an audio file and returns the result
-func (s *Service) TranscribeFile(ctx context.Context, filePath string) (*TranscriptionResult, error) {
+func (s *Service) TranscribeFile(ctx context.Context, filePath string, opts *Options) (*TranscriptionResult, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
 	startTime := time.Now()
 
 	// Validate file exists and is accessible
@@ -105,15 +181,21 @@ func (s *Service) TranscribeFile(ctx context.Context, filePath string) (*Transcr
 		StartTime: startTime,
 	}
 
+	var output transcript
 	var err error
 	switch s.config.Mode {
 	case "local":
-		result.Text, err = s.transcribeLocal(ctx, filePath)
+		output, err = s.transcribeLocal(ctx, filePath, opts)
 	case "remote":
-		result.Text, err = s.transcribeRemote(ctx, filePath)
+		if s.config.Remote.Provider == "deepgram" {
+			output, err = s.transcribeDeepgram(ctx, filePath, opts)
+		} else {
+			output, err = s.transcribeRemote(ctx, filePath, opts)
+		}
 	default:
 		err = fmt.Errorf("unknown transcription mode: %s", s.config.Mode)
 	}
+	result.Text, result.Language, result.Segments = output.text, normalizeLanguage(output.language), output.segments
 
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime).Seconds()
@@ -124,21 +206,39 @@ func (s *Service) TranscribeFile(ctx context.Context, filePath string) (*Transcr
 		return result, err
 	}
 
+	result.Segments = normalizeSegments(result.Segments)
+
 	s.logger.Success("Transcription completed",
 		"file", filepath.Base(filePath),
 		"duration", fmt.Sprintf("%.2fs", result.Duration),
-		"length", len(result.Text))
+		"length", len(result.Text),
+		"language", result.Language,
+		"speakers", SpeakerCount(result.Segments))
 
 	return result, nil
 }
 
 // transcribeLocal performs local transcription using faster-whisper
-func (s *Service) transcribeLocal(ctx context.Context, filePath string) (string, error) {
+func (s *Service) transcribeLocal(ctx context.Context, filePath string, opts *Options) (transcript, error) {
 	s.logger.Debug("Transcription", "Starting local transcription", "file", filepath.Base(filePath))
 
+	// The worker already has the model loaded
+	if s.worker != nil {
+		return s.worker.transcribe(ctx, filePath, buildInitialPrompt(s.config.Local.InitialPrompt, opts.Vocabulary))
+	}
+
 	// Build the command
-	args := []string{s.config.Local.WhisperScript, filePath}
+	args := append([]string{s.config.Local.WhisperScript, filePath}, scriptArgs(s.config.Local, s.config.Diarization)...)
+
+	// Bias Whisper towards local vocabulary via its initial prompt
+	if prompt := buildInitialPrompt(s.config.Local.InitialPrompt, opts.Vocabulary); prompt != "" {
+		args = append(args, "--initial-prompt", prompt)
+	}
+
 	cmd := exec.CommandContext(ctx, s.config.Local.PythonPath, args...)
+	if s.config.Diarization.Enabled && s.config.Diarization.HFToken != "" {
+		cmd.Env = append(os.Environ(), "HF_TOKEN="+s.config.Diarization.HFToken)
+	}
 
 	// Capture output
 	var stdout bytes.Buffer
@@ -152,35 +252,37 @@ func (s *Service) transcribeLocal(ctx context.Context, filePath string) (string,
 		if stderrStr != "" {
 			s.logger.Error("Whisper script stderr", "output", stderrStr)
 		}
-		return "", fmt.Errorf("whisper script failed: %w", err)
+		return transcript{}, fmt.Errorf("whisper script failed: %w", err)
 	}
 
 	// Parse the JSON output
 	output := stdout.String()
 	if output == "" {
-		return "", fmt.Errorf("no output from whisper script")
+		return transcript{}, fmt.Errorf("no output from whisper script")
 	}
 
 	var result struct {
-		Text string `json:"text"`
+		Text            string    `json:"text"`
+		Language        string    `json:"language"`
+		SpeakerSegments []Segment `json:"speaker_segments"`
 	}
 
 	if err := json.Unmarshal([]byte(output), &result); err != nil {
 		s.logger.Error("Failed to parse whisper output", "output", output, "error", err)
-		return "", fmt.Errorf("failed to parse whisper output: %w", err)
+		return transcript{}, fmt.Errorf("failed to parse whisper output: %w", err)
 	}
 
-	return strings.TrimSpace(result.Text), nil
+	return transcript{text: strings.TrimSpace(result.Text), language: result.Language, segments: result.SpeakerSegments}, nil
 }
 
 // transcribeRemote performs remote transcription via API
-func (s *Service) transcribeRemote(ctx context.Context, filePath string) (string, error) {
+func (s *Service) transcribeRemote(ctx context.Context, filePath string, opts *Options) (transcript, error) {
 	s.logger.Debug("Transcription", "Starting remote transcription", "file", filepath.Base(filePath))
 
 	// Open the file
 	file, err := os.Open(filePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
+		return transcript{}, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
@@ -191,11 +293,25 @@ func (s *Service) transcribeRemote(ctx context.Context, filePath string) (string
 	// Add the file
 	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
 	if err != nil {
-		return "", fmt.Errorf("failed to create form file: %w", err)
+		return transcript{}, fmt.Errorf("failed to create form file: %w", err)
 	}
 
 	if _, err := io.Copy(part, file); err != nil {
-		return "", fmt.Errorf("failed to copy file data: %w", err)
+		return transcript{}, fmt.Errorf("failed to copy file data: %w", err)
+	}
+
+	// Pass glossary hints along for Whisper-compatible APIs
+	if prompt := buildInitialPrompt("", opts.Vocabulary); prompt != "" {
+		writer.WriteField("prompt", prompt)
+	}
+	if opts.TalkgroupID != "" {
+		writer.WriteField("talkgroup_id", opts.TalkgroupID)
+	}
+	if language := s.config.Remote.Language; language != "" && language != "auto" {
+		writer.WriteField("language", language)
+	}
+	if s.config.Diarization.Enabled {
+		writer.WriteField("diarize", "true")
 	}
 
 	writer.Close()
@@ -203,7 +319,7 @@ func (s *Service) transcribeRemote(ctx context.Context, filePath string) (string
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "POST", s.config.Remote.Endpoint, &buf)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return transcript{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
@@ -215,26 +331,269 @@ func (s *Service) transcribeRemote(ctx context.Context, filePath string) (string
 	// Send the request
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return transcript{}, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return transcript{}, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
 	// Parse response
 	var result struct {
-		Text string `json:"text"`
+		Text     string    `json:"text"`
+		Language string    `json:"language"`
+		Segments []Segment `json:"segments"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return transcript{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return transcript{text: strings.TrimSpace(result.Text), language: result.Language, segments: result.Segments}, nil
+}
+
+// transcribeDeepgram performs remote transcription via the Deepgram pre-recorded API
+func (s *Service) transcribeDeepgram(ctx context.Context, filePath string, opts *Options) (transcript, error) {
+	s.logger.Debug("Transcription", "Starting Deepgram transcription", "file", filepath.Base(filePath))
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return transcript{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	endpoint, err := url.Parse(s.config.Remote.Endpoint)
+	if err != nil {
+		return transcript{}, fmt.Errorf("invalid Deepgram endpoint: %w", err)
+	}
+
+	query := endpoint.Query()
+	query.Set("smart_format", "true")
+	if language := s.config.Remote.Language; language != "" && language != "auto" {
+		query.Set("language", language)
+	} else {
+		query.Set("detect_language", "true")
+	}
+	// Boost glossary terms so local street names and unit designators are recognized
+	for _, term := range opts.Vocabulary {
+		query.Add("keywords", term+":2")
+	}
+	if s.config.Diarization.Enabled {
+		query.Set("diarize", "true")
+		query.Set("utterances", "true")
+	}
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint.String(), file)
+	if err != nil {
+		return transcript{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", audioContentType(filePath))
+	if s.config.Remote.APIKey != "" {
+		req.Header.Set("Authorization", "Token "+s.config.Remote.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return transcript{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return transcript{}, fmt.Errorf("Deepgram request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Results struct {
+			Channels []struct {
+				DetectedLanguage string `json:"detected_language"`
+				Alternatives     []struct {
+					Transcript string `json:"transcript"`
+				} `json:"alternatives"`
+			} `json:"channels"`
+			Utterances []struct {
+				Speaker    int     `json:"speaker"`
+				Start      float64 `json:"start"`
+				End        float64 `json:"end"`
+				Transcript string  `json:"transcript"`
+			} `json:"utterances"`
+		} `json:"results"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return transcript{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Results.Channels) == 0 || len(result.Results.Channels[0].Alternatives) == 0 {
+		return transcript{}, nil
+	}
+
+	var segments []Segment
+	for _, utterance := range result.Results.Utterances {
+		segments = append(segments, Segment{
+			Speaker: fmt.Sprintf("%d", utterance.Speaker),
+			Start:   utterance.Start,
+			End:     utterance.End,
+			Text:    utterance.Transcript,
+		})
+	}
+
+	channel := result.Results.Channels[0]
+	language := channel.DetectedLanguage
+	if language == "" {
+		language = s.config.Remote.Language
+	}
+	return transcript{
+		text:     strings.TrimSpace(channel.Alternatives[0].Transcript),
+		language: language,
+		segments: segments,
+	}, nil
+}
+
+// normalizeLanguage reduces a reported language to a lowercase code, turning
+// the full names some Whisper-compatible APIs return into ISO 639-1 codes
+func normalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language == "auto" {
+		return ""
+	}
+	if code, ok := languageCodes[language]; ok {
+		return code
+	}
+	// Regional variants such as en-US
+	if base, _, found := strings.Cut(language, "-"); found && len(base) == 2 {
+		return base
+	}
+	return language
+}
+
+// languageCodes maps the language names Whisper reports to their codes
+var languageCodes = map[string]string{
+	"english":        "en",
+	"spanish":        "es",
+	"french":         "fr",
+	"portuguese":     "pt",
+	"german":         "de",
+	"italian":        "it",
+	"chinese":        "zh",
+	"vietnamese":     "vi",
+	"korean":         "ko",
+	"japanese":       "ja",
+	"russian":        "ru",
+	"arabic":         "ar",
+	"tagalog":        "tl",
+	"haitian creole": "ht",
+}
+
+// buildInitialPrompt turns glossary terms into a Whisper initial prompt
+func buildInitialPrompt(base string, vocabulary []string) string {
+	if len(vocabulary) == 0 {
+		return base
+	}
+	if base == "" {
+		base = "Radio dispatch traffic."
+	}
+	return base + " Terms: " + strings.Join(vocabulary, ", ") + "."
+}
+
+// scriptArgs returns the whisper script options for the model, decoding and
+// diarization settings
+func scriptArgs(local config.LocalTranscriptionConfig, diarization config.DiarizationConfig) []string {
+	args := []string{
+		"--model", local.ModelSize,
+		"--device", local.Device,
+		"--device-index", strconv.Itoa(local.DeviceIndex),
+		"--compute-type", local.ComputeType,
+		"--beam-size", strconv.Itoa(local.BeamSize),
+		"--language", local.Language,
+	}
+	if !local.VADEnabled() {
+		args = append(args, "--no-vad-filter")
+	}
+
+	// Speaker diarization via pyannote in the whisper script
+	if diarization.Enabled {
+		args = append(args, "--diarize")
+		if diarization.MaxSpeakers > 0 {
+			args = append(args, "--max-speakers", strconv.Itoa(diarization.MaxSpeakers))
+		}
+	}
+	return args
+}
+
+// normalizeSegments drops untagged segments, merges consecutive segments from
+// the same speaker and relabels speakers as "Speaker 1", "Speaker 2", ... in
+// order of appearance
+func normalizeSegments(segments []Segment) []Segment {
+	labels := make(map[string]string)
+	var normalized []Segment
+
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if segment.Speaker == "" || text == "" {
+			continue
+		}
+
+		label, exists := labels[segment.Speaker]
+		if !exists {
+			label = fmt.Sprintf("Speaker %d", len(labels)+1)
+			labels[segment.Speaker] = label
+		}
+
+		if last := len(normalized) - 1; last >= 0 && normalized[last].Speaker == label {
+			normalized[last].Text += " " + text
+			normalized[last].End = segment.End
+			continue
+		}
+
+		normalized = append(normalized, Segment{
+			Speaker: label,
+			Start:   segment.Start,
+			End:     segment.End,
+			Text:    text,
+		})
 	}
 
-	return strings.TrimSpace(result.Text), nil
+	return normalized
+}
+
+// SpeakerCount returns the number of distinct speakers in the segments
+func SpeakerCount(segments []Segment) int {
+	speakers := make(map[string]bool)
+	for _, segment := range segments {
+		speakers[segment.Speaker] = true
+	}
+	return len(speakers)
+}
+
+// FormatDialog renders speaker segments as one line per transmission
+func FormatDialog(segments []Segment) string {
+	lines := make([]string, len(segments))
+	for i, segment := range segments {
+		lines[i] = segment.Speaker + ": " + segment.Text
+	}
+	return strings.Join(lines, "\n")
+}
+
+// audioContentType returns the MIME type for an audio file based on its extension
+func audioContentType(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".wav":
+		return "audio/wav"
+	case ".m4a":
+		return "audio/mp4"
+	case ".ogg":
+		return "audio/ogg"
+	case ".flac":
+		return "audio/flac"
+	default:
+		return "audio/mpeg"
+	}
 }
 
 // validateFile validates that the audio file is suitable for transcription
@@ -282,7 +641,7 @@ func (s *Service) TranscribeBatch(ctx context.Context, filePaths []string) ([]*T
 		case <-ctx.Done():
 			return results, ctx.Err()
 		default:
-			result, err := s.TranscribeFile(ctx, filePath)
+			result, err := s.TranscribeFile(ctx, filePath, nil)
 			if result != nil {
 				results[i] = result
 			} else {
@@ -0,0 +1,341 @@
+package transcription
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+)
+
+const (
+	// workerStartTimeout allows for the model being downloaded on first start
+	workerStartTimeout = 5 * time.Minute
+	// workerPingTimeout is how long a health check waits for a reply
+	workerPingTimeout = 10 * time.Second
+	// maxWorkerRestartDelay caps the doubling delay between failed starts
+	maxWorkerRestartDelay = 5 * time.Minute
+)
+
+// errWorkerExited is returned when the worker process exits mid-request
+var errWorkerExited = errors.New("whisper worker exited")
+
+// workerRequest is one line sent to the whisper worker
+type workerRequest struct {
+	ID            int    `json:"id"`
+	Command       string `json:"command"` // transcribe or ping
+	AudioFile     string `json:"audio_file,omitempty"`
+	InitialPrompt string `json:"initial_prompt,omitempty"`
+}
+
+// workerResponse is one line received from the whisper worker
+type workerResponse struct {
+	ID              int       `json:"id"`
+	Ready           bool      `json:"ready"`
+	Text            string    `json:"text"`
+	Language        string    `json:"language"`
+	SpeakerSegments []Segment `json:"speaker_segments"`
+	Error           string    `json:"error"`
+}
+
+// workerProcess is one run of the whisper worker
+type workerProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan workerResponse
+	exited    chan struct{} // Closed once the process has exited
+}
+
+// whisperWorker keeps a faster-whisper process with the model loaded and
+// sends it one call at a time, so calls don't wait for the model to load
+type whisperWorker struct {
+	local       config.LocalTranscriptionConfig
+	diarization config.DiarizationConfig
+	logger      *logger.Logger
+
+	mu       sync.Mutex // Held for a whole request; the worker handles one call at a time
+	process  *workerProcess
+	nextID   int
+	failures int       // Consecutive failed starts
+	retryAt  time.Time // No start is attempted before this after a failure
+}
+
+// newWhisperWorker creates a worker; the process is started on first use
+func newWhisperWorker(local config.LocalTranscriptionConfig, diarization config.DiarizationConfig, logger *logger.Logger) *whisperWorker {
+	return &whisperWorker{local: local, diarization: diarization, logger: logger}
+}
+
+// run starts the worker and checks its health until ctx is cancelled
+func (w *whisperWorker) run(ctx context.Context, interval time.Duration) {
+	w.mu.Lock()
+	if err := w.ensureStarted(ctx); err != nil {
+		w.logger.Error("Failed to start whisper worker", "error", err)
+	}
+	w.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A worker busy with a call is answering requests
+			if !w.mu.TryLock() {
+				continue
+			}
+			if err := w.ping(ctx); err != nil && ctx.Err() == nil {
+				w.logger.Warn("Whisper worker failed health check, restarting", "error", err)
+				w.kill()
+				if err := w.ensureStarted(ctx); err != nil {
+					w.logger.Error("Failed to restart whisper worker", "error", err)
+				}
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+// transcribe sends a call to the worker and waits for its transcription
+func (w *whisperWorker) transcribe(ctx context.Context, filePath, prompt string) (transcript, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	response, err := w.request(ctx, workerRequest{Command: "transcribe", AudioFile: filePath, InitialPrompt: prompt})
+	if err != nil {
+		return transcript{}, err
+	}
+	if response.Error != "" {
+		return transcript{}, fmt.Errorf("whisper worker: %s", response.Error)
+	}
+	return transcript{text: strings.TrimSpace(response.Text), language: response.Language, segments: response.SpeakerSegments}, nil
+}
+
+// healthy reports whether the worker failed to start. A worker busy with a
+// call is taken to be healthy.
+func (w *whisperWorker) healthy() error {
+	if !w.mu.TryLock() {
+		return nil
+	}
+	defer w.mu.Unlock()
+	if w.failures > 0 {
+		return fmt.Errorf("whisper worker failed to start %d times", w.failures)
+	}
+	return nil
+}
+
+// ping checks that the worker is responding. w.mu must be held.
+func (w *whisperWorker) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, workerPingTimeout)
+	defer cancel()
+
+	_, err := w.request(ctx, workerRequest{Command: "ping"})
+	return err
+}
+
+// request sends one request and waits for its response, starting the worker
+// if it isn't running. The worker is killed if ctx ends first, as it can't be
+// interrupted mid-call. w.mu must be held.
+func (w *whisperWorker) request(ctx context.Context, req workerRequest) (workerResponse, error) {
+	if err := w.ensureStarted(ctx); err != nil {
+		return workerResponse{}, err
+	}
+	process := w.process
+
+	w.nextID++
+	req.ID = w.nextID
+	line, err := json.Marshal(req)
+	if err != nil {
+		return workerResponse{}, fmt.Errorf("failed to encode worker request: %w", err)
+	}
+	if _, err := process.stdin.Write(append(line, '\n')); err != nil {
+		w.kill()
+		return workerResponse{}, fmt.Errorf("failed to send request to whisper worker: %w", err)
+	}
+
+	for {
+		select {
+		case response := <-process.responses:
+			if response.ID == req.ID {
+				return response, nil
+			}
+			// A reply to a request that was abandoned
+		case <-process.exited:
+			w.process = nil
+			return workerResponse{}, errWorkerExited
+		case <-ctx.Done():
+			w.kill()
+			return workerResponse{}, ctx.Err()
+		}
+	}
+}
+
+// ensureStarted starts the worker process and waits for the model to load if
+// it isn't already running. w.mu must be held.
+func (w *whisperWorker) ensureStarted(ctx context.Context) error {
+	if w.process != nil {
+		select {
+		case <-w.process.exited:
+			w.logger.Warn("Whisper worker exited, restarting")
+			w.process = nil
+		default:
+			return nil
+		}
+	}
+
+	if wait := time.Until(w.retryAt); wait > 0 {
+		return fmt.Errorf("whisper worker failed to start, retrying in %s", wait.Round(time.Second))
+	}
+
+	start := time.Now()
+	process, err := w.start(ctx)
+	if err != nil {
+		w.failures++
+		delay := min(time.Duration(1<<min(w.failures-1, 8))*5*time.Second, maxWorkerRestartDelay)
+		w.retryAt = time.Now().Add(delay)
+		return err
+	}
+
+	w.process = process
+	w.failures = 0
+	w.retryAt = time.Time{}
+	w.logger.Info("Whisper worker ready", "model", w.local.ModelSize, "took", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// start launches the worker process and waits for it to report ready
+func (w *whisperWorker) start(ctx context.Context) (*workerProcess, error) {
+	args := append([]string{w.local.WhisperScript, "--daemon"}, scriptArgs(w.local, w.diarization)...)
+
+	// The process outlives the request that started it, so it isn't bound to ctx
+	cmd := exec.Command(w.local.PythonPath, args...)
+	if w.diarization.Enabled && w.diarization.HFToken != "" {
+		cmd.Env = append(os.Environ(), "HF_TOKEN="+w.diarization.HFToken)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worker stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worker stdout: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worker stderr: %w", err)
+	}
+
+	w.logger.Info("Starting whisper worker...", "model", w.local.ModelSize, "device", w.local.Device)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start whisper worker: %w", err)
+	}
+
+	process := &workerProcess{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan workerResponse, 16),
+		exited:    make(chan struct{}),
+	}
+
+	logged := make(chan struct{})
+	go func() {
+		w.logStderr(stderr)
+		close(logged)
+	}()
+	go func() {
+		w.readResponses(stdout, process.responses)
+		<-logged
+		cmd.Wait()
+		close(process.exited)
+	}()
+
+	// The first line reports whether the model loaded
+	timer := time.NewTimer(workerStartTimeout)
+	defer timer.Stop()
+	select {
+	case response := <-process.responses:
+		if !response.Ready {
+			process.kill()
+			return nil, fmt.Errorf("whisper worker failed to start: %s", response.Error)
+		}
+		return process, nil
+	case <-process.exited:
+		return nil, fmt.Errorf("whisper worker exited during startup")
+	case <-timer.C:
+		process.kill()
+		return nil, fmt.Errorf("whisper worker did not start within %s", workerStartTimeout)
+	case <-ctx.Done():
+		process.kill()
+		return nil, ctx.Err()
+	}
+}
+
+// readResponses decodes response lines until the worker's stdout closes
+func (w *whisperWorker) readResponses(stdout io.Reader, responses chan<- workerResponse) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var response workerResponse
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			w.logger.Warn("Failed to parse whisper worker output", "output", scanner.Text(), "error", err)
+			continue
+		}
+		select {
+		case responses <- response:
+		default:
+			w.logger.Warn("Dropping unexpected whisper worker output", "id", response.ID)
+		}
+	}
+}
+
+// logStderr forwards the worker's log output at debug level
+func (w *whisperWorker) logStderr(stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		w.logger.Debug("Transcription", "Whisper worker", "output", scanner.Text())
+	}
+}
+
+// kill stops the current worker process, if any. w.mu must be held.
+func (w *whisperWorker) kill() {
+	if w.process != nil {
+		w.process.kill()
+		w.process = nil
+	}
+}
+
+// stop ends the worker process after any call in progress
+func (w *whisperWorker) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.process == nil {
+		return
+	}
+
+	// Closing stdin ends the worker's request loop
+	w.process.stdin.Close()
+	select {
+	case <-w.process.exited:
+	case <-time.After(5 * time.Second):
+		w.process.kill()
+	}
+	w.process = nil
+}
+
+// kill terminates the process and waits for it to exit
+func (p *workerProcess) kill() {
+	p.cmd.Process.Kill()
+	<-p.exited
+}
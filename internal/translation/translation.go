@@ -0,0 +1,44 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+
+	"Meiko/internal/llm"
+)
+
+// Translator translates transcriptions to English with a language model
+type Translator struct {
+	provider llm.Provider
+}
+
+// New creates a translator using an LLM provider
+func New(provider llm.Provider) *Translator {
+	return &Translator{provider: provider}
+}
+
+// Needed reports whether a transcription in the given language should be
+// translated. Calls with no detected language are assumed to be English.
+func Needed(language string) bool {
+	return language != "" && language != "en"
+}
+
+// Translate returns the English translation of a transcription
+func (t *Translator) Translate(ctx context.Context, text, language string) (string, error) {
+	prompt := fmt.Sprintf(`Translate this public safety radio transcript from %s to English.
+Keep unit numbers, addresses, names, codes and any "Speaker N:" labels unchanged.
+Reply with only the translation.
+
+%s`, language, text)
+
+	translated, err := t.provider.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("translation request failed: %w", err)
+	}
+	return translated, nil
+}
+
+// Close releases the provider
+func (t *Translator) Close() error {
+	return t.provider.Close()
+}
@@ -0,0 +1,159 @@
+package usb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// sysfsDevices is where Linux lists enumerated USB devices
+const sysfsDevices = "/sys/bus/usb/devices"
+
+// Receivers are the vendor:product IDs of common SDR receivers. A bare
+// vendor ID followed by a colon matches every product from that vendor.
+var Receivers = map[string]string{
+	"0bda:2838": "RTL-SDR (RTL2838)",
+	"0bda:2832": "RTL-SDR (RTL2832U)",
+	"1d50:6089": "HackRF One",
+	"1d50:604b": "HackRF Jawbreaker",
+	"1d50:60a1": "Airspy",
+	"03eb:800c": "Airspy HF+",
+	"1df7:":     "SDRplay",
+}
+
+// Device is an enumerated USB device
+type Device struct {
+	ID     string `json:"id"` // vendor:product
+	Name   string `json:"name"`
+	Serial string `json:"serial,omitempty"`
+	Port   string `json:"port,omitempty"` // Bus and port path, e.g. 1-1.2
+}
+
+// Key identifies a device across re-enumeration. The port is used when the
+// device has no serial number.
+func (d Device) Key() string {
+	if d.Serial != "" {
+		return d.ID + ":" + d.Serial
+	}
+	return d.ID + "@" + d.Port
+}
+
+// String describes the device for logs and alerts
+func (d Device) String() string {
+	name := d.Name
+	if name == "" {
+		name = d.ID
+	}
+	if d.Serial != "" {
+		return fmt.Sprintf("%s [%s] serial %s", name, d.ID, d.Serial)
+	}
+	return fmt.Sprintf("%s [%s]", name, d.ID)
+}
+
+// Scan lists the connected USB devices, from sysfs where available and from
+// lsusb otherwise
+func Scan() ([]Device, error) {
+	if _, err := os.Stat(sysfsDevices); err == nil {
+		return scanSysfs()
+	}
+	return scanLsusb()
+}
+
+// Match returns the devices whose IDs are listed. With no IDs, known SDR
+// receivers are matched.
+func Match(devices []Device, ids []string) []Device {
+	if len(ids) == 0 {
+		ids = make([]string, 0, len(Receivers))
+		for id := range Receivers {
+			ids = append(ids, id)
+		}
+	}
+
+	var matched []Device
+	for _, device := range devices {
+		for _, id := range ids {
+			id = strings.ToLower(id)
+			if device.ID == id || (strings.HasSuffix(id, ":") && strings.HasPrefix(device.ID, id)) {
+				matched = append(matched, device)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// scanSysfs reads device IDs from /sys/bus/usb/devices. Interfaces (1-1:1.0)
+// and root hubs (usb1) have no idVendor file and are skipped.
+func scanSysfs() ([]Device, error) {
+	entries, err := os.ReadDir(sysfsDevices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sysfsDevices, err)
+	}
+
+	var devices []Device
+	for _, entry := range entries {
+		dir := filepath.Join(sysfsDevices, entry.Name())
+		vendor := readAttribute(dir, "idVendor")
+		product := readAttribute(dir, "idProduct")
+		if vendor == "" || product == "" {
+			continue
+		}
+
+		device := Device{
+			ID:     strings.ToLower(vendor + ":" + product),
+			Name:   readAttribute(dir, "product"),
+			Serial: readAttribute(dir, "serial"),
+			Port:   entry.Name(),
+		}
+		if name, ok := Receivers[device.ID]; ok {
+			device.Name = name
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// readAttribute reads a sysfs attribute, returning an empty string if it is missing
+func readAttribute(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// lsusbLine matches "Bus 001 Device 004: ID 0bda:2838 Realtek Semiconductor Corp. RTL2838 DVB-T"
+var lsusbLine = regexp.MustCompile(`^Bus \d+ Device \d+: ID ([0-9a-fA-F]{4}:[0-9a-fA-F]{4}) ?(.*)$`)
+
+// scanLsusb parses lsusb output. It carries no serial numbers, and device
+// numbers change when a device re-enumerates, so devices are keyed by ID alone.
+func scanLsusb() ([]Device, error) {
+	output, err := exec.Command("lsusb").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run lsusb: %w", err)
+	}
+
+	var devices []Device
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := lsusbLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+
+		device := Device{
+			ID:   strings.ToLower(match[1]),
+			Name: match[2],
+		}
+		if name, ok := Receivers[device.ID]; ok {
+			device.Name = name
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
@@ -0,0 +1,185 @@
+package usb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"Meiko/internal/config"
+	"Meiko/internal/logger"
+)
+
+// Event reports a watched device disappearing or returning
+type Event struct {
+	Device  Device
+	Present bool // False when the device disappeared
+	Missing int  // Watched devices still missing after the change
+}
+
+// Watchdog periodically checks that the SDR receivers present at startup are
+// still enumerated, since a USB dropout otherwise leaves SDRTrunk recording
+// dead air
+type Watchdog struct {
+	config      config.USBWatchdogConfig
+	logger      *logger.Logger
+	expected    map[string]Device // Devices seen since startup
+	missing     map[string]Device
+	onEvent     func(Event)
+	onRecovered func()
+	scanFailed  bool
+	mutex       sync.RWMutex
+}
+
+// NewWatchdog creates a USB device watchdog
+func NewWatchdog(config config.USBWatchdogConfig, logger *logger.Logger) *Watchdog {
+	return &Watchdog{
+		config:   config,
+		logger:   logger,
+		expected: make(map[string]Device),
+		missing:  make(map[string]Device),
+	}
+}
+
+// OnEvent sets a callback for devices disappearing and returning. It must be
+// set before Start.
+func (w *Watchdog) OnEvent(fn func(Event)) {
+	w.onEvent = fn
+}
+
+// OnRecovered sets a callback run once every missing device has returned and
+// settled, if restart_sdrtrunk is enabled. It must be set before Start.
+func (w *Watchdog) OnRecovered(fn func()) {
+	w.onRecovered = fn
+}
+
+// Start records the devices present now and begins watching them
+func (w *Watchdog) Start(ctx context.Context) {
+	devices, err := w.scan()
+	if err != nil {
+		w.logger.Warn("USB watchdog could not list devices", "error", err)
+	}
+	for key, device := range devices {
+		w.expected[key] = device
+		w.logger.Info("Watching USB device", "device", device.String())
+	}
+	if len(devices) == 0 && err == nil {
+		w.logger.Warn("USB watchdog found no SDR receivers; devices will be watched once they appear")
+	}
+
+	go w.run(ctx)
+}
+
+// Devices returns the watched devices and whether each is present
+func (w *Watchdog) Devices() map[string]bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	devices := make(map[string]bool, len(w.expected))
+	for key, device := range w.expected {
+		_, missing := w.missing[key]
+		devices[device.String()] = !missing
+	}
+	return devices
+}
+
+// run polls for device changes until the context is cancelled
+func (w *Watchdog) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(w.config.Interval) * time.Second)
+	defer ticker.Stop()
+
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.check() {
+				settle = time.After(time.Duration(w.config.SettleTime) * time.Second)
+			}
+		case <-settle:
+			settle = nil
+			w.mutex.RLock()
+			recovered := len(w.missing) == 0
+			w.mutex.RUnlock()
+			if recovered && w.config.RestartSDRTrunk && w.onRecovered != nil {
+				w.logger.Info("USB devices are back, restarting SDRTrunk")
+				w.onRecovered()
+			}
+		}
+	}
+}
+
+// check compares the enumerated devices with the expected ones and reports
+// changes. It returns true when the last missing device has returned.
+func (w *Watchdog) check() bool {
+	devices, err := w.scan()
+	if err != nil {
+		if !w.scanFailed {
+			w.logger.Error("USB watchdog scan failed", "error", err)
+			w.scanFailed = true
+		}
+		return false
+	}
+	w.scanFailed = false
+
+	var events []Event
+	w.mutex.Lock()
+	for key, device := range w.expected {
+		_, present := devices[key]
+		_, wasMissing := w.missing[key]
+		switch {
+		case !present && !wasMissing:
+			w.missing[key] = device
+			events = append(events, Event{Device: device})
+		case present && wasMissing:
+			delete(w.missing, key)
+			events = append(events, Event{Device: device, Present: true})
+		}
+	}
+	for key, device := range devices {
+		if _, ok := w.expected[key]; !ok {
+			w.expected[key] = device
+			w.logger.Info("New USB device detected, now watching it", "device", device.String())
+		}
+	}
+	missing := len(w.missing)
+	w.mutex.Unlock()
+
+	recovered := false
+	for _, event := range events {
+		event.Missing = missing
+		if event.Present {
+			w.logger.Success("USB device returned", "device", event.Device.String())
+			recovered = missing == 0
+		} else {
+			w.logger.Error("USB device disappeared", "device", event.Device.String())
+		}
+		if w.onEvent != nil {
+			w.onEvent(event)
+		}
+	}
+	return recovered
+}
+
+// scan lists the watched devices, keyed so that identical devices without
+// serial numbers are counted separately
+func (w *Watchdog) scan() (map[string]Device, error) {
+	devices, err := Scan()
+	if err != nil {
+		return nil, err
+	}
+
+	keyed := make(map[string]Device)
+	for _, device := range Match(devices, w.config.Devices) {
+		key := device.Key()
+		for n := 2; ; n++ {
+			if _, taken := keyed[key]; !taken {
+				break
+			}
+			key = fmt.Sprintf("%s#%d", device.Key(), n)
+		}
+		keyed[key] = device
+	}
+	return keyed, nil
+}
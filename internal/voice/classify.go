@@ -0,0 +1,157 @@
+package voice
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"Meiko/internal/audio"
+	"Meiko/internal/config"
+)
+
+// Class is what a recording was classified as. Voice is empty so calls
+// recorded before classification read as voice.
+type Class string
+
+const (
+	Voice     Class = ""
+	Encrypted Class = "encrypted" // Noise-like audio, such as encrypted or undecodable voice
+	Data      Class = "data"      // A short steady burst, such as a data or signalling burst
+)
+
+const (
+	sampleRate = 8000
+	frameSize  = 256 // 32ms analysis window
+
+	// Speech band the spectral flatness is measured over
+	minVoiceHz = 300.0
+	maxVoiceHz = 3400.0
+
+	// silenceDB is the frame level below which a frame counts as silence
+	silenceDB = -50.0
+	// minActiveFrames is the least audio that can be judged (~250ms)
+	minActiveFrames = 8
+	// maxDataBurst is the longest a data burst can be in seconds; longer
+	// steady audio is left for tone detection and transcription
+	maxDataBurst = 2.0
+)
+
+// Result describes a recording's classification and the measurements behind it
+type Result struct {
+	Class      Class
+	Flatness   float64 // Median spectral flatness of active frames (0 tonal - 1 white noise)
+	Modulation float64 // Standard deviation of the frame level in dB
+	Active     float64 // Seconds between the first and last non-silent frame
+}
+
+// Classifier flags recordings that are not speech before they are transcribed
+type Classifier struct {
+	config config.VoiceDetectionConfig
+	ffmpeg string // ffmpeg executable that decodes files
+}
+
+// NewClassifier creates a classifier that decodes files with ffmpeg
+func NewClassifier(cfg config.VoiceDetectionConfig, ffmpeg string) *Classifier {
+	return &Classifier{config: cfg, ffmpeg: ffmpeg}
+}
+
+// ClassifyFile decodes an audio file with ffmpeg and classifies it
+func (c *Classifier) ClassifyFile(ctx context.Context, filePath string) (Result, error) {
+	samples, err := audio.Decode(ctx, c.ffmpeg, filePath, sampleRate)
+	if err != nil {
+		return Result{}, err
+	}
+	return c.Classify(samples), nil
+}
+
+// Classify classifies mono samples at 8 kHz. Speech has a shaped spectrum and
+// a level that rises and falls with syllables and pauses; encrypted audio has
+// a flat, noise-like spectrum and data bursts hold a steady level.
+func (c *Classifier) Classify(samples []float64) Result {
+	window := audio.HannWindow(frameSize)
+	binWidth := float64(sampleRate) / frameSize
+	lowBin, highBin := int(minVoiceHz/binWidth), int(maxVoiceHz/binWidth)
+
+	var levels, flatness []float64
+	first, last := -1, -1
+	for offset := 0; offset+frameSize <= len(samples); offset += frameSize {
+		frame := samples[offset : offset+frameSize]
+		level := frameLevel(frame)
+		levels = append(levels, level)
+		if level < silenceDB {
+			continue
+		}
+
+		if first < 0 {
+			first = len(levels) - 1
+		}
+		last = len(levels) - 1
+		flatness = append(flatness, spectralFlatness(audio.PowerSpectrum(frame, window)[lowBin:highBin+1]))
+	}
+
+	if len(flatness) < minActiveFrames {
+		return Result{Class: Voice}
+	}
+
+	result := Result{
+		Flatness:   median(flatness),
+		Modulation: stdDev(levels[first : last+1]),
+		Active:     float64((last-first+1)*frameSize) / sampleRate,
+	}
+
+	switch {
+	case result.Flatness >= c.config.MaxFlatness:
+		result.Class = Encrypted
+	case result.Modulation < c.config.MinModulation && result.Active <= maxDataBurst:
+		result.Class = Data
+	default:
+		result.Class = Voice
+	}
+	return result
+}
+
+// frameLevel returns a frame's RMS level in dBFS, floored at -90
+func frameLevel(frame []float64) float64 {
+	var sum float64
+	for _, sample := range frame {
+		sum += sample * sample
+	}
+	rms := math.Sqrt(sum / float64(len(frame)))
+	return math.Max(20*math.Log10(rms+1e-12), -90)
+}
+
+// spectralFlatness returns the geometric mean of the power spectrum over its
+// arithmetic mean
+func spectralFlatness(power []float64) float64 {
+	var logSum, sum float64
+	for _, p := range power {
+		logSum += math.Log(p + 1e-12)
+		sum += p
+	}
+	n := float64(len(power))
+	if sum == 0 {
+		return 0
+	}
+	return math.Exp(logSum/n) / (sum / n)
+}
+
+// median returns the middle value of a slice, reordering it
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	return values[len(values)/2]
+}
+
+// stdDev returns the standard deviation of a slice
+func stdDev(values []float64) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
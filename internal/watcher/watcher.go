@@ -3,10 +3,12 @@ package watcher
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -21,27 +23,46 @@ type FileEvent struct {
 	Size      int64
 	ModTime   time.Time
 	EventType string
+	Site      string // Receive site for recordings uploaded by agents
+	System    string // Radio system the recording was captured on
+
+	// Settings of the directory the recording was found in
+	FilenameFormat  string        // Empty for the system's format
+	MinCallDuration time.Duration // 0 for the global minimum
 }
 
 // FileWatcher monitors a directory for new audio files
 type FileWatcher struct {
 	directory string
+	system    string
+	patterns  []string
+	format    string // Filename format of the directory's recordings
+	minCall   time.Duration
+	recursive bool
 	config    config.FileMonitorConfig
 	logger    *logger.Logger
 	watcher   *fsnotify.Watcher
 	events    chan FileEvent
 	errors    chan error
 	running   bool
+	lastFile  time.Time    // When a recording was last written
+	deferred  atomic.Int64 // Times a recording waited out queue_timeout and was put back
+	dropped   atomic.Int64 // Recordings never queued because the watcher stopped
 	mutex     sync.RWMutex
 	ctx       context.Context
 	cancel    context.CancelFunc
 }
 
-// New creates a new file watcher
-func New(directory string, config config.FileMonitorConfig, logger *logger.Logger) (*FileWatcher, error) {
+// New creates a new file watcher for a directory. Events are tagged with the
+// directory's system ID, which may be empty when only one system is monitored.
+func New(directory config.WatchDirectoryConfig, config config.FileMonitorConfig, logger *logger.Logger) (*FileWatcher, error) {
 	// Validate directory exists
-	if _, err := os.Stat(directory); os.IsNotExist(err) {
-		return nil, fmt.Errorf("directory does not exist: %s", directory)
+	if _, err := os.Stat(directory.Path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", directory.Path)
+	}
+	patterns := directory.Patterns
+	if len(patterns) == 0 {
+		patterns = config.Patterns
 	}
 
 	watcher, err := fsnotify.NewWatcher()
@@ -50,7 +71,12 @@ func New(directory string, config config.FileMonitorConfig, logger *logger.Logge
 	}
 
 	return &FileWatcher{
-		directory: directory,
+		directory: directory.Path,
+		system:    directory.System,
+		patterns:  patterns,
+		format:    directory.FilenameFormat,
+		minCall:   time.Duration(directory.MinCallDuration) * time.Second,
+		recursive: directory.Recursive,
 		config:    config,
 		logger:    logger,
 		watcher:   watcher,
@@ -70,13 +96,15 @@ func (fw *FileWatcher) Start(ctx context.Context) error {
 
 	fw.ctx, fw.cancel = context.WithCancel(ctx)
 
-	// Add the directory to the watcher
-	if err := fw.watcher.Add(fw.directory); err != nil {
-		return fmt.Errorf("failed to add directory to watcher: %w", err)
+	// Add the directory to the watcher, unless it is listed on each poll instead
+	if !fw.config.Polling {
+		if err := fw.watchDirectories(); err != nil {
+			return err
+		}
 	}
 
 	fw.running = true
-	fw.logger.Info("File watcher started", "directory", fw.directory)
+	fw.logger.Info("File watcher started", "directory", fw.directory, "system", fw.system, "polling", fw.config.Polling, "recursive", fw.recursive)
 
 	// Start the monitoring goroutine
 	go fw.monitor()
@@ -84,6 +112,41 @@ func (fw *FileWatcher) Start(ctx context.Context) error {
 	return nil
 }
 
+// watchDirectories adds the directory to the filesystem watcher, along with
+// its subdirectories when watching recursively
+func (fw *FileWatcher) watchDirectories() error {
+	if !fw.recursive {
+		if err := fw.watcher.Add(fw.directory); err != nil {
+			return fmt.Errorf("failed to add directory to watcher: %w", err)
+		}
+		return nil
+	}
+	return fw.watchTree(fw.directory, nil)
+}
+
+// watchTree adds a directory and every directory below it to the filesystem
+// watcher. Recordings already in them become pending when pendingFiles is
+// given, since a new directory may be written to before it is watched.
+func (fw *FileWatcher) watchTree(root string, pendingFiles map[string]time.Time) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to list directory: %w", err)
+		}
+		if !entry.IsDir() {
+			if pendingFiles != nil && fw.matchesPattern(path) {
+				if _, pending := pendingFiles[path]; !pending {
+					pendingFiles[path] = time.Now()
+				}
+			}
+			return nil
+		}
+		if err := fw.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to add directory to watcher: %w", err)
+		}
+		return nil
+	})
+}
+
 // Stop stops monitoring the directory
 func (fw *FileWatcher) Stop() error {
 	fw.mutex.Lock()
@@ -128,21 +191,50 @@ func (fw *FileWatcher) IsWatching() bool {
 	return fw.running
 }
 
+// LastFile returns when a new recording last appeared, or the zero time if
+// none has since the watcher was created
+func (fw *FileWatcher) LastFile() time.Time {
+	fw.mutex.RLock()
+	defer fw.mutex.RUnlock()
+	return fw.lastFile
+}
+
 // monitor runs in a separate goroutine to handle filesystem events
 func (fw *FileWatcher) monitor() {
+	// Keep track of files that are being written to, and of those ready but
+	// waiting for room in the processing queue
+	pendingFiles := make(map[string]time.Time)
+	waiting := make(map[string]bool)
+
 	defer func() {
 		fw.mutex.Lock()
 		fw.running = false
 		fw.mutex.Unlock()
 		close(fw.events)
 		close(fw.errors)
-	}()
 
-	// Keep track of files that are being written to
-	pendingFiles := make(map[string]time.Time)
+		dropped := 0
+		for filename := range waiting {
+			if _, pending := pendingFiles[filename]; pending {
+				dropped++
+			}
+		}
+		fw.dropped.Add(int64(dropped))
+		if len(pendingFiles) > 0 {
+			fw.logger.Warn("File watcher stopped before queueing recordings, run 'meiko scan' to process them",
+				"directory", fw.directory, "recordings", len(pendingFiles), "waiting_for_queue", dropped)
+		}
+	}()
 	ticker := time.NewTicker(time.Duration(fw.config.PollInterval) * time.Millisecond)
 	defer ticker.Stop()
 
+	// When polling, recordings already in the directory are left alone
+	var known map[string]bool
+	if fw.config.Polling {
+		known = make(map[string]bool)
+		fw.poll(pendingFiles, known, true)
+	}
+
 	for {
 		select {
 		case <-fw.ctx.Done():
@@ -169,8 +261,11 @@ func (fw *FileWatcher) monitor() {
 			fw.errors <- err
 
 		case <-ticker.C:
+			if fw.config.Polling {
+				fw.poll(pendingFiles, known, false)
+			}
 			// Check pending files to see if they're ready for processing
-			fw.checkPendingFiles(pendingFiles)
+			fw.checkPendingFiles(pendingFiles, waiting)
 		}
 	}
 }
@@ -182,6 +277,18 @@ func (fw *FileWatcher) handleEvent(event fsnotify.Event, pendingFiles map[string
 		return nil
 	}
 
+	// Watch directories created or moved in below a recursive watch, such as
+	// SDRTrunk's dated folders. Watches of removed directories end by themselves.
+	if fw.recursive && event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			fw.logger.Debug("FileWatcher", "Watching new subdirectory", "directory", event.Name)
+			if err := fw.watchTree(event.Name, pendingFiles); err != nil {
+				return fmt.Errorf("failed to watch new subdirectory: %w", err)
+			}
+			return nil
+		}
+	}
+
 	// Check if the file matches our patterns
 	if !fw.matchesPattern(event.Name) {
 		return nil
@@ -195,8 +302,78 @@ func (fw *FileWatcher) handleEvent(event fsnotify.Event, pendingFiles map[string
 	return nil
 }
 
-// checkPendingFiles checks if pending files are ready for processing
-func (fw *FileWatcher) checkPendingFiles(pendingFiles map[string]time.Time) {
+// poll lists the directory in place of filesystem events, which don't arrive
+// for network shares and many container volumes. New recordings become
+// pending, and stay pending while their modification time keeps moving. known
+// holds every recording seen, so each is handled once; seed only fills it.
+func (fw *FileWatcher) poll(pendingFiles map[string]time.Time, known map[string]bool, seed bool) {
+	entries, err := fw.listRecordings()
+	if err != nil {
+		fw.logger.Error("Failed to list watched directory", "error", err, "directory", fw.directory)
+		return
+	}
+
+	present := make(map[string]bool, len(entries))
+	for filename, entry := range entries {
+		present[filename] = true
+
+		added, pending := pendingFiles[filename]
+		if seed || (known[filename] && !pending) {
+			known[filename] = true
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if !pending {
+			fw.logger.Debug("FileWatcher", "File found by polling", "file", filename)
+			known[filename] = true
+			pendingFiles[filename] = time.Now()
+		} else if info.ModTime().After(added) {
+			// Still being written
+			pendingFiles[filename] = info.ModTime()
+		}
+	}
+
+	// Forget recordings that were moved or deleted
+	for filename := range known {
+		if !present[filename] {
+			delete(known, filename)
+		}
+	}
+}
+
+// listRecordings lists the recordings in the directory, and in its
+// subdirectories when watching recursively, by path
+func (fw *FileWatcher) listRecordings() (map[string]fs.DirEntry, error) {
+	recordings := make(map[string]fs.DirEntry)
+	err := filepath.WalkDir(fw.directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == fw.directory {
+				return err
+			}
+			// An unreadable subdirectory doesn't hide the rest
+			fw.logger.Debug("FileWatcher", "Failed to list subdirectory", "directory", path, "error", err)
+			return nil
+		}
+		if entry.IsDir() {
+			if path != fw.directory && !fw.recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fw.matchesPattern(path) {
+			recordings[path] = entry
+		}
+		return nil
+	})
+	return recordings, err
+}
+
+// checkPendingFiles checks if pending files are ready for processing. Ready
+// files the processing queue has no room for are marked in waiting.
+func (fw *FileWatcher) checkPendingFiles(pendingFiles map[string]time.Time, waiting map[string]bool) {
 	now := time.Now()
 	minAge := time.Duration(fw.config.MinFileAge) * time.Second
 
@@ -212,12 +389,18 @@ func (fw *FileWatcher) checkPendingFiles(pendingFiles map[string]time.Time) {
 			if os.IsNotExist(err) {
 				// File was deleted, remove from pending
 				delete(pendingFiles, filename)
+				delete(waiting, filename)
 			} else {
 				fw.logger.Error("Error checking file info", "error", err, "file", filename)
 			}
 			continue
 		}
 
+		// Even a recording too small to process shows SDRTrunk is working
+		fw.mutex.Lock()
+		fw.lastFile = now
+		fw.mutex.Unlock()
+
 		// Check if file size is reasonable (not empty, not too small)
 		if fileInfo.Size() < 1024 { // Less than 1KB
 			fw.logger.Debug("FileWatcher", "File too small, skipping", "file", filename, "size", fileInfo.Size())
@@ -231,27 +414,63 @@ func (fw *FileWatcher) checkPendingFiles(pendingFiles map[string]time.Time) {
 			Size:      fileInfo.Size(),
 			ModTime:   fileInfo.ModTime(),
 			EventType: "new_file",
+			System:    fw.system,
+
+			FilenameFormat:  fw.format,
+			MinCallDuration: fw.minCall,
 		}
 
-		select {
-		case fw.events <- event:
-			fw.logger.Debug("FileWatcher", "New file detected", "file", filepath.Base(filename), "size", fileInfo.Size())
-		case <-fw.ctx.Done():
+		// Wait for the processor to make room rather than losing the
+		// recording. One still not queued stays pending for the next check,
+		// and the rest wait too, as the queue is full.
+		if !fw.enqueue(event) {
+			if fw.ctx.Err() == nil {
+				fw.deferred.Add(1)
+				if !waiting[filename] {
+					fw.logger.Warn("Processing queue is full, will retry recording",
+						"file", filepath.Base(filename), "waiting", len(pendingFiles))
+				}
+			}
+			waiting[filename] = true
 			return
-		default:
-			fw.logger.Warn("File events channel full, dropping event", "file", filename)
 		}
+		fw.logger.Debug("FileWatcher", "New file detected", "file", filepath.Base(filename), "size", fileInfo.Size())
 
 		// Remove from pending
 		delete(pendingFiles, filename)
+		delete(waiting, filename)
+	}
+}
+
+// enqueue hands a recording to the processor, waiting up to queue_timeout
+// for room in the queue. It reports whether the recording was queued.
+func (fw *FileWatcher) enqueue(event FileEvent) bool {
+	select {
+	case fw.events <- event:
+		return true
+	default:
 	}
+
+	wait := fw.config.QueueWait()
+	if wait <= 0 {
+		return false
+	}
+	timeout := time.NewTimer(wait)
+	defer timeout.Stop()
+	select {
+	case fw.events <- event:
+		return true
+	case <-fw.ctx.Done():
+	case <-timeout.C:
+	}
+	return false
 }
 
 // matchesPattern checks if a filename matches any of the configured patterns
 func (fw *FileWatcher) matchesPattern(filename string) bool {
 	basename := filepath.Base(filename)
 
-	for _, pattern := range fw.config.Patterns {
+	for _, pattern := range fw.patterns {
 		matched, err := filepath.Match(pattern, basename)
 		if err != nil {
 			fw.logger.Debug("FileWatcher", "Pattern match error", "pattern", pattern, "file", basename, "error", err)
@@ -265,7 +484,8 @@ func (fw *FileWatcher) matchesPattern(filename string) bool {
 	return false
 }
 
-// ScanExisting scans for existing files in the directory that haven't been processed
+// ScanExisting scans for existing files in the directory, and in its
+// subdirectories when watching recursively, that haven't been processed
 func (fw *FileWatcher) ScanExisting() ([]FileEvent, error) {
 	var events []FileEvent
 
@@ -276,6 +496,9 @@ func (fw *FileWatcher) ScanExisting() ([]FileEvent, error) {
 
 		// Skip directories
 		if info.IsDir() {
+			if path != fw.directory && !fw.recursive {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -300,6 +523,10 @@ func (fw *FileWatcher) ScanExisting() ([]FileEvent, error) {
 			Size:      info.Size(),
 			ModTime:   info.ModTime(),
 			EventType: "existing_file",
+			System:    fw.system,
+
+			FilenameFormat:  fw.format,
+			MinCallDuration: fw.minCall,
 		}
 
 		events = append(events, event)
@@ -322,20 +549,31 @@ func (fw *FileWatcher) GetStats() map[string]interface{} {
 	return map[string]interface{}{
 		"running":         fw.running,
 		"directory":       fw.directory,
-		"patterns":        fw.config.Patterns,
+		"system":          fw.system,
+		"patterns":        fw.patterns,
+		"recursive":       fw.recursive,
 		"poll_interval":   fw.config.PollInterval,
 		"min_file_age":    fw.config.MinFileAge,
 		"events_buffered": len(fw.events),
 		"errors_buffered": len(fw.errors),
+		"events_deferred": fw.deferred.Load(),
+		"events_dropped":  fw.dropped.Load(),
 	}
 }
 
+// Overflow returns how many times a recording waited out queue_timeout for
+// room in the processing queue, and how many recordings were never queued
+// because the watcher stopped
+func (fw *FileWatcher) Overflow() (deferred, dropped int64) {
+	return fw.deferred.Load(), fw.dropped.Load()
+}
+
 // UpdatePatterns updates the file patterns to watch for
 func (fw *FileWatcher) UpdatePatterns(patterns []string) {
 	fw.mutex.Lock()
 	defer fw.mutex.Unlock()
 
-	fw.config.Patterns = patterns
+	fw.patterns = patterns
 	fw.logger.Info("Updated file patterns", "patterns", patterns)
 }
 
@@ -344,6 +582,33 @@ func (fw *FileWatcher) MatchesAnyPattern(filename string) bool {
 	return fw.matchesPattern(filename)
 }
 
+// Merge combines the events of several watchers into one channel, which is
+// closed once every watcher has stopped
+func Merge(watchers ...*FileWatcher) <-chan FileEvent {
+	if len(watchers) == 1 {
+		return watchers[0].Events()
+	}
+
+	merged := make(chan FileEvent, 100)
+	var wg sync.WaitGroup
+	for _, fw := range watchers {
+		wg.Add(1)
+		go func(events <-chan FileEvent) {
+			defer wg.Done()
+			for event := range events {
+				merged <- event
+			}
+		}(fw.Events())
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	return merged
+}
+
 // GetDirectory returns the monitored directory
 func (fw *FileWatcher) GetDirectory() string {
 	return fw.directory
@@ -0,0 +1,187 @@
+package web
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/apikeys"
+	"Meiko/internal/database"
+)
+
+// apiKeyTouchInterval limits how often a key's last-used time is written
+const apiKeyTouchInterval = time.Minute
+
+// identifyAPIKey resolves the API key sent with a request, if any, so that
+// rate limiting and scope checks can use it
+func (s *Server) identifyAPIKey(c *fiber.Ctx) error {
+	if !s.config.Web.APIKeys.Enabled {
+		return c.Next()
+	}
+
+	key := requestAPIKey(c)
+	if key == "" {
+		return c.Next()
+	}
+
+	record, err := s.db.GetActiveAPIKeyByHash(apikeys.Hash(key))
+	if err != nil {
+		s.logger.Error("Failed to look up API key", "error", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to verify API key",
+			"details": err.Error(),
+		})
+	}
+	if record == nil {
+		return c.Next()
+	}
+
+	if record.LastUsedAt == nil || time.Since(*record.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.db.TouchAPIKey(record.ID, time.Now()); err != nil {
+			s.logger.Warn("Failed to record API key use", "id", record.ID, "error", err)
+		}
+	}
+
+	c.Locals("api_key", record)
+	return c.Next()
+}
+
+// requestKey returns the verified API key for a request, or nil
+func requestKey(c *fiber.Ctx) *database.APIKey {
+	record, _ := c.Locals("api_key").(*database.APIKey)
+	return record
+}
+
+// requireScope returns middleware that rejects requests without the scope.
+// Scopes granted to the public pass without a key.
+func (s *Server) requireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !s.config.Web.APIKeys.Enabled || apikeys.Allows(s.publicScopes, scope) {
+			return c.Next()
+		}
+
+		record := requestKey(c)
+		if record == nil {
+			message := "API key required"
+			if requestAPIKey(c) != "" {
+				message = "Invalid or revoked API key"
+			}
+			return c.Status(401).JSON(fiber.Map{
+				"error": message,
+				"scope": scope,
+			})
+		}
+		if !apikeys.Allows(record.Scopes, scope) {
+			return c.Status(403).JSON(fiber.Map{
+				"error": "API key does not have the required scope",
+				"scope": scope,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// isAdmin reports whether a request has admin access: with API keys, only a
+// verified key with the admin scope does. Without keys every route is open
+// to the caller, except in public mode.
+func (s *Server) isAdmin(c *fiber.Ctx) bool {
+	if !s.config.Web.APIKeys.Enabled {
+		return !s.publicMode()
+	}
+	record := requestKey(c)
+	return record != nil && apikeys.Allows(record.Scopes, apikeys.ScopeAdmin)
+}
+
+// requireKeys returns middleware that refuses a request unless API keys are
+// enabled, for actions that delete data. Without keys every visitor could
+// use them.
+func (s *Server) requireKeys(c *fiber.Ctx) error {
+	if !s.config.Web.APIKeys.Enabled {
+		return c.Status(403).JSON(fiber.Map{
+			"error":   "This action is only available with API keys enabled",
+			"details": "Set web.api_keys.enabled and use a key with the admin scope",
+		})
+	}
+	return c.Next()
+}
+
+// requestAPIKey reads an API key from the Authorization or X-API-Key header,
+// or the api_key query parameter for clients that can't set headers
+func requestAPIKey(c *fiber.Ctx) string {
+	if auth := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	if key := c.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return c.Query("api_key")
+}
+
+// getAPIKeys lists all API keys without their secrets
+func (s *Server) getAPIKeys(c *fiber.Ctx) error {
+	keys, err := s.db.GetAPIKeys()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to load API keys",
+			"details": err.Error(),
+		})
+	}
+	if keys == nil {
+		keys = []*database.APIKey{}
+	}
+
+	return c.JSON(fiber.Map{
+		"keys":   keys,
+		"scopes": apikeys.Scopes,
+	})
+}
+
+// createAPIKey creates a key and returns it. The key is only ever shown here.
+func (s *Server) createAPIKey(c *fiber.Ctx) error {
+	var request struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	record, key, err := apikeys.Create(s.db, request.Name, request.Scopes)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Failed to create API key",
+			"details": err.Error(),
+		})
+	}
+
+	s.logger.Info("API key created", "id", record.ID, "name", record.Name, "scopes", strings.Join(record.Scopes, ","))
+	return c.Status(201).JSON(fiber.Map{
+		"key":     key,
+		"api_key": record,
+	})
+}
+
+// revokeAPIKey revokes a key by ID
+func (s *Server) revokeAPIKey(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid API key ID",
+		})
+	}
+
+	if err := s.db.RevokeAPIKey(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error":   "Failed to revoke API key",
+			"details": err.Error(),
+		})
+	}
+
+	s.logger.Info("API key revoked", "id", id)
+	return c.JSON(fiber.Map{"revoked": id})
+}
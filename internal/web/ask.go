@@ -0,0 +1,181 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+)
+
+const (
+	// askSearchLimit is how many matching calls are given to the LLM
+	askSearchLimit = 40
+	// askRecentLimit is how many recent calls are used when the question has
+	// no searchable terms or nothing matches them
+	askRecentLimit = 25
+	// maxQuestionLength bounds the question sent to the LLM
+	maxQuestionLength = 500
+)
+
+// askStopWords are question words that never help find calls
+var askStopWords = map[string]bool{
+	"the": true, "and": true, "are": true, "was": true, "were": true, "there": true,
+	"any": true, "what": true, "when": true, "where": true, "which": true, "who": true,
+	"how": true, "many": true, "much": true, "did": true, "does": true, "has": true,
+	"have": true, "had": true, "been": true, "for": true, "from": true, "with": true,
+	"about": true, "into": true, "this": true, "that": true, "these": true, "those": true,
+	"today": true, "yesterday": true, "tonight": true, "last": true, "night": true,
+	"morning": true, "afternoon": true, "evening": true, "week": true, "hour": true,
+	"hours": true, "happened": true, "happening": true, "going": true, "calls": true,
+	"call": true, "scanner": true, "radio": true, "side": true, "tell": true, "can": true,
+	"you": true, "all": true, "some": true, "near": true, "around": true, "recent": true,
+	"recently": true, "anything": true, "out": true, "show": true,
+}
+
+// citationPattern matches call citations such as [#123] in an answer
+var citationPattern = regexp.MustCompile(`\[#(\d+)\]`)
+
+// askTerms extracts the search terms from a question
+func askTerms(question string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 3 && !unicode.IsDigit(rune(word[0])) {
+			continue
+		}
+		if askStopWords[word] || slices.Contains(terms, word) {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// askQuestion answers a natural-language question about recent calls. The
+// calls most relevant to the question are found with the full-text index and
+// given to the LLM, which cites the calls its answer is based on.
+// Body: question, and range (a time range such as "today" or "week", the default).
+func (s *Server) askQuestion(c *fiber.Ctx) error {
+	if s.llm == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "AI is not configured",
+		})
+	}
+
+	var req struct {
+		Question string `json:"question"`
+		Range    string `json:"range"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "question is required",
+		})
+	}
+	if len(req.Question) > maxQuestionLength {
+		return c.Status(400).JSON(fiber.Map{
+			"error": fmt.Sprintf("question must be at most %d characters", maxQuestionLength),
+		})
+	}
+	if req.Range == "" {
+		req.Range = "week"
+	}
+
+	tr, err := s.parseTimeRange(req.Range)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid time range",
+			"details": err.Error(),
+		})
+	}
+
+	calls, err := s.db.SearchCalls(askTerms(req.Question), tr.Start, tr.End, askSearchLimit)
+	if err == nil && len(calls) == 0 {
+		calls, err = s.db.GetCallRecords(database.CallFilter{Start: &tr.Start, End: &tr.End}, askRecentLimit, 0)
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to search calls",
+			"details": err.Error(),
+		})
+	}
+
+	if len(calls) == 0 {
+		return c.JSON(fiber.Map{
+			"question": req.Question,
+			"answer":   "There were no calls in this time range to answer from.",
+			"calls":    []CallRecord{},
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.config.LLM.Timeout)*time.Second)
+	defer cancel()
+
+	answer, err := s.llm.Generate(ctx, buildAskPrompt(req.Question, calls))
+	if err != nil {
+		s.logger.Error("Failed to answer question", "error", err)
+		return c.Status(502).JSON(fiber.Map{
+			"error":   "Failed to generate answer",
+			"details": err.Error(),
+		})
+	}
+
+	// Only calls the answer cites are returned, so the dashboard can link them
+	cited := make(map[int]bool)
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		id, _ := strconv.Atoi(match[1])
+		cited[id] = true
+	}
+	sources := []CallRecord{}
+	for _, call := range calls {
+		if cited[call.ID] {
+			sources = append(sources, s.apiCall(call))
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"question": req.Question,
+		"answer":   s.redact("", strings.TrimSpace(answer)),
+		"calls":    sources,
+		"searched": len(calls),
+	})
+}
+
+// buildAskPrompt asks the LLM to answer a question from a set of calls
+func buildAskPrompt(question string, calls []*database.CallRecord) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `You answer questions about public safety radio traffic using only the scanner calls below.
+The current time is %s.
+Cite every call your answer relies on by its ID in the form [#123]. If the calls don't answer the question, say so.
+Keep the answer short and factual.
+
+Question: %s
+
+Calls:
+`, time.Now().Format("Monday, January 2, 2006 15:04 MST"), question)
+
+	for _, call := range calls {
+		text := call.Transcription
+		if call.Translation != "" {
+			text = call.Translation
+		}
+		fmt.Fprintf(&b, "[#%d] %s - %s: %s\n", call.ID, call.Timestamp.Format("Mon Jan 2 15:04"), call.TalkgroupAlias, text)
+	}
+
+	return b.String()
+}
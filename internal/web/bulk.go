@@ -0,0 +1,216 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+)
+
+const (
+	// maxBulkCalls caps how many calls one bulk operation may touch
+	maxBulkCalls = 50000
+	// bulkSampleSize is how many matching calls a dry run lists
+	bulkSampleSize = 20
+)
+
+// CallReprocessor re-runs processing steps on stored calls
+type CallReprocessor interface {
+	Retranscribe(ctx context.Context, call *database.CallRecord) error
+	Reclassify(call *database.CallRecord) (bool, error)
+	Renotify(call *database.CallRecord) bool
+}
+
+// SetReprocessor sets the processor used by bulk call operations
+func (s *Server) SetReprocessor(reprocessor CallReprocessor) {
+	s.reprocessor = reprocessor
+}
+
+// bulkCalls applies an action to every call matching a filter: delete,
+// retranscribe, reclassify or renotify. A time range is required so a
+// mistyped filter can't reach the whole history, and dry_run=true reports
+// what would be affected without changing anything.
+func (s *Server) bulkCalls(c *fiber.Ctx) error {
+	action := c.Params("action")
+	switch action {
+	case "delete", "retranscribe", "reclassify", "renotify":
+	default:
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid action. Use delete, retranscribe, reclassify or renotify",
+		})
+	}
+	if action != "delete" && s.reprocessor == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Call processing is not available on this server",
+		})
+	}
+
+	start, end, _, err := s.exportRange(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	filter, err := s.callFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	filter.Start = &start
+	filter.End = &end
+	dryRun := c.QueryBool("dry_run", false)
+
+	total, err := s.db.CountCallRecords(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to count call records",
+			"details": err.Error(),
+		})
+	}
+	if total > maxBulkCalls {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Too many calls for one bulk operation",
+			"details": fmt.Sprintf("%d calls match; narrow the filter to at most %d", total, maxBulkCalls),
+		})
+	}
+
+	if dryRun {
+		calls, err := s.db.GetCallRecords(filter, bulkSampleSize, 0)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"error":   "Failed to fetch call records",
+				"details": err.Error(),
+			})
+		}
+		sample := make([]CallRecord, len(calls))
+		for i, call := range calls {
+			sample[i] = newCallRecord(call)
+		}
+		return c.JSON(fiber.Map{
+			"action":  action,
+			"dry_run": true,
+			"matched": total,
+			"sample":  sample,
+		})
+	}
+
+	// Jobs that call out to transcription or notification services take a
+	// while, so only one runs at a time
+	if (action == "retranscribe" || action == "renotify") && !s.bulkRunning.CompareAndSwap(false, true) {
+		return c.Status(409).JSON(fiber.Map{
+			"error": "Another bulk operation is still running",
+		})
+	}
+
+	calls, err := s.exportedCalls(filter)
+	if err != nil {
+		s.bulkRunning.Store(false)
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to load call records",
+			"details": err.Error(),
+		})
+	}
+
+	switch action {
+	case "delete":
+		return s.bulkDelete(c, calls)
+	case "reclassify":
+		return s.bulkReclassify(c, calls)
+	case "retranscribe":
+		go s.bulkRetranscribe(calls)
+	case "renotify":
+		go s.bulkRenotify(calls)
+	}
+
+	s.logger.Info("Started bulk call operation", "action", action, "calls", len(calls))
+	return c.Status(202).JSON(fiber.Map{
+		"action":  action,
+		"matched": total,
+		"queued":  len(calls),
+	})
+}
+
+// bulkDelete deletes calls along with their recordings
+func (s *Server) bulkDelete(c *fiber.Ctx, calls []*database.CallRecord) error {
+	if err := s.db.DeleteCalls(calls); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to delete calls",
+			"details": err.Error(),
+		})
+	}
+
+	removed := 0
+	for _, call := range calls {
+		err := os.Remove(call.Filepath)
+		if err == nil {
+			removed++
+		} else if !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn("Failed to remove recording of deleted call", "call_id", call.ID, "file", call.Filepath, "error", err)
+		}
+	}
+	s.clearTimelineCache()
+
+	s.logger.Info("Bulk deleted calls", "calls", len(calls), "recordings", removed)
+	return c.JSON(fiber.Map{
+		"action":     "delete",
+		"deleted":    len(calls),
+		"recordings": removed,
+	})
+}
+
+// bulkReclassify looks the calls' talkgroups up again in the current playlist
+func (s *Server) bulkReclassify(c *fiber.Ctx, calls []*database.CallRecord) error {
+	updated := 0
+	for _, call := range calls {
+		changed, err := s.reprocessor.Reclassify(call)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"error":   "Failed to reclassify calls",
+				"details": err.Error(),
+				"updated": updated,
+			})
+		}
+		if changed {
+			updated++
+		}
+	}
+	s.clearTimelineCache()
+
+	s.logger.Info("Bulk reclassified calls", "calls", len(calls), "updated", updated)
+	return c.JSON(fiber.Map{
+		"action":  "reclassify",
+		"matched": len(calls),
+		"updated": updated,
+	})
+}
+
+// bulkRetranscribe transcribes calls again one at a time in the background
+func (s *Server) bulkRetranscribe(calls []*database.CallRecord) {
+	defer s.bulkRunning.Store(false)
+
+	failed := 0
+	for _, call := range calls {
+		if err := s.reprocessor.Retranscribe(context.Background(), call); err != nil {
+			s.logger.Warn("Failed to retranscribe call", "call_id", call.ID, "error", err)
+			failed++
+		}
+	}
+	s.clearTimelineCache()
+
+	s.logger.Info("Bulk retranscription finished", "calls", len(calls), "failed", failed)
+}
+
+// bulkRenotify sends the calls' notifications again in the background
+func (s *Server) bulkRenotify(calls []*database.CallRecord) {
+	defer s.bulkRunning.Store(false)
+
+	sent := 0
+	for _, call := range calls {
+		if s.reprocessor.Renotify(call) {
+			sent++
+		}
+	}
+
+	s.logger.Info("Bulk renotification finished", "calls", len(calls), "sent", sent)
+}
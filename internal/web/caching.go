@@ -0,0 +1,52 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// revalidate returns middleware that lets clients reuse their copy of a GET
+// response. Each response gets a weak ETag from its body, and a request whose
+// If-None-Match matches is answered 304 without the body. Responses are marked
+// private and must be revalidated, since they depend on the API key.
+//
+// With lastModified, responses also carry the time call data last changed,
+// and a request with If-Modified-Since but no ETag is answered 304 without
+// running the handler when nothing has changed since. Only use it where the
+// response depends on nothing but stored calls and events.
+func (s *Server) revalidate(lastModified bool) fiber.Handler {
+	tag := etag.New(etag.Config{Weak: true})
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "private, no-cache")
+
+		if lastModified {
+			modified := s.lastModified()
+			c.Set(fiber.HeaderLastModified, modified.Format(http.TimeFormat))
+
+			if c.Get(fiber.HeaderIfNoneMatch) == "" {
+				since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+				if err == nil && !modified.After(since) {
+					return c.SendStatus(fiber.StatusNotModified)
+				}
+			}
+		}
+
+		return tag(c)
+	}
+}
+
+// lastModified returns when call data or system events last changed, to the
+// second as HTTP dates are
+func (s *Server) lastModified() time.Time {
+	return time.Unix(s.dataModified.Load(), 0).UTC()
+}
+
+// dataChanged records that calls or system events changed, so clients
+// revalidating with If-Modified-Since get the new data
+func (s *Server) dataChanged() {
+	s.dataModified.Store(time.Now().Unix())
+}
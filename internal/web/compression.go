@@ -0,0 +1,43 @@
+package web
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+)
+
+// compressionLevels maps web.compression.level to the middleware's levels
+var compressionLevels = map[string]compress.Level{
+	"speed":   compress.LevelBestSpeed,
+	"default": compress.LevelDefault,
+	"best":    compress.LevelBestCompression,
+}
+
+// compression returns middleware that compresses responses with Brotli, gzip
+// or deflate, whichever the client accepts first, or nil when disabled
+func (s *Server) compression() fiber.Handler {
+	cfg := s.config.Web.Compression
+	if !cfg.IsEnabled() {
+		return nil
+	}
+
+	return compress.New(compress.Config{
+		Next:  skipCompression,
+		Level: compressionLevels[cfg.Level],
+	})
+}
+
+// skipCompression leaves alone responses that are already compressed or are
+// streamed: call audio, which also serves byte ranges for seeking, and the
+// event stream, which must reach clients as each event is written
+func skipCompression(c *fiber.Ctx) bool {
+	path := unversionedPath(c.Path())
+	switch {
+	case strings.HasPrefix(path, "/api/calls/") && strings.HasSuffix(path, "/audio"):
+		return true
+	case path == "/api/events", strings.HasPrefix(path, "/ws"):
+		return true
+	}
+	return false
+}
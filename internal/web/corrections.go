@@ -0,0 +1,184 @@
+package web
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/corrections"
+	"Meiko/internal/database"
+	"Meiko/internal/redaction"
+)
+
+// correctionRuleRequest is the request body for creating or updating a rule
+type correctionRuleRequest struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+	IsRegex     bool   `json:"is_regex"`
+	TalkgroupID string `json:"talkgroup_id"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+}
+
+// SetCorrections sets the correction engine managed by the API
+func (s *Server) SetCorrections(engine *corrections.Engine) {
+	s.corrections = engine
+}
+
+// SetRedaction sets the rules that hide sensitive details in the calls the
+// API returns
+func (s *Server) SetRedaction(redactor *redaction.Redactor) {
+	s.redaction = redactor
+}
+
+// getCorrectionRules returns all database-managed correction rules
+func (s *Server) getCorrectionRules(c *fiber.Ctx) error {
+	if s.corrections == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Correction engine is not enabled"})
+	}
+
+	rules, err := s.db.GetCorrectionRules()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch correction rules",
+			"details": err.Error(),
+		})
+	}
+	if rules == nil {
+		rules = []*database.CorrectionRule{}
+	}
+
+	return c.JSON(fiber.Map{
+		"rules":        rules,
+		"config_rules": s.config.Corrections.Rules,
+		"active_count": s.corrections.RuleCount(),
+	})
+}
+
+// createCorrectionRule adds a new correction rule
+func (s *Server) createCorrectionRule(c *fiber.Ctx) error {
+	if s.corrections == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Correction engine is not enabled"})
+	}
+
+	var req correctionRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	rule := &database.CorrectionRule{
+		Pattern:     req.Pattern,
+		Replacement: req.Replacement,
+		IsRegex:     req.IsRegex,
+		TalkgroupID: req.TalkgroupID,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+
+	if _, err := corrections.Compile(rule.Pattern, rule.IsRegex); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid pattern",
+			"details": err.Error(),
+		})
+	}
+
+	if err := s.db.InsertCorrectionRule(rule); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to create correction rule",
+			"details": err.Error(),
+		})
+	}
+
+	s.reloadCorrections()
+	return c.Status(201).JSON(rule)
+}
+
+// updateCorrectionRule replaces an existing correction rule
+func (s *Server) updateCorrectionRule(c *fiber.Ctx) error {
+	if s.corrections == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Correction engine is not enabled"})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	var req correctionRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	rule := &database.CorrectionRule{
+		ID:          id,
+		Pattern:     req.Pattern,
+		Replacement: req.Replacement,
+		IsRegex:     req.IsRegex,
+		TalkgroupID: req.TalkgroupID,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+
+	if _, err := corrections.Compile(rule.Pattern, rule.IsRegex); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid pattern",
+			"details": err.Error(),
+		})
+	}
+
+	if err := s.db.UpdateCorrectionRule(rule); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error":   "Failed to update correction rule",
+			"details": err.Error(),
+		})
+	}
+
+	s.reloadCorrections()
+	return c.JSON(rule)
+}
+
+// deleteCorrectionRule removes a correction rule
+func (s *Server) deleteCorrectionRule(c *fiber.Ctx) error {
+	if s.corrections == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Correction engine is not enabled"})
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid rule ID"})
+	}
+
+	if err := s.db.DeleteCorrectionRule(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error":   "Failed to delete correction rule",
+			"details": err.Error(),
+		})
+	}
+
+	s.reloadCorrections()
+	return c.JSON(fiber.Map{"deleted": id})
+}
+
+// testCorrections previews the effect of the active rules on a piece of text
+func (s *Server) testCorrections(c *fiber.Ctx) error {
+	if s.corrections == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Correction engine is not enabled"})
+	}
+
+	var req struct {
+		Text        string `json:"text"`
+		TalkgroupID string `json:"talkgroup_id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	return c.JSON(fiber.Map{
+		"original":  req.Text,
+		"corrected": s.corrections.Apply(req.TalkgroupID, req.Text),
+	})
+}
+
+// reloadCorrections recompiles the engine after a rule change
+func (s *Server) reloadCorrections() {
+	if err := s.corrections.Reload(); err != nil {
+		s.logger.Error("Failed to reload correction rules", "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package web
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// corsExposedHeaders are response headers cross-origin clients may read:
+// pagination links, API deprecation notices, caching and rate limits
+const corsExposedHeaders = "Link,Deprecation,Sunset,ETag,Last-Modified,Retry-After"
+
+// cors returns middleware applying the configured CORS policies. A route
+// policy applies to the paths under its prefix, the longest prefix winning,
+// and the default policy to everything else. Prefixes match with or without
+// the API version.
+func (s *Server) cors() fiber.Handler {
+	cfg := s.config.Web.CORS
+
+	type routePolicy struct {
+		prefix  string
+		handler fiber.Handler
+	}
+	routes := make([]routePolicy, 0, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		routes = append(routes, routePolicy{
+			prefix:  strings.TrimRight(unversionedPath(route.Path), "/"),
+			handler: corsPolicy(route.AllowOrigins, route.AllowCredentials, cfg.MaxAge),
+		})
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].prefix) > len(routes[j].prefix)
+	})
+	fallback := corsPolicy(cfg.AllowOrigins, cfg.AllowCredentials, cfg.MaxAge)
+
+	return func(c *fiber.Ctx) error {
+		path := unversionedPath(c.Path())
+		for _, route := range routes {
+			if path == route.prefix || strings.HasPrefix(path, route.prefix+"/") {
+				return route.handler(c)
+			}
+		}
+		return fallback(c)
+	}
+}
+
+// corsPolicy returns middleware letting pages on the given origins call the
+// API. Without origins no CORS headers are sent, so browsers only allow
+// same-origin requests.
+func corsPolicy(origins []string, credentials bool, maxAge int) fiber.Handler {
+	if len(origins) == 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return cors.New(cors.Config{
+		AllowOrigins:     strings.Join(origins, ","),
+		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-API-Key,X-Client-Token",
+		AllowCredentials: credentials,
+		ExposeHeaders:    corsExposedHeaders,
+		MaxAge:           maxAge,
+	})
+}
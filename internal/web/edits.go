@@ -0,0 +1,171 @@
+package web
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+)
+
+// callEditRequest holds the corrections to a call. Omitted fields are left
+// alone.
+type callEditRequest struct {
+	Transcription *string `json:"transcription"`
+	TalkgroupID   *string `json:"talkgroup_id"`
+	Noise         *bool   `json:"noise"` // false clears a noise mark, treating the call as speech
+}
+
+// editCall corrects a call's transcription or talkgroup, or marks it as
+// noise, recording each change in the call's audit log
+func (s *Server) editCall(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid call ID",
+		})
+	}
+
+	var req callEditRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+	if req.Transcription == nil && req.TalkgroupID == nil && req.Noise == nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Nothing to change. Set transcription, talkgroup_id or noise",
+		})
+	}
+
+	call, err := s.db.GetCallRecord(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Call record not found",
+		})
+	}
+
+	edited := *call
+	if req.Transcription != nil {
+		edited.Transcription = strings.TrimSpace(*req.Transcription)
+	}
+	if req.TalkgroupID != nil {
+		talkgroupID := strings.TrimSpace(*req.TalkgroupID)
+		if talkgroupID == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "talkgroup_id cannot be empty",
+			})
+		}
+		if talkgroupID != call.TalkgroupID {
+			edited.TalkgroupID = talkgroupID
+			edited.TalkgroupAlias = "TG " + talkgroupID
+			edited.TalkgroupGroup = ""
+			if s.talkgroups != nil {
+				edited.TalkgroupAlias = s.talkgroups.FormatTalkgroupDisplay(talkgroupID)
+				edited.TalkgroupGroup = s.talkgroups.FormatTalkgroupGroup(talkgroupID)
+			}
+		}
+	}
+	if req.Noise != nil {
+		if *req.Noise {
+			edited.AudioClass = database.AudioClassNoise
+		} else {
+			edited.AudioClass = ""
+		}
+	}
+
+	edits, err := s.db.EditCall(call, &edited, s.editor(c))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to edit call",
+			"details": err.Error(),
+		})
+	}
+	if edits == nil {
+		edits = []*database.CallEdit{}
+	}
+
+	if len(edits) > 0 {
+		s.clearTimelineCache()
+		s.BroadcastMessage(MessageCallUpdated, s.apiCall(&edited))
+
+		// Keep semantic search in step with the corrected transcription
+		if s.embeddings != nil && edited.Transcription != call.Transcription {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := s.embeddings.EmbedCall(ctx, &edited); err != nil {
+				s.logger.Warn("Failed to embed edited call", "call_id", id, "error", err)
+			}
+		}
+
+		s.logger.Info("Call edited", "call_id", id, "fields", len(edits), "editor", s.editor(c))
+	}
+
+	return c.JSON(fiber.Map{
+		"call":  s.apiCall(&edited),
+		"edits": s.apiEdits(c, edited.TalkgroupID, edits),
+	})
+}
+
+// getCallEdits returns the audit log of a call's manual corrections
+func (s *Server) getCallEdits(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid call ID",
+		})
+	}
+
+	call, err := s.db.GetCallRecord(id)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Call record not found",
+		})
+	}
+
+	edits, err := s.db.GetCallEdits(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to load call edits",
+			"details": err.Error(),
+		})
+	}
+	if edits == nil {
+		edits = []*database.CallEdit{}
+	}
+
+	return c.JSON(fiber.Map{"edits": s.apiEdits(c, call.TalkgroupID, edits)})
+}
+
+// apiEdits returns a call's edits with the transcriptions redacted like the
+// call's own, unless the request has admin access
+func (s *Server) apiEdits(c *fiber.Ctx, talkgroupID string, edits []*database.CallEdit) []*database.CallEdit {
+	if s.isAdmin(c) {
+		return edits
+	}
+
+	redacted := make([]*database.CallEdit, len(edits))
+	for i, edit := range edits {
+		if edit.Field == "transcription" {
+			copied := *edit
+			copied.OldValue = s.redact(talkgroupID, edit.OldValue)
+			copied.NewValue = s.redact(talkgroupID, edit.NewValue)
+			edit = &copied
+		}
+		redacted[i] = edit
+	}
+	return redacted
+}
+
+// editor names who made a request for the audit log: the API key's name, or
+// the client address when API keys are disabled
+func (s *Server) editor(c *fiber.Ctx) string {
+	if record := requestKey(c); record != nil {
+		return record.Name
+	}
+	return s.clientIP(c)
+}
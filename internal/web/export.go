@@ -0,0 +1,276 @@
+package web
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+)
+
+// maxExportCalls caps a single export; larger ranges should be split up
+const maxExportCalls = 50000
+
+// exportBatchSize is how many calls are read from the database at a time
+const exportBatchSize = 500
+
+// exportColumns are the CSV header fields, in order
+var exportColumns = []string{
+	"id", "timestamp", "duration", "frequency", "talkgroup_id", "talkgroup_alias",
+	"talkgroup_group", "system_id", "site", "severity", "priority", "filename", "audio", "transcription",
+}
+
+// exportCall is a call as written to an export, with the audio file's path in
+// the ZIP bundle when audio is included
+type exportCall struct {
+	CallRecord
+	Audio string `json:"audio,omitempty"`
+}
+
+// exportCalls downloads the calls in a time range as CSV or JSON Lines,
+// optionally zipped with their audio. The range is given by date (YYYY-MM-DD),
+// range (as for /api/calls) or start and end (RFC3339). Query parameters:
+// format (csv or jsonl), audio (true to bundle recordings), talkgroup and system.
+func (s *Server) exportCalls(c *fiber.Ctx) error {
+	start, end, label, err := s.exportRange(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid export range",
+			"details": err.Error(),
+		})
+	}
+
+	end = s.publicEnd(end)
+
+	format := c.Query("format", "csv")
+	if format != "csv" && format != "jsonl" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid format. Use csv or jsonl",
+		})
+	}
+	talkgroupID := c.Query("talkgroup", "")
+	systemID := c.Query("system", "")
+
+	filter := database.CallFilter{Start: &start, End: &end, TalkgroupID: talkgroupID, SystemID: systemID}
+	total, err := s.db.CountCallRecords(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to count call records",
+			"details": err.Error(),
+		})
+	}
+	if total > maxExportCalls {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Too many calls to export",
+			"details": fmt.Sprintf("%d calls match; narrow the range to at most %d", total, maxExportCalls),
+		})
+	}
+
+	calls, err := s.exportedCalls(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to load call records",
+			"details": err.Error(),
+		})
+	}
+
+	filename := "meiko-calls-" + label
+	withAudio := c.QueryBool("audio", false)
+	if withAudio {
+		c.Set(fiber.HeaderContentType, "application/zip")
+		c.Attachment(filename + ".zip")
+	} else if format == "csv" {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Attachment(filename + ".csv")
+	} else {
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+		c.Attachment(filename + ".jsonl")
+	}
+
+	s.logger.Info("Exporting calls", "calls", len(calls), "format", format, "audio", withAudio, "range", label)
+
+	// Stream the response so large audio bundles aren't held in memory
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var err error
+		if withAudio {
+			err = s.writeExportBundle(w, calls, format, filename+"."+format)
+		} else {
+			exported := make([]exportCall, len(calls))
+			for i, call := range calls {
+				exported[i] = exportCall{CallRecord: s.apiCall(call)}
+			}
+			err = writeExport(w, exported, format)
+		}
+		if err != nil {
+			s.logger.Error("Failed to write export", "error", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// exportRange parses the export's time range and returns a label for the filename
+func (s *Server) exportRange(c *fiber.Ctx) (time.Time, time.Time, string, error) {
+	if date := c.Query("date"); date != "" {
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("date must be YYYY-MM-DD")
+		}
+		return day, day.AddDate(0, 0, 1), date, nil
+	}
+
+	if rangeParam := c.Query("range"); rangeParam != "" {
+		tr, err := s.parseTimeRange(rangeParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", err
+		}
+		return tr.Start, tr.End, rangeParam + "-" + tr.End.Format("20060102-1504"), nil
+	}
+
+	if c.Query("start") != "" && c.Query("end") != "" {
+		start, err := time.Parse(time.RFC3339, c.Query("start"))
+		if err != nil {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("start must be an RFC3339 timestamp")
+		}
+		end, err := time.Parse(time.RFC3339, c.Query("end"))
+		if err != nil {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("end must be an RFC3339 timestamp")
+		}
+		if !end.After(start) {
+			return time.Time{}, time.Time{}, "", fmt.Errorf("end must be after start")
+		}
+		return start, end, start.Format("20060102-1504") + "-" + end.Format("20060102-1504"), nil
+	}
+
+	return time.Time{}, time.Time{}, "", fmt.Errorf("specify date, range, or start and end")
+}
+
+// exportedCalls loads every call matching the filter, oldest first
+func (s *Server) exportedCalls(filter database.CallFilter) ([]*database.CallRecord, error) {
+	var calls []*database.CallRecord
+	var cursor *database.CallCursor
+	for {
+		batch, err := s.db.GetCallRecordsAfter(filter, cursor, exportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, batch...)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		cursor = &database.CallCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	for i, j := 0, len(calls)-1; i < j; i, j = i+1, j-1 {
+		calls[i], calls[j] = calls[j], calls[i]
+	}
+	return calls, nil
+}
+
+// writeExport writes calls as CSV or JSON Lines
+func writeExport(w io.Writer, calls []exportCall, format string) error {
+	if format == "jsonl" {
+		encoder := json.NewEncoder(w)
+		for _, call := range calls {
+			if err := encoder.Encode(call); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, call := range calls {
+		record := []string{
+			strconv.Itoa(call.ID),
+			call.Timestamp.Format(time.RFC3339),
+			strconv.Itoa(call.Duration),
+			call.Frequency,
+			call.TalkgroupID,
+			call.TalkgroupAlias,
+			call.TalkgroupGroup,
+			call.SystemID,
+			call.Site,
+			strconv.Itoa(call.Severity),
+			strconv.Itoa(call.Priority),
+			call.Filename,
+			call.Audio,
+			call.Transcription,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeExportBundle writes a ZIP holding the call list and, under audio/, the
+// recordings. Recordings that no longer exist are left out of the bundle and
+// have no audio path in the list.
+func (s *Server) writeExportBundle(w io.Writer, calls []*database.CallRecord, format, listName string) error {
+	archive := zip.NewWriter(w)
+
+	exported := make([]exportCall, len(calls))
+	used := make(map[string]bool)
+	for i, call := range calls {
+		exported[i] = exportCall{CallRecord: s.apiCall(call)}
+
+		name := "audio/" + call.Filename
+		if used[name] {
+			// The same filename can come from different sites
+			name = fmt.Sprintf("audio/%d_%s", call.ID, call.Filename)
+		}
+		if err := addExportAudio(archive, name, call); err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+			s.logger.Warn("Recording missing from export", "call_id", call.ID, "file", call.Filepath)
+			continue
+		}
+		used[name] = true
+		exported[i].Audio = name
+	}
+
+	list, err := archive.Create(listName)
+	if err != nil {
+		return err
+	}
+	if err := writeExport(list, exported, format); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// addExportAudio copies a recording into the bundle. Audio is already
+// compressed, so it is stored rather than deflated.
+func addExportAudio(archive *zip.Writer, name string, call *database.CallRecord) error {
+	file, err := os.Open(call.Filepath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: call.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
@@ -0,0 +1,297 @@
+package web
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+)
+
+const (
+	// maxFeedIncidents caps the incidents listed in a feed
+	maxFeedIncidents = 200
+	// feedMaxAge is how long clients and proxies may cache a feed, in seconds
+	feedMaxAge = 300
+)
+
+// feedItem is an incident or daily summary, rendered as an Atom entry or an
+// iCal event
+type feedItem struct {
+	ID       string
+	Title    string
+	Text     string
+	Category string
+	Start    time.Time
+	End      time.Time
+	Updated  time.Time
+	AllDay   bool // A summary covers a whole day
+}
+
+// getIncidentFeed serves the major incidents of the last few days as Atom
+// or iCal. It needs no API key, so community members can subscribe.
+func (s *Server) getIncidentFeed(c *fiber.Ctx) error {
+	cfg := s.config.Web.Feeds
+	end := s.publicNow()
+	start := end.AddDate(0, 0, -cfg.Days)
+
+	calls, err := s.db.GetCallRecords(database.CallFilter{Start: &start, End: &end, MinPriority: cfg.MinPriority}, maxFeedIncidents, 0)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch incidents",
+			"details": err.Error(),
+		})
+	}
+
+	items := make([]feedItem, len(calls))
+	for i, call := range calls {
+		items[i] = s.incidentItem(call)
+	}
+	return s.renderFeed(c, cfg.Title+" • Major incidents", "incidents", items)
+}
+
+// getSummaryFeed serves the daily AI summaries of the last few days as Atom
+// or iCal all-day events
+func (s *Server) getSummaryFeed(c *fiber.Ctx) error {
+	cfg := s.config.Web.Feeds
+	end := time.Now()
+	start := end.AddDate(0, 0, -cfg.Days)
+
+	summaries, err := s.db.GetSummaries("daily", start, end, cfg.Days*4)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch summaries",
+			"details": err.Error(),
+		})
+	}
+
+	// Daily summaries cover one day; the newest summary of each day is listed
+	seen := make(map[string]bool)
+	var items []feedItem
+	for _, summary := range summaries {
+		length := summary.EndTime.Sub(summary.StartTime)
+		if length < 23*time.Hour || length > 25*time.Hour {
+			continue
+		}
+		day := summary.StartTime.Format("2006-01-02")
+		if seen[day] {
+			continue
+		}
+		seen[day] = true
+
+		items = append(items, feedItem{
+			ID:       "summary:" + day,
+			Title:    fmt.Sprintf("Daily summary • %s (%d calls)", summary.StartTime.Format("Monday, January 2"), summary.CallCount),
+			Text:     s.redact("", summary.Summary),
+			Category: "summary",
+			Start:    summary.StartTime,
+			End:      summary.EndTime,
+			Updated:  summary.GeneratedAt,
+			AllDay:   true,
+		})
+	}
+	return s.renderFeed(c, cfg.Title+" • Daily summaries", "summaries", items)
+}
+
+// incidentItem describes a call as a feed item, titled with the incident
+// details extracted by enrichment when it has them
+func (s *Server) incidentItem(call *database.CallRecord) feedItem {
+	title := call.TalkgroupAlias
+	category := "incident"
+	if call.Enrichment != nil && call.Enrichment.IncidentType != "" {
+		category = call.Enrichment.IncidentType
+		title = strings.ToUpper(category[:1]) + strings.ReplaceAll(category[1:], "_", " ")
+		if call.Enrichment.Location != "" {
+			title += " at " + call.Enrichment.Location
+		}
+		title += " • " + call.TalkgroupAlias
+	}
+
+	text := s.redact(call.TalkgroupID, call.Transcription)
+	if call.Translation != "" {
+		text = s.redact(call.TalkgroupID, call.Translation)
+	}
+
+	return feedItem{
+		ID:       fmt.Sprintf("call:%d", call.ID),
+		Title:    title,
+		Text:     text,
+		Category: category,
+		Start:    call.Timestamp,
+		End:      call.Timestamp.Add(time.Duration(max(call.Duration, 60)) * time.Second),
+		Updated:  call.Timestamp,
+	}
+}
+
+// renderFeed writes items in the format named by the :format parameter
+func (s *Server) renderFeed(c *fiber.Ctx, title, name string, items []feedItem) error {
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", feedMaxAge))
+
+	switch c.Params("format") {
+	case "atom":
+		body, err := s.atomFeed(c, title, name, items)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"error":   "Failed to render feed",
+				"details": err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, "application/atom+xml; charset=utf-8")
+		return c.Send(body)
+	case "ics":
+		c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+		return c.SendString(icalFeed(title, items))
+	default:
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Feeds are available as .atom or .ics",
+		})
+	}
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Category  atomCategory `xml:"category"`
+	Links     []atomLink   `xml:"link,omitempty"`
+	Content   atomContent  `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// atomFeed renders items as an Atom feed, newest first
+func (s *Server) atomFeed(c *fiber.Ctx, title, name string, items []feedItem) ([]byte, error) {
+	baseURL := strings.TrimRight(s.config.Web.Feeds.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = requestBaseURL(c)
+	}
+
+	feed := atomFeed{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		ID:      baseURL + apiPrefix + "/feeds/" + name + ".atom",
+		Title:   title,
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: s.config.Web.Feeds.Title},
+		Links: []atomLink{
+			{Href: baseURL + apiPrefix + "/feeds/" + name + ".atom", Rel: "self"},
+			{Href: baseURL + "/"},
+		},
+	}
+	for _, item := range items {
+		text := item.Text
+		if text == "" {
+			text = "No transcription available"
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        baseURL + "/#" + strings.ReplaceAll(item.ID, ":", "-"),
+			Title:     item.Title,
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Published: item.Start.UTC().Format(time.RFC3339),
+			Category:  atomCategory{Term: item.Category},
+			Links:     []atomLink{{Href: baseURL + "/"}},
+			Content:   atomContent{Type: "text", Body: text},
+		})
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// icalFeed renders items as an iCalendar; summaries are all-day events
+func icalFeed(title string, items []feedItem) string {
+	var b strings.Builder
+	line := func(text string) {
+		b.WriteString(foldICalLine(text))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Meiko//Scanner Feeds//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + escapeICal(title))
+	line("REFRESH-INTERVAL;VALUE=DURATION:PT15M")
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, item := range items {
+		line("BEGIN:VEVENT")
+		line("UID:" + item.ID + "@meiko")
+		line("DTSTAMP:" + stamp)
+		if item.AllDay {
+			line("DTSTART;VALUE=DATE:" + item.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + item.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			line("DTSTART:" + item.Start.UTC().Format("20060102T150405Z"))
+			line("DTEND:" + item.End.UTC().Format("20060102T150405Z"))
+		}
+		line("LAST-MODIFIED:" + item.Updated.UTC().Format("20060102T150405Z"))
+		line("SUMMARY:" + escapeICal(item.Title))
+		if item.Text != "" {
+			line("DESCRIPTION:" + escapeICal(item.Text))
+		}
+		line("CATEGORIES:" + escapeICal(item.Category))
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeICal escapes text for an iCalendar property value
+func escapeICal(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldICalLine splits a content line longer than 75 bytes into continuation
+// lines, without splitting a UTF-8 character
+func foldICalLine(text string) string {
+	var b strings.Builder
+	limit := 75
+	for len(text) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		b.WriteString(text[:cut])
+		b.WriteString("\r\n ")
+		text = text[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	b.WriteString(text)
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+	"Meiko/internal/frequency"
+)
+
+// frequencyRequest is the request body for creating or updating a frequency
+type frequencyRequest struct {
+	Frequency string `json:"frequency"` // "851.0125", "851.0125 MHz", "851012500", ...
+	Label     string `json:"label"`
+	SystemID  string `json:"system_id"`
+	Mode      string `json:"mode"`
+}
+
+// frequencyEntry is a known frequency with its display form
+type frequencyEntry struct {
+	*database.Frequency
+	Display string `json:"display"`
+}
+
+// getFrequencies returns all known frequencies
+func (s *Server) getFrequencies(c *fiber.Ctx) error {
+	frequencies, err := s.db.GetFrequencies()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch frequencies",
+			"details": err.Error(),
+		})
+	}
+
+	entries := make([]frequencyEntry, len(frequencies))
+	for i, f := range frequencies {
+		entries[i] = frequencyEntry{Frequency: f, Display: frequency.Format(f.Frequency)}
+	}
+
+	return c.JSON(fiber.Map{
+		"frequencies": entries,
+		"count":       len(entries),
+	})
+}
+
+// createFrequency adds a known frequency
+func (s *Server) createFrequency(c *fiber.Ctx) error {
+	f, err := parseFrequencyRequest(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := s.db.InsertFrequency(f); err != nil {
+		return c.Status(409).JSON(fiber.Map{
+			"error":   "Failed to create frequency",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(frequencyEntry{Frequency: f, Display: frequency.Format(f.Frequency)})
+}
+
+// updateFrequency replaces a known frequency
+func (s *Server) updateFrequency(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid frequency ID"})
+	}
+
+	f, err := parseFrequencyRequest(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	f.ID = id
+
+	if err := s.db.UpdateFrequency(f); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error":   "Failed to update frequency",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(frequencyEntry{Frequency: f, Display: frequency.Format(f.Frequency)})
+}
+
+// deleteFrequency removes a known frequency
+func (s *Server) deleteFrequency(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid frequency ID"})
+	}
+
+	if err := s.db.DeleteFrequency(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error":   "Failed to delete frequency",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"deleted": id})
+}
+
+// parseFrequencyRequest reads and validates a frequency request body
+func parseFrequencyRequest(c *fiber.Ctx) (*database.Frequency, error) {
+	var req frequencyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, errors.New("Invalid request body")
+	}
+
+	hz, ok := frequency.Normalize(req.Frequency)
+	if !ok {
+		return nil, errors.New("Invalid frequency")
+	}
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return nil, errors.New("Label is required")
+	}
+
+	return &database.Frequency{
+		Frequency: hz,
+		Label:     label,
+		SystemID:  strings.TrimSpace(req.SystemID),
+		Mode:      strings.TrimSpace(req.Mode),
+	}, nil
+}
+
+// getFrequencyInfo returns what is known about a call's frequency
+func (s *Server) getFrequencyInfo(stored, systemID string) fiber.Map {
+	info := fiber.Map{
+		"frequency": stored,
+		"display":   frequency.Display(stored),
+		"known":     false,
+	}
+
+	hz, ok := frequency.Normalize(stored)
+	if !ok {
+		return info
+	}
+	known, err := s.db.LookupFrequency(hz, systemID)
+	if err != nil {
+		s.logger.Warn("Failed to look up frequency", "frequency", stored, "error", err)
+		return info
+	}
+	if known != nil {
+		info["known"] = true
+		info["label"] = known.Label
+		info["mode"] = known.Mode
+	}
+	return info
+}
+
+// queryFrequency reads a frequency query parameter in Hz, returning 0 when it
+// is absent
+func queryFrequency(c *fiber.Ctx, param string) (int64, error) {
+	raw := c.Query(param, "")
+	if raw == "" {
+		return 0, nil
+	}
+	hz, ok := frequency.Normalize(raw)
+	if !ok {
+		return 0, fmt.Errorf("Invalid %s. Use Hz or MHz, e.g. 851.0125", param)
+	}
+	return hz, nil
+}
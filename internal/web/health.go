@@ -0,0 +1,180 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// healthCheckTimeout bounds each component check, so a hung backend can't
+// stall the probe
+const healthCheckTimeout = 5 * time.Second
+
+// Component health states. A critical component that is down makes the whole
+// service unhealthy; anything else only degrades it.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+// HealthCheck checks one component, returning why it is unhealthy
+type HealthCheck func(ctx context.Context) error
+
+// ComponentHealth is the result of one component's check
+type ComponentHealth struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Message   string `json:"message,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// healthCheck is a registered component check
+type healthCheck struct {
+	name     string
+	critical bool
+	check    HealthCheck
+}
+
+// AddHealthCheck adds a component to /api/health. When a critical component
+// fails the endpoint returns 503; other failures report the service as
+// degraded but still return 200.
+func (s *Server) AddHealthCheck(name string, critical bool, check HealthCheck) {
+	s.healthChecksMu.Lock()
+	defer s.healthChecksMu.Unlock()
+	s.healthChecks = append(s.healthChecks, healthCheck{name: name, critical: critical, check: check})
+}
+
+// degradedError marks a component as degraded rather than down
+type degradedError struct{ message string }
+
+func (e degradedError) Error() string { return e.message }
+
+// Degraded returns an error that reports a component as degraded instead of
+// down, for a component that is partly working
+func Degraded(format string, args ...any) error {
+	return degradedError{message: fmt.Sprintf(format, args...)}
+}
+
+// builtinHealthChecks are the components the server can check itself
+func (s *Server) builtinHealthChecks() []healthCheck {
+	checks := []healthCheck{
+		{name: "database", critical: true, check: func(ctx context.Context) error { return s.db.Ping() }},
+		{name: "disk", critical: true, check: s.checkDisk},
+	}
+	if s.sdrtrunk != nil {
+		checks = append(checks, healthCheck{name: "sdrtrunk", critical: true, check: s.checkSDRTrunk})
+	}
+	return checks
+}
+
+// checkSDRTrunk reports SDRTrunk down when no process is running, and
+// degraded when only some systems are
+func (s *Server) checkSDRTrunk(ctx context.Context) error {
+	running, total := s.sdrtrunk.RunningCount()
+	switch {
+	case running == 0:
+		return fmt.Errorf("not running")
+	case running < total:
+		return Degraded("%d of %d systems running", running, total)
+	}
+	return nil
+}
+
+// checkDisk reports the disk down below the free space floor, and degraded
+// when it is projected to fill within the storage alert window
+func (s *Server) checkDisk(ctx context.Context) error {
+	usage, err := disk.Usage(filepath.Dir(s.config.Database.Path))
+	if err != nil {
+		return fmt.Errorf("failed to read disk usage: %w", err)
+	}
+	freeGB := float64(usage.Free) / (1 << 30)
+	if freeGB < s.config.Storage.MinFreeGB {
+		return fmt.Errorf("%.1f GB free, below the %.1f GB floor", freeGB, s.config.Storage.MinFreeGB)
+	}
+	if s.storage != nil {
+		if days := s.storage.Forecast().DaysUntilFull; days != nil && *days < s.config.Storage.AlertDays {
+			return Degraded("%.1f GB free, projected to fill in %.1f days", freeGB, *days)
+		}
+	}
+	return nil
+}
+
+// getHealth runs every component check and returns 200 when the service is
+// healthy or degraded, and 503 when a critical component is down, for uptime
+// monitors and container probes
+func (s *Server) getHealth(c *fiber.Ctx) error {
+	s.healthChecksMu.RLock()
+	checks := append(s.builtinHealthChecks(), s.healthChecks...)
+	s.healthChecksMu.RUnlock()
+
+	components := make([]ComponentHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = runHealthCheck(c.UserContext(), check)
+		}()
+	}
+	wg.Wait()
+
+	status := HealthOK
+	for _, component := range components {
+		if component.Status == HealthDown && component.Critical {
+			status = HealthDown
+			break
+		}
+		if component.Status != HealthOK {
+			status = HealthDegraded
+		}
+	}
+
+	code := fiber.StatusOK
+	if status == HealthDown {
+		code = fiber.StatusServiceUnavailable
+	}
+	return c.Status(code).JSON(fiber.Map{
+		"status":     status,
+		"components": components,
+		"checked_at": time.Now(),
+	})
+}
+
+// runHealthCheck runs one check under the timeout
+func runHealthCheck(ctx context.Context, check healthCheck) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out")
+	}
+
+	result := ComponentHealth{
+		Name:      check.name,
+		Status:    HealthOK,
+		Critical:  check.critical,
+		LatencyMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		result.Message = err.Error()
+		result.Status = HealthDown
+		if _, ok := err.(degradedError); ok {
+			result.Status = HealthDegraded
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package web
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// heatmapDays labels the heatmap's rows, in time.Weekday order
+var heatmapDays = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// getHeatmap returns call counts by local weekday and hour of day, for a
+// weekly activity heatmap. The range defaults to the last month and can be
+// narrowed to a system, talkgroup or service type.
+func (s *Server) getHeatmap(c *fiber.Ctx) error {
+	rangeParam := c.Query("range", "month")
+	tr, err := s.parseTimeRange(rangeParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid time range",
+			"details": err.Error(),
+		})
+	}
+
+	talkgroupIDs, err := s.serviceTalkgroups(c.Query("service_type", ""))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid filter",
+			"details": err.Error(),
+		})
+	}
+	if talkgroup := c.Query("talkgroup", ""); talkgroup != "" {
+		if talkgroupIDs == nil {
+			talkgroupIDs = []string{talkgroup}
+		} else {
+			talkgroupIDs = intersectTalkgroups(talkgroupIDs, talkgroup)
+		}
+	}
+
+	// Rollups are hourly, so the hour still in progress is left out
+	systemID := c.Query("system", "")
+	end := tr.End.Truncate(time.Hour)
+	heatmap, err := s.db.GetActivityHeatmap(tr.Start, end, systemID, talkgroupIDs)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to build activity heatmap",
+			"details": err.Error(),
+		})
+	}
+
+	var total, peak int64
+	for _, hours := range heatmap {
+		for _, calls := range hours {
+			total += calls
+			peak = max(peak, calls)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"range":  rangeParam,
+		"system": systemID,
+		"start":  tr.Start,
+		"end":    end,
+		"days":   heatmapDays,
+		"matrix": heatmap,
+		"total":  total,
+		"max":    peak,
+	})
+}
+
+// intersectTalkgroups keeps talkgroup if it is one of talkgroupIDs
+func intersectTalkgroups(talkgroupIDs []string, talkgroup string) []string {
+	for _, id := range talkgroupIDs {
+		if id == talkgroup {
+			return []string{talkgroup}
+		}
+	}
+	return []string{}
+}
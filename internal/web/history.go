@@ -0,0 +1,170 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"Meiko/internal/database"
+)
+
+// historySettle is how long after midnight a day is left alone before it is
+// rendered, so late uploads and the last hour summaries make it in
+const historySettle = 3 * time.Hour
+
+// historyDay is a past day of activity as rendered to disk
+type historyDay struct {
+	Date          string                  `json:"date"`
+	GeneratedAt   time.Time               `json:"generated_at"`
+	CallCount     int                     `json:"call_count"`
+	TotalDuration int                     `json:"total_duration"` // Seconds
+	Calls         []CallRecord            `json:"calls"`          // Oldest first
+	HourSummaries []*database.HourSummary `json:"hour_summaries"`
+}
+
+// historyIndex lists the rendered days
+type historyIndex struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Days        []string  `json:"days"` // Newest first
+}
+
+// historyRoutine renders past days now and then hourly. Rendered days are
+// served as static files from /api/history, so browsing history doesn't
+// query the database.
+func (s *Server) historyRoutine() {
+	s.renderHistory()
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.renderHistory()
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+// renderHistory renders every settled day with calls that hasn't been rendered
+// yet, then refreshes the index
+func (s *Server) renderHistory() {
+	dir := s.config.Web.History.Directory
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.logger.Error("Failed to create history directory", "error", err)
+		return
+	}
+
+	days, err := s.db.GetCallDays(time.Now().Add(-historySettle))
+	if err != nil {
+		s.logger.Error("Failed to list days to render", "error", err)
+		return
+	}
+
+	rendered := 0
+	for _, day := range days {
+		path := filepath.Join(dir, day+".json")
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := s.renderHistoryDay(day, path); err != nil {
+			s.logger.Error("Failed to render history day", "date", day, "error", err)
+			return
+		}
+		rendered++
+
+		select {
+		case <-s.closing:
+			return
+		default:
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "index.json")); rendered == 0 && err == nil {
+		return
+	}
+	if err := s.writeHistoryIndex(dir); err != nil {
+		s.logger.Error("Failed to write history index", "error", err)
+		return
+	}
+	if rendered > 0 {
+		s.logger.Info("Rendered history days", "days", rendered, "directory", dir)
+	}
+}
+
+// renderHistoryDay writes one day's calls and hour summaries
+func (s *Server) renderHistoryDay(day, path string) error {
+	start, err := time.ParseInLocation("2006-01-02", day, time.Local)
+	if err != nil {
+		return err
+	}
+	end := start.AddDate(0, 0, 1)
+
+	calls, err := s.exportedCalls(database.CallFilter{Start: &start, End: &end})
+	if err != nil {
+		return err
+	}
+	summaries, err := s.db.GetHourSummariesForDate(day)
+	if err != nil {
+		return err
+	}
+
+	rendered := historyDay{
+		Date:          day,
+		GeneratedAt:   time.Now(),
+		CallCount:     len(calls),
+		Calls:         make([]CallRecord, len(calls)),
+		HourSummaries: summaries,
+	}
+	for i, call := range calls {
+		rendered.Calls[i] = s.apiCall(call)
+		rendered.TotalDuration += call.Duration
+	}
+	if rendered.HourSummaries == nil {
+		rendered.HourSummaries = []*database.HourSummary{}
+	}
+
+	s.logger.Debug("History", "Rendered day", "date", day, "calls", len(calls))
+	return writeJSONFile(path, rendered)
+}
+
+// writeHistoryIndex lists the rendered days, newest first
+func (s *Server) writeHistoryIndex(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	index := historyIndex{GeneratedAt: time.Now(), Days: []string{}}
+	for _, entry := range entries {
+		day, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", day); err == nil {
+			index.Days = append(index.Days, day)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(index.Days)))
+
+	return writeJSONFile(filepath.Join(dir, "index.json"), index)
+}
+
+// writeJSONFile writes a value as JSON, replacing the file atomically so it
+// is never served half-written
+func writeJSONFile(path string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,82 @@
+package web
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// clientQueueSize is how many messages a real-time client can fall behind
+	// before it is disconnected as too slow
+	clientQueueSize = 256
+
+	// writeWait is how long a single write to a real-time client may take
+	writeWait = 10 * time.Second
+)
+
+// hubClient is a WebSocket or event stream connection's message queue. Only
+// the connection's own handler reads the queue and writes to the connection.
+type hubClient struct {
+	send chan hubMessage // Closed when the client is evicted or unregistered
+}
+
+// hub fans broadcast messages out to real-time clients. Publishing never
+// blocks: a client whose queue is full is evicted instead of holding up the
+// others.
+type hub struct {
+	mu      sync.Mutex
+	clients map[*hubClient]bool
+}
+
+// newHub creates an empty hub
+func newHub() *hub {
+	return &hub{clients: make(map[*hubClient]bool)}
+}
+
+// register adds a client and returns it along with the number of clients
+func (h *hub) register() (*hubClient, int) {
+	client := &hubClient{send: make(chan hubMessage, clientQueueSize)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[client] = true
+	return client, len(h.clients)
+}
+
+// unregister removes a client if it is still registered and returns the
+// number of clients left
+func (h *hub) unregister(client *hubClient) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[client] {
+		delete(h.clients, client)
+		close(client.send)
+	}
+	return len(h.clients)
+}
+
+// publish queues a message for every client and returns how many clients
+// were evicted for having a full queue
+func (h *hub) publish(message hubMessage) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	evicted := 0
+	for client := range h.clients {
+		select {
+		case client.send <- message:
+		default:
+			delete(h.clients, client)
+			close(client.send)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// count returns the number of connected clients
+func (h *hub) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
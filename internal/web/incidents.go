@@ -0,0 +1,137 @@
+package web
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"Meiko/internal/database"
+)
+
+// timelineIncidentMinSize is how many related calls make an incident worth
+// marking on the timeline
+const timelineIncidentMinSize = 3
+
+// incidentColor sets incident boundaries apart from calls and system events
+const incidentColor = "#f97316"
+
+// incidentTimelineEvents marks where each incident in the range starts and
+// ends, newest first, continuing after the cursor. Incidents are clusters of
+// related calls, so they are only found when semantic search is enabled.
+// Each boundary is positioned by its timestamp and an ID derived from the
+// incident's first call, twice it for the start and one more for the end.
+func (s *Server) incidentTimelineEvents(start, end time.Time, cursor *database.CallCursor, limit int) ([]timelineEntry, error) {
+	if s.embeddings == nil {
+		return nil, nil
+	}
+
+	clusters, err := s.embeddings.Clusters(start, end, timelineIncidentMinSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cluster calls: %w", err)
+	}
+
+	var entries []timelineEntry
+	for _, cluster := range clusters {
+		calls, err := s.db.GetCallRecordsByIDs(cluster.CallIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(calls) == 0 {
+			continue
+		}
+		sort.SliceStable(calls, func(i, j int) bool {
+			return calls[i].Timestamp.Before(calls[j].Timestamp)
+		})
+		first, last := calls[0], calls[len(calls)-1]
+
+		title := "Incident"
+		for _, call := range calls {
+			if call.Enrichment != nil && call.Enrichment.IncidentType != "" {
+				title += ": " + strings.ReplaceAll(call.Enrichment.IncidentType, "_", " ")
+				break
+			}
+		}
+		description := fmt.Sprintf("%d related calls on %s", len(calls), incidentTalkgroups(calls))
+		callIDs := make([]int, len(calls))
+		for i, call := range calls {
+			callIDs[i] = call.ID
+		}
+
+		boundaries := []struct {
+			suffix, label, icon string
+			call                *database.CallRecord
+			offset              int
+		}{
+			{"start", "started", "layer-group", first, 0},
+			{"end", "ended", "flag-checkered", last, 1},
+		}
+		for _, boundary := range boundaries {
+			entries = append(entries, timelineEntry{
+				TimelineEvent: TimelineEvent{
+					ID:          fmt.Sprintf("incident_%d_%s", first.ID, boundary.suffix),
+					Type:        "incident",
+					Timestamp:   boundary.call.Timestamp,
+					Title:       fmt.Sprintf("%s %s", title, boundary.label),
+					Description: description,
+					Icon:        boundary.icon,
+					Color:       incidentColor,
+					Data: map[string]interface{}{
+						"boundary": boundary.suffix,
+						"call_id":  boundary.call.ID,
+						"call_ids": callIDs,
+					},
+				},
+				source:   timelineIncidents,
+				position: &database.CallCursor{Timestamp: boundary.call.Timestamp, ID: first.ID*2 + boundary.offset},
+			})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].position, entries[j].position
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.After(b.Timestamp)
+		}
+		return a.ID > b.ID
+	})
+
+	// Skip the boundaries already shown on earlier pages
+	if cursor != nil {
+		after := entries[:0]
+		for _, entry := range entries {
+			position := entry.position
+			if position.Timestamp.Before(cursor.Timestamp) ||
+				(position.Timestamp.Equal(cursor.Timestamp) && position.ID < cursor.ID) {
+				after = append(after, entry)
+			}
+		}
+		entries = after
+	}
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// incidentTalkgroups names the talkgroups an incident's calls were on, most
+// active first
+func incidentTalkgroups(calls []*database.CallRecord) string {
+	counts := make(map[string]int)
+	var names []string
+	for _, call := range calls {
+		if counts[call.TalkgroupAlias] == 0 {
+			names = append(names, call.TalkgroupAlias)
+		}
+		counts[call.TalkgroupAlias]++
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return counts[names[i]] > counts[names[j]]
+	})
+
+	if len(names) > 3 {
+		return strings.Join(names[:3], ", ") + fmt.Sprintf(" and %d more", len(names)-3)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/watcher"
+)
+
+// maxSidecarSize bounds the metadata file uploaded with a recording
+const maxSidecarSize = 1 << 20
+
+// CallIngester queues recordings uploaded by agents for processing
+type CallIngester interface {
+	Enqueue(event watcher.FileEvent) bool
+}
+
+// SetIngester sets the processor that receives uploaded recordings
+func (s *Server) SetIngester(ingester CallIngester) {
+	s.ingester = ingester
+}
+
+// ingestCall accepts a recording from a remote agent. The multipart form holds
+// the audio file (keeping SDRTrunk's filename, which carries the call metadata),
+// the name of the receive site, and optionally the ID of the radio system and
+// the recorder's JSON metadata file.
+func (s *Server) ingestCall(c *fiber.Ctx) error {
+	if s.ingester == nil || !s.config.Ingest.Enabled {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Ingest is not enabled on this server",
+		})
+	}
+
+	file, err := c.FormFile("audio")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Missing audio file",
+			"details": err.Error(),
+		})
+	}
+	if file.Size > int64(s.config.Ingest.MaxUploadMB)*1024*1024 {
+		return c.Status(413).JSON(fiber.Map{
+			"error": "Recording exceeds ingest.max_upload_mb",
+		})
+	}
+
+	filename := filepath.Base(file.Filename)
+	if !s.acceptsRecording(filename) {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Unsupported recording",
+			"details": "filename must match file_monitor.patterns",
+		})
+	}
+
+	site := sanitizeSite(c.FormValue("site"))
+	if site == "" {
+		if record := requestKey(c); record != nil {
+			site = sanitizeSite(record.Name)
+		}
+	}
+	if site == "" {
+		site = "unknown"
+	}
+
+	system := sanitizeSite(c.FormValue("system"))
+
+	dir := filepath.Join(s.config.Ingest.Directory, site)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to create ingest directory",
+			"details": err.Error(),
+		})
+	}
+	path := filepath.Join(dir, filename)
+
+	// Agents retry uploads, so a recording may arrive more than once
+	exists, err := s.db.FileExists(path)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to check for duplicate",
+			"details": err.Error(),
+		})
+	}
+	if exists {
+		return c.JSON(fiber.Map{
+			"status":   "duplicate",
+			"filename": filename,
+			"site":     site,
+		})
+	}
+
+	// The metadata file is saved first, so it is there when the recording is processed
+	if sidecar, err := c.FormFile("metadata"); err == nil {
+		if sidecar.Size > maxSidecarSize {
+			return c.Status(413).JSON(fiber.Map{
+				"error": "Metadata file is too large",
+			})
+		}
+		if err := c.SaveFile(sidecar, strings.TrimSuffix(path, filepath.Ext(path))+".json"); err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"error":   "Failed to store metadata file",
+				"details": err.Error(),
+			})
+		}
+	}
+
+	if err := c.SaveFile(file, path); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to store recording",
+			"details": err.Error(),
+		})
+	}
+
+	event := watcher.FileEvent{
+		Path:      path,
+		Size:      file.Size,
+		ModTime:   time.Now(),
+		EventType: "ingest",
+		Site:      site,
+		System:    system,
+	}
+	if !s.ingester.Enqueue(event) {
+		os.Remove(path)
+		c.Set(fiber.HeaderRetryAfter, "30")
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Processing queue is full, retry later",
+		})
+	}
+
+	s.logger.Info("Recording received from agent", "site", site, "system", system, "file", filename, "size", file.Size)
+	return c.Status(202).JSON(fiber.Map{
+		"status":   "queued",
+		"filename": filename,
+		"site":     site,
+	})
+}
+
+// acceptsRecording reports whether a filename matches the monitored patterns
+func (s *Server) acceptsRecording(filename string) bool {
+	if filename == "" || strings.HasPrefix(filename, ".") {
+		return false
+	}
+	for _, pattern := range s.config.FileMonitor.Patterns {
+		if matched, _ := filepath.Match(pattern, filename); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeSite makes a site or system name safe to use as a directory name or ID
+func sanitizeSite(site string) string {
+	site = strings.TrimSpace(site)
+	var b strings.Builder
+	for _, r := range site {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ' || r == '.':
+			b.WriteByte('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,115 @@
+package web
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+
+	meikoLogger "Meiko/internal/logger"
+)
+
+// maxLogBacklog matches the number of entries the logger keeps in memory
+const maxLogBacklog = 100
+
+// checkLogStream validates the log stream query before the connection is upgraded
+func (s *Server) checkLogStream(c *fiber.Ctx) error {
+	if _, err := meikoLogger.ParseLevel(c.Query("level", "INFO")); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid log level",
+			"details": err.Error(),
+		})
+	}
+	if backlog := c.QueryInt("backlog", 50); backlog < 0 || backlog > maxLogBacklog {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid backlog",
+			"details": "backlog must be between 0 and 100",
+		})
+	}
+	return c.Next()
+}
+
+// handleLogStream sends recent log entries and then streams new ones as they
+// are logged. The level query parameter sets the minimum level (default INFO)
+// and backlog sets how many recent entries are sent first (default 50).
+func (s *Server) handleLogStream(c *websocket.Conn) {
+	defer c.Close()
+
+	level, _ := meikoLogger.ParseLevel(c.Query("level", "INFO"))
+	entries, unsubscribe := s.logger.Subscribe(level)
+	defer unsubscribe()
+
+	// Entries logged while the backlog is sent arrive on the subscription
+	// too, so skip those already covered by the backlog
+	var sentUntil time.Time
+	for _, entry := range s.recentLogs(c, level) {
+		if !s.sendLogEntry(c, entry) {
+			return
+		}
+		sentUntil = entry.Timestamp
+	}
+
+	// The client doesn't send anything, but reading detects when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			if !entry.Timestamp.After(sentUntil) {
+				continue
+			}
+			if !s.sendLogEntry(c, entry) {
+				return
+			}
+		case <-ping.C:
+			if err := c.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// recentLogs returns the requested number of buffered entries at or above level
+func (s *Server) recentLogs(c *websocket.Conn, level meikoLogger.LogLevel) []meikoLogger.LogEntry {
+	backlog, err := strconv.Atoi(c.Query("backlog", "50"))
+	if err != nil {
+		backlog = 50
+	}
+
+	var entries []meikoLogger.LogEntry
+	for _, entry := range s.logger.GetRecentLogs(maxLogBacklog) {
+		if entry.LogLevel() >= level {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) > backlog {
+		entries = entries[len(entries)-backlog:]
+	}
+	return entries
+}
+
+// sendLogEntry writes a log message, reporting whether the client is still connected
+func (s *Server) sendLogEntry(c *websocket.Conn, entry meikoLogger.LogEntry) bool {
+	data, err := encodeMessage(MessageLog, entry, nil)
+	if err != nil {
+		return true
+	}
+	return c.WriteMessage(websocket.TextMessage, data) == nil
+}
@@ -0,0 +1,226 @@
+package web
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WSSchemaVersion is the version of the WebSocket message schema.
+// Bump it whenever a payload field is removed or changes meaning.
+const WSSchemaVersion = 2
+
+// MessageType identifies the kind of WebSocket message
+type MessageType string
+
+const (
+	MessageStatus       MessageType = "status"
+	MessageNewCall      MessageType = "new_call"
+	MessageCallUpdated  MessageType = "call_updated"
+	MessageStatsUpdate  MessageType = "stats_update"
+	MessageLog          MessageType = "log"
+	MessageHealth       MessageType = "health"
+	MessageScannerEvent MessageType = "live_scanner_event"
+	MessageHelloAck     MessageType = "hello_ack"
+	MessageHeartbeat    MessageType = "heartbeat"
+	MessageError        MessageType = "error"
+)
+
+// Message types clients can send on /ws
+const (
+	ClientHello MessageType = "hello"
+	ClientPing  MessageType = "ping"
+)
+
+// MessageSchema documents a single WebSocket message type
+type MessageSchema struct {
+	Type        MessageType       `json:"type"`
+	Description string            `json:"description"`
+	Since       int               `json:"since"`
+	Fields      map[string]string `json:"fields"`
+}
+
+// envelopeFields are present on every WebSocket message
+var envelopeFields = map[string]string{
+	"version":   "integer - schema version of this message",
+	"type":      "string - message type from the catalog",
+	"timestamp": "RFC3339 timestamp - when the server emitted the message",
+	"seq":       "integer - position in the broadcast sequence, counting from 1 per epoch; omitted on stats_update and on replies to one client",
+}
+
+// messageCatalog lists every message type the server can emit
+var messageCatalog = []MessageSchema{
+	{
+		Type:        MessageStatus,
+		Description: "Sent once when a client connects",
+		Since:       1,
+		Fields: map[string]string{
+			"connected": "boolean - always true",
+			"epoch":     "string - identifies this run of the server; sequence numbers restart when it changes (since 2)",
+			"latest":    "integer - sequence number of the latest broadcast (since 2)",
+			"resumed":   "boolean - on /api/events reconnects with Last-Event-ID, whether every missed message follows (since 2)",
+		},
+	},
+	{
+		Type:        MessageNewCall,
+		Description: "A call has been transcribed and stored",
+		Since:       1,
+		Fields: map[string]string{
+			"data":                          "object - call record (see /api/calls/:id)",
+			"live_scanner.should_auto_play": "boolean - hint for the live scanner",
+			"live_scanner.waveform_data":    "array of numbers - waveform preview",
+			"live_scanner.frequency_info":   "object - frequency metadata",
+		},
+	},
+	{
+		Type:        MessageCallUpdated,
+		Description: "A stored call was corrected by hand",
+		Since:       1,
+		Fields: map[string]string{
+			"data": "object - call record after the correction (see /api/calls/:id)",
+		},
+	},
+	{
+		Type:        MessageStatsUpdate,
+		Description: "Periodic system statistics",
+		Since:       1,
+		Fields: map[string]string{
+			"data.cpu":         "number - CPU usage percent",
+			"data.memory":      "number - memory usage percent",
+			"data.disk":        "number - disk usage percent",
+			"data.temperature": "number - hottest sensor temperature in Celsius",
+			"data.sensors":     "array - per-sensor readings with name, temperature and source (omitted when none)",
+			"data.timestamp":   "RFC3339 timestamp - when stats were sampled",
+		},
+	},
+	{
+		Type:        MessageLog,
+		Description: "A new log entry, sent on /ws/logs (admin scope) filtered by the level query parameter",
+		Since:       1,
+		Fields: map[string]string{
+			"data.timestamp": "RFC3339 timestamp",
+			"data.level":     "string - DEBUG, INFO, WARN, ERROR or SUCCESS",
+			"data.component": "string - component that logged the entry",
+			"data.message":   "string - formatted log message",
+		},
+	},
+	{
+		Type:        MessageHealth,
+		Description: "A monitored metric crossed its alert threshold or recovered",
+		Since:       1,
+		Fields: map[string]string{
+			"data.metric":    "string - cpu_usage, memory_usage, disk_usage or temperature",
+			"data.state":     "string - breach or recovered",
+			"data.value":     "number - current reading",
+			"data.threshold": "number - alert threshold",
+			"data.clear_at":  "number - reading the metric must fall below to recover",
+			"data.unit":      "string - unit of value and threshold",
+			"data.message":   "string - human-readable description",
+			"data.host":      "string - host name of the server",
+			"data.timestamp": "RFC3339 timestamp - when the change was detected",
+		},
+	},
+	{
+		Type:        MessageScannerEvent,
+		Description: "Live scanner specific events",
+		Since:       1,
+		Fields: map[string]string{
+			"event": "string - scanner event name",
+			"data":  "any - event payload",
+		},
+	},
+	{
+		Type:        MessageHelloAck,
+		Description: "Reply to a client's hello. When resumed is true, the messages missed since last_seq follow in order; otherwise the client should backfill from the REST API, e.g. /api/v1/calls.",
+		Since:       2,
+		Fields: map[string]string{
+			"version": "integer - the schema version the server speaks",
+			"epoch":   "string - identifies this run of the server",
+			"latest":  "integer - sequence number of the latest broadcast",
+			"resumed": "boolean - whether the missed messages follow",
+			"missed":  "integer - how many missed messages follow",
+		},
+	},
+	{
+		Type:        MessageHeartbeat,
+		Description: "Sent every 30 seconds after a hello, and in reply to a ping, so clients can spot gaps and dead connections",
+		Since:       2,
+		Fields: map[string]string{
+			"epoch":  "string - identifies this run of the server",
+			"latest": "integer - sequence number of the latest broadcast",
+		},
+	},
+	{
+		Type:        MessageError,
+		Description: "A client message could not be handled",
+		Since:       2,
+		Fields: map[string]string{
+			"error": "string - what went wrong",
+		},
+	},
+}
+
+// clientMessageCatalog lists the messages clients can send on /ws. Clients
+// that send nothing receive every broadcast as before.
+var clientMessageCatalog = []MessageSchema{
+	{
+		Type:        ClientHello,
+		Description: "Starts the session. To resume after a disconnect, send the epoch and the seq of the last message received.",
+		Since:       2,
+		Fields: map[string]string{
+			"version":  "integer - the schema version the client expects",
+			"epoch":    "string - epoch from the previous connection (optional)",
+			"last_seq": "integer - seq of the last message received (optional)",
+		},
+	},
+	{
+		Type:        ClientPing,
+		Description: "Asks for a heartbeat, to check the connection",
+		Since:       2,
+		Fields:      map[string]string{},
+	},
+}
+
+// encodeMessage wraps a payload in the versioned message envelope
+func encodeMessage(msgType MessageType, data interface{}, extra fiber.Map) ([]byte, error) {
+	message := fiber.Map{
+		"version":   WSSchemaVersion,
+		"type":      msgType,
+		"timestamp": time.Now(),
+	}
+	if data != nil {
+		message["data"] = data
+	}
+	for key, value := range extra {
+		message[key] = value
+	}
+
+	return json.Marshal(message)
+}
+
+// BroadcastMessage sends a typed message to all WebSocket clients
+func (s *Server) BroadcastMessage(msgType MessageType, data interface{}) {
+	payload, err := encodeMessage(msgType, data, nil)
+	if err != nil {
+		s.logger.Error("Failed to marshal WebSocket message", "type", msgType, "error", err)
+		return
+	}
+
+	select {
+	case s.broadcast <- payload:
+	default:
+		// Channel is full, skip this broadcast
+	}
+}
+
+// getWebSocketSchema returns the WebSocket message catalog, which also
+// describes the Server-Sent Events sent on /api/events
+func (s *Server) getWebSocketSchema(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"version":         WSSchemaVersion,
+		"envelope":        envelopeFields,
+		"messages":        messageCatalog,
+		"client_messages": clientMessageCatalog,
+	})
+}
@@ -0,0 +1,385 @@
+package web
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/apikeys"
+	"Meiko/internal/database"
+	"Meiko/internal/processor"
+	"Meiko/internal/storage"
+)
+
+// endpointDoc documents one API endpoint for the OpenAPI spec
+type endpointDoc struct {
+	Summary  string
+	Scope    string            // API key scope required, empty for none
+	Query    map[string]string // Query parameters and their descriptions
+	Response interface{}       // Example value whose type describes the response, nil for a generic object
+}
+
+// Query parameters shared by several endpoints
+var (
+	rangeQuery = map[string]string{
+		"date":  "Local day as YYYY-MM-DD",
+		"range": "Relative range: 30min, 1h, today, 24h, week or month",
+		"start": "RFC3339 start, with end",
+		"end":   "RFC3339 end, with start",
+	}
+	callFilterQuery = map[string]string{
+		"talkgroup":         "Talkgroup ID",
+		"system":            "System ID",
+		"min_priority":      "Minimum priority, 0-100",
+		"major":             "true keeps major incidents only",
+		"status":            "processed, pending or failed",
+		"incident_type":     "Enrichment incident type",
+		"has_transcription": "true or false",
+		"min_duration":      "Seconds",
+		"max_duration":      "Seconds",
+		"keyword":           "Words the transcription must contain",
+		"frequency":         "Hz or MHz",
+		"frequency_min":     "Lowest frequency, Hz or MHz",
+		"frequency_max":     "Highest frequency, Hz or MHz",
+		"service_type":      "Talkgroup service type, e.g. FIRE",
+	}
+	pageQuery = map[string]string{
+		"limit":  "Page size",
+		"cursor": "next_cursor from the previous page",
+	}
+)
+
+// callsResponse is a page of call records, as documented for /api/calls
+type callsResponse struct {
+	Calls      []CallRecord `json:"calls"`
+	Pagination struct {
+		Limit      int    `json:"limit"`
+		Offset     int    `json:"offset"`
+		Total      int64  `json:"total"`
+		HasMore    bool   `json:"has_more"`
+		NextCursor string `json:"next_cursor,omitempty"`
+	} `json:"pagination"`
+}
+
+// endpointCatalog documents every API route, keyed by method and path without
+// the API version. Routes missing here still appear in the spec, undescribed.
+var endpointCatalog = map[string]endpointDoc{
+	"GET /api/health": {Summary: "Component health for uptime monitors; 503 when a critical component is down"},
+
+	"GET /api/timeline":       {Summary: "Today's calls and system events, newest first", Scope: apikeys.ScopeReadCalls, Query: pageQuery, Response: TimelineResponse{}},
+	"GET /api/timeline/:date": {Summary: "A day's calls and system events, newest first", Scope: apikeys.ScopeReadCalls, Query: pageQuery, Response: TimelineResponse{}},
+	"GET /api/history/:file":  {Summary: "Pre-rendered timeline of a past day, as YYYY-MM-DD.json", Scope: apikeys.ScopeReadCalls},
+
+	"GET /api/calls":               {Summary: "Call records, newest first", Scope: apikeys.ScopeReadCalls, Query: mergeQuery(rangeQuery, callFilterQuery, pageQuery, map[string]string{"sort": "priority lists the highest priority first", "offset": "Offset for sort=priority"}), Response: callsResponse{}},
+	"POST /api/calls/retry-failed": {Summary: "Queue every failed call for transcription again", Scope: apikeys.ScopeAdmin},
+	"POST /api/calls/bulk/:action": {Summary: "Delete, retranscribe, reclassify or renotify every matching call", Scope: apikeys.ScopeAdmin, Query: mergeQuery(rangeQuery, callFilterQuery, map[string]string{"dry_run": "true reports the matching calls without changing them"})},
+	"GET /api/calls/:id":           {Summary: "One call record", Scope: apikeys.ScopeReadCalls, Response: CallRecord{}},
+	"PATCH /api/calls/:id":         {Summary: "Correct a call's transcription or talkgroup, or mark it as noise", Scope: apikeys.ScopeEditCalls},
+	"GET /api/calls/:id/edits": {Summary: "Audit log of a call's manual corrections", Scope: apikeys.ScopeEditCalls, Response: struct {
+		Edits []database.CallEdit `json:"edits"`
+	}{}},
+	"GET /api/calls/:id/original":         {Summary: "A call with its transcription as stored, before redaction", Scope: apikeys.ScopeAdmin},
+	"GET /api/calls/:id/audio":            {Summary: "The call's audio, with byte ranges for seeking", Scope: apikeys.ScopeReadCalls},
+	"GET /api/calls/summary/:range":       {Summary: "Aggregated call statistics for a relative range", Scope: apikeys.ScopeReadCalls, Query: map[string]string{"system": "System ID"}},
+	"GET /api/export":                     {Summary: "Export calls as CSV, JSON or a ZIP with audio", Scope: apikeys.ScopeReadCalls, Query: mergeQuery(rangeQuery, callFilterQuery, map[string]string{"format": "csv, json or zip"})},
+	"GET /api/stats":                      {Summary: "Current system statistics", Scope: apikeys.ScopeReadStats, Response: SystemStats{}},
+	"GET /api/stats/lifetime":             {Summary: "Call statistics over all history", Scope: apikeys.ScopeReadStats},
+	"GET /api/stats/heatmap":              {Summary: "Call counts by weekday and hour of day", Scope: apikeys.ScopeReadStats, Query: map[string]string{"range": "Relative range, default month", "system": "System ID", "talkgroup": "Talkgroup ID", "service_type": "Talkgroup service type"}},
+	"GET /api/summary/auto":               {Summary: "The latest automatic AI summary", Scope: apikeys.ScopeReadCalls},
+	"GET /api/summaries":                  {Summary: "Stored AI summaries", Scope: apikeys.ScopeReadCalls},
+	"GET /api/system":                     {Summary: "System status, SDRTrunk processes, the disk forecast and the last reconciliation", Scope: apikeys.ScopeReadStats},
+	"POST /api/system/reconcile":          {Summary: "Compare the database with the recordings on disk now", Scope: apikeys.ScopeAdmin, Response: storage.Reconciliation{}},
+	"POST /api/system/reconcile/cleanup":  {Summary: "Delete calls missing their recording and orphaned recordings", Scope: apikeys.ScopeAdmin, Response: storage.CleanupResult{}},
+	"GET /api/systems":                    {Summary: "Configured radio systems and their activity", Scope: apikeys.ScopeReadStats},
+	"GET /api/pipeline":                   {Summary: "Processing queue, in-flight transcriptions, stage latencies and failures", Scope: apikeys.ScopeReadStats, Response: processor.PipelineStats{}},
+	"GET /api/metrics":                    {Summary: "Processing pipeline statistics in the Prometheus text format", Scope: apikeys.ScopeReadStats},
+	"GET /api/logs":                       {Summary: "Recent log entries", Scope: apikeys.ScopeAdmin},
+	"GET /api/live/stream":                {Summary: "Calls from the last five minutes", Scope: apikeys.ScopeReadCalls},
+	"GET /api/live/status":                {Summary: "Live streaming status, the latest call and active frequencies", Scope: apikeys.ScopeReadCalls, Query: map[string]string{"system": "System ID"}},
+	"GET /api/preferences":                {Summary: "Saved dashboard preferences of the API key or X-Client-Token browser", Scope: apikeys.ScopeReadCalls},
+	"PUT /api/preferences":                {Summary: "Replace saved dashboard preferences", Scope: apikeys.ScopeReadCalls},
+	"PATCH /api/preferences":              {Summary: "Merge keys into saved dashboard preferences; null removes a key", Scope: apikeys.ScopeReadCalls},
+	"POST /api/scanner/sessions":          {Summary: "Start a live scanner session with its own playback queue", Scope: apikeys.ScopeReadCalls},
+	"GET /api/scanner/sessions/:id":       {Summary: "A live scanner session and how many calls wait for it", Scope: apikeys.ScopeReadCalls},
+	"DELETE /api/scanner/sessions/:id":    {Summary: "End a live scanner session", Scope: apikeys.ScopeReadCalls},
+	"PUT /api/scanner/sessions/:id/mutes": {Summary: "Replace a session's muted talkgroups", Scope: apikeys.ScopeReadCalls},
+	"GET /api/scanner/sessions/:id/queue": {Summary: "Calls a session has yet to play, in playback order", Scope: apikeys.ScopeReadCalls, Response: struct {
+		Calls []CallRecord `json:"calls"`
+	}{}},
+	"GET /api/scanner/sessions/:id/next":            {Summary: "The next call a session should play; 204 when caught up", Scope: apikeys.ScopeReadCalls},
+	"POST /api/scanner/sessions/:id/played/:callId": {Summary: "Mark a call as played by a session", Scope: apikeys.ScopeReadCalls},
+	"POST /api/debug/broadcast-latest":              {Summary: "Broadcast the latest call again, for testing clients", Scope: apikeys.ScopeAdmin},
+	"POST /api/summary/generate":                    {Summary: "Generate an AI summary of recent calls", Scope: apikeys.ScopeReadCalls},
+	"POST /api/ask":                                 {Summary: "Ask a question about recent calls", Scope: apikeys.ScopeReadCalls},
+	"GET /api/search/semantic":                      {Summary: "Calls similar in meaning to a query", Scope: apikeys.ScopeReadCalls, Query: map[string]string{"q": "Search text", "limit": "Maximum results"}},
+	"GET /api/search/clusters":                      {Summary: "Groups of related calls, such as one incident", Scope: apikeys.ScopeReadCalls},
+	"GET /api/timeline/summaries/:date":             {Summary: "Hourly AI summaries of a day", Scope: apikeys.ScopeReadCalls},
+	"GET /api/timeline/summary/:date/:hour":         {Summary: "The AI summary of one hour", Scope: apikeys.ScopeReadCalls},
+	"POST /api/timeline/summary/generate":           {Summary: "Generate the AI summary of an hour", Scope: apikeys.ScopeReadCalls},
+	"GET /api/frequencies":                          {Summary: "Known frequencies and their labels", Scope: apikeys.ScopeReadCalls},
+	"POST /api/frequencies":                         {Summary: "Label a frequency", Scope: apikeys.ScopeAdmin},
+	"PUT /api/frequencies/:id":                      {Summary: "Update a frequency label", Scope: apikeys.ScopeAdmin},
+	"DELETE /api/frequencies/:id":                   {Summary: "Delete a frequency label", Scope: apikeys.ScopeAdmin},
+	"GET /api/corrections/rules":                    {Summary: "Transcription correction rules", Scope: apikeys.ScopeAdmin},
+	"POST /api/corrections/rules":                   {Summary: "Add a transcription correction rule", Scope: apikeys.ScopeAdmin},
+	"PUT /api/corrections/rules/:id":                {Summary: "Update a transcription correction rule", Scope: apikeys.ScopeAdmin},
+	"DELETE /api/corrections/rules/:id":             {Summary: "Delete a transcription correction rule", Scope: apikeys.ScopeAdmin},
+	"POST /api/corrections/test":                    {Summary: "Try the correction rules on a sample text", Scope: apikeys.ScopeAdmin},
+	"GET /api/talkgroups":                           {Summary: "Talkgroups from the playlist and overrides", Scope: apikeys.ScopeReadCalls},
+	"GET /api/talkgroups/:id":                       {Summary: "One talkgroup", Scope: apikeys.ScopeReadCalls},
+	"PUT /api/talkgroups/:id":                       {Summary: "Override a talkgroup's metadata", Scope: apikeys.ScopeAdmin},
+	"DELETE /api/talkgroups/:id/override":           {Summary: "Remove a talkgroup's override", Scope: apikeys.ScopeAdmin},
+	"GET /api/reports/:date":                        {Summary: "Daily or shift report", Scope: apikeys.ScopeReadStats, Query: map[string]string{"format": "html, pdf or markdown", "start": "Shift start time", "hours": "Shift length", "summary": "false skips the AI summary"}},
+	"GET /api/keys":                                 {Summary: "API keys, without their secrets", Scope: apikeys.ScopeAdmin},
+	"POST /api/keys":                                {Summary: "Create an API key; the key is only shown once", Scope: apikeys.ScopeAdmin},
+	"DELETE /api/keys/:id":                          {Summary: "Revoke an API key", Scope: apikeys.ScopeAdmin},
+	"POST /api/ingest/calls":                        {Summary: "Upload a recording from an agent", Scope: apikeys.ScopeIngest},
+	"GET /api/feeds/incidents.:format":              {Summary: "Major incidents as an RSS or Atom feed"},
+	"GET /api/feeds/summaries.:format":              {Summary: "AI summaries as an RSS or Atom feed"},
+	"GET /api/ws/schema":                            {Summary: "The WebSocket message catalog"},
+	"GET /api/events":                               {Summary: "Server-Sent Events carrying the WebSocket messages", Scope: apikeys.ScopeReadCalls},
+	"GET /api/docs":                                 {Summary: "This documentation, in Swagger UI"},
+	"GET /api/docs/openapi.json":                    {Summary: "This OpenAPI specification"},
+	"GET /ws":                                       {Summary: "WebSocket stream of the messages in x-websocket-messages", Scope: apikeys.ScopeReadCalls},
+	"GET /ws/logs":                                  {Summary: "WebSocket stream of log entries", Scope: apikeys.ScopeAdmin, Query: map[string]string{"level": "Minimum level", "backlog": "Recent entries to send first, 0-100"}},
+}
+
+// mergeQuery combines query parameter lists
+func mergeQuery(lists ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, list := range lists {
+		for name, description := range list {
+			merged[name] = description
+		}
+	}
+	return merged
+}
+
+var (
+	// routeParam matches Fiber path parameters, such as :id
+	routeParam = regexp.MustCompile(`:(\w+)`)
+
+	// specParam matches OpenAPI path parameters, such as {id}
+	specParam = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+// getOpenAPISpec returns an OpenAPI 3 specification of the registered routes,
+// with the WebSocket message catalog as an extension
+func (s *Server) getOpenAPISpec(c *fiber.Ctx) error {
+	paths := make(map[string]fiber.Map)
+	schemas := fiber.Map{
+		"Error": fiber.Map{
+			"type": "object",
+			"properties": fiber.Map{
+				"error":   fiber.Map{"type": "string"},
+				"details": fiber.Map{"type": "string"},
+			},
+		},
+	}
+
+	routes := s.app.GetRoutes(true)
+	if s.config.Web.History.Enabled {
+		// Static files are served by middleware, which GetRoutes leaves out
+		routes = append(routes, fiber.Route{Method: fiber.MethodGet, Path: apiPrefix + "/history/:file"})
+	}
+
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead || route.Method == fiber.MethodOptions {
+			continue
+		}
+		if !strings.HasPrefix(route.Path, "/api/") && route.Path != "/ws" && !strings.HasPrefix(route.Path, "/ws/") {
+			continue
+		}
+
+		doc := endpointCatalog[route.Method+" "+unversionedPath(route.Path)]
+		path := routeParam.ReplaceAllString(route.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = fiber.Map{}
+		}
+		paths[path][strings.ToLower(route.Method)] = openAPIOperation(route, path, doc, schemas)
+	}
+
+	messages := make(fiber.Map, len(messageCatalog))
+	for _, message := range messageCatalog {
+		messages[string(message.Type)] = message
+	}
+
+	clientMessages := make(fiber.Map, len(clientMessageCatalog))
+	for _, message := range clientMessageCatalog {
+		clientMessages[string(message.Type)] = message
+	}
+
+	spec := fiber.Map{
+		"openapi": "3.0.3",
+		"info": fiber.Map{
+			"title":       "Meiko API",
+			"description": "Calls, transcriptions, statistics and live streams from a Meiko scanner. The unversioned /api paths still serve this version but are deprecated.",
+			"version":     "1",
+		},
+		"paths": paths,
+		"components": fiber.Map{
+			"schemas": schemas,
+			"securitySchemes": fiber.Map{
+				"bearer": fiber.Map{"type": "http", "scheme": "bearer"},
+				"apiKey": fiber.Map{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		"x-websocket-envelope":        envelopeFields,
+		"x-websocket-messages":        messages,
+		"x-websocket-client-messages": clientMessages,
+		"x-websocket-version":         WSSchemaVersion,
+	}
+	if base := requestBasePath(c); base != "" {
+		spec["servers"] = []fiber.Map{{"url": base}}
+	}
+	return c.JSON(spec)
+}
+
+// openAPIOperation describes one route
+func openAPIOperation(route fiber.Route, path string, doc endpointDoc, schemas fiber.Map) fiber.Map {
+	segments := strings.Split(strings.TrimPrefix(unversionedPath(path), "/"), "/")
+	tag := segments[0]
+	if tag == "api" && len(segments) > 1 {
+		tag = strings.SplitN(segments[1], ".", 2)[0]
+	}
+
+	var parameters []fiber.Map
+	for _, match := range specParam.FindAllStringSubmatch(path, -1) {
+		parameters = append(parameters, fiber.Map{
+			"name": match[1], "in": "path", "required": true, "schema": fiber.Map{"type": "string"},
+		})
+	}
+	names := make([]string, 0, len(doc.Query))
+	for name := range doc.Query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parameters = append(parameters, fiber.Map{
+			"name": name, "in": "query", "description": doc.Query[name], "schema": fiber.Map{"type": "string"},
+		})
+	}
+
+	content := fiber.Map{"schema": fiber.Map{"type": "object"}}
+	if doc.Response != nil {
+		content = fiber.Map{"schema": schemaOf(reflect.TypeOf(doc.Response), schemas)}
+	}
+	errorContent := fiber.Map{"application/json": fiber.Map{"schema": fiber.Map{"$ref": "#/components/schemas/Error"}}}
+
+	operation := fiber.Map{
+		"summary": doc.Summary,
+		"tags":    []string{tag},
+		"responses": fiber.Map{
+			"200":     fiber.Map{"description": "OK", "content": fiber.Map{"application/json": content}},
+			"default": fiber.Map{"description": "Error", "content": errorContent},
+		},
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if doc.Scope != "" {
+		operation["description"] = "Requires the `" + doc.Scope + "` scope when API keys are enabled, unless it is public."
+		operation["security"] = []fiber.Map{{"bearer": []string{}}, {"apiKey": []string{}}}
+		operation["x-scope"] = doc.Scope
+	}
+	if route.Path == "/ws" || strings.HasPrefix(route.Path, "/ws/") {
+		operation["responses"] = fiber.Map{"101": fiber.Map{"description": "Switching to the WebSocket protocol"}}
+	}
+	return operation
+}
+
+// timeType is described as a date-time string rather than a struct
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf describes a Go type as a JSON schema, adding named structs to
+// schemas and referring to them
+func schemaOf(t reflect.Type, schemas fiber.Map) fiber.Map {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return fiber.Map{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return fiber.Map{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case t.Kind() == reflect.Map:
+		return fiber.Map{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case t.Kind() == reflect.Bool:
+		return fiber.Map{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return fiber.Map{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return fiber.Map{"type": "number"}
+	case t.Kind() == reflect.String:
+		return fiber.Map{"type": "string"}
+	case t.Kind() != reflect.Struct:
+		return fiber.Map{}
+	}
+
+	name := t.Name()
+	if name == "" {
+		return structSchema(t, schemas)
+	}
+	if _, ok := schemas[name]; !ok {
+		schemas[name] = fiber.Map{} // Placeholder so recursive types terminate
+		schemas[name] = structSchema(t, schemas)
+	}
+	return fiber.Map{"$ref": "#/components/schemas/" + name}
+}
+
+// structSchema describes a struct's JSON fields
+func structSchema(t reflect.Type, schemas fiber.Map) fiber.Map {
+	properties := fiber.Map{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			if embedded := structSchema(field.Type, schemas); embedded["properties"] != nil {
+				for key, value := range embedded["properties"].(fiber.Map) {
+					properties[key] = value
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaOf(field.Type, schemas)
+	}
+	return fiber.Map{"type": "object", "properties": properties}
+}
+
+// swaggerUI renders the specification with Swagger UI from a CDN
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Meiko API</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        SwaggerUIBundle({ url: '{{spec}}', dom_id: '#swagger-ui' });
+    </script>
+</body>
+</html>
+`
+
+// getAPIDocs serves Swagger UI for the OpenAPI specification
+func (s *Server) getAPIDocs(c *fiber.Ctx) error {
+	c.Type("html")
+	return c.SendString(strings.Replace(swaggerUI, "{{spec}}", requestBasePath(c)+apiPrefix+"/docs/openapi.json", 1))
+}
@@ -0,0 +1,155 @@
+package web
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// encodeCursor builds an opaque cursor pointing after the given call
+func encodeCursor(call *database.CallRecord) string {
+	raw := fmt.Sprintf("%d:%d", call.Timestamp.UnixNano(), call.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a cursor produced by encodeCursor
+func decodeCursor(cursor string) (*database.CallCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding")
+	}
+	return parsePosition(string(raw))
+}
+
+// parsePosition parses a "nanoseconds:id" position within a cursor
+func parsePosition(raw string) (*database.CallCursor, error) {
+	parts := strings.SplitN(raw, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor format")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor timestamp")
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor ID")
+	}
+
+	return &database.CallCursor{Timestamp: time.Unix(0, nanos), ID: id}, nil
+}
+
+// Timeline sources, each paged through separately
+const (
+	timelineCalls     = "calls"
+	timelineSystem    = "system"
+	timelineIncidents = "incidents"
+)
+
+// timelineCursor records where each timeline source left off. The timeline
+// merges several sources, each paged newest first by its own keys, so a
+// source that had nothing on a page stays where it was.
+type timelineCursor map[string]*database.CallCursor
+
+// encodeTimelineCursor builds an opaque cursor from each source's position
+func encodeTimelineCursor(cursor timelineCursor) string {
+	sources := make([]string, 0, len(cursor))
+	for source := range cursor {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	parts := make([]string, len(sources))
+	for i, source := range sources {
+		position := cursor[source]
+		parts[i] = fmt.Sprintf("%s=%d:%d", source, position.Timestamp.UnixNano(), position.ID)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ",")))
+}
+
+// decodeTimelineCursor parses a cursor produced by encodeTimelineCursor
+func decodeTimelineCursor(cursor string) (timelineCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding")
+	}
+
+	positions := make(timelineCursor)
+	for _, part := range strings.Split(string(raw), ",") {
+		source, position, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cursor format")
+		}
+		parsed, err := parsePosition(position)
+		if err != nil {
+			return nil, err
+		}
+		positions[source] = parsed
+	}
+	return positions, nil
+}
+
+// timelineCursorParam reads the cursor query parameter, returning nil for
+// the first page
+func timelineCursorParam(c *fiber.Ctx) (timelineCursor, error) {
+	if cursor := c.Query("cursor"); cursor != "" {
+		return decodeTimelineCursor(cursor)
+	}
+	return nil, nil
+}
+
+// pageSize reads the limit query parameter, clamped to a sane range
+func pageSize(c *fiber.Ctx) int {
+	limit := c.QueryInt("limit", defaultPageSize)
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// pageURL returns the current request URL with the given query parameters replaced
+func pageURL(c *fiber.Ctx, params map[string]string) string {
+	query := url.Values{}
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		query.Add(string(key), string(value))
+	})
+	for key, value := range params {
+		if value == "" {
+			query.Del(key)
+		} else {
+			query.Set(key, value)
+		}
+	}
+
+	return requestBaseURL(c) + c.Path() + "?" + query.Encode()
+}
+
+// setLinkHeader writes an RFC 8288 Link header from rel -> URL pairs
+func setLinkHeader(c *fiber.Ctx, links map[string]string) {
+	var parts []string
+	for _, rel := range []string{"first", "prev", "next"} {
+		if link, ok := links[rel]; ok {
+			parts = append(parts, fmt.Sprintf("<%s>; rel=\"%s\"", link, rel))
+		}
+	}
+	if len(parts) > 0 {
+		c.Set("Link", strings.Join(parts, ", "))
+	}
+}
@@ -0,0 +1,88 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/processor"
+)
+
+// PipelineReporter reports the call processor's queue and throughput
+type PipelineReporter interface {
+	Pipeline() processor.PipelineStats
+}
+
+// SetPipeline sets the processor whose queue and throughput are reported
+func (s *Server) SetPipeline(pipeline PipelineReporter) {
+	s.pipeline = pipeline
+}
+
+// getPipeline returns the processor's queue depth, in-flight transcriptions,
+// stage latencies and failures since startup
+func (s *Server) getPipeline(c *fiber.Ctx) error {
+	if s.pipeline == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "This instance does not process calls",
+		})
+	}
+	return c.JSON(s.pipeline.Pipeline())
+}
+
+// getMetrics returns the pipeline statistics in the Prometheus text format
+func (s *Server) getMetrics(c *fiber.Ctx) error {
+	if s.pipeline == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "This instance does not process calls",
+		})
+	}
+	c.Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	return c.SendString(prometheusMetrics(s.pipeline.Pipeline()))
+}
+
+// prometheusMetrics renders pipeline statistics in the Prometheus text format
+func prometheusMetrics(stats processor.PipelineStats) string {
+	var b strings.Builder
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	metric("meiko_pipeline_queue_depth", "gauge", "Recordings waiting to be processed.")
+	fmt.Fprintf(&b, "meiko_pipeline_queue_depth %d\n", stats.QueueDepth)
+
+	metric("meiko_pipeline_transcriptions_in_flight", "gauge", "Transcriptions in progress.")
+	fmt.Fprintf(&b, "meiko_pipeline_transcriptions_in_flight %d\n", stats.Transcribing)
+
+	metric("meiko_pipeline_calls_processed_total", "counter", "Calls transcribed and notified.")
+	fmt.Fprintf(&b, "meiko_pipeline_calls_processed_total %d\n", stats.Processed)
+
+	metric("meiko_pipeline_recordings_skipped_total", "counter", "Recordings already processed, muted or too short.")
+	fmt.Fprintf(&b, "meiko_pipeline_recordings_skipped_total %d\n", stats.Skipped)
+
+	metric("meiko_watcher_events_deferred_total", "counter", "Times a watcher found the queue full and retried a recording later.")
+	fmt.Fprintf(&b, "meiko_watcher_events_deferred_total %d\n", stats.Deferred)
+
+	metric("meiko_watcher_events_dropped_total", "counter", "Recordings never queued because their watcher stopped.")
+	fmt.Fprintf(&b, "meiko_watcher_events_dropped_total %d\n", stats.Dropped)
+
+	metric("meiko_pipeline_stage_duration_seconds", "summary", "Time spent in each pipeline stage.")
+	for _, stage := range processor.Stages {
+		timing := stats.Stages[stage]
+		fmt.Fprintf(&b, "meiko_pipeline_stage_duration_seconds_sum{stage=%q} %g\n", stage, timing.TotalSeconds)
+		fmt.Fprintf(&b, "meiko_pipeline_stage_duration_seconds_count{stage=%q} %d\n", stage, timing.Count)
+	}
+
+	metric("meiko_pipeline_failures_total", "counter", "Failures in each pipeline stage.")
+	// Database writes fail but aren't timed, so they only appear here
+	stages := append(append([]string{}, processor.Stages...), processor.StageStore)
+	for _, stage := range stages {
+		fmt.Fprintf(&b, "meiko_pipeline_failures_total{stage=%q} %d\n", stage, stats.Failures[stage])
+	}
+
+	if stats.LastProcessedAt != nil {
+		metric("meiko_pipeline_last_processed_timestamp_seconds", "gauge", "When the last call finished processing.")
+		fmt.Fprintf(&b, "meiko_pipeline_last_processed_timestamp_seconds %d\n", stats.LastProcessedAt.Unix())
+	}
+	return b.String()
+}
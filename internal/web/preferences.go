@@ -0,0 +1,149 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+)
+
+const (
+	// clientTokenHeader carries the random token a browser saves its
+	// preferences under when it has no API key
+	clientTokenHeader = "X-Client-Token"
+
+	// maxPreferencesSize caps the stored preferences of one owner
+	maxPreferencesSize = 16 << 10
+)
+
+// clientTokenPattern keeps browser tokens to something a browser generated
+var clientTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{16,128}$`)
+
+// getPreferences returns the dashboard preferences of the requesting API key
+// or browser, an empty object until some are saved
+func (s *Server) getPreferences(c *fiber.Ctx) error {
+	owner, err := preferencesOwner(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	prefs, err := s.db.GetPreferences(owner)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to load preferences",
+			"details": err.Error(),
+		})
+	}
+	if prefs == nil {
+		return c.JSON(fiber.Map{"preferences": fiber.Map{}})
+	}
+
+	return c.JSON(fiber.Map{
+		"preferences": json.RawMessage(prefs.Preferences),
+		"updated_at":  prefs.UpdatedAt,
+	})
+}
+
+// replacePreferences saves the request body as the requester's preferences
+func (s *Server) replacePreferences(c *fiber.Ctx) error {
+	return s.savePreferences(c, func(_, update map[string]json.RawMessage) map[string]json.RawMessage {
+		return update
+	})
+}
+
+// updatePreferences merges the request body into the requester's saved
+// preferences, so devices can each save the settings they changed. Keys set
+// to null are removed.
+func (s *Server) updatePreferences(c *fiber.Ctx) error {
+	return s.savePreferences(c, func(saved, update map[string]json.RawMessage) map[string]json.RawMessage {
+		for key, value := range update {
+			if string(value) == "null" {
+				delete(saved, key)
+			} else {
+				saved[key] = value
+			}
+		}
+		return saved
+	})
+}
+
+// savePreferences parses the request body as a JSON object, combines it with
+// the saved preferences and stores the result
+func (s *Server) savePreferences(c *fiber.Ctx, combine func(saved, update map[string]json.RawMessage) map[string]json.RawMessage) error {
+	owner, err := preferencesOwner(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var update map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &update); err != nil || update == nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Preferences must be a JSON object",
+		})
+	}
+
+	saved := make(map[string]json.RawMessage)
+	existing, err := s.db.GetPreferences(owner)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to load preferences",
+			"details": err.Error(),
+		})
+	}
+	if existing != nil {
+		if err := json.Unmarshal([]byte(existing.Preferences), &saved); err != nil {
+			s.logger.Warn("Discarding unreadable saved preferences", "error", err)
+			saved = make(map[string]json.RawMessage)
+		}
+	}
+
+	data, err := json.Marshal(combine(saved, update))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid preferences",
+			"details": err.Error(),
+		})
+	}
+	if len(data) > maxPreferencesSize {
+		return c.Status(413).JSON(fiber.Map{
+			"error": fmt.Sprintf("Preferences cannot be larger than %d KB", maxPreferencesSize>>10),
+		})
+	}
+
+	prefs := &database.UserPreferences{Owner: owner, Preferences: string(data)}
+	if err := s.db.SavePreferences(prefs); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to save preferences",
+			"details": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"preferences": json.RawMessage(prefs.Preferences),
+		"updated_at":  prefs.UpdatedAt,
+	})
+}
+
+// preferencesOwner names whose preferences a request reads and writes: the
+// API key's, which follow the key across devices, or else the browser's token
+func preferencesOwner(c *fiber.Ctx) (string, error) {
+	if record := requestKey(c); record != nil {
+		return fmt.Sprintf("key:%d", record.ID), nil
+	}
+
+	token := c.Get(clientTokenHeader)
+	if token == "" {
+		return "", fmt.Errorf("An API key or %s header is required", clientTokenHeader)
+	}
+	if !clientTokenPattern.MatchString(token) {
+		return "", fmt.Errorf("%s must be 16 to 128 letters, digits, dashes or underscores", clientTokenHeader)
+	}
+	return "token:" + token, nil
+}
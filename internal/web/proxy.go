@@ -0,0 +1,111 @@
+package web
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// basePathKey holds the base path in each request's locals
+const basePathKey = "base_path"
+
+// stripBasePath serves Meiko under web.base_path, for reverse proxies that
+// pass a subpath such as /meiko through. The base path is removed before
+// routing, so every route and static file works unchanged. Requests without
+// it are served as well, for proxies that strip the subpath themselves.
+func (s *Server) stripBasePath(c *fiber.Ctx) error {
+	base := s.config.Web.BasePath
+	c.Locals(basePathKey, base)
+
+	path := c.Path()
+	switch {
+	case path == base:
+		// Relative links in the dashboard need the trailing slash
+		return c.Redirect(base+"/", fiber.StatusMovedPermanently)
+	case strings.HasPrefix(path, base+"/"):
+		c.Path(strings.TrimPrefix(path, base))
+	}
+	return c.Next()
+}
+
+// requestBaseURL returns the scheme, host and base path clients reach Meiko
+// at, for building absolute links
+func requestBaseURL(c *fiber.Ctx) string {
+	return c.BaseURL() + requestBasePath(c)
+}
+
+// requestBasePath returns the base path clients reach Meiko under, empty
+// when it is served at the root
+func requestBasePath(c *fiber.Ctx) string {
+	base, _ := c.Locals(basePathKey).(string)
+	return base
+}
+
+// parseTrustedProxies parses web.trusted_proxies, already validated as IP
+// addresses or CIDR ranges, into ranges
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var ranges []*net.IPNet
+	for _, proxy := range proxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ranges = append(ranges, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			ranges = append(ranges, network)
+		}
+	}
+	return ranges
+}
+
+// trustedProxy reports whether an address is one of web.trusted_proxies
+func (s *Server) trustedProxy(ip net.IP) bool {
+	for _, network := range s.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address of the client making a request. Behind
+// trusted proxies, proxy_header is read from the right, skipping the proxies'
+// own addresses: proxies such as nginx append to the header the client sent,
+// so only the entries they added can be believed.
+func (s *Server) clientIP(c *fiber.Ctx) string {
+	remote := c.Context().RemoteIP()
+	header := s.config.Web.ProxyHeader
+	if header == "" {
+		return remote.String()
+	}
+	value := c.Get(header)
+	if value == "" {
+		return remote.String()
+	}
+
+	// Without trusted_proxies every request's header is believed
+	if len(s.trustedProxies) == 0 {
+		return value
+	}
+	if !s.trustedProxy(remote) {
+		return remote.String()
+	}
+
+	client := remote
+	entries := strings.Split(value, ",")
+	for i := len(entries) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(entries[i]))
+		if ip == nil {
+			break
+		}
+		client = ip
+		if !s.trustedProxy(ip) {
+			break
+		}
+	}
+	return client.String()
+}
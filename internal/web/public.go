@@ -0,0 +1,121 @@
+package web
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/config"
+	"Meiko/internal/corrections"
+	"Meiko/internal/database"
+	meikoLogger "Meiko/internal/logger"
+)
+
+// defaultRedactions hide unit identifiers and the names of responders from
+// transcripts in public mode
+var defaultRedactions = []config.CorrectionRuleConfig{
+	{ // Numbered apparatus and units, e.g. "Medic 12" or "unit 4B"
+		Find:    `(?i)\b(unit|medic|engine|ladder|truck|squad|rescue|tower|ambulance|battalion|car|badge)\s*#?\s*\d+[a-z]?\b`,
+		Replace: "${1} [unit]",
+		Regex:   true,
+	},
+	{ // Phonetic call signs, e.g. "1 Adam 12" or "4-David-7"
+		Find:    `(?i)\b\d{1,3}[\s-]*(adam|boy|baker|charlie|david|edward|frank|george|henry|ida|john|king|lincoln|mary|nora|ocean|paul|queen|robert|sam|tom|union|victor|william|x-ray|young|zebra)[\s-]*\d{1,3}\b`,
+		Replace: "[unit]",
+		Regex:   true,
+	},
+	{ // Responders named with their rank, e.g. "Deputy Smith"
+		Find:    `\b((?i:officer|deputy|trooper|sergeant|sgt|lieutenant|lt|captain|capt|detective|corporal|chief)\.?)\s+[A-Z][a-zA-Z'-]+`,
+		Replace: "${1} [name]",
+		Regex:   true,
+	},
+}
+
+// newRedactor builds the correction engine that redacts transcripts in
+// public mode
+func newRedactor(cfg config.WebPublicConfig, logger *meikoLogger.Logger) (*corrections.Engine, error) {
+	var rules []config.CorrectionRuleConfig
+	if !cfg.ReplaceDefaults {
+		rules = append(rules, defaultRedactions...)
+	}
+	rules = append(rules, cfg.Redactions...)
+	return corrections.New(config.CorrectionsConfig{Rules: rules}, nil, logger)
+}
+
+// publicMode reports whether the dashboard is shared publicly
+func (s *Server) publicMode() bool {
+	return s.config.Web.Public.Enabled
+}
+
+// publicNow is the newest time calls are shown for: now, or in public mode
+// now less the delay
+func (s *Server) publicNow() time.Time {
+	if !s.publicMode() {
+		return time.Now()
+	}
+	return time.Now().Add(-time.Duration(s.config.Web.Public.Delay) * time.Minute)
+}
+
+// publicEnd limits the end of a time range to what public mode may show
+func (s *Server) publicEnd(end time.Time) time.Time {
+	if now := s.publicNow(); end.After(now) {
+		return now
+	}
+	return end
+}
+
+// hiddenCall reports whether a call is too recent to show in public mode
+func (s *Server) hiddenCall(call *database.CallRecord) bool {
+	return s.publicMode() && call.Timestamp.After(s.publicNow())
+}
+
+// hideInPublic replaces a route's handler in public mode, so admin and debug
+// endpoints answer as if they didn't exist
+func (s *Server) hideInPublic(handler fiber.Handler) fiber.Handler {
+	if !s.publicMode() {
+		return handler
+	}
+	return func(c *fiber.Ctx) error {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Not available in public mode",
+		})
+	}
+}
+
+// redact applies the redaction rules, and in public mode the public
+// redactions, to text from a talkgroup
+func (s *Server) redact(talkgroupID, text string) string {
+	text = s.redaction.Text(talkgroupID, text)
+	if s.redactor == nil {
+		return text
+	}
+	return s.redactor.Apply(talkgroupID, text)
+}
+
+// apiCall converts a database call into its API representation, with
+// sensitive details redacted
+func (s *Server) apiCall(call *database.CallRecord) CallRecord {
+	record := newCallRecord(s.redaction.Call(call))
+	if s.redactor == nil {
+		return record
+	}
+
+	record.Filepath = ""
+	record.LastError = ""
+	record.Transcription = s.redactor.Apply(call.TalkgroupID, record.Transcription)
+	record.Translation = s.redactor.Apply(call.TalkgroupID, record.Translation)
+	if len(record.Segments) > 0 {
+		segments := make([]database.SpeakerSegment, len(record.Segments))
+		for i, segment := range record.Segments {
+			segment.Text = s.redactor.Apply(call.TalkgroupID, segment.Text)
+			segments[i] = segment
+		}
+		record.Segments = segments
+	}
+	if record.Enrichment != nil {
+		enrichment := *record.Enrichment
+		enrichment.Units = nil
+		record.Enrichment = &enrichment
+	}
+	return record
+}
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// rateLimiters returns the per-client API limiters: one for requests with a
+// verified API key and one per IP address for everything else
+func (s *Server) rateLimiters() []fiber.Handler {
+	cfg := s.config.Web.RateLimit
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return []fiber.Handler{
+		limiter.New(limiter.Config{
+			Next:         func(c *fiber.Ctx) bool { return requestKey(c) != nil },
+			Max:          cfg.RequestsPerMinute,
+			Expiration:   time.Minute,
+			KeyGenerator: s.clientKey,
+			LimitReached: s.limitReached("api"),
+		}),
+		limiter.New(limiter.Config{
+			Next:         func(c *fiber.Ctx) bool { return requestKey(c) == nil },
+			Max:          cfg.KeyRequestsPerMinute,
+			Expiration:   time.Minute,
+			KeyGenerator: s.clientKey,
+			LimitReached: s.limitReached("api"),
+		}),
+	}
+}
+
+// aiRateLimit returns a stricter limiter for endpoints that call the LLM
+func (s *Server) aiRateLimit() fiber.Handler {
+	cfg := s.config.Web.RateLimit
+	if !cfg.Enabled {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+
+	return limiter.New(limiter.Config{
+		Max:          cfg.AIRequestsPerMinute,
+		Expiration:   time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string { return "ai:" + s.clientKey(c) },
+		LimitReached: s.limitReached("ai"),
+	})
+}
+
+// clientKey identifies a client by API key when one was verified, otherwise by IP
+func (s *Server) clientKey(c *fiber.Ctx) string {
+	if record := requestKey(c); record != nil {
+		return fmt.Sprintf("key:%d", record.ID)
+	}
+	return "ip:" + s.clientIP(c)
+}
+
+// limitReached returns the handler used when a client exceeds a limit
+func (s *Server) limitReached(limit string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		s.logger.Warn("Rate limit exceeded", "limit", limit, "client", s.clientKey(c), "path", c.Path())
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error":   "Rate limit exceeded",
+			"details": fmt.Sprintf("Too many %s requests; retry after %s seconds", limit, c.GetRespHeader(fiber.HeaderRetryAfter, "60")),
+		})
+	}
+}
+
+// logRequests logs each API request once it has been handled
+func (s *Server) logRequests(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		} else {
+			status = fiber.StatusInternalServerError
+		}
+	}
+
+	s.logger.Info("HTTP request",
+		"method", c.Method(),
+		"path", c.Path(),
+		"status", status,
+		"duration", time.Since(start).Round(time.Millisecond),
+		"client", s.clientKey(c))
+	return err
+}
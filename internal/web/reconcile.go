@@ -0,0 +1,78 @@
+package web
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/storage"
+)
+
+// SetReconciler sets the reconciler that compares the database with the
+// recordings on disk
+func (s *Server) SetReconciler(reconciler *storage.Reconciler) {
+	s.reconciler = reconciler
+}
+
+// reconcileNow compares the database with the recordings on disk right away
+func (s *Server) reconcileNow(c *fiber.Ctx) error {
+	if s.reconciler == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Reconciliation is not enabled",
+		})
+	}
+
+	report, err := s.reconciler.Run(c.Context())
+	if errors.Is(err, storage.ErrReconcileRunning) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to reconcile recordings",
+			"details": err.Error(),
+		})
+	}
+	return c.JSON(report)
+}
+
+// cleanupReconciled deletes the calls whose recordings are missing and the
+// recordings no call refers to, as found by the last check
+func (s *Server) cleanupReconciled(c *fiber.Ctx) error {
+	if s.reconciler == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Reconciliation is not enabled",
+		})
+	}
+
+	var req struct {
+		Calls      bool `json:"calls"`      // Delete calls missing their recording
+		Recordings bool `json:"recordings"` // Delete orphaned recordings
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if !req.Calls && !req.Recordings {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Nothing to clean up",
+			"details": "Set calls, recordings or both",
+		})
+	}
+
+	result, err := s.reconciler.Cleanup(c.Context(), req.Calls, req.Recordings)
+	if errors.Is(err, storage.ErrReconcileRunning) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to clean up",
+			"details": err.Error(),
+			"result":  result,
+		})
+	}
+	if result.DeletedCalls > 0 {
+		s.clearTimelineCache()
+	}
+	return c.JSON(result)
+}
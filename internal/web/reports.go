@@ -0,0 +1,96 @@
+package web
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/archive"
+)
+
+// defaultShiftHours is the shift length used when only a start time is given
+const defaultShiftHours = 12
+
+// getReport renders a daily or shift report for a date.
+// Query parameters: format (html, pdf, markdown), start (HH:MM shift start),
+// hours (shift length) and summary (false to skip the AI summary).
+func (s *Server) getReport(c *fiber.Ctx) error {
+	date, err := time.ParseInLocation("2006-01-02", c.Params("date"), time.Local)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid date format. Use YYYY-MM-DD",
+		})
+	}
+
+	start, end := date, date.AddDate(0, 0, 1)
+	if shiftStart := c.Query("start"); shiftStart != "" {
+		at, err := time.Parse("15:04", shiftStart)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "Invalid shift start. Use HH:MM",
+			})
+		}
+		hours := c.QueryInt("hours", defaultShiftHours)
+		if hours < 1 || hours > 24 {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "Shift length must be between 1 and 24 hours",
+			})
+		}
+		start = time.Date(date.Year(), date.Month(), date.Day(), at.Hour(), at.Minute(), 0, 0, time.Local)
+		end = start.Add(time.Duration(hours) * time.Hour)
+	}
+
+	var summarizer archive.Summarizer
+	if s.llm != nil && c.QueryBool("summary", true) {
+		summarizer = s
+	}
+
+	baseURL := s.config.Archive.BaseURL
+	if baseURL == "" {
+		baseURL = requestBaseURL(c)
+	}
+
+	builder := archive.NewBuilder(s.db, summarizer, baseURL, s.config.Archive.MinSeverity, s.logger)
+	report, err := builder.Build(start, end)
+	if err != nil {
+		s.logger.Error("Failed to build report", "error", err, "date", c.Params("date"))
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to build report",
+			"details": err.Error(),
+		})
+	}
+
+	var content []byte
+	switch format := c.Query("format", "html"); format {
+	case "html":
+		content, err = archive.RenderHTML(report)
+		c.Set(fiber.HeaderContentType, "text/html; charset=utf-8")
+	case "markdown":
+		content, err = archive.RenderMarkdown(report)
+		c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+	case "pdf":
+		content, err = archive.RenderPDF(report)
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Attachment(reportFilename(report) + ".pdf")
+	default:
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid format. Use html, pdf or markdown",
+		})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to render report",
+			"details": err.Error(),
+		})
+	}
+
+	return c.Send(content)
+}
+
+// reportFilename returns a download name for a report
+func reportFilename(report *archive.Report) string {
+	if report.Shift {
+		return "meiko-shift-" + report.Date.Format("2006-01-02-1504")
+	}
+	return "meiko-daily-" + report.Date.Format("2006-01-02")
+}
@@ -0,0 +1,113 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// replaySize is how many sequenced messages are kept for clients resuming
+// after a brief disconnect
+const replaySize = 1000
+
+// hubMessage is an encoded message with its sequence number, which is 0 for
+// messages outside the sequence such as stats updates
+type hubMessage struct {
+	seq  uint64
+	data []byte
+}
+
+// replayBuffer numbers broadcast messages and keeps the latest, so a client
+// that reconnects can receive what it missed instead of reloading. Sequence
+// numbers restart with the server, which the epoch identifies.
+type replayBuffer struct {
+	mu       sync.Mutex
+	epoch    string
+	seq      uint64
+	messages []hubMessage // Oldest first, at most replaySize
+}
+
+// newReplayBuffer creates an empty buffer with a new epoch
+func newReplayBuffer() *replayBuffer {
+	epoch := make([]byte, 8)
+	rand.Read(epoch)
+	return &replayBuffer{epoch: hex.EncodeToString(epoch)}
+}
+
+// sequence gives an encoded message the next sequence number, adding it to
+// the envelope as seq, and keeps it for replay
+func (r *replayBuffer) sequence(data []byte) hubMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	message := hubMessage{seq: r.seq, data: withSeq(data, r.seq)}
+	r.messages = append(r.messages, message)
+	if len(r.messages) > replaySize {
+		r.messages = r.messages[len(r.messages)-replaySize:]
+	}
+	return message
+}
+
+// position returns the epoch and the latest sequence number
+func (r *replayBuffer) position() (string, uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.epoch, r.seq
+}
+
+// since returns the messages after seq in an epoch, along with the latest
+// sequence number. It reports false when the client can't resume because the
+// server restarted or the messages it missed are no longer kept; the client
+// must then backfill from the REST API.
+func (r *replayBuffer) since(epoch string, seq uint64) ([]hubMessage, uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if epoch != r.epoch || seq > r.seq {
+		return nil, r.seq, false
+	}
+	oldest := r.seq + 1
+	if len(r.messages) > 0 {
+		oldest = r.messages[0].seq
+	}
+	if seq+1 < oldest {
+		return nil, r.seq, false
+	}
+
+	missed := make([]hubMessage, 0, r.seq-seq)
+	for _, message := range r.messages {
+		if message.seq > seq {
+			missed = append(missed, message)
+		}
+	}
+	return missed, r.seq, true
+}
+
+// withSeq adds a sequence number to an encoded message. Every message is a
+// JSON object, so the field goes right after the opening brace.
+func withSeq(data []byte, seq uint64) []byte {
+	return append([]byte(fmt.Sprintf(`{"seq":%d,`, seq)), data[1:]...)
+}
+
+// eventID formats a sequence position as a Server-Sent Events ID, which the
+// browser sends back as Last-Event-ID when it reconnects
+func eventID(epoch string, seq uint64) string {
+	return epoch + ":" + strconv.FormatUint(seq, 10)
+}
+
+// parseEventID reads a position formatted by eventID
+func parseEventID(id string) (string, uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, ":")
+	if !ok {
+		return "", 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return epoch, n, true
+}
@@ -0,0 +1,300 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/config"
+	"Meiko/internal/database"
+)
+
+const (
+	// scannerQueueWindow is how far back a session's queue reaches, so a
+	// listener who steps away isn't left with hours of backlog
+	scannerQueueWindow = 15 * time.Minute
+
+	// scannerMaxQueue caps the calls considered for a session's queue
+	scannerMaxQueue = 200
+
+	// scannerSessionTTL is how long an idle session is kept
+	scannerSessionTTL = 30 * time.Minute
+
+	// maxScannerSessions caps the sessions held in memory
+	maxScannerSessions = 1000
+)
+
+// scannerSession is one listener's live scanner: the talkgroups they muted
+// and the calls they have already heard
+type scannerSession struct {
+	ID       string    `json:"id"`
+	Since    time.Time `json:"since"` // Calls before the session started are not queued
+	Mutes    []string  `json:"mutes"`
+	LastSeen time.Time `json:"last_seen"`
+
+	played map[int]time.Time // Played call IDs and their timestamps, for pruning
+}
+
+// scannerSessions holds the live scanner sessions in memory. They are not
+// persisted; a restart starts every listener afresh.
+type scannerSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*scannerSession
+}
+
+// scannerSessionRequest sets a session's muted talkgroups
+type scannerSessionRequest struct {
+	Mutes []string `json:"mutes"`
+}
+
+// createScannerSession starts a live scanner session that queues calls from now on
+func (s *Server) createScannerSession(c *fiber.Ctx) error {
+	var req scannerSessionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"details": err.Error(),
+			})
+		}
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to create session",
+			"details": err.Error(),
+		})
+	}
+
+	now := time.Now()
+	session := &scannerSession{
+		ID:       hex.EncodeToString(id),
+		Since:    s.publicNow(),
+		Mutes:    mutesOrEmpty(req.Mutes),
+		LastSeen: now,
+		played:   make(map[int]time.Time),
+	}
+
+	s.scanner.mu.Lock()
+	defer s.scanner.mu.Unlock()
+
+	for id, existing := range s.scanner.sessions {
+		if now.Sub(existing.LastSeen) > scannerSessionTTL {
+			delete(s.scanner.sessions, id)
+		}
+	}
+	if len(s.scanner.sessions) >= maxScannerSessions {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Too many live scanner sessions",
+		})
+	}
+	s.scanner.sessions[session.ID] = session
+
+	return c.Status(201).JSON(session)
+}
+
+// getScannerSession returns a session with the number of calls waiting for it
+func (s *Server) getScannerSession(c *fiber.Ctx) error {
+	return s.withScannerSession(c, func(session *scannerSession) error {
+		queue, err := s.scannerQueue(session)
+		if err != nil {
+			return scannerQueueError(c, err)
+		}
+		return c.JSON(fiber.Map{
+			"session": session,
+			"queued":  len(queue),
+		})
+	})
+}
+
+// updateScannerMutes replaces a session's muted talkgroups
+func (s *Server) updateScannerMutes(c *fiber.Ctx) error {
+	var req scannerSessionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	return s.withScannerSession(c, func(session *scannerSession) error {
+		session.Mutes = mutesOrEmpty(req.Mutes)
+		return c.JSON(session)
+	})
+}
+
+// deleteScannerSession ends a session
+func (s *Server) deleteScannerSession(c *fiber.Ctx) error {
+	return s.withScannerSession(c, func(session *scannerSession) error {
+		delete(s.scanner.sessions, session.ID)
+		return c.SendStatus(204)
+	})
+}
+
+// getScannerQueue lists the calls a session has yet to play, in playback order
+func (s *Server) getScannerQueue(c *fiber.Ctx) error {
+	return s.withScannerSession(c, func(session *scannerSession) error {
+		queue, err := s.scannerQueue(session)
+		if err != nil {
+			return scannerQueueError(c, err)
+		}
+
+		calls := make([]CallRecord, len(queue))
+		for i, call := range queue {
+			calls[i] = s.apiCall(call)
+		}
+		return c.JSON(fiber.Map{"calls": calls})
+	})
+}
+
+// getScannerNext returns the next call a session should play, or 204 when
+// it is caught up. The call is not marked as played until the client says so.
+func (s *Server) getScannerNext(c *fiber.Ctx) error {
+	return s.withScannerSession(c, func(session *scannerSession) error {
+		queue, err := s.scannerQueue(session)
+		if err != nil {
+			return scannerQueueError(c, err)
+		}
+		if len(queue) == 0 {
+			return c.SendStatus(204)
+		}
+
+		return c.JSON(fiber.Map{
+			"call":      s.apiCall(queue[0]),
+			"remaining": len(queue) - 1,
+		})
+	})
+}
+
+// markScannerPlayed records that a session played a call, so it leaves the queue
+func (s *Server) markScannerPlayed(c *fiber.Ctx) error {
+	callID, err := strconv.Atoi(c.Params("callId"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid call ID",
+		})
+	}
+
+	call, err := s.db.GetCallRecord(callID)
+	if err != nil || s.hiddenCall(call) {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Call record not found",
+		})
+	}
+
+	return s.withScannerSession(c, func(session *scannerSession) error {
+		session.played[call.ID] = call.Timestamp
+		return c.SendStatus(204)
+	})
+}
+
+// withScannerSession runs fn with the session named in the request, holding
+// the session lock and keeping the session alive
+func (s *Server) withScannerSession(c *fiber.Ctx, fn func(session *scannerSession) error) error {
+	s.scanner.mu.Lock()
+	defer s.scanner.mu.Unlock()
+
+	session, ok := s.scanner.sessions[c.Params("id")]
+	if !ok || time.Since(session.LastSeen) > scannerSessionTTL {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Live scanner session not found",
+		})
+	}
+	session.LastSeen = time.Now()
+	return fn(session)
+}
+
+// scannerQueue returns the calls a session has yet to play: calls since it
+// started, within the queue window, on talkgroups it hasn't muted. Priority
+// talkgroups play first, then higher priority calls, then the oldest first,
+// as a scanner would.
+func (s *Server) scannerQueue(session *scannerSession) ([]*database.CallRecord, error) {
+	end := s.publicNow()
+	start := end.Add(-scannerQueueWindow)
+	if session.Since.After(start) {
+		start = session.Since
+	}
+
+	// Calls played before the window can't be queued again
+	for id, timestamp := range session.played {
+		if timestamp.Before(start) {
+			delete(session.played, id)
+		}
+	}
+
+	calls, err := s.db.GetCallRecords(database.CallFilter{
+		Start:  &start,
+		End:    &end,
+		Status: database.CallStatusProcessed,
+	}, scannerMaxQueue, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	muted := make(map[string]bool, len(session.Mutes))
+	for _, id := range session.Mutes {
+		muted[id] = true
+	}
+
+	queue := make([]*database.CallRecord, 0, len(calls))
+	priority := make(map[string]bool)
+	for _, call := range calls {
+		if muted[call.TalkgroupID] {
+			continue
+		}
+		if _, ok := session.played[call.ID]; ok {
+			continue
+		}
+		if _, ok := priority[call.TalkgroupID]; !ok {
+			priority[call.TalkgroupID] = s.talkgroupFilter(call.TalkgroupID).Priority
+		}
+		queue = append(queue, call)
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, b := queue[i], queue[j]
+		if priority[a.TalkgroupID] != priority[b.TalkgroupID] {
+			return priority[a.TalkgroupID]
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return a.ID < b.ID
+	})
+
+	return queue, nil
+}
+
+// talkgroupFilter returns a talkgroup's filter settings, including overrides
+// made in the dashboard when talkgroup data is loaded
+func (s *Server) talkgroupFilter(talkgroupID string) config.TalkgroupFilterConfig {
+	if s.talkgroups != nil {
+		return s.talkgroups.GetTalkgroupFilter(talkgroupID)
+	}
+	return s.config.GetTalkgroupFilter(talkgroupID)
+}
+
+// scannerQueueError reports a failure to build a session's queue
+func scannerQueueError(c *fiber.Ctx, err error) error {
+	return c.Status(500).JSON(fiber.Map{
+		"error":   "Failed to build the scanner queue",
+		"details": err.Error(),
+	})
+}
+
+// mutesOrEmpty keeps muted talkgroups serialized as a list rather than null
+func mutesOrEmpty(mutes []string) []string {
+	if mutes == nil {
+		return []string{}
+	}
+	return mutes
+}
@@ -0,0 +1,162 @@
+package web
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/embeddings"
+)
+
+// SetEmbeddings sets the service used for semantic search
+func (s *Server) SetEmbeddings(service *embeddings.Service) {
+	s.embeddings = service
+}
+
+// searchSemantic finds calls by meaning rather than exact words.
+// Query parameters: q (text to search for) or call_id (find calls like this
+// one), range (default week) and limit (default 20, max 100).
+func (s *Server) searchSemantic(c *fiber.Ctx) error {
+	if s.embeddings == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Semantic search is not enabled",
+		})
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	callID := c.QueryInt("call_id", 0)
+	if (query == "") == (callID == 0) {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Provide either q or call_id",
+		})
+	}
+
+	tr, err := s.parseTimeRange(c.Query("range", "week"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid time range",
+			"details": err.Error(),
+		})
+	}
+
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "limit must be between 1 and 100",
+		})
+	}
+
+	var matches []embeddings.Match
+	if query != "" {
+		matches, err = s.embeddings.Search(c.Context(), query, tr.Start, tr.End, limit)
+	} else {
+		matches, err = s.embeddings.Similar(callID, tr.Start, tr.End, limit)
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Semantic search failed",
+			"details": err.Error(),
+		})
+	}
+
+	ids := make([]int, len(matches))
+	for i, match := range matches {
+		ids[i] = match.CallID
+	}
+	calls, err := s.db.GetCallRecordsByIDs(ids)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch calls",
+			"details": err.Error(),
+		})
+	}
+
+	similarity := make(map[int]float64, len(matches))
+	for _, match := range matches {
+		similarity[match.CallID] = match.Similarity
+	}
+	results := make([]fiber.Map, len(calls))
+	for i, call := range calls {
+		results[i] = fiber.Map{
+			"call":       s.apiCall(call),
+			"similarity": similarity[call.ID],
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"results": results,
+		"start":   tr.Start,
+		"end":     tr.End,
+		"total":   len(results),
+	})
+}
+
+// getClusters groups related calls, such as the radio traffic of one incident.
+// Query parameters: range (default today), min_size (default 2) and limit
+// (clusters returned, default 20, max 100).
+func (s *Server) getClusters(c *fiber.Ctx) error {
+	if s.embeddings == nil {
+		return c.Status(503).JSON(fiber.Map{
+			"error": "Semantic search is not enabled",
+		})
+	}
+
+	tr, err := s.parseTimeRange(c.Query("range", "today"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid time range",
+			"details": err.Error(),
+		})
+	}
+
+	minSize := c.QueryInt("min_size", 2)
+	if minSize < 1 {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "min_size must be at least 1",
+		})
+	}
+	limit := c.QueryInt("limit", 20)
+	if limit < 1 || limit > 100 {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "limit must be between 1 and 100",
+		})
+	}
+
+	clusters, err := s.embeddings.Clusters(tr.Start, tr.End, minSize)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to cluster calls",
+			"details": err.Error(),
+		})
+	}
+	total := len(clusters)
+	if len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+
+	results := make([]fiber.Map, 0, len(clusters))
+	for _, cluster := range clusters {
+		calls, err := s.db.GetCallRecordsByIDs(cluster.CallIDs)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"error":   "Failed to fetch calls",
+				"details": err.Error(),
+			})
+		}
+		records := make([]CallRecord, len(calls))
+		for i, call := range calls {
+			records[i] = s.apiCall(call)
+		}
+		results = append(results, fiber.Map{
+			"size":  len(records),
+			"calls": records,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"clusters": results,
+		"start":    tr.Start,
+		"end":      tr.End,
+		"total":    total,
+	})
+}
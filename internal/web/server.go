@@ -6,25 +6,34 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"net"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/websocket/v2"
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
 
+	"Meiko/internal/apikeys"
 	"Meiko/internal/config"
+	"Meiko/internal/corrections"
 	"Meiko/internal/database"
+	"Meiko/internal/embeddings"
+	"Meiko/internal/frequency"
+	"Meiko/internal/llm"
 	meikoLogger "Meiko/internal/logger"
 	"Meiko/internal/monitoring"
+	"Meiko/internal/redaction"
+	"Meiko/internal/sdrtrunk"
+	"Meiko/internal/storage"
 	"Meiko/internal/talkgroups"
+	"Meiko/internal/textutil"
 )
 
 // AutoSummary represents an automatically generated summary
@@ -37,18 +46,39 @@ type AutoSummary struct {
 
 // Server represents the web server instance
 type Server struct {
-	app             *fiber.App
-	config          *config.Config
-	db              *database.Database
-	monitor         *monitoring.Monitor
-	talkgroups      *talkgroups.Service
-	logger          *meikoLogger.Logger
-	clients         map[*websocket.Conn]bool
-	broadcast       chan []byte
-	gemini          *genai.Client
-	lastAutoSummary *AutoSummary
-	summaryMu       sync.RWMutex
-	mu              sync.RWMutex
+	app          *fiber.App
+	config       *config.Config
+	db           *database.Database
+	monitor      *monitoring.Monitor
+	talkgroups   *talkgroups.Service
+	logger       *meikoLogger.Logger
+	hub          *hub          // WebSocket and Server-Sent Events clients
+	replay       *replayBuffer // Numbers broadcasts and keeps the latest for resuming clients
+	broadcast    chan []byte
+	closing      chan struct{} // Closed by Stop to end open event streams
+	llm          llm.Provider  // nil when no LLM provider is configured
+	corrections  *corrections.Engine
+	redactor     *corrections.Engine // Redacts calls in public mode, nil otherwise
+	redaction    *redaction.Redactor // Hides sensitive details, nil when disabled
+	embeddings   *embeddings.Service // nil when semantic search is disabled
+	publicScopes []string
+	ingester     CallIngester
+	reprocessor  CallReprocessor
+	bulkRunning  atomic.Bool // Set while a background bulk call operation runs
+	scanner      *scannerSessions
+	dataModified atomic.Int64 // Unix time calls or system events last changed
+	sdrtrunk     *sdrtrunk.Supervisor
+	storage      *storage.Forecaster
+	audioArchive *storage.AudioArchiver
+	reconciler   *storage.Reconciler // nil when reconciliation is disabled
+	pipeline     PipelineReporter    // nil when this instance doesn't process calls
+
+	// Proxies whose proxy_header is believed
+	trustedProxies []*net.IPNet
+
+	// Components checked by /api/health, besides the built-in ones
+	healthChecks   []healthCheck
+	healthChecksMu sync.RWMutex
 
 	// Timeline caching
 	timelineCache    map[string]*TimelineCacheEntry
@@ -65,9 +95,10 @@ type Server struct {
 
 // TimelineCacheEntry represents a cached timeline response
 type TimelineCacheEntry struct {
-	Events    []TimelineEvent `json:"events"`
-	CachedAt  time.Time       `json:"cached_at"`
-	ExpiresAt time.Time       `json:"expires_at"`
+	Events     []TimelineEvent `json:"events"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	CachedAt   time.Time       `json:"cached_at"`
+	ExpiresAt  time.Time       `json:"expires_at"`
 }
 
 // TalkgroupCacheEntry represents cached talkgroup information
@@ -88,18 +119,73 @@ type TalkgroupInfo struct {
 
 // CallRecord represents a call record for API responses
 type CallRecord struct {
-	ID              int       `json:"id"`
-	Filename        string    `json:"filename"`
-	Filepath        string    `json:"filepath"`
-	Timestamp       time.Time `json:"timestamp"`
-	Duration        int       `json:"duration"`
-	Frequency       string    `json:"frequency"`
-	TalkgroupID     string    `json:"talkgroup_id"`
-	TalkgroupAlias  string    `json:"talkgroup_alias"`
-	TalkgroupGroup  string    `json:"talkgroup_group"`
-	TranscriptionID *int      `json:"transcription_id,omitempty"`
-	Transcription   string    `json:"transcription"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID               int                       `json:"id"`
+	Filename         string                    `json:"filename"`
+	Filepath         string                    `json:"filepath"`
+	Timestamp        time.Time                 `json:"timestamp"`
+	Duration         int                       `json:"duration"`
+	Frequency        string                    `json:"frequency"`
+	FrequencyHz      int64                     `json:"frequency_hz,omitempty"`
+	FrequencyDisplay string                    `json:"frequency_display,omitempty"` // e.g. "851.0125 MHz"
+	TalkgroupID      string                    `json:"talkgroup_id"`
+	TalkgroupAlias   string                    `json:"talkgroup_alias"`
+	TalkgroupGroup   string                    `json:"talkgroup_group"`
+	TranscriptionID  *int                      `json:"transcription_id,omitempty"`
+	Transcription    string                    `json:"transcription"`
+	Language         string                    `json:"language,omitempty"`    // Language the call was transcribed in
+	Translation      string                    `json:"translation,omitempty"` // English translation of a non-English transcription
+	Severity         int                       `json:"severity"`
+	Priority         int                       `json:"priority"`
+	Segments         []database.SpeakerSegment `json:"segments,omitempty"`
+	Tones            []database.ToneSequence   `json:"tones,omitempty"`
+	Enrichment       *database.Enrichment      `json:"enrichment,omitempty"` // Incident details extracted by the LLM
+	Site             string                    `json:"site,omitempty"`
+	SystemID         string                    `json:"system_id,omitempty"`
+	DuplicateOf      int                       `json:"duplicate_of,omitempty"` // Original call this is a simulcast duplicate of
+	AudioClass       string                    `json:"audio_class,omitempty"`  // "encrypted", "data" or "noise" for recordings that aren't speech
+	Processed        bool                      `json:"processed"`
+	Attempts         int                       `json:"attempts,omitempty"`   // Failed transcription attempts
+	LastError        string                    `json:"last_error,omitempty"` // Why the last transcription attempt failed
+	RetryAt          *time.Time                `json:"retry_at,omitempty"`   // When transcription is next retried
+	Failed           bool                      `json:"failed,omitempty"`     // Gave up transcribing after the last retry
+	Duplicates       []CallRecord              `json:"duplicates,omitempty"` // Simulcast duplicates of this call
+	CreatedAt        time.Time                 `json:"created_at"`
+}
+
+// newCallRecord converts a database call into its API representation
+func newCallRecord(call *database.CallRecord) CallRecord {
+	return CallRecord{
+		ID:               call.ID,
+		Filename:         call.Filename,
+		Filepath:         call.Filepath,
+		Timestamp:        call.Timestamp,
+		Duration:         call.Duration,
+		Frequency:        call.Frequency,
+		FrequencyHz:      call.FrequencyHz,
+		FrequencyDisplay: frequency.Display(call.Frequency),
+		TalkgroupID:      call.TalkgroupID,
+		TalkgroupAlias:   call.TalkgroupAlias,
+		TalkgroupGroup:   call.TalkgroupGroup,
+		TranscriptionID:  call.TranscriptionID,
+		Transcription:    call.Transcription,
+		Language:         call.Language,
+		Translation:      call.Translation,
+		Severity:         call.Severity,
+		Priority:         call.Priority,
+		Segments:         call.Segments,
+		Tones:            call.Tones,
+		Enrichment:       call.Enrichment,
+		Site:             call.Site,
+		SystemID:         call.SystemID,
+		DuplicateOf:      call.DuplicateOf,
+		AudioClass:       call.AudioClass,
+		Processed:        call.Processed,
+		Attempts:         call.Attempts,
+		LastError:        call.LastError,
+		RetryAt:          call.RetryAt,
+		Failed:           call.Failed,
+		CreatedAt:        call.CreatedAt,
+	}
 }
 
 // TimelineEvent represents an event in the timeline
@@ -123,16 +209,17 @@ type TimelineResponse struct {
 
 // SystemStats represents system statistics for API responses
 type SystemStats struct {
-	CPU         float64          `json:"cpu"`
-	Memory      float64          `json:"memory"`
-	Disk        float64          `json:"disk"`
-	Temperature float64          `json:"temperature"`
-	Uptime      time.Duration    `json:"uptime"`
-	TotalCalls  int64            `json:"total_calls"`
-	LastCall    *time.Time       `json:"last_call,omitempty"`
-	CallsToday  int64            `json:"calls_today"`
-	Frequencies map[string]int64 `json:"frequencies"`
-	Talkgroups  map[string]int64 `json:"talkgroups"`
+	CPU         float64             `json:"cpu"`
+	Memory      float64             `json:"memory"`
+	Disk        float64             `json:"disk"`
+	Temperature float64             `json:"temperature"` // Hottest sensor
+	Sensors     []monitoring.Sensor `json:"sensors,omitempty"`
+	Uptime      time.Duration       `json:"uptime"`
+	TotalCalls  int64               `json:"total_calls"`
+	LastCall    *time.Time          `json:"last_call,omitempty"`
+	CallsToday  int64               `json:"calls_today"`
+	Frequencies map[string]int64    `json:"frequencies"`
+	Talkgroups  map[string]int64    `json:"talkgroups"`
 }
 
 // TimeRange represents a time range filter
@@ -149,13 +236,35 @@ func New(cfg *config.Config, db *database.Database, monitor *monitoring.Monitor,
 		monitor:        monitor,
 		talkgroups:     talkgroups,
 		logger:         logger,
-		clients:        make(map[*websocket.Conn]bool),
-		broadcast:      make(chan []byte),
+		hub:            newHub(),
+		replay:         newReplayBuffer(),
+		broadcast:      make(chan []byte, 256),
+		closing:        make(chan struct{}),
 		timelineCache:  make(map[string]*TimelineCacheEntry),
 		talkgroupCache: make(map[string]*TalkgroupCacheEntry),
+		scanner:        &scannerSessions{sessions: make(map[string]*scannerSession)},
+	}
+	server.dataChanged()
+
+	if len(cfg.Web.APIKeys.PublicScopes) > 0 {
+		scopes, err := apikeys.ParseScopes(cfg.Web.APIKeys.PublicScopes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid web.api_keys.public_scopes: %w", err)
+		}
+		server.publicScopes = scopes
+	}
+
+	if cfg.Web.Public.Enabled {
+		redactor, err := newRedactor(cfg.Web.Public, logger)
+		if err != nil {
+			return nil, fmt.Errorf("invalid web.public_mode.redactions: %w", err)
+		}
+		server.redactor = redactor
+		logger.Info("Public mode enabled", "delay_minutes", cfg.Web.Public.Delay, "redactions", redactor.RuleCount())
 	}
 
 	// Initialize Fiber app
+	trustProxies := len(cfg.Web.TrustedProxies) > 0
 	server.app = fiber.New(fiber.Config{
 		AppName:                   "Meiko Web Dashboard",
 		ReadTimeout:               30 * time.Second,
@@ -172,29 +281,41 @@ func New(cfg *config.Config, db *database.Database, monitor *monitoring.Monitor,
 		CompressedFileSuffix:      ".meiko.gz",      // Custom compressed file suffix
 		ReduceMemoryUsage:         true,             // Optimize memory usage
 		Concurrency:               256 * 1024,       // Max concurrent connections
-		BodyLimit:                 4 * 1024 * 1024,  // 4MB body limit
-		EnableTrustedProxyCheck:   false,            // Skip proxy checks for performance
+		BodyLimit:                 bodyLimit(cfg),   // 4MB, or the ingest upload limit
+		EnableTrustedProxyCheck:   trustProxies,     // Only believe X-Forwarded-Proto and -Host from trusted_proxies
+		TrustedProxies:            cfg.Web.TrustedProxies,
+		// proxy_header is read by clientIP rather than c.IP(), which
+		// would believe the leftmost address the client sent
 	})
+	server.trustedProxies = parseTrustedProxies(cfg.Web.TrustedProxies)
 
 	// Add middleware
+	if cfg.Web.BasePath != "" {
+		server.app.Use(server.stripBasePath)
+	}
 	server.app.Use(recover.New())
-	server.app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
-	}))
-
-	// Initialize Gemini client if enabled
-	if cfg.Web.Gemini.Enabled && cfg.Web.Gemini.APIKey != "" {
-		ctx := context.Background()
-		client, err := genai.NewClient(ctx, option.WithAPIKey(cfg.Web.Gemini.APIKey))
+	if handler := server.compression(); handler != nil {
+		server.app.Use(handler)
+	}
+
+	server.app.Use(server.cors())
+
+	// Initialize the LLM provider for AI summaries
+	if cfg.LLM.Enabled() {
+		provider, err := llm.New(context.Background(), cfg.LLM)
 		if err != nil {
-			log.Printf("Failed to initialize Gemini client: %v", err)
+			logger.Error("Failed to initialize LLM provider", "provider", cfg.LLM.Provider, "error", err)
 		} else {
-			server.gemini = client
+			server.llm = provider
+			logger.Info("AI summaries enabled", "model", provider.Name())
 		}
 	}
 
+	// Reclassify with fresh playlist data when the playlist changes
+	if talkgroups != nil {
+		talkgroups.OnReload(server.clearTalkgroupCache)
+	}
+
 	// Setup routes
 	server.setupRoutes()
 
@@ -210,56 +331,167 @@ func New(cfg *config.Config, db *database.Database, monitor *monitoring.Monitor,
 	// Start hour summary generation routine
 	go server.hourSummaryRoutine()
 
+	// Start pre-rendering past days
+	if cfg.Web.History.Enabled {
+		go server.historyRoutine()
+	}
+
 	return server, nil
 }
 
+// bodyLimit returns the request body limit, raised to fit agent uploads when ingest is enabled
+func bodyLimit(cfg *config.Config) int {
+	limit := 4 * 1024 * 1024
+	if upload := (cfg.Ingest.MaxUploadMB + 1) * 1024 * 1024; cfg.Ingest.Enabled && upload > limit {
+		limit = upload
+	}
+	return limit
+}
+
 // setupRoutes configures all the API routes
 func (s *Server) setupRoutes() {
-	// Serve static files
-	s.app.Static("/", "./web/static")
-	s.app.Static("/static", "./web/static")
-
-	// API routes
-	api := s.app.Group("/api")
+	// API routes: identify the API key first so limits apply per key
+	handlers := []fiber.Handler{s.identifyAPIKey}
+	if s.config.Web.RequestLogging {
+		handlers = append(handlers, s.logRequests)
+	}
+	handlers = append(handlers, s.rateLimiters()...)
+	s.app.Use("/api", s.legacyAPI())
+	api := s.app.Group(apiPrefix, handlers...)
+	aiLimit := s.aiRateLimit()
+	readCalls := s.requireScope(apikeys.ScopeReadCalls)
+	readStats := s.requireScope(apikeys.ScopeReadStats)
+	editCalls := s.hideInPublic(s.requireScope(apikeys.ScopeEditCalls))
+	admin := s.hideInPublic(s.requireScope(apikeys.ScopeAdmin))
+
+	// Health check for uptime monitors and container probes; no key required
+	api.Get("/health", s.getHealth)
 
 	// Timeline endpoints
-	api.Get("/timeline", s.getTimeline)
-	api.Get("/timeline/:date", s.getTimelineForDate)
+	api.Get("/timeline", readCalls, s.revalidate(true), s.getTimeline)
+	api.Get("/timeline/:date", readCalls, s.revalidate(true), s.getTimelineForDate)
+
+	// Pre-rendered past days, served straight from disk
+	if s.config.Web.History.Enabled {
+		api.Use("/history", readCalls)
+		api.Static("/history", s.config.Web.History.Directory, fiber.Static{MaxAge: 3600})
+	}
 
 	// Call records endpoints
-	api.Get("/calls", s.getCalls)
-	api.Get("/calls/:id", s.getCall)
-	api.Get("/calls/:id/audio", s.getCallAudio)
-	api.Get("/calls/summary/:range", s.getCallsSummary)
+	api.Get("/calls", readCalls, s.revalidate(false), s.getCalls)
+	api.Post("/calls/retry-failed", admin, s.retryFailedCalls)
+	api.Post("/calls/bulk/:action", s.requireKeys, admin, s.bulkCalls)
+	api.Get("/calls/:id", readCalls, s.getCall)
+	api.Patch("/calls/:id", editCalls, s.editCall)
+	api.Get("/calls/:id/edits", editCalls, s.getCallEdits)
+	api.Get("/calls/:id/original", admin, s.getCallOriginal)
+	api.Get("/calls/:id/audio", readCalls, s.getCallAudio)
+	api.Get("/calls/summary/:range", readCalls, s.revalidate(false), s.getCallsSummary)
+	api.Get("/export", readCalls, s.exportCalls)
 
 	// Statistics endpoints
-	api.Get("/stats", s.getStats)
-	api.Get("/stats/lifetime", s.getLifetimeStats)
+	api.Get("/stats", readStats, s.revalidate(false), s.getStats)
+	api.Get("/stats/lifetime", readStats, s.revalidate(false), s.getLifetimeStats)
+	api.Get("/stats/heatmap", readStats, s.revalidate(false), s.getHeatmap)
 
 	// Auto-summary endpoints
-	api.Get("/summary/auto", s.getAutoSummary)
+	api.Get("/summary/auto", readCalls, s.getAutoSummary)
+	api.Get("/summaries", readCalls, s.getSummaries)
 
 	// System endpoints
-	api.Get("/system", s.getSystemInfo)
-	api.Get("/logs", s.getLogs)
+	api.Get("/system", readStats, s.getSystemInfo)
+	api.Post("/system/reconcile", admin, s.reconcileNow)
+	api.Post("/system/reconcile/cleanup", s.requireKeys, admin, s.cleanupReconciled)
+	api.Get("/systems", readStats, s.getSystems)
+	api.Get("/pipeline", readStats, s.getPipeline)
+	api.Get("/metrics", readStats, s.getMetrics)
+	api.Get("/logs", admin, s.getLogs)
 
 	// Live streaming endpoints
-	api.Get("/live/stream", s.getLiveStream)
-	api.Get("/live/status", s.getLiveStatus)
+	api.Get("/live/stream", readCalls, s.getLiveStream)
+	api.Get("/live/status", readCalls, s.getLiveStatus)
+
+	// Dashboard preferences, saved per API key or browser
+	preferences := s.hideInPublic(readCalls)
+	api.Get("/preferences", preferences, s.getPreferences)
+	api.Put("/preferences", preferences, s.replacePreferences)
+	api.Patch("/preferences", preferences, s.updatePreferences)
+
+	// Live scanner playback queues, one per listening session
+	api.Post("/scanner/sessions", readCalls, s.createScannerSession)
+	api.Get("/scanner/sessions/:id", readCalls, s.getScannerSession)
+	api.Delete("/scanner/sessions/:id", readCalls, s.deleteScannerSession)
+	api.Put("/scanner/sessions/:id/mutes", readCalls, s.updateScannerMutes)
+	api.Get("/scanner/sessions/:id/queue", readCalls, s.getScannerQueue)
+	api.Get("/scanner/sessions/:id/next", readCalls, s.getScannerNext)
+	api.Post("/scanner/sessions/:id/played/:callId", readCalls, s.markScannerPlayed)
 
 	// Debug endpoints (for development)
-	api.Post("/debug/broadcast-latest", s.debugBroadcastLatest)
+	api.Post("/debug/broadcast-latest", admin, s.debugBroadcastLatest)
 
-	// AI Summary endpoints (requires Gemini)
-	api.Post("/summary/generate", s.generateSummary)
+	// AI Summary endpoints (requires an LLM provider)
+	api.Post("/summary/generate", readCalls, aiLimit, s.generateSummary)
+	api.Post("/ask", readCalls, aiLimit, s.askQuestion)
+
+	// Semantic search endpoints (requires embeddings)
+	api.Get("/search/semantic", readCalls, aiLimit, s.searchSemantic)
+	api.Get("/search/clusters", readCalls, s.getClusters)
 
 	// Timeline-specific summary endpoints
-	api.Get("/timeline/summaries/:date", s.getTimelineSummaries)
-	api.Get("/timeline/summary/:date/:hour", s.getHourlySummary)
-	api.Post("/timeline/summary/generate", s.generateTimelineSummary)
+	api.Get("/timeline/summaries/:date", readCalls, s.getTimelineSummaries)
+	api.Get("/timeline/summary/:date/:hour", readCalls, s.getHourlySummary)
+	api.Post("/timeline/summary/generate", readCalls, aiLimit, s.generateTimelineSummary)
+
+	// Known frequencies
+	api.Get("/frequencies", readCalls, s.getFrequencies)
+	api.Post("/frequencies", admin, s.createFrequency)
+	api.Put("/frequencies/:id", admin, s.updateFrequency)
+	api.Delete("/frequencies/:id", admin, s.deleteFrequency)
+
+	// Transcription correction rules
+	api.Get("/corrections/rules", admin, s.getCorrectionRules)
+	api.Post("/corrections/rules", admin, s.createCorrectionRule)
+	api.Put("/corrections/rules/:id", admin, s.updateCorrectionRule)
+	api.Delete("/corrections/rules/:id", admin, s.deleteCorrectionRule)
+	api.Post("/corrections/test", admin, s.testCorrections)
+
+	// Talkgroup metadata and overrides
+	if s.talkgroups != nil {
+		api.Get("/talkgroups", readCalls, s.getTalkgroups)
+		api.Get("/talkgroups/:id", readCalls, s.getTalkgroup)
+		api.Put("/talkgroups/:id", admin, s.updateTalkgroup)
+		api.Delete("/talkgroups/:id/override", admin, s.deleteTalkgroupOverride)
+	}
+
+	// Report endpoints
+	api.Get("/reports/:date", readStats, aiLimit, s.getReport)
+
+	// API key management
+	api.Get("/keys", admin, s.getAPIKeys)
+	api.Post("/keys", admin, s.createAPIKey)
+	api.Delete("/keys/:id", admin, s.revokeAPIKey)
+
+	// Agent ingest endpoint
+	api.Post("/ingest/calls", s.requireScope(apikeys.ScopeIngest), s.ingestCall)
+
+	// Public incident and summary feeds, which need no API key
+	if s.config.Web.Feeds.Enabled {
+		api.Get("/feeds/incidents.:format", s.getIncidentFeed)
+		api.Get("/feeds/summaries.:format", s.getSummaryFeed)
+	}
+
+	// WebSocket schema catalog
+	api.Get("/ws/schema", s.getWebSocketSchema)
+
+	// OpenAPI specification and Swagger UI
+	api.Get("/docs", s.getAPIDocs)
+	api.Get("/docs/openapi.json", s.getOpenAPISpec)
+
+	// Server-Sent Events fallback for the WebSocket stream
+	api.Get("/events", readCalls, s.handleEventStream)
 
 	// WebSocket endpoint
-	s.app.Use("/ws", func(c *fiber.Ctx) error {
+	s.app.Use("/ws", s.identifyAPIKey, readCalls, func(c *fiber.Ctx) error {
 		if websocket.IsWebSocketUpgrade(c) {
 			c.Locals("allowed", true)
 			return c.Next()
@@ -267,39 +499,60 @@ func (s *Server) setupRoutes() {
 		return fiber.ErrUpgradeRequired
 	})
 	s.app.Get("/ws", websocket.New(s.handleWebSocket))
+	s.app.Get("/ws/logs", admin, s.checkLogStream, websocket.New(s.handleLogStream))
+
+	// Serve the dashboard last, so only paths no route handles reach it
+	s.serveDashboard()
 }
 
-// getTimeline returns timeline events for today
+// getTimeline returns timeline events for today. Later pages are fetched by
+// passing the previous response's next_cursor as cursor.
 func (s *Server) getTimeline(c *fiber.Ctx) error {
-	limit := c.QueryInt("limit", 50)
+	limit := pageSize(c)
+	cursor, err := timelineCursorParam(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid cursor",
+			"details": err.Error(),
+		})
+	}
 
 	// Get today's date
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	// Create cache key
+	// Create cache key; only first pages are cached
 	cacheKey := fmt.Sprintf("timeline_%s_%d", startOfDay.Format("2006-01-02"), limit)
 
 	// Check cache first
 	s.timelineCacheMu.RLock()
-	if cached, exists := s.timelineCache[cacheKey]; exists && time.Now().Before(cached.ExpiresAt) {
+	if cached, exists := s.timelineCache[cacheKey]; exists && cursor == nil && time.Now().Before(cached.ExpiresAt) {
 		s.timelineCacheMu.RUnlock()
 		response := TimelineResponse{
-			Events:  cached.Events,
-			HasMore: len(cached.Events) >= limit,
+			Events:     cached.Events,
+			HasMore:    cached.NextCursor != "",
+			NextCursor: cached.NextCursor,
 		}
 		return c.JSON(response)
 	}
 	s.timelineCacheMu.RUnlock()
 
-	events, err := s.buildTimelineEvents(&startOfDay, &endOfDay, limit)
+	events, next, err := s.buildTimelineEvents(&startOfDay, &endOfDay, cursor, limit)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "Failed to fetch timeline events",
 			"details": err.Error(),
 		})
 	}
+	response := TimelineResponse{
+		Events:     events,
+		HasMore:    next != "",
+		NextCursor: next,
+	}
+	if cursor != nil {
+		return c.JSON(response)
+	}
 
 	// Cache the results (5 minute cache for today, 1 hour for past days)
 	cacheExpiry := 5 * time.Minute
@@ -309,24 +562,28 @@ func (s *Server) getTimeline(c *fiber.Ctx) error {
 
 	s.timelineCacheMu.Lock()
 	s.timelineCache[cacheKey] = &TimelineCacheEntry{
-		Events:    events,
-		CachedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(cacheExpiry),
+		Events:     events,
+		NextCursor: next,
+		CachedAt:   time.Now(),
+		ExpiresAt:  time.Now().Add(cacheExpiry),
 	}
 	s.timelineCacheMu.Unlock()
 
-	response := TimelineResponse{
-		Events:  events,
-		HasMore: len(events) >= limit,
-	}
-
 	return c.JSON(response)
 }
 
-// getTimelineForDate returns timeline events for a specific date
+// getTimelineForDate returns timeline events for a specific date, paged like
+// getTimeline
 func (s *Server) getTimelineForDate(c *fiber.Ctx) error {
 	dateParam := c.Params("date")
-	limit := c.QueryInt("limit", 50)
+	limit := pageSize(c)
+	cursor, err := timelineCursorParam(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid cursor",
+			"details": err.Error(),
+		})
+	}
 
 	// Parse date (expected format: YYYY-MM-DD)
 	date, err := time.Parse("2006-01-02", dateParam)
@@ -344,12 +601,13 @@ func (s *Server) getTimelineForDate(c *fiber.Ctx) error {
 
 	// Check cache first
 	s.timelineCacheMu.RLock()
-	if cached, exists := s.timelineCache[cacheKey]; exists && time.Now().Before(cached.ExpiresAt) {
+	if cached, exists := s.timelineCache[cacheKey]; exists && cursor == nil && time.Now().Before(cached.ExpiresAt) {
 		s.timelineCacheMu.RUnlock()
 		s.logger.Debug("Timeline cache hit", "date", dateParam, "events", len(cached.Events))
 		response := TimelineResponse{
-			Events:  cached.Events,
-			HasMore: len(cached.Events) >= limit,
+			Events:     cached.Events,
+			HasMore:    cached.NextCursor != "",
+			NextCursor: cached.NextCursor,
 		}
 		return c.JSON(response)
 	}
@@ -357,7 +615,7 @@ func (s *Server) getTimelineForDate(c *fiber.Ctx) error {
 
 	log.Printf("Timeline request for %s (from %s to %s) with limit %d", dateParam, startOfDay.Format("2006-01-02 15:04:05"), endOfDay.Format("2006-01-02 15:04:05"), limit)
 
-	events, err := s.buildTimelineEvents(&startOfDay, &endOfDay, limit)
+	events, next, err := s.buildTimelineEvents(&startOfDay, &endOfDay, cursor, limit)
 	if err != nil {
 		log.Printf("Failed to build timeline events for %s: %v", dateParam, err)
 		return c.Status(500).JSON(fiber.Map{
@@ -367,6 +625,14 @@ func (s *Server) getTimelineForDate(c *fiber.Ctx) error {
 	}
 
 	log.Printf("Timeline response for %s: %d events", dateParam, len(events))
+	response := TimelineResponse{
+		Events:     events,
+		HasMore:    next != "",
+		NextCursor: next,
+	}
+	if cursor != nil {
+		return c.JSON(response)
+	}
 
 	// Cache the results (longer cache for past dates, shorter for today/future)
 	cacheExpiry := 1 * time.Hour // Default 1 hour
@@ -381,131 +647,253 @@ func (s *Server) getTimelineForDate(c *fiber.Ctx) error {
 
 	s.timelineCacheMu.Lock()
 	s.timelineCache[cacheKey] = &TimelineCacheEntry{
-		Events:    events,
-		CachedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(cacheExpiry),
+		Events:     events,
+		NextCursor: next,
+		CachedAt:   time.Now(),
+		ExpiresAt:  time.Now().Add(cacheExpiry),
 	}
 	s.timelineCacheMu.Unlock()
 
-	response := TimelineResponse{
-		Events:  events,
-		HasMore: len(events) >= limit,
-	}
-
 	return c.JSON(response)
 }
 
-// buildTimelineEvents creates timeline events from various data sources
-func (s *Server) buildTimelineEvents(start, end *time.Time, limit int) ([]TimelineEvent, error) {
-	var events []TimelineEvent
+// buildTimelineEvents creates timeline events from various data sources,
+// newest first. Each source is read with its own keyset cursor and the
+// results merged, so busy days page through completely. It returns the
+// cursor for the next page, or an empty string on the last page.
+func (s *Server) buildTimelineEvents(start, end *time.Time, cursor timelineCursor, limit int) ([]TimelineEvent, string, error) {
+	if s.publicMode() {
+		clamped := s.publicEnd(*end)
+		end = &clamped
+	}
 
-	// Get call records for the time period
-	// Use a higher multiplier to ensure we get enough calls for the full day
-	callLimit := limit * 3
-	if callLimit < 500 {
-		callLimit = 500 // Ensure we get a good amount of data for a full day
+	// Fetch one more than a page from each source to know whether another page exists
+	calls, err := s.db.GetCallRecordsAfter(database.CallFilter{Start: start, End: end}, cursor[timelineCalls], limit+1)
+	if err != nil {
+		return nil, "", err
 	}
 
-	calls, err := s.db.GetCallRecords(start, end, "", callLimit, 0)
+	// Add system events such as startups, SDRTrunk restarts and alerts
+	systemEvents, err := s.db.GetSystemEvents(start, end, cursor[timelineSystem], limit+1)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	log.Printf("Retrieved %d calls for timeline between %s and %s", len(calls), start.Format("2006-01-02 15:04:05"), end.Format("2006-01-02 15:04:05"))
+	// Add the start and end of incidents, found by clustering related calls
+	incidents, err := s.incidentTimelineEvents(*start, *end, cursor[timelineIncidents], limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	events := make([]timelineEntry, 0, len(calls)+len(systemEvents)+len(incidents))
 
 	// Convert calls to timeline events using cached talkgroup processing
 	for _, call := range calls {
-		// Use cached talkgroup information for better performance
-		talkgroupInfo := s.getCachedTalkgroupInfo(call.TalkgroupID, call.TalkgroupGroup)
-
-		event := TimelineEvent{
-			ID:        fmt.Sprintf("call_%d", call.ID),
-			Type:      "call",
-			Timestamp: call.Timestamp,
-			Title:     talkgroupInfo.Title,
-			Icon:      talkgroupInfo.Icon,
-			Color:     talkgroupInfo.Color,
-			Data: map[string]interface{}{
-				"talkgroup":    call.TalkgroupAlias,
-				"frequency":    call.Frequency,
-				"duration":     call.Duration,
-				"call_id":      call.ID,
-				"service_type": string(talkgroupInfo.ServiceType),
-			},
-		}
-
-		// Create description based on transcription
-		if call.Transcription != "" {
-			if len(call.Transcription) > 100 {
-				event.Description = call.Transcription[:100] + "..."
-			} else {
-				event.Description = call.Transcription
-			}
-		} else {
-			event.Description = fmt.Sprintf("Duration: %ds on %s", call.Duration, call.Frequency)
-		}
-
-		events = append(events, event)
+		events = append(events, timelineEntry{
+			TimelineEvent: s.newCallTimelineEvent(call),
+			source:        timelineCalls,
+			position:      &database.CallCursor{Timestamp: call.Timestamp, ID: call.ID},
+		})
 	}
-
-	// Add system events (you can expand this based on your logging/event system)
-	systemInfo := s.monitor.GetSystemInfo()
-	var startupTime time.Time
-
-	// Get the actual startup time from monitor uptime
-	if uptime, ok := systemInfo["uptime"].(float64); ok {
-		startupTime = time.Now().Add(-time.Duration(uptime) * time.Second)
-	} else {
-		// Fallback to a reasonable time
-		startupTime = time.Now().Add(-30 * time.Minute)
-	}
-
-	// Only add system startup event if it falls within the requested time range
-	if (start == nil || startupTime.After(*start)) && (end == nil || startupTime.Before(*end)) {
-		events = append(events, TimelineEvent{
-			ID:          "system_start",
-			Type:        "system",
-			Timestamp:   startupTime,
-			Title:       "Meiko System Started",
-			Description: "SDR monitoring and transcription system came online",
-			Icon:        "power-off",
-			Color:       "#22c55e",
+	for _, event := range systemEvents {
+		events = append(events, timelineEntry{
+			TimelineEvent: newSystemTimelineEvent(event),
+			source:        timelineSystem,
+			position:      &database.CallCursor{Timestamp: event.Timestamp, ID: event.ID},
 		})
 	}
+	events = append(events, incidents...)
 
-	// Sort events by timestamp (newest first) using efficient built-in sort
-	sort.Slice(events, func(i, j int) bool {
+	// Each source is already newest first, so a stable sort keeps its own order
+	sort.SliceStable(events, func(i, j int) bool {
 		return events[i].Timestamp.After(events[j].Timestamp)
 	})
 
-	// Limit results
-	if len(events) > limit {
+	hasMore := len(events) > limit
+	if hasMore {
 		events = events[:limit]
 	}
 
-	return events, nil
+	// Sources move on to the last event they put on this page
+	next := make(timelineCursor)
+	for source, position := range cursor {
+		next[source] = position
+	}
+	timeline := make([]TimelineEvent, len(events))
+	for i, event := range events {
+		timeline[i] = event.TimelineEvent
+		next[event.source] = event.position
+	}
+
+	if !hasMore {
+		return timeline, "", nil
+	}
+	return timeline, encodeTimelineCursor(next), nil
+}
+
+// newCallTimelineEvent converts a call into a timeline event
+func (s *Server) newCallTimelineEvent(call *database.CallRecord) TimelineEvent {
+	// Use cached talkgroup information for better performance
+	talkgroupInfo := s.getCachedTalkgroupInfo(call.TalkgroupID, call.TalkgroupGroup)
+
+	event := TimelineEvent{
+		ID:        fmt.Sprintf("call_%d", call.ID),
+		Type:      "call",
+		Timestamp: call.Timestamp,
+		Title:     talkgroupInfo.Title,
+		Icon:      talkgroupInfo.Icon,
+		Color:     talkgroupInfo.Color,
+		Data: map[string]interface{}{
+			"talkgroup":    call.TalkgroupAlias,
+			"frequency":    call.Frequency,
+			"duration":     call.Duration,
+			"call_id":      call.ID,
+			"service_type": string(talkgroupInfo.ServiceType),
+		},
+	}
+
+	// Create description based on transcription
+	if transcription := s.redact(call.TalkgroupID, call.Transcription); transcription != "" {
+		if len(transcription) > 100 {
+			event.Description = transcription[:100] + "..."
+		} else {
+			event.Description = transcription
+		}
+	} else {
+		event.Description = fmt.Sprintf("Duration: %ds on %s", call.Duration, frequency.Display(call.Frequency))
+	}
+
+	return event
+}
+
+// timelineEntry is a timeline event with the source it came from and its
+// position in that source, for building the next page's cursor
+type timelineEntry struct {
+	TimelineEvent
+	source   string
+	position *database.CallCursor
+}
+
+// systemEventStyles gives the title and icon for each system event type, and
+// a color for types not colored by their level
+var systemEventStyles = map[string]struct{ title, icon, color string }{
+	database.EventStartup:  {"Meiko System Started", "power-off", ""},
+	database.EventShutdown: {"Meiko System Stopped", "power-off", ""},
+	database.EventSDRTrunk: {"SDRTrunk", "broadcast-tower", ""},
+	database.EventAlert:    {"System Alert", "exclamation-triangle", ""},
+	database.EventDevice:   {"SDR Receiver", "plug", ""},
+	database.EventStorage:  {"Disk Space", "hdd", ""},
+	database.EventKeyword:  {"Keyword Alert", "bell", "#a855f7"},
+	database.EventAnomaly:  {"Unusual Activity", "chart-line", ""},
+}
+
+// newSystemTimelineEvent converts a stored system event into a timeline event
+// colored by its level
+func newSystemTimelineEvent(event *database.SystemEvent) TimelineEvent {
+	style, ok := systemEventStyles[event.Type]
+	if !ok {
+		style.title, style.icon = "System Event", "info-circle"
+	}
+	title := style.title
+	if event.Source != "" {
+		title += ": " + event.Source
+	}
+
+	color := "#22c55e"
+	switch {
+	case style.color != "":
+		color = style.color
+	case event.Level == database.EventWarning:
+		color = "#f59e0b"
+	case event.Level == database.EventError:
+		color = "#ef4444"
+	}
+
+	timelineEvent := TimelineEvent{
+		ID:          fmt.Sprintf("system_%d", event.ID),
+		Type:        "system",
+		Timestamp:   event.Timestamp,
+		Title:       title,
+		Description: event.Message,
+		Icon:        style.icon,
+		Color:       color,
+		Data: map[string]interface{}{
+			"event_type": event.Type,
+			"level":      event.Level,
+			"source":     event.Source,
+		},
+	}
+	if event.CallID > 0 {
+		timelineEvent.Data["call_id"] = event.CallID
+	}
+	return timelineEvent
 }
 
-// getCalls returns call records with optional filtering
+// getCalls returns call records with optional filtering. Clients can page with
+// offset/limit or, for large result sets, with the opaque next_cursor.
+// sort=priority orders the highest priority calls first, and min_priority or
+// major=true keep only important calls. status=failed lists calls whose
+// transcription was given up on, and incident_type filters on enrichment.
+// has_transcription, min_duration and max_duration (seconds), keyword,
+// frequency and service_type narrow the list further; total always counts
+// every call matching the filters.
 func (s *Server) getCalls(c *fiber.Ctx) error {
 	// Parse query parameters
-	limit := c.QueryInt("limit", 50)
+	limit := pageSize(c)
 	offset := c.QueryInt("offset", 0)
+	cursorParam := c.Query("cursor", "")
 	timeRange := c.Query("range", "")
-	talkgroupID := c.Query("talkgroup", "")
+
+	filter, err := s.callFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	sortBy := c.Query("sort", "time")
+	if sortBy != "time" && sortBy != "priority" {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid sort. Use time or priority"})
+	}
+	if sortBy == "priority" && cursorParam != "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Cursors are only supported when sorting by time"})
+	}
 
 	// Build time filter
-	var start, end *time.Time
 	if timeRange != "" {
 		tr, err := s.parseTimeRange(timeRange)
 		if err == nil {
-			start = &tr.Start
-			end = &tr.End
+			filter.Start = &tr.Start
+			filter.End = &tr.End
 		}
+	} else if s.publicMode() {
+		now := s.publicNow()
+		filter.End = &now
 	}
 
-	// Get calls from database
-	calls, err := s.db.GetCallRecords(start, end, talkgroupID, limit, offset)
+	total, err := s.db.CountCallRecords(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to count call records",
+			"details": err.Error(),
+		})
+	}
+
+	// Fetch one extra record to know whether another page exists
+	var calls []*database.CallRecord
+	if cursorParam != "" {
+		cursor, err := decodeCursor(cursorParam)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error":   "Invalid cursor",
+				"details": err.Error(),
+			})
+		}
+		offset = 0
+		calls, err = s.db.GetCallRecordsAfter(filter, cursor, limit+1)
+	} else if sortBy == "priority" {
+		calls, err = s.db.GetCallRecordsByPriority(filter, limit+1, offset)
+	} else {
+		calls, err = s.db.GetCallRecords(filter, limit+1, offset)
+	}
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "Failed to fetch call records",
@@ -513,35 +901,148 @@ func (s *Server) getCalls(c *fiber.Ctx) error {
 		})
 	}
 
+	hasMore := len(calls) > limit
+	if hasMore {
+		calls = calls[:limit]
+	}
+
 	// Convert to API format
 	apiCalls := make([]CallRecord, len(calls))
 	for i, call := range calls {
-		apiCalls[i] = CallRecord{
-			ID:              call.ID,
-			Filename:        call.Filename,
-			Filepath:        call.Filepath,
-			Timestamp:       call.Timestamp,
-			Duration:        call.Duration,
-			Frequency:       call.Frequency,
-			TalkgroupID:     call.TalkgroupID,
-			TalkgroupAlias:  call.TalkgroupAlias,
-			TalkgroupGroup:  call.TalkgroupGroup,
-			TranscriptionID: call.TranscriptionID,
-			Transcription:   call.Transcription,
-			CreatedAt:       call.CreatedAt,
+		apiCalls[i] = s.apiCall(call)
+	}
+
+	pagination := fiber.Map{
+		"limit":    limit,
+		"offset":   offset,
+		"total":    total,
+		"has_more": hasMore,
+	}
+
+	links := map[string]string{
+		"first": pageURL(c, map[string]string{"cursor": "", "offset": ""}),
+	}
+	if hasMore && sortBy == "priority" {
+		links["next"] = pageURL(c, map[string]string{"offset": strconv.Itoa(offset + limit)})
+	} else if hasMore {
+		nextCursor := encodeCursor(calls[len(calls)-1])
+		pagination["next_cursor"] = nextCursor
+		links["next"] = pageURL(c, map[string]string{"cursor": nextCursor, "offset": ""})
+	}
+	if cursorParam == "" && offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
 		}
+		links["prev"] = pageURL(c, map[string]string{"offset": strconv.Itoa(prevOffset)})
 	}
+	setLinkHeader(c, links)
 
 	return c.JSON(fiber.Map{
-		"calls": apiCalls,
-		"pagination": fiber.Map{
-			"limit":  limit,
-			"offset": offset,
-			"total":  len(apiCalls),
-		},
+		"calls":      apiCalls,
+		"pagination": pagination,
 	})
 }
 
+// callFilter reads the call filters shared by call listings from the query,
+// apart from the time range
+func (s *Server) callFilter(c *fiber.Ctx) (database.CallFilter, error) {
+	filter := database.CallFilter{
+		TalkgroupID:  c.Query("talkgroup", ""),
+		SystemID:     c.Query("system", ""),
+		MinPriority:  c.QueryInt("min_priority", 0),
+		Status:       c.Query("status", ""),
+		IncidentType: strings.ToLower(c.Query("incident_type", "")),
+		MinDuration:  c.QueryInt("min_duration", 0),
+		MaxDuration:  c.QueryInt("max_duration", 0),
+		Keyword:      strings.TrimSpace(c.Query("keyword", "")),
+	}
+	if c.QueryBool("major", false) {
+		filter.MinPriority = max(filter.MinPriority, s.config.Priority.MajorIncident)
+	}
+
+	switch filter.Status {
+	case "", database.CallStatusProcessed, database.CallStatusPending, database.CallStatusFailed:
+	default:
+		return filter, fmt.Errorf("Invalid status. Use processed, pending or failed")
+	}
+
+	if raw := c.Query("has_transcription", ""); raw != "" {
+		hasTranscription, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid has_transcription. Use true or false")
+		}
+		filter.HasTranscription = &hasTranscription
+	}
+
+	if filter.MinDuration < 0 || filter.MaxDuration < 0 {
+		return filter, fmt.Errorf("Durations cannot be negative")
+	}
+	if filter.MaxDuration > 0 && filter.MinDuration > filter.MaxDuration {
+		return filter, fmt.Errorf("min_duration cannot be above max_duration")
+	}
+
+	// Matching redacted words would reveal what was redacted
+	if filter.Keyword != "" && s.redactor != nil {
+		return filter, fmt.Errorf("Keyword search is not available in public mode")
+	}
+
+	// One frequency, or a range such as a band plan's channels
+	if c.Query("frequency", "") != "" {
+		if c.Query("frequency_min", "") != "" || c.Query("frequency_max", "") != "" {
+			return filter, fmt.Errorf("Use either frequency or frequency_min and frequency_max")
+		}
+		hz, err := queryFrequency(c, "frequency")
+		if err != nil {
+			return filter, err
+		}
+		filter.MinFrequency, filter.MaxFrequency = hz, hz
+	} else {
+		var err error
+		if filter.MinFrequency, err = queryFrequency(c, "frequency_min"); err != nil {
+			return filter, err
+		}
+		if filter.MaxFrequency, err = queryFrequency(c, "frequency_max"); err != nil {
+			return filter, err
+		}
+		if filter.MaxFrequency > 0 && filter.MinFrequency > filter.MaxFrequency {
+			return filter, fmt.Errorf("frequency_min cannot be above frequency_max")
+		}
+	}
+
+	talkgroupIDs, err := s.serviceTalkgroups(c.Query("service_type", ""))
+	if err != nil {
+		return filter, err
+	}
+	filter.TalkgroupIDs = talkgroupIDs
+
+	return filter, nil
+}
+
+// serviceTalkgroups returns the talkgroups of a service type, or nil when no
+// service type is given. Service types are assigned to talkgroups, not stored
+// with calls.
+func (s *Server) serviceTalkgroups(serviceType string) ([]string, error) {
+	serviceType = strings.ToUpper(serviceType)
+	if serviceType == "" {
+		return nil, nil
+	}
+	if s.talkgroups == nil {
+		return nil, fmt.Errorf("Service type filtering needs talkgroup data")
+	}
+	if _, ok := s.talkgroups.GetServiceTypes()[talkgroups.ServiceType(serviceType)]; !ok {
+		return nil, fmt.Errorf("Unknown service_type %s", serviceType)
+	}
+
+	talkgroupIDs := []string{}
+	for id, info := range s.talkgroups.GetAllTalkgroups() {
+		if string(info.ServiceType) == serviceType {
+			talkgroupIDs = append(talkgroupIDs, id)
+		}
+	}
+	return talkgroupIDs, nil
+}
+
 // getCall returns a specific call record
 func (s *Server) getCall(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -552,32 +1053,29 @@ func (s *Server) getCall(c *fiber.Ctx) error {
 	}
 
 	call, err := s.db.GetCallRecord(id)
-	if err != nil {
+	if err != nil || s.hiddenCall(call) {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "Call record not found",
 		})
 	}
 
-	apiCall := CallRecord{
-		ID:              call.ID,
-		Filename:        call.Filename,
-		Filepath:        call.Filepath,
-		Timestamp:       call.Timestamp,
-		Duration:        call.Duration,
-		Frequency:       call.Frequency,
-		TalkgroupID:     call.TalkgroupID,
-		TalkgroupAlias:  call.TalkgroupAlias,
-		TalkgroupGroup:  call.TalkgroupGroup,
-		TranscriptionID: call.TranscriptionID,
-		Transcription:   call.Transcription,
-		CreatedAt:       call.CreatedAt,
+	apiCall := s.apiCall(call)
+
+	// Include the other recordings of the same transmission
+	duplicates, err := s.db.GetDuplicateCalls(call.ID)
+	if err != nil {
+		s.logger.Warn("Failed to get duplicate calls", "call_id", call.ID, "error", err)
+	}
+	for _, duplicate := range duplicates {
+		apiCall.Duplicates = append(apiCall.Duplicates, s.apiCall(duplicate))
 	}
 
 	return c.JSON(apiCall)
 }
 
-// getCallAudio serves the audio file for a specific call
-func (s *Server) getCallAudio(c *fiber.Ctx) error {
+// getCallOriginal returns a call with its transcription as stored, before
+// redaction
+func (s *Server) getCallOriginal(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{
@@ -592,15 +1090,65 @@ func (s *Server) getCallAudio(c *fiber.Ctx) error {
 		})
 	}
 
+	return c.JSON(newCallRecord(call))
+}
+
+// retryFailedCalls queues every call whose transcription was given up on to
+// be transcribed again
+func (s *Server) retryFailedCalls(c *fiber.Ctx) error {
+	count, err := s.db.RetryFailedCalls()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to queue failed calls",
+			"details": err.Error(),
+		})
+	}
+
+	s.logger.Info("Queued failed calls for transcription", "count", count)
+	return c.JSON(fiber.Map{"queued": count})
+}
+
+// SetAudioArchive sets the archiver used to link to recordings in object storage
+func (s *Server) SetAudioArchive(archiver *storage.AudioArchiver) {
+	s.audioArchive = archiver
+}
+
+// getCallAudio serves the audio file for a specific call, redirecting to a
+// presigned object storage URL once the local copy has been removed
+func (s *Server) getCallAudio(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid call ID",
+		})
+	}
+
+	call, err := s.db.GetCallRecord(id)
+	if err != nil || s.hiddenCall(call) {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Call record not found",
+		})
+	}
+
 	// Check if audio file exists
 	if _, err := os.Stat(call.Filepath); os.IsNotExist(err) {
+		if call.StorageKey != "" && s.audioArchive != nil {
+			url, err := s.audioArchive.URL(call.StorageKey)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{
+					"error":   "Failed to link archived audio",
+					"details": err.Error(),
+				})
+			}
+			return c.Redirect(url, fiber.StatusFound)
+		}
 		return c.Status(404).JSON(fiber.Map{
 			"error": "Audio file not found",
 		})
 	}
 
 	// Set proper headers for audio streaming
-	c.Set("Content-Type", "audio/mpeg")
+	c.Set("Content-Type", audioContentType(call.Filename))
 	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", call.Filename))
 	c.Set("Accept-Ranges", "bytes")
 
@@ -608,6 +1156,18 @@ func (s *Server) getCallAudio(c *fiber.Ctx) error {
 	return c.SendFile(call.Filepath)
 }
 
+// audioContentType returns the MIME type for a recording's file extension
+func audioContentType(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".wav":
+		return "audio/wav"
+	case ".opus", ".ogg":
+		return "audio/ogg"
+	default:
+		return "audio/mpeg"
+	}
+}
+
 // getCallsSummary returns aggregated call statistics
 func (s *Server) getCallsSummary(c *fiber.Ctx) error {
 	rangeParam := c.Params("range")
@@ -620,7 +1180,8 @@ func (s *Server) getCallsSummary(c *fiber.Ctx) error {
 		})
 	}
 
-	stats, err := s.db.GetCallStats(&tr.Start, &tr.End)
+	systemID := c.Query("system", "")
+	stats, err := s.db.GetCallStats(&tr.Start, &tr.End, systemID)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "Failed to fetch call statistics",
@@ -629,10 +1190,11 @@ func (s *Server) getCallsSummary(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"range": rangeParam,
-		"start": tr.Start,
-		"end":   tr.End,
-		"stats": stats,
+		"range":  rangeParam,
+		"system": systemID,
+		"start":  tr.Start,
+		"end":    tr.End,
+		"stats":  stats,
 	})
 }
 
@@ -641,12 +1203,13 @@ func (s *Server) getStats(c *fiber.Ctx) error {
 	stats := s.monitor.GetCurrentStats()
 	systemInfo := s.monitor.GetSystemInfo()
 
-	// Get call statistics
-	totalCalls, _ := s.db.GetTotalCallCount()
-	lastCall, _ := s.db.GetLastCallTime()
-	callsToday, _ := s.db.GetCallsToday()
-	frequencies, _ := s.db.GetFrequencyStats()
-	talkgroups, _ := s.db.GetTalkgroupStats()
+	// Get call statistics, optionally for one system
+	systemID := c.Query("system", "")
+	totalCalls, _ := s.db.GetTotalCallCount(systemID)
+	lastCall, _ := s.db.GetLastCallTime(systemID)
+	callsToday, _ := s.db.GetCallsToday(systemID)
+	frequencies, _ := s.db.GetFrequencyStats(systemID)
+	talkgroups, _ := s.db.GetTalkgroupStats(systemID)
 
 	// Convert uptime from seconds to duration
 	var uptime time.Duration
@@ -659,6 +1222,7 @@ func (s *Server) getStats(c *fiber.Ctx) error {
 		Memory:      stats.Memory,
 		Disk:        stats.Disk,
 		Temperature: stats.Temperature,
+		Sensors:     stats.Sensors,
 		Uptime:      uptime,
 		TotalCalls:  totalCalls,
 		LastCall:    lastCall,
@@ -673,23 +1237,33 @@ func (s *Server) getStats(c *fiber.Ctx) error {
 // getLifetimeStats returns lifetime system statistics
 // getAutoSummary returns the latest auto-generated summary
 func (s *Server) getAutoSummary(c *fiber.Ctx) error {
-	s.summaryMu.RLock()
-	defer s.summaryMu.RUnlock()
+	summary, err := s.db.GetLatestSummary(autoSummaryScope)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch auto-generated summary",
+			"details": err.Error(),
+		})
+	}
 
-	if s.lastAutoSummary == nil {
+	if summary == nil {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "No auto-generated summary available yet",
 		})
 	}
 
 	// Check if summary is still fresh (within last 2 hours)
-	if time.Since(s.lastAutoSummary.GeneratedAt) > 2*time.Hour {
+	if time.Since(summary.GeneratedAt) > 2*time.Hour {
 		return c.Status(404).JSON(fiber.Map{
 			"error": "Auto-generated summary is stale, generating new one...",
 		})
 	}
 
-	return c.JSON(s.lastAutoSummary)
+	return c.JSON(AutoSummary{
+		Summary:     s.redact("", summary.Summary),
+		GeneratedAt: summary.GeneratedAt,
+		TimeRange:   "today",
+		CallCount:   summary.CallCount,
+	})
 }
 
 func (s *Server) getLifetimeStats(c *fiber.Ctx) error {
@@ -704,9 +1278,27 @@ func (s *Server) getLifetimeStats(c *fiber.Ctx) error {
 	return c.JSON(stats)
 }
 
-// getSystemInfo returns system information
+// getSystemInfo returns system information, including the state of the
+// SDRTrunk processes when this instance captures audio and the disk forecast
 func (s *Server) getSystemInfo(c *fiber.Ctx) error {
-	info := s.monitor.GetSystemInfo()
+	info := map[string]interface{}{}
+	if s.monitor != nil {
+		info = s.monitor.GetSystemInfo()
+	}
+	if s.sdrtrunk != nil {
+		running, total := s.sdrtrunk.RunningCount()
+		info["sdrtrunk"] = fiber.Map{
+			"running":   running,
+			"total":     total,
+			"processes": s.sdrtrunk.Statuses(),
+		}
+	}
+	if s.storage != nil {
+		info["storage"] = s.storage.Forecast()
+	}
+	if s.reconciler != nil {
+		info["reconcile"] = s.reconciler.Report()
+	}
 	return c.JSON(info)
 }
 
@@ -737,17 +1329,18 @@ func (s *Server) getLogs(c *fiber.Ctx) error {
 	})
 }
 
-// generateSummary generates an AI summary using Gemini
+// generateSummary generates an AI summary with the configured LLM
 func (s *Server) generateSummary(c *fiber.Ctx) error {
-	if s.gemini == nil {
+	if s.llm == nil {
 		return c.Status(503).JSON(fiber.Map{
-			"error": "Gemini AI is not configured",
+			"error": "AI summaries are not configured",
 		})
 	}
 
 	var req struct {
 		TimeRange string `json:"time_range"`
 		Prompt    string `json:"prompt,omitempty"`
+		summaryCacheOptions
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -764,121 +1357,209 @@ func (s *Server) generateSummary(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get call records for the time range
-	calls, err := s.db.GetCallRecords(&tr.Start, &tr.End, "", 100, 0)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to fetch call records",
+	if !s.allowsCacheOptions(c, req.summaryCacheOptions) {
+		return c.Status(403).JSON(fiber.Map{
+			"error": "no_cache and ttl_seconds require the admin scope",
 		})
 	}
 
-	// Build prompt for Gemini
-	prompt := s.buildSummaryPrompt(calls, req.Prompt)
-
-	// Generate summary using Gemini
-	ctx := context.Background()
-	model := s.gemini.GenerativeModel(s.config.Web.Gemini.Model)
-
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
+	summary, cached, err := s.summarizeRange("range", tr.Start, tr.End, req.Prompt, s.buildSummaryPrompt, req.summaryCacheOptions)
+	if err == errSummaryUnavailable {
+		return c.Status(429).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	} else if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error":   "Failed to generate summary",
 			"details": err.Error(),
 		})
 	}
 
-	var summary string
-	if len(resp.Candidates) > 0 && len(resp.Candidates[0].Content.Parts) > 0 {
-		summary = fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-	}
-
 	return c.JSON(fiber.Map{
-		"summary":      summary,
+		"summary":      s.redact("", summary.Summary),
 		"time_range":   req.TimeRange,
-		"call_count":   len(calls),
-		"generated_at": time.Now(),
+		"call_count":   summary.CallCount,
+		"generated_at": summary.GeneratedAt,
+		"expires_at":   summary.ExpiresAt,
+		"cache_key":    summary.CacheKey,
+		"cached":       cached,
 	})
 }
 
-// handleWebSocket manages WebSocket connections
+// handleWebSocket manages a WebSocket connection. This goroutine is the only
+// writer to the connection; it sends the client's queued broadcasts, replies
+// to the client's messages and pings.
 func (s *Server) handleWebSocket(c *websocket.Conn) {
+	client, clientCount := s.hub.register()
+	s.logger.Info("WebSocket client connected", "total_clients", clientCount)
+
+	done := make(chan struct{})
 	defer func() {
-		delete(s.clients, c)
+		close(done)
+		clientCount := s.hub.unregister(client)
 		c.Close()
+		s.logger.Info("WebSocket client disconnected", "total_clients", clientCount)
 	}()
 
-	s.mu.Lock()
-	s.clients[c] = true
-	clientCount := len(s.clients)
-	s.mu.Unlock()
-
-	s.logger.Info("WebSocket client connected", "total_clients", clientCount)
-
 	// Send initial status
-	status := fiber.Map{
-		"type":      "status",
-		"connected": true,
-		"timestamp": time.Now(),
-	}
-	if err := c.WriteJSON(status); err != nil {
+	epoch, latest := s.replay.position()
+	status, _ := encodeMessage(MessageStatus, nil, fiber.Map{"connected": true, "epoch": epoch, "latest": latest})
+	if err := writeWebSocket(c, websocket.TextMessage, status); err != nil {
 		s.logger.Error("Failed to send initial status", "error", err)
+		return
 	}
 
-	// Read messages from client (though we don't expect many)
+	// Reading also detects when the client goes away
+	requests := make(chan []byte)
+	closed := make(chan struct{})
 	go func() {
-		defer func() {
-			s.mu.Lock()
-			delete(s.clients, c)
-			clientCount := len(s.clients)
-			s.mu.Unlock()
-			s.logger.Info("WebSocket client disconnected", "total_clients", clientCount)
-			c.Close()
-		}()
-
+		defer close(closed)
 		for {
-			_, _, err := c.ReadMessage()
+			_, message, err := c.ReadMessage()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					s.logger.Warn("WebSocket read error", "error", err)
 				}
-				break
+				return
+			}
+			select {
+			case requests <- message:
+			case <-done:
+				return
 			}
 		}
 	}()
 
-	// Listen for broadcast messages
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+
+	var lastSent uint64 // Sequence number replayed up to, so queued copies are skipped
+	heartbeats := false // Set once the client says hello
 	for {
 		select {
-		case message := <-s.broadcast:
-			s.logger.Debug("Broadcasting message to WebSocket clients", "message_size", len(message), "total_clients", clientCount)
-
-			s.mu.Lock()
-			activeClients := len(s.clients)
-			sentCount := 0
-			for client := range s.clients {
-				if err := client.WriteMessage(websocket.TextMessage, message); err != nil {
-					s.logger.Warn("Failed to send message to WebSocket client", "error", err)
-					delete(s.clients, client)
-					client.Close()
-				} else {
-					sentCount++
-				}
+		case message, ok := <-client.send:
+			if !ok {
+				// Evicted by the hub for falling behind
+				writeWebSocket(c, websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"))
+				return
 			}
-			s.mu.Unlock()
-
-			s.logger.Debug("WebSocket broadcast completed", "sent_to", sentCount, "total_clients", activeClients)
-
-		case <-time.After(30 * time.Second):
+			if message.seq != 0 && message.seq <= lastSent {
+				continue
+			}
+			if err := writeWebSocket(c, websocket.TextMessage, message.data); err != nil {
+				s.logger.Warn("Failed to send message to WebSocket client", "error", err)
+				return
+			}
+		case request := <-requests:
+			replayed, hello, err := s.handleClientMessage(c, request)
+			if err != nil {
+				s.logger.Warn("Failed to reply to WebSocket client", "error", err)
+				return
+			}
+			if replayed > lastSent {
+				lastSent = replayed
+			}
+			heartbeats = heartbeats || hello
+		case <-ping.C:
 			// Ping to keep connection alive
-			if err := c.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := writeWebSocket(c, websocket.PingMessage, nil); err != nil {
 				s.logger.Warn("Failed to send ping", "error", err)
 				return
 			}
+			if heartbeats {
+				if err := s.sendHeartbeat(c); err != nil {
+					s.logger.Warn("Failed to send heartbeat", "error", err)
+					return
+				}
+			}
+		case <-closed:
+			return
 		}
 	}
 }
 
-// handleBroadcast manages broadcasting to WebSocket clients
+// clientMessage is a message sent by a WebSocket client
+type clientMessage struct {
+	Type    MessageType `json:"type"`
+	Version int         `json:"version"`
+	Epoch   string      `json:"epoch"`
+	LastSeq *uint64     `json:"last_seq"`
+}
+
+// handleClientMessage replies to a message from a WebSocket client. For a
+// hello that resumes, the missed messages are sent too, and the sequence
+// number replayed up to is returned. It also reports whether the message was
+// a hello.
+func (s *Server) handleClientMessage(c *websocket.Conn, data []byte) (uint64, bool, error) {
+	var request clientMessage
+	if err := json.Unmarshal(data, &request); err != nil {
+		return 0, false, s.sendClientError(c, "Messages must be JSON objects")
+	}
+
+	switch request.Type {
+	case ClientHello:
+		if request.Version > WSSchemaVersion {
+			if err := s.sendClientError(c, fmt.Sprintf("Schema version %d is not supported; the server speaks version %d", request.Version, WSSchemaVersion)); err != nil {
+				return 0, true, err
+			}
+		}
+
+		epoch, latest := s.replay.position()
+		var missed []hubMessage
+		resumed := request.LastSeq == nil
+		if request.LastSeq != nil {
+			missed, latest, resumed = s.replay.since(request.Epoch, *request.LastSeq)
+		}
+
+		ack, _ := encodeMessage(MessageHelloAck, nil, fiber.Map{
+			"version": WSSchemaVersion,
+			"epoch":   epoch,
+			"latest":  latest,
+			"resumed": resumed,
+			"missed":  len(missed),
+		})
+		if err := writeWebSocket(c, websocket.TextMessage, ack); err != nil {
+			return 0, true, err
+		}
+		for _, message := range missed {
+			if err := writeWebSocket(c, websocket.TextMessage, message.data); err != nil {
+				return 0, true, err
+			}
+		}
+		if request.LastSeq == nil || !resumed {
+			return 0, true, nil
+		}
+		return latest, true, nil
+	case ClientPing:
+		return 0, false, s.sendHeartbeat(c)
+	default:
+		return 0, false, s.sendClientError(c, fmt.Sprintf("Unknown message type %q", request.Type))
+	}
+}
+
+// sendHeartbeat tells a WebSocket client the latest sequence number, so it
+// can tell whether it missed anything
+func (s *Server) sendHeartbeat(c *websocket.Conn) error {
+	epoch, latest := s.replay.position()
+	heartbeat, _ := encodeMessage(MessageHeartbeat, nil, fiber.Map{"epoch": epoch, "latest": latest})
+	return writeWebSocket(c, websocket.TextMessage, heartbeat)
+}
+
+// sendClientError tells a WebSocket client its message could not be handled
+func (s *Server) sendClientError(c *websocket.Conn, message string) error {
+	data, _ := encodeMessage(MessageError, nil, fiber.Map{"error": message})
+	return writeWebSocket(c, websocket.TextMessage, data)
+}
+
+// writeWebSocket writes a message, giving up on clients that stop reading
+func writeWebSocket(c *websocket.Conn, messageType int, data []byte) error {
+	c.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.WriteMessage(messageType, data)
+}
+
+// handleBroadcast is the single dispatcher that hands broadcasts and periodic
+// stats to the hub
 func (s *Server) handleBroadcast() {
 	ticker := time.NewTicker(time.Duration(s.config.Web.Realtime.UpdateInterval) * time.Millisecond)
 	defer ticker.Stop()
@@ -890,38 +1571,48 @@ func (s *Server) handleBroadcast() {
 				s.broadcastStats()
 			}
 		case message := <-s.broadcast:
-			s.sendToClients(message)
+			s.publish(s.replay.sequence(message))
 		}
 	}
 }
 
-// broadcastStats sends current statistics to all WebSocket clients
+// broadcastStats sends current statistics to all real-time clients. Stats
+// are outside the message sequence, since a missed update is soon replaced.
 func (s *Server) broadcastStats() {
 	stats := s.monitor.GetCurrentStats()
-	data, err := json.Marshal(fiber.Map{
-		"type":      "stats_update",
-		"data":      stats,
-		"timestamp": time.Now(),
-	})
+	data, err := encodeMessage(MessageStatsUpdate, stats, nil)
 	if err != nil {
 		return
 	}
 
-	s.sendToClients(data)
+	s.publish(hubMessage{data: data})
 }
 
-// sendToClients sends data to all connected WebSocket clients
-func (s *Server) sendToClients(data []byte) {
-	for client := range s.clients {
-		if err := client.WriteMessage(websocket.TextMessage, data); err != nil {
-			delete(s.clients, client)
-			client.Close()
-		}
+// publish queues a message for every WebSocket and event stream client
+func (s *Server) publish(message hubMessage) {
+	if evicted := s.hub.publish(message); evicted > 0 {
+		s.logger.Warn("Disconnected real-time clients that fell behind", "clients", evicted)
 	}
 }
 
-// BroadcastNewCall sends a new call notification to all clients
+// BroadcastNewCall sends a new call notification to all clients. In public
+// mode the call is sent once the delay has passed.
 func (s *Server) BroadcastNewCall(call *database.CallRecord) {
+	if s.publicMode() {
+		time.AfterFunc(time.Until(call.Timestamp.Add(time.Duration(s.config.Web.Public.Delay)*time.Minute)), func() {
+			select {
+			case <-s.closing:
+			default:
+				s.broadcastCall(call)
+			}
+		})
+		return
+	}
+	s.broadcastCall(call)
+}
+
+// broadcastCall sends a call to all clients
+func (s *Server) broadcastCall(call *database.CallRecord) {
 	// Invalidate timeline cache to ensure fresh data
 	s.InvalidateTimelineCache()
 
@@ -929,36 +1620,18 @@ func (s *Server) BroadcastNewCall(call *database.CallRecord) {
 		"call_id", call.ID,
 		"filename", call.Filename,
 		"talkgroup", call.TalkgroupAlias,
-		"connected_clients", len(s.clients))
-
-	apiCall := CallRecord{
-		ID:              call.ID,
-		Filename:        call.Filename,
-		Filepath:        call.Filepath,
-		Timestamp:       call.Timestamp,
-		Duration:        call.Duration,
-		Frequency:       call.Frequency,
-		TalkgroupID:     call.TalkgroupID,
-		TalkgroupAlias:  call.TalkgroupAlias,
-		TalkgroupGroup:  call.TalkgroupGroup,
-		TranscriptionID: call.TranscriptionID,
-		Transcription:   call.Transcription,
-		CreatedAt:       call.CreatedAt,
-	}
+		"connected_clients", s.hub.count())
+
+	apiCall := s.apiCall(call)
 
 	// Enhanced data for live scanner
-	enhancedData := fiber.Map{
-		"type":      "new_call",
-		"data":      apiCall,
-		"timestamp": time.Now(),
+	data, err := encodeMessage(MessageNewCall, apiCall, fiber.Map{
 		"live_scanner": fiber.Map{
 			"should_auto_play": true,
 			"waveform_data":    generateSampleWaveformData(call.Duration),
-			"frequency_info":   s.getFrequencyInfo(call.Frequency),
+			"frequency_info":   s.getFrequencyInfo(call.Frequency, call.SystemID),
 		},
-	}
-
-	data, err := json.Marshal(enhancedData)
+	})
 	if err != nil {
 		s.logger.Error("Failed to marshal new call data for WebSocket", "error", err)
 		return
@@ -976,12 +1649,7 @@ func (s *Server) BroadcastNewCall(call *database.CallRecord) {
 
 // BroadcastLiveScannerEvent sends live scanner specific events
 func (s *Server) BroadcastLiveScannerEvent(eventType string, eventData interface{}) {
-	data, err := json.Marshal(fiber.Map{
-		"type":      "live_scanner_event",
-		"event":     eventType,
-		"data":      eventData,
-		"timestamp": time.Now(),
-	})
+	data, err := encodeMessage(MessageScannerEvent, eventData, fiber.Map{"event": eventType})
 	if err != nil {
 		return
 	}
@@ -993,6 +1661,20 @@ func (s *Server) BroadcastLiveScannerEvent(eventType string, eventData interface
 	}
 }
 
+// BroadcastHealth sends a monitoring threshold breach or recovery to all clients
+func (s *Server) BroadcastHealth(alert monitoring.Alert) {
+	data, err := encodeMessage(MessageHealth, alert, nil)
+	if err != nil {
+		return
+	}
+
+	select {
+	case s.broadcast <- data:
+	default:
+		s.logger.Warn("Broadcast channel full, skipping health message", "metric", alert.Metric)
+	}
+}
+
 // generateSampleWaveformData creates sample waveform data for visualization
 func generateSampleWaveformData(duration int) []float64 {
 	// Generate realistic-looking waveform data
@@ -1016,20 +1698,9 @@ func generateSampleWaveformData(duration int) []float64 {
 	return waveform
 }
 
-// getFrequencyInfo returns information about a frequency
-func (s *Server) getFrequencyInfo(frequency string) fiber.Map {
-	// Basic frequency info - could be enhanced with frequency database
-	return fiber.Map{
-		"frequency":       frequency,
-		"description":     "Emergency Services",
-		"is_encrypted":    false,
-		"signal_strength": 85 + int(rand.Float64()*15), // Simulated signal strength
-	}
-}
-
 // parseTimeRange parses a time range string into start and end times
 func (s *Server) parseTimeRange(rangeStr string) (TimeRange, error) {
-	now := time.Now()
+	now := s.publicNow()
 
 	switch rangeStr {
 	case "30min", "30m":
@@ -1063,7 +1734,7 @@ func (s *Server) parseTimeRange(rangeStr string) (TimeRange, error) {
 	}
 }
 
-// buildSummaryPrompt builds a prompt for Gemini based on call data
+// buildSummaryPrompt builds a prompt for the LLM based on call data
 func (s *Server) buildSummaryPrompt(calls []*database.CallRecord, customPrompt string) string {
 	// Use the enhanced timeline summary prompt for better results
 	return s.buildTimelineSummaryPrompt(calls, customPrompt)
@@ -1073,6 +1744,9 @@ func (s *Server) buildSummaryPrompt(calls []*database.CallRecord, customPrompt s
 func (s *Server) Start() error {
 	addr := fmt.Sprintf("%s:%d", s.config.Web.Host, s.config.Web.Port)
 
+	if s.config.Web.TLS.ACME.Enabled {
+		return s.listenACME(addr)
+	}
 	if s.config.Web.TLS.Enabled {
 		return s.app.ListenTLS(addr, s.config.Web.TLS.CertFile, s.config.Web.TLS.KeyFile)
 	}
@@ -1144,7 +1818,7 @@ func (s *Server) generateMissingHourSummaries() {
 		hourStart := time.Date(targetTime.Year(), targetTime.Month(), targetTime.Day(), hour, 0, 0, 0, targetTime.Location())
 		hourEnd := hourStart.Add(time.Hour)
 
-		calls, err := s.db.GetCallRecords(&hourStart, &hourEnd, "", 50, 0)
+		calls, err := s.db.GetCallRecords(database.CallFilter{Start: &hourStart, End: &hourEnd}, 50, 0)
 		if err != nil {
 			s.logger.Error("Failed to get calls for hour summary generation", "error", err, "date", dateStr, "hour", hour)
 			continue
@@ -1169,10 +1843,10 @@ func (s *Server) generateMissingHourSummaries() {
 // getLiveStream provides real-time audio streaming status
 func (s *Server) getLiveStream(c *fiber.Ctx) error {
 	// Get recent calls for live streaming
-	now := time.Now()
+	now := s.publicNow()
 	since := now.Add(-5 * time.Minute) // Last 5 minutes
 
-	calls, err := s.db.GetCallRecords(&since, &now, "", 10, 0)
+	calls, err := s.db.GetCallRecords(database.CallFilter{Start: &since, End: &now}, 10, 0)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "Failed to fetch recent calls",
@@ -1182,27 +1856,24 @@ func (s *Server) getLiveStream(c *fiber.Ctx) error {
 	// Convert to API format
 	recentCalls := make([]CallRecord, len(calls))
 	for i, call := range calls {
-		recentCalls[i] = CallRecord{
-			ID:              call.ID,
-			Filename:        call.Filename,
-			Filepath:        call.Filepath,
-			Timestamp:       call.Timestamp,
-			Duration:        call.Duration,
-			Frequency:       call.Frequency,
-			TalkgroupID:     call.TalkgroupID,
-			TalkgroupAlias:  call.TalkgroupAlias,
-			TalkgroupGroup:  call.TalkgroupGroup,
-			TranscriptionID: call.TranscriptionID,
-			Transcription:   call.Transcription,
-			CreatedAt:       call.CreatedAt,
-		}
+		recentCalls[i] = s.apiCall(call)
+	}
+
+	// Frequencies heard in the last hour in Hz; /live/status has their labels
+	frequencies := []string{}
+	active, err := s.db.GetActiveFrequencies(now.Add(-time.Hour), "")
+	if err != nil {
+		s.logger.Warn("Failed to get active frequencies", "error", err)
+	}
+	for _, activity := range active {
+		frequencies = append(frequencies, strconv.FormatInt(activity.Frequency, 10))
 	}
 
 	return c.JSON(fiber.Map{
 		"status":             "active",
 		"recent_calls":       recentCalls,
 		"timestamp":          now,
-		"active_frequencies": s.getActiveFrequencies(),
+		"active_frequencies": frequencies,
 	})
 }
 
@@ -1211,68 +1882,49 @@ func (s *Server) getLiveStatus(c *fiber.Ctx) error {
 	stats := s.monitor.GetCurrentStats()
 
 	// Get latest call
-	now := time.Now()
+	now := s.publicNow()
 	since := now.Add(-1 * time.Hour)
 
-	calls, err := s.db.GetCallRecords(&since, &now, "", 1, 0)
+	calls, err := s.db.GetCallRecords(database.CallFilter{Start: &since, End: &now}, 1, 0)
 	var lastCall *CallRecord
 	if err == nil && len(calls) > 0 {
-		call := calls[0]
-		lastCall = &CallRecord{
-			ID:              call.ID,
-			Filename:        call.Filename,
-			Filepath:        call.Filepath,
-			Timestamp:       call.Timestamp,
-			Duration:        call.Duration,
-			Frequency:       call.Frequency,
-			TalkgroupID:     call.TalkgroupID,
-			TalkgroupAlias:  call.TalkgroupAlias,
-			TalkgroupGroup:  call.TalkgroupGroup,
-			TranscriptionID: call.TranscriptionID,
-			Transcription:   call.Transcription,
-			CreatedAt:       call.CreatedAt,
-		}
+		call := s.apiCall(calls[0])
+		lastCall = &call
 	}
 
 	return c.JSON(fiber.Map{
-		"is_active":         true,
-		"connected_clients": len(s.clients),
-		"system_stats":      stats,
-		"last_call":         lastCall,
-		"timestamp":         now,
+		"is_active":          true,
+		"connected_clients":  s.hub.count(),
+		"system_stats":       stats,
+		"last_call":          lastCall,
+		"active_frequencies": s.getActiveFrequencies(c.Query("system", "")),
+		"timestamp":          now,
 	})
 }
 
-// getActiveFrequencies returns currently active frequencies
-func (s *Server) getActiveFrequencies() []string {
-	// Get frequencies from recent calls (last hour)
-	now := time.Now()
-	since := now.Add(-1 * time.Hour)
-
-	calls, err := s.db.GetCallRecords(&since, &now, "", 100, 0)
+// getActiveFrequencies returns the frequencies heard in the last hour, most
+// recent first, with their labels
+func (s *Server) getActiveFrequencies(systemID string) []fiber.Map {
+	active, err := s.db.GetActiveFrequencies(s.publicNow().Add(-time.Hour), systemID)
 	if err != nil {
-		return []string{}
+		s.logger.Warn("Failed to get active frequencies", "error", err)
+		return []fiber.Map{}
 	}
 
-	frequencyMap := make(map[string]bool)
-	for _, call := range calls {
-		if call.Frequency != "" {
-			frequencyMap[call.Frequency] = true
-		}
-	}
-
-	frequencies := make([]string, 0, len(frequencyMap))
-	for freq := range frequencyMap {
-		frequencies = append(frequencies, freq)
+	frequencies := make([]fiber.Map, len(active))
+	for i, activity := range active {
+		info := s.getFrequencyInfo(strconv.FormatInt(activity.Frequency, 10), systemID)
+		info["frequency_hz"] = activity.Frequency
+		info["calls"] = activity.Calls
+		frequencies[i] = info
 	}
-
 	return frequencies
 }
 
 // generateAutoSummary creates a new auto-generated summary
 func (s *Server) generateAutoSummary() {
-	// Only generate if Gemini is configured
-	if s.gemini == nil {
+	// Only generate if an LLM is configured
+	if s.llm == nil {
 		return
 	}
 
@@ -1281,7 +1933,7 @@ func (s *Server) generateAutoSummary() {
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	tomorrow := today.Add(24 * time.Hour)
 
-	calls, err := s.db.GetCallRecords(&today, &tomorrow, "", 100, 0)
+	calls, err := s.db.GetCallRecords(database.CallFilter{Start: &today, End: &tomorrow}, 100, 0)
 	if err != nil {
 		log.Printf("Failed to get calls for auto summary: %v", err)
 		return
@@ -1315,24 +1967,32 @@ func (s *Server) generateAutoSummary() {
 		return
 	}
 
-	// Store the summary
-	s.summaryMu.Lock()
-	s.lastAutoSummary = &AutoSummary{
+	// Store the summary; each day keeps its latest auto summary
+	summary := &database.Summary{
+		CacheKey:    summaryCacheKey(autoSummaryScope, today, tomorrow, "", ""),
+		Scope:       autoSummaryScope,
+		StartTime:   today,
+		EndTime:     tomorrow,
 		Summary:     summaryText,
-		GeneratedAt: time.Now(),
-		TimeRange:   "today",
 		CallCount:   len(calls),
+		Categories:  "[]",
+		GeneratedAt: time.Now(),
+	}
+	if err := s.db.UpsertSummary(summary); err != nil {
+		s.logger.Error("Failed to store auto summary", "error", err)
+		return
 	}
-	s.summaryMu.Unlock()
 
 	log.Printf("Auto-generated summary for %d calls", len(calls))
 }
 
 // Stop gracefully stops the web server
 func (s *Server) Stop() error {
-	if s.gemini != nil {
-		s.gemini.Close()
+	if s.llm != nil {
+		s.llm.Close()
 	}
+	// Event streams never finish on their own, so end them before shutting down
+	close(s.closing)
 	return s.app.Shutdown()
 }
 
@@ -1389,6 +2049,14 @@ func (s *Server) getTimelineSummaries(c *fiber.Ctx) error {
 
 	// Process each hour
 	for hour := 0; hour < 24; hour++ {
+		hourStart := startOfDay.Add(time.Duration(hour) * time.Hour)
+		hourEnd := hourStart.Add(time.Hour)
+
+		// In public mode an hour is only summarized once all of it is shown
+		if s.publicEnd(hourEnd).Before(hourEnd) {
+			continue
+		}
+
 		// Check if we already have a summary for this hour
 		if existingSummary, exists := existingSummaryMap[hour]; exists {
 			// Use existing summary from database
@@ -1399,7 +2067,7 @@ func (s *Server) getTimelineSummaries(c *fiber.Ctx) error {
 
 			summaries[hour] = fiber.Map{
 				"hour":         hour,
-				"summary":      existingSummary.Summary,
+				"summary":      s.redact("", existingSummary.Summary),
 				"call_count":   existingSummary.CallCount,
 				"time_range":   fmt.Sprintf("%02d:00-%02d:59", hour, hour),
 				"generated_at": existingSummary.GeneratedAt,
@@ -1409,10 +2077,7 @@ func (s *Server) getTimelineSummaries(c *fiber.Ctx) error {
 		}
 
 		// No existing summary - check if we should generate one
-		hourStart := startOfDay.Add(time.Duration(hour) * time.Hour)
-		hourEnd := hourStart.Add(time.Hour)
-
-		calls, err := s.db.GetCallRecords(&hourStart, &hourEnd, "", 50, 0)
+		calls, err := s.db.GetCallRecords(database.CallFilter{Start: &hourStart, End: &hourEnd}, 50, 0)
 		if err != nil || len(calls) == 0 {
 			continue // Skip hours with no calls
 		}
@@ -1424,7 +2089,7 @@ func (s *Server) getTimelineSummaries(c *fiber.Ctx) error {
 				categories := s.categorizeHourActivity(calls)
 				summaries[hour] = fiber.Map{
 					"hour":         hour,
-					"summary":      summary,
+					"summary":      s.redact("", summary),
 					"call_count":   len(calls),
 					"time_range":   fmt.Sprintf("%02d:00-%02d:59", hour, hour),
 					"generated_at": time.Now(),
@@ -1469,9 +2134,15 @@ func (s *Server) getHourlySummary(c *fiber.Ctx) error {
 	hourStart := startOfDay.Add(time.Duration(hour) * time.Hour)
 	hourEnd := hourStart.Add(time.Hour)
 
-	calls, err := s.db.GetCallRecords(&hourStart, &hourEnd, "", 100, 0)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch calls"})
+	// In public mode only the calls already shown are listed, and the hour
+	// is summarized once all of it is shown
+	visibleEnd := s.publicEnd(hourEnd)
+	var calls []*database.CallRecord
+	if visibleEnd.After(hourStart) {
+		calls, err = s.db.GetCallRecords(database.CallFilter{Start: &hourStart, End: &visibleEnd}, 100, 0)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch calls"})
+		}
 	}
 
 	if len(calls) == 0 {
@@ -1495,7 +2166,9 @@ func (s *Server) getHourlySummary(c *fiber.Ctx) error {
 		s.logger.Error("Failed to check existing hour summary", "error", dbErr, "date", dateStr, "hour", hour)
 	}
 
-	if existingSummary != nil {
+	if visibleEnd.Before(hourEnd) {
+		categories = s.categorizeHourActivity(calls)
+	} else if existingSummary != nil {
 		// Use existing summary from database
 		summary = existingSummary.Summary
 		if existingSummary.Categories != "" {
@@ -1512,7 +2185,7 @@ func (s *Server) getHourlySummary(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{
 		"hour":         hour,
-		"summary":      summary,
+		"summary":      s.redact("", summary),
 		"call_count":   len(calls),
 		"time_range":   fmt.Sprintf("%02d:00-%02d:59", hour, hour),
 		"generated_at": generatedAt,
@@ -1527,6 +2200,7 @@ func (s *Server) generateTimelineSummary(c *fiber.Ctx) error {
 		StartTime string `json:"start_time"`
 		EndTime   string `json:"end_time"`
 		Prompt    string `json:"prompt,omitempty"`
+		summaryCacheOptions
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -1543,18 +2217,13 @@ func (s *Server) generateTimelineSummary(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid end_time format"})
 	}
 
-	calls, err := s.db.GetCallRecords(&startTime, &endTime, "", 100, 0)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch calls"})
+	endTime = s.publicEnd(endTime)
+	if !endTime.After(startTime) {
+		return c.Status(400).JSON(fiber.Map{"error": "end_time must be after start_time"})
 	}
 
-	if len(calls) == 0 {
-		return c.JSON(fiber.Map{
-			"summary":    "No radio activity detected during this time period",
-			"call_count": 0,
-			"time_range": fmt.Sprintf("%s to %s", startTime.Format("15:04"), endTime.Format("15:04")),
-			"categories": []string{},
-		})
+	if !s.allowsCacheOptions(c, req.summaryCacheOptions) {
+		return c.Status(403).JSON(fiber.Map{"error": "no_cache and ttl_seconds require the admin scope"})
 	}
 
 	prompt := req.Prompt
@@ -1562,15 +2231,31 @@ func (s *Server) generateTimelineSummary(c *fiber.Ctx) error {
 		prompt = "Provide a detailed timeline summary of radio communication activity, highlighting significant events and patterns"
 	}
 
-	summary := s.generateCustomSummary(calls, prompt)
-	categories := s.categorizeHourActivity(calls)
+	summary, cached, err := s.summarizeRange("timeline", startTime, endTime, prompt, s.buildTimelineSummaryPrompt, req.summaryCacheOptions)
+	if err != nil && err != errSummaryUnavailable {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch calls"})
+	}
+
+	timeRange := fmt.Sprintf("%s to %s", startTime.Format("15:04"), endTime.Format("15:04"))
+	if err == errSummaryUnavailable {
+		return c.JSON(fiber.Map{
+			"summary":      "",
+			"time_range":   timeRange,
+			"generated_at": time.Now(),
+			"categories":   []string{},
+			"cached":       false,
+		})
+	}
 
 	return c.JSON(fiber.Map{
-		"summary":      summary,
-		"call_count":   len(calls),
-		"time_range":   fmt.Sprintf("%s to %s", startTime.Format("15:04"), endTime.Format("15:04")),
-		"generated_at": time.Now(),
-		"categories":   categories,
+		"summary":      s.redact("", summary.Summary),
+		"call_count":   summary.CallCount,
+		"time_range":   timeRange,
+		"generated_at": summary.GeneratedAt,
+		"expires_at":   summary.ExpiresAt,
+		"categories":   summaryCategories(summary),
+		"cache_key":    summary.CacheKey,
+		"cached":       cached,
 	})
 }
 
@@ -1602,9 +2287,9 @@ func (s *Server) generateHourSummary(calls []*database.CallRecord, date time.Tim
 		return existingSummary.Summary
 	}
 
-	// Generate new summary only if we have Gemini and this is a completed hour
-	if s.gemini == nil {
-		s.logger.Debug("Gemini not configured, skipping summary generation", "date", dateStr, "hour", hour)
+	// Generate new summary only if we have an LLM and this is a completed hour
+	if s.llm == nil {
+		s.logger.Debug("LLM not configured, skipping summary generation", "date", dateStr, "hour", hour)
 		return ""
 	}
 
@@ -1632,11 +2317,10 @@ func (s *Server) generateHourSummary(calls []*database.CallRecord, date time.Tim
 	// Generate the summary
 	prompt := s.buildTimelineSummaryPrompt(calls, fmt.Sprintf("Analyze radio communications for hour %02d:00-%02d:59", hour, hour))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.config.LLM.Timeout)*time.Second)
 	defer cancel()
 
-	model := s.gemini.GenerativeModel(s.config.Web.Gemini.Model)
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	summaryText, err := s.llm.Generate(ctx, prompt)
 	if err != nil {
 		s.aiCallMu.Lock()
 		s.aiErrorCount++
@@ -1650,13 +2334,6 @@ func (s *Server) generateHourSummary(calls []*database.CallRecord, date time.Tim
 	s.aiErrorCount = 0
 	s.aiCallMu.Unlock()
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		s.logger.Warn("Empty AI summary response", "date", dateStr, "hour", hour)
-		return ""
-	}
-
-	summaryText := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-
 	// Store permanently in database
 	categories := s.categorizeHourActivity(calls)
 	categoriesJSON, _ := json.Marshal(categories)
@@ -1680,9 +2357,9 @@ func (s *Server) generateHourSummary(calls []*database.CallRecord, date time.Tim
 	return summaryText
 }
 
-// generateCustomSummary generates a custom summary with specific prompt (no caching for custom summaries)
-func (s *Server) generateCustomSummary(calls []*database.CallRecord, customPrompt string) string {
-	if s.gemini == nil || len(calls) == 0 {
+// generateCustomSummary generates a summary of calls from a built prompt (no caching for custom summaries)
+func (s *Server) generateCustomSummary(calls []*database.CallRecord, prompt string) string {
+	if s.llm == nil || len(calls) == 0 {
 		return ""
 	}
 
@@ -1705,14 +2382,10 @@ func (s *Server) generateCustomSummary(calls []*database.CallRecord, customPromp
 	s.aiRequestCount++
 	s.aiCallMu.Unlock()
 
-	// Generate the summary
-	prompt := s.buildTimelineSummaryPrompt(calls, customPrompt)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.config.LLM.Timeout)*time.Second)
 	defer cancel()
 
-	model := s.gemini.GenerativeModel(s.config.Web.Gemini.Model)
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	summary, err := s.llm.Generate(ctx, prompt)
 	if err != nil {
 		s.aiCallMu.Lock()
 		s.aiErrorCount++
@@ -1726,12 +2399,7 @@ func (s *Server) generateCustomSummary(calls []*database.CallRecord, customPromp
 	s.aiErrorCount = 0
 	s.aiCallMu.Unlock()
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		s.logger.Warn("Empty custom summary response")
-		return ""
-	}
-
-	return fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
+	return summary
 }
 
 // categorizeHourActivity categorizes the types of activity in an hour
@@ -1795,11 +2463,7 @@ func (s *Server) buildCallSummaries(calls []*database.CallRecord) []fiber.Map {
 
 		// Add brief transcription
 		if call.Transcription != "" {
-			if len(call.Transcription) > 100 {
-				summary["brief"] = call.Transcription[:100] + "..."
-			} else {
-				summary["brief"] = call.Transcription
-			}
+			summary["brief"] = textutil.Truncate(s.redact(call.TalkgroupID, call.Transcription), 100)
 		}
 
 		summaries = append(summaries, summary)
@@ -1844,7 +2508,7 @@ Instructions:
 		prompt += fmt.Sprintf("• %s [%s] %s (%ds): %s\n",
 			timeStr,
 			talkgroup,
-			call.Frequency,
+			frequency.Display(call.Frequency),
 			call.Duration,
 			transcription)
 	}
@@ -1945,6 +2609,12 @@ func (s *Server) cacheCleanupRoutine() {
 		case <-ticker.C:
 			s.cleanUpExpiredCacheEntries()
 
+			if removed, err := s.db.DeleteExpiredSummaries(); err != nil {
+				s.logger.Error("Failed to prune expired summaries", "error", err)
+			} else if removed > 0 {
+				s.logger.Debug("Pruned expired summaries", "count", removed)
+			}
+
 			// Reset AI error count periodically to allow recovery
 			s.aiCallMu.Lock()
 			if s.aiErrorCount > 0 {
@@ -1988,6 +2658,14 @@ func (s *Server) cleanUpExpiredCacheEntries() {
 	}
 }
 
+// clearTimelineCache drops every cached timeline after past calls change
+func (s *Server) clearTimelineCache() {
+	s.timelineCacheMu.Lock()
+	s.timelineCache = make(map[string]*TimelineCacheEntry)
+	s.timelineCacheMu.Unlock()
+	s.dataChanged()
+}
+
 // InvalidateTimelineCache invalidates timeline cache for today to ensure fresh data
 func (s *Server) InvalidateTimelineCache() {
 	today := time.Now().Format("2006-01-02")
@@ -1999,6 +2677,7 @@ func (s *Server) InvalidateTimelineCache() {
 		}
 	}
 	s.timelineCacheMu.Unlock()
+	s.dataChanged()
 
 	s.logger.Debug("Timeline cache invalidated for today", "date", today)
 }
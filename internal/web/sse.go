@@ -0,0 +1,102 @@
+package web
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// handleEventStream streams the same messages as /ws as Server-Sent Events,
+// for clients behind proxies that break WebSockets. Each event's data is one
+// JSON message from the WebSocket catalog, and broadcasts carry their
+// sequence position as the event ID. Browsers send the last ID back when they
+// reconnect, and the messages missed in between are sent first.
+func (s *Server) handleEventStream(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+
+	client, streamCount := s.hub.register()
+	s.logger.Info("Event stream client connected", "total_clients", streamCount)
+
+	// Registered first, so nothing falls between the replay and the queue
+	epoch, latest := s.replay.position()
+	status := fiber.Map{"connected": true, "epoch": epoch, "latest": latest}
+	var missed []hubMessage
+	var lastSent uint64
+	if lastID := c.Get("Last-Event-ID"); lastID != "" {
+		resumed := false
+		if lastEpoch, lastSeq, ok := parseEventID(lastID); ok {
+			missed, latest, resumed = s.replay.since(lastEpoch, lastSeq)
+		}
+		if resumed {
+			lastSent = latest
+		}
+		status["latest"] = latest
+		status["resumed"] = resumed
+	}
+
+	// Each write gets its own deadline in place of the server's write timeout,
+	// which would cut the stream off after 30 seconds
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer func() {
+			streamCount := s.hub.unregister(client)
+			s.logger.Info("Event stream client disconnected", "total_clients", streamCount)
+		}()
+
+		write := func(event string) bool {
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			fmt.Fprint(w, event)
+			return w.Flush() == nil
+		}
+		send := func(message hubMessage) bool {
+			if message.seq == 0 {
+				return write(fmt.Sprintf("data: %s\n\n", message.data))
+			}
+			return write(fmt.Sprintf("id: %s\ndata: %s\n\n", eventID(epoch, message.seq), message.data))
+		}
+
+		data, _ := encodeMessage(MessageStatus, nil, status)
+		if !write(fmt.Sprintf("data: %s\n\n", data)) {
+			return
+		}
+		for _, message := range missed {
+			if !send(message) {
+				return
+			}
+		}
+
+		// Comments keep proxies from closing an idle stream and reveal
+		// clients that have gone away
+		ping := time.NewTicker(30 * time.Second)
+		defer ping.Stop()
+
+		for {
+			select {
+			case message, ok := <-client.send:
+				// A closed queue means the hub evicted this client for
+				// falling behind; the browser reconnects on its own
+				if !ok {
+					return
+				}
+				if message.seq != 0 && message.seq <= lastSent {
+					continue
+				}
+				if !send(message) {
+					return
+				}
+			case <-ping.C:
+				if !write(": ping\n\n") {
+					return
+				}
+			case <-s.closing:
+				return
+			}
+		}
+	})
+	return nil
+}
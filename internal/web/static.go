@@ -0,0 +1,36 @@
+package web
+
+import (
+	"io/fs"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+
+	assets "Meiko/web"
+)
+
+// serveDashboard serves the dashboard at the root and under /static. The copy
+// embedded in the binary is served unless web.static_dir names a directory to
+// serve instead, so the dashboard can be edited without rebuilding.
+func (s *Server) serveDashboard() {
+	var root http.FileSystem
+	if dir := s.config.Web.StaticDir; dir != "" {
+		s.logger.Info("Serving dashboard from directory", "path", dir)
+		root = http.Dir(dir)
+	} else {
+		static, err := fs.Sub(assets.Static, "static")
+		if err != nil {
+			// The embed pattern guarantees the directory
+			panic(err)
+		}
+		root = http.FS(static)
+	}
+
+	// Each mount needs its own handler, which remembers the prefix to strip
+	for _, prefix := range []string{"/static", "/"} {
+		s.app.Use(prefix, filesystem.New(filesystem.Config{
+			Root:  root,
+			Index: "index.html",
+		}))
+	}
+}
@@ -0,0 +1,203 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"Meiko/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// autoSummaryScope is the scope of the background daily summaries
+const autoSummaryScope = "auto"
+
+// errSummaryUnavailable is returned when the AI backend could not produce a summary
+var errSummaryUnavailable = errors.New("summary unavailable (AI not configured, rate limited, or failed)")
+
+// summaryCacheOptions controls cache behaviour for summary requests. Only
+// admins may set them, as they decide how often the LLM is called.
+type summaryCacheOptions struct {
+	NoCache    bool `json:"no_cache"`    // Bypass the cache and regenerate
+	TTLSeconds int  `json:"ttl_seconds"` // Override expiry; negative disables storage
+}
+
+// allowsCacheOptions reports whether a request may set summary cache
+// options, which only admins may
+func (s *Server) allowsCacheOptions(c *fiber.Ctx, opts summaryCacheOptions) bool {
+	return opts == (summaryCacheOptions{}) || s.isAdmin(c)
+}
+
+// summaryPromptBuilder builds the LLM prompt for a range's calls and a custom prompt
+type summaryPromptBuilder func(calls []*database.CallRecord, customPrompt string) string
+
+// summaryCacheKey derives a deterministic cache key for a summary request
+func summaryCacheKey(scope string, start, end time.Time, model, prompt string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|%s|%s|%s",
+		scope,
+		start.UTC().Format(time.RFC3339),
+		end.UTC().Format(time.RFC3339),
+		model,
+		strings.TrimSpace(prompt))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// summarizeRange returns a cached summary for the range or generates and stores a new one.
+// The boolean result reports whether the summary was served from the cache.
+func (s *Server) summarizeRange(scope string, start, end time.Time, prompt string, buildPrompt summaryPromptBuilder, opts summaryCacheOptions) (*database.Summary, bool, error) {
+	// Align to whole minutes so relative ranges ("1h") share cache entries
+	start = start.Truncate(time.Minute)
+	end = end.Truncate(time.Minute)
+
+	key := summaryCacheKey(scope, start, end, s.config.LLM.Model, prompt)
+
+	if !opts.NoCache {
+		cached, err := s.db.GetSummaryByKey(key)
+		if err != nil {
+			s.logger.Error("Failed to read summary cache", "error", err, "key", key)
+		} else if cached != nil {
+			s.logger.Debug("Summary cache hit", "scope", scope, "key", key)
+			return cached, true, nil
+		}
+
+		// A closed range doesn't change, so a summary from an earlier model is still served
+		if end.Before(time.Now().Add(-time.Minute)) {
+			stored, err := s.db.FindSummary(scope, start, end, prompt)
+			if err != nil {
+				s.logger.Error("Failed to read stored summary", "error", err, "scope", scope)
+			} else if stored != nil {
+				s.logger.Debug("Serving stored summary", "scope", scope, "key", stored.CacheKey)
+				return stored, true, nil
+			}
+		}
+	}
+
+	calls, err := s.db.GetCallRecords(database.CallFilter{Start: &start, End: &end}, 100, 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch calls: %w", err)
+	}
+
+	now := time.Now()
+	if len(calls) == 0 {
+		return &database.Summary{
+			CacheKey:    key,
+			Scope:       scope,
+			StartTime:   start,
+			EndTime:     end,
+			Prompt:      prompt,
+			Summary:     "No radio activity detected during this time period",
+			Categories:  "[]",
+			GeneratedAt: now,
+		}, false, nil
+	}
+
+	text := s.generateCustomSummary(calls, buildPrompt(calls, prompt))
+	if text == "" {
+		return nil, false, errSummaryUnavailable
+	}
+
+	categoriesJSON, _ := json.Marshal(s.categorizeHourActivity(calls))
+	summary := &database.Summary{
+		CacheKey:    key,
+		Scope:       scope,
+		StartTime:   start,
+		EndTime:     end,
+		Prompt:      prompt,
+		Summary:     text,
+		CallCount:   len(calls),
+		Categories:  string(categoriesJSON),
+		GeneratedAt: now,
+	}
+
+	// Ranges still receiving calls expire quickly; closed ranges are kept indefinitely
+	switch {
+	case opts.TTLSeconds < 0:
+		return summary, false, nil
+	case opts.TTLSeconds > 0:
+		expires := now.Add(time.Duration(opts.TTLSeconds) * time.Second)
+		summary.ExpiresAt = &expires
+	case end.After(now.Add(-time.Minute)):
+		expires := now.Add(10 * time.Minute)
+		summary.ExpiresAt = &expires
+	}
+
+	if err := s.db.UpsertSummary(summary); err != nil {
+		s.logger.Error("Failed to store summary", "error", err, "key", key)
+	}
+
+	return summary, false, nil
+}
+
+// summaryCategories decodes the stored JSON category list
+func summaryCategories(summary *database.Summary) []string {
+	categories := []string{}
+	if summary.Categories != "" {
+		json.Unmarshal([]byte(summary.Categories), &categories)
+	}
+	return categories
+}
+
+// SummarizeRange returns a cached or newly generated summary text for a time range
+func (s *Server) SummarizeRange(start, end time.Time) (string, error) {
+	summary, _, err := s.summarizeRange("daily", start, end, "", s.buildTimelineSummaryPrompt, summaryCacheOptions{})
+	if err != nil {
+		return "", err
+	}
+	return summary.Summary, nil
+}
+
+// getSummaries lists stored summaries overlapping a time range, so past
+// summaries are read back without calling the LLM again
+func (s *Server) getSummaries(c *fiber.Ctx) error {
+	tr, err := s.parseTimeRange(c.Query("range", "week"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid time range",
+			"details": err.Error(),
+		})
+	}
+
+	scope := c.Query("scope", "")
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > 500 {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "limit must be between 1 and 500",
+		})
+	}
+
+	summaries, err := s.db.GetSummaries(scope, tr.Start, tr.End, limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch summaries",
+			"details": err.Error(),
+		})
+	}
+
+	results := make([]fiber.Map, len(summaries))
+	for i, summary := range summaries {
+		results[i] = fiber.Map{
+			"scope":        summary.Scope,
+			"start_time":   summary.StartTime,
+			"end_time":     summary.EndTime,
+			"prompt":       summary.Prompt,
+			"summary":      s.redact("", summary.Summary),
+			"call_count":   summary.CallCount,
+			"categories":   summaryCategories(summary),
+			"generated_at": summary.GeneratedAt,
+			"expires_at":   summary.ExpiresAt,
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"summaries": results,
+		"start":     tr.Start,
+		"end":       tr.End,
+		"total":     len(results),
+	})
+}
@@ -0,0 +1,73 @@
+package web
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+	"Meiko/internal/sdrtrunk"
+	"Meiko/internal/storage"
+)
+
+// SystemInfo describes a radio system and its call volume
+type SystemInfo struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Configured bool       `json:"configured"` // False for systems that only appear in stored calls
+	TotalCalls int64      `json:"total_calls"`
+	CallsToday int64      `json:"calls_today"`
+	LastCall   *time.Time `json:"last_call,omitempty"`
+}
+
+// SetSDRTrunk sets the supervisor whose processes are reported by /api/system
+func (s *Server) SetSDRTrunk(supervisor *sdrtrunk.Supervisor) {
+	s.sdrtrunk = supervisor
+}
+
+// SetStorage sets the disk forecaster reported by /api/system
+func (s *Server) SetStorage(forecaster *storage.Forecaster) {
+	s.storage = forecaster
+}
+
+// newSystemInfo combines a system's name with its call volume
+func newSystemInfo(id, name string, configured bool, activity *database.SystemActivity) SystemInfo {
+	info := SystemInfo{ID: id, Name: name, Configured: configured}
+	if activity != nil {
+		info.TotalCalls = activity.TotalCalls
+		info.CallsToday = activity.CallsToday
+		info.LastCall = activity.LastCall
+	}
+	return info
+}
+
+// getSystems lists the configured systems, plus any others found in stored
+// calls, with their call volume. Calls without a system are listed under an
+// empty ID.
+func (s *Server) getSystems(c *fiber.Ctx) error {
+	activity, err := s.db.GetSystemActivity()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to load system activity",
+			"details": err.Error(),
+		})
+	}
+
+	systems := make([]SystemInfo, 0, len(s.config.Systems)+len(activity))
+	for _, system := range s.config.Systems {
+		systems = append(systems, newSystemInfo(system.ID, system.Name, true, activity[system.ID]))
+		delete(activity, system.ID)
+	}
+
+	// Systems removed from the configuration, or calls recorded before systems were set up
+	var others []SystemInfo
+	for id, a := range activity {
+		others = append(others, newSystemInfo(id, id, false, a))
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].ID < others[j].ID })
+
+	return c.JSON(fiber.Map{
+		"systems": append(systems, others...),
+	})
+}
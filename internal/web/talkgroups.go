@@ -0,0 +1,168 @@
+package web
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Meiko/internal/database"
+	"Meiko/internal/talkgroups"
+)
+
+// hexColorPattern matches display colors accepted in overrides
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// talkgroupOverrideRequest is the request body for overriding a talkgroup.
+// Empty fields fall back to the playlist and config.
+type talkgroupOverrideRequest struct {
+	Name        string `json:"name"`
+	Group       string `json:"group"`
+	ServiceType string `json:"service_type"`
+	Color       string `json:"color"`
+	Priority    *bool  `json:"priority"`
+	Mute        *bool  `json:"mute"`
+}
+
+// talkgroupEntry is a talkgroup's effective metadata and filtering
+type talkgroupEntry struct {
+	*talkgroups.TalkgroupInfo
+	Priority bool                        `json:"priority"`
+	Mute     bool                        `json:"mute"`
+	Override *database.TalkgroupOverride `json:"override,omitempty"`
+}
+
+// getTalkgroups lists every known talkgroup with overrides applied
+func (s *Server) getTalkgroups(c *fiber.Ctx) error {
+	overrides, err := s.talkgroupOverrides()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch talkgroup overrides",
+			"details": err.Error(),
+		})
+	}
+
+	all := s.talkgroups.GetAllTalkgroups()
+	entries := make([]talkgroupEntry, 0, len(all))
+	for id, info := range all {
+		entries = append(entries, s.talkgroupEntry(info, overrides[id]))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+
+	return c.JSON(fiber.Map{
+		"talkgroups": entries,
+		"count":      len(entries),
+	})
+}
+
+// getTalkgroup returns a single talkgroup with overrides applied
+func (s *Server) getTalkgroup(c *fiber.Ctx) error {
+	overrides, err := s.talkgroupOverrides()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to fetch talkgroup overrides",
+			"details": err.Error(),
+		})
+	}
+
+	id := c.Params("id")
+	return c.JSON(s.talkgroupEntry(s.talkgroups.GetTalkgroupInfo(id), overrides[id]))
+}
+
+// updateTalkgroup replaces a talkgroup's override, taking effect for the
+// next call on it
+func (s *Server) updateTalkgroup(c *fiber.Ctx) error {
+	var req talkgroupOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	override := &database.TalkgroupOverride{
+		TalkgroupID: c.Params("id"),
+		Name:        strings.TrimSpace(req.Name),
+		Group:       strings.TrimSpace(req.Group),
+		ServiceType: strings.ToUpper(strings.TrimSpace(req.ServiceType)),
+		Color:       strings.TrimSpace(req.Color),
+		Priority:    req.Priority,
+		Mute:        req.Mute,
+	}
+
+	if override.ServiceType != "" {
+		if _, exists := s.talkgroups.GetServiceTypes()[talkgroups.ServiceType(override.ServiceType)]; !exists {
+			return c.Status(400).JSON(fiber.Map{"error": "Unknown service type"})
+		}
+	}
+	if override.Color != "" && !hexColorPattern.MatchString(override.Color) {
+		return c.Status(400).JSON(fiber.Map{"error": "Color must be a hex color such as #ff7700"})
+	}
+	if override.Mute != nil && *override.Mute && override.Priority != nil && *override.Priority {
+		return c.Status(400).JSON(fiber.Map{"error": "A talkgroup cannot be both muted and priority"})
+	}
+
+	if err := s.db.SaveTalkgroupOverride(override); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error":   "Failed to save talkgroup override",
+			"details": err.Error(),
+		})
+	}
+
+	s.reloadTalkgroups()
+	return c.JSON(s.talkgroupEntry(s.talkgroups.GetTalkgroupInfo(override.TalkgroupID), override))
+}
+
+// deleteTalkgroupOverride reverts a talkgroup to its playlist and config values
+func (s *Server) deleteTalkgroupOverride(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := s.db.DeleteTalkgroupOverride(id); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error":   "Failed to delete talkgroup override",
+			"details": err.Error(),
+		})
+	}
+
+	s.reloadTalkgroups()
+	return c.JSON(s.talkgroupEntry(s.talkgroups.GetTalkgroupInfo(id), nil))
+}
+
+// talkgroupOverrides returns the stored overrides by talkgroup ID
+func (s *Server) talkgroupOverrides() (map[string]*database.TalkgroupOverride, error) {
+	list, err := s.db.GetTalkgroupOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[string]*database.TalkgroupOverride, len(list))
+	for _, override := range list {
+		overrides[override.TalkgroupID] = override
+	}
+	return overrides, nil
+}
+
+// talkgroupEntry combines a talkgroup's information with its effective filter
+func (s *Server) talkgroupEntry(info *talkgroups.TalkgroupInfo, override *database.TalkgroupOverride) talkgroupEntry {
+	filter := s.talkgroups.GetTalkgroupFilter(info.ID)
+	return talkgroupEntry{
+		TalkgroupInfo: info,
+		Priority:      filter.Priority,
+		Mute:          filter.Mute,
+		Override:      override,
+	}
+}
+
+// reloadTalkgroups applies override changes and drops cached classifications
+func (s *Server) reloadTalkgroups() {
+	if err := s.talkgroups.ReloadOverrides(); err != nil {
+		s.logger.Error("Failed to reload talkgroup overrides", "error", err)
+	}
+	s.clearTalkgroupCache()
+}
+
+// clearTalkgroupCache drops cached classifications after talkgroups change
+func (s *Server) clearTalkgroupCache() {
+	s.talkgroupCacheMu.Lock()
+	s.talkgroupCache = make(map[string]*TalkgroupCacheEntry)
+	s.talkgroupCacheMu.Unlock()
+}
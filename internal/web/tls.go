@@ -0,0 +1,39 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"Meiko/internal/certs"
+)
+
+// listenACME serves HTTPS with certificates obtained and renewed from the
+// ACME certificate authority, until Stop
+func (s *Server) listenACME(addr string) error {
+	manager, err := certs.New(s.config.Web.TLS.ACME, s.logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := manager.Start(ctx); err != nil {
+		return fmt.Errorf("failed to obtain TLS certificate: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.app.Listener(tls.NewListener(listener, manager.TLSConfig()))
+}
@@ -0,0 +1,76 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// apiPrefix is where the current version of the REST API is served. Responses
+// under it only change compatibly; breaking changes get a new version.
+const apiPrefix = "/api/v1"
+
+// legacyAPI serves the unversioned /api paths, which predate /api/v1, from
+// /api/v1. Their responses are marked deprecated and link to their successor,
+// so clients can find out before the paths are removed. When the legacy paths
+// are disabled they answer 404 with where to go instead.
+func (s *Server) legacyAPI() fiber.Handler {
+	cfg := s.config.Web.LegacyAPI
+
+	var sunset string
+	if date, err := time.ParseInLocation("2006-01-02", cfg.Sunset, time.Local); err == nil {
+		sunset = date.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if isVersionedPath(path) {
+			return c.Next()
+		}
+		successor := apiPrefix + strings.TrimPrefix(path, "/api")
+
+		if !cfg.IsEnabled() {
+			return c.Status(404).JSON(fiber.Map{
+				"error":   "Unversioned API paths are disabled",
+				"details": "Use " + successor,
+			})
+		}
+
+		c.Path(successor)
+		err := c.Next()
+
+		// After the handler, so pagination links are kept alongside the successor
+		c.Set("Deprecation", "true")
+		if sunset != "" {
+			c.Set("Sunset", sunset)
+		}
+		target := requestBasePath(c) + successor
+		if query := c.Request().URI().QueryString(); len(query) > 0 {
+			target += "?" + string(query)
+		}
+		link := fmt.Sprintf(`<%s>; rel="successor-version"`, target)
+		if existing := string(c.Response().Header.Peek(fiber.HeaderLink)); existing != "" {
+			link = existing + ", " + link
+		}
+		c.Set(fiber.HeaderLink, link)
+
+		return err
+	}
+}
+
+// isVersionedPath reports whether a path is under the current API version
+func isVersionedPath(path string) bool {
+	return path == apiPrefix || strings.HasPrefix(path, apiPrefix+"/")
+}
+
+// unversionedPath returns a path with the API version removed, as route
+// checks that predate versioning expect
+func unversionedPath(path string) string {
+	if isVersionedPath(path) {
+		return "/api" + strings.TrimPrefix(path, apiPrefix)
+	}
+	return path
+}
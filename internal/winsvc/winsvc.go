@@ -0,0 +1,13 @@
+// Package winsvc runs Meiko as a Windows service and registers it with the
+// service control manager. On other platforms every operation reports that
+// services are unsupported, so callers need no build tags of their own.
+package winsvc
+
+// Name is the service name registered with Windows
+const Name = "Meiko"
+
+// displayName and description are shown in the Services console
+const (
+	displayName = "Meiko"
+	description = "Unified SDRTrunk and transcription system"
+)
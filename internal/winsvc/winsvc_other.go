@@ -0,0 +1,42 @@
+//go:build !windows
+
+package winsvc
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrUnsupported is returned for service operations outside Windows
+var ErrUnsupported = errors.New("Windows services are only supported on Windows")
+
+// IsService reports whether the process was started by the service control
+// manager, which is never the case outside Windows
+func IsService() bool {
+	return false
+}
+
+// Run is only supported on Windows
+func Run(serve func(stop <-chan os.Signal)) error {
+	return ErrUnsupported
+}
+
+// Install is only supported on Windows
+func Install(args ...string) error {
+	return ErrUnsupported
+}
+
+// Uninstall is only supported on Windows
+func Uninstall() error {
+	return ErrUnsupported
+}
+
+// Start is only supported on Windows
+func Start() error {
+	return ErrUnsupported
+}
+
+// Stop is only supported on Windows
+func Stop() error {
+	return ErrUnsupported
+}
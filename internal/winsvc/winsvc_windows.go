@@ -0,0 +1,158 @@
+//go:build windows
+
+package winsvc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// IsService reports whether the process was started by the service control
+// manager
+func IsService() bool {
+	isService, err := svc.IsWindowsService()
+	return err == nil && isService
+}
+
+// Run runs serve as the Meiko service until it returns. A stop or shutdown
+// request from Windows is delivered on the stop channel as SIGTERM, the same
+// way a console Meiko learns it should shut down.
+func Run(serve func(stop <-chan os.Signal)) error {
+	return svc.Run(Name, &handler{serve: serve})
+}
+
+// handler adapts serve to the service control manager's protocol
+type handler struct {
+	serve func(stop <-chan os.Signal)
+}
+
+// Execute reports the service running, forwards stop requests and waits for
+// serve to finish shutting down
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	stop := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.serve(stop)
+	}()
+
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case <-done:
+			// serve returned without being asked to, such as after a fatal error
+			status <- svc.Status{State: svc.StopPending}
+			return false, 0
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				status <- request.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				stop <- syscall.SIGTERM
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
+
+// Install registers Meiko as an automatically started service running the
+// current executable with args. Windows restarts the service if it crashes.
+func Install(args ...string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the Meiko executable: %w", err)
+	}
+	exe, err = filepath.Abs(exe)
+	if err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager (run as Administrator): %w", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(Name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s is already installed", Name)
+	}
+
+	s, err := m.CreateService(Name, exe, mgr.Config{
+		DisplayName: displayName,
+		Description: description,
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", err)
+	}
+	defer s.Close()
+
+	recovery := []mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 10 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}
+	if err := s.SetRecoveryActions(recovery, uint32((24 * time.Hour).Seconds())); err != nil {
+		return fmt.Errorf("failed to set service recovery actions: %w", err)
+	}
+
+	return nil
+}
+
+// Uninstall removes the Meiko service
+func Uninstall() error {
+	return withService(func(s *mgr.Service) error {
+		if err := s.Delete(); err != nil {
+			return fmt.Errorf("failed to delete service: %w", err)
+		}
+		return nil
+	})
+}
+
+// Start asks Windows to start the Meiko service
+func Start() error {
+	return withService(func(s *mgr.Service) error {
+		if err := s.Start(); err != nil {
+			return fmt.Errorf("failed to start service: %w", err)
+		}
+		return nil
+	})
+}
+
+// Stop asks the Meiko service to shut down
+func Stop() error {
+	return withService(func(s *mgr.Service) error {
+		if _, err := s.Control(svc.Stop); err != nil {
+			return fmt.Errorf("failed to stop service: %w", err)
+		}
+		return nil
+	})
+}
+
+// withService opens the installed Meiko service for fn
+func withService(fn func(s *mgr.Service) error) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service manager (run as Administrator): %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(Name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", Name)
+	}
+	defer s.Close()
+
+	return fn(s)
+}
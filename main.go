@@ -5,21 +5,43 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
+	"Meiko/internal/agent"
+	"Meiko/internal/anomaly"
+	"Meiko/internal/archive"
+	"Meiko/internal/backup"
 	"Meiko/internal/config"
+	"Meiko/internal/corrections"
 	"Meiko/internal/database"
+	"Meiko/internal/digest"
 	"Meiko/internal/discord"
+	"Meiko/internal/embeddings"
+	"Meiko/internal/enrichment"
+	"Meiko/internal/llm"
 	"Meiko/internal/logger"
+	"Meiko/internal/matrix"
 	"Meiko/internal/monitoring"
+	"Meiko/internal/notify"
 	"Meiko/internal/preflight"
 	"Meiko/internal/processor"
+	"Meiko/internal/push"
+	"Meiko/internal/redaction"
 	"Meiko/internal/sdrtrunk"
+	"Meiko/internal/storage"
+	"Meiko/internal/systemd"
 	"Meiko/internal/talkgroups"
+	"Meiko/internal/telegram"
 	"Meiko/internal/transcription"
+	"Meiko/internal/translation"
+	"Meiko/internal/usb"
 	"Meiko/internal/watcher"
 	"Meiko/internal/web"
+	"Meiko/internal/winsvc"
 )
 
 const (
@@ -28,27 +50,104 @@ const (
 )
 
 type Application struct {
-	config      *config.Config
-	logger      *logger.Logger
-	db          *database.Database
-	talkgroups  *talkgroups.Service
-	discord     *discord.Client
-	sdrtrunk    *sdrtrunk.Manager
-	watcher     *watcher.FileWatcher
-	transcriber *transcription.Service
-	processor   *processor.CallProcessor
-	monitor     *monitoring.SystemMonitor
-	webServer   *web.Server
-	ctx         context.Context
-	cancel      context.CancelFunc
+	config       *config.Config
+	logger       *logger.Logger
+	db           *database.Database
+	talkgroups   *talkgroups.Service
+	discord      *discord.Client
+	notifier     *notify.Notifier       // Telegram and Matrix
+	sdrtrunk     *sdrtrunk.Supervisor   // One SDRTrunk process per system
+	watchers     []*watcher.FileWatcher // One file watcher per system
+	transcriber  *transcription.Service
+	processor    *processor.CallProcessor
+	corrections  *corrections.Engine
+	redactor     *redaction.Redactor // nil when redaction is disabled
+	translator   *translation.Translator
+	enricher     *enrichment.Enricher
+	embeddings   *embeddings.Service
+	monitor      *monitoring.SystemMonitor
+	usbWatchdog  *usb.Watchdog
+	webServer    *web.Server
+	archive      *archive.Exporter
+	digests      *digest.Scheduler
+	storage      *storage.Forecaster
+	anomalies    *anomaly.Detector
+	audioArchive *storage.AudioArchiver
+	reconciler   *storage.Reconciler
+	backups      *backup.Service
+	agent        *agent.Uploader
+	configPath   string
+	debug        bool // Overrides logging.level
+	ctx          context.Context
+	cancel       context.CancelFunc
 }
 
 func main() {
+	// Without a command, or with only flags, Meiko serves
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		runServe(args)
+	case "transcribe":
+		os.Exit(runTranscribeCommand(args))
+	case "import":
+		os.Exit(runImportCommand(args))
+	case "scan":
+		os.Exit(runScanCommand(args))
+	case "db":
+		os.Exit(runDBCommand(args))
+	case "apikey":
+		os.Exit(runAPIKeyCommand(args))
+	case "service":
+		os.Exit(runServiceCommand(args))
+	case "version":
+		fmt.Printf("%s v%s\n", AppName, AppVersion)
+	case "help":
+		printUsage()
+	default:
+		printUsage()
+		os.Exit(2)
+	}
+}
+
+// runServe runs the full application until interrupted
+func runServe(args []string) {
+	flags, configPath := commandFlags("serve")
+	debug := flags.Bool("debug", false, "Log at debug level")
+	flags.Parse(args)
+
 	fmt.Printf("🎤 %s v%s - Unified SDRTrunk & Transcription System\n", AppName, AppVersion)
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-	app := &Application{}
+	app := &Application{configPath: *configPath, debug: *debug}
+
+	// Under the Windows service manager, stop requests arrive from Windows
+	// instead of signals
+	if winsvc.IsService() {
+		if err := app.useConfigDir(); err != nil {
+			fmt.Printf("❌ Failed to initialize: %v\n", err)
+			os.Exit(1)
+		}
+		if err := winsvc.Run(app.serve); err != nil {
+			fmt.Printf("❌ Service failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Setup signal handling
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	app.serve(sigChan)
+}
 
+// serve initializes and starts the application, then runs it until a value
+// arrives on stop
+func (app *Application) serve(stop <-chan os.Signal) {
 	// Setup graceful shutdown
 	app.ctx, app.cancel = context.WithCancel(context.Background())
 
@@ -58,36 +157,54 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Setup signal handling
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
 	// Start the application
 	if err := app.start(); err != nil {
 		app.logger.Error("Failed to start application", "error", err)
 		os.Exit(1)
 	}
 
-	// Wait for shutdown signal
-	<-sigChan
+	// Tell systemd startup is complete, then wait for a shutdown signal
+	app.notifySystemd(systemd.Ready)
+	app.wait(stop)
 	app.logger.Info("Shutdown signal received, gracefully shutting down...")
 
 	// Shutdown the application
 	app.shutdown()
 }
 
+// useConfigDir makes the configuration path absolute and moves to its
+// directory, so relative paths in the configuration resolve next to it.
+// Windows starts services in the system directory.
+func (app *Application) useConfigDir() error {
+	path, err := filepath.Abs(app.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve configuration path: %w", err)
+	}
+	app.configPath = path
+	if err := os.Chdir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to change to configuration directory: %w", err)
+	}
+	return nil
+}
+
 func (app *Application) initialize() error {
 	var err error
 
 	// Load configuration
-	app.config, err = config.Load("config.yaml")
+	app.config, err = config.Load(app.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	if app.debug {
+		app.config.Logging.Level = "debug"
+	}
 
 	// Initialize logger
 	app.logger = logger.New(app.config.Logging)
 	app.logger.Info("Configuration loaded successfully")
+	if len(app.config.IgnoredEnv) > 0 {
+		app.logger.Warn("Ignoring environment variables that match no setting", "variables", strings.Join(app.config.IgnoredEnv, ", "))
+	}
 
 	// Run pre-flight checks
 	if app.config.Preflight.Enabled {
@@ -99,6 +216,15 @@ func (app *Application) initialize() error {
 		app.logger.Success("All pre-flight checks passed ✓")
 	}
 
+	// Agents only capture and forward recordings to a server
+	if app.config.Mode == "agent" {
+		if err := app.initializeCapture(); err != nil {
+			return err
+		}
+		app.initializeStorage()
+		return nil
+	}
+
 	// Initialize database
 	app.db, err = database.New(app.config.Database, app.logger)
 	if err != nil {
@@ -107,17 +233,42 @@ func (app *Application) initialize() error {
 
 	// Initialize talkgroups service
 	app.talkgroups = talkgroups.New(app.config, app.logger)
+	if err := app.talkgroups.SetDatabase(app.db); err != nil {
+		return err
+	}
+
+	// Initialize redaction of sensitive details in calls shown and posted
+	if app.config.Redaction.Enabled {
+		app.redactor, err = redaction.New(app.config.Redaction, app.logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize redaction: %w", err)
+		}
+		app.logger.Info("Redaction enabled", "rules", app.redactor.RuleCount())
+	}
 
 	// Initialize Discord client
-	if app.config.Discord.Token != "" {
+	if app.config.Discord.Enabled() {
 		app.discord, err = discord.New(app.config.Discord, app.logger, app.talkgroups)
 		if err != nil {
 			app.logger.Warn("Failed to initialize Discord client", "error", err)
+		} else {
+			app.discord.SetSystems(app.config.Systems)
+			app.discord.SetRedactor(app.redactor)
 		}
 	}
 
-	// Initialize SDRTrunk manager
-	app.sdrtrunk = sdrtrunk.New(app.config.SDRTrunk, app.logger)
+	// Initialize Telegram and Matrix notifications
+	if app.config.Telegram.Enabled || app.config.Matrix.Enabled {
+		app.notifier = notify.New(app.logger)
+		if app.config.Telegram.Enabled {
+			app.notifier.Add(telegram.New(app.config.Telegram), app.config.Telegram.Notifications, app.config.Telegram.MinSeverity)
+		}
+		if app.config.Matrix.Enabled {
+			app.notifier.Add(matrix.New(app.config.Matrix), app.config.Matrix.Notifications, app.config.Matrix.MinSeverity)
+		}
+		app.notifier.SetSystems(app.config.Systems)
+		app.notifier.SetRedactor(app.redactor)
+	}
 
 	// Initialize transcription service
 	app.transcriber, err = transcription.New(app.config.Transcription, app.logger)
@@ -125,18 +276,94 @@ func (app *Application) initialize() error {
 		return fmt.Errorf("failed to initialize transcription service: %w", err)
 	}
 
-	// Initialize file watcher
-	app.watcher, err = watcher.New(app.config.SDRTrunk.AudioOutputDir, app.config.FileMonitor, app.logger)
-	if err != nil {
-		return fmt.Errorf("failed to initialize file watcher: %w", err)
+	// Initialize SDRTrunk and the file watcher (servers only receive uploads)
+	if app.config.Captures() {
+		if err := app.initializeCapture(); err != nil {
+			return err
+		}
 	}
 
 	// Initialize call processor
 	app.processor = processor.New(app.db, app.transcriber, app.discord, app.config, app.logger, app.talkgroups)
+	if app.notifier != nil {
+		app.processor.SetNotifier(app.notifier)
+	}
+	if app.config.Push.Enabled {
+		pushService := push.New(app.config.Push, app.talkgroups, app.logger)
+		pushService.SetRedactor(app.redactor)
+		pushService.OnAlert(app.recordKeywordAlert)
+		app.processor.SetPush(pushService)
+	}
+
+	// Initialize transcription correction engine
+	if app.config.Corrections.Enabled {
+		app.corrections, err = corrections.New(app.config.Corrections, app.db, app.logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize correction engine: %w", err)
+		}
+		app.processor.SetCorrections(app.corrections)
+	}
+
+	// Initialize translation of non-English transcriptions
+	if app.config.Transcription.Translation.Enabled {
+		llmConfig := app.config.LLM
+		if app.config.Transcription.Translation.Model != "" {
+			llmConfig.Model = app.config.Transcription.Translation.Model
+		}
+		provider, err := llm.New(app.ctx, llmConfig)
+		if err != nil {
+			return fmt.Errorf("failed to initialize translation: %w", err)
+		}
+		app.translator = translation.New(provider)
+		app.processor.SetTranslator(app.translator)
+	}
+
+	// Initialize LLM enrichment of transcriptions
+	if app.config.Enrichment.Enabled {
+		llmConfig := app.config.LLM
+		if app.config.Enrichment.Model != "" {
+			llmConfig.Model = app.config.Enrichment.Model
+		}
+		provider, err := llm.New(app.ctx, llmConfig)
+		if err != nil {
+			return fmt.Errorf("failed to initialize enrichment: %w", err)
+		}
+		app.enricher = enrichment.New(provider, app.config.Enrichment)
+		app.processor.SetEnricher(app.enricher)
+	}
+
+	// Initialize semantic search over transcriptions
+	if app.config.Embeddings.Enabled {
+		embedder, err := llm.NewEmbedder(app.ctx, app.config.Embeddings.LLM(app.config.LLM.Timeout))
+		if err != nil {
+			return fmt.Errorf("failed to initialize embeddings: %w", err)
+		}
+		app.embeddings = embeddings.New(embedder, app.db, app.config.Embeddings,
+			time.Duration(app.config.LLM.Timeout)*time.Second, app.logger)
+		app.processor.SetEmbeddings(app.embeddings)
+	}
 
 	// Initialize system monitor
 	if app.config.Monitoring.Enabled {
 		app.monitor = monitoring.New(app.config.Monitoring, app.discord, app.logger)
+		app.monitor.OnAlert(func(alert monitoring.Alert) {
+			level := database.EventWarning
+			if alert.State == "recovered" {
+				level = database.EventInfo
+			}
+			app.recordEvent(database.EventAlert, level, alert.Metric, alert.Message)
+			if app.notifier != nil {
+				name := strings.ReplaceAll(alert.Metric, "_", " ")
+				if alert.State == "recovered" {
+					app.notifier.SendHealthAlert("✅ "+name+" back to normal", alert.Message, true)
+				} else {
+					app.notifier.SendHealthAlert("⚠️ High "+name, alert.Message, false)
+				}
+			}
+			if app.webServer != nil {
+				app.webServer.BroadcastHealth(alert)
+			}
+		})
 	}
 
 	// Initialize web server
@@ -145,12 +372,289 @@ func (app *Application) initialize() error {
 		if err != nil {
 			return fmt.Errorf("failed to initialize web server: %w", err)
 		}
+		app.webServer.SetCorrections(app.corrections)
+		app.webServer.SetRedaction(app.redactor)
+		if app.embeddings != nil {
+			app.webServer.SetEmbeddings(app.embeddings)
+		}
+		if app.sdrtrunk != nil {
+			app.webServer.SetSDRTrunk(app.sdrtrunk)
+		}
+		if app.config.Ingest.Enabled {
+			app.webServer.SetIngester(app.processor)
+		}
+		app.webServer.SetReprocessor(app.processor)
+		app.webServer.SetPipeline(app.processor)
+		app.addHealthChecks()
 		app.logger.Info("Web server initialized", "port", app.config.Web.Port)
 	}
 
+	// Initialize daily report archive
+	if app.config.Archive.Enabled {
+		var summarizer archive.Summarizer
+		if app.webServer != nil {
+			summarizer = app.webServer
+		}
+		app.archive, err = archive.New(app.config.Archive, app.db, summarizer, app.logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize archive exporter: %w", err)
+		}
+	}
+
+	// Initialize email digests
+	if app.config.Email.Enabled && len(app.config.Email.Digests) > 0 {
+		var summarizer archive.Summarizer
+		if app.webServer != nil {
+			summarizer = app.webServer
+		}
+		app.digests, err = digest.New(app.config.Email, app.db, summarizer, app.logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize email digests: %w", err)
+		}
+	}
+
+	app.initializeStorage()
+
+	// Initialize audio archiving to object storage
+	if app.config.AudioArchive.Enabled {
+		app.audioArchive = storage.NewAudioArchiver(app.config.AudioArchive, app.db, app.logger)
+		if app.webServer != nil {
+			app.webServer.SetAudioArchive(app.audioArchive)
+		}
+	}
+
+	// Initialize reconciliation of the database with the recordings on disk
+	if app.config.Reconcile.Enabled {
+		app.reconciler = storage.NewReconciler(app.config.Reconcile, app.db, app.recordingDirectories(), app.recordingPatterns(), app.logger)
+		if app.webServer != nil {
+			app.webServer.SetReconciler(app.reconciler)
+		}
+	}
+
+	// Initialize detection of unusual talkgroup activity
+	if app.config.Anomalies.Enabled {
+		app.anomalies = anomaly.New(app.config.Anomalies, app.db, app.logger)
+		app.anomalies.OnAnomaly(func(a anomaly.Anomaly) {
+			app.recordEvent(database.EventAnomaly, database.EventWarning, a.TalkgroupAlias, a.Message())
+			if app.discord != nil && app.config.Anomalies.Discord {
+				app.discord.SendAnomalyAlert(a.TalkgroupAlias, a.Message())
+			}
+		})
+	}
+
+	// Initialize scheduled database backups
+	if app.config.Backup.Enabled {
+		app.backups = backup.New(app.config.Backup, app.db, app.logger)
+	}
+
 	return nil
 }
 
+// addHealthChecks registers the components only the application knows about
+// with /api/health
+func (app *Application) addHealthChecks() {
+	if len(app.watchers) > 0 {
+		app.webServer.AddHealthCheck("watcher", true, func(ctx context.Context) error {
+			watching := 0
+			for _, fw := range app.watchers {
+				if fw.IsWatching() {
+					watching++
+				}
+			}
+			switch {
+			case watching == 0:
+				return fmt.Errorf("not watching")
+			case watching < len(app.watchers):
+				return web.Degraded("%d of %d directories watched", watching, len(app.watchers))
+			}
+			return nil
+		})
+	}
+	if app.discord != nil {
+		app.webServer.AddHealthCheck("discord", false, func(ctx context.Context) error {
+			if !app.discord.IsConnected() {
+				return fmt.Errorf("disconnected")
+			}
+			return nil
+		})
+	}
+	// Calls wait for transcription to recover, so an outage only degrades
+	app.webServer.AddHealthCheck("transcription", false, app.transcriber.Check)
+}
+
+// initializeStorage sets up the disk forecaster for the recording directories
+func (app *Application) initializeStorage() {
+	if !app.config.Storage.Enabled {
+		return
+	}
+
+	directories := app.recordingDirectories()
+	if len(directories) == 0 {
+		return
+	}
+
+	app.storage = storage.New(app.config.Storage, directories, app.recordingPatterns(), app.logger)
+	app.storage.OnAlert(func(message string) {
+		app.recordEvent(database.EventStorage, database.EventWarning, "", message)
+		if app.discord != nil {
+			app.discord.SendHealthAlert("💾 Disk space", message, false)
+		}
+		if app.notifier != nil {
+			app.notifier.SendHealthAlert("💾 Disk space", message, false)
+		}
+	})
+	if app.webServer != nil {
+		app.webServer.SetStorage(app.storage)
+	}
+}
+
+// recordingDirectories returns the directories recordings are kept in: the
+// watched directories, uploads from agents and the organized archive
+func (app *Application) recordingDirectories() []string {
+	var directories []string
+	if app.config.Captures() {
+		for _, directory := range app.config.WatchDirectories() {
+			directories = append(directories, directory.Path)
+		}
+	}
+	if app.config.Ingest.Enabled {
+		directories = append(directories, app.config.Ingest.Directory)
+	}
+	if app.config.Organize.Enabled {
+		directories = append(directories, app.config.Organize.Directory)
+	}
+	return directories
+}
+
+// recordingPatterns returns the filename patterns of recordings in any
+// watched directory
+func (app *Application) recordingPatterns() []string {
+	patterns := slices.Clone(app.config.FileMonitor.Patterns)
+	for _, directory := range app.config.FileMonitor.Directories {
+		for _, pattern := range directory.Patterns {
+			if !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+// initializeCapture sets up SDRTrunk, unless it runs externally, and a file
+// watcher for each system, plus the uploader in agent mode
+func (app *Application) initializeCapture() error {
+	if app.config.ManagesSDRTrunk() {
+		app.initializeSDRTrunk()
+	} else {
+		app.logger.Info("SDRTrunk runs externally, only watching its recordings")
+	}
+
+	// Initialize file watchers, the systems' output directories first
+	systems := app.config.CaptureSystems()
+	for i, directory := range app.config.WatchDirectories() {
+		fw, err := watcher.New(directory, app.config.FileMonitor, app.logger)
+		if err != nil {
+			return fmt.Errorf("failed to initialize file watcher for %s: %w", directory.Path, err)
+		}
+		app.watchers = append(app.watchers, fw)
+
+		// Let the health check see when this system last produced a recording
+		if app.sdrtrunk != nil && i < len(systems) {
+			app.sdrtrunk.Managers()[i].SetActivitySource(fw.LastFile)
+		}
+	}
+
+	// Initialize USB device watchdog
+	if app.config.USBWatchdog.Enabled {
+		app.usbWatchdog = usb.NewWatchdog(app.config.USBWatchdog, app.logger)
+		app.usbWatchdog.OnEvent(func(event usb.Event) {
+			if event.Present {
+				app.recordEvent(database.EventDevice, database.EventInfo, event.Device.String(), "SDR receiver reconnected")
+			} else {
+				app.recordEvent(database.EventDevice, database.EventError, event.Device.String(), "SDR receiver disconnected")
+			}
+			if app.discord != nil {
+				app.discord.SendDeviceAlert(event.Device.String(), event.Present, event.Missing)
+			}
+			if app.notifier != nil {
+				app.notifier.SendDeviceAlert(event.Device.String(), event.Present, event.Missing)
+			}
+		})
+		if app.sdrtrunk != nil {
+			app.usbWatchdog.OnRecovered(app.sdrtrunk.Restart)
+		}
+	}
+
+	// Initialize agent uploader
+	if app.config.Mode == "agent" {
+		app.agent = agent.New(app.config.Agent, app.logger)
+		app.logger.Info("Agent mode: recordings will be forwarded", "server", app.config.Agent.ServerURL, "site", app.config.Agent.Site)
+	}
+
+	return nil
+}
+
+// initializeSDRTrunk sets up the supervisor running SDRTrunk for each system
+func (app *Application) initializeSDRTrunk() {
+	app.sdrtrunk = sdrtrunk.NewSupervisor(app.config.CaptureSystems(), app.logger)
+	app.sdrtrunk.OnEvent(func(event sdrtrunk.Event) {
+		level := database.EventWarning
+		switch {
+		case event.Running == 0:
+			level = database.EventError
+		case event.Process.Running && event.Process.Hung == "":
+			level = database.EventInfo
+		}
+		app.recordEvent(database.EventSDRTrunk, level, event.Process.Name, event.Message)
+		if app.discord != nil {
+			app.discord.SendProcessAlert(event.Process.Name, event.Message, event.Running, event.Total)
+		}
+		if app.notifier != nil {
+			app.notifier.SendProcessAlert(event.Process.Name, event.Message, event.Running, event.Total)
+		}
+	})
+}
+
+// wait blocks until a shutdown signal arrives, sending systemd watchdog
+// heartbeats meanwhile when the service has WatchdogSec set
+func (app *Application) wait(sigChan <-chan os.Signal) {
+	interval := systemd.WatchdogInterval()
+	if interval == 0 {
+		<-sigChan
+		return
+	}
+
+	// Heartbeat at half the timeout, as systemd recommends
+	app.logger.Info("Sending systemd watchdog heartbeats", "interval", interval/2)
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sigChan:
+			return
+		case <-ticker.C:
+			// A hung database stops the heartbeat, so systemd restarts Meiko
+			if app.db != nil {
+				if err := app.db.Ping(); err != nil {
+					app.logger.Warn("Skipping watchdog heartbeat, database not responding", "error", err)
+					continue
+				}
+			}
+			app.notifySystemd(systemd.Watchdog)
+		}
+	}
+}
+
+// notifySystemd sends a state to systemd when running as a Type=notify service
+func (app *Application) notifySystemd(state string) {
+	sent, err := systemd.Notify(state)
+	if err != nil {
+		app.logger.Warn("Failed to notify systemd", "state", state, "error", err)
+	} else if sent {
+		app.logger.Debug("Systemd", "Notified systemd", "state", state)
+	}
+}
+
 func (app *Application) start() error {
 	app.logger.Info("Starting Meiko application...")
 
@@ -164,21 +668,60 @@ func (app *Application) start() error {
 		}
 	}
 
-	// Start SDRTrunk process
-	app.logger.Info("Starting SDRTrunk process...")
-	if err := app.sdrtrunk.Start(app.ctx); err != nil {
-		return fmt.Errorf("failed to start SDRTrunk: %w", err)
+	// Start Telegram and Matrix notifications
+	if app.notifier != nil {
+		app.notifier.Start()
+		app.notifier.SendStartup(AppName, AppVersion)
 	}
 
-	// Start file watcher
-	app.logger.Info("Starting file watcher...")
-	if err := app.watcher.Start(app.ctx); err != nil {
-		return fmt.Errorf("failed to start file watcher: %w", err)
+	// Reload the talkgroup playlist when SDRTrunk or an editor changes it
+	if app.talkgroups != nil && app.config.Talkgroups.PlaylistPath != "" {
+		if err := app.talkgroups.WatchPlaylist(app.ctx); err != nil {
+			app.logger.Warn("Failed to watch talkgroup playlist", "error", err)
+		}
 	}
 
-	// Start call processor
-	app.logger.Info("Starting call processor...")
-	app.processor.Start(app.ctx, app.watcher.Events())
+	// Start an SDRTrunk process and file watcher for each system
+	var events <-chan watcher.FileEvent
+	if app.sdrtrunk != nil {
+		app.logger.Info("Starting SDRTrunk processes...", "count", len(app.sdrtrunk.Managers()))
+		if err := app.sdrtrunk.Start(app.ctx); err != nil {
+			return fmt.Errorf("failed to start SDRTrunk: %w", err)
+		}
+	}
+	if app.usbWatchdog != nil {
+		app.logger.Info("Starting USB device watchdog...")
+		app.usbWatchdog.Start(app.ctx)
+	}
+	for _, fw := range app.watchers {
+		app.logger.Info("Starting file watcher...", "directory", fw.GetDirectory())
+		if err := fw.Start(app.ctx); err != nil {
+			return fmt.Errorf("failed to start file watcher for %s: %w", fw.GetDirectory(), err)
+		}
+	}
+	if len(app.watchers) > 0 {
+		events = watcher.Merge(app.watchers...)
+	}
+
+	// Start agent uploader
+	if app.agent != nil {
+		app.logger.Info("Starting agent uploader...")
+		app.agent.Start(app.ctx, events)
+	}
+
+	// Start call processor, loading the transcription model before the first call arrives
+	if app.processor != nil {
+		app.transcriber.Start(app.ctx)
+		app.logger.Info("Starting call processor...")
+		app.processor.SetWatchers(app.watchers)
+		app.processor.Start(app.ctx, events)
+	}
+
+	// Start embedding calls for semantic search
+	if app.embeddings != nil {
+		app.logger.Info("Starting semantic search indexing...", "model", app.config.Embeddings.Model)
+		app.embeddings.Start(app.ctx)
+	}
 
 	// Start system monitor
 	if app.monitor != nil {
@@ -186,6 +729,54 @@ func (app *Application) start() error {
 		app.monitor.Start(app.ctx)
 	}
 
+	// Start Discord health reports
+	if app.discord != nil {
+		app.discord.StartHealthReports(app.ctx, app.healthReport)
+		app.discord.StartDailyDigest(app.ctx, app.dailyReport)
+	}
+
+	// Start daily report archive
+	if app.archive != nil {
+		app.logger.Info("Starting daily report archive...", "run_at", app.config.Archive.RunAt)
+		app.archive.Start(app.ctx)
+	}
+
+	// Start email digests
+	if app.digests != nil {
+		app.logger.Info("Starting email digests...", "digests", len(app.config.Email.Digests))
+		app.digests.Start(app.ctx)
+	}
+
+	// Start disk forecaster
+	if app.storage != nil {
+		app.logger.Info("Starting disk space forecaster...")
+		app.storage.Start(app.ctx)
+	}
+
+	// Start anomaly detection
+	if app.anomalies != nil {
+		app.logger.Info("Starting anomaly detection...", "baseline_days", app.config.Anomalies.BaselineDays)
+		app.anomalies.Start(app.ctx)
+	}
+
+	// Start audio archiving
+	if app.audioArchive != nil {
+		app.logger.Info("Starting audio archiving...", "bucket", app.config.AudioArchive.S3.Bucket)
+		app.audioArchive.Start(app.ctx)
+	}
+
+	// Start reconciliation
+	if app.reconciler != nil {
+		app.logger.Info("Starting recording reconciliation...", "interval_hours", app.config.Reconcile.Interval)
+		app.reconciler.Start(app.ctx)
+	}
+
+	// Start scheduled backups
+	if app.backups != nil {
+		app.logger.Info("Starting scheduled backups...", "directory", app.config.Backup.Directory, "interval_hours", app.config.Backup.Interval)
+		app.backups.Start(app.ctx)
+	}
+
 	// Start web server
 	if app.webServer != nil {
 		app.logger.Info("Starting web server...")
@@ -201,6 +792,7 @@ func (app *Application) start() error {
 		app.logger.Info("Optimized for SDR monitoring workload")
 	}
 
+	app.recordEvent(database.EventStartup, database.EventInfo, "", fmt.Sprintf("%s v%s started", AppName, AppVersion))
 	app.logger.Success("🚀 Meiko is now running!")
 	app.logger.Info("Press Ctrl+C to shutdown gracefully")
 
@@ -210,28 +802,64 @@ func (app *Application) start() error {
 	return nil
 }
 
+// shutdown stops intake first, then gives queued calls and Discord messages
+// up to shutdown_timeout to finish before closing the database
 func (app *Application) shutdown() {
 	app.logger.Info("Initiating graceful shutdown...")
+	app.notifySystemd(systemd.Stopping)
+	timeout := time.Duration(app.config.ShutdownTimeout) * time.Second
 
-	// Cancel context to signal all goroutines to stop
+	// Stop accepting uploads from agents
+	if app.webServer != nil {
+		app.webServer.Stop()
+	}
+
+	// Cancel context to stop the file watchers and background routines
 	app.cancel()
 
-	// Give components time to shutdown gracefully
-	time.Sleep(2 * time.Second)
+	// Stop SDRTrunk processes that haven't exited yet
+	if app.sdrtrunk != nil {
+		app.sdrtrunk.Stop()
+	}
 
-	// Send shutdown notification
+	// Finish transcribing recordings that were already queued
+	if app.processor != nil {
+		app.logger.Info("Waiting for queued calls to finish...", "timeout", timeout)
+		if !app.processor.Drain(timeout) {
+			app.logger.Warn("Shutdown timeout reached, abandoned calls in progress")
+		}
+	}
+	if app.transcriber != nil {
+		app.transcriber.Stop()
+	}
+	if app.translator != nil {
+		app.translator.Close()
+	}
+	if app.enricher != nil {
+		app.enricher.Close()
+	}
+	if app.embeddings != nil {
+		app.embeddings.Close()
+	}
+
+	// Send shutdown notification and wait for pending messages
 	if app.discord != nil {
 		app.discord.SendShutdownNotification()
-		time.Sleep(500 * time.Millisecond) // Give Discord time to send
+		if !app.discord.Flush(10 * time.Second) {
+			app.logger.Warn("Discord messages still sending at shutdown")
+		}
 	}
-
-	// Stop web server
-	if app.webServer != nil {
-		app.webServer.Stop()
+	if app.notifier != nil {
+		app.notifier.SendShutdown(AppName)
+		if !app.notifier.Flush(10 * time.Second) {
+			app.logger.Warn("Notifications still sending at shutdown")
+		}
+		app.notifier.Stop()
 	}
 
 	// Close database
 	if app.db != nil {
+		app.recordEvent(database.EventShutdown, database.EventInfo, "", AppName+" shut down")
 		app.db.Close()
 	}
 
@@ -243,6 +871,40 @@ func (app *Application) shutdown() {
 	app.logger.Info("Shutdown complete. Goodbye! 👋")
 }
 
+// recordEvent stores a system event for the timeline. Agents have no database,
+// so their events are only logged.
+func (app *Application) recordEvent(eventType, level, source, message string) {
+	if app.db == nil {
+		return
+	}
+	app.storeEvent(&database.SystemEvent{Type: eventType, Level: level, Source: source, Message: message})
+}
+
+// storeEvent saves a system event and refreshes today's timeline to show it
+func (app *Application) storeEvent(event *database.SystemEvent) {
+	if err := app.db.InsertSystemEvent(event); err != nil {
+		app.logger.Warn("Failed to record system event", "type", event.Type, "error", err)
+		return
+	}
+	if app.webServer != nil {
+		app.webServer.InvalidateTimelineCache()
+	}
+}
+
+// recordKeywordAlert stores a call that raised a push alert for the timeline,
+// at the time of the call so it sits beside it
+func (app *Application) recordKeywordAlert(rule string, call *database.CallRecord) {
+	event := &database.SystemEvent{
+		Timestamp: call.Timestamp,
+		Type:      database.EventKeyword,
+		Level:     database.EventWarning,
+		Source:    rule,
+		Message:   "Alert raised by a call on " + call.TalkgroupAlias,
+		CallID:    call.ID,
+	}
+	app.storeEvent(event)
+}
+
 func (app *Application) showStatus() {
 	fmt.Println()
 	fmt.Println("📊 System Status:")
@@ -254,10 +916,46 @@ func (app *Application) showStatus() {
 }
 
 func (app *Application) getSDRTrunkStatus() string {
-	if app.sdrtrunk.IsRunning() {
-		return "🟢 Running"
+	if app.sdrtrunk == nil {
+		return "⚪ Disabled"
+	}
+	running, total := app.sdrtrunk.RunningCount()
+	return multiStatus("🟢 Running", running, total)
+}
+
+// healthReport collects the figures for Discord health reports
+func (app *Application) healthReport() discord.HealthReport {
+	var report discord.HealthReport
+	if app.monitor != nil {
+		stats := app.monitor.GetCurrentStats()
+		report.HasStats = true
+		report.CPU = stats.CPU
+		report.Memory = stats.Memory
+		report.Disk = stats.Disk
+		report.Temperature = stats.Temperature
+	}
+	if app.db != nil {
+		if callsToday, err := app.db.GetCallsToday(""); err == nil {
+			report.CallsToday = callsToday
+		}
+	}
+	if app.processor != nil {
+		report.QueueDepth = app.processor.QueueDepth()
+	}
+	if app.sdrtrunk != nil {
+		report.SDRRunning, report.SDRTotal = app.sdrtrunk.RunningCount()
+	}
+	return report
+}
+
+// dailyReport builds the report for the Discord daily recap
+func (app *Application) dailyReport(start, end time.Time) (*archive.Report, error) {
+	var summarizer archive.Summarizer
+	if app.webServer != nil {
+		summarizer = app.webServer
 	}
-	return "🔴 Stopped"
+	builder := archive.NewBuilder(app.db, summarizer, app.config.Archive.BaseURL, app.config.Discord.Digest.MinSeverity, app.logger)
+	return builder.Build(start, end)
 }
 
 func (app *Application) getDiscordStatus() string {
@@ -270,10 +968,30 @@ func (app *Application) getDiscordStatus() string {
 }
 
 func (app *Application) getWatcherStatus() string {
-	if app.watcher.IsWatching() {
-		return "🟢 Monitoring"
+	if len(app.watchers) == 0 {
+		return "⚪ Disabled"
+	}
+	watching := 0
+	for _, fw := range app.watchers {
+		if fw.IsWatching() {
+			watching++
+		}
+	}
+	return multiStatus("🟢 Monitoring", watching, len(app.watchers))
+}
+
+// multiStatus describes how many of a component's instances are up, one per system
+func multiStatus(up string, count, total int) string {
+	switch {
+	case count == 0:
+		return "🔴 Stopped"
+	case total == 1:
+		return up
+	case count < total:
+		return fmt.Sprintf("🟡 %d/%d systems", count, total)
+	default:
+		return fmt.Sprintf("%s (%d systems)", up, total)
 	}
-	return "🔴 Stopped"
 }
 
 func (app *Application) getMonitorStatus() string {
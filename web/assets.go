@@ -0,0 +1,10 @@
+// Package web holds the dashboard's static assets, which are embedded into the
+// binary so Meiko serves them from any working directory
+package web
+
+import "embed"
+
+// Static is the dashboard, under static/
+//
+//go:embed static
+var Static embed.FS
@@ -0,0 +1,93 @@
+// Ask the scanner: natural-language questions answered from recent calls
+function askScanner(event) {
+    event.preventDefault();
+
+    const input = document.getElementById('ask-question');
+    const question = input.value.trim();
+    if (!question) return;
+
+    const range = document.getElementById('ask-range').value;
+    const log = document.getElementById('ask-log');
+    const button = document.getElementById('ask-submit');
+
+    appendAskMessage('question', question);
+    const pending = appendAskMessage('answer pending', 'Meiko is checking the calls...');
+    input.value = '';
+    button.disabled = true;
+
+    fetch('api/v1/ask', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ question, range })
+    })
+        .then(response => response.json().then(data => {
+            if (!response.ok) {
+                throw new Error(data.details || data.error || `HTTP ${response.status}`);
+            }
+            return data;
+        }))
+        .then(data => {
+            pending.remove();
+            appendAskAnswer(data.answer, data.calls || []);
+        })
+        .catch(error => {
+            console.error('Failed to ask the scanner:', error);
+            pending.remove();
+            appendAskMessage('answer error', `Meiko couldn't answer that: ${error.message}`);
+        })
+        .finally(() => {
+            button.disabled = false;
+            log.scrollTop = log.scrollHeight;
+        });
+}
+
+function appendAskMessage(kind, text) {
+    const log = document.getElementById('ask-log');
+    const empty = log.querySelector('.ask-empty');
+    if (empty) empty.remove();
+
+    const message = document.createElement('div');
+    message.className = `ask-message ${kind}`;
+    message.textContent = text;
+    log.appendChild(message);
+    log.scrollTop = log.scrollHeight;
+    return message;
+}
+
+// Renders an answer with its [#123] citations linked to the call details
+function appendAskAnswer(answer, calls) {
+    const message = appendAskMessage('answer', '');
+
+    answer.split(/(\[#\d+\])/).forEach(part => {
+        const citation = part.match(/^\[#(\d+)\]$/);
+        if (citation) {
+            const link = document.createElement('a');
+            link.href = '#';
+            link.className = 'ask-citation';
+            link.textContent = `#${citation[1]}`;
+            link.onclick = (e) => {
+                e.preventDefault();
+                showCallDetails(citation[1]);
+            };
+            message.appendChild(link);
+        } else {
+            message.appendChild(document.createTextNode(part));
+        }
+    });
+
+    if (calls.length > 0) {
+        const sources = document.createElement('div');
+        sources.className = 'ask-sources';
+        calls.forEach(call => {
+            const source = document.createElement('a');
+            source.href = '#';
+            source.textContent = `#${call.id} ${new Date(call.timestamp).toLocaleString()} · ${call.talkgroup_alias || call.talkgroup_id}`;
+            source.onclick = (e) => {
+                e.preventDefault();
+                showCallDetails(call.id);
+            };
+            sources.appendChild(source);
+        });
+        message.appendChild(sources);
+    }
+}
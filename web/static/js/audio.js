@@ -17,7 +17,7 @@ function playCallAudio(callId) {
     }
     
     // If clicking the same audio that was already loaded
-    if (currentTimelineAudio && currentTimelineAudio.src.includes(`/api/calls/${callId}/audio`) && currentTimelineButton === button) {
+    if (currentTimelineAudio && currentTimelineAudio.src.includes(`api/v1/calls/${callId}/audio`) && currentTimelineButton === button) {
         if (currentTimelineAudio.paused) {
             // Resume playback
             currentTimelineAudio.play().then(() => {
@@ -40,7 +40,7 @@ function playCallAudio(callId) {
         currentTimelineAudio = null;
     }
     
-    currentTimelineAudio = new Audio(`/api/calls/${callId}/audio`);
+    currentTimelineAudio = new Audio(`api/v1/calls/${callId}/audio`);
     currentTimelineButton = button;
     
     // Set up event listeners
@@ -150,6 +150,26 @@ function formatTime(seconds) {
     return `${mins}:${secs.toString().padStart(2, '0')}`;
 }
 
+// Helper function to format a frequency stored in Hz as MHz
+function formatFrequency(frequency) {
+    if (!/^\d+$/.test(frequency || '')) {
+        return frequency;
+    }
+    return `${parseFloat((Number(frequency) / 1e6).toFixed(6))} MHz`;
+}
+
+// Helper function to describe a recording that isn't speech, or '' for voice
+function audioClassLabel(call) {
+    switch (call.audio_class) {
+        case 'encrypted':
+            return '🔒 Encrypted audio';
+        case 'data':
+            return '📶 Data burst';
+        default:
+            return '';
+    }
+}
+
 // Helper function to format duration
 function formatDuration(seconds) {
     if (seconds < 60) {
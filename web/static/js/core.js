@@ -8,12 +8,75 @@ document.addEventListener('DOMContentLoaded', function() {
     connectWebSocket();
     startConnectivityMonitor(); // Start monitoring WebSocket connection
     loadSystemStats();
+    loadSDRStatus();
     loadTimeline();
     startMeikoPersonality();
     initTimelineDatePicker(); // Initialize date picker
+    initTimelineInfiniteScroll(); // Load older events while scrolling
+    loadPreferences(); // Restore saved settings such as the scanner volume
     setInterval(updateSystemStats, 5000); // Update every 5 seconds
 });
 
+// Saved dashboard preferences, synced to the server under this browser's token
+let preferences = {};
+let preferencesSaveTimer = null;
+let pendingPreferences = {};
+
+function clientToken() {
+    let token = localStorage.getItem('meikoClientToken');
+    if (!token) {
+        const bytes = crypto.getRandomValues(new Uint8Array(16));
+        token = Array.from(bytes, b => b.toString(16).padStart(2, '0')).join('');
+        localStorage.setItem('meikoClientToken', token);
+    }
+    return token;
+}
+
+async function loadPreferences() {
+    try {
+        const response = await fetch('api/v1/preferences', {
+            headers: { 'X-Client-Token': clientToken() }
+        });
+        if (!response.ok) return; // Not available, e.g. in public mode
+        const data = await response.json();
+        preferences = data.preferences || {};
+        applyPreferences();
+    } catch (error) {
+        console.warn('Failed to load preferences:', error);
+    }
+}
+
+function applyPreferences() {
+    if (typeof preferences.volume === 'number') {
+        liveScanner.volume = preferences.volume;
+        const volumeSlider = document.getElementById('master-volume');
+        const volumeDisplay = document.getElementById('volume-display');
+        if (volumeSlider) volumeSlider.value = Math.round(preferences.volume * 100);
+        if (volumeDisplay) volumeDisplay.textContent = Math.round(preferences.volume * 100) + '%';
+    }
+}
+
+// Save a preference, batching changes made in quick succession such as
+// dragging the volume slider
+function savePreference(key, value) {
+    preferences[key] = value;
+    pendingPreferences[key] = value;
+    clearTimeout(preferencesSaveTimer);
+    preferencesSaveTimer = setTimeout(async () => {
+        const changes = pendingPreferences;
+        pendingPreferences = {};
+        try {
+            await fetch('api/v1/preferences', {
+                method: 'PATCH',
+                headers: { 'Content-Type': 'application/json', 'X-Client-Token': clientToken() },
+                body: JSON.stringify(changes)
+            });
+        } catch (error) {
+            console.warn('Failed to save preferences:', error);
+        }
+    }, 1000);
+}
+
 // Meiko personality system
 function startMeikoPersonality() {
     const statusTexts = [
@@ -66,6 +129,11 @@ function switchTab(tabName) {
 
     currentTab = tabName;
 
+    // The log stream only runs while the console is open
+    if (tabName !== 'console') {
+        disconnectLogStream();
+    }
+
     // Load content for the selected tab
     switch(tabName) {
         case 'timeline':
@@ -132,7 +200,7 @@ function refreshSummary() {
 }
 
 function refreshLogs() {
-    loadLogs();
+    connectLogStream();
 }
 
 // Close modal when clicking outside
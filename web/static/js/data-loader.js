@@ -1,15 +1,23 @@
 // Call records functions
 function loadCalls() {
     const tbody = document.getElementById('calls-tbody');
-    tbody.innerHTML = '<tr><td colspan="6" class="loading"><img src="/static/Meiko.png" alt="Meiko" style="width: 24px; height: 24px; opacity: 0.7; vertical-align: middle; margin-right: 8px;">Meiko is scanning call records...</td></tr>';
+    tbody.innerHTML = '<tr><td colspan="7" class="loading"><img src="static/Meiko.png" alt="Meiko" style="width: 24px; height: 24px; opacity: 0.7; vertical-align: middle; margin-right: 8px;">Meiko is scanning call records...</td></tr>';
 
-    fetch('/api/calls?limit=50')
+    const view = document.getElementById('calls-view').value;
+    let url = 'api/v1/calls?limit=50';
+    if (view === 'priority') {
+        url += '&sort=priority';
+    } else if (view === 'major') {
+        url += '&major=true';
+    }
+
+    fetch(url)
         .then(response => response.json())
         .then(data => {
             displayCalls(data.calls);
         })
         .catch(error => {
-            tbody.innerHTML = '<tr><td colspan="6" style="text-align: center; color: var(--text-muted);"><img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 32px; height: 32px; opacity: 0.3; vertical-align: middle; margin-right: 8px;">Meiko couldn\'t load call records</td></tr>';
+            tbody.innerHTML = '<tr><td colspan="7" style="text-align: center; color: var(--text-muted);"><img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 32px; height: 32px; opacity: 0.3; vertical-align: middle; margin-right: 8px;">Meiko couldn\'t load call records</td></tr>';
         });
 }
 
@@ -17,7 +25,7 @@ function displayCalls(calls) {
     const tbody = document.getElementById('calls-tbody');
     
     if (!calls || calls.length === 0) {
-        tbody.innerHTML = '<tr><td colspan="6" style="text-align: center; color: var(--text-muted);"><img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 32px; height: 32px; opacity: 0.5; vertical-align: middle; margin-right: 8px;">Meiko hasn\'t detected any calls yet</td></tr>';
+        tbody.innerHTML = '<tr><td colspan="7" style="text-align: center; color: var(--text-muted);"><img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 32px; height: 32px; opacity: 0.5; vertical-align: middle; margin-right: 8px;">Meiko hasn\'t detected any calls yet</td></tr>';
         return;
     }
 
@@ -26,7 +34,7 @@ function displayCalls(calls) {
         const duration = call.duration + 's';
         const transcription = call.transcription ? 
             (call.transcription.length > 50 ? call.transcription.substring(0, 50) + '...' : call.transcription) :
-            'No transcription';
+            (audioClassLabel(call) || 'No transcription');
 
         // Format timestamp consistently with 12-hour format
         const formattedTime = timestamp.toLocaleString('en-US', {
@@ -44,7 +52,8 @@ function displayCalls(calls) {
                 <td>${call.talkgroup_group || 'Unknown'}</td>
                 <td>${call.talkgroup_alias || call.talkgroup_id || 'Unknown'}</td>
                 <td>${duration}</td>
-                <td>${call.frequency || 'N/A'}</td>
+                <td>${call.frequency_display || 'N/A'}</td>
+                <td>${call.priority || '-'}</td>
                 <td>${transcription}</td>
             </tr>
         `;
@@ -58,7 +67,7 @@ function loadAnalytics() {
 }
 
 function updateStatCards() {
-    fetch('/api/stats')
+    fetch('api/v1/stats')
         .then(response => response.json())
         .then(stats => {
             document.getElementById('total-calls-stat').textContent = stats.total_calls || '0';
@@ -89,7 +98,7 @@ function refreshSummary() {
 function loadDepartmentStats() {
     const container = document.getElementById('department-stats');
     
-    fetch('/api/stats')
+    fetch('api/v1/stats')
         .then(response => response.json())
         .then(stats => {
             if (stats.talkgroups && Object.keys(stats.talkgroups).length > 0) {
@@ -104,28 +113,31 @@ function loadDepartmentStats() {
                     </div>
                 `).join('');
             } else {
-                container.innerHTML = '<div class="empty-state"><img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.5; margin-bottom: 12px;"><p>Meiko found no department data</p></div>';
+                container.innerHTML = '<div class="empty-state"><img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.5; margin-bottom: 12px;"><p>Meiko found no department data</p></div>';
             }
         })
         .catch(error => {
-            container.innerHTML = '<div class="empty-state"><img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.3; margin-bottom: 12px;"><p>Meiko couldn\'t load department stats</p></div>';
+            container.innerHTML = '<div class="empty-state"><img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.3; margin-bottom: 12px;"><p>Meiko couldn\'t load department stats</p></div>';
         });
 }
 
 // Console functions
 function loadConsole() {
     loadSystemStats();
-    loadLogs();
+    loadPipelineStats();
+    connectLogStream();
 }
 
 function loadSystemStats() {
-    fetch('/api/stats')
+    fetch('api/v1/stats')
         .then(response => response.json())
         .then(stats => {
             document.getElementById('cpu-usage').textContent = (stats.cpu || 0).toFixed(1) + '%';
             document.getElementById('memory-usage').textContent = (stats.memory || 0).toFixed(1) + '%';
             document.getElementById('disk-usage').textContent = (stats.disk || 0).toFixed(1) + '%';
-            document.getElementById('temperature').textContent = (stats.temperature || 0).toFixed(1) + '°C';
+            const temperature = document.getElementById('temperature');
+            temperature.textContent = (stats.temperature || 0).toFixed(1) + '°C';
+            temperature.title = (stats.sensors || []).map(s => `${s.name}: ${s.temperature.toFixed(1)}°C`).join('\n');
         })
         .catch(error => {
             console.error('Failed to load system stats:', error);
@@ -136,46 +148,156 @@ function updateSystemStats() {
     if (currentTab === 'console' || currentTab === 'analytics') {
         loadSystemStats();
     }
+    if (currentTab === 'console') {
+        loadPipelineStats();
+    }
+    loadSDRStatus();
+}
+
+// Show the processing queue, stage latencies and failures on the console
+function loadPipelineStats() {
+    fetch('api/v1/pipeline')
+        .then(response => response.json())
+        .then(pipeline => {
+            const container = document.getElementById('pipeline-stats');
+            if (!container || !pipeline.stages) {
+                return;
+            }
+
+            const failures = Object.values(pipeline.failures || {}).reduce((total, count) => total + count, 0);
+            const items = [
+                ['Queued', pipeline.queue_depth],
+                ['Transcribing', pipeline.transcribing],
+                ['Processed', pipeline.processed],
+                ['Skipped', pipeline.skipped],
+                ['Failures', failures],
+                ['Queue Full', pipeline.deferred],
+                ['Dropped', pipeline.dropped],
+            ];
+            // Stages in the order they run, as JSON lists them alphabetically
+            for (const stage of ['parse', 'duration', 'transcribe', 'notify']) {
+                const timing = pipeline.stages[stage] || {};
+                items.push([`Avg ${stage}`, timing.count > 0 ? formatStageSeconds(timing.average_seconds) : '-']);
+            }
+            const last = pipeline.last_processed_at
+                ? `${pipeline.last_file} (${new Date(pipeline.last_processed_at).toLocaleTimeString('en-US', { hour12: true })})`
+                : '-';
+            items.push(['Last Processed', last]);
+
+            // Filenames come from the recorders, so they are set as text
+            container.replaceChildren(...items.map(([label, value]) => {
+                const item = document.createElement('div');
+                item.className = 'call-meta-item';
+                const labelElement = document.createElement('div');
+                labelElement.className = 'call-meta-label';
+                labelElement.textContent = label;
+                const valueElement = document.createElement('div');
+                valueElement.className = 'call-meta-value';
+                valueElement.textContent = value;
+                item.append(labelElement, valueElement);
+                return item;
+            }));
+            container.title = Object.entries(pipeline.failures || {}).map(([stage, count]) => `${stage}: ${count} failed`).join('\n');
+        })
+        .catch(error => {
+            console.error('Failed to load pipeline statistics:', error);
+        });
+}
+
+// Format a stage latency in seconds, in milliseconds when under a second
+function formatStageSeconds(seconds) {
+    return seconds < 1 ? `${Math.round(seconds * 1000)}ms` : `${seconds.toFixed(1)}s`;
+}
+
+// Show how many SDRTrunk processes are running in the header indicator
+function loadSDRStatus() {
+    fetch('api/v1/system')
+        .then(response => response.json())
+        .then(info => {
+            const indicator = document.getElementById('sdr-status');
+            const sdr = info.sdrtrunk;
+            if (!indicator || !sdr) {
+                return;
+            }
+
+            const processes = sdr.processes || [];
+            const hung = processes.some(p => p.hung);
+
+            indicator.classList.remove('online', 'offline', 'connecting');
+            if (sdr.running === sdr.total && !hung) {
+                indicator.classList.add('online');
+            } else if (sdr.running === 0) {
+                indicator.classList.add('offline');
+            } else {
+                indicator.classList.add('connecting');
+            }
+
+            indicator.querySelector('span').textContent = sdr.total > 1 ? `SDR ${sdr.running}/${sdr.total}` : 'SDR';
+            indicator.title = processes.map(p => {
+                const state = p.hung ? `hung, ${p.hung}` : p.state;
+                const restarts = p.restarts > 0 ? ` (${p.restarts} restarts)` : '';
+                return `${p.name || 'sdrtrunk'}: ${state}${restarts}`;
+            }).join('\n');
+        })
+        .catch(error => {
+            console.error('Failed to load SDRTrunk status:', error);
+        });
 }
 
 function loadLogs() {
     const container = document.getElementById('logs-container');
-    container.innerHTML = '<div class="loading"><img src="/static/Meiko.png" alt="Meiko" style="width: 32px; height: 32px; opacity: 0.7; margin-right: 12px;">Meiko is fetching system logs...</div>';
+    container.innerHTML = '<div class="loading"><img src="static/Meiko.png" alt="Meiko" style="width: 32px; height: 32px; opacity: 0.7; margin-right: 12px;">Meiko is fetching system logs...</div>';
 
-    fetch('/api/logs')
+    fetch('api/v1/logs')
         .then(response => response.json())
         .then(data => {
             if (data.logs && data.logs.length > 0) {
-                container.innerHTML = data.logs.map(log => {
-                    const timestamp = new Date(log.timestamp).toLocaleTimeString('en-US', {
-                        hour: '2-digit',
-                        minute: '2-digit',
-                        second: '2-digit',
-                        hour12: true
-                    });
-                    const levelColor = getLevelColor(log.level);
-                    // Clean message of any existing level indicators to prevent duplication
-                    const cleanMessage = cleanLogMessage(log.message, log.level);
-                    return `
-                        <div style="margin-bottom: 8px; font-family: var(--font-mono); font-size: 12px;">
-                            <span style="color: var(--text-muted);">[${timestamp}]</span>
-                            <span style="color: ${levelColor}; font-weight: 500;">[${log.level}]</span>
-                            <span style="color: var(--text-secondary);">[${log.component}]</span>
-                            <span style="color: var(--text-primary);">${cleanMessage}</span>
-                        </div>
-                    `;
-                }).join('');
+                container.innerHTML = data.logs.map(renderLogEntry).join('');
                 container.scrollTop = container.scrollHeight;
             } else {
-                container.innerHTML = '<div class="empty-state"><img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.5; margin-bottom: 12px;"><p>Meiko found no logs to display</p></div>';
+                container.innerHTML = '<div class="empty-state"><img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.5; margin-bottom: 12px;"><p>Meiko found no logs to display</p></div>';
             }
         })
         .catch(error => {
-            container.innerHTML = '<div class="empty-state"><img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.3; margin-bottom: 12px;"><p>Meiko couldn\'t access system logs</p></div>';
+            container.innerHTML = '<div class="empty-state"><img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 48px; height: 48px; opacity: 0.3; margin-bottom: 12px;"><p>Meiko couldn\'t access system logs</p></div>';
         });
 }
 
-// Helper function to get color for log level
+function renderLogEntry(log) {
+    const timestamp = new Date(log.timestamp).toLocaleTimeString('en-US', {
+        hour: '2-digit',
+        minute: '2-digit',
+        second: '2-digit',
+        hour12: true
+    });
+    const levelColor = getLevelColor(log.level);
+    // Clean message of any existing level indicators to prevent duplication
+    const cleanMessage = cleanLogMessage(log.message, log.level);
+    return `
+        <div style="margin-bottom: 8px; font-family: var(--font-mono); font-size: 12px;">
+            <span style="color: var(--text-muted);">[${timestamp}]</span>
+            <span style="color: ${levelColor}; font-weight: 500;">[${log.level}]</span>
+            <span style="color: var(--text-secondary);">[${log.component}]</span>
+            <span style="color: var(--text-primary);">${cleanMessage}</span>
+        </div>
+    `;
+}
+
+// Append a streamed log entry, keeping the view pinned to the bottom unless scrolled up
+function appendLogEntry(log) {
+    const container = document.getElementById('logs-container');
+    const atBottom = container.scrollHeight - container.scrollTop - container.clientHeight < 40;
+
+    container.insertAdjacentHTML('beforeend', renderLogEntry(log));
+    while (container.children.length > maxStreamedLogs) {
+        container.removeChild(container.firstElementChild);
+    }
+
+    if (atBottom) {
+        container.scrollTop = container.scrollHeight;
+    }
+}
+
 function getLevelColor(level) {
     switch (level.toUpperCase()) {
         case 'ERROR': return '#ff6b6b';
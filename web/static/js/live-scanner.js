@@ -46,6 +46,7 @@ function initLiveScanner() {
     volumeSlider.addEventListener('input', (e) => {
         liveScanner.volume = e.target.value / 100;
         volumeDisplay.textContent = e.target.value + '%';
+        savePreference('volume', liveScanner.volume);
         
         if (liveScanner.currentAudio) {
             liveScanner.currentAudio.volume = liveScanner.volume;
@@ -640,33 +641,46 @@ function animateWaveform() {
 function startLiveScannerMonitoring() {
     if (!liveScanner.isActive) return;
     
-    // Simulate scanning activity
+    let ticks = 0;
     setInterval(() => {
         if (!liveScanner.isActive) return;
         
-        // Randomly update frequency display to simulate scanning
-        const frequencies = ['453.100', '453.200', '453.300', '460.100', '460.200'];
-        const services = ['Police Dispatch', 'Fire Department', 'EMS Services', 'Public Works', 'Airport Operations'];
+        // Show the most recently heard frequency every 10 seconds
+        if (ticks++ % 5 === 0) {
+            updateActiveFrequency();
+        }
+        
+        // Update time indicator
+        updateTimeIndicator();
         
-        if (Math.random() > 0.8) { // 20% chance to update
-            const randomFreq = frequencies[Math.floor(Math.random() * frequencies.length)];
-            const randomService = services[Math.floor(Math.random() * services.length)];
+    }, 2000); // Update every 2 seconds
+}
+
+// Show the frequency calls were most recently heard on, unless a call is playing
+function updateActiveFrequency() {
+    fetch('api/v1/live/status')
+        .then(response => response.json())
+        .then(status => {
+            const latest = (status.active_frequencies || [])[0];
+            if (!latest || !liveScanner.isActive || liveScanner.isPlaying) return;
             
-            document.getElementById('active-frequency').textContent = 
-                `Scanning ${randomFreq} • ${randomService}`;
+            const freqElement = document.getElementById('active-frequency');
+            const text = `Last heard ${frequencyLabel(latest)}`;
+            if (freqElement.textContent === text) return;
+            freqElement.textContent = text;
                 
             // Briefly flash the frequency display
-            const freqElement = document.getElementById('active-frequency');
             freqElement.style.color = 'var(--accent-blue)';
             setTimeout(() => {
                 freqElement.style.color = '';
             }, 500);
-        }
-        
-        // Update time indicator
-        updateTimeIndicator();
-        
-    }, 2000); // Update every 2 seconds
+        })
+        .catch(error => console.error('Error loading live status:', error));
+}
+
+// Format frequency info from the API as "851.0125 MHz • Label"
+function frequencyLabel(info) {
+    return info.label ? `${info.display} • ${info.label}` : info.display;
 }
 
 function playLiveCall(callData) {
@@ -707,7 +721,7 @@ function startPlayingCall(callData) {
     liveScanner.currentCall = callData;
     
     // Create new audio instance
-    const audioUrl = `/api/calls/${callData.id}/audio`;
+    const audioUrl = `api/v1/calls/${callData.id}/audio`;
     console.log('Creating audio instance for URL:', audioUrl);
     liveScanner.currentAudio = new Audio(audioUrl);
     liveScanner.currentAudio.volume = liveScanner.volume;
@@ -1325,7 +1339,7 @@ function playCallFromFeed(callId) {
     updateMeikoStatus("Loading call", `Fetching call #${callId}`);
     
     // Fetch the actual call data
-    fetch(`/api/calls/${callId}`)
+    fetch(`api/v1/calls/${callId}`)
         .then(response => {
             if (!response.ok) {
                 throw new Error(`HTTP error! status: ${response.status}`);
@@ -1394,7 +1408,7 @@ function showCallDetails(callId) {
     updateMeikoStatus("Loading details", `Fetching call #${callId} details`);
     
     // Fetch call details
-    fetch(`/api/calls/${callId}`)
+    fetch(`api/v1/calls/${callId}`)
         .then(response => {
             if (!response.ok) {
                 throw new Error(`HTTP error! status: ${response.status}`);
@@ -1548,6 +1562,20 @@ function showCallDetailsModal(callData) {
                             <span style="color: var(--text-primary);">${callData.transcription}</span>
                         </div>
                     </div>
+                    ` : audioClassLabel(callData) ? `
+                    <div class="detail-section">
+                        <h4 style="margin: 0 0 12px 0; color: var(--text-primary); font-size: 14px; font-weight: 600; text-transform: uppercase; letter-spacing: 0.5px;">
+                            Not Transcribed
+                        </h4>
+                        <div style="
+                            background: var(--bg-secondary);
+                            padding: 16px;
+                            border-radius: 6px;
+                            border: 1px solid var(--border-secondary);
+                        ">
+                            <span style="color: var(--text-muted);">${audioClassLabel(callData)}</span>
+                        </div>
+                    </div>
                     ` : ''}
                     
                     <div class="detail-actions" style="
@@ -1722,7 +1750,7 @@ function testAudioPlayback() {
     updateMeikoStatus("Testing audio", "Fetching recent call for test");
     
     // Get the most recent call for testing
-    fetch('/api/calls?limit=1')
+    fetch('api/v1/calls?limit=1')
         .then(response => response.json())
         .then(data => {
             if (data.calls && data.calls.length > 0) {
@@ -1732,7 +1760,7 @@ function testAudioPlayback() {
                 updateMeikoStatus("Playing test audio", `Testing with call #${testCall.id}`);
                 
                 // Create test audio
-                const testAudio = new Audio(`/api/calls/${testCall.id}/audio`);
+                const testAudio = new Audio(`api/v1/calls/${testCall.id}/audio`);
                 testAudio.volume = liveScanner.volume;
                 
                 testAudio.addEventListener('loadeddata', () => {
@@ -1806,8 +1834,7 @@ function handleWebSocketMessageForLiveScanner(data) {
         // Update frequency display
         if (liveScannerData && liveScannerData.frequency_info) {
             const freqInfo = liveScannerData.frequency_info;
-            document.getElementById('active-frequency').textContent = 
-                `${freqInfo.frequency} • ${freqInfo.description}`;
+            document.getElementById('active-frequency').textContent = frequencyLabel(freqInfo);
             console.log('Updated frequency display:', freqInfo);
         }
         
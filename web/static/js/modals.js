@@ -1,6 +1,6 @@
 // Call details modal
 function showCallDetails(callId) {
-    fetch(`/api/calls/${callId}`)
+    fetch(`api/v1/calls/${callId}`)
         .then(response => response.json())
         .then(call => {
             displayCallDetails(call);
@@ -11,7 +11,7 @@ function showCallDetails(callId) {
             const container = document.getElementById('call-details-content');
             container.innerHTML = `
                 <div class="empty-state">
-                    <img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 64px; height: 64px; opacity: 0.3; margin-bottom: 16px;">
+                    <img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 64px; height: 64px; opacity: 0.3; margin-bottom: 16px;">
                     <p>Meiko couldn't load call details</p>
                     <small style="color: var(--text-muted);">Failed to fetch call information</small>
                 </div>
@@ -48,7 +48,7 @@ function displayCallDetails(call) {
             </div>
             <div class="call-meta-item">
                 <div class="call-meta-label">Frequency</div>
-                <div class="call-meta-value">${call.frequency || 'Unknown'}</div>
+                <div class="call-meta-value">${call.frequency_display || 'Unknown'}</div>
             </div>
             <div class="call-meta-item">
                 <div class="call-meta-label">System</div>
@@ -63,6 +63,12 @@ function displayCallDetails(call) {
             <dd>${call.talkgroup_alias || call.talkgroup_id}</dd>
             <dt>Filename</dt>
             <dd>${call.filename}</dd>
+            ${call.enrichment ? `
+                <dt>Incident</dt>
+                <dd>${call.enrichment.incident_type || 'other'}${call.enrichment.severity ? ` (severity ${call.enrichment.severity}/5)` : ''}</dd>
+                ${call.enrichment.location ? `<dt>Location</dt><dd>${call.enrichment.location}</dd>` : ''}
+                ${call.enrichment.units && call.enrichment.units.length ? `<dt>Units</dt><dd>${call.enrichment.units.join(', ')}</dd>` : ''}
+            ` : ''}
         </div>
 
         <div class="custom-audio-player">
@@ -80,7 +86,7 @@ function displayCallDetails(call) {
                 </div>
             </div>
             <audio id="audio-${call.id}" preload="metadata">
-                <source src="/api/calls/${call.id}/audio" type="audio/mpeg">
+                <source src="api/v1/calls/${call.id}/audio" type="audio/mpeg">
             </audio>
         </div>
 
@@ -88,12 +94,33 @@ function displayCallDetails(call) {
             <div class="call-transcription-section">
                 <div class="transcription-header">
                     <i class="fas fa-quote-left"></i>
-                    Transcription
+                    Transcription${call.language ? ` (${call.language.toUpperCase()})` : ''}
                 </div>
                 <div class="transcription-content">
                     ${call.transcription}
                 </div>
             </div>
+            ${call.translation ? `
+                <div class="call-transcription-section">
+                    <div class="transcription-header">
+                        <i class="fas fa-language"></i>
+                        English Translation
+                    </div>
+                    <div class="transcription-content">
+                        ${call.translation}
+                    </div>
+                </div>
+            ` : ''}
+        ` : audioClassLabel(call) ? `
+            <div class="call-transcription-section">
+                <div class="transcription-header">
+                    <i class="fas fa-ban"></i>
+                    Not transcribed
+                </div>
+                <div class="transcription-content">
+                    ${audioClassLabel(call)}
+                </div>
+            </div>
         ` : ''}
     `;
 
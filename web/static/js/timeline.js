@@ -1,7 +1,10 @@
 // Timeline functions
+const TIMELINE_PAGE_SIZE = 200;
 let isLoadingTimeline = false;
 let timelineSummaries = {};
 let showSummaries = true;
+let timelineEvents = [];
+let timelineNextCursor = null; // Cursor for the next older page, null once everything is loaded
 
 function loadTimeline(silent = false) {
     // Prevent multiple simultaneous loads
@@ -13,11 +16,10 @@ function loadTimeline(silent = false) {
     const container = document.getElementById('timeline-container');
     
     if (!silent) {
-        container.innerHTML = '<div class="loading"><img src="/static/Meiko.png" alt="Meiko" style="width: 32px; height: 32px; opacity: 0.7; margin-right: 12px;">Meiko is scanning for events...</div>';
+        container.innerHTML = '<div class="loading"><img src="static/Meiko.png" alt="Meiko" style="width: 32px; height: 32px; opacity: 0.7; margin-right: 12px;">Meiko is scanning for events...</div>';
     }
 
-    // Use correct API endpoint format and increase limit
-    const timelineUrl = `/api/timeline/${currentDate}?limit=200`;
+    const timelineUrl = `api/v1/timeline/${currentDate}?limit=${TIMELINE_PAGE_SIZE}`;
     
     // Load both timeline events and summaries
     Promise.all([
@@ -31,14 +33,23 @@ function loadTimeline(silent = false) {
     ])
     .then(([timelineData, summariesData]) => {
         timelineSummaries = summariesData.summaries || {};
-        displayEnhancedTimeline(timelineData.events);
+        const events = timelineData.events || [];
+        if (silent && timelineEvents.length > events.length) {
+            // Keep older pages the user already scrolled to, adding new events on top
+            const seen = new Set(events.map(event => event.id));
+            timelineEvents = events.concat(timelineEvents.filter(event => !seen.has(event.id)));
+        } else {
+            timelineEvents = events;
+            timelineNextCursor = timelineData.next_cursor || null;
+        }
+        displayEnhancedTimeline(timelineEvents);
         console.log(`Timeline loaded: ${timelineData.events?.length || 0} events, ${Object.keys(timelineSummaries).length} summaries for ${currentDate}`);
     })
     .catch(error => {
         console.error('Timeline load error:', error);
         container.innerHTML = `
             <div class="empty-state">
-                <img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 64px; height: 64px; opacity: 0.3; margin-bottom: 16px;">
+                <img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 64px; height: 64px; opacity: 0.3; margin-bottom: 16px;">
                 <p>Meiko encountered an error!</p>
                 <small style="color: var(--text-muted);">Failed to load timeline events: ${error.message}</small>
             </div>
@@ -49,8 +60,53 @@ function loadTimeline(silent = false) {
     });
 }
 
+// Load the next older page of events when scrolled near the end of the timeline
+function loadMoreTimeline() {
+    if (isLoadingTimeline || !timelineNextCursor) {
+        return;
+    }
+
+    isLoadingTimeline = true;
+    const date = currentDate;
+    fetch(`api/v1/timeline/${date}?limit=${TIMELINE_PAGE_SIZE}&cursor=${encodeURIComponent(timelineNextCursor)}`)
+        .then(r => {
+            if (!r.ok) {
+                throw new Error(`HTTP ${r.status}: ${r.statusText}`);
+            }
+            return r.json();
+        })
+        .then(timelineData => {
+            // Drop the page if the date changed while it was loading
+            if (date !== currentDate) {
+                return;
+            }
+            const seen = new Set(timelineEvents.map(event => event.id));
+            timelineEvents = timelineEvents.concat((timelineData.events || []).filter(event => !seen.has(event.id)));
+            timelineNextCursor = timelineData.next_cursor || null;
+            displayEnhancedTimeline(timelineEvents);
+        })
+        .catch(error => {
+            console.error('Failed to load more timeline events:', error);
+        })
+        .finally(() => {
+            isLoadingTimeline = false;
+        });
+}
+
+function initTimelineInfiniteScroll() {
+    window.addEventListener('scroll', () => {
+        const timelineTab = document.getElementById('timeline');
+        if (!timelineTab || !timelineTab.classList.contains('active')) {
+            return;
+        }
+        if (window.innerHeight + window.scrollY >= document.body.offsetHeight - 600) {
+            loadMoreTimeline();
+        }
+    }, { passive: true });
+}
+
 function loadTimelineSummaries(date) {
-    return fetch(`/api/timeline/summaries/${date}`)
+    return fetch(`api/v1/timeline/summaries/${date}`)
         .then(response => {
             if (!response.ok) {
                 throw new Error(`HTTP ${response.status}: ${response.statusText}`);
@@ -69,7 +125,7 @@ function displayEnhancedTimeline(events) {
     if (!events || events.length === 0) {
         container.innerHTML = `
             <div class="empty-state">
-                <img src="/static/MeikoConfused.png" alt="Confused Meiko" style="width: 64px; height: 64px; opacity: 0.5; margin-bottom: 16px;">
+                <img src="static/MeikoConfused.png" alt="Confused Meiko" style="width: 64px; height: 64px; opacity: 0.5; margin-bottom: 16px;">
                 <p>Meiko is waiting for activity...</p>
                 <small style="color: var(--text-muted);">No events found for ${currentDate}</small>
             </div>
@@ -90,7 +146,7 @@ function displayEnhancedTimeline(events) {
         wasPlaying = !currentTimelineAudio.paused;
         audioCurrentTime = currentTimelineAudio.currentTime;
         
-        const srcMatch = currentTimelineAudio.src.match(/\/api\/calls\/(\d+)\/audio/);
+        const srcMatch = currentTimelineAudio.src.match(/\/api\/v1\/calls\/(\d+)\/audio/);
         if (srcMatch) {
             currentCallId = parseInt(srcMatch[1]);
         }
@@ -256,16 +312,16 @@ function createTimelineItem(event) {
             tagsHTML += `<span class="timeline-tag">${event.data.talkgroup}</span>`;
         }
         if (event.data.frequency) {
-            tagsHTML += `<span class="timeline-tag">${event.data.frequency}</span>`;
+            tagsHTML += `<span class="timeline-tag">${formatFrequency(event.data.frequency)}</span>`;
         }
         if (event.data.duration) {
             tagsHTML += `<span class="timeline-tag">${event.data.duration}s</span>`;
         }
     }
 
-    // Build controls for call events
+    // Build controls for call events, and for alerts and incidents raised by a call
     let controlsHTML = '';
-    if (event.type === 'call' && event.data && event.data.call_id) {
+    if (event.data && event.data.call_id) {
         controlsHTML = `
             <div class="timeline-controls">
                 <button class="btn-small play-btn" onclick="playCallAudio(${event.data.call_id})">
@@ -283,7 +339,7 @@ function createTimelineItem(event) {
     return `
         <div class="timeline-item" data-service="${serviceType}">
             <div class="timeline-time">${timeString}</div>
-            <div class="timeline-icon">
+            <div class="timeline-icon"${event.color ? ` style="color: ${event.color}"` : ''}>
                 <i class="fas fa-${event.icon}"></i>
             </div>
             <div class="timeline-content">
@@ -1,17 +1,40 @@
 // WebSocket connection
 let wsReconnectAttempts = 0;
+let wsEverConnected = false;
 const maxReconnectAttempts = 5;
 
+// Server-Sent Events fallback for networks that break WebSockets
+let eventSource = null;
+
+// Position in the server's message sequence, for resuming after a reconnect
+const wsSchemaVersion = 2;
+let wsEpoch = null;
+let wsLastSeq = null;
+
+// WebSocket URL of a path relative to the dashboard, which may be served
+// under a reverse proxy's base path
+function webSocketUrl(path) {
+    const url = new URL(path, document.baseURI);
+    url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+    return url.toString();
+}
+
 function connectWebSocket() {
-    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-    const wsUrl = `${protocol}//${window.location.host}/ws`;
-    
-    ws = new WebSocket(wsUrl);
+    ws = new WebSocket(webSocketUrl('ws'));
     
     ws.onopen = function() {
         console.log('WebSocket connected');
         updateSystemStatus('online');
         wsReconnectAttempts = 0; // Reset reconnect attempts on successful connection
+        wsEverConnected = true;
+
+        // Ask for the messages missed while disconnected
+        const hello = { type: 'hello', version: wsSchemaVersion };
+        if (wsEpoch !== null && wsLastSeq !== null) {
+            hello.epoch = wsEpoch;
+            hello.last_seq = wsLastSeq;
+        }
+        ws.send(JSON.stringify(hello));
         
         // Update Meiko status
         updateMeikoStatus("System connected", "Real-time monitoring active");
@@ -29,6 +52,13 @@ function connectWebSocket() {
         // Update Meiko status
         updateMeikoStatus("Connection lost", "Attempting to reconnect...");
         
+        // A socket that never opened is likely blocked by a proxy, so switch
+        // to Server-Sent Events instead of retrying
+        if (!wsEverConnected) {
+            connectEventStream();
+            return;
+        }
+
         // Attempt to reconnect with exponential backoff
         if (wsReconnectAttempts < maxReconnectAttempts) {
             wsReconnectAttempts++;
@@ -36,8 +66,8 @@ function connectWebSocket() {
             console.log(`Attempting to reconnect in ${delay}ms (attempt ${wsReconnectAttempts}/${maxReconnectAttempts})`);
             setTimeout(connectWebSocket, delay);
         } else {
-            console.log('Max reconnection attempts reached');
-            updateMeikoStatus("Connection failed", "Manual refresh required");
+            console.log('Max reconnection attempts reached, falling back to Server-Sent Events');
+            connectEventStream();
         }
     };
     
@@ -50,10 +80,80 @@ function connectWebSocket() {
     };
 }
 
+// Receive the same messages as the WebSocket over Server-Sent Events. The
+// browser reconnects the stream by itself.
+function connectEventStream() {
+    if (eventSource) {
+        return;
+    }
+    ws = null;
+    eventSource = new EventSource('api/v1/events');
+
+    eventSource.onopen = function() {
+        console.log('Event stream connected');
+        updateSystemStatus('online');
+        updateMeikoStatus("System connected", "Real-time monitoring active (event stream)");
+    };
+
+    eventSource.onmessage = function(event) {
+        const data = JSON.parse(event.data);
+        handleWebSocketMessage(data);
+    };
+
+    eventSource.onerror = function() {
+        console.log('Event stream interrupted, reconnecting');
+        updateSystemStatus('connecting');
+        updateMeikoStatus("Connection lost", "Attempting to reconnect...");
+    };
+}
+
+// Live log stream for the console tab
+let logSocket = null;
+const maxStreamedLogs = 500;
+
+function connectLogStream() {
+    disconnectLogStream();
+
+    const level = document.getElementById('log-level').value;
+    const socket = new WebSocket(webSocketUrl(`ws/logs?level=${level}`));
+    logSocket = socket;
+
+    socket.onopen = function() {
+        document.getElementById('logs-container').innerHTML = '';
+    };
+
+    socket.onmessage = function(event) {
+        const data = JSON.parse(event.data);
+        if (data.type === 'log') {
+            appendLogEntry(data.data);
+        }
+    };
+
+    socket.onclose = function() {
+        if (logSocket !== socket) {
+            return;
+        }
+        logSocket = null;
+        // Fall back to the last buffered entries, e.g. when the API key lacks the admin scope
+        loadLogs();
+    };
+}
+
+function disconnectLogStream() {
+    if (logSocket) {
+        const socket = logSocket;
+        logSocket = null;
+        socket.close();
+    }
+}
+
 // Periodic connectivity check
 function startConnectivityMonitor() {
     setInterval(() => {
-        if (ws && ws.readyState === WebSocket.OPEN) {
+        if (eventSource) {
+            const states = ['connecting', 'online', 'offline'];
+            updateSystemStatus(states[eventSource.readyState]);
+        } else if (ws && ws.readyState === WebSocket.OPEN) {
             // Connection is healthy
             updateSystemStatus('online');
         } else if (ws && ws.readyState === WebSocket.CONNECTING) {
@@ -66,9 +166,59 @@ function startConnectivityMonitor() {
     }, 5000); // Check every 5 seconds
 }
 
+// Reload the open tab from the API after missing real-time messages
+function backfillMissedMessages() {
+    console.log('Missed real-time messages, reloading');
+    if (currentTab === 'timeline') {
+        loadTimeline(true);
+    }
+    if (currentTab === 'calls') {
+        loadCalls();
+    }
+    if (currentTab === 'analytics') {
+        updateStatCards();
+    }
+}
+
 // WebSocket message handling
 function handleWebSocketMessage(data) {
+    if (data.seq) {
+        wsLastSeq = data.seq;
+    }
+
     switch(data.type) {
+        case 'status':
+            if (wsEpoch === null) {
+                wsEpoch = data.epoch;
+                wsLastSeq = data.latest;
+            }
+            if (data.resumed === false) {
+                wsEpoch = data.epoch;
+                wsLastSeq = data.latest;
+                backfillMissedMessages();
+            }
+            break;
+        case 'hello_ack':
+            if (!data.resumed) {
+                wsEpoch = data.epoch;
+                wsLastSeq = data.latest;
+                backfillMissedMessages();
+            } else if (data.missed > 0) {
+                console.log(`Resumed, replaying ${data.missed} missed messages`);
+            }
+            break;
+        case 'heartbeat':
+            // The server restarted since the last message, or messages were
+            // dropped on the way, such as when this client fell behind
+            if (wsEpoch !== null && (data.epoch !== wsEpoch || data.latest > wsLastSeq)) {
+                wsEpoch = data.epoch;
+                wsLastSeq = data.latest;
+                backfillMissedMessages();
+            }
+            break;
+        case 'error':
+            console.warn('Real-time server error:', data.error);
+            break;
         case 'stats_update':
             if (currentTab === 'console' || currentTab === 'analytics') {
                 updateSystemStats();
@@ -122,7 +272,7 @@ function testWebSocketBroadcast() {
     console.log('Testing WebSocket broadcast...');
     updateMeikoStatus("Testing WebSocket", "Triggering manual broadcast");
     
-    fetch('/api/debug/broadcast-latest', {
+    fetch('api/v1/debug/broadcast-latest', {
         method: 'POST',
         headers: {
             'Content-Type': 'application/json',